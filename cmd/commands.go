@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/doctor"
+	"github.com/richbl/go-ble-sync-cycle/internal/flags"
+	"github.com/richbl/go-ble-sync-cycle/internal/installer"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
+	"github.com/richbl/go-ble-sync-cycle/internal/session"
+	"github.com/richbl/go-ble-sync-cycle/internal/video"
+)
+
+// runInstall installs the application (the install subcommand)
+func runInstall() {
+
+	if err := installer.Install(); err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("installation failed: %v", err))
+	}
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runUninstall uninstalls the application (the uninstall subcommand)
+func runUninstall() {
+
+	if err := installer.Uninstall(); err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("uninstallation failed: %v", err))
+	}
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runSelfTest runs the session self-test (the selftest subcommand)
+func runSelfTest() {
+
+	if err := session.SelfTest(logger.BackgroundCtx); err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("session self-test failed: %v", err))
+	}
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runDecode re-parses a BLE traffic log through the speed sensor parser (the decode subcommand)
+func runDecode() {
+
+	file, err := os.Open(flags.Flags().Decode)
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to open BLE traffic log: %v", err))
+	}
+	defer file.Close()
+
+	// Decode using the wheel circumference and speed units of the session config (if available)
+	speedCfg := config.NewDefault().Speed
+
+	if cfg, err := config.Load(configFile); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("using default speed settings (%d mm, %s): %v", speedCfg.WheelCircumferenceMM, speedCfg.SpeedUnits, err))
+	} else {
+		speedCfg = cfg.Speed
+	}
+
+	fmt.Fprintln(os.Stdout, "")
+
+	if err := ble.DecodeTrafficLog(file, os.Stdout, speedCfg); err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to decode BLE traffic log: %v", err))
+	}
+
+	fmt.Fprintln(os.Stdout, "")
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runExport exports the session directory into a settings bundle (the export subcommand)
+func runExport() {
+
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to locate session directory: %v", err))
+	}
+
+	count, err := config.ExportBundle(configDir, flags.Flags().Bundle)
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to export settings bundle: %v", err))
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.APP, fmt.Sprintf("%d file(s) from %s exported to %s", count, configDir, flags.Flags().Bundle))
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runImport imports a settings bundle into the session directory (the import subcommand)
+func runImport() {
+
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to locate session directory: %v", err))
+	}
+
+	result, err := config.ImportBundle(flags.Flags().Bundle, configDir, flags.Flags().Overwrite)
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to import settings bundle: %v", err))
+	}
+
+	for _, name := range result.Skipped {
+		logger.Warn(logger.BackgroundCtx, logger.APP, name+" already exists and was not imported (use --overwrite to replace it)")
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.APP, fmt.Sprintf("%d file(s) imported into %s", len(result.Imported), configDir))
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runSchema writes a JSON Schema of the session config file (the schema subcommand)
+func runSchema() {
+
+	var schema bytes.Buffer
+
+	if err := config.WriteSchema(&schema); err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to generate config schema: %v", err))
+	}
+
+	if err := os.WriteFile(flags.Flags().Schema, schema.Bytes(), 0644); err != nil { //nolint:gosec // The config schema is not secret
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to write config schema: %v", err))
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.APP, "config schema written to "+flags.Flags().Schema)
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runVersion displays the application version and build information (the version subcommand)
+func runVersion() {
+
+	fmt.Fprintln(os.Stdout, "")
+	fmt.Fprint(os.Stdout, config.GetBuildInfo().String())
+	fmt.Fprintln(os.Stdout, "")
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runDoctor checks the environment for problems (the doctor subcommand)
+func runDoctor() {
+
+	cfgFile := configFile
+	if flags.Flags().Config != "" {
+		cfgFile = flags.Flags().Config
+	}
+
+	opts := doctor.Options{
+		EnableAdapter:    ble.DefaultAdapter().Enable,
+		ProbeMediaPlayer: video.ProbeMediaPlayer,
+		ProbeVideoFile:   probeVideoFile,
+		LoadConfig: func() (string, error) {
+
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return "", err
+			}
+
+			return cfg.Video.FilePath, nil
+		},
+		ConfigFile: cfgFile,
+	}
+
+	fmt.Fprintln(os.Stdout, "")
+	fmt.Fprintln(os.Stdout, "Checking the "+config.GetFullVersion()+" environment...")
+
+	if failed := doctor.Report(os.Stdout, doctor.Run(logger.BackgroundCtx, doctor.Checks(opts))); failed > 0 {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("%d diagnostic check(s) failed", failed))
+	}
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// probeVideoFile describes the video stream of a video file for the environment diagnostics
+func probeVideoFile(path string) (string, error) {
+
+	info, err := video.ProbeVideoFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s video (%dx%d, %s)", info.Codec, info.Width, info.Height, info.Duration.Round(time.Second)), nil
+}
+
+// runScan scans for BLE CSC sensors and lists them (the scan subcommand)
+func runScan() {
+
+	sensors := discoverSensors(time.Duration(flags.Flags().ScanSecs) * time.Second)
+
+	fmt.Fprintln(os.Stdout, "")
+
+	if len(sensors) == 0 {
+		fmt.Fprintln(os.Stdout, "No BLE speed and cadence sensors found")
+	}
+
+	for _, sensor := range sensors {
+		fmt.Fprintln(os.Stdout, "  "+sensor.String())
+	}
+
+	fmt.Fprintln(os.Stdout, "")
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// runInit writes a starter config file (the init subcommand)
+func runInit() {
+
+	cfg := config.StarterConfig()
+
+	// Pre-fill the BD_ADDR of the sensor with the strongest signal
+	if flags.IsInitScanFlag() {
+
+		if sensors := discoverSensors(initScanDuration); len(sensors) > 0 {
+			cfg.BLE.SensorBDAddr = sensors[0].Address
+		}
+	}
+
+	path, err := config.WriteStarterConfig(flags.Flags().Init, cfg)
+	if err != nil {
+		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to write starter config file: %v", err))
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.APP, "starter config file written to "+path)
+
+	if cfg.BLE.SensorBDAddr == config.PlaceholderBDAddr {
+		logger.Info(logger.BackgroundCtx, logger.APP, "edit sensor_bd_addr and file_path in the starter config file before running a session")
+	} else {
+		logger.Info(logger.BackgroundCtx, logger.APP, "edit file_path in the starter config file before running a session")
+	}
+
+	services.WaveGoodbye(logger.BackgroundCtx)
+
+}
+
+// discoverSensors scans for BLE CSC sensors for the given duration, returning the sensors found
+// (strongest signal first)
+func discoverSensors(duration time.Duration) []ble.DiscoveredSensor {
+
+	logger.Info(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("scanning for BLE sensors for %s (spin the wheel to wake the sensor)...", duration))
+
+	ctx, cancel := context.WithTimeout(logger.BackgroundCtx, duration)
+	defer cancel()
+
+	sensors, err := ble.DiscoverSensors(ctx, ble.DefaultAdapter())
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("sensor scan failed: %v", err))
+
+		return nil
+	}
+
+	if len(sensors) == 0 {
+		logger.Warn(logger.BackgroundCtx, logger.BLE, "no BLE sensors found")
+
+		return nil
+	}
+
+	for _, sensor := range sensors {
+		logger.Info(logger.BackgroundCtx, logger.BLE, "found BLE sensor "+sensor.String())
+	}
+
+	return sensors
+}
@@ -1,71 +1,207 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
-
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/crashreport"
 	"github.com/richbl/go-ble-sync-cycle/internal/flags"
-	"github.com/richbl/go-ble-sync-cycle/internal/installer"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/services"
 	"github.com/richbl/go-ble-sync-cycle/internal/session"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+	"github.com/richbl/go-ble-sync-cycle/internal/video"
 	"github.com/richbl/go-ble-sync-cycle/ui"
 )
 
 // Application constants
 const (
-	configFile = "config.toml"
+	configFile       = "config.toml"
+	initScanDuration = 15 * time.Second
 )
 
+// Exit code of the application when a fatal error is logged (see the apperr package)
+var fatalExitCode = apperr.ExitFailure
+
 func main() {
 
+	// Generate a crash report if the application panics
+	defer recoverPanic()
+
 	// Initialize the application
 	appInitialize()
 
 	// Hello computer...
 	services.WaveHello(logger.BackgroundCtx)
 
-	// Parse for command-line flags
+	// Parse for the command-line subcommand and its flags
 	parseCLIFlags()
 	checkForHelpFlag()
-	checkForInstallFlag()
-	checkForUninstallFlag()
 
-	// Check for application mode (CLI or GUI)
-	if !flags.IsCLIMode() {
+	// Run the subcommand
+	switch flags.Command() {
+
+	case flags.CmdRun, flags.CmdReplay:
+		runSession()
+
+	case flags.CmdScan:
+		runScan()
+
+	case flags.CmdDoctor:
+		runDoctor()
+
+	case flags.CmdInit:
+		runInit()
+
+	case flags.CmdSelfTest:
+		runSelfTest()
+
+	case flags.CmdDecode:
+		runDecode()
+
+	case flags.CmdExport:
+		runExport()
+
+	case flags.CmdImport:
+		runImport()
+
+	case flags.CmdSchema:
+		runSchema()
+
+	case flags.CmdVersion:
+		runVersion()
+
+	case flags.CmdInstall:
+		runInstall()
+
+	case flags.CmdUninstall:
+		runUninstall()
+
+	default:
 		logger.Debug(logger.BackgroundCtx, logger.APP, "now running in GUI mode...")
 		ui.StartGUI()
-
-		return
 	}
 
-	// Continue running in CLI mode
+}
+
+// runSession runs a BSC session in CLI mode (using either the BLE sensor or a replayed session)
+func runSession() {
+
 	logger.Debug(logger.BackgroundCtx, logger.APP, "running in CLI mode")
 
 	// Create session manager
 	sessionMgr := session.NewManager()
 
+	// Generate a crash report (including the session configuration) on a fatal session error
+	crashreport.SetConfigSource(sessionMgr.ActiveConfig)
+	logger.SetExitHandler(func() {
+		reportCrash("fatal error (see the last [FTL] message in log.txt)", nil)
+		services.WaveGoodbyeWithExitCode(logger.BackgroundCtx, fatalExitCode)
+	})
+
+	// Ring the terminal bell at each interval transition of the interval timer (if configured)
+	sessionMgr.SetIntervalHandler(func(_ speed.IntervalState) {
+		fmt.Fprint(os.Stdout, "\a")
+	})
+
 	// Load configuration
 	if err := sessionMgr.LoadTargetSession(configFile); err != nil {
-		logger.Fatal(logger.BackgroundCtx, logger.APP, err)
+		fatal(apperr.ExitCode(err), err)
 	}
 
+	// Report common Bluetooth permission problems before connecting to the BLE sensor
+	checkBluetoothPermissions()
+
 	// Start the session (initializes controllers, connects BLE, starts services)
 	if err := sessionMgr.StartSession(); err != nil {
 
-		if errors.Is(err, context.Canceled) {
-			logger.Info(logger.BackgroundCtx, logger.APP, "application exiting due to user cancellation")
-		} else {
-			logger.Fatal(logger.BackgroundCtx, logger.APP, err)
+		if !errors.Is(err, context.Canceled) {
+			fatal(apperr.ExitCode(err), err)
 		}
+
+		logger.Info(logger.BackgroundCtx, logger.APP, "application exiting due to user cancellation")
+		services.WaveGoodbyeWithExitCode(logger.BackgroundCtx, apperr.ExitInterrupted)
 	}
 
 	// Wait patiently for shutdown (Ctrl+C or services error)
 	sessionMgr.Wait()
+	exitCode := sessionExitCode(sessionMgr)
+	sessionMgr.ReleaseSession()
+
+	// Ask for notes and tags describing the ride (if there was one)
+	promptSessionNotes(sessionMgr)
 
 	// Wave goodbye
-	services.WaveGoodbye(logger.BackgroundCtx)
+	services.WaveGoodbyeWithExitCode(logger.BackgroundCtx, exitCode)
+
+}
+
+// sessionExitCode returns the exit code of a session that has stopped: stopped by the user (e.g.,
+// Ctrl+C), stopped when the video playback completed (or the session time limit was reached), or
+// stopped by a session error
+func sessionExitCode(sessionMgr *session.StateManager) int {
+
+	if sessionMgr.Interrupted() {
+		return apperr.ExitInterrupted
+	}
+
+	err := sessionMgr.Err()
+	if errors.Is(err, video.ErrVideoComplete) || errors.Is(err, session.ErrSessionTimeLimit) {
+		return apperr.ExitOK
+	}
+
+	return apperr.ExitCode(err)
+}
+
+// fatal logs a fatal error and exits with the given exit code (see the apperr package)
+func fatal(code int, msg any) {
+
+	fatalExitCode = code
+	logger.Fatal(logger.BackgroundCtx, logger.APP, msg)
+
+}
+
+// promptSessionNotes asks for notes and tags to add to the ride just recorded in the session
+// history (only when run from a terminal, so unattended sessions never wait on input)
+func promptSessionNotes(sessionMgr *session.StateManager) {
+
+	record, ok := sessionMgr.TakeLastSessionRecord()
+	if !ok {
+		return
+	}
+
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+
+	logger.ClearCLILine()
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Fprint(os.Stdout, "Session notes (press Enter to skip): ")
+	notes, _ := reader.ReadString('\n')
+
+	fmt.Fprint(os.Stdout, "Session tags, comma-separated (e.g., recovery, FTP test): ")
+	tags, _ := reader.ReadString('\n')
+
+	notes = strings.TrimSpace(notes)
+	tagList := config.ParseTags(tags)
+
+	if notes == "" && len(tagList) == 0 {
+		return
+	}
+
+	if err := sessionMgr.AnnotateSession(record, notes, tagList); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, err.Error())
+	}
 
 }
 
@@ -77,48 +213,61 @@ func appInitialize() {
 
 	// Initialize the fatal log events exit handler until the service manager is loaded
 	logger.SetExitHandler(func() {
-		services.WaveGoodbye(logger.BackgroundCtx)
+		services.WaveGoodbyeWithExitCode(logger.BackgroundCtx, fatalExitCode)
+	})
+
+	// Generate a crash report when a service panics
+	services.SetPanicHandler(func(service string, r any, stack []byte) {
+		reportCrash(fmt.Sprintf("%s service panic: %v", service, r), stack)
 	})
 
 }
 
-// parseCLIFlags parses and validates command-line flags
-func parseCLIFlags() {
+// recoverPanic generates a crash report if the main goroutine panics, then continues panicking
+func recoverPanic() {
 
-	if err := flags.ParseArgs(); err != nil {
-		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to parse command-line flags: %v", err))
+	if r := recover(); r != nil {
+		reportCrash(fmt.Sprintf("panic: %v", r), debug.Stack())
+		panic(r)
 	}
 
 }
 
-// checkForInstallFlag checks for the installer flag passed on the command-line
-func checkForInstallFlag() {
+// reportCrash generates a crash report bundle, and points the user to it
+func reportCrash(reason string, stack []byte) {
+
+	path, err := crashreport.Generate(crashreport.Report{Reason: reason, Stack: stack})
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to generate crash report: %v", err))
 
-	if !flags.IsInstallFlag() {
 		return
 	}
 
-	if err := installer.Install(); err != nil {
-		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("installation failed: %v", err))
-	}
+	logger.Error(logger.BackgroundCtx, logger.APP, "crash report saved to "+path+" (please attach it when filing an issue)")
 
-	services.WaveGoodbye(logger.BackgroundCtx)
+}
+
+// parseCLIFlags parses and validates command-line flags
+func parseCLIFlags() {
+
+	if err := flags.ParseArgs(); err != nil {
+		fatal(apperr.ExitUsage, fmt.Sprintf("failed to parse command-line flags: %v", err))
+	}
 
 }
 
-// checkForUninstallFlag checks for the uninstaller flag passed on the command-line
-func checkForUninstallFlag() {
+// checkBluetoothPermissions reports common Bluetooth permission problems, along with their
+// remedies (a recorded session replay doesn't use the BLE sensor)
+func checkBluetoothPermissions() {
 
-	if !flags.IsUninstallFlag() {
+	if flags.IsReplayFlag() {
 		return
 	}
 
-	if err := installer.Uninstall(); err != nil {
-		logger.Fatal(logger.BackgroundCtx, logger.APP, fmt.Sprintf("uninstallation failed: %v", err))
+	for _, issue := range ble.CheckPermissions() {
+		logger.Warn(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("%s: to fix, %s", issue.Problem, issue.Remedy))
 	}
 
-	services.WaveGoodbye(logger.BackgroundCtx)
-
 }
 
 // checkForHelpFlag checks for the help flag passed on the command-line
@@ -0,0 +1,191 @@
+package apperr
+
+import "errors"
+
+// Category of a user-facing error
+type Category int
+
+// Categories of user-facing errors
+const (
+	Internal    Category = iota // Unexpected errors (e.g., a bug)
+	ConfigError                 // Missing or invalid session configuration
+	SensorError                 // BLE sensor or Bluetooth adapter problems
+	PlayerError                 // Media player or video playback problems
+	Timeout                     // A device or service stopped responding in time
+)
+
+// String returns the name of the error category (e.g., "sensor error")
+func (c Category) String() string {
+
+	switch c {
+	case ConfigError:
+		return "configuration error"
+	case SensorError:
+		return "sensor error"
+	case PlayerError:
+		return "player error"
+	case Timeout:
+		return "timeout"
+	default:
+		return "internal error"
+	}
+}
+
+// Retryable returns true if retrying (e.g., restarting the session) might succeed without
+// changing the session configuration
+func (c Category) Retryable() bool {
+	return c == SensorError || c == Timeout
+}
+
+// Exit codes of the application, so that wrapper scripts and service managers (e.g., systemd
+// Restart= policies) can tell why a session ended
+const (
+	ExitOK          = 0   // Success (e.g., the video playback completed)
+	ExitFailure     = 1   // Unexpected (internal) error
+	ExitUsage       = 2   // Invalid command-line flags
+	ExitConfig      = 3   // Missing or invalid session configuration
+	ExitSensor      = 4   // BLE sensor not found, or otherwise unusable
+	ExitPlayer      = 5   // Media player failed to start or to play the video
+	ExitTimeout     = 6   // A device or service stopped responding in time
+	ExitInterrupted = 130 // Stopped by a shutdown signal (e.g., Ctrl+C)
+)
+
+// ExitCode returns the exit code of the application for errors in the category
+func (c Category) ExitCode() int {
+
+	switch c {
+	case ConfigError:
+		return ExitConfig
+	case SensorError:
+		return ExitSensor
+	case PlayerError:
+		return ExitPlayer
+	case Timeout:
+		return ExitTimeout
+	default:
+		return ExitFailure
+	}
+}
+
+// Code identifies a user-facing error
+type Code string
+
+// Codes of user-facing errors
+const (
+	CodeConfigNotFound    Code = "config_not_found"
+	CodeConfigInvalid     Code = "config_invalid"
+	CodeVideoSeek         Code = "video_seek"
+	CodeBluetoothSetup    Code = "bluetooth_setup"
+	CodeSensorNotFound    Code = "sensor_not_found"
+	CodeSensorInUse       Code = "sensor_in_use"
+	CodeSensorUnsupported Code = "sensor_unsupported"
+	CodeSensorConnect     Code = "sensor_connect"
+	CodeSensorTimeout     Code = "sensor_timeout"
+	CodePlayerInit        Code = "player_init"
+	CodePlayerFailed      Code = "player_failed"
+	CodePlayerStalled     Code = "player_stalled"
+)
+
+// codeCategories maps each error code to its category
+var codeCategories = map[Code]Category{
+	CodeConfigNotFound:    ConfigError,
+	CodeConfigInvalid:     ConfigError,
+	CodeVideoSeek:         ConfigError,
+	CodeBluetoothSetup:    SensorError,
+	CodeSensorNotFound:    SensorError,
+	CodeSensorInUse:       SensorError,
+	CodeSensorUnsupported: SensorError,
+	CodeSensorConnect:     SensorError,
+	CodeSensorTimeout:     Timeout,
+	CodePlayerInit:        PlayerError,
+	CodePlayerFailed:      PlayerError,
+	CodePlayerStalled:     Timeout,
+}
+
+// Category returns the category of the error code
+func (c Code) Category() Category {
+	return codeCategories[c]
+}
+
+// Error is a user-facing error with an error code
+type Error struct {
+	Code    Code
+	message string
+	err     error
+}
+
+// New creates a user-facing error with the given code and message (e.g., a sentinel error)
+func New(code Code, message string) *Error {
+	return &Error{Code: code, message: message}
+}
+
+// Wrap gives an error the given code, unless it already has one (the more specific code of the
+// wrapped error is kept)
+func Wrap(code Code, err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	return &Error{Code: code, err: err}
+}
+
+// Error returns the error message (of the wrapped error, if any)
+func (e *Error) Error() string {
+
+	if e.err != nil {
+		return e.err.Error()
+	}
+
+	return e.message
+}
+
+// Unwrap returns the wrapped error (nil if none)
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Category returns the category of the error
+func (e *Error) Category() Category {
+	return e.Code.Category()
+}
+
+// As returns the user-facing error found in the error chain, if any
+func As(err error) (*Error, bool) {
+
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		return nil, false
+	}
+
+	return appErr, true
+}
+
+// CodeOf returns the code of the error (empty if the error has no code)
+func CodeOf(err error) Code {
+
+	if appErr, ok := As(err); ok {
+		return appErr.Code
+	}
+
+	return ""
+}
+
+// CategoryOf returns the category of the error (Internal if the error has no code)
+func CategoryOf(err error) Category {
+	return CodeOf(err).Category()
+}
+
+// ExitCode returns the exit code of the application for the error (ExitOK if there's no error)
+func ExitCode(err error) int {
+
+	if err == nil {
+		return ExitOK
+	}
+
+	return CategoryOf(err).ExitCode()
+}
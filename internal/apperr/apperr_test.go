@@ -0,0 +1,106 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestCategoryOf tests finding the code and category of errors in an error chain
+func TestCategoryOf(t *testing.T) {
+
+	errSensor := New(CodeSensorNotFound, "scanning time limit reached")
+	errPlain := errors.New("something went wrong")
+
+	// Define test cases
+	tests := []struct {
+		name         string
+		err          error
+		wantCode     Code
+		wantCategory Category
+		wantMessage  string
+	}{
+		{"sentinel", errSensor, CodeSensorNotFound, SensorError, "scanning time limit reached"},
+		{"wrapped sentinel", fmt.Errorf("BLE scan failed: %w", errSensor), CodeSensorNotFound, SensorError, "BLE scan failed: scanning time limit reached"},
+		{"wrapped with a code", Wrap(CodeConfigInvalid, errPlain), CodeConfigInvalid, ConfigError, "something went wrong"},
+		{"more specific code kept", Wrap(CodeSensorConnect, fmt.Errorf("connect: %w", errSensor)), CodeSensorNotFound, SensorError, "connect: scanning time limit reached"},
+		{"timeout", Wrap(CodePlayerStalled, errPlain), CodePlayerStalled, Timeout, "something went wrong"},
+		{"no code", errPlain, "", Internal, "something went wrong"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			if code := CodeOf(tt.err); code != tt.wantCode {
+				t.Errorf("CodeOf() = %q, want %q", code, tt.wantCode)
+			}
+
+			if category := CategoryOf(tt.err); category != tt.wantCategory {
+				t.Errorf("CategoryOf() = %v, want %v", category, tt.wantCategory)
+			}
+
+			if tt.err.Error() != tt.wantMessage {
+				t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.wantMessage)
+			}
+
+		})
+	}
+
+	// Wrapped errors are still found in the error chain
+	if err := Wrap(CodeConfigInvalid, errPlain); !errors.Is(err, errPlain) {
+		t.Errorf("errors.Is() = false, want the wrapped error found")
+	}
+
+	if Wrap(CodeConfigInvalid, nil) != nil {
+		t.Errorf("Wrap(nil) != nil, want nil")
+	}
+
+}
+
+// TestCategoryRetryable tests which error categories might succeed when retried, and the exit
+// code of each error category
+func TestCategoryRetryable(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		category     Category
+		want         bool
+		wantExitCode int
+	}{
+		{Internal, false, ExitFailure},
+		{ConfigError, false, ExitConfig},
+		{SensorError, true, ExitSensor},
+		{PlayerError, false, ExitPlayer},
+		{Timeout, true, ExitTimeout},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.category.String(), func(t *testing.T) {
+
+			if got := tt.category.Retryable(); got != tt.want {
+				t.Errorf("Retryable() = %v, want %v", got, tt.want)
+			}
+
+			if got := tt.category.ExitCode(); got != tt.wantExitCode {
+				t.Errorf("ExitCode() = %v, want %v", got, tt.wantExitCode)
+			}
+
+		})
+	}
+
+	if ExitCode(nil) != ExitOK || ExitCode(New(CodeSensorNotFound, "not found")) != ExitSensor {
+		t.Errorf("ExitCode() = %v, %v, want %v, %v", ExitCode(nil), ExitCode(New(CodeSensorNotFound, "not found")), ExitOK, ExitSensor)
+	}
+
+	// Every error code belongs to a category
+	for code := range codeCategories {
+		if code.Category() == Internal {
+			t.Errorf("code %q has no category", code)
+		}
+	}
+
+}
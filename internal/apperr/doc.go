@@ -0,0 +1,14 @@
+// Package apperr defines the categories and codes of user-facing errors for BLE Sync Cycle (BSC)
+//
+// Errors that a user can act on (e.g., an invalid session file, a BLE sensor that can't be found,
+// or a media player that fails to start) are given a code, and each code belongs to a category:
+//
+// - ConfigError: the session configuration is missing or invalid (fix the session file)
+// - SensorError: the BLE sensor or Bluetooth adapter can't be used
+// - PlayerError: the media player failed to start or to play the video
+// - Timeout: a device or service stopped responding in time (retrying may help)
+//
+// The category of an error chooses how it's presented (e.g., the GUI dialog shown, and whether it
+// offers to retry), and the code identifies the error in logs and scripts. Errors without a code
+// are unexpected, so they fall into the Internal category.
+package apperr
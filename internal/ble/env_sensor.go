@@ -0,0 +1,195 @@
+package ble
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"tinygo.org/x/bluetooth"
+)
+
+// Environmental Sensing service and characteristic UUIDs as defined by Bluetooth SIG
+var (
+	envServiceUUID                = bluetooth.New16BitUUID(0x181A)
+	temperatureCharacteristicUUID = bluetooth.New16BitUUID(0x2A6E)
+	humidityCharacteristicUUID    = bluetooth.New16BitUUID(0x2A6F)
+)
+
+// Values reported by environmental sensors when a measurement is unknown
+const (
+	temperatureUnknown = -0x8000 // Temperature (sint16) of 0x8000
+	humidityUnknown    = 0xFFFF
+)
+
+// Environmental Sensing service configuration
+var envServiceConfig = serviceConfig{
+	serviceUUID:              envServiceUUID,
+	characteristicUUID:       temperatureCharacteristicUUID,
+	errNoServicesFound:       ErrNoEnvServices,
+	errNoCharacteristicFound: ErrNoEnvCharacteristics,
+}
+
+// EnvReading is a temperature (and humidity, if the sensor measures it) reading from a BLE
+// environmental sensor
+type EnvReading struct {
+	TemperatureC float64
+	HumidityPct  float64
+	HasHumidity  bool
+	Time         time.Time
+}
+
+// EnvServices discovers and returns available Environmental Sensing services from the BLE
+// peripheral
+func (m *Controller) EnvServices(ctx context.Context, device ServiceDiscoverer) ([]CharacteristicDiscoverer, error) {
+
+	result, err := executeAction(
+		ctx,
+		m,
+		"discovering environmental sensing service UUID="+envServiceConfig.serviceUUID.String(),
+		func(_ context.Context, found chan<- []CharacteristicDiscoverer, errChan chan<- error) {
+			discoverServices(envServiceConfig, device, found, errChan)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, logger.BLE, "found environmental sensing service")
+
+	return result, nil
+}
+
+// EnvCharacteristics discovers and stores the temperature and (optional) humidity characteristics
+// from the BLE peripheral
+func (m *Controller) EnvCharacteristics(ctx context.Context, services []CharacteristicDiscoverer) error {
+
+	_, err := executeAction(
+		ctx,
+		m,
+		"discovering temperature and humidity characteristics",
+		func(_ context.Context, found chan<- bool, errChan chan<- error) {
+
+			if len(services) == 0 {
+				errChan <- ErrNoServicesProvided
+
+				return
+			}
+
+			characteristics, err := services[0].DiscoverCharacteristics([]bluetooth.UUID{temperatureCharacteristicUUID, humidityCharacteristicUUID})
+			if err != nil {
+				errChan <- err
+
+				return
+			}
+
+			for _, characteristic := range characteristics {
+
+				switch characteristic.UUID() {
+				case temperatureCharacteristicUUID:
+					m.blePeripheralDetails.temperatureCharacteristic = characteristic
+				case humidityCharacteristicUUID:
+					m.blePeripheralDetails.humidityCharacteristic = characteristic
+				}
+			}
+
+			if m.blePeripheralDetails.temperatureCharacteristic == nil {
+				errChan <- ErrNoEnvCharacteristics
+
+				return
+			}
+
+			found <- true
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(ctx, logger.BLE, fmt.Sprintf("found temperature characteristic (humidity: %t)", m.blePeripheralDetails.humidityCharacteristic != nil))
+
+	return nil
+}
+
+// ReadEnvironment reads the current temperature (and humidity, if available) from the BLE
+// peripheral
+func (m *Controller) ReadEnvironment() (EnvReading, error) {
+
+	details := &m.blePeripheralDetails
+
+	if details.temperatureCharacteristic == nil {
+		return EnvReading{}, ErrNoEnvCharacteristics
+	}
+
+	buffer := make([]byte, 2)
+
+	n, err := details.temperatureCharacteristic.Read(buffer)
+	if err != nil {
+		return EnvReading{}, fmt.Errorf(errFormat, "failed to read temperature", err)
+	}
+
+	temperature, err := parseTemperature(buffer[:n])
+	if err != nil {
+		return EnvReading{}, err
+	}
+
+	reading := EnvReading{TemperatureC: temperature, Time: time.Now()}
+
+	// Humidity is optional, so a failed read only omits it from the reading
+	if details.humidityCharacteristic != nil {
+
+		if n, err := details.humidityCharacteristic.Read(buffer); err == nil {
+			reading.HumidityPct, err = parseHumidity(buffer[:n])
+			reading.HasHumidity = err == nil
+		}
+	}
+
+	m.environment.Store(&reading)
+
+	return reading, nil
+}
+
+// EnvironmentLast returns the last reading from the BLE environmental sensor (false if there's
+// no reading)
+func (m *Controller) EnvironmentLast() (EnvReading, bool) {
+
+	reading := m.environment.Load()
+	if reading == nil {
+		return EnvReading{}, false
+	}
+
+	return *reading, true
+}
+
+// parseTemperature parses a Temperature characteristic value (sint16, in 0.01 degrees Celsius)
+func parseTemperature(data []byte) (float64, error) {
+
+	if len(data) < 2 {
+		return 0, fmt.Errorf(errFormat, "temperature", ErrInvalidEnvData)
+	}
+
+	raw := int16(binary.LittleEndian.Uint16(data)) //nolint:gosec // Temperature is a signed 16-bit value
+	if raw == temperatureUnknown {
+		return 0, fmt.Errorf(errFormat, "temperature", ErrUnknownMeasurement)
+	}
+
+	return float64(raw) / 100, nil
+}
+
+// parseHumidity parses a Humidity characteristic value (uint16, in 0.01 percent)
+func parseHumidity(data []byte) (float64, error) {
+
+	if len(data) < 2 {
+		return 0, fmt.Errorf(errFormat, "humidity", ErrInvalidEnvData)
+	}
+
+	raw := binary.LittleEndian.Uint16(data)
+	if raw == humidityUnknown {
+		return 0, fmt.Errorf(errFormat, "humidity", ErrUnknownMeasurement)
+	}
+
+	return float64(raw) / 100, nil
+}
@@ -0,0 +1,111 @@
+package ble
+
+import (
+	"testing"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tinygo.org/x/bluetooth"
+)
+
+// createMockEnvReader creates a mock characteristic reader returning the given value
+func createMockEnvReader(charUUID bluetooth.UUID, value []byte) *mockCharacteristicReader {
+
+	return &mockCharacteristicReader{
+		readFunc: func(p []byte) (int, error) {
+			return copy(p, value), nil
+		},
+		uuidFunc: func() bluetooth.UUID {
+			return charUUID
+		},
+	}
+}
+
+// TestParseEnvMeasurements tests parsing temperature and humidity characteristic values
+func TestParseEnvMeasurements(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		parse     func([]byte) (float64, error)
+		data      []byte
+		want      float64
+		wantError error
+	}{
+		{"temperature", parseTemperature, []byte{0x66, 0x08}, 21.50, nil},
+		{"negative temperature", parseTemperature, []byte{0x0C, 0xFE}, -5.00, nil},
+		{"unknown temperature", parseTemperature, []byte{0x00, 0x80}, 0, ErrUnknownMeasurement},
+		{"short temperature", parseTemperature, []byte{0x66}, 0, ErrInvalidEnvData},
+		{"humidity", parseHumidity, []byte{0x94, 0x11}, 45.00, nil},
+		{"unknown humidity", parseHumidity, []byte{0xFF, 0xFF}, 0, ErrUnknownMeasurement},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := tt.parse(tt.data)
+
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+
+}
+
+// TestReadEnvironment tests discovering and reading the temperature and humidity characteristics
+func TestReadEnvironment(t *testing.T) {
+
+	controller := createTestBLEController(t)
+
+	_, ok := controller.EnvironmentLast()
+	assert.False(t, ok, "EnvironmentLast() returned a reading before any read")
+
+	mockService := createMockCharDiscoverer(func(uuids []bluetooth.UUID) ([]CharacteristicReader, error) {
+		assert.Equal(t, []bluetooth.UUID{temperatureCharacteristicUUID, humidityCharacteristicUUID}, uuids)
+
+		return []CharacteristicReader{
+			createMockEnvReader(temperatureCharacteristicUUID, []byte{0x66, 0x08}),
+			createMockEnvReader(humidityCharacteristicUUID, []byte{0x94, 0x11}),
+		}, nil
+	})
+
+	require.NoError(t, controller.EnvCharacteristics(logger.BackgroundCtx, []CharacteristicDiscoverer{mockService}))
+
+	reading, err := controller.ReadEnvironment()
+	require.NoError(t, err)
+	assert.InDelta(t, 21.5, reading.TemperatureC, 1e-9)
+	assert.InDelta(t, 45.0, reading.HumidityPct, 1e-9)
+	assert.True(t, reading.HasHumidity)
+
+	last, ok := controller.EnvironmentLast()
+	assert.True(t, ok)
+	assert.Equal(t, reading, last)
+
+}
+
+// TestEnvCharacteristicsNoTemperature tests that a sensor without a temperature characteristic
+// is rejected
+func TestEnvCharacteristicsNoTemperature(t *testing.T) {
+
+	controller := createTestBLEController(t)
+
+	mockService := createMockCharDiscoverer(func(_ []bluetooth.UUID) ([]CharacteristicReader, error) {
+		return []CharacteristicReader{createMockEnvReader(humidityCharacteristicUUID, []byte{0x94, 0x11})}, nil
+	})
+
+	err := controller.EnvCharacteristics(logger.BackgroundCtx, []CharacteristicDiscoverer{mockService})
+	require.ErrorIs(t, err, ErrNoEnvCharacteristics)
+
+	_, err = controller.ReadEnvironment()
+	require.ErrorIs(t, err, ErrNoEnvCharacteristics)
+
+}
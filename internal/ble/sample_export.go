@@ -0,0 +1,84 @@
+package ble
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sampleExportHeader defines the columns of the raw CSC sample export (compatible with the
+// recorded session replay speed source)
+var sampleExportHeader = []string{"timestamp", "wheel_revs", "wheel_time", "speed"}
+
+// sampleExporter writes every raw CSC notification (and its computed speed) to a CSV file
+type sampleExporter struct {
+	file   *os.File
+	writer *csv.Writer
+	mu     sync.Mutex
+}
+
+// newSampleExporter creates (or truncates) the CSV file used to export raw CSC samples
+func newSampleExporter(path string) (*sampleExporter, error) {
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to create sample export file", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	if err := writer.Write(sampleExportHeader); err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf(errFormat, "failed to write sample export header", err)
+	}
+
+	return &sampleExporter{
+		file:   file,
+		writer: writer,
+	}, nil
+}
+
+// write exports a single raw CSC sample
+func (se *sampleExporter) write(timestamp time.Time, wheelRevs uint32, wheelTime uint16, speed float64) error {
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	// Samples that arrive after the export is closed are dropped
+	if se.file == nil {
+		return nil
+	}
+
+	return se.writer.Write([]string{
+		timestamp.Format(time.RFC3339Nano),
+		strconv.FormatUint(uint64(wheelRevs), 10),
+		strconv.FormatUint(uint64(wheelTime), 10),
+		strconv.FormatFloat(speed, 'f', 2, 64),
+	})
+}
+
+// close flushes any buffered samples and closes the export file
+func (se *sampleExporter) close() error {
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if se.file == nil {
+		return nil
+	}
+
+	se.writer.Flush()
+	err := se.writer.Error()
+
+	if closeErr := se.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	se.file = nil
+
+	return err
+}
@@ -0,0 +1,50 @@
+package ble
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSampleExporter tests writing raw CSC samples to a CSV file
+func TestSampleExporter(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	exporter, err := newSampleExporter(path)
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.write(timestamp, 1234, 5678, 21.456))
+	require.NoError(t, exporter.close())
+
+	// Samples written after the export is closed are dropped
+	require.NoError(t, exporter.write(timestamp, 1235, 5700, 22.0))
+	require.NoError(t, exporter.close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		sampleExportHeader,
+		{"2025-01-02T03:04:05Z", "1234", "5678", "21.46"},
+	}, records)
+
+}
+
+// TestSampleExporterInvalidPath tests creating a sample export in a missing directory
+func TestSampleExporterInvalidPath(t *testing.T) {
+
+	_, err := newSampleExporter(filepath.Join(t.TempDir(), "missing", "samples.csv"))
+	assert.Error(t, err)
+
+}
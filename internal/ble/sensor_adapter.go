@@ -0,0 +1,88 @@
+package ble
+
+import (
+	"sync"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// Adapter provides the BLE adapter operations used by the controller, allowing the system BLE
+// adapter to be replaced (e.g., by a fake adapter in tests)
+type Adapter interface {
+	Enable() error
+	Address() (string, error)
+	Scan(callback func(result bluetooth.ScanResult)) error
+	StopScan() error
+	Connect(address bluetooth.Address, params bluetooth.ConnectionParams) (Device, error)
+}
+
+// Device is a connected BLE peripheral
+type Device interface {
+	ServiceDiscoverer
+	Disconnect() error
+}
+
+// systemAdapter is the Adapter backed by the system BLE adapter
+type systemAdapter struct {
+	adapter *bluetooth.Adapter
+}
+
+// Mutex for synchronizing system adapter access (shared by all controllers)
+var adapterMu sync.Mutex
+
+// DefaultAdapter returns the system BLE adapter
+//
+//nolint:ireturn // Returns the system implementation of the Adapter interface
+func DefaultAdapter() Adapter {
+	return &systemAdapter{adapter: bluetooth.DefaultAdapter}
+}
+
+// Enable enables the system BLE adapter
+func (a *systemAdapter) Enable() error {
+
+	adapterMu.Lock()
+	defer adapterMu.Unlock()
+
+	return a.adapter.Enable()
+}
+
+// Address returns the BD_ADDR of the system BLE adapter
+func (a *systemAdapter) Address() (string, error) {
+
+	address, err := a.adapter.Address()
+	if err != nil {
+		return "", err
+	}
+
+	return address.String(), nil
+}
+
+// Scan starts scanning for BLE peripherals, calling the callback for each scan result until the
+// scan is stopped
+func (a *systemAdapter) Scan(callback func(result bluetooth.ScanResult)) error {
+
+	adapterMu.Lock()
+	defer adapterMu.Unlock()
+
+	return a.adapter.Scan(func(_ *bluetooth.Adapter, result bluetooth.ScanResult) {
+		callback(result)
+	})
+}
+
+// StopScan stops a scan in progress
+func (a *systemAdapter) StopScan() error {
+	return a.adapter.StopScan()
+}
+
+// Connect connects to the BLE peripheral at the given address
+//
+//nolint:ireturn // Returns the system implementation of the Device interface
+func (a *systemAdapter) Connect(address bluetooth.Address, params bluetooth.ConnectionParams) (Device, error) {
+
+	device, err := a.adapter.Connect(address, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
@@ -0,0 +1,291 @@
+package ble
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tinygo.org/x/bluetooth"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+const fakeSensorBDAddr = "AA:BB:CC:DD:EE:FF"
+
+var errEnableFailed = errors.New("adapter unavailable")
+
+// fakeDevice is a fake implementation of Device
+type fakeDevice struct {
+	mockServiceDiscoverer
+}
+
+// Disconnect fakes the Disconnect method
+func (d *fakeDevice) Disconnect() error {
+	return nil
+}
+
+// fakeAdapter is a fake implementation of Adapter that reports a fixed set of scan results
+// (optionally only once a number of scans have come up empty, like a sensor waking up)
+type fakeAdapter struct {
+	enableErr   error
+	address     string
+	results     []bluetooth.ScanResult
+	connected   []bluetooth.Address
+	stopScanned int
+	scans       int
+	emptyScans  int
+}
+
+// Enable fakes the Enable method
+func (a *fakeAdapter) Enable() error {
+	return a.enableErr
+}
+
+// Address fakes the Address method
+func (a *fakeAdapter) Address() (string, error) {
+	return a.address, nil
+}
+
+// Scan fakes the Scan method, reporting each scan result until the scan is stopped
+func (a *fakeAdapter) Scan(callback func(result bluetooth.ScanResult)) error {
+
+	a.scans++
+	if a.scans <= a.emptyScans {
+		return nil
+	}
+
+	stopScanned := a.stopScanned
+
+	for _, result := range a.results {
+
+		if a.stopScanned > stopScanned {
+			break
+		}
+
+		callback(result)
+	}
+
+	return nil
+}
+
+// StopScan fakes the StopScan method
+func (a *fakeAdapter) StopScan() error {
+
+	a.stopScanned++
+
+	return nil
+}
+
+// Connect fakes the Connect method
+//
+//nolint:ireturn // Returns a fake implementation of the Device interface
+func (a *fakeAdapter) Connect(address bluetooth.Address, _ bluetooth.ConnectionParams) (Device, error) {
+
+	a.connected = append(a.connected, address)
+
+	return &fakeDevice{}, nil
+}
+
+// fakeScanResult creates a scan result for the given BD_ADDR
+func fakeScanResult(t *testing.T, addr string) bluetooth.ScanResult {
+
+	t.Helper()
+
+	mac, err := bluetooth.ParseMAC(addr)
+	require.NoError(t, err)
+
+	return bluetooth.ScanResult{Address: bluetooth.Address{MACAddress: bluetooth.MACAddress{MAC: mac}}}
+}
+
+// newFakeAdapterController creates a controller using the given fake adapter
+func newFakeAdapterController(t *testing.T, adapter Adapter) *Controller {
+
+	t.Helper()
+
+	bleConfig := config.BLEConfig{
+		SensorBDAddr:    fakeSensorBDAddr,
+		ScanTimeoutSecs: 1,
+		ScanAttempts:    1,
+	}
+
+	speedConfig := config.SpeedConfig{
+		WheelCircumferenceMM: wheelCircumferenceMM,
+		SpeedUnits:           speedUnitsKMH,
+	}
+
+	controller, err := NewBLEControllerWithAdapter(logger.BackgroundCtx, adapter, bleConfig, speedConfig)
+	require.NoError(t, err)
+
+	return controller
+}
+
+// TestNewBLEControllerWithAdapter tests creating a controller when the adapter can't be enabled, or
+// when the sensor BD_ADDR is that of the adapter
+func TestNewBLEControllerWithAdapter(t *testing.T) {
+
+	_, err := NewBLEControllerWithAdapter(logger.BackgroundCtx, &fakeAdapter{enableErr: errEnableFailed}, config.BLEConfig{}, config.SpeedConfig{})
+	require.ErrorIs(t, err, errEnableFailed)
+
+	// A sensor BD_ADDR that's the address of the adapter itself is rejected
+	_, err = NewBLEControllerWithAdapter(logger.BackgroundCtx, &fakeAdapter{address: fakeSensorBDAddr}, config.BLEConfig{SensorBDAddr: fakeSensorBDAddr}, config.SpeedConfig{})
+	require.ErrorIs(t, err, ErrSensorIsAdapter)
+
+}
+
+// TestScanAndConnectWithFakeAdapter tests scanning for and connecting to a BLE peripheral
+func TestScanAndConnectWithFakeAdapter(t *testing.T) {
+
+	sensor := fakeScanResult(t, fakeSensorBDAddr)
+	sensor.RSSI = -62
+	sensor.AdvertisementPayload = fakePayload{name: "Speed 2"}
+
+	adapter := &fakeAdapter{
+		results: []bluetooth.ScanResult{
+			fakeScanResult(t, "11:22:33:44:55:66"),
+			sensor,
+			fakeScanResult(t, fakeSensorBDAddr),
+		},
+	}
+
+	controller := newFakeAdapterController(t, adapter)
+
+	result, err := controller.ScanForBLEPeripheral(logger.BackgroundCtx)
+	require.NoError(t, err)
+	assert.Equal(t, fakeSensorBDAddr, result.Address.String())
+	assert.Equal(t, int16(-62), controller.RSSILast())
+	assert.Equal(t, "Speed 2", controller.LocalNameLast())
+	assert.Equal(t, 1, adapter.stopScanned, "scan should stop once the peripheral is found")
+
+	device, err := controller.ConnectToBLEPeripheral(logger.BackgroundCtx, result)
+	require.NoError(t, err)
+	assert.NotNil(t, device)
+	assert.Equal(t, []bluetooth.Address{result.Address}, adapter.connected)
+
+}
+
+// TestScanRetryWithFakeAdapter tests retrying a scan for a BLE peripheral that only starts
+// advertising after the first scan
+func TestScanRetryWithFakeAdapter(t *testing.T) {
+
+	adapter := &fakeAdapter{
+		results:    []bluetooth.ScanResult{fakeScanResult(t, fakeSensorBDAddr)},
+		emptyScans: 1,
+	}
+
+	controller := newFakeAdapterController(t, adapter)
+	controller.blePeripheralDetails.bleConfig.ScanAttempts = 2
+	controller.blePeripheralDetails.bleConfig.ScanTimeoutSecs = 3 // Two 1s attempts and the pause between them
+
+	var progress []int
+	controller.SetScanProgress(func(attempt, attempts int) {
+		assert.Equal(t, 2, attempts)
+		progress = append(progress, attempt)
+	})
+
+	result, err := controller.ScanForBLEPeripheral(logger.BackgroundCtx)
+	require.NoError(t, err)
+	assert.Equal(t, fakeSensorBDAddr, result.Address.String())
+	assert.Equal(t, 2, adapter.scans)
+	assert.Equal(t, []int{1, 2}, progress)
+
+	// A sensor that never wakes up still times out once all attempts are used
+	adapter = &fakeAdapter{emptyScans: 2}
+	controller = newFakeAdapterController(t, adapter)
+	controller.blePeripheralDetails.bleConfig.ScanAttempts = 2
+	controller.blePeripheralDetails.bleConfig.ScanTimeoutSecs = 3 // Two 1s attempts and the pause between them
+
+	_, err = controller.ScanForBLEPeripheral(logger.BackgroundCtx)
+	require.ErrorIs(t, err, ErrScanTimeout)
+	assert.Equal(t, 2, adapter.scans)
+
+}
+
+// TestScanAttemptTimeouts tests splitting the scan timeout across scan attempts
+func TestScanAttemptTimeouts(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		total    time.Duration
+		attempts int
+		want     []time.Duration
+	}{
+		{"single attempt", 30 * time.Second, 1, []time.Duration{30 * time.Second}},
+		{"unset attempts", 30 * time.Second, 0, []time.Duration{30 * time.Second}},
+		{"three attempts", 30 * time.Second, 3, []time.Duration{4 * time.Second, 8 * time.Second, 16 * time.Second}},
+		{"minimum attempt timeout", 5 * time.Second, 3, []time.Duration{time.Second, time.Second, time.Second}},
+		{"attempts limited by timeout", 2 * time.Second, 3, []time.Duration{2 * time.Second}},
+		{"many attempts", 10 * time.Second, 10, []time.Duration{time.Second, time.Second, time.Second, time.Second, 2 * time.Second}},
+		{"timeout under minimum", 1 * time.Second, 10, []time.Duration{time.Second}},
+		{"zero timeout", 0, 3, []time.Duration{time.Second}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			timeouts := scanAttemptTimeouts(tt.total, tt.attempts)
+			assert.Equal(t, tt.want, timeouts)
+
+			// Attempts (and the pauses between them) fit within the scan timeout
+			elapsed := time.Duration(len(timeouts)-1) * scanRetryPause
+			for _, timeout := range timeouts {
+				elapsed += timeout
+			}
+
+			assert.LessOrEqual(t, elapsed, max(tt.total, minScanAttemptTimeout))
+
+		})
+	}
+
+}
+
+// TestScanWakeHintWithFakeAdapter tests hinting at waking a BLE peripheral that hasn't advertised
+// shortly after scanning starts
+func TestScanWakeHintWithFakeAdapter(t *testing.T) {
+
+	defaultDelay := wakeHintDelay
+	wakeHintDelay = 10 * time.Millisecond
+
+	defer func() { wakeHintDelay = defaultDelay }()
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		emptyScans int
+		wantHint   bool
+	}{
+		{"sensor advertising", 0, false},
+		{"sensor asleep", 1, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			adapter := &fakeAdapter{
+				results:    []bluetooth.ScanResult{fakeScanResult(t, fakeSensorBDAddr)},
+				emptyScans: tt.emptyScans,
+			}
+
+			controller := newFakeAdapterController(t, adapter)
+			controller.blePeripheralDetails.bleConfig.ScanAttempts = 2
+			controller.blePeripheralDetails.bleConfig.ScanTimeoutSecs = 3 // Two 1s attempts and the pause between them
+
+			var hinted atomic.Bool
+			controller.SetWakeHint(func() { hinted.Store(true) })
+
+			_, err := controller.ScanForBLEPeripheral(logger.BackgroundCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHint, hinted.Load())
+
+		})
+	}
+
+}
@@ -0,0 +1,83 @@
+package ble
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// ErrSensorInUse is returned when another session has already claimed a BLE sensor
+var ErrSensorInUse = apperr.New(apperr.CodeSensorInUse, "BLE sensor is in use by another session")
+
+// sensorClaims prevents concurrently running sessions from using the same BLE sensor, since a
+// peripheral can only be connected to (and send notifications to) a single controller
+var sensorClaims = struct {
+	sync.Mutex
+	owners map[string]int64
+}{
+	owners: make(map[string]int64),
+}
+
+// ClaimSensor reserves the configured BLE sensor for this controller, returning ErrSensorInUse
+// if another controller has already claimed it
+func (m *Controller) ClaimSensor() error {
+
+	addr := strings.ToUpper(m.blePeripheralDetails.bleConfig.SensorBDAddr)
+
+	sensorClaims.Lock()
+	defer sensorClaims.Unlock()
+
+	if owner, ok := sensorClaims.owners[addr]; ok && owner != m.InstanceID {
+		return fmt.Errorf("%w: %s (controller id:%04d)", ErrSensorInUse, addr, owner)
+	}
+
+	sensorClaims.owners[addr] = m.InstanceID
+
+	return nil
+}
+
+// ReleaseSensor releases the BLE sensor claimed by this controller (if any)
+func (m *Controller) ReleaseSensor() {
+
+	addr := strings.ToUpper(m.blePeripheralDetails.bleConfig.SensorBDAddr)
+
+	sensorClaims.Lock()
+	defer sensorClaims.Unlock()
+
+	if sensorClaims.owners[addr] == m.InstanceID {
+		delete(sensorClaims.owners, addr)
+	}
+
+}
+
+// adapterClaim serializes BLE peripheral scans and connections between concurrently running
+// sessions (holding a token while in use), since sessions using different sensors still share
+// the one BLE adapter (which can only run a single scan at a time)
+var adapterClaim = make(chan struct{}, 1)
+
+// claimAdapter waits until no other controller is scanning for (or connecting to) a BLE
+// peripheral, returning a function that releases the BLE adapter
+func (m *Controller) claimAdapter(ctx context.Context) (func(), error) {
+
+	release := func() { <-adapterClaim }
+
+	select {
+	case adapterClaim <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	logger.Info(ctx, logger.BLE, "BLE adapter in use by another session: waiting...")
+
+	select {
+	case adapterClaim <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf(errFormat, "user interrupt detected", ctx.Err())
+	}
+
+}
@@ -0,0 +1,85 @@
+package ble
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tinygo.org/x/bluetooth"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// newClaimTestController creates a controller suitable for sensor claim tests (no adapter required)
+func newClaimTestController(id int64, addr string) *Controller {
+
+	return &Controller{
+		blePeripheralDetails: blePeripheralDetails{
+			bleConfig: config.BLEConfig{SensorBDAddr: addr},
+		},
+		InstanceID: id,
+	}
+}
+
+// TestClaimSensor tests BLE sensor arbitration between controllers
+func TestClaimSensor(t *testing.T) {
+
+	first := newClaimTestController(9001, "aa:bb:cc:dd:ee:ff")
+	second := newClaimTestController(9002, "AA:BB:CC:DD:EE:FF")
+	other := newClaimTestController(9003, "11:22:33:44:55:66")
+
+	require.NoError(t, first.ClaimSensor())
+	require.NoError(t, first.ClaimSensor(), "re-claiming an owned sensor should succeed")
+	require.NoError(t, other.ClaimSensor(), "claiming a different sensor should succeed")
+
+	assert.ErrorIs(t, second.ClaimSensor(), ErrSensorInUse)
+
+	// Releasing a sensor that isn't owned has no effect
+	second.ReleaseSensor()
+	assert.ErrorIs(t, second.ClaimSensor(), ErrSensorInUse)
+
+	first.ReleaseSensor()
+	require.NoError(t, second.ClaimSensor())
+
+	second.ReleaseSensor()
+	other.ReleaseSensor()
+}
+
+// TestClaimAdapter tests that BLE peripheral scans wait while another controller is using the
+// BLE adapter
+func TestClaimAdapter(t *testing.T) {
+
+	first := newFakeAdapterController(t, &fakeAdapter{})
+	adapter := &fakeAdapter{results: []bluetooth.ScanResult{fakeScanResult(t, fakeSensorBDAddr)}}
+	second := newFakeAdapterController(t, adapter)
+
+	release, err := first.claimAdapter(logger.BackgroundCtx)
+	require.NoError(t, err)
+
+	// A scan gives up waiting for the adapter when its context is done, without scanning
+	ctx, cancel := context.WithTimeout(logger.BackgroundCtx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err = second.ScanForBLEPeripheral(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, adapter.scans, "scan should wait for the adapter")
+
+	// A waiting scan starts once the adapter is released
+	time.AfterFunc(50*time.Millisecond, release)
+
+	result, err := second.ScanForBLEPeripheral(logger.BackgroundCtx)
+	require.NoError(t, err)
+	assert.Equal(t, fakeSensorBDAddr, result.Address.String())
+
+	_, err = second.ConnectToBLEPeripheral(logger.BackgroundCtx, result)
+	require.NoError(t, err)
+
+	// The adapter is released after scanning and connecting
+	release, err = first.claimAdapter(logger.BackgroundCtx)
+	require.NoError(t, err)
+	release()
+
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sync"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"tinygo.org/x/bluetooth"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 )
@@ -23,17 +24,26 @@ type CharacteristicReader interface {
 
 // blePeripheralDetails holds details about the BLE peripheral
 type blePeripheralDetails struct {
-	bleAdapter            bluetooth.Adapter
-	bleCharacteristic     CharacteristicReader
-	batteryCharacteristic CharacteristicReader
-	bleConfig             config.BLEConfig
-	batteryLevel          byte
+	bleAdapter                Adapter
+	bleCharacteristic         CharacteristicReader
+	batteryCharacteristic     CharacteristicReader
+	temperatureCharacteristic CharacteristicReader
+	humidityCharacteristic    CharacteristicReader
+	bleConfig                 config.BLEConfig
+	batteryLevel              byte
+	rssi                      int16
+	localName                 string
 }
 
 // Controller is a central controller for managing the BLE peripheral
 type Controller struct {
 	blePeripheralDetails blePeripheralDetails
 	speedConfig          config.SpeedConfig
+	heartbeat            func()
+	scanProgress         func(attempt, attempts int)
+	wakeHint             func()
+	sensorType           atomic.Value               // config.SensorTypeSpeed or config.SensorTypeCadence, once data is reported
+	environment          atomic.Pointer[EnvReading] // Last reading of an environmental sensor
 	InstanceID           int64
 }
 
@@ -42,10 +52,17 @@ type actionParams[T any] struct {
 	action     func(context.Context, chan<- T, chan<- error)
 	stopAction func() error
 	logMessage string
+	timeout    time.Duration
 }
 
-// Mutex for synchronizing adapter access
-var AdapterMu sync.Mutex
+// BLE scan retry policy
+const (
+	scanRetryPause        = time.Second // Pause between scan attempts (counted against the scan timeout)
+	minScanAttemptTimeout = time.Second
+)
+
+// Time to wait for the BLE peripheral to advertise before hinting that the sensor needs waking
+var wakeHintDelay = 5 * time.Second
 
 // Instance counter to distinguish between controller object instances
 var bleInstanceCounter atomic.Int64
@@ -53,9 +70,10 @@ var bleInstanceCounter atomic.Int64
 // Error definitions
 var (
 	// General BLE errors
-	ErrScanTimeout        = errors.New("scanning time limit reached")
+	ErrScanTimeout        = apperr.New(apperr.CodeSensorNotFound, "scanning time limit reached")
 	ErrNoServicesProvided = errors.New("no services provided for characteristic discovery")
 	ErrTypeMismatch       = errors.New("type mismatch")
+	ErrSensorIsAdapter    = apperr.New(apperr.CodeConfigInvalid, "sensor BD_ADDR is the address of this computer's Bluetooth adapter, not of the BLE sensor")
 
 	// Battery service/characteristic errors
 	ErrNoBatteryServices        = errors.New("no battery services found")
@@ -67,9 +85,16 @@ var (
 	ErrNoCSCServices        = errors.New("no CSC services found")
 	ErrNoCSCCharacteristics = errors.New("no CSC characteristics found")
 
+	// Environmental sensing service/characteristic errors
+	ErrNoEnvServices        = errors.New("no environmental sensing services found")
+	ErrNoEnvCharacteristics = errors.New("no temperature characteristic found")
+	ErrInvalidEnvData       = errors.New("invalid environmental data length")
+	ErrUnknownMeasurement   = errors.New("measurement unknown")
+
 	// Speed data processing errors
 	ErrNoSpeedData        = errors.New("no speed data reported")
 	ErrInvalidSpeedData   = errors.New("invalid data format or length")
+	ErrCadenceOnly        = apperr.New(apperr.CodeSensorUnsupported, "sensor provides cadence only")
 	ErrNotificationEnable = errors.New("failed to enable BLE notifications")
 )
 
@@ -78,21 +103,29 @@ const (
 	errFormat = "%v: %w"
 )
 
-// NewBLEController creates a new BLE central controller for accessing a BLE peripheral
+// NewBLEController creates a new BLE central controller for accessing a BLE peripheral using the
+// system BLE adapter
 func NewBLEController(ctx context.Context, bleConfig config.BLEConfig, speedConfig config.SpeedConfig) (*Controller, error) {
+	return NewBLEControllerWithAdapter(ctx, DefaultAdapter(), bleConfig, speedConfig)
+}
 
-	AdapterMu.Lock()
-	defer AdapterMu.Unlock()
+// NewBLEControllerWithAdapter creates a new BLE central controller for accessing a BLE peripheral
+// using the given BLE adapter
+func NewBLEControllerWithAdapter(ctx context.Context, adapter Adapter, bleConfig config.BLEConfig, speedConfig config.SpeedConfig) (*Controller, error) {
 
 	// Increment instance counter
 	instanceID := bleInstanceCounter.Add(1)
 
 	logger.Debug(ctx, logger.BLE, fmt.Sprintf("creating BLE controller object (id:%04d)...", instanceID))
 
-	bleAdapter := bluetooth.DefaultAdapter
+	if err := adapter.Enable(); err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to enable BLE controller", explainAdapterError(err))
+	}
 
-	if err := bleAdapter.Enable(); err != nil {
-		return nil, fmt.Errorf(errFormat, "failed to enable BLE controller", err)
+	// A common misconfiguration is a sensor BD_ADDR copied from the adapter (e.g., "bluetoothctl
+	// list"), which no scan would ever find
+	if address, err := adapter.Address(); err == nil && address != "" && strings.EqualFold(address, bleConfig.SensorBDAddr) {
+		return nil, fmt.Errorf(errFormat, bleConfig.SensorBDAddr, ErrSensorIsAdapter)
 	}
 
 	logger.Debug(ctx, logger.BLE, fmt.Sprintf("created BLE controller object (id:%04d)", instanceID))
@@ -100,46 +133,144 @@ func NewBLEController(ctx context.Context, bleConfig config.BLEConfig, speedConf
 	return &Controller{
 		blePeripheralDetails: blePeripheralDetails{
 			bleConfig:  bleConfig,
-			bleAdapter: *bleAdapter,
+			bleAdapter: adapter,
 		},
 		speedConfig: speedConfig,
 		InstanceID:  instanceID,
 	}, nil
 }
 
-// ScanForBLEPeripheral scans for a BLE peripheral with the specified BD_ADDR
-func (m *Controller) ScanForBLEPeripheral(ctx context.Context) (bluetooth.ScanResult, error) {
+// SetScanProgress sets the function called as each BLE peripheral scan attempt starts
+func (m *Controller) SetScanProgress(progress func(attempt, attempts int)) {
+	m.scanProgress = progress
+}
 
-	params := actionParams[bluetooth.ScanResult]{
-		action:     m.scanAction,
-		logMessage: "scanning for BLE peripheral BD_ADDR=" + m.blePeripheralDetails.bleConfig.SensorBDAddr,
-		stopAction: m.blePeripheralDetails.bleAdapter.StopScan,
-	}
+// SetWakeHint sets the function called when the BLE peripheral hasn't advertised shortly after
+// scanning starts (i.e., the sensor likely needs waking)
+func (m *Controller) SetWakeHint(hint func()) {
+	m.wakeHint = hint
+}
 
-	result, err := performBLEAction(ctx, m, params)
+// ScanForBLEPeripheral scans for a BLE peripheral with the specified BD_ADDR, retrying with
+// increasingly longer scans (within the scan timeout) since sensors often take a few seconds to
+// wake up
+func (m *Controller) ScanForBLEPeripheral(ctx context.Context) (bluetooth.ScanResult, error) {
+
+	release, err := m.claimAdapter(ctx)
 	if err != nil {
 		return bluetooth.ScanResult{}, err
 	}
+	defer release()
 
-	logger.Info(ctx, logger.BLE, "found BLE peripheral", "BD_ADDR", result.Address.String())
+	timeouts := scanAttemptTimeouts(m.scanTimeout(), m.blePeripheralDetails.bleConfig.ScanAttempts)
 
-	return result, nil
+	// Sensors sleep until the wheel spins, so hint at waking the sensor (and keep scanning)
+	hint := time.AfterFunc(wakeHintDelay, func() {
+		logger.Warn(ctx, logger.BLE, "no advertisement from BLE peripheral yet: spin the wheel to wake the sensor...")
+
+		if m.wakeHint != nil {
+			m.wakeHint()
+		}
+
+	})
+	defer hint.Stop()
+
+	for i, timeout := range timeouts {
+		attempt := i + 1
+
+		if len(timeouts) > 1 {
+			logger.Info(ctx, logger.BLE, fmt.Sprintf("scanning for BLE peripheral (attempt %d/%d)...", attempt, len(timeouts)))
+		}
+
+		if m.scanProgress != nil {
+			m.scanProgress(attempt, len(timeouts))
+		}
+
+		params := actionParams[bluetooth.ScanResult]{
+			action:     m.scanAction,
+			logMessage: "scanning for BLE peripheral BD_ADDR=" + m.blePeripheralDetails.bleConfig.SensorBDAddr,
+			stopAction: m.blePeripheralDetails.bleAdapter.StopScan,
+			timeout:    timeout,
+		}
+
+		var result bluetooth.ScanResult
+
+		result, err = performBLEAction(ctx, m, params)
+		if err == nil {
+			m.blePeripheralDetails.rssi = result.RSSI
+			m.blePeripheralDetails.localName = advertisedName(result)
+			logger.Info(ctx, logger.BLE, "found BLE peripheral", "BD_ADDR", result.Address.String(), "RSSI", result.RSSI)
+
+			return result, nil
+		}
+
+		// Only retry scans that timed out (and only while the caller is still waiting)
+		if !errors.Is(err, ErrScanTimeout) || ctx.Err() != nil || attempt == len(timeouts) {
+			break
+		}
+
+		logger.Info(ctx, logger.BLE, fmt.Sprintf("BLE peripheral not found (attempt %d/%d); retrying...", attempt, len(timeouts)))
+
+		select {
+		case <-ctx.Done():
+			return bluetooth.ScanResult{}, fmt.Errorf(errFormat, "user interrupt detected", ctx.Err())
+		case <-time.After(scanRetryPause):
+		}
+
+	}
+
+	return bluetooth.ScanResult{}, err
+}
+
+// scanTimeout returns the total time allowed to scan for (or connect to) the BLE peripheral
+func (m *Controller) scanTimeout() time.Duration {
+	return time.Duration(m.blePeripheralDetails.bleConfig.ScanTimeoutSecs) * time.Second
+}
+
+// scanAttemptTimeouts splits the scan timeout (less the pauses between attempts) across scan
+// attempts, doubling the timeout of each successive attempt. Attempts are limited to those that fit
+// within the scan timeout, given the minimum attempt timeout and the pauses between attempts, and
+// an attempt given less than the minimum timeout takes its time from the attempts that follow
+func scanAttemptTimeouts(total time.Duration, attempts int) []time.Duration {
+
+	fitting := int((total + scanRetryPause) / (minScanAttemptTimeout + scanRetryPause))
+	attempts = max(min(attempts, fitting), 1)
+
+	remaining := total - time.Duration(attempts-1)*scanRetryPause
+	timeouts := make([]time.Duration, attempts)
+
+	for i := range timeouts {
+		weights := (1 << attempts) - (1 << i) // Sum of the remaining attempt weights (1, 2, 4, ...)
+		timeouts[i] = max(remaining*time.Duration(1<<i)/time.Duration(weights), minScanAttemptTimeout)
+		remaining -= timeouts[i]
+	}
+
+	return timeouts
 }
 
 // ConnectToBLEPeripheral connects to the specified BLE peripheral
-func (m *Controller) ConnectToBLEPeripheral(ctx context.Context, device bluetooth.ScanResult) (bluetooth.Device, error) {
+//
+//nolint:ireturn // Returns the connected peripheral provided by the adapter
+func (m *Controller) ConnectToBLEPeripheral(ctx context.Context, device bluetooth.ScanResult) (Device, error) {
+
+	release, err := m.claimAdapter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	params := actionParams[bluetooth.Device]{
-		action: func(_ context.Context, found chan<- bluetooth.Device, errChan chan<- error) {
+	params := actionParams[Device]{
+		action: func(_ context.Context, found chan<- Device, errChan chan<- error) {
 			m.connectAction(device, found, errChan)
 		},
 		logMessage: "connecting to BLE peripheral BD_ADDR=" + device.Address.String(),
 		stopAction: nil,
+		timeout:    m.scanTimeout(),
 	}
 
 	result, err := performBLEAction(ctx, m, params)
 	if err != nil {
-		return bluetooth.Device{}, err
+		return nil, err
 	}
 
 	logger.Info(ctx, logger.BLE, "BLE peripheral connected")
@@ -152,12 +283,41 @@ func (m *Controller) BatteryLevelLast() byte {
 	return m.blePeripheralDetails.batteryLevel
 }
 
+// RSSILast returns the signal strength (dBm) of the BLE peripheral when it was last found
+func (m *Controller) RSSILast() int16 {
+	return m.blePeripheralDetails.rssi
+}
+
+// advertisedName returns the name advertised in a scan result (if any)
+func advertisedName(result bluetooth.ScanResult) string {
+
+	if result.AdvertisementPayload == nil {
+		return ""
+	}
+
+	return result.LocalName()
+}
+
+// LocalNameLast returns the name advertised by the BLE peripheral when it was last found
+func (m *Controller) LocalNameLast() string {
+	return m.blePeripheralDetails.localName
+}
+
+// SensorType returns the type of the BLE sensor (config.SensorTypeSpeed or
+// config.SensorTypeCadence), or an empty string if the sensor has not yet reported any data
+func (m *Controller) SensorType() string {
+
+	sensorType, _ := m.sensorType.Load().(string)
+
+	return sensorType
+}
+
 // performBLEAction is a wrapper for performing BLE discovery actions
 //
 //nolint:ireturn // Generic function returning T
 func performBLEAction[T any](ctx context.Context, m *Controller, params actionParams[T]) (T, error) {
 
-	scanCtx, cancel := context.WithTimeout(ctx, time.Duration(m.blePeripheralDetails.bleConfig.ScanTimeoutSecs)*time.Second)
+	scanCtx, cancel := context.WithTimeout(ctx, params.timeout)
 	defer cancel()
 	found := make(chan T, 1)
 	done := make(chan struct{})
@@ -237,7 +397,7 @@ func (m *Controller) scanAction(ctx context.Context, found chan<- bluetooth.Scan
 }
 
 // connectAction performs the connection to the BLE peripheral
-func (m *Controller) connectAction(device bluetooth.ScanResult, found chan<- bluetooth.Device, errChan chan<- error) {
+func (m *Controller) connectAction(device bluetooth.ScanResult, found chan<- Device, errChan chan<- error) {
 
 	dev, err := m.blePeripheralDetails.bleAdapter.Connect(device.Address, bluetooth.ConnectionParams{})
 	if err != nil {
@@ -253,20 +413,17 @@ func (m *Controller) connectAction(device bluetooth.ScanResult, found chan<- blu
 // startScanning starts the BLE peripheral scan and handles device discovery
 func (m *Controller) startScanning(ctx context.Context, found chan<- bluetooth.ScanResult) error {
 
-	AdapterMu.Lock()
-	defer AdapterMu.Unlock()
-
 	// Use an atomic flag to ensure we only trigger the device discovery logic once
 	var foundOnce atomic.Bool
 
-	err := m.blePeripheralDetails.bleAdapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
+	err := m.blePeripheralDetails.bleAdapter.Scan(func(result bluetooth.ScanResult) {
 
 		// Address comparison
 		if result.Address.String() == m.blePeripheralDetails.bleConfig.SensorBDAddr {
 
 			if foundOnce.CompareAndSwap(false, true) {
 				logger.Debug(ctx, logger.BLE, "BLE peripheral found; stopping scan...")
-				_ = adapter.StopScan()
+				_ = m.blePeripheralDetails.bleAdapter.StopScan()
 
 				select {
 				case found <- result:
@@ -281,7 +438,7 @@ func (m *Controller) startScanning(ctx context.Context, found chan<- bluetooth.S
 	})
 
 	if err != nil {
-		return fmt.Errorf(errFormat, "unable to start BLE scan", err)
+		return fmt.Errorf(errFormat, "unable to start BLE scan", explainAdapterError(err))
 	}
 
 	return nil
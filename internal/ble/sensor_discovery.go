@@ -0,0 +1,112 @@
+package ble
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"tinygo.org/x/bluetooth"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// DiscoveredSensor is a BLE peripheral found advertising the Cycling Speed and Cadence (CSC)
+// service
+type DiscoveredSensor struct {
+	Address string
+	Name    string
+	RSSI    int16
+}
+
+// DiscoverSensors scans for BLE peripherals advertising the CSC service until the context is
+// done, returning the sensors found (strongest signal first)
+func DiscoverSensors(ctx context.Context, adapter Adapter) ([]DiscoveredSensor, error) {
+
+	if err := adapter.Enable(); err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to enable BLE adapter", explainAdapterError(err))
+	}
+
+	logger.Debug(ctx, logger.BLE, "scanning for BLE CSC sensors...")
+
+	var (
+		mu      sync.Mutex
+		sensors = make(map[string]DiscoveredSensor)
+	)
+
+	scanDone := make(chan error, 1)
+
+	go func() {
+
+		scanDone <- adapter.Scan(func(result bluetooth.ScanResult) {
+
+			if result.AdvertisementPayload == nil || !result.HasServiceUUID(bluetooth.ServiceUUIDCyclingSpeedAndCadence) {
+				return
+			}
+
+			sensor := DiscoveredSensor{
+				Address: result.Address.String(),
+				Name:    result.LocalName(),
+				RSSI:    result.RSSI,
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Keep the name of a sensor that omits it from later advertisements
+			if previous, ok := sensors[sensor.Address]; ok && sensor.Name == "" {
+				sensor.Name = previous.Name
+			}
+
+			sensors[sensor.Address] = sensor
+		})
+
+	}()
+
+	var err error
+
+	select {
+
+	case err = <-scanDone:
+
+	case <-ctx.Done():
+
+		if stopErr := adapter.StopScan(); stopErr != nil {
+			logger.Warn(ctx, logger.BLE, fmt.Sprintf("failed to stop scan: %v", stopErr))
+		}
+
+		err = <-scanDone
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "unable to start BLE scan", explainAdapterError(err))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	found := make([]DiscoveredSensor, 0, len(sensors))
+	for _, sensor := range sensors {
+		found = append(found, sensor)
+	}
+
+	slices.SortFunc(found, func(a, b DiscoveredSensor) int {
+		return cmp.Or(cmp.Compare(b.RSSI, a.RSSI), cmp.Compare(a.Address, b.Address))
+	})
+
+	logger.Debug(ctx, logger.BLE, fmt.Sprintf("found %d BLE CSC sensor(s)", len(found)))
+
+	return found, nil
+}
+
+// String returns a description of the discovered sensor
+func (s DiscoveredSensor) String() string {
+
+	name := s.Name
+	if name == "" {
+		name = "Unnamed sensor"
+	}
+
+	return fmt.Sprintf("%s (%s, %d dBm)", name, s.Address, s.RSSI)
+}
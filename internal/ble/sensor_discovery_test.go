@@ -0,0 +1,107 @@
+package ble
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tinygo.org/x/bluetooth"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// fakePayload is a fake implementation of bluetooth.AdvertisementPayload
+type fakePayload struct {
+	name     string
+	services []bluetooth.UUID
+}
+
+// LocalName fakes the LocalName method
+func (p fakePayload) LocalName() string {
+	return p.name
+}
+
+// HasServiceUUID fakes the HasServiceUUID method
+func (p fakePayload) HasServiceUUID(uuid bluetooth.UUID) bool {
+
+	for _, service := range p.services {
+
+		if service == uuid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ServiceUUIDs fakes the ServiceUUIDs method
+func (p fakePayload) ServiceUUIDs() []bluetooth.UUID {
+	return p.services
+}
+
+// Bytes fakes the Bytes method
+func (p fakePayload) Bytes() []byte {
+	return nil
+}
+
+// ManufacturerData fakes the ManufacturerData method
+func (p fakePayload) ManufacturerData() []bluetooth.ManufacturerDataElement {
+	return nil
+}
+
+// ServiceData fakes the ServiceData method
+func (p fakePayload) ServiceData() []bluetooth.ServiceDataElement {
+	return nil
+}
+
+// fakeAdvertisement creates a scan result for the given BD_ADDR, name, and signal strength
+func fakeAdvertisement(t *testing.T, addr, name string, rssi int16, services ...bluetooth.UUID) bluetooth.ScanResult {
+
+	t.Helper()
+
+	result := fakeScanResult(t, addr)
+	result.RSSI = rssi
+	result.AdvertisementPayload = fakePayload{name: name, services: services}
+
+	return result
+}
+
+// TestDiscoverSensors tests discovering BLE CSC sensors
+func TestDiscoverSensors(t *testing.T) {
+
+	csc := bluetooth.ServiceUUIDCyclingSpeedAndCadence
+
+	adapter := &fakeAdapter{
+		results: []bluetooth.ScanResult{
+			fakeScanResult(t, "00:00:00:00:00:01"),
+			fakeAdvertisement(t, "00:00:00:00:00:02", "Heart Rate", -40, bluetooth.ServiceUUIDHeartRate),
+			fakeAdvertisement(t, "00:00:00:00:00:03", "Speed Sensor", -70, csc),
+			fakeAdvertisement(t, fakeSensorBDAddr, "Cadence Sensor", -50, csc),
+			fakeAdvertisement(t, "00:00:00:00:00:03", "", -60, csc),
+		},
+	}
+
+	sensors, err := DiscoverSensors(logger.BackgroundCtx, adapter)
+	require.NoError(t, err)
+
+	assert.Equal(t, []DiscoveredSensor{
+		{Address: fakeSensorBDAddr, Name: "Cadence Sensor", RSSI: -50},
+		{Address: "00:00:00:00:00:03", Name: "Speed Sensor", RSSI: -60},
+	}, sensors)
+
+	assert.Equal(t, "Cadence Sensor (AA:BB:CC:DD:EE:FF, -50 dBm)", sensors[0].String())
+	assert.Equal(t, "Unnamed sensor (00:00:00:00:00:01, 0 dBm)", DiscoveredSensor{Address: "00:00:00:00:00:01"}.String())
+
+}
+
+// TestDiscoverSensorsEnableError tests discovering sensors when the adapter can't be enabled
+func TestDiscoverSensorsEnableError(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(logger.BackgroundCtx)
+	defer cancel()
+
+	_, err := DiscoverSensors(ctx, &fakeAdapter{enableErr: errEnableFailed})
+	require.ErrorIs(t, err, errEnableFailed)
+
+}
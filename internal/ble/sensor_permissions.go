@@ -0,0 +1,219 @@
+package ble
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+)
+
+// Bluetooth permission errors
+var (
+	ErrBluetoothPermission = apperr.New(apperr.CodeBluetoothSetup, "insufficient Bluetooth permissions")
+	ErrNoBluetoothAdapter  = apperr.New(apperr.CodeBluetoothSetup, "no Bluetooth adapter found")
+	ErrAdapterNotPowered   = apperr.New(apperr.CodeBluetoothSetup, "adapter not powered on")
+)
+
+// Bluetooth permission settings
+const (
+	bluetoothGroup  = "bluetooth"
+	capNetAdminBit  = 12 // CAP_NET_ADMIN (see linux/capability.h)
+	procSelfStatus  = "/proc/self/status"
+	remedyGroup     = "add your user to the 'bluetooth' group ('sudo usermod -aG bluetooth $USER'), and then log out and back in"
+	remedyPowerOn   = "power on the Bluetooth adapter ('bluetoothctl power on')"
+	remedyAdapter   = "connect a Bluetooth 4.0+ (BLE) adapter, and confirm that it's listed by 'bluetoothctl list'"
+	remedyServices  = "start the D-Bus and BlueZ services ('sudo systemctl start dbus bluetooth')"
+	remedyUnblock   = "unblock Bluetooth ('rfkill unblock bluetooth')"
+	remedySetcapFmt = "grant the application network capabilities ('sudo setcap cap_net_raw,cap_net_admin+eip %s')"
+)
+
+// PermissionIssue describes a Bluetooth permission (or capability) problem and how to fix it
+type PermissionIssue struct {
+	Problem string
+	Remedy  string
+}
+
+// permissionEnv captures the parts of the host environment checked for Bluetooth permissions
+type permissionEnv struct {
+	userGroups      func() ([]string, error)
+	groupExists     func(name string) bool
+	systemBusSocket string
+	sysfsRoot       string
+	uid             int
+}
+
+// hostPermissionEnv returns the permission environment of the running process
+func hostPermissionEnv() permissionEnv {
+
+	return permissionEnv{
+		userGroups:      currentUserGroups,
+		groupExists:     func(name string) bool { _, err := user.LookupGroup(name); return err == nil },
+		systemBusSocket: "/var/run/dbus/system_bus_socket",
+		sysfsRoot:       "/sys",
+		uid:             os.Getuid(),
+	}
+}
+
+// CheckPermissions detects the common Bluetooth permission (and capability) problems that
+// otherwise cause opaque BLE scan failures, returning each problem with its remedy
+func CheckPermissions() []PermissionIssue {
+	return checkPermissions(hostPermissionEnv())
+}
+
+// checkPermissions detects Bluetooth permission problems in the given environment
+func checkPermissions(env permissionEnv) []PermissionIssue {
+
+	var issues []PermissionIssue
+
+	if _, err := os.Stat(env.systemBusSocket); err != nil {
+		issues = append(issues, PermissionIssue{Problem: "the D-Bus system bus is not available", Remedy: remedyServices})
+	}
+
+	adapters, _ := filepath.Glob(filepath.Join(env.sysfsRoot, "class", "bluetooth", "hci*"))
+	if len(adapters) == 0 {
+		issues = append(issues, PermissionIssue{Problem: ErrNoBluetoothAdapter.Error(), Remedy: remedyAdapter})
+	}
+
+	if rfkillBlocked(env.sysfsRoot) {
+		issues = append(issues, PermissionIssue{Problem: "Bluetooth is blocked (rfkill)", Remedy: remedyUnblock})
+	}
+
+	// Some distributions restrict BlueZ access to members of the bluetooth group
+	if env.uid != 0 && env.groupExists(bluetoothGroup) {
+
+		groups, err := env.userGroups()
+		if err == nil && !slices.Contains(groups, bluetoothGroup) {
+			issues = append(issues, PermissionIssue{Problem: "your user is not a member of the 'bluetooth' group", Remedy: remedyGroup})
+		}
+	}
+
+	return issues
+}
+
+// explainAdapterError maps the opaque errors reported by the BLE adapter for common permission
+// and adapter problems to specific errors that include a remedy
+func explainAdapterError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(msg, "accessdenied"), strings.Contains(msg, "notpermitted"),
+		strings.Contains(msg, "operation not permitted"), strings.Contains(msg, "permission denied"):
+
+		remedies := []string{remedyGroup}
+		if !hasCapNetAdmin(procSelfStatus) {
+			remedies = append(remedies, fmt.Sprintf(remedySetcapFmt, executablePath()))
+		}
+
+		return fmt.Errorf("%w: %v (to fix: %s)", ErrBluetoothPermission, err, strings.Join(remedies, ", or "))
+
+	case strings.Contains(msg, "does not exist"), strings.Contains(msg, "no such file or directory"):
+		return fmt.Errorf("%w: %v (to fix: %s, and %s)", ErrNoBluetoothAdapter, err, remedyAdapter, remedyServices)
+
+	case strings.Contains(msg, "notready"), strings.Contains(msg, "not powered"):
+		return fmt.Errorf("%w: %v (to fix: %s, or %s)", ErrAdapterNotPowered, err, remedyPowerOn, remedyUnblock)
+	}
+
+	return err
+}
+
+// rfkillBlocked returns true if any Bluetooth rfkill switch is blocked (by hardware or software)
+func rfkillBlocked(sysfsRoot string) bool {
+
+	switches, _ := filepath.Glob(filepath.Join(sysfsRoot, "class", "rfkill", "rfkill*"))
+
+	for _, sw := range switches {
+
+		if readAttribute(filepath.Join(sw, "type")) != bluetoothGroup {
+			continue
+		}
+
+		if readAttribute(filepath.Join(sw, "soft")) == "1" || readAttribute(filepath.Join(sw, "hard")) == "1" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasCapNetAdmin returns true if the process has the CAP_NET_ADMIN capability in effect
+func hasCapNetAdmin(procStatus string) bool {
+
+	data, err := os.ReadFile(procStatus)
+	if err != nil {
+		return false
+	}
+
+	for line := range strings.Lines(string(data)) {
+
+		value, found := strings.CutPrefix(line, "CapEff:")
+		if !found {
+			continue
+		}
+
+		caps, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return false
+		}
+
+		return caps&(1<<capNetAdminBit) != 0
+	}
+
+	return false
+}
+
+// currentUserGroups returns the names of the groups of the current user
+func currentUserGroups() ([]string, error) {
+
+	current, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := current.GroupIds()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+
+		if group, err := user.LookupGroupId(id); err == nil {
+			names = append(names, group.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// executablePath returns the path of the running executable (or its name if unavailable)
+func executablePath() string {
+
+	path, err := os.Executable()
+	if err != nil {
+		return "ble-sync-cycle"
+	}
+
+	return path
+}
+
+// readAttribute returns the trimmed contents of a sysfs attribute file (or an empty string)
+func readAttribute(path string) string {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,118 @@
+package ble
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAttribute creates a fake sysfs (or procfs) file
+func writeAttribute(t *testing.T, path, value string) {
+
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+
+}
+
+// newTestPermissionEnv creates a permission environment with a working adapter and system bus
+func newTestPermissionEnv(t *testing.T, groups []string) permissionEnv {
+
+	t.Helper()
+
+	root := t.TempDir()
+	socket := filepath.Join(root, "system_bus_socket")
+	writeAttribute(t, socket, "")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "class", "bluetooth", "hci0"), 0o755))
+
+	return permissionEnv{
+		userGroups:      func() ([]string, error) { return groups, nil },
+		groupExists:     func(_ string) bool { return true },
+		systemBusSocket: socket,
+		sysfsRoot:       root,
+		uid:             1000,
+	}
+}
+
+// TestCheckPermissions tests detection of common Bluetooth permission problems
+func TestCheckPermissions(t *testing.T) {
+
+	env := newTestPermissionEnv(t, []string{"users", bluetoothGroup})
+	assert.Empty(t, checkPermissions(env))
+
+	// Not a member of the bluetooth group
+	env = newTestPermissionEnv(t, []string{"users"})
+	issues := checkPermissions(env)
+	require.Len(t, issues, 1)
+	assert.Equal(t, remedyGroup, issues[0].Remedy)
+
+	// Root doesn't need bluetooth group membership
+	env.uid = 0
+	assert.Empty(t, checkPermissions(env))
+
+	// Blocked adapter, and no system bus
+	sw := filepath.Join(env.sysfsRoot, "class", "rfkill", "rfkill0")
+	writeAttribute(t, filepath.Join(sw, "type"), bluetoothGroup)
+	writeAttribute(t, filepath.Join(sw, "soft"), "1")
+	writeAttribute(t, filepath.Join(sw, "hard"), "0")
+	env.systemBusSocket = filepath.Join(env.sysfsRoot, "missing")
+
+	issues = checkPermissions(env)
+	require.Len(t, issues, 2)
+	assert.Equal(t, remedyServices, issues[0].Remedy)
+	assert.Equal(t, remedyUnblock, issues[1].Remedy)
+
+}
+
+// TestExplainAdapterError tests mapping opaque adapter errors to specific errors
+func TestExplainAdapterError(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		err      error
+		expected error
+		name     string
+	}{
+		{errors.New("org.freedesktop.DBus.Error.AccessDenied: rejected send message"), ErrBluetoothPermission, "access denied"},
+		{errors.New("org.bluez.Error.NotPermitted"), ErrBluetoothPermission, "not permitted"},
+		{errors.New("bluetooth: adapter /org/bluez/hci0 does not exist"), ErrNoBluetoothAdapter, "no adapter"},
+		{errors.New("org.bluez.Error.NotReady: Resource Not Ready"), ErrAdapterNotPowered, "not powered"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := explainAdapterError(tt.err)
+			require.ErrorIs(t, err, tt.expected)
+			assert.Contains(t, err.Error(), "to fix:")
+
+		})
+	}
+
+	other := errors.New("some other failure")
+	assert.Equal(t, other, explainAdapterError(other))
+	assert.NoError(t, explainAdapterError(nil))
+
+}
+
+// TestHasCapNetAdmin tests reading the effective capabilities of the process
+func TestHasCapNetAdmin(t *testing.T) {
+
+	status := filepath.Join(t.TempDir(), "status")
+
+	writeAttribute(t, status, "Name:\tbsc\nCapEff:\t0000000000001000\n")
+	assert.True(t, hasCapNetAdmin(status))
+
+	writeAttribute(t, status, "Name:\tbsc\nCapEff:\t0000000000000000\n")
+	assert.False(t, hasCapNetAdmin(status))
+
+	assert.False(t, hasCapNetAdmin(status+".missing"))
+
+}
@@ -183,6 +183,7 @@ func executeAction[T any](ctx context.Context, m *Controller, logMessage string,
 		action:     action,
 		logMessage: logMessage,
 		stopAction: nil,
+		timeout:    m.scanTimeout(),
 	}
 
 	return performBLEAction(ctx, m, params)
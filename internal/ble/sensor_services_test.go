@@ -125,7 +125,8 @@ func createTestBLEController(t *testing.T) *Controller {
 
 	t.Helper()
 
-	controller, err := NewBLEController(logger.BackgroundCtx,
+	controller, err := NewBLEControllerWithAdapter(logger.BackgroundCtx,
+		&fakeAdapter{},
 		config.BLEConfig{ScanTimeoutSecs: 10},
 		config.SpeedConfig{},
 	)
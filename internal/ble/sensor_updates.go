@@ -3,95 +3,270 @@ package ble
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
 )
 
 const (
-	minDataLength = 7           // Data length as defined in BLE CSC specification
-	wheelRevFlag  = uint8(0x01) // Wheel revolutions flag as defined in BLE CSC specification
-	mphConversion = 0.621371    // Conversion factor for miles per hour
+	minDataLength      = 7           // Data length as defined in BLE CSC specification
+	minCrankDataLength = 5           // Data length of crank-only data as defined in BLE CSC specification
+	wheelRevFlag       = uint8(0x01) // Wheel revolutions flag as defined in BLE CSC specification
+	crankRevFlag       = uint8(0x02) // Crank revolutions flag as defined in BLE CSC specification
+	maxWheelSpeedMPS   = 30.0        // Faster wheel speeds (108 km/h) are rejected as sensor glitches
+	maxCrankRPM        = 250.0       // Faster cadences are rejected as sensor glitches
 )
 
 // speedData represents the values needed to calculate the speed
 type speedData struct {
-	wheelTime     uint16
-	lastWheelTime uint16
-	wheelRevs     uint32
-	lastWheelRevs uint32
-	distance      float64
+	wheelTime        uint16
+	lastWheelTime    uint16
+	wheelRevs        uint32
+	lastWheelRevs    uint32
+	wheelInitialized bool
+	distance         float64
+	lastSpeed        float64
+	rejected         bool // True if the last data was rejected (counter moved backward or speed spike)
+
+	// Crank data (used by cadence-driven playback)
+	crankOnly        bool // True if the last data reported crank revolutions only
+	crankTime        uint16
+	lastCrankTime    uint16
+	crankRevs        uint16
+	lastCrankRevs    uint16
+	crankInitialized bool
 
 	// Pre-calculated speed constants
 	wheelCircumferenceM   float64 // wheelCircumferenceMM / 1000
 	timeConversionFactor  float64 // 1/1024 seconds (BLE CSC specification time interval)
-	speedConversionFactor float64 // 3.6 * speedUnitMultiplier
-}
-
-// unitConversion maps speed units to their respective conversion factors
-var unitConversion = map[string]float64{
-	config.SpeedUnitsKMH: 1.0,
-	config.SpeedUnitsMPH: mphConversion,
+	speedConversionFactor float64 // Meters per second to speed units
+	cadenceSpeedPerRPM    float64 // Virtual speed per crank RPM (0 disables cadence-driven playback)
 }
 
 // initSpeedData initializes the speedData struct with pre-calculated constants
-func initSpeedData(wheelCircumferenceMM int, speedUnitMultiplier float64) *speedData {
+func initSpeedData(speedConfig config.SpeedConfig) *speedData {
+
 	return &speedData{
-		wheelCircumferenceM:   float64(wheelCircumferenceMM) / 1000,
+		wheelCircumferenceM:   float64(speedConfig.WheelCircumferenceMM) / units.MillimetersPerMeter,
 		timeConversionFactor:  1.0 / 1024,
-		speedConversionFactor: 3.6 * speedUnitMultiplier,
+		speedConversionFactor: speedConfig.Converter().FromMetersPerSecond(1),
+		cadenceSpeedPerRPM:    speedConfig.CadenceSpeedPerRPM,
+	}
+}
+
+// SetHeartbeat sets the function called each time a BLE sensor notification is received
+func (m *Controller) SetHeartbeat(heartbeat func()) {
+	m.heartbeat = heartbeat
+}
+
+// The BLE controller is a speed source for the speed controller
+var _ speed.SpeedSource = (*Controller)(nil)
+
+// Name returns the name of the BLE speed source
+func (m *Controller) Name() string {
+	return "BLE"
+}
+
+// Units returns the speed units of the speeds provided by the BLE speed source
+func (m *Controller) Units() string {
+	return m.speedConfig.SpeedUnits
+}
+
+// Updates sends speeds calculated from BLE sensor notifications to the updater
+func (m *Controller) Updates(ctx context.Context, updater speed.Updater) error {
+	return m.BLEUpdates(ctx, updater)
+}
+
+// recordSensorType records the type of the BLE sensor from the data it reports
+func (m *Controller) recordSensorType(crankOnly bool) {
+
+	if crankOnly {
+		m.sensorType.Store(config.SensorTypeCadence)
+
+		return
 	}
+
+	m.sensorType.Store(config.SensorTypeSpeed)
+
 }
 
 // BLEUpdates starts the real-time monitoring of BLE sensor notifications
-func (m *Controller) BLEUpdates(ctx context.Context, speedController *speed.Controller) error {
+func (m *Controller) BLEUpdates(ctx context.Context, speedController speed.Updater) error {
 
 	logger.Debug(ctx, logger.BLE, "starting the monitoring for BLE sensor notifications...")
 
 	errChan := make(chan error, 1)
+	sensorErr := make(chan error, 1)
 
 	// Precalculate speed data values
-	speedUnitMultiplier := unitConversion[m.speedConfig.SpeedUnits]
-	sd := initSpeedData(m.speedConfig.WheelCircumferenceMM, speedUnitMultiplier)
+	sd := initSpeedData(m.speedConfig)
+
+	// Export raw CSC samples if requested (e.g., to debug sensor math)
+	exporter, err := m.openSampleExport(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Log raw CSC notification payloads if requested (e.g., to debug nonstandard sensor frames)
+	traffic, err := m.openTrafficLog(ctx)
+	if err != nil {
+		m.closeSampleExport(ctx, exporter)
+
+		return err
+	}
 
 	// notificationHandler processes the BLE speed data
 	notificationHandler := func(buf []byte) {
+
+		if m.heartbeat != nil {
+			m.heartbeat()
+		}
+
+		if traffic != nil {
+
+			if err := traffic.write(time.Now(), buf); err != nil {
+				logger.Warn(ctx, logger.BLE, fmt.Sprintf("failed to log BLE traffic: %v", err))
+			}
+
+		}
+
 		speed, err := sd.processBLESpeed(ctx, m.speedConfig.SpeedUnits, buf)
+
+		// A cadence-only sensor will never report wheel data, so stop monitoring it
+		if errors.Is(err, ErrCadenceOnly) {
+			m.sensorType.Store(config.SensorTypeCadence)
+
+			select {
+			case sensorErr <- err:
+			default:
+			}
+
+			return
+		}
+
 		if err != nil {
 			logger.Warn(ctx, logger.SPEED, fmt.Sprintf("error processing BLE speed data: %v", err))
 
 			return
 		}
 
+		m.recordSensorType(sd.crankOnly)
+
+		if exporter != nil {
+
+			if err := exporter.write(time.Now(), sd.wheelRevs, sd.wheelTime, speed); err != nil {
+				logger.Warn(ctx, logger.BLE, fmt.Sprintf("failed to export BLE sample: %v", err))
+			}
+
+		}
+
 		speedController.UpdateSpeed(ctx, speed)
 	}
 
 	// Enable real-time notifications from BLE sensor
 	if err := m.blePeripheralDetails.bleCharacteristic.EnableNotifications(notificationHandler); err != nil {
+		m.closeSampleExport(ctx, exporter)
+		m.closeTrafficLog(ctx, traffic)
+
 		return fmt.Errorf(errFormat, ErrNotificationEnable, err)
 	}
 
-	// Manage context cancellation
+	// Manage context cancellation (or an unusable sensor)
 	go func() {
-		<-ctx.Done()
 
-		logger.Debug(ctx, logger.BLE, "interrupt detected, stopping the monitoring for BLE sensor notifications...")
+		var err error
+
+		select {
+		case <-ctx.Done():
+			logger.Debug(ctx, logger.BLE, "interrupt detected, stopping the monitoring for BLE sensor notifications...")
+
+		case err = <-sensorErr:
+			logger.Error(ctx, logger.BLE, fmt.Sprintf("%v: set cadence_speed_per_rpm in the [speed] section to enable cadence-driven playback", err))
+		}
 
 		// Disable real-time notifications from BLE sensor
 		if err := m.blePeripheralDetails.bleCharacteristic.EnableNotifications(nil); err != nil {
 			logger.Error(ctx, logger.BLE, fmt.Sprintf("failed to disable BLE notifications: %v", err))
 		}
 
-		errChan <- nil
+		m.closeSampleExport(ctx, exporter)
+		m.closeTrafficLog(ctx, traffic)
+
+		errChan <- err
 		close(errChan)
 	}()
 
 	return <-errChan
 }
 
+// openSampleExport opens the raw CSC sample export file, returning nil if no export is configured
+func (m *Controller) openSampleExport(ctx context.Context) (*sampleExporter, error) {
+
+	path := m.blePeripheralDetails.bleConfig.SampleExportFile
+	if path == "" {
+		return nil, nil //nolint:nilnil // no sample export configured
+	}
+
+	exporter, err := newSampleExporter(path)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, logger.BLE, "exporting raw BLE sensor samples to "+path)
+
+	return exporter, nil
+}
+
+// closeSampleExport flushes and closes the raw CSC sample export file (if any)
+func (m *Controller) closeSampleExport(ctx context.Context, exporter *sampleExporter) {
+
+	if exporter == nil {
+		return
+	}
+
+	if err := exporter.close(); err != nil {
+		logger.Error(ctx, logger.BLE, fmt.Sprintf("failed to close sample export file: %v", err))
+	}
+
+}
+
+// openTrafficLog opens the BLE traffic log file, returning nil if no traffic log is configured
+func (m *Controller) openTrafficLog(ctx context.Context) (*trafficLogger, error) {
+
+	path := m.blePeripheralDetails.bleConfig.TrafficLogFile
+	if path == "" {
+		return nil, nil //nolint:nilnil // no traffic log configured
+	}
+
+	traffic, err := newTrafficLogger(path)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, logger.BLE, "logging raw BLE sensor traffic to "+path)
+
+	return traffic, nil
+}
+
+// closeTrafficLog flushes and closes the BLE traffic log file (if any)
+func (m *Controller) closeTrafficLog(ctx context.Context, traffic *trafficLogger) {
+
+	if traffic == nil {
+		return
+	}
+
+	if err := traffic.close(); err != nil {
+		logger.Error(ctx, logger.BLE, fmt.Sprintf("failed to close BLE traffic log file: %v", err))
+	}
+
+}
+
 // processBLESpeed processes raw BLE speed data into human-readable speed values
 func (sd *speedData) processBLESpeed(ctx context.Context, speedUnits string, speedData []byte) (float64, error) {
 
@@ -100,6 +275,11 @@ func (sd *speedData) processBLESpeed(ctx context.Context, speedUnits string, spe
 	}
 
 	speed := sd.calculateSpeed()
+
+	if sd.rejected {
+		logger.Debug(ctx, logger.SPEED, fmt.Sprintf("BLE sensor data rejected (counter moved backward or speed spike): holding speed at %.2f %s", speed, speedUnits))
+	}
+
 	logger.Debug(ctx, logger.SPEED, fmt.Sprintf("%sBLE sensor speed: %.2f %s", logger.Blue, speed, speedUnits))
 
 	return speed, nil
@@ -108,44 +288,128 @@ func (sd *speedData) processBLESpeed(ctx context.Context, speedUnits string, spe
 // calculateSpeed calculates the speed from the raw BLE data
 func (sd *speedData) calculateSpeed() float64 {
 
-	// Initialize last wheel revs and time if they are zero
-	if sd.lastWheelTime == 0 {
+	sd.rejected = false
+
+	if sd.crankOnly {
+		return sd.calculateCadenceSpeed()
+	}
+
+	// Initialize last wheel revs and time on the first data received
+	if !sd.wheelInitialized {
 		return sd.initializeWheelData()
 	}
 
-	// Get the rev and time differences (in 1/1024 seconds) between the current and last wheel revs
-	revDiff := sd.wheelRevs - sd.lastWheelRevs
+	// Get the rev and time differences (in 1/1024 seconds) between the current and last wheel revs,
+	// using modular arithmetic so that counter rollovers yield the correct (small) differences
+	revDiff := int32(sd.wheelRevs - sd.lastWheelRevs) //nolint:gosec // Signed modular difference
 	timeDiff := sd.wheelTime - sd.lastWheelTime
 
+	// A counter that moves backward (jitter or a sensor reset) can't be used, so start over
+	if revDiff < 0 {
+		sd.rejected = true
+		sd.initializeWheelData()
+
+		return sd.lastSpeed
+	}
+
 	// Early exit if no data has changed
 	if timeDiff == 0 || revDiff == 0 {
+		sd.lastSpeed = 0.0
+
 		return 0.0
 	}
 
-	// Calculate the distance (in meters)
+	// Calculate the distance (in meters) and speed (in meters per second)
 	distance := float64(revDiff) * sd.wheelCircumferenceM
+	speedMPS := distance / (float64(timeDiff) * sd.timeConversionFactor)
+
+	// Reject implausible speed spikes (e.g., missed notifications spanning a wheel time rollover)
+	if speedMPS > maxWheelSpeedMPS {
+		sd.rejected = true
+		sd.initializeWheelData()
+
+		return sd.lastSpeed
+	}
 
 	// Update the total distance cycled
 	sd.distance += distance
 
-	// Calculate the speed in km/h or mph
-	speed := (distance / (float64(timeDiff) * sd.timeConversionFactor)) * sd.speedConversionFactor
-
-	// Round the speed to two decimal places
-	speed = math.Round(speed*100) / 100
+	// Calculate the speed in the configured speed units, rounded to two decimal places
+	speed := math.Round(speedMPS*sd.speedConversionFactor*100) / 100
 
 	// Update the last values for next calculation
 	sd.lastWheelRevs = sd.wheelRevs
 	sd.lastWheelTime = sd.wheelTime
+	sd.lastSpeed = speed
+
+	return speed
+}
+
+// calculateCadenceSpeed calculates a virtual speed from the crank revolutions per minute of
+// crank-only BLE data
+func (sd *speedData) calculateCadenceSpeed() float64 {
+
+	// Initialize last crank revs and time on the first data received
+	if !sd.crankInitialized {
+		return sd.initializeCrankData()
+	}
+
+	// Get the rev and time differences (in 1/1024 seconds) between the current and last crank revs,
+	// using modular arithmetic so that counter rollovers yield the correct (small) differences
+	revDiff := int16(sd.crankRevs - sd.lastCrankRevs) //nolint:gosec // Signed modular difference
+	timeDiff := sd.crankTime - sd.lastCrankTime
+
+	// A counter that moves backward (jitter or a sensor reset) can't be used, so start over
+	if revDiff < 0 {
+		sd.rejected = true
+		sd.initializeCrankData()
+
+		return sd.lastSpeed
+	}
+
+	// Early exit if no data has changed
+	if timeDiff == 0 || revDiff == 0 {
+		sd.lastSpeed = 0.0
+
+		return 0.0
+	}
+
+	rpm := float64(revDiff) / (float64(timeDiff) * sd.timeConversionFactor) * 60
+
+	// Reject implausible cadence spikes
+	if rpm > maxCrankRPM {
+		sd.rejected = true
+		sd.initializeCrankData()
+
+		return sd.lastSpeed
+	}
+
+	speed := math.Round(rpm*sd.cadenceSpeedPerRPM*100) / 100
+
+	// Update the last values for next calculation
+	sd.lastCrankRevs = sd.crankRevs
+	sd.lastCrankTime = sd.crankTime
+	sd.lastSpeed = speed
 
 	return speed
 }
 
-// initializeWheelData initializes the speed data
+// initializeWheelData initializes (or restarts) the wheel data used to calculate speed
 func (sd *speedData) initializeWheelData() float64 {
 
 	sd.lastWheelRevs = sd.wheelRevs
 	sd.lastWheelTime = sd.wheelTime
+	sd.wheelInitialized = true
+
+	return 0.0
+}
+
+// initializeCrankData initializes (or restarts) the crank data used to calculate speed
+func (sd *speedData) initializeCrankData() float64 {
+
+	sd.lastCrankRevs = sd.crankRevs
+	sd.lastCrankTime = sd.crankTime
+	sd.crankInitialized = true
 
 	return 0.0
 }
@@ -157,12 +421,33 @@ func (sd *speedData) parseSpeedData(speedData []byte) error {
 		return ErrNoSpeedData
 	}
 
+	// Cadence-only sensors report crank data without wheel data
+	if speedData[0]&wheelRevFlag == 0 && speedData[0]&crankRevFlag != 0 && len(speedData) >= minCrankDataLength {
+		return sd.parseCrankData(speedData)
+	}
+
 	if speedData[0]&wheelRevFlag == 0 || len(speedData) < minDataLength {
 		return ErrInvalidSpeedData
 	}
 
+	sd.crankOnly = false
 	sd.wheelRevs = binary.LittleEndian.Uint32(speedData[1:5])
 	sd.wheelTime = binary.LittleEndian.Uint16(speedData[5:7])
 
 	return nil
 }
+
+// parseCrankData parses raw crank-only BLE data, which is only usable if cadence-driven playback
+// is configured
+func (sd *speedData) parseCrankData(speedData []byte) error {
+
+	if sd.cadenceSpeedPerRPM == 0 {
+		return ErrCadenceOnly
+	}
+
+	sd.crankOnly = true
+	sd.crankRevs = binary.LittleEndian.Uint16(speedData[1:3])
+	sd.crankTime = binary.LittleEndian.Uint16(speedData[3:5])
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package ble
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 )
 
@@ -15,28 +17,44 @@ func init() {
 func TestCalculateSpeed(t *testing.T) {
 
 	wheelCircumferenceMM := 2100 // Example wheel circumference in mm
-	speedUnitMultiplier := 1.0   // km/h
 
-	sd := initSpeedData(wheelCircumferenceMM, speedUnitMultiplier)
-	sd.wheelRevs = 2
-	sd.wheelTime = 1024
-
-	// First call: Initialization phase
-	speed := sd.calculateSpeed()
-	if speed != 0.0 {
-		t.Errorf("Expected speed of 0.0 during initialization, got %v", speed)
+	// Define test cases (two wheel revolutions in one second)
+	tests := []struct {
+		speedUnits    string
+		expectedSpeed float64
+	}{
+		{config.SpeedUnitsKMH, 15.12},
+		{config.SpeedUnitsMPH, 9.4},
+		{config.SpeedUnitsMPS, 4.2},
+		{config.SpeedUnitsRPM, 120},
 	}
 
-	// Update the wheel data for the second call
-	sd.wheelRevs = 4
-	sd.wheelTime = 2048
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.speedUnits, func(t *testing.T) {
 
-	// Second call: Speed calculation
-	speed = sd.calculateSpeed()
-	expectedSpeed := 15.12 // Expected speed in km/h
+			sd := initSpeedData(config.SpeedConfig{WheelCircumferenceMM: wheelCircumferenceMM, SpeedUnits: tt.speedUnits})
+			sd.wheelRevs = 2
+			sd.wheelTime = 1024
 
-	if speed != expectedSpeed {
-		t.Errorf("Expected speed %v, got %v", expectedSpeed, speed)
+			// First call: Initialization phase
+			speed := sd.calculateSpeed()
+			if speed != 0.0 {
+				t.Errorf("Expected speed of 0.0 during initialization, got %v", speed)
+			}
+
+			// Update the wheel data for the second call
+			sd.wheelRevs = 4
+			sd.wheelTime = 2048
+
+			// Second call: Speed calculation
+			speed = sd.calculateSpeed()
+			if speed != tt.expectedSpeed {
+				t.Errorf("Expected speed %v, got %v", tt.expectedSpeed, speed)
+			}
+
+		})
 	}
 
 }
@@ -57,3 +75,185 @@ func TestParseSpeedData(t *testing.T) {
 	}
 
 }
+
+// TestParseCrankData tests parsing crank-only data from cadence-only sensors
+func TestParseCrankData(t *testing.T) {
+
+	data := []byte{0x02, 0x0a, 0x00, 0x00, 0x04} // Crank revolutions only
+
+	// Define test cases
+	tests := []struct {
+		name         string
+		cadenceSpeed float64
+		wantErr      error
+	}{
+		{"cadence-driven playback disabled", 0.0, ErrCadenceOnly},
+		{"cadence-driven playback enabled", 0.2, nil},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			sd := &speedData{cadenceSpeedPerRPM: tt.cadenceSpeed}
+
+			err := sd.parseSpeedData(data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseSpeedData() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && (!sd.crankOnly || sd.crankRevs != 10 || sd.crankTime != 1024) {
+				t.Errorf("Parsed data mismatch: expected crankRevs=10, crankTime=1024; got crankRevs=%v, crankTime=%v", sd.crankRevs, sd.crankTime)
+			}
+
+		})
+	}
+
+	// Short crank data is still invalid
+	sd := &speedData{cadenceSpeedPerRPM: 0.2}
+	if err := sd.parseSpeedData(data[:3]); !errors.Is(err, ErrInvalidSpeedData) {
+		t.Errorf("parseSpeedData() error = %v, want %v", err, ErrInvalidSpeedData)
+	}
+
+}
+
+// TestCalculateCadenceSpeed tests the virtual speed calculated from crank-only data
+func TestCalculateCadenceSpeed(t *testing.T) {
+
+	sd := initSpeedData(config.SpeedConfig{WheelCircumferenceMM: 2100, SpeedUnits: config.SpeedUnitsMPH, CadenceSpeedPerRPM: 0.2})
+
+	// First call: Initialization phase
+	if err := sd.parseSpeedData([]byte{0x02, 0x0a, 0x00, 0x00, 0x04}); err != nil {
+		t.Fatalf("Failed to parse crank data: %v", err)
+	}
+
+	if speed := sd.calculateSpeed(); speed != 0.0 {
+		t.Errorf("Expected speed of 0.0 during initialization, got %v", speed)
+	}
+
+	// Second call: three crank revolutions in two seconds (90 RPM)
+	if err := sd.parseSpeedData([]byte{0x02, 0x0d, 0x00, 0x00, 0x0c}); err != nil {
+		t.Fatalf("Failed to parse crank data: %v", err)
+	}
+
+	if speed := sd.calculateSpeed(); speed != 18.0 {
+		t.Errorf("Expected speed 18.0, got %v", speed)
+	}
+
+}
+
+// speedStep is a single wheel (or crank) counter update and its expected speed
+type speedStep struct {
+	revs         uint32
+	eventTime    uint16
+	wantSpeed    float64
+	wantRejected bool
+}
+
+// TestCalculateSpeedRollover tests speed calculations across counter rollovers, counters moving
+// backward and speed spikes
+func TestCalculateSpeedRollover(t *testing.T) {
+
+	// Define test cases (two wheel revolutions per second is 15.12 km/h)
+	tests := []struct {
+		name  string
+		steps []speedStep
+	}{
+		{"wheel time rollover", []speedStep{
+			{100, 64512, 0.0, false},
+			{102, 0, 15.12, false},
+			{104, 1024, 15.12, false},
+		}},
+		{"wheel revs rollover", []speedStep{
+			{0xFFFFFFFF, 1024, 0.0, false},
+			{1, 2048, 15.12, false},
+		}},
+		{"wheel revs backward", []speedStep{
+			{100, 1024, 0.0, false},
+			{102, 2048, 15.12, false},
+			{101, 2560, 15.12, true},
+			{103, 3584, 15.12, false},
+		}},
+		{"speed spike", []speedStep{
+			{100, 1024, 0.0, false},
+			{102, 2048, 15.12, false},
+			{150, 2150, 15.12, true},
+			{152, 3174, 15.12, false},
+		}},
+		{"no wheel movement", []speedStep{
+			{100, 1024, 0.0, false},
+			{102, 2048, 15.12, false},
+			{102, 2048, 0.0, false},
+			{101, 3072, 0.0, true},
+		}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			sd := initSpeedData(config.SpeedConfig{WheelCircumferenceMM: 2100, SpeedUnits: config.SpeedUnitsKMH})
+
+			for i, step := range tt.steps {
+				sd.wheelRevs = step.revs
+				sd.wheelTime = step.eventTime
+
+				if speed := sd.calculateSpeed(); speed != step.wantSpeed || sd.rejected != step.wantRejected {
+					t.Errorf("step %d: got speed %v (rejected %v), want %v (rejected %v)", i, speed, sd.rejected, step.wantSpeed, step.wantRejected)
+				}
+			}
+
+		})
+	}
+
+}
+
+// TestCalculateCadenceSpeedRollover tests cadence-driven speed calculations across counter
+// rollovers and cadence spikes
+func TestCalculateCadenceSpeedRollover(t *testing.T) {
+
+	// Define test cases (one crank revolution per second is 60 RPM, or 12.0 at 0.2 per RPM)
+	tests := []struct {
+		name  string
+		steps []speedStep
+	}{
+		{"crank rollover", []speedStep{
+			{0xFFFF, 64512, 0.0, false},
+			{0, 0, 12.0, false},
+			{1, 1024, 12.0, false},
+		}},
+		{"crank revs backward", []speedStep{
+			{10, 1024, 0.0, false},
+			{11, 2048, 12.0, false},
+			{9, 3072, 12.0, true},
+		}},
+		{"cadence spike", []speedStep{
+			{10, 1024, 0.0, false},
+			{11, 2048, 12.0, false},
+			{21, 2148, 12.0, true},
+		}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			sd := initSpeedData(config.SpeedConfig{WheelCircumferenceMM: 2100, SpeedUnits: config.SpeedUnitsKMH, CadenceSpeedPerRPM: 0.2})
+			sd.crankOnly = true
+
+			for i, step := range tt.steps {
+				sd.crankRevs = uint16(step.revs)
+				sd.crankTime = step.eventTime
+
+				if speed := sd.calculateSpeed(); speed != step.wantSpeed || sd.rejected != step.wantRejected {
+					t.Errorf("step %d: got speed %v (rejected %v), want %v (rejected %v)", i, speed, sd.rejected, step.wantSpeed, step.wantRejected)
+				}
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,151 @@
+package ble
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// Error definitions
+var (
+	ErrTrafficLogEntry  = errors.New("invalid BLE traffic log entry")
+	errTrafficLogFormat = errors.New("expected '<timestamp> <hex>'")
+)
+
+// trafficLogger writes every raw CSC notification payload (as hex) to a BLE traffic log file
+type trafficLogger struct {
+	file   *os.File
+	writer *bufio.Writer
+	mu     sync.Mutex
+}
+
+// newTrafficLogger creates (or truncates) the BLE traffic log file
+func newTrafficLogger(path string) (*trafficLogger, error) {
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to create BLE traffic log file", err)
+	}
+
+	return &trafficLogger{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// write logs a single raw CSC notification payload, one "<timestamp> <hex>" entry per line
+func (tl *trafficLogger) write(timestamp time.Time, payload []byte) error {
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	// Payloads that arrive after the log is closed are dropped
+	if tl.file == nil {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(tl.writer, "%s %s\n", timestamp.Format(time.RFC3339Nano), hex.EncodeToString(payload))
+
+	return err
+}
+
+// close flushes any buffered entries and closes the BLE traffic log file
+func (tl *trafficLogger) close() error {
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if tl.file == nil {
+		return nil
+	}
+
+	err := tl.writer.Flush()
+
+	if closeErr := tl.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	tl.file = nil
+
+	return err
+}
+
+// DecodeTrafficLog re-parses the raw CSC notification payloads of a BLE traffic log through the
+// CSC speed parser, writing the decoded wheel data and speed (or parser error) of each entry
+func DecodeTrafficLog(r io.Reader, w io.Writer, speedConfig config.SpeedConfig) error {
+
+	sd := initSpeedData(speedConfig)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		timestamp, payload, err := parseTrafficLogEntry(line)
+		if err != nil {
+			return fmt.Errorf("%w (line %d): %w", ErrTrafficLogEntry, lineNum, err)
+		}
+
+		fmt.Fprintf(w, "%s  %-16s  ", timestamp.Format("15:04:05.000"), hex.EncodeToString(payload))
+
+		if err := sd.parseSpeedData(payload); err != nil {
+			fmt.Fprintf(w, "error: %v\n", err)
+
+			continue
+		}
+
+		speed := sd.calculateSpeed()
+
+		if sd.crankOnly {
+			fmt.Fprintf(w, "crank_revs=%d crank_time=%d speed=%.2f %s", sd.crankRevs, sd.crankTime, speed, speedConfig.SpeedUnits)
+		} else {
+			fmt.Fprintf(w, "wheel_revs=%d wheel_time=%d speed=%.2f %s", sd.wheelRevs, sd.wheelTime, speed, speedConfig.SpeedUnits)
+		}
+
+		if sd.rejected {
+			fmt.Fprint(w, " (rejected)")
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf(errFormat, "failed to read BLE traffic log", err)
+	}
+
+	return nil
+}
+
+// parseTrafficLogEntry parses the timestamp and raw payload of a single BLE traffic log entry
+func parseTrafficLogEntry(line string) (time.Time, []byte, error) {
+
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return time.Time{}, nil, fmt.Errorf("%w, got %q", errTrafficLogFormat, line)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf(errFormat, "invalid timestamp", err)
+	}
+
+	payload, err := hex.DecodeString(fields[1])
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf(errFormat, "invalid payload", err)
+	}
+
+	return timestamp, payload, nil
+}
@@ -0,0 +1,100 @@
+package ble
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrafficLogger tests logging raw CSC notification payloads to a BLE traffic log file
+func TestTrafficLogger(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "traffic.log")
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	traffic, err := newTrafficLogger(path)
+	require.NoError(t, err)
+
+	require.NoError(t, traffic.write(timestamp, []byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x04}))
+	require.NoError(t, traffic.close())
+
+	// Payloads written after the log is closed are dropped
+	require.NoError(t, traffic.write(timestamp, []byte{0x01}))
+	require.NoError(t, traffic.close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-01-02T03:04:05Z 01020000000004\n", string(data))
+
+}
+
+// TestDecodeTrafficLog tests re-parsing a BLE traffic log through the CSC speed parser
+func TestDecodeTrafficLog(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name    string
+		log     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "valid payloads",
+			log: "# comment\n" +
+				"2025-01-02T03:04:05Z 01020000000004\n" +
+				"\n" +
+				"2025-01-02T03:04:06Z 01040000000008\n",
+			want: []string{
+				"03:04:05.000  01020000000004    wheel_revs=2 wheel_time=1024 speed=0.00 mph",
+				"03:04:06.000  01040000000008    wheel_revs=4 wheel_time=2048 speed=9.40 mph",
+			},
+		},
+		{
+			name: "nonstandard payloads",
+			log: "2025-01-02T03:04:05Z 020100\n" +
+				"2025-01-02T03:04:06Z 0102\n",
+			want: []string{
+				"03:04:05.000  020100            error: " + ErrInvalidSpeedData.Error(),
+				"03:04:06.000  0102              error: " + ErrInvalidSpeedData.Error(),
+			},
+		},
+		{
+			name:    "invalid hex",
+			log:     "2025-01-02T03:04:05Z 01zz\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing payload",
+			log:     "2025-01-02T03:04:05Z\n",
+			wantErr: true,
+		},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			var out strings.Builder
+
+			err := DecodeTrafficLog(strings.NewReader(tt.log), &out, config.SpeedConfig{WheelCircumferenceMM: 2100, SpeedUnits: config.SpeedUnitsMPH})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrTrafficLogEntry)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, strings.Join(tt.want, "\n")+"\n", out.String())
+
+		})
+	}
+
+}
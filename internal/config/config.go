@@ -3,26 +3,40 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
 	"github.com/richbl/go-ble-sync-cycle/internal/flags"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
 )
 
 // Config represents the complete application configuration structure from the TOML config file
 type Config struct {
-	App   AppConfig   `toml:"app"`
-	BLE   BLEConfig   `toml:"ble"`
-	Speed SpeedConfig `toml:"speed"`
-	Video VideoConfig `toml:"video"`
+	App       AppConfig      `toml:"app"`
+	BLE       BLEConfig      `toml:"ble"`
+	Speed     SpeedConfig    `toml:"speed"`
+	Video     VideoConfig    `toml:"video"`
+	Goal      GoalConfig     `toml:"goal"`
+	Intervals IntervalConfig `toml:"intervals"`
+	Laps      LapConfig      `toml:"laps"`
+	Race      RaceConfig     `toml:"race"`
+	Hooks     HooksConfig    `toml:"hooks"`
 }
 
 // AppConfig defines application-wide settings
 type AppConfig struct {
-	SessionTitle string `toml:"session_title"`
-	LogLevel     string `toml:"logging_level"`
+	SessionTitle        string `toml:"session_title"`
+	LogLevel            string `toml:"logging_level"`
+	BackupCount         int    `toml:"backup_count"`
+	MaxSessionMinutes   int    `toml:"max_session_minutes"`
+	ShutdownTimeoutSecs int    `toml:"shutdown_timeout_secs"`
 }
 
 // ValidationType, used for config validation, is a type that can be either an int or a float64
@@ -32,6 +46,7 @@ type ValidationType interface {
 
 // validationRange is a struct used for validating config field ranges
 type validationRange struct {
+	key    string // The TOML key of the setting, qualified by its section (e.g., "video.OSD.margin_x")
 	value  any
 	min    any
 	max    any
@@ -46,37 +61,99 @@ const (
 	logLevelError = "error"
 	logLevelFatal = "fatal"
 
-	SpeedUnitsKMH = "km/h"
-	SpeedUnitsMPH = "mph"
+	SpeedUnitsKMH = units.KMH
+	SpeedUnitsMPH = units.MPH
+	SpeedUnitsMPS = units.MPS
+	SpeedUnitsRPM = units.RPM // Wheel revolutions per minute
 
 	MediaPlayerMPV = "mpv"
 
+	HWDecAuto  = "auto"
+	HWDecVAAPI = "vaapi"
+	HWDecNVDEC = "nvdec"
+	HWDecOff   = "off"
+
+	FocusModeOff  = "off"
+	FocusModeHide = "hide" // Hide the GUI (as when running in the background)
+
 	errTypeFormat = "%w: %T"
 	errFormat     = "%v: %w"
 	errFormatRev  = "%w: %v"
 )
 
+// Valid logging levels
+var validLogLevels = map[string]bool{
+	logLevelDebug: true,
+	logLevelInfo:  true,
+	logLevelWarn:  true,
+	logLevelError: true,
+	logLevelFatal: true,
+}
+
 // Error messages
 var (
 	errInvalidLogLevel     = errors.New("invalid log level")
 	errInvalidSessionTitle = errors.New("invalid session title")
 	errInvalidConfigFile   = errors.New("invalid config file")
+	errBackupCount         = errors.New("backup_count must be 0-10")
+	errMaxSessionMinutes   = errors.New("max_session_minutes must be 0-1440")
+	errShutdownTimeout     = errors.New("shutdown_timeout_secs must be 1-300")
 	errInvalidSpeedUnits   = errors.New("invalid speed units")
 	errVideoFile           = errors.New("video file error")
+	errSubtitleFile        = errors.New("subtitle file error")
 	errInvalidPlayer       = errors.New("invalid media player")
+	errInvalidPlayerArg    = errors.New("invalid media_player_args entry")
+	errInvalidHWDec        = errors.New("invalid hardware_decoding value")
+	errInvalidFocusMode    = errors.New("invalid focus_mode value")
 	errInvalidInterval     = errors.New("update_interval_secs must be 0.1-3.0")
 	errInvalidSeek         = errors.New("seek_to_position must be in HH:MM:SS format")
 	errSmoothingWindow     = errors.New("smoothing window must be 1-25")
 	errWheelCircumference  = errors.New("wheel_circumference_mm must be 50-3000")
 	errSpeedThreshold      = errors.New("speed_threshold must be 0.00-10.00")
 	errSpeedMultiplier     = errors.New("speed_multiplier must be 0.1-1.5")
+	errMinPlaybackSpeed    = errors.New("min_playback_speed must be 0.00-1.00")
+	errMaxPlaybackSpeed    = errors.New("max_playback_speed must be 0.00-10.00")
+	errPlaybackSpeedRange  = errors.New("min_playback_speed must not exceed max_playback_speed")
+	errInterpolationSpeed  = errors.New("frame_interpolation_speed must be 0.00-1.00")
+	errStartCountdown      = errors.New("start_countdown_secs must be 0-30")
+	errResumeFade          = errors.New("resume_fade_secs must be 0.0-5.0")
+	errResumeDistance      = errors.New("resume_distance_km must be 0.0-100000.0")
 	errInvalidBDAddr       = errors.New("invalid sensor BD_ADDR in configuration")
+	errInvalidEnvBDAddr    = errors.New("invalid environmental sensor BD_ADDR in configuration")
+	errBDAddrFormat        = errors.New("must be 12 hexadecimal digits, e.g., FA:46:1D:77:C8:E1")
+	errBDAddrReserved      = errors.New("null and broadcast addresses are not BLE peripheral addresses")
+	errNoBDAddrInQR        = errors.New("no BD_ADDR found in QR code")
 	errInvalidScanTimeout  = errors.New("scan_timeout_secs must be 1-100")
+	errInvalidScanAttempts = errors.New("scan_attempts must be 1-10")
 	errFontSize            = errors.New("font_size must be 10-200")
 	errOSDMargin           = errors.New("osd margin value out of range")
+	errOSDInterval         = errors.New("osd_update_interval_secs must be 0.0-10.0")
+	errNightModeHours      = errors.New("night_mode_hours must be in \"START-END\" format (hours 0-23)")
+	errNightBrightness     = errors.New("night_brightness must be 10-100")
 	errInvalidAlignX       = errors.New("invalid align_x value")
 	errInvalidAlignY       = errors.New("invalid align_y value")
 	errWindowScale         = errors.New("window_scale_factor must be 0.1-1.0")
+	errWindowPosition      = errors.New("window_position must be in \"X,Y\" format")
+	errVolume              = errors.New("volume must be 0-100")
+	errAudioTrack          = errors.New("audio_track must be 0-99")
+	errCadenceSpeed        = errors.New("cadence_speed_per_rpm must be 0.00-10.00")
+	errCoastDecay          = errors.New("coast_decay_secs must be 0.0-60.0")
+	errSpeedZones          = errors.New("speed_zones must be empty, or 3 ascending speeds (0.0-1000.0)")
+	errInvalidGoalType     = errors.New("invalid goal type")
+	errGoalTarget          = errors.New("goal target must be 0.0-10000.0")
+	errRiderWeight         = errors.New("rider_weight_kg must be 30-250")
+	errIntervalSecs        = errors.New("interval length must be 0-3600 seconds")
+	errIntervalPair        = errors.New("hard_secs and easy_secs must both be set (or both be 0)")
+	errLapDistance         = errors.New("lap distance must be 0.0-1000.0")
+	errLapMinutes          = errors.New("lap minutes must be 0-600")
+	errLapPair             = errors.New("laps must be marked by distance or by minutes (not both)")
+	errRaceTargetSpeed     = errors.New("race target speed must be 0.0-1000.0")
+	errRaceEasyTarget      = errors.New("easy_target_speed requires a target_speed")
+	errOutdoorGPXFile      = errors.New("outdoor GPX file error")
+	errHookTimeout         = errors.New("hook timeout_secs must be 1-300")
+	errSessionNotRecorded  = errors.New("session not found in session history")
+	errReplayFile          = errors.New("replay file error")
+	errReplayRate          = errors.New("replay rate must be 0.1-100.0")
 	errUnsupportedType     = errors.New("unsupported type")
 )
 
@@ -89,22 +166,61 @@ func Load(configFile string) (*Config, error) {
 		configFile = clFlags.Config
 	}
 
-	cfg, err := readConfigFile(configFile, &Config{})
+	cfg, err := readConfigFile(configFile, defaultConfig())
 	if err != nil {
-		return nil, err
+		return nil, configError(err)
 	}
 
 	if err := cfg.Validate(); err != nil {
-		return nil, err
+		return nil, configError(err)
 	}
 
 	if err := setSeekToPosition(cfg, clFlags); err != nil {
-		return nil, err
+		return nil, configError(err)
 	}
 
+	setLowPower(cfg, clFlags)
+	setMeasureLatency(cfg, clFlags)
+
+	if err := setReplay(cfg, clFlags); err != nil {
+		return nil, configError(err)
+	}
+
+	setSampleExport(cfg, clFlags)
+	setTrafficLog(cfg, clFlags)
+
 	return cfg, nil
 }
 
+// configError gives an error loading the configuration its user-facing error code
+func configError(err error) error {
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return apperr.Wrap(apperr.CodeConfigNotFound, err)
+	}
+
+	return apperr.Wrap(apperr.CodeConfigInvalid, err)
+}
+
+// defaultConfig returns a Config pre-populated with defaults for settings that may be absent
+// from session files created by earlier versions of the application. These are the NewDefault
+// settings, other than the placeholders (which must be set by the session file), and the features
+// that new sessions turn on but that stay off for sessions that predate them (hardware decoding,
+// the playback speed limits, and the speed zones, whose defaults are in mph)
+func defaultConfig() *Config {
+
+	cfg := NewDefault()
+
+	cfg.BLE.SensorBDAddr = ""
+	cfg.Video.FilePath = ""
+	cfg.Video.HardwareDecoding = HWDecOff
+	cfg.Video.MinPlaybackSpeed = 0.0
+	cfg.Video.MaxPlaybackSpeed = 0.0
+	cfg.Speed.SpeedZones = nil
+
+	return cfg
+}
+
 // readConfigFile reads the configuration file
 func readConfigFile(path string, cfg *Config) (*Config, error) {
 
@@ -116,6 +232,74 @@ func readConfigFile(path string, cfg *Config) (*Config, error) {
 	return cfg, nil
 }
 
+// setLowPower enables the low-power profile if requested on the command-line
+func setLowPower(cfg *Config, clFlags flags.CLIFlags) {
+
+	if clFlags.LowPower {
+		cfg.Video.LowPower = true
+	}
+
+}
+
+// setMeasureLatency enables the measurement of speed update latency if requested on the
+// command-line
+func setMeasureLatency(cfg *Config, clFlags flags.CLIFlags) {
+
+	if clFlags.Latency {
+		cfg.Video.MeasureLatency = true
+	}
+
+}
+
+// setSampleExport sets the CSV file used to export raw BLE sensor samples if requested on the
+// command-line
+func setSampleExport(cfg *Config, clFlags flags.CLIFlags) {
+
+	if clFlags.Export != "" {
+		cfg.BLE.SampleExportFile = clFlags.Export
+	}
+
+}
+
+// setTrafficLog sets the file used to log raw BLE sensor notification payloads if requested on the
+// command-line
+func setTrafficLog(cfg *Config, clFlags flags.CLIFlags) {
+
+	if clFlags.TrafficLog != "" {
+		cfg.BLE.TrafficLogFile = clFlags.TrafficLog
+	}
+
+}
+
+// setReplay validates and then sets the recorded session file (and replay rate) used in place of
+// the BLE sensor, based on the command-line flags
+func setReplay(cfg *Config, clFlags flags.CLIFlags) error {
+
+	if clFlags.Replay == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(clFlags.Replay); err != nil {
+		return fmt.Errorf(errFormat, errReplayFile, err)
+	}
+
+	rate := 1.0
+
+	if clFlags.ReplayRate != "" {
+		var err error
+
+		rate, err = strconv.ParseFloat(clFlags.ReplayRate, 64)
+		if err != nil || rate < 0.1 || rate > 100.0 {
+			return fmt.Errorf(errFormatRev, errReplayRate, clFlags.ReplayRate)
+		}
+	}
+
+	cfg.Speed.ReplayFile = clFlags.Replay
+	cfg.Speed.ReplayRate = rate
+
+	return nil
+}
+
 // setSeekToPosition validates and then sets the seek position based on the command-line flag
 func setSeekToPosition(cfg *Config, clFlags flags.CLIFlags) error {
 
@@ -140,6 +324,11 @@ func (c *Config) Validate() error {
 		{c.Speed.validate, "speed"},
 		{c.BLE.validate, "BLE"},
 		{c.Video.validate, "video"},
+		{c.Goal.validate, "goal"},
+		{c.Intervals.validate, "intervals"},
+		{c.Laps.validate, "laps"},
+		{c.Race.validate, "race"},
+		{c.Hooks.validate, "hooks"},
 	}
 
 	for _, v := range validators {
@@ -154,14 +343,6 @@ func (c *Config) Validate() error {
 // validate checks AppConfig for valid settings
 func (ac *AppConfig) validate() error {
 
-	validLogLevels := map[string]bool{
-		logLevelDebug: true,
-		logLevelInfo:  true,
-		logLevelWarn:  true,
-		logLevelError: true,
-		logLevelFatal: true,
-	}
-
 	if !validLogLevels[ac.LogLevel] {
 		return fmt.Errorf(errFormatRev, errInvalidLogLevel, ac.LogLevel)
 	}
@@ -175,7 +356,23 @@ func (ac *AppConfig) validate() error {
 		return fmt.Errorf(errFormatRev, errInvalidSessionTitle, "session title contains illegal characters (<, &, or \")")
 	}
 
-	return nil
+	return validateConfigFields(ac.configValidationRanges())
+}
+
+// configValidationRanges returns validation ranges for AppConfig
+func (ac *AppConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"app.backup_count", ac.BackupCount, 0, maxBackupCount, errBackupCount},
+		{"app.max_session_minutes", ac.MaxSessionMinutes, 0, 1440, errMaxSessionMinutes}, // Up to a day
+		{"app.shutdown_timeout_secs", ac.ShutdownTimeoutSecs, 1, 300, errShutdownTimeout},
+	}
+
+}
+
+// ShutdownTimeout returns the time allowed for the services of a session to stop
+func (ac AppConfig) ShutdownTimeout() time.Duration {
+	return time.Duration(ac.ShutdownTimeoutSecs) * time.Second
 }
 
 // validateConfigFields validates multiple fields against their min/max values
@@ -8,26 +8,122 @@ import (
 
 // BLEConfig defines Bluetooth Low Energy settings from the TOML config file
 type BLEConfig struct {
-	SensorBDAddr    string `toml:"sensor_bd_addr"`
-	ScanTimeoutSecs int    `toml:"scan_timeout_secs"`
+	SensorBDAddr     string `toml:"sensor_bd_addr"`
+	ScanTimeoutSecs  int    `toml:"scan_timeout_secs"`
+	ScanAttempts     int    `toml:"scan_attempts"`
+	EnvSensorBDAddr  string `toml:"env_sensor_bd_addr"` // Optional environmental (temperature/humidity) sensor
+	SampleExportFile string `toml:"-"`                  // CSV file for raw sensor samples (set from the command-line)
+	TrafficLogFile   string `toml:"-"`                  // Log file for raw sensor notification payloads (set from the command-line)
 }
 
+// Separators accepted between the octets of a BD_ADDR (e.g., "FA:46:1D:77:C8:E1",
+// "fa-46-1d-77-c8-e1", or "fa46.1d77.c8e1"), which may also be left out (e.g., "FA461D77C8E1")
+var bdAddrSeparators = strings.NewReplacer(":", "", "-", "", ".", "", " ", "")
+
+// BD_ADDR (without separators) of 12 hexadecimal digits
+var bdAddrDigits = regexp.MustCompile(`^[0-9A-F]{12}$`)
+
+// BD_ADDR within other text, in any of the formats accepted by NormalizeBDAddr
+var bdAddrInText = regexp.MustCompile(`(?i)\b(?:[0-9a-f]{2}(?:[:-][0-9a-f]{2}){5}|[0-9a-f]{4}(?:\.[0-9a-f]{4}){2}|[0-9a-f]{12})\b`)
+
 // validate checks BLEConfig for valid settings
 func (bc *BLEConfig) validate() error {
 
-	// Validate scan timeout
-	if err := validateField(bc.ScanTimeoutSecs, 1, 100, errInvalidScanTimeout); err != nil {
+	// Validate scan timeout and attempts
+	if err := validateConfigFields(bc.configValidationRanges()); err != nil {
 		return err
 	}
 
-	// Generate BD_ADDR format
-	pattern := `^([0-9A-Fa-f]{2}(:[0-9A-Fa-f]{2}){5})$`
-	re := regexp.MustCompile(pattern)
+	// Validate the BD_ADDR, keeping it in its canonical form
+	addr, err := NormalizeBDAddr(bc.SensorBDAddr)
+	if err != nil {
+		return fmt.Errorf(errFormatRev, errInvalidBDAddr, fmt.Sprintf("%s (%v)", bc.SensorBDAddr, err))
+	}
+
+	bc.SensorBDAddr = addr
 
-	// Validate BD_ADDR format
-	if !re.MatchString(strings.TrimSpace(bc.SensorBDAddr)) {
-		return fmt.Errorf(errFormatRev, errInvalidBDAddr, bc.SensorBDAddr)
+	// Validate the (optional) environmental sensor BD_ADDR
+	if bc.EnvSensorBDAddr != "" {
+
+		addr, err := NormalizeBDAddr(bc.EnvSensorBDAddr)
+		if err != nil {
+			return fmt.Errorf(errFormatRev, errInvalidEnvBDAddr, fmt.Sprintf("%s (%v)", bc.EnvSensorBDAddr, err))
+		}
+
+		bc.EnvSensorBDAddr = addr
 	}
 
 	return nil
 }
+
+// configValidationRanges returns validation ranges for BLEConfig
+func (bc *BLEConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"ble.scan_timeout_secs", bc.ScanTimeoutSecs, 1, 100, errInvalidScanTimeout},
+		{"ble.scan_attempts", bc.ScanAttempts, 1, 10, errInvalidScanAttempts},
+	}
+
+}
+
+// NormalizeBDAddr returns a BD_ADDR given in any of the accepted formats (upper or lower case, with
+// colons, dashes, dots, or no separators) in its canonical form of upper case octets separated by
+// colons (e.g., "FA:46:1D:77:C8:E1"), or an error if it isn't a valid BLE peripheral address
+func NormalizeBDAddr(addr string) (string, error) {
+
+	digits := strings.ToUpper(bdAddrSeparators.Replace(strings.TrimSpace(addr)))
+	if !bdAddrDigits.MatchString(digits) {
+		return "", errBDAddrFormat
+	}
+
+	// Neither the null nor the broadcast address is ever advertised by a BLE peripheral
+	if digits == strings.Repeat("0", 12) || digits == strings.Repeat("F", 12) {
+		return "", errBDAddrReserved
+	}
+
+	octets := make([]string, 0, 6)
+	for i := 0; i < len(digits); i += 2 {
+		octets = append(octets, digits[i:i+2])
+	}
+
+	return strings.Join(octets, ":"), nil
+}
+
+// CanonicalBDAddr returns a BD_ADDR in its canonical form, or unchanged if it isn't valid (e.g., an
+// empty BD_ADDR for no environmental sensor)
+func CanonicalBDAddr(addr string) string {
+
+	if canonical, err := NormalizeBDAddr(addr); err == nil {
+		return canonical
+	}
+
+	return addr
+}
+
+// FindBDAddr returns the first valid BD_ADDR found within text (e.g., a line of "bluetoothctl
+// devices" output pasted from the clipboard), in its canonical form
+func FindBDAddr(text string) (string, bool) {
+
+	for _, match := range bdAddrInText.FindAllString(text, -1) {
+
+		if addr, err := NormalizeBDAddr(match); err == nil {
+			return addr, true
+		}
+	}
+
+	return "", false
+}
+
+// ReplaceBDAddrs replaces each valid BD_ADDR found within text with the result of replace, which
+// is given the BD_ADDR in its canonical form
+func ReplaceBDAddrs(text string, replace func(addr string) string) string {
+
+	return bdAddrInText.ReplaceAllStringFunc(text, func(match string) string {
+
+		if addr, err := NormalizeBDAddr(match); err == nil {
+			return replace(addr)
+		}
+
+		return match
+	})
+}
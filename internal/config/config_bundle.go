@@ -0,0 +1,291 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Settings bundle settings
+const (
+	BundleManifestFile = "bsc-bundle.toml"
+	maxBundleEntrySize = 16 << 20 // Largest file accepted from a settings bundle (16 MiB)
+)
+
+// Error definitions
+var (
+	ErrNotBundle         = errors.New("not a BSC settings bundle")
+	errBundleEntry       = errors.New("invalid settings bundle entry")
+	errBundleEntryTooBig = errors.New("settings bundle entry too large")
+)
+
+// bundleManifest identifies a settings bundle, and the application version that exported it
+type bundleManifest struct {
+	Version  string    `toml:"version"`
+	Exported time.Time `toml:"exported"`
+}
+
+// BundleImport lists the files extracted from a settings bundle, and the files skipped because
+// they already exist
+type BundleImport struct {
+	Imported []string
+	Skipped  []string
+}
+
+// ConfigDir returns the application config directory (the session directory), which holds the
+// session files, the sensor registry and any other application data
+func ConfigDir() (string, error) {
+
+	configHome, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf(errFormat, "failed to get user config dir", err)
+	}
+
+	return filepath.Join(configHome, ApplicationID), nil
+}
+
+// LogDir returns the directory of saved log files (e.g., the Session Log saved when an error
+// occurs), kept in the user cache directory so log files aren't exported in settings bundles
+func LogDir() (string, error) {
+
+	cacheHome, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf(errFormat, "failed to get user cache dir", err)
+	}
+
+	return filepath.Join(cacheHome, ApplicationID, "logs"), nil
+}
+
+// ExportBundle writes every file of the config directory (sessions, sensor registry and any other
+// application data) into a single settings bundle (zip archive), returning the number of files
+// exported
+func ExportBundle(configDir, bundlePath string) (int, error) {
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return 0, fmt.Errorf(errFormat, "failed to create settings bundle", err)
+	}
+
+	archive := zip.NewWriter(file)
+
+	count, err := writeBundle(archive, configDir, bundlePath)
+
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(bundlePath)
+
+		return 0, fmt.Errorf(errFormat, "failed to export settings bundle", err)
+	}
+
+	return count, nil
+}
+
+// writeBundle writes the manifest and the files of the config directory into the archive
+func writeBundle(archive *zip.Writer, configDir, bundlePath string) (int, error) {
+
+	manifest, err := archive.Create(BundleManifestFile)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := toml.NewEncoder(manifest).Encode(bundleManifest{Version: GetVersion(), Exported: time.Now()}); err != nil {
+		return 0, err
+	}
+
+	// A bundle exported into the config directory must not include itself
+	bundleAbs, err := filepath.Abs(bundlePath)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	err = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if abs, err := filepath.Abs(path); err == nil && abs == bundleAbs {
+			return nil
+		}
+
+		rel, err := filepath.Rel(configDir, path)
+		if err != nil {
+			return err
+		}
+
+		if err := addBundleFile(archive, path, filepath.ToSlash(rel)); err != nil {
+			return err
+		}
+
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
+
+// addBundleFile copies a single file into the archive
+func addBundleFile(archive *zip.Writer, path, name string) error {
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+
+	return err
+}
+
+// ImportBundle extracts a settings bundle into the config directory: existing files are skipped
+// unless overwrite is set, although the sensors of a bundled sensor registry are always merged
+// into the existing sensor registry
+func ImportBundle(bundlePath, configDir string, overwrite bool) (*BundleImport, error) {
+
+	archive, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to open settings bundle", err)
+	}
+	defer archive.Close()
+
+	manifest, err := archive.Open(BundleManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf(errFormatRev, ErrNotBundle, bundlePath)
+	}
+
+	_ = manifest.Close()
+
+	result := &BundleImport{}
+
+	for _, entry := range archive.File {
+
+		if entry.Name == BundleManifestFile || entry.FileInfo().IsDir() {
+			continue
+		}
+
+		imported, err := importBundleEntry(entry, configDir, overwrite)
+		if err != nil {
+			return result, err
+		}
+
+		if imported {
+			result.Imported = append(result.Imported, entry.Name)
+		} else {
+			result.Skipped = append(result.Skipped, entry.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// importBundleEntry extracts a single settings bundle entry into the config directory, returning
+// false if the entry was skipped
+func importBundleEntry(entry *zip.File, configDir string, overwrite bool) (bool, error) {
+
+	// Entries must stay within the config directory
+	name := filepath.FromSlash(entry.Name)
+	if !filepath.IsLocal(name) {
+		return false, fmt.Errorf(errFormatRev, errBundleEntry, entry.Name)
+	}
+
+	data, err := readBundleEntry(entry)
+	if err != nil {
+		return false, err
+	}
+
+	target := filepath.Join(configDir, name)
+
+	if _, err := os.Stat(target); err == nil && !overwrite {
+
+		// Merge the sensors of a bundled sensor registry into the existing sensor registry
+		if name == filepath.Join(sensorRegistryDir, SensorRegistryFile) {
+			return true, mergeSensorRegistry(target, data)
+		}
+
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return false, fmt.Errorf(errFormat, "failed to create directory", err)
+	}
+
+	if err := os.WriteFile(target, data, 0644); err != nil { //nolint:gosec // Session files are not secret
+		return false, fmt.Errorf(errFormat, "failed to write "+entry.Name, err)
+	}
+
+	return true, nil
+}
+
+// readBundleEntry reads the content of a single settings bundle entry
+func readBundleEntry(entry *zip.File) ([]byte, error) {
+
+	src, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to read "+entry.Name, err)
+	}
+	defer src.Close()
+
+	var data bytes.Buffer
+
+	n, err := io.Copy(&data, io.LimitReader(src, maxBundleEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to read "+entry.Name, err)
+	}
+
+	if n > maxBundleEntrySize {
+		return nil, fmt.Errorf(errFormatRev, errBundleEntryTooBig, entry.Name)
+	}
+
+	return data.Bytes(), nil
+}
+
+// mergeSensorRegistry merges the sensors of a bundled sensor registry into the sensor registry at
+// path, keeping the nicknames of sensors already known
+func mergeSensorRegistry(path string, data []byte) error {
+
+	bundled := &SensorRegistry{}
+	if _, err := toml.Decode(string(data), bundled); err != nil {
+		return fmt.Errorf(errFormat, "failed to read bundled sensor registry", err)
+	}
+
+	return UpdateSensorRegistry(path, func(r *SensorRegistry) {
+
+		for _, profile := range bundled.Sensors {
+
+			if known, ok := r.Lookup(profile.BDAddr); ok && known.Name != "" {
+				profile.Name = ""
+			}
+
+			r.Record(profile)
+		}
+
+	})
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestBundleRoundTrip tests exporting a config directory into a settings bundle and importing it
+// into another config directory
+func TestBundleRoundTrip(t *testing.T) {
+
+	srcDir := t.TempDir()
+	registryPath := filepath.Join(srcDir, sensorRegistryDir, SensorRegistryFile)
+
+	writeTestFile(t, filepath.Join(srcDir, "morning_ride.toml"), "session")
+
+	registry := &SensorRegistry{}
+	registry.Rename("F1:42:D8:DE:35:16", "Garmin Speed 2 (rear wheel)")
+	registry.Rename("AA:BB:CC:DD:EE:01", "Spare sensor")
+
+	if err := registry.Save(registryPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	// A bundle exported into the config directory doesn't include itself
+	bundlePath := filepath.Join(srcDir, "bsc-settings.zip")
+
+	count, err := ExportBundle(srcDir, bundlePath)
+	if err != nil {
+		t.Fatalf("ExportBundle() returned error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("ExportBundle() exported %d files, want 2", count)
+	}
+
+	// Import into a config directory that already holds a renamed sensor
+	dstDir := t.TempDir()
+	dstRegistryPath := filepath.Join(dstDir, sensorRegistryDir, SensorRegistryFile)
+
+	existing := &SensorRegistry{}
+	existing.Rename("F1:42:D8:DE:35:16", "Trainer bike")
+
+	if err := existing.Save(dstRegistryPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	result, err := ImportBundle(bundlePath, dstDir, false)
+	if err != nil {
+		t.Fatalf("ImportBundle() returned error: %v", err)
+	}
+
+	if !slices.Contains(result.Imported, "morning_ride.toml") || len(result.Skipped) != 0 {
+		t.Errorf("ImportBundle() = %+v, want morning_ride.toml imported and nothing skipped", result)
+	}
+
+	merged, err := LoadSensorRegistry(dstRegistryPath)
+	if err != nil {
+		t.Fatalf("LoadSensorRegistry() returned error: %v", err)
+	}
+
+	if got := merged.DisplayName("F1:42:D8:DE:35:16"); got != "Trainer bike" {
+		t.Errorf("DisplayName() = %q, want the existing nickname %q", got, "Trainer bike")
+	}
+
+	if got := merged.DisplayName("AA:BB:CC:DD:EE:01"); got != "Spare sensor" {
+		t.Errorf("DisplayName() = %q, want the bundled nickname %q", got, "Spare sensor")
+	}
+
+	// Existing session files are only replaced when overwriting
+	writeTestFile(t, filepath.Join(dstDir, "morning_ride.toml"), "edited session")
+
+	result, err = ImportBundle(bundlePath, dstDir, false)
+	if err != nil {
+		t.Fatalf("ImportBundle() returned error: %v", err)
+	}
+
+	if !slices.Contains(result.Skipped, "morning_ride.toml") {
+		t.Errorf("ImportBundle() = %+v, want morning_ride.toml skipped", result)
+	}
+
+	if _, err := ImportBundle(bundlePath, dstDir, true); err != nil {
+		t.Fatalf("ImportBundle() returned error: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(dstDir, "morning_ride.toml")); string(content) != "session" {
+		t.Errorf("session file content = %q, want %q", content, "session")
+	}
+
+}
+
+// TestImportInvalidBundle tests that archives that aren't settings bundles, or that hold entries
+// outside the config directory, are rejected
+func TestImportInvalidBundle(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name    string
+		entries []string
+		wantErr error
+	}{
+		{"missing manifest", []string{"session.toml"}, ErrNotBundle},
+		{"entry outside config directory", []string{BundleManifestFile, "../session.toml"}, errBundleEntry},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			bundlePath := filepath.Join(t.TempDir(), "bundle.zip")
+			writeTestZip(t, bundlePath, tt.entries)
+
+			if _, err := ImportBundle(bundlePath, t.TempDir(), false); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ImportBundle() error = %v, want %v", err, tt.wantErr)
+			}
+
+		})
+	}
+
+}
+
+// writeTestFile writes a test file, failing the test on error
+func writeTestFile(t *testing.T, path, content string) {
+
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+
+}
+
+// writeTestZip writes a zip archive holding empty entries with the given names
+func writeTestZip(t *testing.T, path string, names []string) {
+
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	for _, name := range names {
+
+		if _, err := archive.Create(name); err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to close %s: %v", path, err)
+	}
+
+}
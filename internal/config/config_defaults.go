@@ -0,0 +1,154 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// NewDefault returns a Config fully populated with the default value of every setting (as
+// documented in the BSC TOML file anatomy), with placeholders for the BLE sensor BD_ADDR and the
+// video file path. It's the single source of defaults for new sessions (from the New Session
+// wizard or the init command), and for resetting a setting to its default
+func NewDefault() *Config {
+
+	return &Config{
+		App: AppConfig{
+			SessionTitle:        "New BSC Session",
+			LogLevel:            logLevelInfo,
+			BackupCount:         1,
+			MaxSessionMinutes:   0,
+			ShutdownTimeoutSecs: 30,
+		},
+		BLE: BLEConfig{
+			SensorBDAddr:    PlaceholderBDAddr,
+			ScanTimeoutSecs: 30,
+			ScanAttempts:    3,
+			EnvSensorBDAddr: "",
+		},
+		Speed: SpeedConfig{
+			SpeedUnits:           SpeedUnitsMPH,
+			WheelCircumferenceMM: 2155,
+			SpeedThreshold:       0.25,
+			SmoothingWindow:      5,
+			CadenceSpeedPerRPM:   0.0,
+			CoastDecaySecs:       0.0,
+			SpeedZones:           []float64{10.0, 15.0, 20.0},
+		},
+		Video: VideoConfig{
+			MediaPlayer:        MediaPlayerMPV,
+			FilePath:           placeholderVideo,
+			SeekToPosition:     "00:00:00",
+			WindowScaleFactor:  1.0,
+			WindowPosition:     "",
+			UpdateIntervalSec:  0.25,
+			SpeedMultiplier:    0.8,
+			MinPlaybackSpeed:   0.25,
+			MaxPlaybackSpeed:   4.0,
+			InterpolationSpeed: 0.0,
+			TargetDisplayName:  "",
+			FocusMode:          FocusModeOff,
+			AutoResume:         false,
+			ResumeDistance:     false,
+			ResumeDistanceKM:   0.0,
+			ResumeSpeeds:       nil,
+			HardwareDecoding:   HWDecAuto,
+			MediaPlayerArgs:    nil,
+			SubtitlePath:       "",
+			ShowSubtitles:      false,
+			LowPower:           false,
+			VideoFirstStart:    false,
+			StartCountdownSecs: 0,
+			ResumeFadeSecs:     0.0,
+			OnScreenDisplay: VideoOSDConfig{
+				FontSize:              40,
+				MarginX:               20,
+				MarginY:               20,
+				AlignX:                "left",
+				AlignY:                "top",
+				DisplayCycleSpeed:     true,
+				DisplayPlaybackSpeed:  true,
+				DisplayTimeRemaining:  true,
+				DisplaySpeedStats:     false,
+				DisplayMetricsOverlay: false,
+				UpdateIntervalSec:     1.0,
+				NightModeHours:        "",
+				NightBrightness:       40,
+				ShowOSD:               true,
+			},
+			Audio: VideoAudioConfig{
+				Volume:          100,
+				Mute:            false,
+				AudioTrack:      0,
+				PitchCorrection: true,
+			},
+		},
+		Goal: GoalConfig{
+			Type:          GoalTypeNone,
+			Target:        0.0,
+			RiderWeightKG: 75,
+		},
+		Intervals: IntervalConfig{
+			HardSecs: 0,
+			EasySecs: 0,
+		},
+		Laps: LapConfig{
+			Distance: 0.0,
+			Minutes:  0,
+		},
+		Race: RaceConfig{
+			TargetSpeed:     0.0,
+			EasyTargetSpeed: 0.0,
+			OutdoorGPXFile:  "",
+		},
+		Hooks: HooksConfig{
+			OnSessionStart: "",
+			OnSessionStop:  "",
+			OnLap:          "",
+			OnSensorLost:   "",
+			TimeoutSecs:    10,
+		},
+	}
+}
+
+// DefaultValue returns the default value of a session setting, given its TOML key qualified by
+// its section (e.g., "video.update_interval_secs" or "video.OSD.margin_x"), as used to reset a
+// setting to its default, or false if there's no such setting
+func DefaultValue(key string) (any, bool) {
+
+	value := reflect.ValueOf(NewDefault()).Elem()
+
+	for name := range strings.SplitSeq(key, ".") {
+
+		field, ok := tomlField(value, name)
+		if !ok {
+			return nil, false
+		}
+
+		value = field
+	}
+
+	// Sections aren't settings
+	if value.Kind() == reflect.Struct {
+		return nil, false
+	}
+
+	return value.Interface(), true
+}
+
+// tomlField returns the field of a struct value with the given TOML key
+func tomlField(value reflect.Value, key string) (reflect.Value, bool) {
+
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	for i := range value.NumField() {
+
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("toml"), ",")
+		if name == key && name != "-" {
+			return value.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestNewDefault tests that every default setting is valid (other than the placeholder video file)
+func TestNewDefault(t *testing.T) {
+
+	cfg := NewDefault()
+
+	if cfg.BLE.SensorBDAddr != PlaceholderBDAddr || cfg.Video.FilePath != placeholderVideo {
+		t.Errorf("NewDefault() placeholders = (%q, %q), want (%q, %q)", cfg.BLE.SensorBDAddr, cfg.Video.FilePath, PlaceholderBDAddr, placeholderVideo)
+	}
+
+	cfg.Video.FilePath = testVideo
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("NewDefault() is not valid: %v", err)
+	}
+
+	// Each call returns its own copy of the defaults
+	cfg.Speed.SpeedZones[0] = 5.0
+
+	if zones := NewDefault().Speed.SpeedZones; zones[0] != 10.0 {
+		t.Errorf("NewDefault() speed zones = %v, want defaults unchanged", zones)
+	}
+
+}
+
+// TestDefaultConfig tests that the defaults for settings absent from a session file match the
+// NewDefault settings, other than those that deliberately differ
+func TestDefaultConfig(t *testing.T) {
+
+	differ := map[string]any{
+		"ble.sensor_bd_addr":       "",
+		"video.file_path":          "",
+		"video.hardware_decoding":  HWDecOff,
+		"video.min_playback_speed": 0.0,
+		"video.max_playback_speed": 0.0,
+		"speed.speed_zones":        []float64(nil),
+	}
+
+	checkDefaults(t, "", reflect.ValueOf(defaultConfig()).Elem(), reflect.ValueOf(NewDefault()).Elem(), differ)
+
+}
+
+// TestDefaultConfigEarlierFile tests that a session file created by an earlier version of the
+// application (without the settings added since) loads with the features added since turned off
+func TestDefaultConfigEarlierFile(t *testing.T) {
+
+	content := `[app]
+  session_title = "Session Title"
+  logging_level = "info"
+
+[ble]
+  sensor_bd_addr = "FA:46:1D:77:C8:E1"
+  scan_timeout_secs = 30
+
+[speed]
+  wheel_circumference_mm = 2155
+  speed_units = "km/h"
+  speed_threshold = 0.25
+  smoothing_window = 5
+
+[video]
+  media_player = "mpv"
+  file_path = "` + testVideo + `"
+  seek_to_position = "00:00:00"
+  auto_resume = false
+  window_scale_factor = 1.0
+  update_interval_secs = 0.25
+  speed_multiplier = 0.8
+  target_display_name = ""
+
+  [video.OSD]
+    display_cycle_speed = true
+    display_playback_speed = true
+    display_time_remaining = true
+    font_size = 40
+    align_x = "left"
+    align_y = "top"
+    margin_x = 20
+    margin_y = 20
+`
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := readConfigFile(path, defaultConfig())
+	if err != nil {
+		t.Fatalf("readConfigFile() error = %v", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("earlier session file is not valid: %v", err)
+	}
+
+	if cfg.Video.MinPlaybackSpeed != 0 || cfg.Video.MaxPlaybackSpeed != 0 {
+		t.Errorf("playback speed limits = (%v, %v), want none", cfg.Video.MinPlaybackSpeed, cfg.Video.MaxPlaybackSpeed)
+	}
+
+	if cfg.Speed.SpeedZones != nil {
+		t.Errorf("speed zones = %v, want none", cfg.Speed.SpeedZones)
+	}
+
+	if cfg.Video.HardwareDecoding != HWDecOff {
+		t.Errorf("hardware decoding = %q, want %q", cfg.Video.HardwareDecoding, HWDecOff)
+	}
+
+}
+
+// checkDefaults compares each setting of a defaultConfig section with its NewDefault setting,
+// given their TOML keys qualified by section
+func checkDefaults(t *testing.T, section string, got, want reflect.Value, differ map[string]any) {
+
+	t.Helper()
+
+	for i := range got.NumField() {
+
+		key, _, _ := strings.Cut(got.Type().Field(i).Tag.Get("toml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+
+		gotField, wantField := got.Field(i), want.Field(i)
+
+		if gotField.Kind() == reflect.Struct {
+			checkDefaults(t, key, gotField, wantField, differ)
+
+			continue
+		}
+
+		wantValue := wantField.Interface()
+		if value, ok := differ[key]; ok {
+			wantValue = value
+		}
+
+		if !reflect.DeepEqual(gotField.Interface(), wantValue) {
+			t.Errorf("defaultConfig() %s = %v, want %v", key, gotField.Interface(), wantValue)
+		}
+	}
+
+}
+
+// TestDefaultValue tests looking up the default values of session settings by TOML key
+func TestDefaultValue(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		key    string
+		want   any
+		wantOK bool
+	}{
+		{"video.update_interval_secs", 0.25, true},
+		{"video.OSD.margin_x", 20, true},
+		{"speed.smoothing_window", 5, true},
+		{"speed.speed_units", SpeedUnitsMPH, true},
+		{"video.media_player_args", []string(nil), true},
+		{"video.OSD", nil, false},
+		{"video.unknown_setting", nil, false},
+		{"speed.smoothing_window.extra", nil, false},
+		{"speed.replay_file", nil, false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.key, func(t *testing.T) {
+
+			got, ok := DefaultValue(tt.key)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultValue() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+
+		})
+	}
+
+}
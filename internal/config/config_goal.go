@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Session goal types
+const (
+	GoalTypeNone     = ""
+	GoalTypeTime     = "time"     // Riding time, in minutes
+	GoalTypeDistance = "distance" // Distance ridden, in kilometers (or miles when speed_units is "mph")
+	GoalTypeCalories = "calories" // Estimated calories burned, in kilocalories
+)
+
+// Valid session goal types
+var validGoalTypes = map[string]bool{
+	GoalTypeNone:     true,
+	GoalTypeTime:     true,
+	GoalTypeDistance: true,
+	GoalTypeCalories: true,
+}
+
+// GoalConfig defines the (optional) session goal settings from the TOML config file
+type GoalConfig struct {
+	Type          string  `toml:"type"`
+	Target        float64 `toml:"target"`
+	RiderWeightKG int     `toml:"rider_weight_kg"`
+}
+
+// validate checks GoalConfig for valid settings
+func (gc *GoalConfig) validate() error {
+
+	if !validGoalTypes[gc.Type] {
+		return fmt.Errorf(errFormatRev, errInvalidGoalType, gc.Type)
+	}
+
+	if err := validateConfigFields(gc.configValidationRanges()); err != nil {
+		return err
+	}
+
+	// A goal requires a target to ride toward
+	if gc.Type != GoalTypeNone && gc.Target <= 0 {
+		return fmt.Errorf(errFormatRev, errGoalTarget, "a goal target is required when a goal type is set")
+	}
+
+	return nil
+}
+
+// configValidationRanges returns validation ranges for GoalConfig
+func (gc *GoalConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"goal.target", gc.Target, 0.0, 10000.0, errGoalTarget},
+		{"goal.rider_weight_kg", gc.RiderWeightKG, 30, 250, errRiderWeight},
+	}
+}
+
+// Enabled returns true if the session has a goal
+func (gc GoalConfig) Enabled() bool {
+	return gc.Type != GoalTypeNone
+}
+
+// Units returns the units of the goal target (distances are ridden in miles when the speed units
+// are mph, and in kilometers otherwise)
+func (gc GoalConfig) Units(speedUnits string) string {
+
+	switch gc.Type {
+	case GoalTypeTime:
+		return "min"
+	case GoalTypeDistance:
+		return units.DistanceUnits(speedUnits)
+	case GoalTypeCalories:
+		return "kcal"
+	}
+
+	return ""
+}
+
+// Describe returns a short description of the goal (e.g., "ride 60 min" or "ride 25.0 km")
+func (gc GoalConfig) Describe(speedUnits string) string {
+
+	if !gc.Enabled() {
+		return "no goal"
+	}
+
+	return fmt.Sprintf("%s %s %s", goalVerb(gc.Type), FormatGoalValue(gc.Type, gc.Target), gc.Units(speedUnits))
+}
+
+// FormatGoalValue formats a value toward a goal for display (distances keep one decimal place)
+func FormatGoalValue(goalType string, value float64) string {
+
+	if goalType == GoalTypeDistance {
+		return fmt.Sprintf("%.1f", value)
+	}
+
+	return fmt.Sprintf("%.0f", value)
+}
+
+// goalVerb returns the verb used to describe a goal of the given type
+func goalVerb(goalType string) string {
+
+	if goalType == GoalTypeCalories {
+		return "burn"
+	}
+
+	return "ride"
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Session history settings
+const (
+	SessionHistoryFile = "history.toml"
+	maxHistoryRecords  = 1000 // Oldest records are dropped once the history holds this many
+)
+
+// SessionRecord is a completed BSC session, as recorded in the session history
+type SessionRecord struct {
+	Title       string    `toml:"title"`
+	Video       string    `toml:"video,omitempty"` // File name of the video played
+	Started     time.Time `toml:"started"`
+	RideSecs    int       `toml:"ride_time_secs"`
+	DistanceKM  float64   `toml:"distance_km"`
+	Calories    float64   `toml:"calories"`
+	GoalType    string    `toml:"goal_type"`
+	GoalTarget  float64   `toml:"goal_target"`
+	GoalReached bool      `toml:"goal_reached"`
+
+	// Average (while moving) and maximum smoothed speeds of the session
+	AvgSpeedKPH float64 `toml:"avg_speed_kph,omitempty"`
+	MaxSpeedKPH float64 `toml:"max_speed_kph,omitempty"`
+
+	// Ride time spent in each speed zone, from the slowest zone (if speed zones are configured)
+	ZoneSecs []int `toml:"zone_secs,omitempty"`
+
+	// Average conditions reported by an environmental sensor (if any)
+	TemperatureC float64 `toml:"temperature_c,omitempty"`
+	HumidityPct  float64 `toml:"humidity_pct,omitempty"`
+
+	// Effort score of a race against the clock (0-100), if the session was a race
+	RaceScore float64 `toml:"race_score,omitempty"`
+
+	// Laps of the session (if any were marked), including the ride since the last lap
+	Laps []LapRecord `toml:"lap,omitempty"`
+
+	// Free-text notes and tags (e.g., "recovery", "ftp test") added when the session stops
+	Notes string   `toml:"notes,omitempty"`
+	Tags  []string `toml:"tags,omitempty"`
+}
+
+// LapRecord is a lap of a completed BSC session, as recorded in the session history
+type LapRecord struct {
+	RideSecs   int     `toml:"ride_time_secs"`
+	DistanceKM float64 `toml:"distance_km"`
+	Manual     bool    `toml:"manual"`
+}
+
+// SessionHistory is the persistent list of completed BSC sessions, oldest first
+type SessionHistory struct {
+	Sessions []SessionRecord `toml:"session"`
+}
+
+// sessionHistoryMu serializes session history updates (e.g., by concurrently running sessions)
+var sessionHistoryMu sync.Mutex
+
+// SessionHistoryPath returns the path of the session history in the user config directory
+func SessionHistoryPath() (string, error) {
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, sensorRegistryDir, SessionHistoryFile), nil
+}
+
+// LoadSessionHistory loads the session history file, returning an empty history if the file
+// does not yet exist
+func LoadSessionHistory(path string) (*SessionHistory, error) {
+
+	history := &SessionHistory{}
+
+	if _, err := toml.DecodeFile(path, history); err != nil {
+
+		if errors.Is(err, os.ErrNotExist) {
+			return history, nil
+		}
+
+		return nil, fmt.Errorf(errFormat, "failed to load session history", err)
+	}
+
+	return history, nil
+}
+
+// AppendSessionHistory adds a session record to the session history file, serializing
+// concurrent updates
+func AppendSessionHistory(path string, record SessionRecord) error {
+
+	sessionHistoryMu.Lock()
+	defer sessionHistoryMu.Unlock()
+
+	history, err := LoadSessionHistory(path)
+	if err != nil {
+		return err
+	}
+
+	history.Add(record)
+
+	return history.Save(path)
+}
+
+// AnnotateSessionHistory sets the notes and tags of the session record started at the given time,
+// serializing concurrent updates
+func AnnotateSessionHistory(path string, started time.Time, notes string, tags []string) error {
+
+	sessionHistoryMu.Lock()
+	defer sessionHistoryMu.Unlock()
+
+	history, err := LoadSessionHistory(path)
+	if err != nil {
+		return err
+	}
+
+	// Search from the newest record, as annotations usually follow the session just stopped
+	for i := len(history.Sessions) - 1; i >= 0; i-- {
+
+		if history.Sessions[i].Started.Equal(started) {
+			history.Sessions[i].Notes = strings.TrimSpace(notes)
+			history.Sessions[i].Tags = tags
+
+			return history.Save(path)
+		}
+	}
+
+	return fmt.Errorf(errFormatRev, errSessionNotRecorded, started.Format(time.RFC3339))
+}
+
+// ParseTags parses a comma-separated list of session tags, trimming (and dropping empty and
+// duplicate) tags
+func ParseTags(list string) []string {
+
+	var tags []string
+
+	for tag := range strings.SplitSeq(list, ",") {
+
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// Matches returns true if the session title, notes, or any of its tags contain the search query
+// (ignoring case), or if the query is empty
+func (r SessionRecord) Matches(query string) bool {
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+
+	fields := append([]string{r.Title, r.Notes}, r.Tags...)
+
+	return slices.ContainsFunc(fields, func(field string) bool {
+		return strings.Contains(strings.ToLower(field), query)
+	})
+}
+
+// Search returns the session records matching the search query, newest first
+func (h *SessionHistory) Search(query string) []SessionRecord {
+
+	var records []SessionRecord
+
+	for _, record := range slices.Backward(h.Sessions) {
+
+		if record.Matches(query) {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// Add appends a session record to the history, dropping the oldest records beyond the history
+// limit
+func (h *SessionHistory) Add(record SessionRecord) {
+
+	h.Sessions = append(h.Sessions, record)
+
+	if excess := len(h.Sessions) - maxHistoryRecords; excess > 0 {
+		h.Sessions = h.Sessions[excess:]
+	}
+
+}
+
+// Save writes the session history file, creating its directory as needed
+func (h *SessionHistory) Save(path string) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(errFormat, "failed to create session history directory", err)
+	}
+
+	err := writeFileAtomic(path, 0644, 0, func(w io.Writer) error {
+		return toml.NewEncoder(w).Encode(h)
+	})
+
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to write session history", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestSessionHistory tests appending session records to (and reloading) the session history
+func TestSessionHistory(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, SessionHistoryFile)
+
+	// A missing history file is an empty history
+	history, err := LoadSessionHistory(path)
+	if err != nil {
+		t.Fatalf("LoadSessionHistory() returned error: %v", err)
+	}
+
+	if len(history.Sessions) != 0 {
+		t.Fatalf("LoadSessionHistory() returned %d sessions, want 0", len(history.Sessions))
+	}
+
+	started := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+
+	records := []SessionRecord{
+		{Title: "Morning ride", Started: started, RideSecs: 3600, DistanceKM: 25.2, Calories: 610, GoalType: GoalTypeTime, GoalTarget: 60, GoalReached: true},
+		{Title: "Evening ride", Started: started.Add(10 * time.Hour), RideSecs: 900, DistanceKM: 6.1, Calories: 150},
+	}
+
+	for _, record := range records {
+
+		if err := AppendSessionHistory(path, record); err != nil {
+			t.Fatalf("AppendSessionHistory() returned error: %v", err)
+		}
+	}
+
+	history, err = LoadSessionHistory(path)
+	if err != nil {
+		t.Fatalf("LoadSessionHistory() returned error: %v", err)
+	}
+
+	if len(history.Sessions) != len(records) {
+		t.Fatalf("LoadSessionHistory() returned %d sessions, want %d", len(history.Sessions), len(records))
+	}
+
+	for i, got := range history.Sessions {
+
+		want := records[i]
+		if got.Title != want.Title || !got.Started.Equal(want.Started) || got.RideSecs != want.RideSecs ||
+			got.GoalType != want.GoalType || got.GoalReached != want.GoalReached {
+			t.Errorf("session %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// The oldest records are dropped beyond the history limit
+	for range maxHistoryRecords {
+		history.Add(SessionRecord{Title: "Ride"})
+	}
+
+	if len(history.Sessions) != maxHistoryRecords || history.Sessions[0].Title != "Ride" {
+		t.Errorf("history holds %d sessions (first %q), want %d sessions of the latest rides", len(history.Sessions), history.Sessions[0].Title, maxHistoryRecords)
+	}
+
+}
+
+// TestAnnotateSessionHistory tests adding notes and tags to a recorded session
+func TestAnnotateSessionHistory(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, SessionHistoryFile)
+	started := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+
+	if err := AppendSessionHistory(path, SessionRecord{Title: "Morning ride", Started: started, RideSecs: 3600}); err != nil {
+		t.Fatalf("AppendSessionHistory() returned error: %v", err)
+	}
+
+	if err := AnnotateSessionHistory(path, started, "  Legs felt heavy  ", []string{"recovery", "Alps video"}); err != nil {
+		t.Fatalf("AnnotateSessionHistory() returned error: %v", err)
+	}
+
+	history, err := LoadSessionHistory(path)
+	if err != nil {
+		t.Fatalf("LoadSessionHistory() returned error: %v", err)
+	}
+
+	got := history.Sessions[0]
+	if got.Notes != "Legs felt heavy" || len(got.Tags) != 2 || got.Tags[1] != "Alps video" || got.RideSecs != 3600 {
+		t.Errorf("annotated session = %+v, want notes and tags added", got)
+	}
+
+	// An unrecorded session can't be annotated
+	if err := AnnotateSessionHistory(path, started.Add(time.Hour), "notes", nil); !errors.Is(err, errSessionNotRecorded) {
+		t.Errorf("AnnotateSessionHistory() of unrecorded session returned %v, want %v", err, errSessionNotRecorded)
+	}
+
+}
+
+// TestParseTags tests parsing a comma-separated list of session tags
+func TestParseTags(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{name: "empty", list: "", want: nil},
+		{name: "single", list: "recovery", want: []string{"recovery"}},
+		{name: "trimmed", list: " recovery , FTP test ,Alps video ", want: []string{"recovery", "FTP test", "Alps video"}},
+		{name: "empty tags dropped", list: "recovery,, ,", want: []string{"recovery"}},
+		{name: "duplicates dropped", list: "recovery, Recovery, ftp", want: []string{"recovery", "ftp"}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := ParseTags(tt.list); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTags(%q) = %q, want %q", tt.list, got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestSessionHistorySearch tests searching the session history by title, notes, and tags
+func TestSessionHistorySearch(t *testing.T) {
+
+	history := &SessionHistory{Sessions: []SessionRecord{
+		{Title: "Morning ride", Tags: []string{"recovery"}},
+		{Title: "FTP test", Notes: "New best!"},
+		{Title: "Evening ride", Notes: "Alps video, hot room", Tags: []string{"endurance"}},
+	}}
+
+	// Define test cases
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty query (newest first)", query: "", want: []string{"Evening ride", "FTP test", "Morning ride"}},
+		{name: "title", query: "ride", want: []string{"Evening ride", "Morning ride"}},
+		{name: "notes ignoring case", query: "ALPS", want: []string{"Evening ride"}},
+		{name: "tag", query: "recovery", want: []string{"Morning ride"}},
+		{name: "no match", query: "sprint", want: nil},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			var got []string
+			for _, record := range history.Search(tt.query) {
+				got = append(got, record.Title)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+
+		})
+	}
+
+}
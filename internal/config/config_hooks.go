@@ -0,0 +1,58 @@
+package config
+
+import (
+	"time"
+)
+
+// Session hook events
+const (
+	HookSessionStart = "session_start"
+	HookSessionStop  = "session_stop"
+	HookLap          = "lap"
+	HookSensorLost   = "sensor_lost"
+)
+
+// HooksConfig defines the (optional) commands run on session events from the TOML config file,
+// each receiving the session event and metrics as JSON
+type HooksConfig struct {
+	OnSessionStart string `toml:"on_session_start"`
+	OnSessionStop  string `toml:"on_session_stop"`
+	OnLap          string `toml:"on_lap"`
+	OnSensorLost   string `toml:"on_sensor_lost"`
+	TimeoutSecs    int    `toml:"timeout_secs"`
+}
+
+// validate checks HooksConfig for valid settings
+func (hc *HooksConfig) validate() error {
+	return validateConfigFields(hc.configValidationRanges())
+}
+
+// configValidationRanges returns validation ranges for HooksConfig
+func (hc *HooksConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"hooks.timeout_secs", hc.TimeoutSecs, 1, 300, errHookTimeout},
+	}
+}
+
+// Command returns the command run on the given session event (empty if none)
+func (hc HooksConfig) Command(event string) string {
+
+	switch event {
+	case HookSessionStart:
+		return hc.OnSessionStart
+	case HookSessionStop:
+		return hc.OnSessionStop
+	case HookLap:
+		return hc.OnLap
+	case HookSensorLost:
+		return hc.OnSensorLost
+	}
+
+	return ""
+}
+
+// Timeout returns the time allowed for each hook command to complete
+func (hc HooksConfig) Timeout() time.Duration {
+	return time.Duration(hc.TimeoutSecs) * time.Second
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Starter configuration settings
+const (
+	StarterFileName   = "config.toml"
+	PlaceholderBDAddr = "AA:BB:CC:DD:EE:FF"
+	placeholderVideo  = "/path/to/your/cycling_video.mp4"
+)
+
+// ErrConfigExists is returned when writing a starter config file would overwrite an existing file
+var ErrConfigExists = errors.New("config file already exists")
+
+// StarterConfig returns a Config populated with the defaults of every setting (see NewDefault),
+// and placeholders for the BLE sensor BD_ADDR and the video file path
+func StarterConfig() *Config {
+
+	cfg := NewDefault()
+	cfg.App.SessionTitle = "My First BSC Session"
+
+	return cfg
+}
+
+// WriteStarterConfig writes a commented starter config file into the directory (creating the
+// directory if needed), returning the path of the new config file
+func WriteStarterConfig(dir string, cfg *Config) (string, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	path := filepath.Join(dir, StarterFileName)
+
+	// Never overwrite an existing (and possibly customized) config file
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf(errFormatRev, ErrConfigExists, path)
+	}
+
+	if err := Save(path, cfg, GetVersion()); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TestWriteStarterConfig tests writing a starter config file, and that an existing config file
+// is never overwritten
+func TestWriteStarterConfig(t *testing.T) {
+
+	dir := filepath.Join(t.TempDir(), "rides")
+
+	path, err := WriteStarterConfig(dir, StarterConfig())
+	if err != nil {
+		t.Fatalf("WriteStarterConfig() returned error: %v", err)
+	}
+
+	if path != filepath.Join(dir, StarterFileName) {
+		t.Errorf("WriteStarterConfig() path = %q, want %q", path, filepath.Join(dir, StarterFileName))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read starter config file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "# The Bluetooth Device Address") {
+		t.Error("starter config file is missing its comments")
+	}
+
+	// Every setting other than the placeholder video file must be valid
+	cfg := defaultConfig()
+	if _, err := toml.Decode(string(content), cfg); err != nil {
+		t.Fatalf("failed to decode starter config file: %v", err)
+	}
+
+	cfg.Video.FilePath = "test_video.mp4"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("starter config file is not valid: %v", err)
+	}
+
+	if _, err := WriteStarterConfig(dir, StarterConfig()); !errors.Is(err, ErrConfigExists) {
+		t.Errorf("WriteStarterConfig() error = %v, want %v", err, ErrConfigExists)
+	}
+
+}
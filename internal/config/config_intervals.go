@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+)
+
+// IntervalConfig defines the (optional) interval timer settings from the TOML config file, which
+// repeat a hard interval followed by an easy (recovery) interval
+type IntervalConfig struct {
+	HardSecs int `toml:"hard_secs"`
+	EasySecs int `toml:"easy_secs"`
+}
+
+// validate checks IntervalConfig for valid settings
+func (ic *IntervalConfig) validate() error {
+
+	if err := validateConfigFields(ic.configValidationRanges()); err != nil {
+		return err
+	}
+
+	// An interval timer requires both a hard and an easy interval
+	if (ic.HardSecs > 0) != (ic.EasySecs > 0) {
+		return fmt.Errorf(errFormatRev, errIntervalPair, fmt.Sprintf("hard_secs = %d, easy_secs = %d", ic.HardSecs, ic.EasySecs))
+	}
+
+	return nil
+}
+
+// configValidationRanges returns validation ranges for IntervalConfig
+func (ic *IntervalConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"intervals.hard_secs", ic.HardSecs, 0, 3600, errIntervalSecs},
+		{"intervals.easy_secs", ic.EasySecs, 0, 3600, errIntervalSecs},
+	}
+}
+
+// Enabled returns true if the session has an interval timer
+func (ic IntervalConfig) Enabled() bool {
+	return ic.HardSecs > 0 && ic.EasySecs > 0
+}
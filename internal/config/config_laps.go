@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// LapConfig defines the (optional) automatic lap settings from the TOML config file, which mark a
+// lap every lap distance or every lap length in minutes (laps can also be marked manually)
+type LapConfig struct {
+	Distance float64 `toml:"distance"`
+	Minutes  int     `toml:"minutes"`
+}
+
+// validate checks LapConfig for valid settings
+func (lc *LapConfig) validate() error {
+
+	if err := validateConfigFields(lc.configValidationRanges()); err != nil {
+		return err
+	}
+
+	// Automatic laps are marked either by distance or by time
+	if lc.Distance > 0 && lc.Minutes > 0 {
+		return fmt.Errorf(errFormatRev, errLapPair, fmt.Sprintf("distance = %.1f, minutes = %d", lc.Distance, lc.Minutes))
+	}
+
+	return nil
+}
+
+// configValidationRanges returns validation ranges for LapConfig
+func (lc *LapConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"laps.distance", lc.Distance, 0.0, 1000.0, errLapDistance},
+		{"laps.minutes", lc.Minutes, 0, 600, errLapMinutes},
+	}
+}
+
+// Enabled returns true if the session marks laps automatically
+func (lc LapConfig) Enabled() bool {
+	return lc.Distance > 0 || lc.Minutes > 0
+}
+
+// DistanceKM returns the automatic lap distance in kilometers (the lap distance is given in miles
+// when the speed units are mph)
+func (lc LapConfig) DistanceKM(speedUnits string) float64 {
+	return units.DistanceKM(lc.Distance, speedUnits)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Thresholds used when checking for settings that are likely mistakes
+const (
+	lintMaxInchCircumference = 100  // Wheel circumferences (in mm) at or below this look like inches
+	lintMaxTimeRemainingSecs = 1.0  // Update intervals above this make the time remaining jump
+	lintMaxThresholdRatio    = 0.20 // Speed thresholds above this share of a typical speed hide changes
+	mmPerInch                = 25.4
+)
+
+// Typical cycling speed for each of the speed units
+var typicalSpeeds = map[string]float64{
+	SpeedUnitsKMH: 25.0,
+	SpeedUnitsMPH: 15.0,
+	SpeedUnitsMPS: 7.0,
+	SpeedUnitsRPM: 200.0,
+}
+
+// Warning describes a valid setting that is likely a mistake (e.g., a wheel circumference entered
+// in inches). Unlike a validation error, a warning doesn't prevent a session from running
+type Warning struct {
+	Key     string // The TOML key of the setting (e.g., "speed.wheel_circumference_mm")
+	Message string
+}
+
+// String returns the warning in the form "key: message"
+func (w Warning) String() string {
+	return w.Key + ": " + w.Message
+}
+
+// Lint checks a (valid) configuration for settings that are likely mistakes, returning a warning
+// for each one found
+func (c *Config) Lint() []Warning {
+
+	var warnings []Warning
+
+	osd := c.Video.OnScreenDisplay
+
+	if osd.DisplayTimeRemaining && c.Video.UpdateIntervalSec > lintMaxTimeRemainingSecs {
+		warnings = append(warnings, Warning{
+			Key: "video.update_interval_secs",
+			Message: fmt.Sprintf("an update interval of %.1f seconds makes the time remaining shown on the OSD jump between updates (try %.1f seconds or less, or turn off display_time_remaining)",
+				c.Video.UpdateIntervalSec, lintMaxTimeRemainingSecs),
+		})
+	}
+
+	if typical, ok := typicalSpeeds[c.Speed.SpeedUnits]; ok && c.Speed.SpeedThreshold > typical*lintMaxThresholdRatio {
+		warnings = append(warnings, Warning{
+			Key: "speed.speed_threshold",
+			Message: fmt.Sprintf("a speed threshold of %.2f %s is large compared to a typical cycling speed (about %.0f %s), so video playback only follows large changes in speed",
+				c.Speed.SpeedThreshold, c.Speed.SpeedUnits, typical, c.Speed.SpeedUnits),
+		})
+	}
+
+	if c.Speed.WheelCircumferenceMM <= lintMaxInchCircumference {
+		warnings = append(warnings, Warning{
+			Key: "speed.wheel_circumference_mm",
+			Message: fmt.Sprintf("a wheel circumference of %d mm looks like it was entered in inches (%d inches is about %.0f mm)",
+				c.Speed.WheelCircumferenceMM, c.Speed.WheelCircumferenceMM, float64(c.Speed.WheelCircumferenceMM)*mmPerInch),
+		})
+	}
+
+	if c.Video.InterpolationSpeed > 0 && c.Video.LowPower {
+		warnings = append(warnings, Warning{
+			Key:     "video.frame_interpolation_speed",
+			Message: "frame interpolation is not used when low_power is enabled (it adds to the GPU load the low-power profile reduces)",
+		})
+	}
+
+	return warnings
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestLint tests that settings that are likely mistakes generate warnings
+func TestLint(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		wantKeys []string
+	}{
+		{"typical settings", func(_ *Config) {}, nil},
+		{"coarse update interval with time remaining", func(cfg *Config) { cfg.Video.UpdateIntervalSec = 2.0 }, []string{"video.update_interval_secs"}},
+		{"coarse update interval without time remaining", func(cfg *Config) {
+			cfg.Video.UpdateIntervalSec = 2.0
+			cfg.Video.OnScreenDisplay.DisplayTimeRemaining = false
+		}, nil},
+		{"large speed threshold", func(cfg *Config) { cfg.Speed.SpeedThreshold = 8.0 }, []string{"speed.speed_threshold"}},
+		{"speed threshold in rpm", func(cfg *Config) {
+			cfg.Speed.SpeedUnits = SpeedUnitsRPM
+			cfg.Speed.SpeedThreshold = 8.0
+		}, nil},
+		{"wheel circumference in inches", func(cfg *Config) { cfg.Speed.WheelCircumferenceMM = 83 }, []string{"speed.wheel_circumference_mm"}},
+		{"frame interpolation with low power", func(cfg *Config) {
+			cfg.Video.InterpolationSpeed = 0.5
+			cfg.Video.LowPower = true
+		}, []string{"video.frame_interpolation_speed"}},
+		{"several warnings", func(cfg *Config) {
+			cfg.Speed.WheelCircumferenceMM = 83
+			cfg.Speed.SpeedThreshold = 8.0
+		}, []string{"speed.speed_threshold", "speed.wheel_circumference_mm"}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			cfg := createTestConfig()
+			tt.modify(cfg)
+
+			var gotKeys []string
+			for _, w := range cfg.Lint() {
+				gotKeys = append(gotKeys, w.Key)
+			}
+
+			if !slices.Equal(gotKeys, tt.wantKeys) {
+				t.Errorf("Lint() warned about %v, want %v", gotKeys, tt.wantKeys)
+			}
+
+		})
+	}
+
+}
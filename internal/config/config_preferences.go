@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Application preferences settings
+const (
+	PreferencesFile = "preferences.toml"
+)
+
+// Keyboard shortcut actions (named after the GUI application actions they activate)
+const (
+	ShortcutStartStop    = "start-stop"
+	ShortcutTogglePause  = "toggle-pause"
+	ShortcutLap          = "lap"
+	ShortcutShowSelect   = "show-page1"
+	ShortcutShowStatus   = "show-page2"
+	ShortcutShowLog      = "show-page3"
+	ShortcutShowEditor   = "show-page4"
+	ShortcutShowHistory  = "show-page5"
+	ShortcutShowKeyboard = "shortcuts"
+)
+
+// Shortcut is a keyboard shortcut action and its default accelerator (in GTK accelerator format,
+// e.g., "<Control>l")
+type Shortcut struct {
+	Action  string
+	Default string
+}
+
+// Shortcuts lists the keyboard shortcut actions of the GUI, in the order they're shown
+var Shortcuts = []Shortcut{
+	{ShortcutStartStop, "<Control>Return"},
+	{ShortcutTogglePause, "<Control>p"},
+	{ShortcutLap, "<Control>l"},
+	{ShortcutShowSelect, "<Alt>1"},
+	{ShortcutShowStatus, "<Alt>2"},
+	{ShortcutShowLog, "<Alt>3"},
+	{ShortcutShowEditor, "<Alt>4"},
+	{ShortcutShowHistory, "<Alt>5"},
+	{ShortcutShowKeyboard, "<Control>question"},
+}
+
+// Preferences holds the application preferences of the GUI, shared by all BSC sessions
+type Preferences struct {
+	Keymap      map[string]string `toml:"keymap"`       // Accelerators of the rebound shortcut actions ("" = unbound)
+	SessionDirs []string          `toml:"session_dirs"` // Other directories searched for session files (e.g., on a NAS)
+}
+
+// PreferencesPath returns the path of the application preferences in the user config directory
+func PreferencesPath() (string, error) {
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, sensorRegistryDir, PreferencesFile), nil
+}
+
+// LoadPreferences loads the application preferences file, returning default preferences if the
+// file does not yet exist
+func LoadPreferences(path string) (*Preferences, error) {
+
+	prefs := &Preferences{}
+
+	if _, err := toml.DecodeFile(path, prefs); err != nil {
+
+		if errors.Is(err, os.ErrNotExist) {
+			return prefs, nil
+		}
+
+		return nil, fmt.Errorf(errFormat, "failed to load preferences", err)
+	}
+
+	return prefs, nil
+}
+
+// Save writes the application preferences file, creating its directory as needed
+func (p *Preferences) Save(path string) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(errFormat, "failed to create preferences directory", err)
+	}
+
+	err := writeFileAtomic(path, 0644, 0, func(w io.Writer) error {
+		return toml.NewEncoder(w).Encode(p)
+	})
+
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to write preferences", err)
+	}
+
+	return nil
+}
+
+// Accelerator returns the accelerator of a keyboard shortcut action: the rebound accelerator from
+// the keymap, or else the default accelerator of the action
+func (p *Preferences) Accelerator(action string) string {
+
+	if accel, ok := p.Keymap[action]; ok {
+		return accel
+	}
+
+	for _, shortcut := range Shortcuts {
+
+		if shortcut.Action == action {
+			return shortcut.Default
+		}
+	}
+
+	return ""
+}
+
+// Rebind sets the accelerator of a keyboard shortcut action (an empty accelerator unbinds the
+// action), unbinding any other action using the same accelerator
+func (p *Preferences) Rebind(action, accel string) {
+
+	accel = strings.TrimSpace(accel)
+
+	if p.Keymap == nil {
+		p.Keymap = make(map[string]string)
+	}
+
+	if accel != "" {
+
+		for _, shortcut := range Shortcuts {
+
+			if shortcut.Action != action && p.Accelerator(shortcut.Action) == accel {
+				p.Keymap[shortcut.Action] = ""
+			}
+		}
+	}
+
+	p.Keymap[action] = accel
+
+	// Keep the keymap limited to actions that differ from their defaults
+	for _, shortcut := range Shortcuts {
+
+		if accel, ok := p.Keymap[shortcut.Action]; ok && accel == shortcut.Default {
+			delete(p.Keymap, shortcut.Action)
+		}
+	}
+
+}
+
+// ResetKeymap restores the default accelerators of all keyboard shortcut actions
+func (p *Preferences) ResetKeymap() {
+	p.Keymap = nil
+}
+
+// AddSessionDir adds a directory to the directories searched for session files, returning false if
+// the directory is already included
+func (p *Preferences) AddSessionDir(dir string) bool {
+
+	dir = filepath.Clean(dir)
+
+	if slices.Contains(p.SessionDirs, dir) {
+		return false
+	}
+
+	p.SessionDirs = append(p.SessionDirs, dir)
+
+	return true
+}
+
+// RemoveSessionDir removes a directory from the directories searched for session files
+func (p *Preferences) RemoveSessionDir(dir string) {
+
+	p.SessionDirs = slices.DeleteFunc(p.SessionDirs, func(d string) bool {
+		return d == filepath.Clean(dir)
+	})
+
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestPreferencesKeymap tests rebinding, saving and reloading the keyboard shortcuts of the
+// application preferences
+func TestPreferencesKeymap(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, PreferencesFile)
+
+	// A missing preferences file holds the default keyboard shortcuts
+	prefs, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatalf("LoadPreferences() returned error: %v", err)
+	}
+
+	if got := prefs.Accelerator(ShortcutLap); got != "<Control>l" {
+		t.Fatalf("Accelerator(%q) = %q, want the default accelerator", ShortcutLap, got)
+	}
+
+	// Rebinding an action unbinds any other action using the same accelerator
+	prefs.Rebind(ShortcutTogglePause, "space")
+	prefs.Rebind(ShortcutLap, "space")
+	prefs.Rebind(ShortcutStartStop, "<Control>Return")
+
+	if err := prefs.Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	prefs, err = LoadPreferences(path)
+	if err != nil {
+		t.Fatalf("LoadPreferences() returned error: %v", err)
+	}
+
+	// Define test cases
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{ShortcutLap, "space"},
+		{ShortcutTogglePause, ""},
+		{ShortcutStartStop, "<Control>Return"},
+		{ShortcutShowStatus, "<Alt>2"},
+		{"unknown", ""},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+
+			if got := prefs.Accelerator(tt.action); got != tt.want {
+				t.Errorf("Accelerator(%q) = %q, want %q", tt.action, got, tt.want)
+			}
+
+		})
+	}
+
+	// Default accelerators are not kept in the keymap
+	if _, ok := prefs.Keymap[ShortcutStartStop]; ok || len(prefs.Keymap) != 2 {
+		t.Errorf("Keymap = %v, want only the rebound actions", prefs.Keymap)
+	}
+
+	prefs.ResetKeymap()
+
+	if got := prefs.Accelerator(ShortcutTogglePause); got != "<Control>p" {
+		t.Errorf("Accelerator(%q) after ResetKeymap() = %q, want the default accelerator", ShortcutTogglePause, got)
+	}
+
+}
+
+// TestPreferencesSessionDirs tests adding and removing the session directories of the application
+// preferences
+func TestPreferencesSessionDirs(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, PreferencesFile)
+	prefs := &Preferences{}
+
+	if !prefs.AddSessionDir("/mnt/nas/rides/") || !prefs.AddSessionDir("/media/usb/rides") {
+		t.Fatal("AddSessionDir() = false, want true for new directories")
+	}
+
+	// A directory is added only once (regardless of a trailing separator)
+	if prefs.AddSessionDir("/mnt/nas/rides") {
+		t.Error("AddSessionDir() = true, want false for a directory already added")
+	}
+
+	if err := prefs.Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	prefs, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatalf("LoadPreferences() returned error: %v", err)
+	}
+
+	prefs.RemoveSessionDir("/mnt/nas/rides/")
+
+	if want := []string{"/media/usb/rides"}; !slices.Equal(prefs.SessionDirs, want) {
+		t.Errorf("SessionDirs = %v, want %v", prefs.SessionDirs, want)
+	}
+
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Tables written by the config template (the top-level table is "")
+var templateTables = []string{"", "app", "ble", "speed", "goal", "intervals", "laps", "race", "hooks", "video", "video.OSD", "video.audio"}
+
+// Patterns used to find the table headers and bare keys of a TOML file
+var (
+	tableHeaderPattern = regexp.MustCompile(`^\[\[?\s*([^\]]+?)\s*\]\]?`)
+	bareKeyPattern     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// extraContent holds the content of an existing config file that the config template doesn't
+// write (unknown keys, unknown tables and standalone comments), so that content survives a save
+type extraContent struct {
+	sections map[string][]string // Lines to add to each template table (comments, then keys)
+	tables   []string            // Unknown top-level tables, in file order
+}
+
+// readExtraContent reads the content of an existing config file that the config template doesn't
+// write, returning nil if the file doesn't exist (or can't be parsed, in which case there's
+// nothing that can be reliably preserved)
+func readExtraContent(path string) *extraContent {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	raw := map[string]any{}
+	if _, err := toml.Decode(string(content), &raw); err != nil {
+		return nil
+	}
+
+	md, err := toml.Decode(string(content), defaultConfig())
+	if err != nil {
+		return nil
+	}
+
+	extra := &extraContent{sections: map[string][]string{}}
+	extra.addComments(string(content))
+	extra.addKeys(md.Undecoded(), raw)
+
+	return extra
+}
+
+// addComments adds the standalone comments of a config file to the table they're found in (the
+// header comments at the top of the file are written by the config template)
+func (e *extraContent) addComments(content string) {
+
+	section := ""
+	inHeader := true
+
+	for line := range strings.Lines(content) {
+
+		trimmed := strings.TrimSpace(line)
+
+		if match := tableHeaderPattern.FindStringSubmatch(trimmed); match != nil {
+			section = match[1]
+			inHeader = false
+
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, "#") {
+
+			if trimmed != "" {
+				inHeader = false
+			}
+
+			continue
+		}
+
+		if !inHeader {
+			table := e.tableOf(section)
+			e.sections[table] = append(e.sections[table], indentFor(table)+trimmed)
+		}
+	}
+
+}
+
+// addKeys adds the unknown keys of a config file to the table they belong to: unknown top-level
+// tables are kept as tables, while other unknown keys (including unknown subtables of template
+// tables) are kept as key/value pairs of their template table
+func (e *extraContent) addKeys(undecoded []toml.Key, raw map[string]any) {
+
+	seen := map[string]bool{}
+
+	for _, key := range undecoded {
+
+		// Only the topmost unknown key of a subtree is kept (it holds the whole subtree)
+		known := 0
+		for known < len(key)-1 && e.isTemplateTable(key[:known+1]) {
+			known++
+		}
+
+		key = key[:known+1]
+		if seen[key.String()] {
+			continue
+		}
+
+		seen[key.String()] = true
+
+		value, ok := lookupRaw(raw, key)
+		if !ok {
+			continue
+		}
+
+		name := key[known]
+
+		// Unknown top-level tables are kept as tables
+		if table, isTable := value.(map[string]any); isTable && known == 0 {
+			e.addTable(name)
+
+			for _, k := range sortedKeys(table) {
+				e.sections[name] = append(e.sections[name], indentFor(name)+tomlKey(k)+" = "+tomlValue(table[k]))
+			}
+
+			continue
+		}
+
+		table := e.tableOf(strings.Join(key[:known], "."))
+		e.sections[table] = append(e.sections[table], indentFor(table)+tomlKey(name)+" = "+tomlValue(value))
+	}
+
+}
+
+// isTemplateTable returns true if a key names a table written by the config template
+func (e *extraContent) isTemplateTable(key toml.Key) bool {
+
+	name := strings.Join(key, ".")
+
+	return slices.ContainsFunc(templateTables, func(table string) bool {
+		return strings.EqualFold(table, name)
+	})
+}
+
+// addTable adds an unknown top-level table (once)
+func (e *extraContent) addTable(name string) {
+
+	if !slices.Contains(e.tables, name) {
+		e.tables = append(e.tables, name)
+	}
+
+}
+
+// tableOf returns the template table (or unknown top-level table) that holds a table
+func (e *extraContent) tableOf(name string) string {
+
+	for {
+
+		for _, table := range templateTables {
+
+			if strings.EqualFold(table, name) {
+				return table
+			}
+		}
+
+		if !strings.Contains(name, ".") {
+			e.addTable(name)
+
+			return name
+		}
+
+		name = name[:strings.LastIndex(name, ".")]
+	}
+
+}
+
+// merge adds the extra content to the content written by the config template, placing the extra
+// lines of each template table after the last key of the table, top-level keys before the first
+// table, and unknown top-level tables at the end of the file
+func (e *extraContent) merge(rendered []byte) []byte {
+
+	if e == nil {
+		return rendered
+	}
+
+	lines := strings.Split(strings.TrimRight(string(rendered), "\n"), "\n")
+	var out []string
+
+	section := ""
+
+	flush := func() {
+
+		extra := e.sections[section]
+		if len(extra) == 0 {
+			return
+		}
+
+		// Extra lines follow the last (non-blank) line of the section
+		end := len(out)
+		for end > 0 && strings.TrimSpace(out[end-1]) == "" {
+			end--
+		}
+
+		// Top-level keys are set apart from the header comments
+		if section == "" {
+			extra = append([]string{""}, extra...)
+		}
+
+		trailing := slices.Clone(out[end:])
+		out = append(append(out[:end], extra...), trailing...)
+
+	}
+
+	for _, line := range lines {
+
+		if match := tableHeaderPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			flush()
+			section = match[1]
+		}
+
+		out = append(out, line)
+	}
+
+	flush()
+
+	for _, table := range e.tables {
+		out = append(out, "", "["+tomlKey(table)+"]")
+		out = append(out, e.sections[table]...)
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// lookupRaw returns the value of a key in a decoded TOML document
+func lookupRaw(raw map[string]any, key toml.Key) (any, bool) {
+
+	var value any = raw
+
+	for _, part := range key {
+
+		table, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = table[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// tomlValue formats a decoded TOML value as a single-line TOML value, using inline tables for
+// (arrays of) tables
+func tomlValue(value any) string {
+
+	switch v := value.(type) {
+	case map[string]any:
+
+		pairs := make([]string, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			pairs = append(pairs, tomlKey(k)+" = "+tomlValue(v[k]))
+		}
+
+		return "{" + strings.Join(pairs, ", ") + "}"
+
+	case []map[string]any:
+
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlValue(item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+
+	case []any:
+
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlValue(item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	// Scalar values are formatted by the TOML encoder
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]any{"v": value}); err != nil {
+		return `""`
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(buf.String(), "v = "), "\n")
+}
+
+// tomlKey formats a TOML key, quoting keys that aren't bare keys
+func tomlKey(key string) string {
+
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+
+	return tomlValue(key)
+}
+
+// indentFor returns the indentation used by the config template for the keys of a table
+func indentFor(table string) string {
+
+	if table == "" {
+		return ""
+	}
+
+	return "  "
+}
+
+// sortedKeys returns the keys of a decoded TOML table in sorted order
+func sortedKeys(table map[string]any) []string {
+
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
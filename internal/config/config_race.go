@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// RaceConfig defines the (optional) race settings from the TOML config file: in a race against the
+// clock, the video plays at a fixed 1.0x while the rider is scored on how closely their speed
+// tracks the target speed (with a separate target for the easy intervals of the interval timer, if
+// set), and in a race against an outdoor ride of the same route (recorded as a GPX file), the
+// rider is shown how far ahead of or behind the outdoor ride they are
+type RaceConfig struct {
+	TargetSpeed     float64 `toml:"target_speed"`
+	EasyTargetSpeed float64 `toml:"easy_target_speed"`
+	OutdoorGPXFile  string  `toml:"outdoor_gpx_file"`
+}
+
+// validate checks RaceConfig for valid settings
+func (rc *RaceConfig) validate() error {
+
+	if err := validateConfigFields(rc.configValidationRanges()); err != nil {
+		return err
+	}
+
+	// An easy interval target only applies to a race against a target speed
+	if rc.EasyTargetSpeed > 0 && rc.TargetSpeed <= 0 {
+		return fmt.Errorf(errFormatRev, errRaceEasyTarget, fmt.Sprintf("easy_target_speed = %.1f", rc.EasyTargetSpeed))
+	}
+
+	return checkForOutdoorGPXFile(rc.OutdoorGPXFile)
+}
+
+// checkForOutdoorGPXFile checks that the outdoor ride GPX file exists, if one is provided
+func checkForOutdoorGPXFile(filename string) error {
+
+	if filename == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(filename); err != nil {
+		return fmt.Errorf(errFormat, errOutdoorGPXFile, err)
+	}
+
+	return nil
+}
+
+// configValidationRanges returns validation ranges for RaceConfig
+func (rc *RaceConfig) configValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"race.target_speed", rc.TargetSpeed, 0.0, maxSpeedZone, errRaceTargetSpeed},
+		{"race.easy_target_speed", rc.EasyTargetSpeed, 0.0, maxSpeedZone, errRaceTargetSpeed},
+	}
+}
+
+// Enabled returns true if the session is a race against the clock
+func (rc RaceConfig) Enabled() bool {
+	return rc.TargetSpeed > 0
+}
+
+// Target returns the target speed of the race, during an easy interval of the interval timer if
+// easy is set (the easy target defaults to the target speed)
+func (rc RaceConfig) Target(easy bool) float64 {
+
+	if easy && rc.EasyTargetSpeed > 0 {
+		return rc.EasyTargetSpeed
+	}
+
+	return rc.TargetSpeed
+}
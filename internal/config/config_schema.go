@@ -0,0 +1,203 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// JSON Schema dialect and title of the config file schema
+const (
+	schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+	schemaTitle   = "BLE Sync Cycle session configuration (TOML)"
+)
+
+// Lines of the config file template declaring a section (e.g., "[video.OSD]"), or a setting
+// followed by its comment
+var (
+	templateSection = regexp.MustCompile(`^\[(.+)\]\s*$`)
+	templateSetting = regexp.MustCompile(`^\s*(\w+) = .*\}\}#\s*(.*?)\s*$`)
+)
+
+// validValues holds the valid values of the settings with a fixed set of values, keyed by the TOML
+// key of the setting qualified by its section
+var validValues = map[string]map[string]bool{
+	"app.logging_level":       validLogLevels,
+	"speed.speed_units":       validSpeedUnits,
+	"video.media_player":      validPlayer,
+	"video.hardware_decoding": validHWDec,
+	"video.focus_mode":        validFocusMode,
+	"video.OSD.align_x":       validAlignX,
+	"video.OSD.align_y":       validAlignY,
+	"goal.type":               validGoalTypes,
+}
+
+// schemaProperty is a (partial) JSON Schema describing the config file, or one of its sections
+// or settings
+type schemaProperty struct {
+	Schema      string                     `json:"$schema,omitempty"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Type        string                     `json:"type"`
+	Enum        []string                   `json:"enum,omitempty"`
+	Minimum     any                        `json:"minimum,omitempty"`
+	Maximum     any                        `json:"maximum,omitempty"`
+	Default     any                        `json:"default,omitempty"`
+	Items       *schemaProperty            `json:"items,omitempty"`
+	Properties  map[string]*schemaProperty `json:"properties,omitempty"`
+}
+
+// WriteSchema writes a JSON Schema of the config file, generated from the config structure, its
+// validation rules (ranges and valid values), the defaults of new sessions, and the comments of
+// the config file template, for use with editor autocompletion and third-party config generators
+func WriteSchema(w io.Writer) error {
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(configSchema()); err != nil {
+		return fmt.Errorf(errFormat, "failed to write config schema", err)
+	}
+
+	return nil
+}
+
+// configSchema returns the JSON Schema of the config file
+func configSchema() *schemaProperty {
+
+	defaults := NewDefault()
+
+	ranges := make(map[string]validationRange)
+	for _, r := range defaults.validationRanges() {
+		ranges[r.key] = r
+	}
+
+	schema := schemaOf(reflect.ValueOf(defaults).Elem(), "", ranges, templateComments())
+	schema.Schema = schemaDialect
+	schema.Title = schemaTitle
+
+	return schema
+}
+
+// validationRanges returns the validation ranges of every section of the config
+func (c *Config) validationRanges() []validationRange {
+
+	var ranges []validationRange
+
+	for _, sectionRanges := range []*[]validationRange{
+		c.App.configValidationRanges(),
+		c.BLE.configValidationRanges(),
+		c.Speed.configValidationRanges(),
+		c.Video.configValidationRanges(),
+		c.Video.OnScreenDisplay.nightModeValidationRanges(),
+		c.Goal.configValidationRanges(),
+		c.Intervals.configValidationRanges(),
+		c.Laps.configValidationRanges(),
+		c.Race.configValidationRanges(),
+		c.Hooks.configValidationRanges(),
+	} {
+		ranges = append(ranges, *sectionRanges...)
+	}
+
+	return ranges
+}
+
+// schemaOf returns the schema of a config section or setting (given its qualified TOML key),
+// using its value as the default value of a setting
+func schemaOf(value reflect.Value, key string, ranges map[string]validationRange, comments map[string]string) *schemaProperty {
+
+	schema := &schemaProperty{
+		Type:        schemaType(value.Type()),
+		Description: comments[key],
+	}
+
+	if value.Kind() == reflect.Struct {
+		schema.Properties = make(map[string]*schemaProperty)
+
+		for i := range value.NumField() {
+
+			name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("toml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			schema.Properties[name] = schemaOf(value.Field(i), strings.TrimPrefix(key+"."+name, "."), ranges, comments)
+		}
+
+		return schema
+	}
+
+	if r, ok := ranges[key]; ok {
+		schema.Minimum = r.min
+		schema.Maximum = r.max
+	}
+
+	if values, ok := validValues[key]; ok {
+		schema.Enum = slices.Sorted(maps.Keys(values))
+	}
+
+	if value.Kind() == reflect.Slice {
+		schema.Items = &schemaProperty{Type: schemaType(value.Type().Elem())}
+
+		// An empty list (rather than null) is the default of a list without a default value
+		if value.IsNil() {
+			value = reflect.MakeSlice(value.Type(), 0, 0)
+		}
+	}
+
+	// The placeholders of the BLE sensor BD_ADDR and the video file path aren't default values
+	if defaultValue := value.Interface(); defaultValue != PlaceholderBDAddr && defaultValue != placeholderVideo {
+		schema.Default = defaultValue
+	}
+
+	return schema
+}
+
+// schemaType returns the JSON Schema type of a config section or setting type
+func schemaType(t reflect.Type) string {
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return "object"
+	case reflect.Slice:
+		return "array"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int:
+		return "integer"
+	case reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+
+}
+
+// templateComments returns the comments describing each setting in the config file template,
+// keyed by the TOML key of the setting qualified by its section
+func templateComments() map[string]string {
+
+	comments := make(map[string]string)
+	section := ""
+
+	for line := range strings.SplitSeq(ConfigTemplate, "\n") {
+
+		if match := templateSection.FindStringSubmatch(line); match != nil {
+			section = match[1]
+
+			continue
+		}
+
+		if match := templateSetting.FindStringSubmatch(line); match != nil {
+			comments[section+"."+match[1]] = match[2]
+		}
+	}
+
+	return comments
+}
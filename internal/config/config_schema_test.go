@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestWriteSchema tests the ranges, valid values and defaults of settings in the config schema
+func TestWriteSchema(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if err := WriteSchema(&buf); err != nil {
+		t.Fatalf("WriteSchema() error = %v", err)
+	}
+
+	schema := &schemaProperty{}
+	if err := json.Unmarshal(buf.Bytes(), schema); err != nil {
+		t.Fatalf("WriteSchema() wrote invalid JSON: %v", err)
+	}
+
+	if schema.Schema != schemaDialect {
+		t.Errorf("WriteSchema() $schema = %q, want %q", schema.Schema, schemaDialect)
+	}
+
+	// Define test cases
+	tests := []struct {
+		key         string
+		wantType    string
+		wantMin     any
+		wantMax     any
+		wantEnum    []string
+		wantDefault any
+	}{
+		{"speed.smoothing_window", "integer", 1.0, 25.0, nil, 5.0},
+		{"video.update_interval_secs", "number", 0.1, 3.0, nil, 0.25},
+		{"video.OSD.night_brightness", "integer", 10.0, 100.0, nil, 40.0},
+		{"app.backup_count", "integer", 0.0, 10.0, nil, 1.0},
+		{"video.OSD.align_x", "string", nil, nil, []string{"center", "left", "right"}, "left"},
+		{"goal.type", "string", nil, nil, []string{"", "calories", "distance", "time"}, ""},
+		{"video.media_player_args", "array", nil, nil, nil, []any{}},
+		{"video.audio.mute", "boolean", nil, nil, nil, false},
+		{"ble.sensor_bd_addr", "string", nil, nil, nil, nil},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.key, func(t *testing.T) {
+
+			got := schemaSetting(schema, tt.key)
+			if got == nil {
+				t.Fatalf("setting %q missing from schema", tt.key)
+			}
+
+			if got.Type != tt.wantType || got.Minimum != tt.wantMin || got.Maximum != tt.wantMax {
+				t.Errorf("schema of %q = (%s, %v-%v), want (%s, %v-%v)", tt.key, got.Type, got.Minimum, got.Maximum, tt.wantType, tt.wantMin, tt.wantMax)
+			}
+
+			if !reflect.DeepEqual(got.Enum, tt.wantEnum) || !reflect.DeepEqual(got.Default, tt.wantDefault) {
+				t.Errorf("schema of %q = (enum %v, default %v), want (enum %v, default %v)", tt.key, got.Enum, got.Default, tt.wantEnum, tt.wantDefault)
+			}
+
+		})
+	}
+
+}
+
+// TestConfigSchemaCoverage tests that the config schema describes every setting of the config file
+// template, and gives a range for every numeric setting
+func TestConfigSchemaCoverage(t *testing.T) {
+
+	schema := configSchema()
+
+	for key := range templateComments() {
+
+		setting := schemaSetting(schema, key)
+
+		switch {
+		case setting == nil:
+			t.Errorf("setting %q missing from schema", key)
+		case setting.Description == "":
+			t.Errorf("setting %q has no description", key)
+		case (setting.Type == "integer" || setting.Type == "number") && (setting.Minimum == nil || setting.Maximum == nil):
+			t.Errorf("numeric setting %q has no range", key)
+		}
+	}
+
+}
+
+// schemaSetting returns the schema of a setting given its qualified TOML key (or nil if missing)
+func schemaSetting(schema *schemaProperty, key string) *schemaProperty {
+
+	for name := range strings.SplitSeq(key, ".") {
+
+		if schema = schema.Properties[name]; schema == nil {
+			return nil
+		}
+	}
+
+	return schema
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Sensor registry settings
+const (
+	ApplicationID      = "com.github.richbl.ble-sync-cycle"
+	SensorRegistryFile = "sensors.toml"
+	sensorRegistryDir  = "registry" // Kept apart from the session files in the config directory
+
+	SensorTypeSpeed   = "speed"
+	SensorTypeCadence = "cadence"
+)
+
+// SensorProfile is a known BLE sensor, as recorded in the sensor registry
+type SensorProfile struct {
+	Name         string    `toml:"name"`
+	BDAddr       string    `toml:"bd_addr"`
+	Type         string    `toml:"type"`
+	BatteryLevel int       `toml:"last_battery_level"`
+	LastSeen     time.Time `toml:"last_seen"`
+}
+
+// Sensor name within the QR code of a sensor (e.g., "name=Speed 2" or {"name": "Speed 2"})
+var sensorQRName = regexp.MustCompile(`(?i)\bname"?\s*[:=]\s*"?([^"\r\n,;}]+)`)
+
+// SensorRegistry is the persistent list of known BLE sensors, allowing sensors to be shown by
+// nickname rather than by BD_ADDR
+type SensorRegistry struct {
+	Sensors []SensorProfile `toml:"sensor"`
+}
+
+// sensorRegistryMu serializes sensor registry updates (e.g., by concurrently running sessions)
+var sensorRegistryMu sync.Mutex
+
+// SensorRegistryPath returns the path of the sensor registry in the user config directory
+func SensorRegistryPath() (string, error) {
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, sensorRegistryDir, SensorRegistryFile), nil
+}
+
+// LoadSensorRegistry loads the sensor registry file, returning an empty registry if the file
+// does not yet exist
+func LoadSensorRegistry(path string) (*SensorRegistry, error) {
+
+	registry := &SensorRegistry{}
+
+	if _, err := toml.DecodeFile(path, registry); err != nil {
+
+		if errors.Is(err, os.ErrNotExist) {
+			return registry, nil
+		}
+
+		return nil, fmt.Errorf(errFormat, "failed to load sensor registry", err)
+	}
+
+	return registry, nil
+}
+
+// UpdateSensorRegistry loads the sensor registry file, applies the update and saves the result,
+// serializing concurrent updates
+func UpdateSensorRegistry(path string, update func(r *SensorRegistry)) error {
+
+	sensorRegistryMu.Lock()
+	defer sensorRegistryMu.Unlock()
+
+	registry, err := LoadSensorRegistry(path)
+	if err != nil {
+		return err
+	}
+
+	update(registry)
+
+	return registry.Save(path)
+}
+
+// Save writes the sensor registry file, creating its directory as needed
+func (r *SensorRegistry) Save(path string) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(errFormat, "failed to create sensor registry directory", err)
+	}
+
+	err := writeFileAtomic(path, 0644, 0, func(w io.Writer) error {
+		return toml.NewEncoder(w).Encode(r)
+	})
+
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to write sensor registry", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the profile of the sensor with the given BD_ADDR
+func (r *SensorRegistry) Lookup(bdAddr string) (SensorProfile, bool) {
+
+	for _, profile := range r.Sensors {
+
+		if strings.EqualFold(profile.BDAddr, strings.TrimSpace(bdAddr)) {
+			return profile, true
+		}
+	}
+
+	return SensorProfile{}, false
+}
+
+// Record adds a sensor to the registry, or updates the profile of a known sensor: fields left
+// empty in the profile keep their registered values, so a sensor nickname is never lost
+func (r *SensorRegistry) Record(profile SensorProfile) {
+
+	profile.BDAddr = strings.ToUpper(strings.TrimSpace(profile.BDAddr))
+
+	for i := range r.Sensors {
+
+		known := &r.Sensors[i]
+		if !strings.EqualFold(known.BDAddr, profile.BDAddr) {
+			continue
+		}
+
+		if profile.Name != "" {
+			known.Name = profile.Name
+		}
+
+		if profile.Type != "" {
+			known.Type = profile.Type
+		}
+
+		if profile.BatteryLevel > 0 {
+			known.BatteryLevel = profile.BatteryLevel
+		}
+
+		if profile.LastSeen.After(known.LastSeen) {
+			known.LastSeen = profile.LastSeen
+		}
+
+		return
+	}
+
+	r.Sensors = append(r.Sensors, profile)
+}
+
+// Rename sets the nickname of a sensor, adding the sensor to the registry if not yet known
+func (r *SensorRegistry) Rename(bdAddr, name string) {
+
+	bdAddr = strings.ToUpper(strings.TrimSpace(bdAddr))
+
+	for i := range r.Sensors {
+
+		if strings.EqualFold(r.Sensors[i].BDAddr, bdAddr) {
+			r.Sensors[i].Name = strings.TrimSpace(name)
+
+			return
+		}
+	}
+
+	r.Sensors = append(r.Sensors, SensorProfile{Name: strings.TrimSpace(name), BDAddr: bdAddr})
+}
+
+// DisplayName returns the nickname of the sensor with the given BD_ADDR, or the BD_ADDR itself
+// if the sensor has no nickname
+func (r *SensorRegistry) DisplayName(bdAddr string) string {
+
+	if profile, ok := r.Lookup(bdAddr); ok && profile.Name != "" {
+		return profile.Name
+	}
+
+	return bdAddr
+}
+
+// String returns the nickname and BD_ADDR of the sensor (e.g., "Garmin Speed 2 (rear wheel)
+// [F1:42:D8:DE:35:16]")
+func (p SensorProfile) String() string {
+
+	if p.Name == "" {
+		return p.BDAddr
+	}
+
+	return fmt.Sprintf("%s [%s]", p.Name, p.BDAddr)
+}
+
+// ParseSensorQR returns the details of a sensor read from its QR code (e.g., as printed in the
+// sensor manual): the BD_ADDR, along with the sensor name if the QR code gives one
+func ParseSensorQR(payload string) (SensorProfile, error) {
+
+	addr, ok := FindBDAddr(payload)
+	if !ok {
+		return SensorProfile{}, errNoBDAddrInQR
+	}
+
+	profile := SensorProfile{BDAddr: addr}
+
+	if match := sensorQRName.FindStringSubmatch(payload); match != nil {
+		profile.Name = strings.TrimSpace(match[1])
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSensorRegistry tests recording, renaming and reloading sensors in the sensor registry
+func TestSensorRegistry(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, SensorRegistryFile)
+
+	// A missing registry file is an empty registry
+	registry, err := LoadSensorRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadSensorRegistry() returned error: %v", err)
+	}
+
+	if len(registry.Sensors) != 0 {
+		t.Fatalf("LoadSensorRegistry() returned %d sensors, want 0", len(registry.Sensors))
+	}
+
+	lastSeen := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+
+	registry.Record(SensorProfile{Name: "Speed 2", BDAddr: "f1:42:d8:de:35:16", Type: SensorTypeSpeed, BatteryLevel: 80, LastSeen: lastSeen})
+	registry.Rename("F1:42:D8:DE:35:16", "Garmin Speed 2 (rear wheel)")
+
+	// Recording a known sensor keeps its nickname
+	registry.Record(SensorProfile{Name: "", BDAddr: "F1:42:D8:DE:35:16", BatteryLevel: 75, LastSeen: lastSeen.Add(time.Hour)})
+
+	if err := registry.Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	registry, err = LoadSensorRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadSensorRegistry() returned error: %v", err)
+	}
+
+	if len(registry.Sensors) != 1 {
+		t.Fatalf("LoadSensorRegistry() returned %d sensors, want 1", len(registry.Sensors))
+	}
+
+	profile, ok := registry.Lookup("f1:42:d8:de:35:16")
+	if !ok {
+		t.Fatal("Lookup() did not find the recorded sensor")
+	}
+
+	want := SensorProfile{
+		Name:         "Garmin Speed 2 (rear wheel)",
+		BDAddr:       "F1:42:D8:DE:35:16",
+		Type:         SensorTypeSpeed,
+		BatteryLevel: 75,
+		LastSeen:     lastSeen.Add(time.Hour),
+	}
+
+	if profile.Name != want.Name || profile.BDAddr != want.BDAddr || profile.Type != want.Type ||
+		profile.BatteryLevel != want.BatteryLevel || !profile.LastSeen.Equal(want.LastSeen) {
+		t.Errorf("Lookup() = %+v, want %+v", profile, want)
+	}
+
+	// Define test cases
+	tests := []struct {
+		bdAddr string
+		want   string
+	}{
+		{"F1:42:D8:DE:35:16", "Garmin Speed 2 (rear wheel)"},
+		{"AA:BB:CC:DD:EE:FF", "AA:BB:CC:DD:EE:FF"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.bdAddr, func(t *testing.T) {
+
+			if got := registry.DisplayName(tt.bdAddr); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestParseSensorQR tests reading the BD_ADDR and name of a sensor from the payload of its QR code
+func TestParseSensorQR(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		payload   string
+		want      SensorProfile
+		wantError bool
+	}{
+		{"BD_ADDR only", "fa461d77c8e1", SensorProfile{BDAddr: "FA:46:1D:77:C8:E1"}, false},
+		{"key-value pairs", "name=Speed 2;mac=FA-46-1D-77-C8-E1", SensorProfile{Name: "Speed 2", BDAddr: "FA:46:1D:77:C8:E1"}, false},
+		{"JSON", `{"name": "Garmin Speed", "addr": "fa:46:1d:77:c8:e1"}`, SensorProfile{Name: "Garmin Speed", BDAddr: "FA:46:1D:77:C8:E1"}, false},
+		{"bluetoothctl output", "Device FA:46:1D:77:C8:E1 SPD-BLE0123", SensorProfile{BDAddr: "FA:46:1D:77:C8:E1"}, false},
+		{"no BD_ADDR", "https://example.com/manual", SensorProfile{}, true},
+		{"broadcast BD_ADDR", "FF:FF:FF:FF:FF:FF", SensorProfile{}, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := ParseSensorQR(tt.payload)
+			if (err != nil) != tt.wantError || got != tt.want {
+				t.Errorf("ParseSensorQR(%q) = (%+v, %v), want (%+v, error %v)", tt.payload, got, err, tt.want, tt.wantError)
+			}
+
+		})
+	}
+
+}
@@ -49,7 +49,7 @@ func LoadSessionMetadata(filePath string) (*SessionMetadata, error) {
 // loadAndValidateConfig loads a config file without applying command-line flag overrides
 func loadAndValidateConfig(filePath string) (*Config, error) {
 
-	cfg := &Config{}
+	cfg := defaultConfig()
 
 	// Decode the TOML file
 	_, err := toml.DecodeFile(filePath, cfg)
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SessionCache caches the metadata of valid session files by modification time and size, so that
+// rescanning the session directory only loads (and validates) new and changed session files
+type SessionCache struct {
+	mu      sync.Mutex
+	entries map[string]sessionCacheEntry
+}
+
+// sessionCacheEntry holds the cached metadata of a session file
+type sessionCacheEntry struct {
+	modTime  time.Time
+	size     int64
+	metadata SessionMetadata
+}
+
+// NewSessionCache creates an empty session metadata cache
+func NewSessionCache() *SessionCache {
+
+	return &SessionCache{
+		entries: make(map[string]sessionCacheEntry),
+	}
+}
+
+// ScanSessions returns the metadata of every session file (*.toml) in a directory (in file name
+// order), loading the session files that aren't cached in parallel. Session files that fail to
+// load are returned with IsValid false and the reason in ErrorMsg, and are never cached (so they're
+// checked again by the next scan, e.g., once a missing video file is restored)
+func (c *SessionCache) ScanSessions(dir string) ([]SessionMetadata, error) {
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to scan for session files", err)
+	}
+
+	results := make([]SessionMetadata, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(runtime.NumCPU(), len(files)) {
+
+		wg.Go(func() {
+
+			for i := range jobs {
+				results[i] = c.load(files[i])
+			}
+
+		})
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	c.prune(dir, files)
+
+	return results, nil
+}
+
+// load returns the metadata of a session file, from the cache if the file is unchanged since it
+// was cached
+func (c *SessionCache) load(filePath string) SessionMetadata {
+
+	info, statErr := os.Stat(filePath)
+
+	if statErr == nil {
+		c.mu.Lock()
+		entry, ok := c.entries[filePath]
+		c.mu.Unlock()
+
+		if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+			return entry.metadata
+		}
+	}
+
+	metadata, err := LoadSessionMetadata(filePath)
+	if err != nil {
+		return SessionMetadata{
+			FilePath: filePath,
+			ErrorMsg: err.Error(),
+		}
+	}
+
+	if statErr == nil {
+		c.mu.Lock()
+		c.entries[filePath] = sessionCacheEntry{modTime: info.ModTime(), size: info.Size(), metadata: *metadata}
+		c.mu.Unlock()
+	}
+
+	return *metadata
+}
+
+// prune removes the cached metadata of session files no longer found in a session directory
+func (c *SessionCache) prune(dir string, files []string) {
+
+	found := make(map[string]bool, len(files))
+	for _, filePath := range files {
+		found[filePath] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for filePath := range c.entries {
+
+		if filepath.Dir(filePath) == filepath.Clean(dir) && !found[filePath] {
+			delete(c.entries, filePath)
+		}
+	}
+
+}
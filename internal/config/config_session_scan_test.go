@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestScanSessions tests scanning a session directory, with the metadata of unchanged session
+// files taken from the cache
+func TestScanSessions(t *testing.T) {
+
+	dir := t.TempDir()
+
+	for _, title := range []string{"Ride A", "Ride B", "Ride C"} {
+		saveTestSession(t, filepath.Join(dir, title[len(title)-1:]+".toml"), title)
+	}
+
+	createTestConfigFile(t, filepath.Join(dir, "invalid.toml"), "[app\n")
+
+	cache := NewSessionCache()
+
+	sessions, err := cache.ScanSessions(dir)
+	if err != nil {
+		t.Fatalf("ScanSessions() error = %v", err)
+	}
+
+	// Define test cases (in file name order)
+	tests := []struct {
+		file      string
+		wantTitle string
+		wantValid bool
+	}{
+		{"A.toml", "Ride A", true},
+		{"B.toml", "Ride B", true},
+		{"C.toml", "Ride C", true},
+		{"invalid.toml", "", false},
+	}
+
+	if len(sessions) != len(tests) {
+		t.Fatalf("ScanSessions() found %d session files, want %d", len(sessions), len(tests))
+	}
+
+	// Run tests
+	for i, tt := range tests {
+
+		t.Run(tt.file, func(t *testing.T) {
+
+			got := sessions[i]
+			if filepath.Base(got.FilePath) != tt.file || got.Title != tt.wantTitle || got.IsValid != tt.wantValid {
+				t.Errorf("ScanSessions() = (%s, %q, %v), want (%s, %q, %v)", filepath.Base(got.FilePath), got.Title, got.IsValid, tt.file, tt.wantTitle, tt.wantValid)
+			}
+
+			if !tt.wantValid && got.ErrorMsg == "" {
+				t.Error("ScanSessions() gave no reason for an invalid session file")
+			}
+
+		})
+	}
+
+	// Rewrite a session file without changing its size or modification time (so it's cached)
+	pathA := filepath.Join(dir, "A.toml")
+	info, _ := os.Stat(pathA)
+
+	saveTestSession(t, pathA, "Ride Z")
+
+	if err := os.Chtimes(pathA, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("failed to restore modification time: %v", err)
+	}
+
+	if sessions, _ := cache.ScanSessions(dir); sessions[0].Title != "Ride A" {
+		t.Errorf("ScanSessions() title = %q, want cached title %q", sessions[0].Title, "Ride A")
+	}
+
+	// A changed modification time reloads the session file
+	if err := os.Chtimes(pathA, time.Now(), info.ModTime().Add(time.Second)); err != nil {
+		t.Fatalf("failed to change modification time: %v", err)
+	}
+
+	if sessions, _ := cache.ScanSessions(dir); sessions[0].Title != "Ride Z" {
+		t.Errorf("ScanSessions() title = %q, want reloaded title %q", sessions[0].Title, "Ride Z")
+	}
+
+	// Removed session files are dropped from the cache
+	if err := os.Remove(pathA); err != nil {
+		t.Fatalf("failed to remove session file: %v", err)
+	}
+
+	if sessions, _ := cache.ScanSessions(dir); len(sessions) != 3 || len(cache.entries) != 2 {
+		t.Errorf("ScanSessions() = %d sessions (%d cached), want 3 (2 cached)", len(sessions), len(cache.entries))
+	}
+
+	// Scanning another session directory keeps the cached metadata of this one
+	if _, err := cache.ScanSessions(t.TempDir()); err != nil || len(cache.entries) != 2 {
+		t.Errorf("ScanSessions() of another directory left %d cached (error %v), want 2", len(cache.entries), err)
+	}
+
+}
+
+// saveTestSession saves a valid session file with the given session title
+func saveTestSession(t *testing.T, path, title string) {
+
+	t.Helper()
+
+	videoPath, err := filepath.Abs(testVideo)
+	if err != nil {
+		t.Fatalf("failed to locate test video: %v", err)
+	}
+
+	cfg := NewDefault()
+	cfg.App.SessionTitle = title
+	cfg.BLE.SensorBDAddr = "FA:46:1D:77:C8:E1"
+	cfg.Video.FilePath = videoPath
+
+	if err := Save(path, cfg, "test"); err != nil {
+		t.Fatalf("failed to save test session: %v", err)
+	}
+
+}
@@ -2,37 +2,156 @@ package config
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Speed zone settings
+const (
+	maxSpeedZone = 1000.0
 )
 
+// SpeedZoneColors defines the colors (as "#RRGGBB") of the speed zones, from the slowest zone
+// (blue) to the fastest zone (red)
+var SpeedZoneColors = []string{"#3584e4", "#33d17a", "#f6d32d", "#e01b24"}
+
+// Valid speed units
+var validSpeedUnits = map[string]bool{
+	SpeedUnitsKMH: true,
+	SpeedUnitsMPH: true,
+	SpeedUnitsMPS: true,
+	SpeedUnitsRPM: true,
+}
+
 // SpeedConfig defines speed calculation and measurement settings from the TOML config file
 type SpeedConfig struct {
-	SpeedUnits           string  `toml:"speed_units"`
-	WheelCircumferenceMM int     `toml:"wheel_circumference_mm"`
-	SpeedThreshold       float64 `toml:"speed_threshold"`
-	SmoothingWindow      int     `toml:"smoothing_window"`
+	SpeedUnits           string    `toml:"speed_units"`
+	WheelCircumferenceMM int       `toml:"wheel_circumference_mm"`
+	SpeedThreshold       float64   `toml:"speed_threshold"`
+	SmoothingWindow      int       `toml:"smoothing_window"`
+	CadenceSpeedPerRPM   float64   `toml:"cadence_speed_per_rpm"`
+	CoastDecaySecs       float64   `toml:"coast_decay_secs"`
+	SpeedZones           []float64 `toml:"speed_zones"`
+	ReplayFile           string    `toml:"-"` // Recorded session to replay (set from the command-line)
+	ReplayRate           float64   `toml:"-"`
 }
 
 // validate checks SpeedConfig for valid settings
 func (sc *SpeedConfig) validate() error {
 
-	validSpeedUnits := map[string]bool{
-		SpeedUnitsKMH: true,
-		SpeedUnitsMPH: true,
-	}
-
 	if !validSpeedUnits[sc.SpeedUnits] {
 		return fmt.Errorf(errFormatRev, errInvalidSpeedUnits, sc.SpeedUnits)
 	}
 
-	return validateConfigFields(sc.configValidationRanges())
+	if err := validateConfigFields(sc.configValidationRanges()); err != nil {
+		return err
+	}
+
+	return sc.validateSpeedZones()
+}
+
+// validateSpeedZones checks that the speed zones are either unset, or hold the (ascending) speeds
+// that separate each of the speed zone colors
+func (sc *SpeedConfig) validateSpeedZones() error {
+
+	if len(sc.SpeedZones) == 0 {
+		return nil
+	}
+
+	if len(sc.SpeedZones) != len(SpeedZoneColors)-1 {
+		return fmt.Errorf(errFormatRev, errSpeedZones, fmt.Sprintf("%d speeds given", len(sc.SpeedZones)))
+	}
+
+	for i, zone := range sc.SpeedZones {
+
+		if zone <= 0 || zone > maxSpeedZone || (i > 0 && zone <= sc.SpeedZones[i-1]) {
+			return fmt.Errorf(errFormatRev, errSpeedZones, sc.SpeedZones)
+		}
+	}
+
+	return nil
+}
+
+// SpeedZone returns the speed zone of a speed (1 = slowest zone, up to the number of speed zone
+// colors), or 0 if no speed zones are configured
+func (sc *SpeedConfig) SpeedZone(speed float64) int {
+
+	if len(sc.SpeedZones) == 0 {
+		return 0
+	}
+
+	zone := 1
+	for _, limit := range sc.SpeedZones {
+
+		if speed >= limit {
+			zone++
+		}
+	}
+
+	return min(zone, len(SpeedZoneColors))
 }
 
 // configValidationRanges returns validation ranges for SpeedConfig
 func (sc *SpeedConfig) configValidationRanges() *[]validationRange {
 
 	return &[]validationRange{
-		{sc.SmoothingWindow, 1, 25, errSmoothingWindow},
-		{sc.SpeedThreshold, 0.0, 10.0, errSpeedThreshold},
-		{sc.WheelCircumferenceMM, 50, 3000, errWheelCircumference},
+		{"speed.smoothing_window", sc.SmoothingWindow, 1, 25, errSmoothingWindow},
+		{"speed.speed_threshold", sc.SpeedThreshold, 0.0, 10.0, errSpeedThreshold},
+		{"speed.wheel_circumference_mm", sc.WheelCircumferenceMM, 50, 3000, errWheelCircumference},
+		{"speed.cadence_speed_per_rpm", sc.CadenceSpeedPerRPM, 0.0, 10.0, errCadenceSpeed},
+		{"speed.coast_decay_secs", sc.CoastDecaySecs, 0.0, 60.0, errCoastDecay},
+	}
+}
+
+// Converter returns the converter for speeds in the configured speed units (wheel revolutions per
+// minute depend on the wheel circumference)
+func (sc *SpeedConfig) Converter() units.Converter {
+	return units.NewConverter(sc.SpeedUnits, sc.WheelCircumferenceMM)
+}
+
+// CoastDecay returns the time constant of the coasting model (0 when coasting is disabled)
+func (sc *SpeedConfig) CoastDecay() time.Duration {
+	return time.Duration(sc.CoastDecaySecs * float64(time.Second))
+}
+
+// ParseSpeedZones parses speed zones written as comma-separated speeds (e.g., "10, 15, 20"),
+// returning an error if the speeds aren't valid speed zones (an empty string means no zones)
+func ParseSpeedZones(text string) ([]float64, error) {
+
+	var zones []float64
+
+	if strings.TrimSpace(text) == "" {
+		return zones, nil
 	}
+
+	for field := range strings.SplitSeq(text, ",") {
+
+		zone, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf(errFormatRev, errSpeedZones, text)
+		}
+
+		zones = append(zones, zone)
+	}
+
+	sc := SpeedConfig{SpeedZones: zones}
+	if err := sc.validateSpeedZones(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// FormatSpeedZones formats speed zones as comma-separated speeds (e.g., "10.0, 15.0, 20.0")
+func FormatSpeedZones(zones []float64) string {
+
+	formatted := make([]string, len(zones))
+	for i, zone := range zones {
+		formatted[i] = strconv.FormatFloat(zone, 'f', 1, 64)
+	}
+
+	return strings.Join(formatted, ", ")
 }
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// UsageStatsFile is the name of the usage statistics file in the registry directory
+const UsageStatsFile = "stats.toml"
+
+// UsageStats holds the lifetime totals of all completed BSC sessions (unlike the session history,
+// no records are ever dropped). Usage statistics are stored locally and never sent anywhere
+type UsageStats struct {
+	Since      time.Time      `toml:"since"` // Start of the first session counted
+	Sessions   int            `toml:"sessions"`
+	RideSecs   int64          `toml:"ride_time_secs"`
+	DistanceKM float64        `toml:"distance_km"`
+	Calories   float64        `toml:"calories"`
+	Videos     map[string]int `toml:"videos,omitempty"` // Sessions ridden with each video file
+}
+
+// VideoCount is the number of sessions ridden with a video file
+type VideoCount struct {
+	Video    string
+	Sessions int
+}
+
+// usageStatsMu serializes usage statistics updates (e.g., by concurrently running sessions)
+var usageStatsMu sync.Mutex
+
+// UsageStatsPath returns the path of the usage statistics in the user config directory
+func UsageStatsPath() (string, error) {
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, sensorRegistryDir, UsageStatsFile), nil
+}
+
+// LoadUsageStats loads the usage statistics file, returning empty statistics if the file does
+// not yet exist
+func LoadUsageStats(path string) (*UsageStats, error) {
+
+	stats := &UsageStats{}
+
+	if _, err := toml.DecodeFile(path, stats); err != nil {
+
+		if errors.Is(err, os.ErrNotExist) {
+			return stats, nil
+		}
+
+		return nil, fmt.Errorf(errFormat, "failed to load usage statistics", err)
+	}
+
+	return stats, nil
+}
+
+// AddUsageStats adds a session record to the usage statistics file, serializing concurrent
+// updates
+func AddUsageStats(path string, record SessionRecord) error {
+
+	usageStatsMu.Lock()
+	defer usageStatsMu.Unlock()
+
+	stats, err := LoadUsageStats(path)
+	if err != nil {
+		return err
+	}
+
+	stats.Add(record)
+
+	return stats.Save(path)
+}
+
+// Add counts a session record in the usage statistics
+func (s *UsageStats) Add(record SessionRecord) {
+
+	if s.Since.IsZero() || (!record.Started.IsZero() && record.Started.Before(s.Since)) {
+		s.Since = record.Started
+	}
+
+	s.Sessions++
+	s.RideSecs += int64(record.RideSecs)
+	s.DistanceKM += record.DistanceKM
+	s.Calories += record.Calories
+
+	if record.Video == "" {
+		return
+	}
+
+	if s.Videos == nil {
+		s.Videos = make(map[string]int)
+	}
+
+	s.Videos[record.Video]++
+
+}
+
+// TopVideos returns up to n video files with the most sessions, most ridden first (ties are
+// ordered by name)
+func (s *UsageStats) TopVideos(n int) []VideoCount {
+
+	videos := make([]VideoCount, 0, len(s.Videos))
+	for video, sessions := range s.Videos {
+		videos = append(videos, VideoCount{Video: video, Sessions: sessions})
+	}
+
+	slices.SortFunc(videos, func(a, b VideoCount) int {
+		return cmp.Or(cmp.Compare(b.Sessions, a.Sessions), cmp.Compare(a.Video, b.Video))
+	})
+
+	return videos[:min(n, len(videos))]
+}
+
+// Save writes the usage statistics file, creating its directory as needed
+func (s *UsageStats) Save(path string) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(errFormat, "failed to create usage statistics directory", err)
+	}
+
+	err := writeFileAtomic(path, 0644, 0, func(w io.Writer) error {
+		return toml.NewEncoder(w).Encode(s)
+	})
+
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to write usage statistics", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestUsageStats tests adding session records to (and reloading) the usage statistics
+func TestUsageStats(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), sensorRegistryDir, UsageStatsFile)
+
+	// A missing statistics file is empty statistics
+	stats, err := LoadUsageStats(path)
+	if err != nil {
+		t.Fatalf("LoadUsageStats() returned error: %v", err)
+	}
+
+	if stats.Sessions != 0 || len(stats.TopVideos(3)) != 0 {
+		t.Fatalf("LoadUsageStats() = %+v, want empty statistics", stats)
+	}
+
+	started := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+
+	records := []SessionRecord{
+		{Video: "alps.mp4", Started: started.Add(24 * time.Hour), RideSecs: 3600, DistanceKM: 25.5, Calories: 600},
+		{Video: "coast.mp4", Started: started, RideSecs: 1800, DistanceKM: 12, Calories: 300},
+		{Video: "alps.mp4", Started: started.Add(48 * time.Hour), RideSecs: 600, DistanceKM: 4.5, Calories: 100},
+		{Started: started.Add(72 * time.Hour), RideSecs: 60},
+	}
+
+	for _, record := range records {
+
+		if err := AddUsageStats(path, record); err != nil {
+			t.Fatalf("AddUsageStats() returned error: %v", err)
+		}
+	}
+
+	stats, err = LoadUsageStats(path)
+	if err != nil {
+		t.Fatalf("LoadUsageStats() returned error: %v", err)
+	}
+
+	if stats.Sessions != 4 || stats.RideSecs != 6060 || stats.DistanceKM != 42 || stats.Calories != 1000 {
+		t.Errorf("LoadUsageStats() = %+v, want 4 sessions, 6060 secs, 42 km, and 1000 kcal", stats)
+	}
+
+	if !stats.Since.Equal(started) {
+		t.Errorf("Since = %v, want the earliest session start %v", stats.Since, started)
+	}
+
+	// Define test cases
+	tests := []struct {
+		name string
+		n    int
+		want []VideoCount
+	}{
+		{"all videos", 5, []VideoCount{{"alps.mp4", 2}, {"coast.mp4", 1}}},
+		{"most ridden video", 1, []VideoCount{{"alps.mp4", 2}}},
+		{"no videos", 0, []VideoCount{}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := stats.TopVideos(tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("TopVideos(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+
+		})
+	}
+
+}
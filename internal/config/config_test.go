@@ -1,7 +1,17 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+	"github.com/richbl/go-ble-sync-cycle/internal/flags"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
 )
 
 const (
@@ -11,11 +21,17 @@ const (
 // TestLoad tests the Load function
 func TestLoad(t *testing.T) {
 
+	invalidConfig := filepath.Join(t.TempDir(), "invalid_config.toml")
+	if err := os.WriteFile(invalidConfig, []byte("[app\n"), 0o600); err != nil {
+		t.Fatalf("failed to write invalid config file: %v", err)
+	}
+
 	// Define test cases
 	tests := []struct {
 		name        string
 		configFile  string
 		expectError bool
+		expectCode  apperr.Code
 	}{
 		{
 			name:        "valid config file",
@@ -24,13 +40,15 @@ func TestLoad(t *testing.T) {
 		},
 		{
 			name:        "invalid config file",
-			configFile:  "invalid_config.toml",
+			configFile:  invalidConfig,
 			expectError: true,
+			expectCode:  apperr.CodeConfigInvalid,
 		},
 		{
 			name:        "non-existent config file",
 			configFile:  "non_existent.toml",
 			expectError: true,
+			expectCode:  apperr.CodeConfigNotFound,
 		},
 	}
 
@@ -44,6 +62,10 @@ func TestLoad(t *testing.T) {
 				t.Errorf("Load() error = %v, expectError %v", err, tt.expectError)
 			}
 
+			if code := apperr.CodeOf(err); code != tt.expectCode {
+				t.Errorf("Load() error code = %q, want %q", code, tt.expectCode)
+			}
+
 		})
 	}
 
@@ -59,16 +81,27 @@ func TestAppConfigValidate(t *testing.T) {
 		name         string
 		logLevel     string
 		sessionTitle string
+		backupCount  int
+		maxMinutes   int
+		shutdownSecs int
 		expectError  bool
 	}{
-		{"valid debug", logLevelDebug, sessionTitle, false},
-		{"valid info", logLevelInfo, sessionTitle, false},
-		{"valid warn", logLevelWarn, sessionTitle, false},
-		{"valid error", logLevelError, sessionTitle, false},
-		{"valid fatal", logLevelFatal, sessionTitle, false},
-		{"invalid log level", "invalid", sessionTitle, true},
-		{"valid session title", logLevelInfo, sessionTitle, false},
-		{"invalid session title", logLevelInfo, "This is a very long session title that is designed to be well over the two hundred character limit that has been imposed on it to ensure that the validation logic is correctly catching strings that are too long.", true},
+		{"valid debug", logLevelDebug, sessionTitle, 1, 0, 30, false},
+		{"valid info", logLevelInfo, sessionTitle, 1, 0, 30, false},
+		{"valid warn", logLevelWarn, sessionTitle, 1, 0, 30, false},
+		{"valid error", logLevelError, sessionTitle, 1, 0, 30, false},
+		{"valid fatal", logLevelFatal, sessionTitle, 1, 0, 30, false},
+		{"invalid log level", "invalid", sessionTitle, 1, 0, 30, true},
+		{"valid session title", logLevelInfo, sessionTitle, 1, 0, 30, false},
+		{"invalid session title", logLevelInfo, "This is a very long session title that is designed to be well over the two hundred character limit that has been imposed on it to ensure that the validation logic is correctly catching strings that are too long.", 1, 0, 30, true},
+		{"no backups", logLevelInfo, sessionTitle, 0, 0, 30, false},
+		{"too many backups", logLevelInfo, sessionTitle, 11, 0, 30, true},
+		{"max session duration", logLevelInfo, sessionTitle, 1, 90, 30, false},
+		{"max session duration too long", logLevelInfo, sessionTitle, 1, 1441, 30, true},
+		{"negative max session duration", logLevelInfo, sessionTitle, 1, -1, 30, true},
+		{"short shutdown timeout", logLevelInfo, sessionTitle, 1, 0, 1, false},
+		{"no shutdown timeout", logLevelInfo, sessionTitle, 1, 0, 0, true},
+		{"shutdown timeout too long", logLevelInfo, sessionTitle, 1, 0, 301, true},
 	}
 
 	// Run tests
@@ -76,8 +109,11 @@ func TestAppConfigValidate(t *testing.T) {
 
 		t.Run(tt.name, func(t *testing.T) {
 			ac := AppConfig{
-				LogLevel:     tt.logLevel,
-				SessionTitle: tt.sessionTitle,
+				LogLevel:            tt.logLevel,
+				SessionTitle:        tt.sessionTitle,
+				BackupCount:         tt.backupCount,
+				MaxSessionMinutes:   tt.maxMinutes,
+				ShutdownTimeoutSecs: tt.shutdownSecs,
 			}
 			err := ac.validate()
 			if (err != nil) != tt.expectError {
@@ -96,12 +132,20 @@ func TestBLEConfigValidate(t *testing.T) {
 	tests := []struct {
 		name            string
 		sensorBDAddr    string
+		envSensorBDAddr string
 		scanTimeoutSecs int
+		scanAttempts    int
 		expectError     bool
 	}{
-		{"valid BD_ADDR and timeout", "00:11:22:33:44:55", 10, false},
-		{"invalid BD_ADDR", "invalid", 10, true},
-		{"invalid scan timeout", "00:11:22:33:44:55", 0, true},
+		{"valid BD_ADDR and timeout", "00:11:22:33:44:55", "", 10, 3, false},
+		{"invalid BD_ADDR", "invalid", "", 10, 3, true},
+		{"invalid scan timeout", "00:11:22:33:44:55", "", 0, 3, true},
+		{"invalid scan attempts", "00:11:22:33:44:55", "", 10, 0, true},
+		{"too many scan attempts", "00:11:22:33:44:55", "", 10, 11, true},
+		{"valid environmental sensor BD_ADDR", "00:11:22:33:44:55", "66:77:88:99:AA:BB", 10, 3, false},
+		{"invalid environmental sensor BD_ADDR", "00:11:22:33:44:55", "invalid", 10, 3, true},
+		{"lower case BD_ADDR without separators", "fa461d77c8e1", "66-77-88-99-aa-bb", 10, 3, false},
+		{"broadcast BD_ADDR", "FF:FF:FF:FF:FF:FF", "", 10, 3, true},
 	}
 
 	// Run tests
@@ -109,7 +153,7 @@ func TestBLEConfigValidate(t *testing.T) {
 
 		t.Run(tt.name, func(t *testing.T) {
 
-			bc := BLEConfig{SensorBDAddr: tt.sensorBDAddr, ScanTimeoutSecs: tt.scanTimeoutSecs}
+			bc := BLEConfig{SensorBDAddr: tt.sensorBDAddr, EnvSensorBDAddr: tt.envSensorBDAddr, ScanTimeoutSecs: tt.scanTimeoutSecs, ScanAttempts: tt.scanAttempts}
 			err := bc.validate()
 			if (err != nil) != tt.expectError {
 				t.Errorf("BLEConfig.validate() error = %v, expectError %v", err, tt.expectError)
@@ -120,6 +164,48 @@ func TestBLEConfigValidate(t *testing.T) {
 
 }
 
+// TestNormalizeBDAddr tests converting BD_ADDRs in the accepted formats to their canonical form
+func TestNormalizeBDAddr(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		addr      string
+		want      string
+		wantError error
+	}{
+		{"FA:46:1D:77:C8:E1", "FA:46:1D:77:C8:E1", nil},
+		{"fa:46:1d:77:c8:e1", "FA:46:1D:77:C8:E1", nil},
+		{" fa-46-1d-77-c8-e1 ", "FA:46:1D:77:C8:E1", nil},
+		{"fa46.1d77.c8e1", "FA:46:1D:77:C8:E1", nil},
+		{"FA461D77C8E1", "FA:46:1D:77:C8:E1", nil},
+		{"FA:46:1D:77:C8", "", errBDAddrFormat},
+		{"FA:46:1D:77:C8:G1", "", errBDAddrFormat},
+		{"", "", errBDAddrFormat},
+		{"00:00:00:00:00:00", "", errBDAddrReserved},
+		{"ff:ff:ff:ff:ff:ff", "", errBDAddrReserved},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.addr, func(t *testing.T) {
+
+			got, err := NormalizeBDAddr(tt.addr)
+			if got != tt.want || !errors.Is(err, tt.wantError) {
+				t.Errorf("NormalizeBDAddr(%q) = (%q, %v), want (%q, %v)", tt.addr, got, err, tt.want, tt.wantError)
+			}
+
+		})
+	}
+
+	// Validation keeps the BD_ADDRs in their canonical form
+	bc := BLEConfig{SensorBDAddr: "fa461d77c8e1", EnvSensorBDAddr: "66-77-88-99-aa-bb", ScanTimeoutSecs: 10, ScanAttempts: 3}
+	if err := bc.validate(); err != nil || bc.SensorBDAddr != "FA:46:1D:77:C8:E1" || bc.EnvSensorBDAddr != "66:77:88:99:AA:BB" {
+		t.Errorf("BLEConfig.validate() = (%q, %q, %v), want canonical BD_ADDRs", bc.SensorBDAddr, bc.EnvSensorBDAddr, err)
+	}
+
+}
+
 // TestSpeedConfigValidate tests the SpeedConfig validate function
 func TestSpeedConfigValidate(t *testing.T) {
 
@@ -130,13 +216,21 @@ func TestSpeedConfigValidate(t *testing.T) {
 		speedThreshold     float64
 		wheelCircumference int
 		speedUnits         string
+		cadenceSpeed       float64
+		coastDecay         float64
 		expectError        bool
 	}{
-		{"valid config", 10, 5.0, 1000, SpeedUnitsKMH, false},
-		{"invalid speed units", 10, 5.0, 1000, "invalid", true},
-		{"invalid smoothing window", 0, 5.0, 1000, SpeedUnitsKMH, true},
-		{"invalid speed threshold", 10, 11.0, 1000, SpeedUnitsKMH, true},
-		{"invalid wheel circumference", 10, 5.0, 49, SpeedUnitsKMH, true},
+		{"valid config", 10, 5.0, 1000, SpeedUnitsKMH, 0.0, 0.0, false},
+		{"valid m/s speed units", 10, 5.0, 1000, SpeedUnitsMPS, 0.0, 0.0, false},
+		{"valid rpm speed units", 10, 5.0, 1000, SpeedUnitsRPM, 0.0, 0.0, false},
+		{"invalid speed units", 10, 5.0, 1000, "invalid", 0.0, 0.0, true},
+		{"invalid smoothing window", 0, 5.0, 1000, SpeedUnitsKMH, 0.0, 0.0, true},
+		{"invalid speed threshold", 10, 11.0, 1000, SpeedUnitsKMH, 0.0, 0.0, true},
+		{"invalid wheel circumference", 10, 5.0, 49, SpeedUnitsKMH, 0.0, 0.0, true},
+		{"valid cadence speed", 10, 5.0, 1000, SpeedUnitsKMH, 0.33, 0.0, false},
+		{"invalid cadence speed", 10, 5.0, 1000, SpeedUnitsKMH, 10.5, 0.0, true},
+		{"valid coast decay", 10, 5.0, 1000, SpeedUnitsKMH, 0.0, 8.0, false},
+		{"invalid coast decay", 10, 5.0, 1000, SpeedUnitsKMH, 0.0, 61.0, true},
 	}
 
 	// Run tests
@@ -149,6 +243,8 @@ func TestSpeedConfigValidate(t *testing.T) {
 				SpeedThreshold:       tt.speedThreshold,
 				WheelCircumferenceMM: tt.wheelCircumference,
 				SpeedUnits:           tt.speedUnits,
+				CadenceSpeedPerRPM:   tt.cadenceSpeed,
+				CoastDecaySecs:       tt.coastDecay,
 			}
 
 			err := sc.validate()
@@ -204,6 +300,7 @@ func TestVideoConfigValidate(t *testing.T) {
 				SeekToPosition:    tt.seekToPosition,
 				UpdateIntervalSec: tt.updateIntervalSec,
 				SpeedMultiplier:   tt.speedMultiplier,
+				HardwareDecoding:  HWDecAuto,
 				OnScreenDisplay: VideoOSDConfig{
 					FontSize: tt.fontSize,
 					AlignX:   "center",
@@ -223,6 +320,361 @@ func TestVideoConfigValidate(t *testing.T) {
 
 }
 
+// TestPlaybackSpeedLimitsValidate tests the validation of the minimum and maximum playback speeds
+func TestPlaybackSpeedLimitsValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		minSpeed    float64
+		maxSpeed    float64
+		expectError bool
+	}{
+		{"no limits", 0.0, 0.0, false},
+		{"minimum and maximum", 0.25, 4.0, false},
+		{"minimum only", 0.5, 0.0, false},
+		{"minimum above maximum", 1.0, 0.5, true},
+		{"minimum out of range", 1.5, 4.0, true},
+		{"maximum out of range", 0.25, 10.5, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			vc := createTestConfig().Video
+			vc.FilePath = testVideo
+			vc.MinPlaybackSpeed = tt.minSpeed
+			vc.MaxPlaybackSpeed = tt.maxSpeed
+
+			err := vc.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("VideoConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+		})
+	}
+
+}
+
+// TestFocusModeValidate tests the validation of the focus mode
+func TestFocusModeValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		focusMode   string
+		want        string
+		expectError bool
+	}{
+		{"off", FocusModeOff, FocusModeOff, false},
+		{"compact", "compact", "compact", true},
+		{"hide", FocusModeHide, FocusModeHide, false},
+		{"unset", "", FocusModeOff, false},
+		{"invalid", "fullscreen", "fullscreen", true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			vc := createTestConfig().Video
+			vc.FilePath = testVideo
+			vc.FocusMode = tt.focusMode
+
+			err := vc.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("VideoConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if vc.FocusMode != tt.want {
+				t.Errorf("FocusMode = %q, want %q", vc.FocusMode, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestSpeedZones tests the validation of speed zones, and the speed zone of a speed
+func TestSpeedZones(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		zones       []float64
+		speed       float64
+		wantZone    int
+		expectError bool
+	}{
+		{"no zones", nil, 12.0, 0, false},
+		{"slowest zone", []float64{10.0, 15.0, 20.0}, 5.0, 1, false},
+		{"second zone", []float64{10.0, 15.0, 20.0}, 10.0, 2, false},
+		{"third zone", []float64{10.0, 15.0, 20.0}, 19.9, 3, false},
+		{"fastest zone", []float64{10.0, 15.0, 20.0}, 32.0, 4, false},
+		{"too few zones", []float64{10.0, 15.0}, 0, 0, true},
+		{"descending zones", []float64{20.0, 15.0, 10.0}, 0, 0, true},
+		{"zone out of range", []float64{10.0, 15.0, 2000.0}, 0, 0, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			sc := createTestConfig().Speed
+			sc.SpeedZones = tt.zones
+
+			err := sc.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("SpeedConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if tt.expectError {
+				return
+			}
+
+			if got := sc.SpeedZone(tt.speed); got != tt.wantZone {
+				t.Errorf("SpeedZone(%.1f) = %d, want %d", tt.speed, got, tt.wantZone)
+			}
+
+		})
+	}
+
+}
+
+// TestParseSpeedZones tests parsing speed zones written as comma-separated speeds
+func TestParseSpeedZones(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		input       string
+		want        string
+		expectError bool
+	}{
+		{"", "", false},
+		{"10, 15, 20", "10.0, 15.0, 20.0", false},
+		{" 12.5,18,24.25 ", "12.5, 18.0, 24.2", false},
+		{"10, 15", "", true},
+		{"20, 15, 10", "", true},
+		{"10, fast, 20", "", true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.input, func(t *testing.T) {
+
+			zones, err := ParseSpeedZones(tt.input)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseSpeedZones() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if got := FormatSpeedZones(zones); !tt.expectError && got != tt.want {
+				t.Errorf("FormatSpeedZones() = %q, want %q", got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestGoalConfigValidate tests the GoalConfig validate function
+func TestGoalConfigValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		goal        GoalConfig
+		expectError bool
+	}{
+		{"no goal", GoalConfig{RiderWeightKG: 75}, false},
+		{"time goal", GoalConfig{Type: GoalTypeTime, Target: 60, RiderWeightKG: 75}, false},
+		{"distance goal", GoalConfig{Type: GoalTypeDistance, Target: 25, RiderWeightKG: 75}, false},
+		{"calories goal", GoalConfig{Type: GoalTypeCalories, Target: 500, RiderWeightKG: 75}, false},
+		{"invalid goal type", GoalConfig{Type: "laps", Target: 10, RiderWeightKG: 75}, true},
+		{"missing goal target", GoalConfig{Type: GoalTypeTime, RiderWeightKG: 75}, true},
+		{"goal target out of range", GoalConfig{Type: GoalTypeTime, Target: 20000, RiderWeightKG: 75}, true},
+		{"rider weight out of range", GoalConfig{Type: GoalTypeCalories, Target: 500, RiderWeightKG: 10}, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.goal.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("GoalConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+		})
+	}
+
+}
+
+// TestIntervalConfigValidate tests the IntervalConfig validate function
+func TestIntervalConfigValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		intervals     IntervalConfig
+		expectEnabled bool
+		expectError   bool
+	}{
+		{"no interval timer", IntervalConfig{}, false, false},
+		{"interval timer", IntervalConfig{HardSecs: 30, EasySecs: 90}, true, false},
+		{"missing easy interval", IntervalConfig{HardSecs: 30}, false, true},
+		{"missing hard interval", IntervalConfig{EasySecs: 90}, false, true},
+		{"interval out of range", IntervalConfig{HardSecs: 7200, EasySecs: 90}, true, true},
+		{"negative interval", IntervalConfig{HardSecs: -30, EasySecs: 90}, false, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.intervals.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("IntervalConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if enabled := tt.intervals.Enabled(); enabled != tt.expectEnabled {
+				t.Errorf("IntervalConfig.Enabled() = %v, want %v", enabled, tt.expectEnabled)
+			}
+
+		})
+	}
+
+}
+
+// TestLapConfigValidate tests the LapConfig validate function
+func TestLapConfigValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		laps          LapConfig
+		expectEnabled bool
+		expectError   bool
+	}{
+		{"no automatic laps", LapConfig{}, false, false},
+		{"distance laps", LapConfig{Distance: 5}, true, false},
+		{"timed laps", LapConfig{Minutes: 10}, true, false},
+		{"distance and timed laps", LapConfig{Distance: 5, Minutes: 10}, true, true},
+		{"distance out of range", LapConfig{Distance: 5000}, true, true},
+		{"negative minutes", LapConfig{Minutes: -10}, false, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.laps.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("LapConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if enabled := tt.laps.Enabled(); enabled != tt.expectEnabled {
+				t.Errorf("LapConfig.Enabled() = %v, want %v", enabled, tt.expectEnabled)
+			}
+
+		})
+	}
+
+	// Lap distances are given in miles when the speed units are mph
+	if km := (LapConfig{Distance: 2}).DistanceKM(SpeedUnitsMPH); km != 2*units.KilometersPerMile {
+		t.Errorf("LapConfig.DistanceKM() = %.3f, want %.3f", km, 2*units.KilometersPerMile)
+	}
+
+}
+
+// TestRaceConfigValidate tests the RaceConfig validate function
+func TestRaceConfigValidate(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		race          RaceConfig
+		expectEnabled bool
+		expectEasy    float64
+		expectError   bool
+	}{
+		{"no race", RaceConfig{}, false, 0, false},
+		{"race", RaceConfig{TargetSpeed: 15}, true, 15, false},
+		{"race with easy target", RaceConfig{TargetSpeed: 20, EasyTargetSpeed: 12}, true, 12, false},
+		{"easy target without target", RaceConfig{EasyTargetSpeed: 12}, false, 12, true},
+		{"target out of range", RaceConfig{TargetSpeed: 5000}, true, 5000, true},
+		{"negative easy target", RaceConfig{TargetSpeed: 15, EasyTargetSpeed: -1}, true, 15, true},
+		{"outdoor ride", RaceConfig{OutdoorGPXFile: "config_test.toml"}, false, 0, false},
+		{"missing outdoor ride", RaceConfig{OutdoorGPXFile: "missing.gpx"}, false, 0, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.race.validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("RaceConfig.validate() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if enabled := tt.race.Enabled(); enabled != tt.expectEnabled {
+				t.Errorf("RaceConfig.Enabled() = %v, want %v", enabled, tt.expectEnabled)
+			}
+
+			if target := tt.race.Target(true); target != tt.expectEasy {
+				t.Errorf("RaceConfig.Target(true) = %v, want %v", target, tt.expectEasy)
+			}
+
+		})
+	}
+
+}
+
+// TestHooksConfig tests the HooksConfig validate and Command functions
+func TestHooksConfig(t *testing.T) {
+
+	hooks := HooksConfig{OnSessionStart: "lights on", OnLap: "notify-send lap", OnSensorLost: "notify-send lost", TimeoutSecs: 10}
+
+	// Define test cases
+	tests := []struct {
+		event string
+		want  string
+	}{
+		{HookSessionStart, "lights on"},
+		{HookSessionStop, ""},
+		{HookLap, "notify-send lap"},
+		{HookSensorLost, "notify-send lost"},
+		{"unknown", ""},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		if got := hooks.Command(tt.event); got != tt.want {
+			t.Errorf("Command(%q) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+
+	if err := hooks.validate(); err != nil {
+		t.Errorf("HooksConfig.validate() returned error: %v", err)
+	}
+
+	hooks.TimeoutSecs = 0
+
+	if err := hooks.validate(); err == nil {
+		t.Error("HooksConfig.validate() accepted a timeout of 0 seconds")
+	}
+
+}
+
 // TestVideoOSDConfigValidate tests the VideoOSDConfig validate function
 func TestValidateTimeFormat(t *testing.T) {
 
@@ -289,3 +741,327 @@ func TestValidateField(t *testing.T) {
 	}
 
 }
+
+// TestValidateMediaPlayerArgs tests the validateMediaPlayerArgs function
+func TestValidateMediaPlayerArgs(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"no args", nil, false},
+		{"option with value", []string{"hwdec=auto"}, false},
+		{"option with leading dashes", []string{"--audio-device=pulse"}, false},
+		{"flag option", []string{"mute"}, false},
+		{"empty option", []string{""}, true},
+		{"missing option name", []string{"=auto"}, true},
+		{"option name with space", []string{"hw dec=auto"}, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := validateMediaPlayerArgs(tt.args)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateMediaPlayerArgs() error = %v, expectError %v", err, tt.expectError)
+			}
+
+		})
+	}
+
+}
+
+// TestParseMediaPlayerArg tests the ParseMediaPlayerArg function
+func TestParseMediaPlayerArg(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		arg       string
+		wantName  string
+		wantValue string
+	}{
+		{"hwdec=auto", "hwdec", "auto"},
+		{"--sub-font-size=40", "sub-font-size", "40"},
+		{"--mute", "mute", "yes"},
+		{"lavfi-complex=[vid1]hflip[vo]", "lavfi-complex", "[vid1]hflip[vo]"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.arg, func(t *testing.T) {
+
+			name, value := ParseMediaPlayerArg(tt.arg)
+			if name != tt.wantName || value != tt.wantValue {
+				t.Errorf("ParseMediaPlayerArg() = (%q, %q), want (%q, %q)", name, value, tt.wantName, tt.wantValue)
+			}
+
+		})
+	}
+
+}
+
+// TestParseMediaPlayerArgs tests parsing and formatting space-separated media player arguments
+func TestParseMediaPlayerArgs(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		text        string
+		want        []string
+		expectError bool
+	}{
+		{"no args", "  ", []string{}, false},
+		{"one arg", "hwdec=auto", []string{"hwdec=auto"}, false},
+		{"several args", " hwdec=auto  --mute ", []string{"hwdec=auto", "--mute"}, false},
+		{"missing option name", "hwdec=auto =yes", nil, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			args, err := ParseMediaPlayerArgs(tt.text)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseMediaPlayerArgs() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if !reflect.DeepEqual(args, tt.want) {
+				t.Errorf("ParseMediaPlayerArgs() = %q, want %q", args, tt.want)
+			}
+
+			if got, want := FormatMediaPlayerArgs(args), strings.Join(tt.want, " "); got != want {
+				t.Errorf("FormatMediaPlayerArgs() = %q, want %q", got, want)
+			}
+
+		})
+	}
+
+}
+
+// TestParseWindowPosition tests the ParseWindowPosition function
+func TestParseWindowPosition(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		pos    string
+		wantX  int
+		wantY  int
+		wantOK bool
+	}{
+		{"100,50", 100, 50, true},
+		{" 0 , 0 ", 0, 0, true},
+		{"", 0, 0, false},
+		{"100", 0, 0, false},
+		{"-10,50", 0, 0, false},
+		{"x,y", 0, 0, false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.pos, func(t *testing.T) {
+
+			x, y, ok := ParseWindowPosition(tt.pos)
+			if x != tt.wantX || y != tt.wantY || ok != tt.wantOK {
+				t.Errorf("ParseWindowPosition() = (%d, %d, %v), want (%d, %d, %v)", x, y, ok, tt.wantX, tt.wantY, tt.wantOK)
+			}
+
+		})
+	}
+
+}
+
+// TestNightModeAt tests parsing the night mode hours and finding whether night mode is on
+func TestNightModeAt(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		hours       string
+		brightness  int
+		hour        int
+		want        bool
+		expectError bool
+	}{
+		{"never", "", 0, 22, false, false},
+		{"evening", "18-23", 40, 20, true, false},
+		{"before evening", "18-23", 40, 17, false, false},
+		{"end hour excluded", "18-23", 40, 23, false, false},
+		{"past midnight (late)", "21-7", 40, 23, true, false},
+		{"past midnight (early)", "21-7", 40, 6, true, false},
+		{"past midnight (day)", "21-7", 40, 12, false, false},
+		{"invalid format", "21:00", 40, 22, false, true},
+		{"invalid hour", "21-24", 40, 22, false, true},
+		{"same hours", "7-7", 40, 7, false, true},
+		{"invalid brightness", "21-7", 5, 22, true, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			oc := VideoOSDConfig{NightModeHours: tt.hours, NightBrightness: tt.brightness}
+
+			if err := oc.validateNightMode(); (err != nil) != tt.expectError {
+				t.Errorf("validateNightMode() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			at := time.Date(2026, 1, 15, tt.hour, 30, 0, 0, time.Local)
+			if got := oc.NightModeAt(at); got != tt.want {
+				t.Errorf("NightModeAt(%02d:30) = %v, want %v", tt.hour, got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestReadConfigFileDefaults tests that settings absent from a config file retain their defaults
+func TestReadConfigFileDefaults(t *testing.T) {
+
+	configFile := filepath.Join(t.TempDir(), "legacy.toml")
+	content := "[app]\n  session_title = \"Legacy Session\"\n"
+
+	if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := readConfigFile(configFile, defaultConfig())
+	if err != nil {
+		t.Fatalf("readConfigFile() returned error: %v", err)
+	}
+
+	if cfg.App.SessionTitle != "Legacy Session" {
+		t.Errorf("SessionTitle = %q, want %q", cfg.App.SessionTitle, "Legacy Session")
+	}
+
+	if cfg.Video.HardwareDecoding != HWDecOff {
+		t.Errorf("HardwareDecoding = %q, want default of %q", cfg.Video.HardwareDecoding, HWDecOff)
+	}
+
+	if cfg.Video.Audio.Volume != 100 {
+		t.Errorf("Audio.Volume = %d, want default of 100", cfg.Video.Audio.Volume)
+	}
+
+	if cfg.Video.OnScreenDisplay.UpdateIntervalSec != 1.0 {
+		t.Errorf("OnScreenDisplay.UpdateIntervalSec = %v, want default of 1.0", cfg.Video.OnScreenDisplay.UpdateIntervalSec)
+	}
+
+}
+
+// TestCheckForSubtitleFile tests the checkForSubtitleFile function
+func TestCheckForSubtitleFile(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		filename    string
+		expectError bool
+	}{
+		{"no subtitle file", "", false},
+		{"existing subtitle file", testVideo, false},
+		{"missing subtitle file", "missing_subtitles.srt", true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := checkForSubtitleFile(tt.filename)
+			if (err != nil) != tt.expectError {
+				t.Errorf("checkForSubtitleFile() error = %v, expectError %v", err, tt.expectError)
+			}
+
+		})
+	}
+
+}
+
+// TestSetReplay tests setting the recorded session replay from the command-line flags
+func TestSetReplay(t *testing.T) {
+
+	replayFile := filepath.Join(t.TempDir(), "samples.csv")
+	if err := os.WriteFile(replayFile, []byte("elapsed_secs,speed\n0,10\n"), 0o600); err != nil {
+		t.Fatalf("failed to create replay file: %v", err)
+	}
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		clFlags  flags.CLIFlags
+		wantFile string
+		wantRate float64
+		wantErr  bool
+	}{
+		{"no replay", flags.CLIFlags{}, "", 0, false},
+		{"default rate", flags.CLIFlags{Replay: replayFile}, replayFile, 1.0, false},
+		{"custom rate", flags.CLIFlags{Replay: replayFile, ReplayRate: "2.5"}, replayFile, 2.5, false},
+		{"invalid rate", flags.CLIFlags{Replay: replayFile, ReplayRate: "fast"}, "", 0, true},
+		{"rate out of range", flags.CLIFlags{Replay: replayFile, ReplayRate: "0"}, "", 0, true},
+		{"missing file", flags.CLIFlags{Replay: replayFile + ".missing"}, "", 0, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			cfg := &Config{}
+
+			err := setReplay(cfg, tt.clFlags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setReplay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if cfg.Speed.ReplayFile != tt.wantFile || cfg.Speed.ReplayRate != tt.wantRate {
+				t.Errorf("setReplay() = (%q, %v), want (%q, %v)", cfg.Speed.ReplayFile, cfg.Speed.ReplayRate, tt.wantFile, tt.wantRate)
+			}
+
+		})
+	}
+
+}
+
+// TestSetSampleExport tests setting the raw BLE sample export file from the command-line flags
+func TestSetSampleExport(t *testing.T) {
+
+	cfg := &Config{}
+	setSampleExport(cfg, flags.CLIFlags{})
+
+	if cfg.BLE.SampleExportFile != "" {
+		t.Errorf("setSampleExport() = %q, want empty", cfg.BLE.SampleExportFile)
+	}
+
+	setSampleExport(cfg, flags.CLIFlags{Export: "samples.csv"})
+
+	if cfg.BLE.SampleExportFile != "samples.csv" {
+		t.Errorf("setSampleExport() = %q, want %q", cfg.BLE.SampleExportFile, "samples.csv")
+	}
+
+}
+
+// TestSetTrafficLog tests setting the BLE traffic log file from the command-line flags
+func TestSetTrafficLog(t *testing.T) {
+
+	cfg := &Config{}
+	setTrafficLog(cfg, flags.CLIFlags{})
+
+	if cfg.BLE.TrafficLogFile != "" {
+		t.Errorf("setTrafficLog() = %q, want empty", cfg.BLE.TrafficLogFile)
+	}
+
+	setTrafficLog(cfg, flags.CLIFlags{TrafficLog: "traffic.log"})
+
+	if cfg.BLE.TrafficLogFile != "traffic.log" {
+		t.Errorf("setTrafficLog() = %q, want %q", cfg.BLE.TrafficLogFile, "traffic.log")
+	}
+
+}
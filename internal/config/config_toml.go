@@ -1,8 +1,10 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"io"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -17,37 +19,97 @@ const ConfigTemplate = `# BLE Sync Cycle Configuration (TOML)
 [app]
   session_title = "{{.App.SessionTitle}}"{{pad (printf "session_title = \"%s\"" .App.SessionTitle)}}# Short description of the current cycling session (0-200 characters, excluding ", &, and <)
   logging_level = "{{.App.LogLevel}}"{{pad (printf "logging_level = \"%s\"" .App.LogLevel)}}# Log messages generated during execution ("debug", "info", "warn", "error")
+  backup_count = {{.App.BackupCount}}{{pad (printf "backup_count = %d" .App.BackupCount)}}# Number of backups (.bak files) of this file kept when saved (0-10, where 0 = no backups)
+  max_session_minutes = {{.App.MaxSessionMinutes}}{{pad (printf "max_session_minutes = %d" .App.MaxSessionMinutes)}}# Stop the session after this many minutes, even while paused (0-1440, where 0 = no limit)
+  shutdown_timeout_secs = {{.App.ShutdownTimeoutSecs}}{{pad (printf "shutdown_timeout_secs = %d" .App.ShutdownTimeoutSecs)}}# Time allowed for the media player and BLE sensor to stop when the session stops (1-300 seconds)
 
 [ble]
-  sensor_bd_addr = "{{.BLE.SensorBDAddr}}"{{pad (printf "sensor_bd_addr = \"%s\"" .BLE.SensorBDAddr)}}# The Bluetooth Device Address (BD_ADDR) of the BLE peripheral
-  scan_timeout_secs = {{.BLE.ScanTimeoutSecs}}{{pad (printf "scan_timeout_secs = %d" .BLE.ScanTimeoutSecs)}}# Time to wait for a response from the peripheral before connect fails (1-100 seconds)
+  sensor_bd_addr = "{{addr .BLE.SensorBDAddr}}"{{pad (printf "sensor_bd_addr = \"%s\"" (addr .BLE.SensorBDAddr))}}# The Bluetooth Device Address (BD_ADDR) of the BLE peripheral
+  scan_timeout_secs = {{.BLE.ScanTimeoutSecs}}{{pad (printf "scan_timeout_secs = %d" .BLE.ScanTimeoutSecs)}}# Total time to wait for a response from the peripheral before connect fails (1-100 seconds)
+  scan_attempts = {{.BLE.ScanAttempts}}{{pad (printf "scan_attempts = %d" .BLE.ScanAttempts)}}# Number of scans for the peripheral within the scan timeout (1-10)
+  env_sensor_bd_addr = "{{addr .BLE.EnvSensorBDAddr}}"{{pad (printf "env_sensor_bd_addr = \"%s\"" (addr .BLE.EnvSensorBDAddr))}}# The BD_ADDR of an (optional) environmental sensor reporting temperature and humidity ("" for none)
 
 [speed]
   wheel_circumference_mm = {{.Speed.WheelCircumferenceMM}}{{pad (printf "wheel_circumference_mm = %d" .Speed.WheelCircumferenceMM)}}# Wheel circumference (50-3000 millimeters)
-  speed_units = "{{.Speed.SpeedUnits}}"{{pad (printf "speed_units = \"%s\"" .Speed.SpeedUnits)}}# The unit of measurement for speed ("mph" or "km/h")
+  speed_units = "{{.Speed.SpeedUnits}}"{{pad (printf "speed_units = \"%s\"" .Speed.SpeedUnits)}}# The unit of measurement for speed ("mph", "km/h", "m/s" or "rpm")
   speed_threshold = {{printf "%.2f" .Speed.SpeedThreshold}}{{pad (printf "speed_threshold = %.2f" .Speed.SpeedThreshold)}}# Minimum speed change to trigger video playback update (0.00-10.00)
   smoothing_window = {{.Speed.SmoothingWindow}}{{pad (printf "smoothing_window = %d" .Speed.SmoothingWindow)}}# Number of recent speed readings to generate a stable moving average (1-25)
+  cadence_speed_per_rpm = {{printf "%.2f" .Speed.CadenceSpeedPerRPM}}{{pad (printf "cadence_speed_per_rpm = %.2f" .Speed.CadenceSpeedPerRPM)}}# Virtual speed per crank RPM for cadence-only sensors (0.00-10.00, where 0.00 = disabled)
+  coast_decay_secs = {{printf "%.1f" .Speed.CoastDecaySecs}}{{pad (printf "coast_decay_secs = %.1f" .Speed.CoastDecaySecs)}}# Time constant of the wheel spin-down when pedaling stops, e.g., on a fluid trainer (0.0-60.0 seconds, where 0.0 = disabled)
+  speed_zones = [{{zones .Speed.SpeedZones}}]{{pad (printf "speed_zones = [%s]" (zones .Speed.SpeedZones))}}# Speeds separating the blue, green, yellow and red speed zones shown on the OSD and GUI (3 ascending speeds, or [] for no zones)
+
+[goal]
+  type = "{{.Goal.Type}}"{{pad (printf "type = \"%s\"" .Goal.Type)}}# Session goal to ride toward ("time", "distance", "calories") ("" for no goal)
+  target = {{printf "%.1f" .Goal.Target}}{{pad (printf "target = %.1f" .Goal.Target)}}# Session goal target (0.0-10000.0 minutes, kilometers (miles when speed_units is "mph"), or kilocalories)
+  rider_weight_kg = {{.Goal.RiderWeightKG}}{{pad (printf "rider_weight_kg = %d" .Goal.RiderWeightKG)}}# Rider weight used to estimate calories burned (30-250 kilograms)
+
+[intervals]
+  hard_secs = {{.Intervals.HardSecs}}{{pad (printf "hard_secs = %d" .Intervals.HardSecs)}}# Length of each hard interval of the interval timer shown on the OSD (0-3600 seconds, where 0 = no interval timer)
+  easy_secs = {{.Intervals.EasySecs}}{{pad (printf "easy_secs = %d" .Intervals.EasySecs)}}# Length of each easy (recovery) interval of the interval timer (0-3600 seconds, where 0 = no interval timer)
+
+[laps]
+  distance = {{printf "%.1f" .Laps.Distance}}{{pad (printf "distance = %.1f" .Laps.Distance)}}# Distance of each automatic lap (0.0-1000.0 kilometers (miles when speed_units is "mph"), where 0.0 = no distance laps)
+  minutes = {{.Laps.Minutes}}{{pad (printf "minutes = %d" .Laps.Minutes)}}# Length of each automatic lap (0-600 minutes of riding time, where 0 = no timed laps)
+
+[race]
+  target_speed = {{printf "%.1f" .Race.TargetSpeed}}{{pad (printf "target_speed = %.1f" .Race.TargetSpeed)}}# Race-the-clock target speed: the video plays at a fixed 1.0x while the rider is scored on holding this speed (0.0-1000.0, where 0.0 = off)
+  easy_target_speed = {{printf "%.1f" .Race.EasyTargetSpeed}}{{pad (printf "easy_target_speed = %.1f" .Race.EasyTargetSpeed)}}# Target speed during the easy intervals of the interval timer (0.0-1000.0, where 0.0 = target_speed)
+  outdoor_gpx_file = {{quote .Race.OutdoorGPXFile}}{{pad (printf "outdoor_gpx_file = %s" (quote .Race.OutdoorGPXFile))}}# GPX file of an outdoor ride of the same route, shown on the OSD as how far ahead or behind it the ride is ("" for none)
+
+[hooks]
+  on_session_start = {{quote .Hooks.OnSessionStart}}{{pad (printf "on_session_start = %s" (quote .Hooks.OnSessionStart))}}# Command run (using "sh -c") when the session starts, receiving the session metrics as JSON on stdin ("" for none)
+  on_session_stop = {{quote .Hooks.OnSessionStop}}{{pad (printf "on_session_stop = %s" (quote .Hooks.OnSessionStop))}}# Command run (using "sh -c") when the session stops, receiving the session metrics as JSON on stdin ("" for none)
+  on_lap = {{quote .Hooks.OnLap}}{{pad (printf "on_lap = %s" (quote .Hooks.OnLap))}}# Command run (using "sh -c") when a lap is marked, receiving the session metrics as JSON on stdin ("" for none)
+  on_sensor_lost = {{quote .Hooks.OnSensorLost}}{{pad (printf "on_sensor_lost = %s" (quote .Hooks.OnSensorLost))}}# Command run (using "sh -c") when the BLE sensor stops responding, receiving the session metrics as JSON on stdin ("" for none)
+  timeout_secs = {{.Hooks.TimeoutSecs}}{{pad (printf "timeout_secs = %d" .Hooks.TimeoutSecs)}}# Time allowed for each hook command to complete (1-300 seconds)
 
 [video]
   media_player = "{{.Video.MediaPlayer}}"{{pad (printf "media_player = \"%s\"" .Video.MediaPlayer)}}# The video playback back-end to use ("mpv")
   file_path = "{{.Video.FilePath}}"{{pad (printf "file_path = \"%s\"" .Video.FilePath)}}# File path to the video file for playback
   seek_to_position = "{{.Video.SeekToPosition}}"{{pad (printf "seek_to_position = \"%s\"" .Video.SeekToPosition)}}# Starting playback position in the video ("HH:MM:SS")
   auto_resume = {{.Video.AutoResume}}{{pad (printf "auto_resume = %t" .Video.AutoResume)}}# Resume video playback from last playback position (true/false)
+  resume_distance = {{.Video.ResumeDistance}}{{pad (printf "resume_distance = %t" .Video.ResumeDistance)}}# Carry the distance ridden over to the next auto-resumed ride, so a video ridden over several days adds up to a single total distance (true/false)
+  resume_distance_km = {{printf "%.2f" .Video.ResumeDistanceKM}}{{pad (printf "resume_distance_km = %.2f" .Video.ResumeDistanceKM)}}# Distance ridden so far, saved with the auto-resume position (0.00-100000.00 km)
+  resume_speeds = {{nums .Video.ResumeSpeeds}}{{pad (printf "resume_speeds = %s" (nums .Video.ResumeSpeeds))}}# Smoothed cycling speeds when the session stopped, saved with the auto-resume position to warm start speed smoothing
   window_scale_factor = {{printf "%.1f" .Video.WindowScaleFactor}}{{pad (printf "window_scale_factor = %.1f" .Video.WindowScaleFactor)}}# Scales the size of the video window (0.1-1.0, where 1.0 = full screen)
+  window_position = "{{.Video.WindowPosition}}"{{pad (printf "window_position = \"%s\"" .Video.WindowPosition)}}# Position of the video window when not full screen ("X,Y" in pixels) ("" to let the desktop place the window)
   update_interval_secs = {{printf "%.1f" .Video.UpdateIntervalSec}}{{pad (printf "update_interval_secs = %.1f" .Video.UpdateIntervalSec)}}# Frequency that the video player is sent speed updates (0.10-3.00 seconds)
   speed_multiplier = {{printf "%.1f" .Video.SpeedMultiplier}}{{pad (printf "speed_multiplier = %.1f" .Video.SpeedMultiplier)}}# Multiplier to control video playback rate (0.1-1.5, where 0.1 = slower, 1.0 = normal, 1.5 = faster playback)
-  target_display_name = "{{.Video.TargetDisplayName}}"{{pad (printf "target_display_name = \"%s\"" .Video.TargetDisplayName)}}# Force playback to a specific monitor (e.g., "eDP-1") ("" to use default primary display)
+  min_playback_speed = {{printf "%.2f" .Video.MinPlaybackSpeed}}{{pad (printf "min_playback_speed = %.2f" .Video.MinPlaybackSpeed)}}# Slowest video playback rate while cycling (0.00-1.00, where 0.00 = no minimum)
+  max_playback_speed = {{printf "%.2f" .Video.MaxPlaybackSpeed}}{{pad (printf "max_playback_speed = %.2f" .Video.MaxPlaybackSpeed)}}# Fastest video playback rate (0.00-10.00, where 0.00 = no maximum)
+  frame_interpolation_speed = {{printf "%.2f" .Video.InterpolationSpeed}}{{pad (printf "frame_interpolation_speed = %.2f" .Video.InterpolationSpeed)}}# Smooth video with frame interpolation when playback runs slower than this rate (0.00-1.00, where 0.00 = disabled)
+  target_display_name = "{{.Video.TargetDisplayName}}"{{pad (printf "target_display_name = \"%s\"" .Video.TargetDisplayName)}}# Force playback to a specific monitor by connector name (e.g., "HDMI-A-1") or index (e.g., "1") ("" to use default primary display)
+  focus_mode = "{{.Video.FocusMode}}"{{pad (printf "focus_mode = \"%s\"" .Video.FocusMode)}}# Rearrange the GUI while the session runs, with the video full screen on the target display ("off", "hide")
+  subtitle_path = "{{.Video.SubtitlePath}}"{{pad (printf "subtitle_path = \"%s\"" .Video.SubtitlePath)}}# File path to an external subtitle file (e.g., .srt) ("" to use subtitles embedded in the video)
+  show_subtitles = {{.Video.ShowSubtitles}}{{pad (printf "show_subtitles = %t" .Video.ShowSubtitles)}}# Display subtitles during video playback (true/false)
+  hardware_decoding = "{{.Video.HardwareDecoding}}"{{pad (printf "hardware_decoding = \"%s\"" .Video.HardwareDecoding)}}# Hardware-accelerated video decoding ("auto", "vaapi", "nvdec", "off")
+  media_player_args = {{list .Video.MediaPlayerArgs}}{{pad (printf "media_player_args = %s" (list .Video.MediaPlayerArgs))}}# Extra options passed verbatim to the media player (e.g., ["hwdec=auto", "audio-device=auto"])
+  low_power = {{.Video.LowPower}}{{pad (printf "low_power = %t" .Video.LowPower)}}# Reduce OSD, metrics, and video output overhead for low-power hosts (e.g., Raspberry Pi 4/5) (true/false)
+  video_first_start = {{.Video.VideoFirstStart}}{{pad (printf "video_first_start = %t" .Video.VideoFirstStart)}}# Show the video (paused) while the BLE sensor connects, then play once speed data arrives (true/false)
+  start_countdown_secs = {{.Video.StartCountdownSecs}}{{pad (printf "start_countdown_secs = %d" .Video.StartCountdownSecs)}}# Seconds counted down on the GUI and OSD before video playback starts, to clip in (0-30, where 0 = no countdown)
+  resume_fade_secs = {{printf "%.1f" .Video.ResumeFadeSecs}}{{pad (printf "resume_fade_secs = %.1f" .Video.ResumeFadeSecs)}}# Seconds over which playback resumed after a pause ramps up from 0.5x to the cycling speed, fading the audio in (0.0-5.0, where 0.0 = no fade-in)
 
 
 [video.OSD]
   display_cycle_speed = {{.Video.OnScreenDisplay.DisplayCycleSpeed}}{{pad (printf "display_cycle_speed = %t" .Video.OnScreenDisplay.DisplayCycleSpeed)}}# Display the current cycle speed on the on-screen display (true/false)
   display_playback_speed = {{.Video.OnScreenDisplay.DisplayPlaybackSpeed}}{{pad (printf "display_playback_speed = %t" .Video.OnScreenDisplay.DisplayPlaybackSpeed)}}# Display the current video playback speed on the on-screen display (true/false)
   display_time_remaining = {{.Video.OnScreenDisplay.DisplayTimeRemaining}}{{pad (printf "display_time_remaining = %t" .Video.OnScreenDisplay.DisplayTimeRemaining)}}# Display the current video time remaining on the on-screen display (true/false)
+  display_speed_stats = {{.Video.OnScreenDisplay.DisplaySpeedStats}}{{pad (printf "display_speed_stats = %t" .Video.OnScreenDisplay.DisplaySpeedStats)}}# Display the session average and max cycle speeds on the on-screen display (true/false)
+  display_metrics_overlay = {{.Video.OnScreenDisplay.DisplayMetricsOverlay}}{{pad (printf "display_metrics_overlay = %t" .Video.OnScreenDisplay.DisplayMetricsOverlay)}}# Display a semi-transparent panel with the cycle speed and a graph of recent speeds (true/false)
+  osd_update_interval_secs = {{printf "%.1f" .Video.OnScreenDisplay.UpdateIntervalSec}}{{pad (printf "osd_update_interval_secs = %.1f" .Video.OnScreenDisplay.UpdateIntervalSec)}}# Frequency that the on-screen display is refreshed (0.0-10.0 seconds, where 0.0 = with every speed update)
   font_size = {{.Video.OnScreenDisplay.FontSize}}{{pad (printf "font_size = %d" .Video.OnScreenDisplay.FontSize)}}# Font size of the on-screen display (10-200 pixels)
   align_x = "{{.Video.OnScreenDisplay.AlignX}}"{{pad (printf "align_x = \"%s\"" .Video.OnScreenDisplay.AlignX)}}# The horizontal position of the OSD ("left", "center", "right")
   align_y = "{{.Video.OnScreenDisplay.AlignY}}"{{pad (printf "align_y = \"%s\"" .Video.OnScreenDisplay.AlignY)}}# The vertical position of the OSD ("top", "center", "bottom")  	
   margin_x = {{.Video.OnScreenDisplay.MarginX}}{{pad (printf "margin_x = %d" .Video.OnScreenDisplay.MarginX)}}# Margin for the left/right edge of the media player window (0-300 pixels)
   margin_y = {{.Video.OnScreenDisplay.MarginY}}{{pad (printf "margin_y = %d" .Video.OnScreenDisplay.MarginY)}}# Margin for the top/bottom edge of the media player window (0-600 pixels)
+  night_mode_hours = "{{.Video.OnScreenDisplay.NightModeHours}}"{{pad (printf "night_mode_hours = \"%s\"" .Video.OnScreenDisplay.NightModeHours)}}# Hours of the day when the OSD is dimmed and the GUI uses its dark theme ("START-END", e.g., "21-7", where "" = never)
+  night_brightness = {{.Video.OnScreenDisplay.NightBrightness}}{{pad (printf "night_brightness = %d" .Video.OnScreenDisplay.NightBrightness)}}# Brightness of the OSD text during night mode (10-100 percent)
+
+[video.audio]
+  volume = {{.Video.Audio.Volume}}{{pad (printf "volume = %d" .Video.Audio.Volume)}}# Audio playback volume (0-100)
+  mute = {{.Video.Audio.Mute}}{{pad (printf "mute = %t" .Video.Audio.Mute)}}# Mute audio playback (true/false)
+  audio_track = {{.Video.Audio.AudioTrack}}{{pad (printf "audio_track = %d" .Video.Audio.AudioTrack)}}# Audio track to play (0-99, where 0 = media player default)
+  pitch_correction = {{.Video.Audio.PitchCorrection}}{{pad (printf "pitch_correction = %t" .Video.Audio.PitchCorrection)}}# Keep the audio pitch natural when playback runs faster or slower than normal (true/false)
 `
 
 // tomlContent wraps Config with version info for TOML template creation
@@ -61,7 +123,12 @@ func Save(filePath string, cfg *Config, version string) error {
 
 	// Create template with custom function
 	tmpl := template.New("config").Funcs(template.FuncMap{
-		"pad": padToColumn,
+		"pad":   padToColumn,
+		"list":  tomlStringList,
+		"zones": FormatSpeedZones,
+		"quote": tomlString,
+		"addr":  CanonicalBDAddr,
+		"nums":  tomlFloatList,
 	})
 
 	// Parse the template
@@ -70,14 +137,6 @@ func Save(filePath string, cfg *Config, version string) error {
 		return fmt.Errorf("failed to parse config template: %w", err)
 	}
 
-	// Create a new file
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0664)
-	if err != nil {
-		return fmt.Errorf("failed to create config file: %w", err)
-	}
-
-	defer f.Close()
-
 	// Create the template data
 	templateData := tomlContent{
 		Config:  cfg,
@@ -85,10 +144,27 @@ func Save(filePath string, cfg *Config, version string) error {
 	}
 
 	// Merge the data with the template
-	if err := tmpl.Execute(f, templateData); err != nil {
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, templateData); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
+	// Keep any content of the existing file the template doesn't write (unknown keys and tables,
+	// and user comments)
+	content := readExtraContent(filePath).merge(rendered.Bytes())
+
+	// Replace the file only once completely written (keeping the previous file as a backup)
+	err = writeFileAtomic(filePath, 0664, cfg.App.BackupCount, func(w io.Writer) error {
+
+		_, err := w.Write(content)
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+
 	return nil
 }
 
@@ -104,3 +180,31 @@ func padToColumn(kvPair string) string {
 
 	return strings.Repeat(" ", spacesNeeded)
 }
+
+// tomlFloatList formats a slice of numbers as a TOML array (e.g., "[12.25, 12.5]")
+func tomlFloatList(items []float64) string {
+
+	formatted := make([]string, len(items))
+	for i, item := range items {
+		formatted[i] = strconv.FormatFloat(item, 'f', -1, 64)
+	}
+
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+// tomlString formats a string as a TOML basic string using the TOML encoder (Go string escapes,
+// such as "\a" or "\x..", aren't valid TOML), replacing any invalid UTF-8, which TOML can't hold
+func tomlString(s string) string {
+	return tomlValue(strings.ToValidUTF8(s, "\uFFFD"))
+}
+
+// tomlStringList formats a slice of strings as a TOML array of basic strings
+func tomlStringList(items []string) string {
+
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = tomlString(item)
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
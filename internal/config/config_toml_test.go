@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/BurntSushi/toml"
 )
 
 // TestSave verifies that the Save function correctly generates a TOML file
@@ -69,10 +72,44 @@ func TestSave(t *testing.T) {
 			t.Error("Output failed float formatting check")
 		}
 
+		// Check string list formatting
+		if !strings.Contains(content, `media_player_args = ["hwdec=auto", "mute"]`) {
+			t.Error("Output failed string list formatting check")
+		}
+
 	})
 
 }
 
+// TestSaveEscapedStrings tests that strings holding characters that need escaping (including
+// invalid UTF-8) are saved as a session file that loads again
+func TestSaveEscapedStrings(t *testing.T) {
+
+	cfg := createTestConfig()
+	cfg.Video.MediaPlayerArgs = []string{"--title=bell\a\vtab\t\"quoted\"", "--bad=\xff"}
+	cfg.Hooks.OnLap = "printf 'lap\a\n' | logger \xfe"
+
+	tmpFile := filepath.Join(t.TempDir(), "escaped_test.toml")
+	if err := Save(tmpFile, cfg, "v1"); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded := &Config{}
+	if _, err := toml.DecodeFile(tmpFile, loaded); err != nil {
+		t.Fatalf("saved file doesn't load: %v", err)
+	}
+
+	wantArgs := []string{"--title=bell\a\vtab\t\"quoted\"", "--bad=\uFFFD"}
+	if !reflect.DeepEqual(loaded.Video.MediaPlayerArgs, wantArgs) {
+		t.Errorf("media_player_args = %q, want %q", loaded.Video.MediaPlayerArgs, wantArgs)
+	}
+
+	if want := "printf 'lap\a\n' | logger \uFFFD"; loaded.Hooks.OnLap != want {
+		t.Errorf("on_lap = %q, want %q", loaded.Hooks.OnLap, want)
+	}
+
+}
+
 // TestPadToColumn verifies the helper function used to align comments
 func TestPadToColumn(t *testing.T) {
 
@@ -105,6 +142,93 @@ func TestPadToColumn(t *testing.T) {
 
 }
 
+// TestSavePreservesExtras verifies that saving over an existing file keeps the keys, tables and
+// comments the config template doesn't write, and that saving again doesn't duplicate them
+func TestSavePreservesExtras(t *testing.T) {
+
+	cfg := createTestConfig()
+	tmpFile := filepath.Join(t.TempDir(), "extras_test.toml")
+
+	if err := Save(tmpFile, cfg, "v1"); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	// Hand-edit the file, adding unknown keys, tables and comments
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read back saved file: %v", err)
+	}
+
+	edited := strings.Replace(string(content), "[video.OSD]", "# Loaned to Sam until June\n  trainer_model = \"Kickr\"\n  future.mode = { level = 2 }\n\n[video.OSD]", 1)
+	edited = strings.Replace(edited, "[app]", "profile = \"weekday\"\n\n[app]", 1)
+	edited += "\n[plugins]\n  # Plugin settings\n  names = [\"strava\", \"zwift\"]\n  [plugins.strava]\n    token = \"abc\"\n"
+	writeTestFile(t, tmpFile, edited)
+
+	// Save twice (the second save must not duplicate the preserved content)
+	for _, version := range []string{"v2", "v3"} {
+
+		if err := Save(tmpFile, cfg, version); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+	}
+
+	content, err = os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read back saved file: %v", err)
+	}
+
+	saved := string(content)
+
+	var got struct {
+		Profile string `toml:"profile"`
+		Video   struct {
+			TrainerModel string `toml:"trainer_model"`
+			Future       struct {
+				Mode struct {
+					Level int `toml:"level"`
+				} `toml:"mode"`
+			} `toml:"future"`
+		} `toml:"video"`
+		Plugins struct {
+			Names  []string `toml:"names"`
+			Strava struct {
+				Token string `toml:"token"`
+			} `toml:"strava"`
+		} `toml:"plugins"`
+	}
+
+	if _, err := toml.Decode(saved, &got); err != nil {
+		t.Fatalf("saved file isn't valid TOML: %v\n%s", err, saved)
+	}
+
+	if got.Profile != "weekday" || got.Video.TrainerModel != "Kickr" || got.Video.Future.Mode.Level != 2 ||
+		len(got.Plugins.Names) != 2 || got.Plugins.Strava.Token != "abc" {
+		t.Errorf("saved file lost unknown keys, got %+v\n%s", got, saved)
+	}
+
+	// Define test cases
+	tests := []string{"# Loaned to Sam until June", "# Plugin settings", "# v3"}
+
+	// Run tests
+	for _, line := range tests {
+
+		if n := strings.Count(saved, line); n != 1 {
+			t.Errorf("saved file holds %q %d times, want once\n%s", line, n, saved)
+		}
+	}
+
+	// The known settings are still decoded from the saved file
+	loaded := &Config{}
+	if _, err := toml.Decode(saved, loaded); err != nil {
+		t.Fatalf("failed to decode saved file: %v", err)
+	}
+
+	if loaded.App.SessionTitle != cfg.App.SessionTitle {
+		t.Errorf("decoded session title = %q, want %q", loaded.App.SessionTitle, cfg.App.SessionTitle)
+	}
+
+}
+
 // createTestConfig returns a fully populated Config struct for testing
 func createTestConfig() *Config {
 
@@ -130,10 +254,16 @@ func createTestConfig() *Config {
 			WindowScaleFactor: 1.0,
 			UpdateIntervalSec: 0.5,
 			SpeedMultiplier:   1.0,
+			HardwareDecoding:  HWDecAuto,
+			MediaPlayerArgs:   []string{"hwdec=auto", "mute"},
+			Audio: VideoAudioConfig{
+				Volume: 75,
+			},
 			OnScreenDisplay: VideoOSDConfig{
 				DisplayCycleSpeed:    true,
 				DisplayPlaybackSpeed: false,
 				DisplayTimeRemaining: true,
+				UpdateIntervalSec:    0.5,
 				FontSize:             20,
 				MarginX:              10,
 				MarginY:              10,
@@ -1,11 +1,38 @@
 package config
 
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
 // Application name and version information
 const (
 	appName    = "BLE Sync Cycle"
 	appVersion = "v0.64.2"
 )
 
+// Build information, set at build time using ldflags (e.g., -ldflags "-X
+// github.com/richbl/go-ble-sync-cycle/internal/config.gitCommit=$(git rev-parse --short HEAD)")
+var (
+	gitCommit string
+	buildDate string
+)
+
+// Placeholder for build information that is not available
+const buildInfoUnknown = "unknown"
+
+// BuildInfo holds the version and build information of the application
+type BuildInfo struct {
+	Version   string
+	GitCommit string
+	BuildDate string
+	GoVersion string
+	Platform  string
+	Backends  []string
+}
+
 // GetVersion returns the current application version
 func GetVersion() string {
 	return appVersion
@@ -20,3 +47,71 @@ func GetAppName() string {
 func GetFullVersion() string {
 	return appName + " " + appVersion
 }
+
+// GetBuildInfo returns the version and build information of the application, falling back to
+// the version control information embedded by the Go toolchain when ldflags weren't used
+func GetBuildInfo() BuildInfo {
+
+	info := BuildInfo{
+		Version:   appVersion,
+		GitCommit: gitCommit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Backends:  backends(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+
+		for _, setting := range bi.Settings {
+
+			switch {
+			case setting.Key == "vcs.revision" && info.GitCommit == "":
+				info.GitCommit = setting.Value
+
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+
+			case setting.Key == "vcs.modified" && setting.Value == "true" && gitCommit == "" && info.GitCommit != "":
+				info.GitCommit += "-dirty"
+			}
+		}
+	}
+
+	if info.GitCommit == "" {
+		info.GitCommit = buildInfoUnknown
+	}
+
+	if info.BuildDate == "" {
+		info.BuildDate = buildInfoUnknown
+	}
+
+	return info
+}
+
+// String returns the build information, one item per line
+func (b BuildInfo) String() string {
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s %s\n", appName, b.Version)
+	fmt.Fprintf(&sb, "  Git commit: %s\n", b.GitCommit)
+	fmt.Fprintf(&sb, "  Build date: %s\n", b.BuildDate)
+	fmt.Fprintf(&sb, "  Go version: %s (%s)\n", b.GoVersion, b.Platform)
+	fmt.Fprintf(&sb, "  Backends:   %s\n", strings.Join(b.Backends, ", "))
+
+	return sb.String()
+}
+
+// backends returns the media player and BLE backends enabled in this build
+func backends() []string {
+
+	enabled := []string{MediaPlayerMPV + " (media player)"}
+
+	// BLE access on Linux uses BlueZ over the D-Bus system bus
+	if runtime.GOOS == "linux" {
+		enabled = append(enabled, "dbus (BlueZ BLE)")
+	}
+
+	return enabled
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestGetBuildInfo tests the build information, with and without build-time ldflags
+func TestGetBuildInfo(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		commit     string
+		date       string
+		wantCommit string
+		wantDate   string
+	}{
+		{"ldflags", "abc1234", "2026-01-02T03:04:05Z", "abc1234", "2026-01-02T03:04:05Z"},
+		{"no ldflags", "", "", buildInfoUnknown, buildInfoUnknown},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			origCommit, origDate := gitCommit, buildDate
+			defer func() { gitCommit, buildDate = origCommit, origDate }()
+
+			gitCommit, buildDate = tt.commit, tt.date
+
+			info := GetBuildInfo()
+
+			// Test binaries don't embed version control information, so the placeholders are used
+			if info.GitCommit != tt.wantCommit {
+				t.Errorf("GetBuildInfo() GitCommit = %q, want %q", info.GitCommit, tt.wantCommit)
+			}
+
+			if info.BuildDate != tt.wantDate {
+				t.Errorf("GetBuildInfo() BuildDate = %q, want %q", info.BuildDate, tt.wantDate)
+			}
+
+			if info.Version != GetVersion() || info.GoVersion != runtime.Version() {
+				t.Errorf("GetBuildInfo() = %+v, want version %s and Go version %s", info, GetVersion(), runtime.Version())
+			}
+
+			if !strings.Contains(info.String(), "Git commit: "+tt.wantCommit) {
+				t.Errorf("BuildInfo.String() missing git commit:\n%s", info.String())
+			}
+
+		})
+	}
+
+	if info := GetBuildInfo(); len(info.Backends) == 0 || !strings.HasPrefix(info.Backends[0], MediaPlayerMPV) {
+		t.Errorf("GetBuildInfo() Backends = %v, want %s first", info.Backends, MediaPlayerMPV)
+	}
+
+}
@@ -3,6 +3,40 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valid values of the video settings with a fixed set of values
+var (
+	validPlayer = map[string]bool{
+		MediaPlayerMPV: true,
+	}
+
+	validHWDec = map[string]bool{
+		HWDecAuto:  true,
+		HWDecVAAPI: true,
+		HWDecNVDEC: true,
+		HWDecOff:   true,
+	}
+
+	validFocusMode = map[string]bool{
+		FocusModeOff:  true,
+		FocusModeHide: true,
+	}
+
+	validAlignX = map[string]bool{
+		"left":   true,
+		"center": true,
+		"right":  true,
+	}
+
+	validAlignY = map[string]bool{
+		"top":    true,
+		"center": true,
+		"bottom": true,
+	}
 )
 
 // DisplayValidationResult captures the results of the Wayland display validation
@@ -14,29 +48,66 @@ type DisplayValidationResult struct {
 
 // VideoConfig defines video playback and display settings from the TOML config file
 type VideoConfig struct {
-	MediaPlayer       string                  `toml:"media_player"`
-	FilePath          string                  `toml:"file_path"`
-	SeekToPosition    string                  `toml:"seek_to_position"`
-	WindowScaleFactor float64                 `toml:"window_scale_factor"`
-	UpdateIntervalSec float64                 `toml:"update_interval_secs"`
-	SpeedMultiplier   float64                 `toml:"speed_multiplier"`
-	TargetDisplayName string                  `toml:"target_display_name"`
-	AutoResume        bool                    `toml:"auto_resume"`
-	OnScreenDisplay   VideoOSDConfig          `toml:"OSD"`
-	ValidationResult  DisplayValidationResult `toml:"-"`
+	MediaPlayer        string                  `toml:"media_player"`
+	FilePath           string                  `toml:"file_path"`
+	SeekToPosition     string                  `toml:"seek_to_position"`
+	WindowScaleFactor  float64                 `toml:"window_scale_factor"`
+	WindowPosition     string                  `toml:"window_position"`
+	UpdateIntervalSec  float64                 `toml:"update_interval_secs"`
+	SpeedMultiplier    float64                 `toml:"speed_multiplier"`
+	MinPlaybackSpeed   float64                 `toml:"min_playback_speed"`
+	MaxPlaybackSpeed   float64                 `toml:"max_playback_speed"`
+	InterpolationSpeed float64                 `toml:"frame_interpolation_speed"`
+	TargetDisplayName  string                  `toml:"target_display_name"`
+	FocusMode          string                  `toml:"focus_mode"`
+	AutoResume         bool                    `toml:"auto_resume"`
+	ResumeDistance     bool                    `toml:"resume_distance"`
+	ResumeDistanceKM   float64                 `toml:"resume_distance_km"` // Distance ridden so far (saved with the auto-resume position)
+	ResumeSpeeds       []float64               `toml:"resume_speeds"`      // Smoothed speeds at stop (saved with the auto-resume position)
+	HardwareDecoding   string                  `toml:"hardware_decoding"`
+	MediaPlayerArgs    []string                `toml:"media_player_args"`
+	SubtitlePath       string                  `toml:"subtitle_path"`
+	ShowSubtitles      bool                    `toml:"show_subtitles"`
+	LowPower           bool                    `toml:"low_power"`
+	VideoFirstStart    bool                    `toml:"video_first_start"`
+	MeasureLatency     bool                    `toml:"-"` // Measure speed update latency (set from the command-line)
+	StartCountdownSecs int                     `toml:"start_countdown_secs"`
+	ResumeFadeSecs     float64                 `toml:"resume_fade_secs"`
+	OnScreenDisplay    VideoOSDConfig          `toml:"OSD"`
+	Audio              VideoAudioConfig        `toml:"audio"`
+	ValidationResult   DisplayValidationResult `toml:"-"`
 }
 
 // VideoOSDConfig defines on-screen display settings for video playback from the TOML config file
 type VideoOSDConfig struct {
-	FontSize             int    `toml:"font_size"`
-	MarginX              int    `toml:"margin_x"`
-	MarginY              int    `toml:"margin_y"`
-	AlignX               string `toml:"align_x"`
-	AlignY               string `toml:"align_y"`
-	DisplayCycleSpeed    bool   `toml:"display_cycle_speed"`
-	DisplayPlaybackSpeed bool   `toml:"display_playback_speed"`
-	DisplayTimeRemaining bool   `toml:"display_time_remaining"`
-	ShowOSD              bool   `toml:"-"`
+	FontSize              int     `toml:"font_size"`
+	MarginX               int     `toml:"margin_x"`
+	MarginY               int     `toml:"margin_y"`
+	AlignX                string  `toml:"align_x"`
+	AlignY                string  `toml:"align_y"`
+	DisplayCycleSpeed     bool    `toml:"display_cycle_speed"`
+	DisplayPlaybackSpeed  bool    `toml:"display_playback_speed"`
+	DisplayTimeRemaining  bool    `toml:"display_time_remaining"`
+	DisplaySpeedStats     bool    `toml:"display_speed_stats"`
+	DisplayMetricsOverlay bool    `toml:"display_metrics_overlay"`
+	UpdateIntervalSec     float64 `toml:"osd_update_interval_secs"`
+	NightModeHours        string  `toml:"night_mode_hours"`
+	NightBrightness       int     `toml:"night_brightness"`
+	ShowOSD               bool    `toml:"-"`
+}
+
+// VideoAudioConfig defines audio playback settings from the TOML config file
+type VideoAudioConfig struct {
+	Volume          int  `toml:"volume"`
+	Mute            bool `toml:"mute"`
+	AudioTrack      int  `toml:"audio_track"`
+	PitchCorrection bool `toml:"pitch_correction"`
+}
+
+// FocusEnabled returns true if the GUI is hidden while the session runs, with the video played
+// full screen on the target display
+func (vc *VideoConfig) FocusEnabled() bool {
+	return vc.FocusMode == FocusModeHide
 }
 
 // validate checks VideoConfig for valid settings
@@ -46,24 +117,21 @@ func (vc *VideoConfig) validate() error {
 		return err
 	}
 
-	validPlayer := map[string]bool{
-		MediaPlayerMPV: true,
+	if !validPlayer[vc.MediaPlayer] {
+		return fmt.Errorf(errFormatRev, errInvalidPlayer, vc.MediaPlayer)
 	}
 
-	validAlignX := map[string]bool{
-		"left":   true,
-		"center": true,
-		"right":  true,
+	if !validHWDec[vc.HardwareDecoding] {
+		return fmt.Errorf(errFormatRev, errInvalidHWDec, vc.HardwareDecoding)
 	}
 
-	validAlignY := map[string]bool{
-		"top":    true,
-		"center": true,
-		"bottom": true,
+	// Session files created before focus_mode was added don't rearrange the GUI
+	if vc.FocusMode == "" {
+		vc.FocusMode = FocusModeOff
 	}
 
-	if !validPlayer[vc.MediaPlayer] {
-		return fmt.Errorf(errFormatRev, errInvalidPlayer, vc.MediaPlayer)
+	if !validFocusMode[vc.FocusMode] {
+		return fmt.Errorf(errFormatRev, errInvalidFocusMode, vc.FocusMode)
 	}
 
 	if !validAlignX[vc.OnScreenDisplay.AlignX] {
@@ -78,13 +146,35 @@ func (vc *VideoConfig) validate() error {
 		return err
 	}
 
+	// A playback speed minimum can't exceed the maximum (where 0.0 = no minimum or maximum)
+	if vc.MaxPlaybackSpeed > 0 && vc.MinPlaybackSpeed > vc.MaxPlaybackSpeed {
+		return fmt.Errorf(errFormatRev, errPlaybackSpeedRange, fmt.Sprintf("%.2f > %.2f", vc.MinPlaybackSpeed, vc.MaxPlaybackSpeed))
+	}
+
 	if !validateTimeFormat(vc.SeekToPosition) {
 		return fmt.Errorf(errFormatRev, errInvalidSeek, vc.SeekToPosition)
 	}
 
+	if _, _, ok := ParseWindowPosition(vc.WindowPosition); !ok && vc.WindowPosition != "" {
+		return fmt.Errorf(errFormatRev, errWindowPosition, vc.WindowPosition)
+	}
+
+	if err := vc.OnScreenDisplay.validateNightMode(); err != nil {
+		return err
+	}
+
+	if err := validateMediaPlayerArgs(vc.MediaPlayerArgs); err != nil {
+		return err
+	}
+
+	if err := checkForSubtitleFile(vc.SubtitlePath); err != nil {
+		return err
+	}
+
 	// Compute ShowOSD state based on display settings in TOML config file
 	vc.OnScreenDisplay.ShowOSD = vc.OnScreenDisplay.DisplayCycleSpeed ||
-		vc.OnScreenDisplay.DisplayPlaybackSpeed || vc.OnScreenDisplay.DisplayTimeRemaining
+		vc.OnScreenDisplay.DisplayPlaybackSpeed || vc.OnScreenDisplay.DisplayTimeRemaining ||
+		vc.OnScreenDisplay.DisplaySpeedStats
 
 	return nil
 }
@@ -93,14 +183,162 @@ func (vc *VideoConfig) validate() error {
 func (vc *VideoConfig) configValidationRanges() *[]validationRange {
 
 	return &[]validationRange{
-		{vc.WindowScaleFactor, 0.1, 1.0, errWindowScale},
-		{vc.UpdateIntervalSec, 0.1, 3.0, errInvalidInterval},
-		{vc.SpeedMultiplier, 0.1, 1.5, errSpeedMultiplier},
-		{vc.OnScreenDisplay.FontSize, 10, 200, errFontSize},
-		{vc.OnScreenDisplay.MarginX, 0, 300, errOSDMargin},
-		{vc.OnScreenDisplay.MarginY, 0, 600, errOSDMargin},
+		{"video.window_scale_factor", vc.WindowScaleFactor, 0.1, 1.0, errWindowScale},
+		{"video.update_interval_secs", vc.UpdateIntervalSec, 0.1, 3.0, errInvalidInterval},
+		{"video.speed_multiplier", vc.SpeedMultiplier, 0.1, 1.5, errSpeedMultiplier},
+		{"video.min_playback_speed", vc.MinPlaybackSpeed, 0.0, 1.0, errMinPlaybackSpeed},
+		{"video.max_playback_speed", vc.MaxPlaybackSpeed, 0.0, 10.0, errMaxPlaybackSpeed},
+		{"video.frame_interpolation_speed", vc.InterpolationSpeed, 0.0, 1.0, errInterpolationSpeed},
+		{"video.start_countdown_secs", vc.StartCountdownSecs, 0, 30, errStartCountdown},
+		{"video.resume_fade_secs", vc.ResumeFadeSecs, 0.0, 5.0, errResumeFade},
+		{"video.resume_distance_km", vc.ResumeDistanceKM, 0.0, 100000.0, errResumeDistance},
+		{"video.OSD.font_size", vc.OnScreenDisplay.FontSize, 10, 200, errFontSize},
+		{"video.OSD.margin_x", vc.OnScreenDisplay.MarginX, 0, 300, errOSDMargin},
+		{"video.OSD.margin_y", vc.OnScreenDisplay.MarginY, 0, 600, errOSDMargin},
+		{"video.OSD.osd_update_interval_secs", vc.OnScreenDisplay.UpdateIntervalSec, 0.0, 10.0, errOSDInterval},
+		{"video.audio.volume", vc.Audio.Volume, 0, 100, errVolume},
+		{"video.audio.audio_track", vc.Audio.AudioTrack, 0, 99, errAudioTrack},
+	}
+
+}
+
+// checkForSubtitleFile checks that the subtitle file exists, if one is provided
+func checkForSubtitleFile(filename string) error {
+
+	if filename == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(filename); err != nil {
+		return fmt.Errorf(errFormat, errSubtitleFile, err)
+	}
+
+	return nil
+}
+
+// validateMediaPlayerArgs checks that each media player argument is in "option" or "option=value" form
+func validateMediaPlayerArgs(args []string) error {
+
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(arg), "--"), "=")
+
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf(errFormatRev, errInvalidPlayerArg, arg)
+		}
+	}
+
+	return nil
+}
+
+// ParseMediaPlayerArg splits a media player argument into its option name and value, where an
+// option without a value (e.g., "--mute") is treated as a flag set to "yes"
+func ParseMediaPlayerArg(arg string) (string, string) {
+
+	name, value, found := strings.Cut(strings.TrimPrefix(strings.TrimSpace(arg), "--"), "=")
+	if !found {
+		value = "yes"
+	}
+
+	return name, value
+}
+
+// ParseMediaPlayerArgs parses media player arguments written as space-separated options (e.g.,
+// "hwdec=auto --mute"), returning an error if any argument isn't a valid option
+func ParseMediaPlayerArgs(text string) ([]string, error) {
+
+	args := strings.Fields(text)
+
+	if err := validateMediaPlayerArgs(args); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// FormatMediaPlayerArgs formats media player arguments as space-separated options
+func FormatMediaPlayerArgs(args []string) string {
+	return strings.Join(args, " ")
+}
+
+// ParseWindowPosition parses a window position in "X,Y" form (in pixels from the top-left corner
+// of the screen), returning false if the position is empty or invalid
+func ParseWindowPosition(pos string) (int, int, bool) {
+
+	xStr, yStr, found := strings.Cut(strings.TrimSpace(pos), ",")
+	if !found {
+		return 0, 0, false
+	}
+
+	x, errX := strconv.Atoi(strings.TrimSpace(xStr))
+	y, errY := strconv.Atoi(strings.TrimSpace(yStr))
+
+	if errX != nil || errY != nil || x < 0 || y < 0 {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
+
+// validateNightMode checks the night mode hours and OSD brightness (if night mode is used)
+func (oc *VideoOSDConfig) validateNightMode() error {
+
+	if oc.NightModeHours == "" {
+		return nil
+	}
+
+	if _, _, ok := ParseNightModeHours(oc.NightModeHours); !ok {
+		return fmt.Errorf(errFormatRev, errNightModeHours, oc.NightModeHours)
+	}
+
+	return validateConfigFields(oc.nightModeValidationRanges())
+}
+
+// nightModeValidationRanges returns validation ranges for the night mode of VideoOSDConfig
+func (oc *VideoOSDConfig) nightModeValidationRanges() *[]validationRange {
+
+	return &[]validationRange{
+		{"video.OSD.night_brightness", oc.NightBrightness, 10, 100, errNightBrightness},
+	}
+
+}
+
+// ParseNightModeHours parses the hours of the night mode in "START-END" form (hours of the day,
+// 0-23), where the night mode may run past midnight (e.g., "21-7"), returning false if the hours
+// are empty or invalid
+func ParseNightModeHours(hours string) (int, int, bool) {
+
+	startStr, endStr, found := strings.Cut(strings.TrimSpace(hours), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, errStart := strconv.Atoi(strings.TrimSpace(startStr))
+	end, errEnd := strconv.Atoi(strings.TrimSpace(endStr))
+
+	if errStart != nil || errEnd != nil || start < 0 || start > 23 || end < 0 || end > 23 || start == end {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// NightModeAt returns true if the night mode (a dimmed OSD, and the dark GUI theme) is on at the
+// given time
+func (oc *VideoOSDConfig) NightModeAt(t time.Time) bool {
+
+	start, end, ok := ParseNightModeHours(oc.NightModeHours)
+	if !ok {
+		return false
+	}
+
+	hour := t.Hour()
+
+	// Night mode running past midnight
+	if start > end {
+		return hour >= start || hour < end
 	}
 
+	return hour >= start && hour < end
 }
 
 // checkForVideoFile checks if the provided file exists
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Backup file settings
+const (
+	backupSuffix   = ".bak"
+	maxBackupCount = 10
+)
+
+// writeFileAtomic writes a file by way of a temporary file in the same directory that replaces
+// the file only once completely written, so a failed (or interrupted) write never leaves a
+// partially written file behind. The previous content of the file is first kept in up to backups
+// backup files (see BackupPath)
+func writeFileAtomic(path string, perm fs.FileMode, backups int, write func(w io.Writer) error) error {
+
+	// An existing file keeps its permissions
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to create temporary file", err)
+	}
+
+	// The temporary file is removed unless it replaces the file
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf(errFormat, "failed to sync temporary file", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf(errFormat, "failed to close temporary file", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf(errFormat, "failed to set file permissions", err)
+	}
+
+	if err := rotateBackups(path, backups); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf(errFormat, "failed to replace file", err)
+	}
+
+	return nil
+}
+
+// BackupPath returns the path of a backup of a file, where backup 1 is the most recent backup
+// (e.g., "ride.toml.bak", "ride.toml.bak.2", ...)
+func BackupPath(path string, n int) string {
+
+	if n <= 1 {
+		return path + backupSuffix
+	}
+
+	return path + backupSuffix + "." + strconv.Itoa(n)
+}
+
+// rotateBackups keeps the current content of a file as its most recent backup, shifting older
+// backups along and removing any backups beyond the number of backups to keep
+func rotateBackups(path string, backups int) error {
+
+	backups = min(max(backups, 0), maxBackupCount)
+
+	// Remove backups no longer kept (e.g., after the number of backups to keep was reduced)
+	for n := backups + 1; n <= maxBackupCount; n++ {
+
+		if err := os.Remove(BackupPath(path, n)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf(errFormat, "failed to remove backup file", err)
+		}
+	}
+
+	if backups == 0 {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // Nothing to back up
+	}
+
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to read file for backup", err)
+	}
+
+	for n := backups - 1; n >= 1; n-- {
+
+		if err := os.Rename(BackupPath(path, n), BackupPath(path, n+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf(errFormat, "failed to rotate backup file", err)
+		}
+	}
+
+	if err := os.WriteFile(BackupPath(path, 1), content, 0600); err != nil {
+		return fmt.Errorf(errFormat, "failed to write backup file", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var errTestWrite = errors.New("test write error")
+
+// TestSaveBackups tests that saving a config file keeps the configured number of backups of the
+// previous file content
+func TestSaveBackups(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ride.toml")
+	cfg := createTestConfig()
+	cfg.App.BackupCount = 2
+
+	// Save four versions of the file (only the two most recent previous versions are kept)
+	for _, version := range []string{"v1", "v2", "v3", "v4"} {
+
+		if err := Save(path, cfg, version); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+	}
+
+	// Define test cases
+	tests := []struct {
+		path    string
+		version string
+	}{
+		{path, "# v4"},
+		{BackupPath(path, 1), "# v3"},
+		{BackupPath(path, 2), "# v2"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+
+			content, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", tt.path, err)
+			}
+
+			if got := string(content); !containsLine(got, tt.version) {
+				t.Errorf("%s is missing version header %q", tt.path, tt.version)
+			}
+
+		})
+	}
+
+	if _, err := os.Stat(BackupPath(path, 3)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backup 3 exists, want only 2 backups kept")
+	}
+
+	// Reducing the number of backups removes the backups no longer kept
+	cfg.App.BackupCount = 0
+
+	if err := Save(path, cfg, "v5"); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	for n := 1; n <= 2; n++ {
+
+		if _, err := os.Stat(BackupPath(path, n)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("backup %d exists, want no backups kept", n)
+		}
+	}
+
+}
+
+// TestWriteFileAtomicFailure tests that a failed write leaves the existing file (and no temporary
+// file) behind
+func TestWriteFileAtomicFailure(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ride.toml")
+	writeTestFile(t, path, "original")
+
+	err := writeFileAtomic(path, 0600, 1, func(w io.Writer) error {
+
+		_, _ = io.WriteString(w, "partial")
+
+		return errTestWrite
+	})
+
+	if !errors.Is(err, errTestWrite) {
+		t.Fatalf("writeFileAtomic() error = %v, want %v", err, errTestWrite)
+	}
+
+	if content, _ := os.ReadFile(path); string(content) != "original" {
+		t.Errorf("file content = %q, want %q", content, "original")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only the original file", len(entries))
+	}
+
+}
+
+// containsLine returns true if the content holds the given line
+func containsLine(content, line string) bool {
+
+	for l := range strings.Lines(content) {
+
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,286 @@
+package crashreport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Crash report bundle settings
+const (
+	bundlePrefix     = "bsc-crash-"
+	bundleTimeLayout = "20060102-150405"
+	redacted         = "<redacted>"
+	commandTimeout   = 2 * time.Second // Longest wait for a system command (e.g., bluetoothd --version)
+)
+
+// Report describes a crash (a panic or a fatal error) written into a crash report bundle
+type Report struct {
+	Reason string // Panic value or fatal error
+	Stack  []byte // Stack trace of the panic (nil for a fatal error)
+}
+
+// bundleEntry is a file written into a crash report bundle
+type bundleEntry struct {
+	name    string
+	content string
+}
+
+// Package variables
+var (
+	configSource atomic.Pointer[func() *config.Config]
+	latestBundle atomic.Pointer[string]
+)
+
+// SetConfigSource sets the function returning the configuration of the running session (or nil
+// if there's none), written (redacted) into crash report bundles
+func SetConfigSource(source func() *config.Config) {
+	configSource.Store(&source)
+}
+
+// Generate writes a crash report bundle into the log directory, returning the bundle path
+func Generate(report Report) (string, error) {
+
+	logDir, err := config.LogDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	path := filepath.Join(logDir, bundlePrefix+time.Now().Format(bundleTimeLayout)+".zip")
+
+	if err := Write(path, report, sessionConfig()); err != nil {
+		return "", err
+	}
+
+	latestBundle.Store(&path)
+
+	return path, nil
+}
+
+// TakeLatest returns the path of the crash report bundle generated since the last call (if any)
+func TakeLatest() (string, bool) {
+
+	path := latestBundle.Swap(nil)
+	if path == nil {
+		return "", false
+	}
+
+	return *path, true
+}
+
+// Write writes a crash report bundle to path, including the (redacted) session configuration if
+// cfg isn't nil
+func Write(path string, report Report, cfg *config.Config) error {
+
+	entries := []bundleEntry{
+		{"crash.txt", crashText(report)},
+		{"version.txt", config.GetBuildInfo().String()},
+		{"system.txt", systemInfo()},
+		{"log.txt", redactLog(logger.RecentLog())},
+	}
+
+	if cfg != nil {
+		entries = append(entries, bundleEntry{"config.toml", redactedConfig(cfg)})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, entry := range entries {
+
+		w, err := archive.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to crash report: %w", entry.name, err)
+		}
+
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			return fmt.Errorf("failed to add %s to crash report: %w", entry.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	return nil
+}
+
+// sessionConfig returns the configuration of the running session (nil if there's none)
+func sessionConfig() *config.Config {
+
+	source := configSource.Load()
+	if source == nil || *source == nil {
+		return nil
+	}
+
+	return (*source)()
+}
+
+// crashText describes the crash: its time, reason, and stack trace (if any)
+func crashText(report Report) string {
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Time:   %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Reason: %s\n", report.Reason)
+
+	if len(report.Stack) > 0 {
+		fmt.Fprintf(&sb, "\nStack trace:\n%s", report.Stack)
+	}
+
+	return sb.String()
+}
+
+// systemInfo describes the operating system and Bluetooth stack, and any Bluetooth permission
+// issues
+func systemInfo() string {
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "OS:        %s\n", osRelease())
+	fmt.Fprintf(&sb, "Kernel:    %s\n", readTrimmed("/proc/sys/kernel/osrelease"))
+	fmt.Fprintf(&sb, "BlueZ:     %s\n", commandOutput("bluetoothd", "--version"))
+
+	adapters, _ := filepath.Glob("/sys/class/bluetooth/hci*")
+	for i, adapter := range adapters {
+		adapters[i] = filepath.Base(adapter)
+	}
+
+	fmt.Fprintf(&sb, "Adapters:  %s\n", strings.Join(adapters, ", "))
+
+	for _, issue := range ble.CheckPermissions() {
+		fmt.Fprintf(&sb, "Bluetooth issue: %s (to fix, %s)\n", issue.Problem, issue.Remedy)
+	}
+
+	return sb.String()
+}
+
+// osRelease returns the name of the operating system (Linux distribution), if known
+func osRelease() string {
+
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return "unknown"
+	}
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+
+		if value, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"`)
+		}
+	}
+
+	return "unknown"
+}
+
+// readTrimmed returns the trimmed content of a file, or "unknown" if it can't be read
+func readTrimmed(path string) string {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// commandOutput returns the trimmed output of a system command, or "unknown" if it fails
+func commandOutput(name string, args ...string) string {
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "unknown"
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// redactedConfig returns the session configuration as TOML, with sensitive fields redacted
+func redactedConfig(cfg *config.Config) string {
+
+	redactedCfg := *cfg
+
+	redactedCfg.BLE.SensorBDAddr = redactBDAddr(cfg.BLE.SensorBDAddr)
+	redactedCfg.BLE.EnvSensorBDAddr = redactBDAddr(cfg.BLE.EnvSensorBDAddr)
+	redactedCfg.Video.FilePath = redactPath(cfg.Video.FilePath)
+	redactedCfg.Video.SubtitlePath = redactPath(cfg.Video.SubtitlePath)
+	redactedCfg.Race.OutdoorGPXFile = redactPath(cfg.Race.OutdoorGPXFile)
+
+	for _, command := range []*string{&redactedCfg.Hooks.OnSessionStart, &redactedCfg.Hooks.OnSessionStop,
+		&redactedCfg.Hooks.OnLap, &redactedCfg.Hooks.OnSensorLost} {
+
+		if *command != "" {
+			*command = redacted
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(redactedCfg); err != nil {
+		return fmt.Sprintf("# failed to encode session configuration: %v\n", err)
+	}
+
+	return buf.String()
+}
+
+// redactBDAddr redacts the device-specific half of a BD_ADDR, keeping the manufacturer half (OUI),
+// which identifies the sensor vendor (e.g., "C3:4F:7A:XX:XX:XX")
+func redactBDAddr(addr string) string {
+
+	if addr == "" {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimSpace(addr), ":")
+	if len(parts) != 6 {
+		return redacted
+	}
+
+	return strings.Join(parts[:3], ":") + ":XX:XX:XX"
+}
+
+// redactLog redacts the BD_ADDRs (see redactBDAddr) and the user home directory (which may include
+// the user name) found in log messages
+func redactLog(text string) string {
+
+	text = config.ReplaceBDAddrs(text, redactBDAddr)
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		text = strings.ReplaceAll(text, home, "~")
+	}
+
+	return text
+}
+
+// redactPath redacts the directory of a file path (which may include the user name), keeping the
+// file name
+func redactPath(path string) string {
+
+	if path == "" {
+		return ""
+	}
+
+	return filepath.Join(redacted, filepath.Base(path))
+}
@@ -0,0 +1,160 @@
+package crashreport
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// init is called to set the log level for tests
+func init() {
+	logger.Initialize("debug")
+}
+
+// TestRedact tests the redaction of sensitive session configuration values
+func TestRedact(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name   string
+		redact func(string) string
+		input  string
+		want   string
+	}{
+		{"BD_ADDR", redactBDAddr, "C3:4F:7A:12:34:56", "C3:4F:7A:XX:XX:XX"},
+		{"invalid BD_ADDR", redactBDAddr, "not-an-address", redacted},
+		{"empty BD_ADDR", redactBDAddr, "", ""},
+		{"file path", redactPath, "/home/rider/videos/alps.mp4", filepath.Join(redacted, "alps.mp4")},
+		{"empty file path", redactPath, "", ""},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := tt.redact(tt.input); got != tt.want {
+				t.Errorf("redact(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestRedactLog tests the redaction of BD_ADDRs and the user home directory in log messages
+func TestRedactLog(t *testing.T) {
+
+	t.Setenv("HOME", "/home/rider")
+
+	// Define test cases
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"BD_ADDR", "connected to BLE sensor C3:4F:7A:12:34:56", "connected to BLE sensor C3:4F:7A:XX:XX:XX"},
+		{"lower case BD_ADDR", "scanning for c3-4f-7a-12-34-56...", "scanning for C3:4F:7A:XX:XX:XX..."},
+		{"home path", "loading video /home/rider/videos/alps.mp4", "loading video ~/videos/alps.mp4"},
+		{"nothing to redact", "speed: 12.34 mph", "speed: 12.34 mph"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := redactLog(tt.input); got != tt.want {
+				t.Errorf("redactLog(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestWrite tests that a crash report bundle holds the crash details and redacted configuration
+func TestWrite(t *testing.T) {
+
+	cfg := &config.Config{}
+	cfg.BLE.SensorBDAddr = "C3:4F:7A:12:34:56"
+	cfg.Video.FilePath = "/home/rider/videos/alps.mp4"
+	cfg.Race.OutdoorGPXFile = "/home/rider/rides/alps.gpx"
+	cfg.Hooks.OnLap = "curl -H 'Authorization: secret' https://example.com"
+
+	logger.Info(logger.BackgroundCtx, logger.APP, "message before the crash")
+	logger.Info(logger.BackgroundCtx, logger.BLE, "connected to BLE sensor C3:4F:7A:12:34:56")
+
+	path := filepath.Join(t.TempDir(), "crash.zip")
+	if err := Write(path, Report{Reason: "test panic", Stack: []byte("goroutine 1 [running]")}, cfg); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	files := readBundle(t, path)
+
+	for _, name := range []string{"crash.txt", "version.txt", "system.txt", "log.txt", "config.toml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("crash report is missing %s", name)
+		}
+	}
+
+	if !strings.Contains(files["crash.txt"], "test panic") || !strings.Contains(files["crash.txt"], "goroutine 1") {
+		t.Errorf("crash.txt = %q, want the crash reason and stack trace", files["crash.txt"])
+	}
+
+	if !strings.Contains(files["log.txt"], "message before the crash") {
+		t.Error("log.txt is missing the recent log messages")
+	}
+
+	if strings.Contains(files["log.txt"], "12:34:56") {
+		t.Error("log.txt contains a BD_ADDR, want it redacted")
+	}
+
+	if !strings.Contains(files["config.toml"], "alps.gpx") {
+		t.Error("config.toml is missing the outdoor GPX file name")
+	}
+
+	for _, secret := range []string{"12:34:56", "/home/rider", "secret"} {
+		if strings.Contains(files["config.toml"], secret) {
+			t.Errorf("config.toml contains %q, want it redacted", secret)
+		}
+	}
+
+}
+
+// readBundle returns the content of each file in a crash report bundle
+func readBundle(t *testing.T, path string) map[string]string {
+
+	t.Helper()
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open crash report: %v", err)
+	}
+
+	defer archive.Close()
+
+	files := make(map[string]string)
+
+	for _, file := range archive.File {
+
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+
+		content, err := io.ReadAll(r)
+		r.Close()
+
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
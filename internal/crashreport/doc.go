@@ -0,0 +1,12 @@
+// Package crashreport generates crash report bundles for BLE Sync Cycle (BSC)
+//
+// When BSC panics or exits on a fatal error, this package writes a diagnostic bundle (a zip
+// archive) into the log directory, to be attached when filing an issue:
+//
+// - The crash reason (panic value or fatal error) and stack trace
+// - The BSC version and build information
+// - The operating system and Bluetooth stack (BlueZ) details, and any Bluetooth permission issues
+// - The most recent log messages
+// - The session configuration, with sensitive fields (sensor addresses, file paths and hook
+// commands) redacted
+package crashreport
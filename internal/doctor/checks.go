@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Default root of the sysfs filesystem
+const defaultSysfsRoot = "/sys"
+
+// Checks returns the BSC environment diagnostic checks, in the order they should be run
+func Checks(opts Options) []Check {
+
+	if opts.SysfsRoot == "" {
+		opts.SysfsRoot = defaultSysfsRoot
+	}
+
+	// The video checks use the video file from the session configuration (once it's loaded)
+	var videoFile string
+
+	return []Check{
+		{Name: "Bluetooth adapter", Run: func(_ context.Context) Result { return checkAdapterPresent(opts.SysfsRoot) }},
+		{Name: "Bluetooth adapter blocking", Run: func(_ context.Context) Result { return checkAdapterBlocked(opts.SysfsRoot) }},
+		{Name: "BlueZ access", Run: func(_ context.Context) Result { return checkBlueZ(opts.EnableAdapter) }},
+		{Name: "Media player", Run: func(_ context.Context) Result { return checkMediaPlayer(opts.ProbeMediaPlayer) }},
+		{Name: "Session configuration", Run: func(_ context.Context) Result {
+
+			result, file := checkConfig(opts.ConfigFile, opts.LoadConfig)
+			videoFile = file
+
+			return result
+		}},
+		{Name: "Video file", Run: func(_ context.Context) Result { return checkVideoReadable(videoFile) }},
+		{Name: "Video decoding", Run: func(_ context.Context) Result { return checkVideoDecoding(videoFile, opts.ProbeVideoFile) }},
+		{Name: "Configuration directory", Run: func(_ context.Context) Result { return checkConfigDirWritable(opts.ConfigFile) }},
+	}
+}
+
+// checkAdapterPresent checks that the kernel reports at least one Bluetooth adapter
+func checkAdapterPresent(sysfsRoot string) Result {
+
+	adapters, _ := filepath.Glob(filepath.Join(sysfsRoot, "class", "bluetooth", "hci*"))
+	if len(adapters) == 0 {
+		return fail("no Bluetooth adapter found", "Connect a Bluetooth 4.0+ (BLE) adapter, and confirm that it's listed by 'bluetoothctl list'")
+	}
+
+	names := make([]string, len(adapters))
+	for i, adapter := range adapters {
+		names[i] = filepath.Base(adapter)
+	}
+
+	return ok("found " + strings.Join(names, ", "))
+}
+
+// checkAdapterBlocked checks that no Bluetooth adapter is blocked by rfkill
+func checkAdapterBlocked(sysfsRoot string) Result {
+
+	switches, _ := filepath.Glob(filepath.Join(sysfsRoot, "class", "rfkill", "rfkill*"))
+
+	for _, sw := range switches {
+
+		if readSysfs(filepath.Join(sw, "type")) != "bluetooth" {
+			continue
+		}
+
+		if readSysfs(filepath.Join(sw, "hard")) == "1" {
+			return fail("Bluetooth is blocked by a hardware switch", "Turn off airplane mode (or the adapter's hardware switch)")
+		}
+
+		if readSysfs(filepath.Join(sw, "soft")) == "1" {
+			return fail("Bluetooth is blocked by software", "Unblock Bluetooth with 'rfkill unblock bluetooth'")
+		}
+	}
+
+	return ok("not blocked")
+}
+
+// checkBlueZ checks that the BLE adapter can be enabled through BlueZ
+func checkBlueZ(enableAdapter func() error) Result {
+
+	if enableAdapter == nil {
+		return warn("skipped", "")
+	}
+
+	if err := enableAdapter(); err != nil {
+		return fail(err.Error(), "Confirm that the BlueZ service is running ('systemctl status bluetooth') and the adapter is powered on ('bluetoothctl power on')")
+	}
+
+	return ok("adapter enabled")
+}
+
+// checkMediaPlayer checks that the media player library is available
+func checkMediaPlayer(probe func() (string, error)) Result {
+
+	if probe == nil {
+		return warn("skipped", "")
+	}
+
+	version, err := probe()
+	if err != nil {
+		return fail(err.Error(), "Install the mpv media player library (e.g., 'sudo apt install libmpv2')")
+	}
+
+	return ok(version)
+}
+
+// checkConfig checks that the session configuration loads (and is valid), returning its video file
+func checkConfig(configFile string, load func() (string, error)) (Result, string) {
+
+	if load == nil {
+		return warn("skipped", ""), ""
+	}
+
+	videoFile, err := load()
+	if err != nil {
+		return fail(err.Error(), "Correct the session configuration file ("+configFile+"), or select another using --config"), ""
+	}
+
+	return ok(configFile + " is valid"), videoFile
+}
+
+// checkVideoReadable checks that the session video file can be read
+func checkVideoReadable(videoFile string) Result {
+
+	if videoFile == "" {
+		return warn("skipped (no valid session configuration)", "")
+	}
+
+	file, err := os.Open(videoFile)
+	if err != nil {
+		return fail(err.Error(), "Confirm the video file_path in the session configuration, and that the file is readable")
+	}
+	defer file.Close()
+
+	if _, err := file.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return fail(err.Error(), "Confirm that the video file is readable (e.g., not a directory)")
+	}
+
+	return ok(videoFile + " is readable")
+}
+
+// checkVideoDecoding checks that the media player can decode the session video file
+func checkVideoDecoding(videoFile string, probe func(string) (string, error)) Result {
+
+	if videoFile == "" || probe == nil {
+		return warn("skipped (no valid session configuration)", "")
+	}
+
+	details, err := probe(videoFile)
+	if err != nil {
+		return fail(err.Error(), "Convert the video to a widely supported format (e.g., H.264 in an MP4 container using ffmpeg)")
+	}
+
+	return ok(details)
+}
+
+// checkConfigDirWritable checks that session edits can be saved to the configuration directory
+func checkConfigDirWritable(configFile string) Result {
+
+	dir := filepath.Dir(configFile)
+
+	file, err := os.CreateTemp(dir, ".bsc-doctor-*")
+	if err != nil {
+		return warn(dir+" is not writable", "Session edits can't be saved here: make the directory writable, or move your session files")
+	}
+
+	file.Close()
+	os.Remove(file.Name())
+
+	return ok(dir + " is writable")
+}
+
+// readSysfs returns the trimmed contents of a sysfs attribute file (or an empty string on error)
+func readSysfs(path string) string {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,14 @@
+// Package doctor provides environment diagnostics for BLE Sync Cycle (BSC)
+//
+// When the user runs the BSC binary with the doctor subcommand, this package checks the
+// environment needed to run a BSC session and prints actionable results:
+//
+// - The Bluetooth adapter is present, not blocked (rfkill), and accessible through BlueZ
+// - The media player library (libmpv) is available, and its version
+// - The session configuration file is valid
+// - The session video file is readable and can be decoded
+// - The session configuration directory is writable (so that session edits can be saved)
+//
+// Checks that require hardware or media player access are provided by the caller, so that this
+// package can be tested without a BLE adapter or a display
+package doctor
@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Status is the outcome of a diagnostic check
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+)
+
+// String returns a human-readable representation of the status
+func (s Status) String() string {
+	return [...]string{
+		"OK",
+		"WARN",
+		"FAIL",
+	}[s]
+}
+
+// Result holds the outcome of a diagnostic check, with a remedy if the check didn't pass
+type Result struct {
+	Name   string
+	Detail string
+	Remedy string
+	Status Status
+}
+
+// Check is a single named diagnostic check
+type Check struct {
+	Run  func(ctx context.Context) Result
+	Name string
+}
+
+// Options provides the environment probes and session details used by the diagnostic checks
+type Options struct {
+	EnableAdapter    func() error                         // Enables the system BLE adapter
+	ProbeMediaPlayer func() (string, error)               // Returns the media player version
+	ProbeVideoFile   func(path string) (string, error)    // Returns a description of the video stream
+	LoadConfig       func() (videoFile string, err error) // Loads the session configuration
+	ConfigFile       string                               // Path of the session configuration file
+	SysfsRoot        string                               // Root of sysfs (default: "/sys")
+}
+
+// Run runs each check in order, returning their results
+func Run(ctx context.Context, checks []Check) []Result {
+
+	results := make([]Result, 0, len(checks))
+
+	for _, check := range checks {
+
+		result := check.Run(ctx)
+		result.Name = check.Name
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Report writes the check results (and any remedies) to w, returning the number of failed checks
+func Report(w io.Writer, results []Result) int {
+
+	failed := 0
+
+	fmt.Fprintln(w, "")
+
+	for _, r := range results {
+
+		fmt.Fprintf(w, "[%-4s] %s: %s\n", r.Status, r.Name, r.Detail)
+
+		if r.Status != StatusOK && r.Remedy != "" {
+			fmt.Fprintf(w, "       → %s\n", r.Remedy)
+		}
+
+		if r.Status == StatusFail {
+			failed++
+		}
+	}
+
+	fmt.Fprintln(w, "")
+
+	if failed == 0 {
+		fmt.Fprintln(w, "All checks passed (see any warnings above).")
+	} else {
+		fmt.Fprintf(w, "%d check(s) failed: see the suggested remedies above.\n", failed)
+	}
+
+	fmt.Fprintln(w, "")
+
+	return failed
+}
+
+// ok returns a passing result
+func ok(detail string) Result {
+	return Result{Status: StatusOK, Detail: detail}
+}
+
+// warn returns a warning result with a remedy
+func warn(detail, remedy string) Result {
+	return Result{Status: StatusWarn, Detail: detail, Remedy: remedy}
+}
+
+// fail returns a failing result with a remedy
+func fail(detail, remedy string) Result {
+	return Result{Status: StatusFail, Detail: detail, Remedy: remedy}
+}
@@ -0,0 +1,135 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errProbeFailed = errors.New("probe failed")
+
+// writeSysfs creates a fake sysfs attribute file
+func writeSysfs(t *testing.T, path, value string) {
+
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+
+}
+
+// TestCheckAdapterPresent tests Bluetooth adapter detection using a fake sysfs
+func TestCheckAdapterPresent(t *testing.T) {
+
+	root := t.TempDir()
+	assert.Equal(t, StatusFail, checkAdapterPresent(root).Status)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "class", "bluetooth", "hci0"), 0o755))
+
+	result := checkAdapterPresent(root)
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Equal(t, "found hci0", result.Detail)
+
+}
+
+// TestCheckAdapterBlocked tests Bluetooth rfkill detection using a fake sysfs
+func TestCheckAdapterBlocked(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		kind     string
+		soft     string
+		hard     string
+		expected Status
+	}{
+		{"not blocked", "bluetooth", "0", "0", StatusOK},
+		{"soft blocked", "bluetooth", "1", "0", StatusFail},
+		{"hard blocked", "bluetooth", "0", "1", StatusFail},
+		{"other radio blocked", "wlan", "1", "1", StatusOK},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			root := t.TempDir()
+			sw := filepath.Join(root, "class", "rfkill", "rfkill0")
+			writeSysfs(t, filepath.Join(sw, "type"), tt.kind)
+			writeSysfs(t, filepath.Join(sw, "soft"), tt.soft)
+			writeSysfs(t, filepath.Join(sw, "hard"), tt.hard)
+
+			assert.Equal(t, tt.expected, checkAdapterBlocked(root).Status)
+
+		})
+	}
+
+}
+
+// TestChecks tests running the full set of checks with fake probes
+func TestChecks(t *testing.T) {
+
+	dir := t.TempDir()
+	videoFile := filepath.Join(dir, "video.mp4")
+	writeSysfs(t, videoFile, "video")
+
+	opts := Options{
+		EnableAdapter:    func() error { return errProbeFailed },
+		ProbeMediaPlayer: func() (string, error) { return "mpv 0.38.0", nil },
+		ProbeVideoFile:   func(_ string) (string, error) { return "h264 1920x1080", nil },
+		LoadConfig:       func() (string, error) { return videoFile, nil },
+		ConfigFile:       filepath.Join(dir, "config.toml"),
+		SysfsRoot:        dir,
+	}
+
+	results := Run(context.Background(), Checks(opts))
+	statuses := make(map[string]Status, len(results))
+
+	for _, r := range results {
+		statuses[r.Name] = r.Status
+	}
+
+	assert.Equal(t, StatusFail, statuses["Bluetooth adapter"])
+	assert.Equal(t, StatusFail, statuses["BlueZ access"])
+	assert.Equal(t, StatusOK, statuses["Media player"])
+	assert.Equal(t, StatusOK, statuses["Session configuration"])
+	assert.Equal(t, StatusOK, statuses["Video file"])
+	assert.Equal(t, StatusOK, statuses["Video decoding"])
+	assert.Equal(t, StatusOK, statuses["Configuration directory"])
+
+	var out bytes.Buffer
+	assert.Equal(t, 2, Report(&out, results))
+	assert.True(t, strings.Contains(out.String(), "[FAIL] BlueZ access: probe failed"))
+
+}
+
+// TestChecksInvalidConfig tests that the video checks are skipped without a valid configuration
+func TestChecksInvalidConfig(t *testing.T) {
+
+	opts := Options{
+		LoadConfig: func() (string, error) { return "", errProbeFailed },
+		ConfigFile: filepath.Join(t.TempDir(), "config.toml"),
+		SysfsRoot:  t.TempDir(),
+	}
+
+	results := Run(context.Background(), Checks(opts))
+
+	for _, r := range results {
+
+		switch r.Name {
+		case "Session configuration":
+			assert.Equal(t, StatusFail, r.Status)
+		case "Video file", "Video decoding":
+			assert.Equal(t, StatusWarn, r.Status)
+		}
+	}
+
+}
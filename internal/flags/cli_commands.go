@@ -0,0 +1,264 @@
+package flags
+
+// Subcommand names
+const (
+	CmdGUI       = "gui"
+	CmdRun       = "run"
+	CmdReplay    = "replay"
+	CmdScan      = "scan"
+	CmdDoctor    = "doctor"
+	CmdInit      = "init"
+	CmdSelfTest  = "selftest"
+	CmdDecode    = "decode"
+	CmdExport    = "export"
+	CmdImport    = "import"
+	CmdSchema    = "schema"
+	CmdVersion   = "version"
+	CmdInstall   = "install"
+	CmdUninstall = "uninstall"
+	CmdHelp      = "help"
+)
+
+// CommandInfo holds structural information about a subcommand and its flags
+type CommandInfo struct {
+	Name  string     // Name of the subcommand, e.g., "run"
+	Args  string     // Positional argument(s) of the subcommand (used for help), e.g., "<dir>"
+	Usage string     // Usage description (used for help)
+	Mode  ModeType   // Mode of operation (CLI or GUI)
+	Flags []FlagInfo // Flags accepted by the subcommand
+}
+
+// Flags shared by more than one subcommand
+var (
+	configFlag = FlagInfo{
+		Result:    &flags.Config,
+		Name:      "config",
+		ShortName: "c",
+		Value:     "",
+		Usage:     "Path to the configuration file ('path/to/config.toml')",
+	}
+
+	seekFlag = FlagInfo{
+		Result:    &flags.Seek,
+		Name:      "seek",
+		ShortName: "s",
+		Value:     "",
+		Usage:     "Seek to a specific time in the video ('HH:MM:SS')",
+	}
+
+	lowPowerFlag = FlagInfo{
+		Result:    &flags.LowPower,
+		Name:      "low-power",
+		ShortName: "p",
+		Value:     "false",
+		Usage:     "Enable the low-power profile (e.g., Raspberry Pi 4/5 hosts)",
+	}
+
+	latencyFlag = FlagInfo{
+		Result:    &flags.Latency,
+		Name:      "measure-latency",
+		ShortName: "m",
+		Value:     "false",
+		Usage:     "Measure and report the latency from speed updates to video playback speed changes",
+	}
+
+	helpFlag = FlagInfo{
+		Result:    &flags.Help,
+		Name:      "help",
+		ShortName: "h",
+		Value:     "false",
+		Usage:     "Display help for the command",
+	}
+)
+
+// commands is the list of available subcommands (the first is the default subcommand)
+var commands = []CommandInfo{
+	{
+		Name:  CmdGUI,
+		Usage: "Run the application with a graphical user interface (GUI) (default)",
+		Mode:  GUI,
+		Flags: []FlagInfo{
+			{
+				Result:    &flags.Logging,
+				Name:      "log-console",
+				ShortName: "l",
+				Value:     "false",
+				Usage:     "Enable logging to the console",
+			},
+		},
+	},
+	{
+		Name:  CmdRun,
+		Usage: "Run a BSC session from the console, without a GUI",
+		Mode:  CLI,
+		Flags: []FlagInfo{
+			configFlag,
+			seekFlag,
+			{
+				Result:    &flags.Export,
+				Name:      "export-samples",
+				ShortName: "e",
+				Value:     "",
+				Usage:     "Export raw BLE sensor samples to a CSV file ('path/to/samples.csv')",
+			},
+			{
+				Result:    &flags.TrafficLog,
+				Name:      "log-traffic",
+				ShortName: "t",
+				Value:     "",
+				Usage:     "Log raw BLE sensor notification payloads to a file ('path/to/traffic.log')",
+			},
+			lowPowerFlag,
+			latencyFlag,
+		},
+	},
+	{
+		Name:  CmdReplay,
+		Args:  "<file.csv>",
+		Usage: "Run a BSC session replaying speeds from a recorded session CSV file instead of a BLE sensor",
+		Mode:  CLI,
+		Flags: []FlagInfo{
+			configFlag,
+			seekFlag,
+			{
+				Result:    &flags.ReplayRate,
+				Name:      "rate",
+				ShortName: "x",
+				Value:     "",
+				Usage:     "Rate of replay (e.g., '2.0' replays twice as fast as recorded)",
+			},
+			lowPowerFlag,
+			latencyFlag,
+		},
+	},
+	{
+		Name:  CmdScan,
+		Usage: "Scan for BLE speed and cadence sensors, and list their BD_ADDRs",
+		Mode:  CLI,
+		Flags: []FlagInfo{
+			{
+				Result:    &flags.ScanSecs,
+				Name:      "timeout",
+				ShortName: "t",
+				Value:     "15",
+				Usage:     "Time to scan for BLE sensors (in seconds)",
+			},
+		},
+	},
+	{
+		Name:  CmdDoctor,
+		Usage: "Check the environment (Bluetooth, media player, session config and video) for problems",
+		Mode:  CLI,
+		Flags: []FlagInfo{configFlag},
+	},
+	{
+		Name:  CmdInit,
+		Args:  "<dir>",
+		Usage: "Write a commented starter config.toml into a directory",
+		Mode:  CLI,
+		Flags: []FlagInfo{
+			{
+				Result:    &flags.InitScan,
+				Name:      "scan",
+				ShortName: "s",
+				Value:     "false",
+				Usage:     "Scan for a BLE sensor to pre-fill the starter config.toml",
+			},
+		},
+	},
+	{
+		Name:  CmdSelfTest,
+		Usage: "Run a session self-test using in-memory controllers (no BLE sensor or display)",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdDecode,
+		Args:  "<traffic.log>",
+		Usage: "Decode a BLE traffic log file through the speed sensor parser (or use --decode)",
+		Mode:  CLI,
+		Flags: []FlagInfo{configFlag},
+	},
+	{
+		Name:  CmdExport,
+		Args:  "<bundle.zip>",
+		Usage: "Export the BSC sessions and sensor registry into a settings bundle (e.g., to move to a new PC)",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdImport,
+		Args:  "<bundle.zip>",
+		Usage: "Import the BSC sessions and sensor registry of a settings bundle",
+		Mode:  CLI,
+		Flags: []FlagInfo{
+			{
+				Result:    &flags.Overwrite,
+				Name:      "overwrite",
+				ShortName: "o",
+				Value:     "false",
+				Usage:     "Overwrite existing session files with those of the settings bundle",
+			},
+		},
+	},
+	{
+		Name:  CmdSchema,
+		Args:  "<schema.json>",
+		Usage: "Write a JSON Schema of the BSC session config file (e.g., for editor autocompletion)",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdVersion,
+		Usage: "Display the application version and build information (or use --version)",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdInstall,
+		Usage: "Install the BSC application to the local user environment",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdUninstall,
+		Usage: "Uninstall the BSC application from the local user environment",
+		Mode:  CLI,
+	},
+	{
+		Name:  CmdHelp,
+		Args:  "[command]",
+		Usage: "Display help for the application (or a command)",
+		Mode:  CLI,
+	},
+}
+
+// findCommand returns the subcommand with the given name
+func findCommand(name string) (CommandInfo, bool) {
+
+	for _, cmd := range commands {
+
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+
+	return CommandInfo{}, false
+}
+
+// positional returns a pointer to the value set by the positional argument of a subcommand (or
+// nil if the subcommand doesn't take a positional argument)
+func positional(name string) *string {
+
+	switch name {
+	case CmdReplay:
+		return &flags.Replay
+	case CmdInit:
+		return &flags.Init
+	case CmdDecode:
+		return &flags.Decode
+	case CmdExport, CmdImport:
+		return &flags.Bundle
+	case CmdSchema:
+		return &flags.Schema
+	case CmdHelp:
+		return &flags.HelpTopic
+	}
+
+	return nil
+}
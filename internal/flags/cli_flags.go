@@ -1,10 +1,14 @@
 package flags
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // ModeType represents the current mode of operation for the application
@@ -20,100 +24,98 @@ const (
 	GUI
 )
 
+// Error definitions
+var (
+	errUnknownCommand  = errors.New("unknown command")
+	errMissingArgument = errors.New("missing argument")
+	errUnexpectedArgs  = errors.New("unexpected arguments")
+)
+
 // FlagInfo holds structural information about a flag
 type FlagInfo struct {
-	Result    any      // Pointer to the resulting value
-	Name      string   // Name of the flag, e.g., "config"
-	ShortName string   // Short name of the flag, e.g., "c"
-	Value     string   // Default value
-	Usage     string   // Usage description (used for help)
-	Mode      ModeType // Mode of operation (CLI or GUI)
+	Result    any    // Pointer to the resulting value
+	Name      string // Name of the flag, e.g., "config"
+	ShortName string // Short name of the flag, e.g., "c"
+	Value     string // Default value
+	Usage     string // Usage description (used for help)
 }
 
-// CLIFlags holds a list of available command-line flags
+// CLIFlags holds the parsed subcommand, its flags, and its positional argument
 type CLIFlags struct {
-	Config    string
-	Seek      string
-	Logging   bool
-	NoGUI     bool
-	Help      bool
-	Install   bool
-	Uninstall bool
+	Command    string
+	Config     string
+	Seek       string
+	Replay     string
+	ReplayRate string
+	Export     string
+	TrafficLog string
+	Decode     string
+	Init       string
+	Bundle     string
+	Schema     string
+	HelpTopic  string
+	ScanSecs   int
+	Logging    bool
+	LowPower   bool
+	Latency    bool
+	InitScan   bool
+	Overwrite  bool
+	Help       bool
 }
 
-var (
-	flags CLIFlags
-
-	flagInfos = []FlagInfo{
-		{
-			Result:    &flags.Logging,
-			Name:      "log-console",
-			ShortName: "l",
-			Value:     "false",
-			Usage:     "Enable logging to the console",
-			Mode:      GUI,
-		},
-		{
-			Result:    &flags.NoGUI,
-			Name:      "no-gui",
-			ShortName: "n",
-			Value:     "false",
-			Usage:     "Run the application without a graphical user interface (GUI)",
-			Mode:      CLI,
-		},
-		{
-			Result:    &flags.Config,
-			Name:      "config",
-			ShortName: "c",
-			Value:     "",
-			Usage:     "Path to the configuration file ('path/to/config.toml')",
-			Mode:      CLI,
-		},
-		{
-			Result:    &flags.Seek,
-			Name:      "seek",
-			ShortName: "s",
-			Value:     "",
-			Usage:     "Seek to a specific time in the video ('HH:MM:SS')",
-			Mode:      CLI,
-		},
-		{
-			Result:    &flags.Install,
-			Name:      "install",
-			ShortName: "i",
-			Value:     "false",
-			Usage:     "Install the BSC application to the local user environment",
-			Mode:      CLI,
-		},
-		{
-			Result:    &flags.Uninstall,
-			Name:      "uninstall",
-			ShortName: "u",
-			Value:     "false",
-			Usage:     "Uninstall the BSC application from the local user environment",
-			Mode:      CLI,
-		},
-		{
-			Result:    &flags.Help,
-			Name:      "help",
-			ShortName: "h",
-			Value:     "false",
-			Usage:     "Display this help message",
-			Mode:      CLI,
-		},
-	}
-)
+var flags CLIFlags
+
+// Flags replaced by subcommands in earlier versions of the application, and their subcommands
+var deprecatedFlags = map[string]string{
+	"no-gui":    CmdRun,
+	"n":         CmdRun,
+	"install":   CmdInstall,
+	"i":         CmdInstall,
+	"uninstall": CmdUninstall,
+	"u":         CmdUninstall,
+}
 
-// ParseArgs parses the command-line flags and returns an error if an undefined flag is found
+// Output for warnings about deprecated flags
+var warningOutput io.Writer = os.Stderr
+
+// ParseArgs parses the subcommand and its flags from the command-line, and returns an error if an
+// unknown subcommand or an undefined flag is found
 func ParseArgs() error {
 
+	args := os.Args[1:]
+
+	// Without a subcommand, the default (GUI) subcommand is run
+	name, explicit := commands[0].Name, true
+
+	switch {
+	case len(args) > 0 && (args[0] == "--"+CmdVersion || args[0] == "-v"):
+		name, args = CmdVersion, args[1:] // Shorthand for the version subcommand
+
+	case len(args) > 0 && args[0] == "--"+CmdDecode:
+		name, args = CmdDecode, args[1:] // Shorthand for the decode subcommand
+
+	case len(args) > 0 && !strings.HasPrefix(args[0], "-"):
+		name, args = args[0], args[1:]
+
+	default:
+		name, args = replaceDeprecatedFlag(name, args)
+		explicit = name != commands[0].Name
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		return fmt.Errorf(errFormat, "failed to parse command", fmt.Errorf("%w: %s", errUnknownCommand, name))
+	}
+
+	flags.Command = cmd.Name
+
 	// Create a custom FlagSet
-	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
 
 	fs.SetOutput(io.Discard) // Suppress all output (important)
 
-	// Register all flags
-	for _, fi := range flagInfos {
+	// Register the subcommand flags
+	for _, fi := range append(cmd.Flags, helpFlag) {
 
 		switch v := fi.Result.(type) {
 
@@ -124,41 +126,118 @@ func ParseArgs() error {
 		case *bool:
 			fs.BoolVar(v, fi.Name, fi.Value == "true", fi.Usage)
 			fs.BoolVar(v, fi.ShortName, fi.Value == "true", fi.Usage)
+
+		case *int:
+			value, _ := strconv.Atoi(fi.Value)
+			fs.IntVar(v, fi.Name, value, fi.Usage)
+			fs.IntVar(v, fi.ShortName, value, fi.Usage)
 		}
 
 	}
 
 	// Parse the flags
-	if err := fs.Parse(os.Args[1:]); err != nil {
+	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf(errFormat, "failed to parse flags", err)
 	}
 
+	// Help requested without a subcommand is help for the application (listing the subcommands)
+	if flags.Help && !explicit {
+		flags.Command = CmdHelp
+	}
+
+	return parsePositional(cmd, fs.Args())
+}
+
+// replaceDeprecatedFlag replaces a deprecated flag (e.g., "--no-gui") with its subcommand, warning
+// that the flag is deprecated
+func replaceDeprecatedFlag(name string, args []string) (string, []string) {
+
+	for i, arg := range args {
+
+		// Flags end at the terminator
+		if arg == "--" {
+			break
+		}
+
+		cmd, ok := deprecatedFlags[strings.TrimLeft(arg, "-")]
+		if !ok || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		fmt.Fprintf(warningOutput, "Warning: %s is deprecated and will be removed in a future release: use 'ble-sync-cycle %s' instead\n", arg, cmd)
+
+		return cmd, slices.Delete(slices.Clone(args), i, i+1)
+	}
+
+	return name, args
+}
+
+// parsePositional sets the positional argument of the subcommand (if any)
+func parsePositional(cmd CommandInfo, args []string) error {
+
+	target := positional(cmd.Name)
+
+	switch {
+	case target == nil && len(args) > 0, len(args) > 1:
+		return fmt.Errorf("%w for %s: %s", errUnexpectedArgs, cmd.Name, strings.Join(args, " "))
+
+	case len(args) == 1:
+		*target = args[0]
+
+	case target != nil && cmd.Name != CmdHelp && !flags.Help:
+		return fmt.Errorf("%w for %s: %s", errMissingArgument, cmd.Name, cmd.Args)
+	}
+
 	return nil
 }
 
-// ShowHelp displays application help information
+// ShowHelp displays help information for the application, or for the requested subcommand
 func ShowHelp() {
 
+	topic := flags.Command
+	if topic == CmdHelp {
+		topic = flags.HelpTopic
+	}
+
+	if cmd, ok := findCommand(topic); ok && cmd.Name != CmdHelp {
+		showCommandHelp(cmd)
+
+		return
+	}
+
 	fmt.Fprintln(os.Stdout, "")
-	fmt.Fprintln(os.Stdout, "Usage: ble-sync-cycle [flags]")
+	fmt.Fprintln(os.Stdout, "Usage: ble-sync-cycle [command] [flags]")
 	fmt.Fprintln(os.Stdout, "")
-	fmt.Fprintln(os.Stdout, "The following flags are available when running in console/CLI mode:")
+	fmt.Fprintln(os.Stdout, "The following commands are available:")
 	fmt.Fprintln(os.Stdout, "")
 
-	for _, fi := range flagInfos {
-		if fi.Mode == CLI {
-			fmt.Fprintf(os.Stdout, "  -%s, --%-12s %s\n", fi.ShortName, fi.Name, fi.Usage)
-		}
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stdout, "  %-11s %s\n", cmd.Name, cmd.Usage)
 	}
 
 	fmt.Fprintln(os.Stdout, "")
-	fmt.Fprintln(os.Stdout, "The following flag is available when running in GUI mode:")
+	fmt.Fprintln(os.Stdout, "Run 'ble-sync-cycle help [command]' for the flags available to a command.")
 	fmt.Fprintln(os.Stdout, "")
+}
 
-	for _, fi := range flagInfos {
-		if fi.Mode == GUI {
-			fmt.Fprintf(os.Stdout, "  -%s, --%-12s %s\n", fi.ShortName, fi.Name, fi.Usage)
-		}
+// showCommandHelp displays help information for a subcommand and its flags
+func showCommandHelp(cmd CommandInfo) {
+
+	usage := "ble-sync-cycle " + cmd.Name + " [flags]"
+	if cmd.Args != "" {
+		usage += " " + cmd.Args
+	}
+
+	fmt.Fprintln(os.Stdout, "")
+	fmt.Fprintln(os.Stdout, "Usage: "+usage)
+	fmt.Fprintln(os.Stdout, "")
+	fmt.Fprintln(os.Stdout, cmd.Usage)
+	fmt.Fprintln(os.Stdout, "")
+	fmt.Fprintln(os.Stdout, "The following flags are available:")
+	fmt.Fprintln(os.Stdout, "")
+
+	for _, fi := range append(cmd.Flags, helpFlag) {
+		fmt.Fprintf(os.Stdout, "  -%s, --%-16s %s\n", fi.ShortName, fi.Name, fi.Usage)
 	}
 
 	fmt.Fprintln(os.Stdout, "")
@@ -169,14 +248,22 @@ func Flags() CLIFlags {
 	return flags
 }
 
-// IsCLIMode checks if the user provided the flag to run in CLI-only mode
+// Command returns the name of the parsed subcommand
+func Command() string {
+	return flags.Command
+}
+
+// IsCLIMode checks if the parsed subcommand runs in CLI-only mode
 func IsCLIMode() bool {
-	return flags.NoGUI
+
+	cmd, ok := findCommand(flags.Command)
+
+	return ok && cmd.Mode == CLI
 }
 
-// IsHelpFlag checks if the user provided the flag to display help
+// IsHelpFlag checks if the user requested help (for the application or a subcommand)
 func IsHelpFlag() bool {
-	return flags.Help
+	return flags.Help || flags.Command == CmdHelp
 }
 
 // IsGUIConsoleLogging returns true/false to enable CLI logging while running in GUI mode
@@ -184,12 +271,18 @@ func IsGUIConsoleLogging() bool {
 	return flags.Logging
 }
 
-// IsInstallFlag checks if the user provided the flag to install the application
-func IsInstallFlag() bool {
-	return flags.Install
+// IsLowPowerFlag checks if the user provided the flag to enable the low-power profile
+func IsLowPowerFlag() bool {
+	return flags.LowPower
+}
+
+// IsReplayFlag checks if the user provided a recorded session file to replay
+func IsReplayFlag() bool {
+	return flags.Replay != ""
 }
 
-// IsUninstallFlag checks if the user provided the flag to uninstall the application
-func IsUninstallFlag() bool {
-	return flags.Uninstall
+// IsInitScanFlag checks if the user provided the flag to scan for a BLE sensor when writing a
+// starter config file
+func IsInitScanFlag() bool {
+	return flags.InitScan
 }
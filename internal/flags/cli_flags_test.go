@@ -1,14 +1,24 @@
 package flags
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
 const (
 	TestConfigFile   = "test.toml"
 	TestSeekPosition = "01:30:00"
+	TestReplayFile   = "samples.csv"
+	TestReplayRate   = "2.0"
+	TestExportFile   = "export.csv"
+	TestTrafficLog   = "traffic.log"
+	TestInitDir      = "rides"
+	TestBundleFile   = "bsc-settings.zip"
+	TestSchemaFile   = "bsc-schema.json"
 )
 
 // TestParseArgs tests the ParseArgs function
@@ -38,28 +48,201 @@ func testCases() []struct {
 		wantErr  bool
 	}{
 		{
-			name:     "no flags",
+			name:     "no command",
 			args:     []string{},
 			wantErr:  false,
-			expected: CLIFlags{Config: "", Seek: "", Help: false},
+			expected: CLIFlags{Command: CmdGUI},
 		},
 		{
-			name:     "all flags with long names",
-			args:     []string{"--config", TestConfigFile, "--seek", TestSeekPosition, "--install", "--uninstall", "--help"},
+			name:     "default command with flags",
+			args:     []string{"--log-console"},
 			wantErr:  false,
-			expected: CLIFlags{Config: TestConfigFile, Seek: TestSeekPosition, Help: true, Install: true, Uninstall: true},
+			expected: CLIFlags{Command: CmdGUI, Logging: true},
 		},
 		{
-			name:     "all flags with short names",
-			args:     []string{"-c", TestConfigFile, "-s", TestSeekPosition, "-i", "-u", "-h"},
+			name:     "run command with long names",
+			args:     []string{CmdRun, "--config", TestConfigFile, "--seek", TestSeekPosition, "--export-samples", TestExportFile, "--log-traffic", TestTrafficLog, "--low-power", "--measure-latency"},
 			wantErr:  false,
-			expected: CLIFlags{Config: TestConfigFile, Seek: TestSeekPosition, Help: true, Install: true, Uninstall: true},
+			expected: CLIFlags{Command: CmdRun, Config: TestConfigFile, Seek: TestSeekPosition, Export: TestExportFile, TrafficLog: TestTrafficLog, LowPower: true, Latency: true},
 		},
 		{
-			name:     "mixed short and long names",
-			args:     []string{"-c", TestConfigFile, "--seek", TestSeekPosition, "-h"},
+			name:     "run command with short names",
+			args:     []string{CmdRun, "-c", TestConfigFile, "-s", TestSeekPosition, "-e", TestExportFile, "-t", TestTrafficLog, "-p", "-m"},
 			wantErr:  false,
-			expected: CLIFlags{Config: TestConfigFile, Seek: TestSeekPosition, Help: true},
+			expected: CLIFlags{Command: CmdRun, Config: TestConfigFile, Seek: TestSeekPosition, Export: TestExportFile, TrafficLog: TestTrafficLog, LowPower: true, Latency: true},
+		},
+		{
+			name:     "replay command",
+			args:     []string{CmdReplay, "-c", TestConfigFile, "--rate", TestReplayRate, TestReplayFile},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdReplay, Config: TestConfigFile, Replay: TestReplayFile, ReplayRate: TestReplayRate},
+		},
+		{
+			name:    "replay command without a file",
+			args:    []string{CmdReplay, "-c", TestConfigFile},
+			wantErr: true,
+		},
+		{
+			name:     "scan command",
+			args:     []string{CmdScan, "-t", "30"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdScan, ScanSecs: 30},
+		},
+		{
+			name:     "scan command default timeout",
+			args:     []string{CmdScan},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdScan, ScanSecs: 15},
+		},
+		{
+			name:     "init command",
+			args:     []string{CmdInit, "--scan", TestInitDir},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdInit, Init: TestInitDir, InitScan: true},
+		},
+		{
+			name:     "command help",
+			args:     []string{CmdInit, "-h"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdInit, Help: true},
+		},
+		{
+			name:     "help flag",
+			args:     []string{"--help"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdHelp, Help: true},
+		},
+		{
+			name:     "help short flag",
+			args:     []string{"-h"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdHelp, Help: true},
+		},
+		{
+			name:     "default command help",
+			args:     []string{CmdGUI, "--help"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdGUI, Help: true},
+		},
+		{
+			name:     "deprecated flag help",
+			args:     []string{"--no-gui", "-h"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdRun, Help: true},
+		},
+		{
+			name:     "help command",
+			args:     []string{CmdHelp, CmdDoctor},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdHelp, HelpTopic: CmdDoctor},
+		},
+		{
+			name:     "version flag",
+			args:     []string{"--version"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdVersion},
+		},
+		{
+			name:     "version short flag",
+			args:     []string{"-v"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdVersion},
+		},
+		{
+			name:     "decode command",
+			args:     []string{CmdDecode, "-c", TestConfigFile, TestTrafficLog},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdDecode, Config: TestConfigFile, Decode: TestTrafficLog},
+		},
+		{
+			name:     "decode flag",
+			args:     []string{"--decode", TestTrafficLog},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdDecode, Decode: TestTrafficLog},
+		},
+		{
+			name:     "export command",
+			args:     []string{CmdExport, TestBundleFile},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdExport, Bundle: TestBundleFile},
+		},
+		{
+			name:     "import command",
+			args:     []string{CmdImport, "--overwrite", TestBundleFile},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdImport, Bundle: TestBundleFile, Overwrite: true},
+		},
+		{
+			name:    "import command without a bundle",
+			args:    []string{CmdImport, "-o"},
+			wantErr: true,
+		},
+		{
+			name:     "schema command",
+			args:     []string{CmdSchema, TestSchemaFile},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdSchema, Schema: TestSchemaFile},
+		},
+		{
+			name:    "schema command without a file",
+			args:    []string{CmdSchema},
+			wantErr: true,
+		},
+		{
+			name:     "deprecated no-gui flag",
+			args:     []string{"--config", TestConfigFile, "--no-gui", "--seek", TestSeekPosition},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdRun, Config: TestConfigFile, Seek: TestSeekPosition},
+		},
+		{
+			name:     "deprecated no-gui short flag",
+			args:     []string{"-n", "-c", TestConfigFile},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdRun, Config: TestConfigFile},
+		},
+		{
+			name:     "deprecated install flag",
+			args:     []string{"--install"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdInstall},
+		},
+		{
+			name:     "deprecated install short flag",
+			args:     []string{"-i"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdInstall},
+		},
+		{
+			name:     "deprecated uninstall flag",
+			args:     []string{"--uninstall"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdUninstall},
+		},
+		{
+			name:     "deprecated uninstall short flag",
+			args:     []string{"-u"},
+			wantErr:  false,
+			expected: CLIFlags{Command: CmdUninstall},
+		},
+		{
+			name:    "deprecated flag with a flag of another command",
+			args:    []string{"--no-gui", "--log-console"},
+			wantErr: true,
+		},
+		{
+			name:    "flag of another command",
+			args:    []string{CmdDoctor, "--seek", TestSeekPosition},
+			wantErr: true,
+		},
+		{
+			name:    "unexpected argument",
+			args:    []string{CmdVersion, "now"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid command",
+			args:    []string{"invalid"},
+			wantErr: true,
 		},
 		{
 			name:    "invalid flag",
@@ -81,6 +264,11 @@ func runTestCase(t *testing.T, tc struct {
 	t.Helper()
 	flags = CLIFlags{} // Reset flags
 
+	origOutput := warningOutput
+	warningOutput = io.Discard
+
+	defer func() { warningOutput = origOutput }()
+
 	origArgs := os.Args
 	defer func() { os.Args = origArgs }()
 
@@ -99,14 +287,45 @@ func runTestCase(t *testing.T, tc struct {
 
 }
 
+// TestDeprecatedFlagWarning tests that a deprecated flag is reported along with its subcommand
+func TestDeprecatedFlagWarning(t *testing.T) {
+
+	var warning bytes.Buffer
+
+	origArgs, origOutput := os.Args, warningOutput
+	defer func() { os.Args, warningOutput = origArgs, origOutput }()
+
+	flags = CLIFlags{} // Reset flags
+	os.Args = []string{"app", "--no-gui", "-c", TestConfigFile}
+	warningOutput = &warning
+
+	if err := ParseArgs(); err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+
+	if !strings.Contains(warning.String(), "--no-gui is deprecated") || !strings.Contains(warning.String(), "ble-sync-cycle run") {
+		t.Errorf("ParseArgs() warning = %q, want the deprecated flag and its subcommand", warning.String())
+	}
+
+	// Without a deprecated flag, there's no warning
+	warning.Reset()
+	os.Args = []string{"app", "--log-console"}
+
+	if err := ParseArgs(); err != nil || warning.Len() > 0 {
+		t.Errorf("ParseArgs() = (%v, %q), want no error or warning", err, warning.String())
+	}
+
+}
+
 // TestFlags tests the Flags function
 func TestFlags(t *testing.T) {
 
 	// Set up test flags
 	testFlags := CLIFlags{
-		Config: TestConfigFile,
-		Seek:   TestSeekPosition,
-		Help:   true,
+		Command: CmdRun,
+		Config:  TestConfigFile,
+		Seek:    TestSeekPosition,
+		Help:    true,
 	}
 
 	// Set the package-level flags variable
@@ -119,71 +338,87 @@ func TestFlags(t *testing.T) {
 		t.Errorf("Flags() = %v, want %v", result, testFlags)
 	}
 
+	if Command() != CmdRun {
+		t.Errorf("Command() = %v, want %v", Command(), CmdRun)
+	}
+
+}
+
+// TestIsCLIMode tests the IsCLIMode function
+func TestIsCLIMode(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{CmdGUI, false},
+		{CmdRun, true},
+		{CmdReplay, true},
+		{CmdDoctor, true},
+		{"", false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.command, func(t *testing.T) {
+
+			flags = CLIFlags{Command: tt.command}
+
+			if got := IsCLIMode(); got != tt.want {
+				t.Errorf("IsCLIMode() = %v, want %v", got, tt.want)
+			}
+
+		})
+	}
+
 }
 
 // TestShowHelp tests the ShowHelp function
 func TestShowHelp(t *testing.T) {
 
+	// Define test cases
+	tests := []CLIFlags{
+		{},
+		{Command: CmdRun, Help: true},
+		{Command: CmdHelp, HelpTopic: CmdInit},
+	}
+
 	// Since ShowHelp() is a simple formatting function, we'll just test that it doesn't panic
-	t.Run("ShowHelp should not panic", func(t *testing.T) {
+	for _, tt := range tests {
 
-		defer func() {
+		t.Run("ShowHelp should not panic "+tt.Command, func(t *testing.T) {
 
-			if r := recover(); r != nil {
-				t.Errorf("ShowHelp() panicked: %v", r)
-			}
+			defer func() {
 
-		}()
+				if r := recover(); r != nil {
+					t.Errorf("ShowHelp() panicked: %v", r)
+				}
 
-		ShowHelp()
-	})
+			}()
+
+			flags = tt
+			ShowHelp()
+		})
+	}
 
 }
 
-// TestFlagInfosConfiguration tests the configuration of flagInfos
-func TestFlagInfosConfiguration(t *testing.T) {
+// TestShowHelpFlag tests that help requested without a subcommand lists every subcommand, while
+// help for a subcommand lists its flags
+func TestShowHelpFlag(t *testing.T) {
 
 	// Define test cases
 	tests := []struct {
-		wantType any
-		name     string
-		flagInfo FlagInfo
+		name    string
+		args    []string
+		want    string
+		notWant string
 	}{
-		{
-			name:     "logger flag",
-			flagInfo: flagInfos[0],
-			wantType: (*bool)(nil),
-		},
-		{
-			name:     "no-gui flag",
-			flagInfo: flagInfos[1],
-			wantType: (*bool)(nil),
-		},
-		{
-			name:     "config flag",
-			flagInfo: flagInfos[2],
-			wantType: (*string)(nil),
-		},
-		{
-			name:     "seek flag",
-			flagInfo: flagInfos[3],
-			wantType: (*string)(nil),
-		},
-		{
-			name:     "install flag",
-			flagInfo: flagInfos[4],
-			wantType: (*bool)(nil),
-		},
-		{
-			name:     "uninstall flag",
-			flagInfo: flagInfos[5],
-			wantType: (*bool)(nil),
-		},
-		{
-			name:     "help flag",
-			flagInfo: flagInfos[6],
-			wantType: (*bool)(nil),
-		},
+		{"help flag", []string{"--help"}, "The following commands are available:", "The following flags are available:"},
+		{"help short flag", []string{"-h"}, "The following commands are available:", "The following flags are available:"},
+		{"command help flag", []string{CmdGUI, "-h"}, "The following flags are available:", "The following commands are available:"},
 	}
 
 	// Run tests
@@ -191,11 +426,79 @@ func TestFlagInfosConfiguration(t *testing.T) {
 
 		t.Run(tt.name, func(t *testing.T) {
 
-			if reflect.TypeOf(tt.flagInfo.Result) != reflect.TypeOf(tt.wantType) {
-				t.Errorf("FlagInfo %s has wrong type = %v, want %v",
-					tt.flagInfo.Name,
-					reflect.TypeOf(tt.flagInfo.Result),
-					reflect.TypeOf(tt.wantType))
+			origArgs := os.Args
+			defer func() { os.Args = origArgs }()
+
+			flags = CLIFlags{} // Reset flags
+			os.Args = append([]string{"app"}, tt.args...)
+
+			if err := ParseArgs(); err != nil || !IsHelpFlag() {
+				t.Fatalf("ParseArgs() = (%v, help %v), want (nil, help true)", err, IsHelpFlag())
+			}
+
+			output := captureStdout(t, ShowHelp)
+
+			if !strings.Contains(output, tt.want) || strings.Contains(output, tt.notWant) {
+				t.Errorf("ShowHelp() output = %q, want %q (and not %q)", output, tt.want, tt.notWant)
+			}
+
+		})
+	}
+
+}
+
+// captureStdout returns the output written to stdout by fn
+func captureStdout(t *testing.T, fn func()) string {
+
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	origStdout := os.Stdout
+	os.Stdout = w
+
+	fn()
+
+	os.Stdout = origStdout
+	w.Close()
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	return string(output)
+}
+
+// TestCommandsConfiguration tests that every subcommand flag has a supported type, and that no
+// flag name is repeated within a subcommand
+func TestCommandsConfiguration(t *testing.T) {
+
+	for _, cmd := range commands {
+
+		t.Run(cmd.Name, func(t *testing.T) {
+
+			seen := map[string]bool{}
+
+			for _, fi := range append(cmd.Flags, helpFlag) {
+
+				switch fi.Result.(type) {
+				case *string, *bool, *int:
+				default:
+					t.Errorf("FlagInfo %s has unsupported type %v", fi.Name, reflect.TypeOf(fi.Result))
+				}
+
+				for _, name := range []string{fi.Name, fi.ShortName} {
+
+					if seen[name] {
+						t.Errorf("flag name %q repeated in command %s", name, cmd.Name)
+					}
+
+					seen[name] = true
+				}
 			}
 
 		})
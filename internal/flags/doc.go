@@ -1,7 +1,9 @@
 // Package flags parses and manages command-line arguments for the application
 //
-// It enables runtime customization of behavior, such as:
+// Command-line arguments are organized into subcommands (e.g., run, gui, scan, doctor, init,
+// replay, and version), each with its own flags and help output, enabling runtime customization
+// of behavior, such as:
 //   - Selecting the configuration file path
-//   - Enabling debug mode
+//   - Enabling console logging
 //   - Choosing between CLI and GUI operation modes
 package flags
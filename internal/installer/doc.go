@@ -1,7 +1,7 @@
 // Package installer provides a self-contained installation procedure for BLE Sync Cycle (BSC)
 //
-// When the user runs the BSC binary with the --install (or -i) flag, this package handles the
-// installation, or uninstallation with the --uninstall (or -u flag), of the application and
+// When the user runs the BSC binary with the install subcommand, this package handles the
+// installation, or uninstallation with the uninstall subcommand, of the application and
 // its associated assets into the user's local directories:
 //
 // - The BSC binary is copied to $XDG_BIN_HOME (default: ~/.local/bin)
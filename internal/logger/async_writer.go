@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Number of log messages queued for a slow writer before the oldest messages are dropped
+const asyncQueueSize = 1024
+
+type (
+	// AsyncWriter writes log messages to a (possibly slow) writer from a background worker, so
+	// logging never blocks the caller. Once the queue is full, the oldest queued message is
+	// dropped and counted
+	AsyncWriter struct {
+		out      io.Writer
+		queue    chan asyncEntry
+		dropped  atomic.Uint64
+		reported uint64 // Dropped messages already reported (worker only)
+	}
+
+	// asyncEntry is a queued log message, or a flush request if flushed is set
+	asyncEntry struct {
+		data    []byte
+		flushed chan struct{}
+	}
+)
+
+// NewAsyncWriter creates a writer that queues log messages for the writer w, writing them from a
+// background worker that runs for the life of the application
+func NewAsyncWriter(w io.Writer) *AsyncWriter {
+	return newAsyncWriter(w, asyncQueueSize)
+}
+
+// newAsyncWriter creates an async writer with the given queue size
+func newAsyncWriter(w io.Writer, queueSize int) *AsyncWriter {
+
+	aw := &AsyncWriter{
+		out:   w,
+		queue: make(chan asyncEntry, queueSize),
+	}
+
+	go aw.run()
+
+	return aw
+}
+
+// Write queues a copy of the log message, dropping the oldest queued message if the queue is full
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+
+	w.enqueue(asyncEntry{data: append([]byte(nil), p...)})
+
+	return len(p), nil
+}
+
+// Flush waits until all log messages queued before the call have been written
+func (w *AsyncWriter) Flush() {
+
+	entry := asyncEntry{flushed: make(chan struct{})}
+	w.enqueue(entry)
+
+	<-entry.flushed
+
+}
+
+// Dropped returns the number of log messages dropped since the writer was created
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// enqueue queues an entry, making room by dropping the oldest entry while the queue is full
+func (w *AsyncWriter) enqueue(entry asyncEntry) {
+
+	for {
+
+		select {
+		case w.queue <- entry:
+			return
+		default:
+		}
+
+		select {
+		case oldest := <-w.queue:
+			w.discard(oldest)
+		default:
+		}
+	}
+
+}
+
+// discard drops a queued entry (flush requests are released rather than dropped)
+func (w *AsyncWriter) discard(entry asyncEntry) {
+
+	if entry.flushed != nil {
+		close(entry.flushed)
+
+		return
+	}
+
+	w.dropped.Add(1)
+
+}
+
+// run writes queued log messages to the writer, reporting any messages dropped in the meantime
+func (w *AsyncWriter) run() {
+
+	for entry := range w.queue {
+
+		if entry.flushed != nil {
+			close(entry.flushed)
+
+			continue
+		}
+
+		w.reportDropped()
+
+		// Write errors can't be returned to the (long gone) caller
+		_, _ = w.out.Write(entry.data)
+	}
+
+}
+
+// reportDropped writes a warning to the writer if log messages were dropped since the last report
+func (w *AsyncWriter) reportDropped() {
+
+	dropped := w.dropped.Load()
+	if dropped == w.reported {
+		return
+	}
+
+	notice := fmt.Sprintf(outputFormat, White, time.Now().Format("15:04:05"), Reset) +
+		fmt.Sprintf(outputFormat, Yellow, "[WRN]", Reset) +
+		fmt.Sprintf(outputFormat, Blue, APP, Reset) +
+		fmt.Sprintf("%d log message(s) dropped: log output too slow\n", dropped-w.reported)
+
+	w.reported = dropped
+
+	_, _ = io.WriteString(w.out, notice)
+
+}
@@ -88,7 +88,8 @@ func Initialize(logLevel string) *slog.Logger {
 	return logger
 }
 
-// AddWriter allows external components (like the GUI) to attach a log listener
+// AddWriter allows external components (like the GUI) to attach a log listener (writers are
+// called while logging, so slow writers should be wrapped using NewAsyncWriter)
 func AddWriter(w io.Writer) {
 
 	if logOutput != nil {
@@ -210,9 +211,11 @@ func (m *syncMultiWriter) Add(w io.Writer) {
 
 }
 
-// Write writes to all attached writers
+// Write writes to all attached writers (and keeps the message in the recent log)
 func (m *syncMultiWriter) Write(p []byte) (int, error) {
 
+	recent.add(string(p))
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
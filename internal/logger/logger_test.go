@@ -3,6 +3,7 @@ package logger
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"log/slog"
 	"regexp"
 	"strings"
@@ -293,3 +294,92 @@ func TestEnabled(t *testing.T) {
 	}
 
 }
+
+// blockingWriter is a writer that blocks until released
+type blockingWriter struct {
+	release chan struct{}
+	buf     bytes.Buffer
+}
+
+// Write waits until the writer is released, then writes to the buffer
+func (w *blockingWriter) Write(p []byte) (int, error) {
+
+	<-w.release
+
+	return w.buf.Write(p)
+}
+
+// TestAsyncWriter tests that a slow writer doesn't block logging, dropping the oldest messages
+func TestAsyncWriter(t *testing.T) {
+
+	out := &blockingWriter{release: make(chan struct{})}
+	w := newAsyncWriter(out, 2)
+
+	// Writes return immediately while the writer is blocked
+	done := make(chan struct{})
+
+	go func() {
+
+		for i := range 10 {
+			if _, err := w.Write([]byte(strings.Repeat("x", i) + "\n")); err != nil {
+				t.Errorf("Write() returned an error: %v", err)
+			}
+		}
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write() blocked on a slow writer")
+	}
+
+	if w.Dropped() == 0 {
+		t.Error("expected messages to be dropped while the writer is blocked")
+	}
+
+	close(out.release)
+	w.Flush()
+
+	output := out.buf.String()
+
+	if !strings.Contains(output, strings.Repeat("x", 9)+"\n") {
+		t.Errorf("expected the newest message to be written, got %q", output)
+	}
+
+	if !strings.Contains(output, "log message(s) dropped") {
+		t.Errorf("expected dropped messages to be reported, got %q", output)
+	}
+
+}
+
+// TestRecentLog verifies that the recent log keeps the most recent messages, without ANSI codes
+func TestRecentLog(t *testing.T) {
+
+	Initialize("debug")
+	UseGUIWriterOnly(&bytes.Buffer{})
+
+	for i := range recentLogSize + 10 {
+		Info(BackgroundCtx, APP, fmt.Sprintf("recent message %d", i))
+	}
+
+	log := RecentLog()
+
+	if strings.Contains(log, "\x1b[") {
+		t.Error("expected ANSI codes to be stripped from the recent log")
+	}
+
+	if strings.Contains(log, "recent message 9\n") {
+		t.Error("expected the oldest messages to be replaced in the recent log")
+	}
+
+	if !strings.HasSuffix(log, fmt.Sprintf("recent message %d\n", recentLogSize+9)) {
+		t.Errorf("expected the recent log to end with the newest message")
+	}
+
+	if lines := strings.Count(log, "\n"); lines != recentLogSize {
+		t.Errorf("recent log has %d lines, want %d", lines, recentLogSize)
+	}
+
+}
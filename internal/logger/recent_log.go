@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Number of the most recent log messages kept for diagnostics (e.g., crash reports)
+const recentLogSize = 2000
+
+// Regex to find ANSI escape sequences, stripped from the recent log messages
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// recentLog keeps the most recent log messages (regardless of the log writers attached)
+type recentLog struct {
+	messages []string
+	next     int
+	mu       sync.Mutex
+}
+
+// recent holds the most recent log messages of the application
+var recent = &recentLog{messages: make([]string, 0, recentLogSize)}
+
+// add adds a log message, replacing the oldest message once the log is full
+func (r *recentLog) add(message string) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.messages) < recentLogSize {
+		r.messages = append(r.messages, message)
+
+		return
+	}
+
+	r.messages[r.next] = message
+	r.next = (r.next + 1) % recentLogSize
+
+}
+
+// RecentLog returns the most recent log messages (oldest first, without ANSI codes), whatever the
+// logging output (e.g., the GUI Session Log or the terminal)
+func RecentLog() string {
+
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	var sb strings.Builder
+
+	for i := range recent.messages {
+		sb.WriteString(recent.messages[(recent.next+i)%len(recent.messages)])
+	}
+
+	return ansiRegex.ReplaceAllString(sb.String(), "")
+}
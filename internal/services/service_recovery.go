@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// RestartPolicy defines how a service is recovered after a panic
+type RestartPolicy int
+
+const (
+	RestartNever   RestartPolicy = iota // A panic stops the service and is reported as a service error
+	RestartOnPanic                      // A panic restarts the service (up to ServiceOptions.MaxRestarts)
+)
+
+// ErrServicePanic is reported when a service panics and is not restarted
+var ErrServicePanic = errors.New("service panicked")
+
+// PanicHandler is called when a service panics (e.g., to generate a crash report)
+type PanicHandler func(service string, r any, stack []byte)
+
+// panicHandler holds the handler called when a service panics (if set)
+var panicHandler atomic.Pointer[PanicHandler]
+
+// SetPanicHandler sets the handler called when a service panics, before the panic is recovered
+func SetPanicHandler(handler PanicHandler) {
+	panicHandler.Store(&handler)
+}
+
+// runRecovered runs a service, recovering from any panic so that a failing service is reported
+// as a service error (or restarted, per the service restart policy) rather than crashing the
+// application
+func (sm *ShutdownManager) runRecovered(ctx context.Context, name string, opts ServiceOptions, fn func(context.Context) error) error {
+
+	for restarts := 0; ; restarts++ {
+
+		panicked, err := runGuarded(ctx, name, fn)
+		if !panicked {
+			return err
+		}
+
+		if opts.Restart != RestartOnPanic || restarts >= opts.MaxRestarts || ctx.Err() != nil {
+			return err
+		}
+
+		logger.Warn(ctx, logger.APP, fmt.Sprintf("restarting %s service after panic (restart %d of %d)", name, restarts+1, opts.MaxRestarts))
+	}
+
+}
+
+// runGuarded runs a service function once, converting a panic into an ErrServicePanic error
+// after logging its stack trace
+func runGuarded(ctx context.Context, name string, fn func(context.Context) error) (panicked bool, err error) {
+
+	defer func() {
+
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			logger.Error(ctx, logger.APP, fmt.Sprintf("%s service panic: %v\n%s", name, r, stack))
+
+			if handler := panicHandler.Load(); handler != nil && *handler != nil {
+				(*handler)(name, r, stack)
+			}
+
+			err = fmt.Errorf("%w: %s: %v", ErrServicePanic, name, r)
+			panicked = true
+		}
+
+	}()
+
+	return false, fn(ctx)
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	sm "github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// TestRunServicePanic tests that a panicking service is reported as a service error
+func TestRunServicePanic(t *testing.T) {
+
+	logger.Initialize("debug")
+
+	tests := []struct {
+		name string
+		run  func(*sm.ShutdownManager, func(context.Context) error)
+	}{
+		{
+			name: "Run",
+			run: func(m *sm.ShutdownManager, fn func(context.Context) error) {
+				m.Run(fn)
+			},
+		},
+		{
+			name: "RunService",
+			run: func(m *sm.ShutdownManager, fn func(context.Context) error) {
+				m.RunService("video", sm.ServiceOptions{}, fn)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			manager := sm.NewShutdownManager(time.Second)
+
+			tt.run(manager, func(_ context.Context) error {
+				panic("mpv went away")
+			})
+
+			select {
+			case <-(*manager.Context()).Done():
+				// The panic was recovered and reported as a service error
+			case <-time.After(2 * time.Second):
+				t.Fatal("service panic was not reported")
+			}
+
+			manager.Shutdown()
+
+		})
+	}
+
+}
+
+// TestRunServicePanicRestart tests that a service is restarted after a panic per its restart policy
+func TestRunServicePanicRestart(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	opts := sm.ServiceOptions{Restart: sm.RestartOnPanic, MaxRestarts: 2}
+	var runs atomic.Int32
+	done := make(chan error, 1)
+
+	manager.RunService("BLE", opts, func(_ context.Context) error {
+
+		if runs.Add(1) <= 2 {
+			panic("notification handler failed")
+		}
+
+		done <- nil
+
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("service was not restarted after panic")
+	}
+
+	if got := runs.Load(); got != 3 {
+		t.Errorf("service runs = %d, want 3", got)
+	}
+
+	if err := (*manager.Context()).Err(); err != nil {
+		t.Errorf("manager context canceled after restarted service panic: %v", err)
+	}
+
+	manager.Shutdown()
+
+}
+
+// TestRunServicePanicRestartLimit tests that a service panic is reported once restarts are exhausted
+func TestRunServicePanicRestartLimit(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	failed := make(chan error, 1)
+	opts := sm.ServiceOptions{
+		Restart:     sm.RestartOnPanic,
+		MaxRestarts: 1,
+		OnError: func(err error) {
+			failed <- err
+		},
+	}
+	var runs atomic.Int32
+
+	manager.RunService("BLE", opts, func(_ context.Context) error {
+		runs.Add(1)
+		panic("notification handler failed")
+	})
+
+	select {
+	case <-(*manager.Context()).Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("service panic was not reported after restarts were exhausted")
+	}
+
+	if got := runs.Load(); got != 2 {
+		t.Errorf("service runs = %d, want 2", got)
+	}
+
+	if err := <-failed; !errors.Is(err, sm.ErrServicePanic) {
+		t.Errorf("OnError() error = %v, want %v", err, sm.ErrServicePanic)
+	}
+
+	manager.Shutdown()
+
+}
+
+// TestPanicHandler tests that the panic handler is called with the service panic
+func TestPanicHandler(t *testing.T) {
+
+	reported := make(chan string, 1)
+
+	sm.SetPanicHandler(func(service string, r any, stack []byte) {
+
+		if len(stack) == 0 {
+			t.Error("expected the panic handler to receive the stack trace")
+		}
+
+		reported <- service + ": " + r.(string)
+	})
+
+	defer sm.SetPanicHandler(nil)
+
+	manager := sm.NewShutdownManager(time.Second)
+	manager.RunService("video", sm.ServiceOptions{}, func(_ context.Context) error {
+		panic("mpv went away")
+	})
+
+	select {
+	case got := <-reported:
+		if got != "video: mpv went away" {
+			t.Errorf("panic handler reported %q, want %q", got, "video: mpv went away")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("panic handler was not called")
+	}
+
+	manager.Shutdown()
+
+}
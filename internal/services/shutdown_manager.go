@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 )
@@ -22,22 +25,71 @@ type smContext struct {
 	cancel context.CancelFunc
 }
 
+// ServiceOptions defines how a service is torn down when the ShutdownManager shuts down, and how
+// it is recovered if it panics
+type ServiceOptions struct {
+	DependsOn   []string      // Services that must keep running until this service has stopped
+	Timeout     time.Duration // Time allowed for the service to stop (zero uses the manager timeout)
+	Restart     RestartPolicy // Whether the service is restarted after a panic
+	MaxRestarts int           // Maximum number of restarts (when Restart is RestartOnPanic)
+	OnError     func(error)   // Called when the service fails (including an unrecovered panic)
+}
+
+// Shutdown timing: the time allowed for services to stop when no timeout is given, and the
+// interval at which a slow shutdown logs the services it is still waiting on
+const (
+	DefaultShutdownTimeout = 30 * time.Second
+	slowShutdownInterval   = 2 * time.Second
+)
+
+// cleanupFunc represents a named cleanup function
+type cleanupFunc struct {
+	name string
+	fn   func()
+}
+
+// service represents a named service with its own teardown context
+type service struct {
+	name   string
+	opts   ServiceOptions
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
 // ShutdownManager manages an application lifecycle
 type ShutdownManager struct {
 	context    smContext
 	errChan    chan error
-	cleanup    []func()
+	cleanup    []cleanupFunc
+	services   []*service
+	running    map[string]int
+	servicesMu sync.Mutex
+	serviceSeq atomic.Int64
+	watchOnce  sync.Once
+	stopOnce   sync.Once
+	cleanOnce  sync.Once
 	wg         sync.WaitGroup
 	timeout    time.Duration
+	interrupt  atomic.Pointer[func()]
+	signaled   atomic.Bool
 	InstanceID int64
+
+	stopMu      sync.Mutex
+	stopTimeout time.Duration // Time allowed for services to stop (the manager timeout, unless overridden)
+	stopChanged chan struct{} // Closed (and replaced) whenever the stop timeout is overridden
 }
 
 // Instance counter to distinguish between shutdown manager objects
 var shutdownInstanceCounter atomic.Int64
 
-// NewShutdownManager creates a new shutdown manager
+// NewShutdownManager creates a new shutdown manager, allowing services the given timeout to stop
+// when shut down (DefaultShutdownTimeout if the timeout isn't positive)
 func NewShutdownManager(timeout time.Duration) *ShutdownManager {
 
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
 	instanceID := shutdownInstanceCounter.Add(1)
 	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("creating ShutdownManager object (id:%04d)...", instanceID))
 
@@ -51,36 +103,307 @@ func NewShutdownManager(timeout time.Duration) *ShutdownManager {
 			ctx:    ctx,
 			cancel: cancel,
 		},
-		timeout:    timeout,
-		InstanceID: instanceID,
-		errChan:    make(chan error, 1),
+		timeout:     timeout,
+		InstanceID:  instanceID,
+		errChan:     make(chan error, 1),
+		running:     make(map[string]int),
+		stopTimeout: timeout,
+		stopChanged: make(chan struct{}),
 	}
 }
 
 // Run starts a service and waits for it to complete
 func (sm *ShutdownManager) Run(fn func(context.Context) error) {
+	sm.RunNamed(fmt.Sprintf("service-%d", sm.serviceSeq.Add(1)), fn)
+}
+
+// RunNamed starts a named service and waits for it to complete, where the name identifies the
+// service if it blocks shutdown
+func (sm *ShutdownManager) RunNamed(name string, fn func(context.Context) error) {
+
+	sm.trackService(name)
 
 	// Run the function in a goroutine managed by the wait group
 	sm.wg.Go(func() {
+		defer sm.untrackService(name)
+
+		sm.reportError(sm.runRecovered(sm.context.ctx, name, ServiceOptions{}, fn))
+	})
 
-		// if the context is canceled, signal the error channel and return
-		if err := fn(sm.context.ctx); err != nil && !errors.Is(err, context.Canceled) {
+}
+
+// RunService starts a named service that is stopped in dependency order (and within its own
+// timeout) when the shutdown manager shuts down: a service is always stopped before any of the
+// services it depends on
+func (sm *ShutdownManager) RunService(name string, opts ServiceOptions, fn func(context.Context) error) {
+
+	// The service context keeps the manager context values, but is canceled only during teardown
+	ctx, cancel := context.WithCancel(context.WithoutCancel(sm.context.ctx))
+
+	svc := &service{
+		name:   name,
+		opts:   opts,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	sm.servicesMu.Lock()
+	sm.services = append(sm.services, svc)
+	sm.servicesMu.Unlock()
+
+	sm.trackService(name)
+
+	// Tear down services in order whenever the manager context ends (e.g., a service error)
+	sm.watchOnce.Do(func() {
+
+		go func() {
+			<-sm.context.ctx.Done()
+			sm.stopServices()
+		}()
+
+	})
+
+	go func() {
+		defer close(svc.done)
+		defer sm.untrackService(name)
+		defer cancel()
+
+		err := sm.runRecovered(ctx, name, opts, fn)
+		if opts.OnError != nil && err != nil && !errors.Is(err, context.Canceled) {
+			opts.OnError(err)
+		}
+
+		sm.reportError(err)
+	}()
+
+	// A service started after teardown began is stopped right away
+	if sm.context.ctx.Err() != nil {
+		cancel()
+	}
+
+}
+
+// ReportError reports a service failure detected outside of the service itself (e.g., by a
+// watchdog), shutting down the managed services as if the service had returned the error
+func (sm *ShutdownManager) ReportError(err error) {
+	sm.reportError(err)
+}
+
+// reportError signals the first service error and cancels the manager context
+func (sm *ShutdownManager) reportError(err error) {
+
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
 
-			select {
-			case sm.errChan <- err:
-				sm.context.cancel()
-			default:
+	select {
+	case sm.errChan <- err:
+		sm.context.cancel()
+	default:
+	}
+
+}
+
+// stopServices stops the named services in dependency order, waiting on each service for (at
+// most) its own timeout, capped at the stop timeout
+func (sm *ShutdownManager) stopServices() {
+
+	sm.stopOnce.Do(func() {
+
+		sm.servicesMu.Lock()
+		stages := shutdownStages(sm.services)
+		sm.servicesMu.Unlock()
+
+		for _, stage := range stages {
+
+			for _, svc := range stage {
+				svc.cancel()
 			}
 
+			for _, svc := range stage {
+				sm.awaitService(svc)
+			}
 		}
 
 	})
 
 }
 
+// awaitService waits for a canceled service to stop, or for its timeout (capped at the stop
+// timeout) to expire
+func (sm *ShutdownManager) awaitService(svc *service) {
+
+	if sm.awaitDone(svc.done, svc.opts.Timeout) {
+		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) %s service stopped", sm.InstanceID, svc.name))
+
+		return
+	}
+
+	timeout, _ := sm.stopTimeoutFor(svc.opts.Timeout)
+	logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) %s service shutdown timed out (%v)", sm.InstanceID, svc.name, timeout))
+
+}
+
+// awaitDone waits for done to be closed or for the stop timeout (capped at the given service
+// timeout, if any) to expire, periodically logging the services still running so that a slow
+// shutdown isn't a silent hang. The deadline follows any override of the stop timeout made while
+// waiting (e.g., a shorter timeout given to a shutdown that a service error had already started)
+func (sm *ShutdownManager) awaitDone(done <-chan struct{}, serviceTimeout time.Duration) bool {
+
+	start := time.Now()
+	ticker := time.NewTicker(slowShutdownInterval)
+	defer ticker.Stop()
+
+	for {
+
+		timeout, changed := sm.stopTimeoutFor(serviceTimeout)
+		deadline := time.NewTimer(time.Until(start.Add(timeout)))
+
+		select {
+
+		case <-done:
+			deadline.Stop()
+
+			return true
+
+		case <-deadline.C:
+			return false
+
+		case <-changed:
+			deadline.Stop()
+
+		case <-ticker.C:
+			deadline.Stop()
+			logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) shutdown waiting on: %s", sm.InstanceID, strings.Join(sm.RunningServices(), ", ")))
+
+		}
+	}
+
+}
+
+// stopTimeoutFor returns the time allowed for a service to stop (its own timeout, if any, capped
+// at the stop timeout), along with a channel closed if the stop timeout is overridden
+func (sm *ShutdownManager) stopTimeoutFor(serviceTimeout time.Duration) (time.Duration, <-chan struct{}) {
+
+	sm.stopMu.Lock()
+	defer sm.stopMu.Unlock()
+
+	timeout := sm.stopTimeout
+	if serviceTimeout > 0 {
+		timeout = min(serviceTimeout, timeout)
+	}
+
+	return timeout, sm.stopChanged
+}
+
+// overrideStopTimeout overrides the time allowed for services to stop, including for services
+// already being waited on
+func (sm *ShutdownManager) overrideStopTimeout(timeout time.Duration) {
+
+	sm.stopMu.Lock()
+	defer sm.stopMu.Unlock()
+
+	sm.stopTimeout = timeout
+	close(sm.stopChanged)
+	sm.stopChanged = make(chan struct{})
+
+}
+
+// RunningServices returns the (sorted) names of the services that are still running
+func (sm *ShutdownManager) RunningServices() []string {
+
+	sm.servicesMu.Lock()
+	defer sm.servicesMu.Unlock()
+
+	names := make([]string, 0, len(sm.running))
+
+	for name, count := range sm.running {
+
+		for range count {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// trackService records a service as running
+func (sm *ShutdownManager) trackService(name string) {
+
+	sm.servicesMu.Lock()
+	sm.running[name]++
+	sm.servicesMu.Unlock()
+
+}
+
+// untrackService records a service as no longer running
+func (sm *ShutdownManager) untrackService(name string) {
+
+	sm.servicesMu.Lock()
+
+	if sm.running[name]--; sm.running[name] <= 0 {
+		delete(sm.running, name)
+	}
+
+	sm.servicesMu.Unlock()
+
+}
+
+// shutdownStages groups services into teardown stages, where each stage holds the services that no
+// remaining service depends on (services caught in a dependency cycle are stopped together)
+func shutdownStages(services []*service) [][]*service {
+
+	var stages [][]*service
+	remaining := slices.Clone(services)
+
+	for len(remaining) > 0 {
+
+		var stage, next []*service
+
+		for _, svc := range remaining {
+
+			if hasDependents(svc, remaining) {
+				next = append(next, svc)
+			} else {
+				stage = append(stage, svc)
+			}
+		}
+
+		if len(stage) == 0 {
+			stage, next = next, nil
+		}
+
+		stages = append(stages, stage)
+		remaining = next
+	}
+
+	return stages
+}
+
+// hasDependents returns true if any of the other services depends on the given service
+func hasDependents(svc *service, services []*service) bool {
+
+	for _, other := range services {
+
+		if other != svc && slices.Contains(other.opts.DependsOn, svc.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // AddCleanup adds a cleanup function to the shutdown manager
 func (sm *ShutdownManager) AddCleanup(fn func()) {
-	sm.cleanup = append(sm.cleanup, fn)
+	sm.RegisterCleanup(fmt.Sprintf("cleanup-%d", len(sm.cleanup)+1), fn)
+}
+
+// RegisterCleanup adds a named cleanup function to the shutdown manager, where the name identifies
+// the cleanup function in shutdown logging
+func (sm *ShutdownManager) RegisterCleanup(name string, fn func()) {
+	sm.cleanup = append(sm.cleanup, cleanupFunc{name: name, fn: fn})
 }
 
 // Start starts the shutdown manager and listens for shutdown signals
@@ -89,22 +412,83 @@ func (sm *ShutdownManager) Start() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Wait for a shutdown signal
+	// Wait for a shutdown signal (handing each signal to the interrupt handler, if set)
 	go func() {
-		<-sigChan
-		logger.ClearCLILine()
-		logger.Info(logger.BackgroundCtx, logger.APP, "shutdown request detected, shutting down now...")
-		sm.Shutdown()
+
+		for range sigChan {
+
+			if handler := sm.interrupt.Load(); handler != nil {
+				logger.Info(logger.BackgroundCtx, logger.APP, "shutdown request detected, confirming shutdown...")
+				(*handler)()
+
+				continue
+			}
+
+			logger.ClearCLILine()
+			logger.Info(logger.BackgroundCtx, logger.APP, "shutdown request detected, shutting down now...")
+			sm.Interrupt()
+
+			return
+		}
+
 	}()
 
 }
 
-// Shutdown shuts down the shutdown manager
+// Interrupted returns true if the shutdown manager was shut down by a shutdown signal (e.g.,
+// CTRL+C)
+func (sm *ShutdownManager) Interrupted() bool {
+	return sm.signaled.Load()
+}
+
+// Interrupt shuts down the shutdown manager as a shutdown signal does (Interrupted then returns
+// true), for an interrupt handler that shuts down once the shutdown signal is confirmed
+func (sm *ShutdownManager) Interrupt() {
+
+	sm.signaled.Store(true)
+	sm.Shutdown()
+
+}
+
+// SetInterruptHandler sets a handler called on each shutdown signal (e.g., CTRL+C) in place of
+// shutting down, so that the shutdown can first be confirmed (a nil handler restores the default
+// of shutting down immediately)
+func (sm *ShutdownManager) SetInterruptHandler(fn func()) {
+
+	if fn == nil {
+		sm.interrupt.Store(nil)
+
+		return
+	}
+
+	sm.interrupt.Store(&fn)
+
+}
+
+// Shutdown shuts down the shutdown manager, allowing services the manager timeout (or the timeout
+// given to an earlier ShutdownWithin) to stop
 func (sm *ShutdownManager) Shutdown() {
+	sm.ShutdownWithin(0)
+}
+
+// ShutdownWithin shuts down the shutdown manager, allowing services the given timeout to stop in
+// place of the manager timeout (e.g., a longer timeout for a media player slow to release its
+// video file, or a much shorter timeout when exiting right away). The timeout also applies to a
+// shutdown already under way (e.g., one started by a service error), and a timeout that isn't
+// positive leaves the time allowed unchanged
+func (sm *ShutdownManager) ShutdownWithin(timeout time.Duration) {
+
+	if timeout > 0 {
+		sm.overrideStopTimeout(timeout)
+	}
 
 	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("shutting down ShutdownManager object (id:%04d)...", sm.InstanceID))
 
 	sm.context.cancel()
+
+	// Stop named services in dependency order before waiting on the remaining services
+	sm.stopServices()
+
 	done := make(chan struct{})
 
 	go func() {
@@ -112,25 +496,32 @@ func (sm *ShutdownManager) Shutdown() {
 		close(done)
 	}()
 
-	select {
-
-	case <-done:
+	if sm.awaitDone(done, 0) {
 		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) services stopped", sm.InstanceID))
+	} else {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) shutdown timed out waiting on: %s", sm.InstanceID, strings.Join(sm.RunningServices(), ", ")))
+	}
 
-	case <-time.After(sm.timeout):
-		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) shutdown timed out", sm.InstanceID))
+	// Execute cleanup functions in reverse order (only once, however many times the shutdown
+	// manager is shut down, e.g., by CTRL+C while a session is being stopped)
+	sm.cleanOnce.Do(func() {
 
-	}
+		for i := len(sm.cleanup) - 1; i >= 0; i-- {
+			logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager (id:%04d) running %s", sm.InstanceID, sm.cleanup[i].name))
+			sm.cleanup[i].fn()
+		}
 
-	// Execute cleanup functions in reverse order
-	for i := len(sm.cleanup) - 1; i >= 0; i-- {
-		sm.cleanup[i]()
-	}
+	})
 
 	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("ShutdownManager object (id:%04d) shutdown complete", sm.InstanceID))
 
 }
 
+// Timeout returns the time services are allowed to stop when the shutdown manager is shut down
+func (sm *ShutdownManager) Timeout() time.Duration {
+	return sm.timeout
+}
+
 // Context returns the shutdown manager's context
 func (sm *ShutdownManager) Context() *context.Context {
 	return &sm.context.ctx
@@ -169,6 +560,12 @@ func WaveHello(ctx context.Context) {
 
 // WaveGoodbye outputs a goodbye message and exits the program
 func WaveGoodbye(ctx context.Context) {
+	WaveGoodbyeWithExitCode(ctx, apperr.ExitOK)
+}
+
+// WaveGoodbyeWithExitCode outputs a goodbye message and exits the program with the given exit code
+// (see the apperr package)
+func WaveGoodbyeWithExitCode(ctx context.Context, code int) {
 
 	// Redirect logging to the console, clear the CLI line, and set the log level so this final
 	// shutdown message is visible regardless of application mode (CLI or GUI)
@@ -180,6 +577,6 @@ func WaveGoodbye(ctx context.Context) {
 	logger.Info(ctx, logger.APP, config.GetFullVersion()+" shutdown complete. Goodbye")
 	drawLine(ctx)
 
-	os.Exit(0)
+	os.Exit(code)
 
 }
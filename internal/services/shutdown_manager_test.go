@@ -3,6 +3,9 @@ package services_test
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -31,6 +34,14 @@ func TestNewShutdownManager(t *testing.T) {
 		t.Fatal("expected non-nil context")
 	}
 
+	if got := manager.Timeout(); got != timeout {
+		t.Errorf("Timeout() = %v, want %v", got, timeout)
+	}
+
+	if got := sm.NewShutdownManager(0).Timeout(); got != sm.DefaultShutdownTimeout {
+		t.Errorf("Timeout() without a timeout = %v, want %v", got, sm.DefaultShutdownTimeout)
+	}
+
 }
 
 // TestRunService tests the Run method of the shutdown manager
@@ -182,3 +193,305 @@ func TestContextCancellation(t *testing.T) {
 	}
 
 }
+
+// TestRunServiceTeardownOrder tests that services are stopped before the services they depend on
+func TestRunServiceTeardownOrder(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	stopped := make(chan string, 3)
+
+	// Register dependents first to confirm that order doesn't depend on registration order
+	services := []struct {
+		name      string
+		dependsOn []string
+	}{
+		{"recorder", []string{"video"}},
+		{"video", []string{"BLE"}},
+		{"BLE", nil},
+	}
+
+	for _, svc := range services {
+
+		manager.RunService(svc.name, sm.ServiceOptions{DependsOn: svc.dependsOn}, func(ctx context.Context) error {
+			<-ctx.Done()
+			stopped <- svc.name
+
+			return ctx.Err()
+		})
+	}
+
+	manager.Shutdown()
+	close(stopped)
+
+	order := make([]string, 0, len(services))
+	for name := range stopped {
+		order = append(order, name)
+	}
+
+	expected := []string{"recorder", "video", "BLE"}
+
+	if len(order) != len(expected) {
+		t.Fatalf("stopped services = %v, want %v", order, expected)
+	}
+
+	for i, name := range order {
+
+		if name != expected[i] {
+			t.Errorf("teardown order = %v, want %v", order, expected)
+		}
+	}
+
+}
+
+// TestRunServiceTimeout tests that a service that won't stop is abandoned after its own timeout
+func TestRunServiceTimeout(t *testing.T) {
+
+	manager := sm.NewShutdownManager(5 * time.Second)
+	serviceTimeout := 100 * time.Millisecond
+	release := make(chan struct{})
+
+	defer close(release)
+
+	manager.RunService("stuck", sm.ServiceOptions{Timeout: serviceTimeout}, func(_ context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	start := time.Now()
+	manager.Shutdown()
+
+	if duration := time.Since(start); duration > serviceTimeout*5 {
+		t.Errorf("shutdown took %v, expected about %v", duration, serviceTimeout)
+	}
+
+}
+
+// TestShutdownWithin tests that a shutdown timeout given to a single shutdown overrides the
+// manager and service timeouts
+func TestShutdownWithin(t *testing.T) {
+
+	manager := sm.NewShutdownManager(5 * time.Second)
+	shutdownTimeout := 100 * time.Millisecond
+	release := make(chan struct{})
+
+	defer close(release)
+
+	manager.RunService("stuck", sm.ServiceOptions{Timeout: 5 * time.Second}, func(_ context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	start := time.Now()
+	manager.ShutdownWithin(shutdownTimeout)
+
+	if duration := time.Since(start); duration > shutdownTimeout*5 {
+		t.Errorf("shutdown took %v, expected about %v", duration, shutdownTimeout)
+	}
+
+}
+
+// TestShutdownWithinAfterError tests that a shutdown timeout given to a shutdown that a service
+// error has already started still overrides the manager and service timeouts
+func TestShutdownWithinAfterError(t *testing.T) {
+
+	manager := sm.NewShutdownManager(5 * time.Second)
+	shutdownTimeout := 100 * time.Millisecond
+	release := make(chan struct{})
+
+	defer close(release)
+
+	manager.RunService("stuck", sm.ServiceOptions{Timeout: 5 * time.Second}, func(_ context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	manager.RunService("failing", sm.ServiceOptions{}, func(_ context.Context) error {
+		return errServiceError
+	})
+
+	// The service error starts the shutdown, which is given time to begin waiting on the stuck
+	// service
+	<-(*manager.Context()).Done()
+	time.Sleep(shutdownTimeout)
+
+	start := time.Now()
+	manager.ShutdownWithin(shutdownTimeout)
+
+	if duration := time.Since(start); duration > shutdownTimeout*5 {
+		t.Errorf("shutdown took %v, expected about %v", duration, shutdownTimeout)
+	}
+
+}
+
+// TestRunServiceErrorStopsServices tests that a failing service stops the other services
+func TestRunServiceErrorStopsServices(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	serviceCanceled := make(chan struct{})
+
+	manager.RunService("video", sm.ServiceOptions{DependsOn: []string{"BLE"}}, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(serviceCanceled)
+
+		return ctx.Err()
+	})
+
+	manager.RunService("BLE", sm.ServiceOptions{}, func(_ context.Context) error {
+		return errServiceError
+	})
+
+	select {
+	case <-serviceCanceled:
+		// Dependent service was canceled after the service error
+	case <-time.After(2 * time.Second):
+		t.Fatal("service was not canceled after service error")
+	}
+
+}
+
+// TestRunningServices tests that named services are listed while running and removed once stopped
+func TestRunningServices(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	release := make(chan struct{})
+
+	manager.RunNamed("session startup", func(_ context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	manager.RunService("video", sm.ServiceOptions{}, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	expected := []string{"session startup", "video"}
+	running := manager.RunningServices()
+
+	if len(running) != len(expected) {
+		t.Fatalf("RunningServices() = %v, want %v", running, expected)
+	}
+
+	for i, name := range running {
+
+		if name != expected[i] {
+			t.Errorf("RunningServices() = %v, want %v", running, expected)
+		}
+	}
+
+	close(release)
+	manager.Shutdown()
+
+	if running := manager.RunningServices(); len(running) != 0 {
+		t.Errorf("RunningServices() after shutdown = %v, want none", running)
+	}
+
+}
+
+// TestRegisterCleanup tests that named cleanup functions run alongside unnamed ones in reverse order
+func TestRegisterCleanup(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	order := make([]string, 0, 2)
+
+	manager.RegisterCleanup("close file", func() {
+		order = append(order, "close file")
+	})
+
+	manager.AddCleanup(func() {
+		order = append(order, "flush recorder")
+	})
+
+	manager.Shutdown()
+
+	expected := []string{"flush recorder", "close file"}
+
+	if len(order) != len(expected) || order[0] != expected[0] || order[1] != expected[1] {
+		t.Errorf("cleanup order = %v, want %v", order, expected)
+	}
+
+}
+
+// TestInterruptHandler tests that a shutdown signal is handed to the interrupt handler (if set)
+// rather than shutting down the shutdown manager
+func TestInterruptHandler(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+	interrupted := make(chan struct{}, 1)
+
+	manager.SetInterruptHandler(func() {
+		interrupted <- struct{}{}
+	})
+
+	manager.Start()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("failed to send SIGINT: %v", err)
+	}
+
+	select {
+	case <-interrupted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("interrupt handler was not called")
+	}
+
+	if err := (*manager.Context()).Err(); err != nil {
+		t.Errorf("context error after interrupt = %v, want nil (no shutdown)", err)
+	}
+
+	manager.Shutdown()
+
+}
+
+// TestInterrupt tests that Interrupt shuts down the shutdown manager as a shutdown signal does
+func TestInterrupt(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+
+	if manager.Interrupted() {
+		t.Error("Interrupted() = true before Interrupt, want false")
+	}
+
+	manager.Interrupt()
+
+	if !manager.Interrupted() {
+		t.Error("Interrupted() = false after Interrupt, want true")
+	}
+
+	if err := (*manager.Context()).Err(); err == nil {
+		t.Error("context not canceled after Interrupt")
+	}
+
+}
+
+// TestConcurrentShutdown tests that cleanup functions run only once when the shutdown manager is
+// shut down more than once at the same time (e.g., by CTRL+C while a session is being stopped)
+func TestConcurrentShutdown(t *testing.T) {
+
+	manager := sm.NewShutdownManager(time.Second)
+
+	var calls atomic.Int32
+
+	manager.AddCleanup(func() {
+		calls.Add(1)
+	})
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Go(manager.Shutdown)
+	}
+
+	wg.Go(manager.Interrupt)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("cleanup function called %d times, want 1", got)
+	}
+
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// TickStats holds the drift of the ticks delivered by a TickScheduler from their scheduled times
+type TickStats struct {
+	Ticks   int           // Number of ticks delivered
+	Skipped int           // Number of ticks skipped while processing overran the interval
+	Mean    time.Duration // Mean lateness of the ticks delivered
+	Max     time.Duration // Maximum lateness of the ticks delivered
+}
+
+// String returns the tick drift in milliseconds (e.g., "mean 1ms, max 12ms, 0 ticks skipped")
+func (s TickStats) String() string {
+	return fmt.Sprintf("mean %dms, max %dms, %d ticks skipped", s.Mean.Milliseconds(), s.Max.Milliseconds(), s.Skipped)
+}
+
+// TickScheduler delivers ticks at a fixed interval, scheduling every tick from the start time on
+// the monotonic clock (rather than from the previous tick), so the time spent processing a tick,
+// or a tick delivered late, doesn't push back the ticks that follow. Of the ticks missed while
+// processing overran the interval, only the first is delivered (late), and the rest are skipped
+// rather than delivered in a burst. A TickScheduler isn't safe for concurrent use
+type TickScheduler struct {
+	interval  time.Duration
+	start     time.Time
+	next      int64 // Number of the next tick, counted from the start time
+	timer     *time.Timer
+	stats     TickStats
+	totalLate time.Duration
+}
+
+// NewTickScheduler creates a tick scheduler delivering ticks at the given interval, with the first
+// tick due one interval from now
+func NewTickScheduler(interval time.Duration) *TickScheduler {
+
+	interval = max(interval, time.Millisecond)
+
+	return &TickScheduler{
+		interval: interval,
+		start:    time.Now(),
+		next:     1,
+		timer:    time.NewTimer(interval),
+	}
+}
+
+// C returns the channel on which ticks are delivered (each tick received must be followed by a
+// call to Tick)
+func (s *TickScheduler) C() <-chan time.Time {
+	return s.timer.C
+}
+
+// Tick records the drift of the tick just received, schedules the next tick, and returns the time
+// the tick received was scheduled for (the time used for the work done on each tick, so that
+// elapsed times add up to whole intervals)
+func (s *TickScheduler) Tick() time.Time {
+
+	now := time.Now()
+	scheduled := s.at(s.next)
+	late := max(now.Sub(scheduled), 0)
+
+	s.stats.Ticks++
+	s.totalLate += late
+	s.stats.Mean = s.totalLate / time.Duration(s.stats.Ticks)
+	s.stats.Max = max(s.stats.Max, late)
+
+	// Skip any ticks already due, so the next tick is the first scheduled after now
+	next := s.next + 1
+	if due := int64(now.Sub(s.start)/s.interval) + 1; due > next {
+		s.stats.Skipped += int(due - next)
+		next = due
+	}
+
+	s.next = next
+	s.timer.Reset(s.at(next).Sub(now))
+
+	return scheduled
+}
+
+// Stats returns the drift of the ticks delivered so far
+func (s *TickScheduler) Stats() TickStats {
+	return s.stats
+}
+
+// Stop stops the tick scheduler, after which no more ticks are delivered
+func (s *TickScheduler) Stop() {
+	s.timer.Stop()
+}
+
+// at returns the scheduled time of a tick
+func (s *TickScheduler) at(tick int64) time.Time {
+	return s.start.Add(time.Duration(tick) * s.interval)
+}
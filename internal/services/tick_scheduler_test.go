@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	sm "github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// Interval of the tick schedulers tested
+const testTickInterval = 20 * time.Millisecond
+
+// TestTickScheduler tests that ticks stay on their schedule, however long each tick takes to
+// process
+func TestTickScheduler(t *testing.T) {
+
+	ticks := sm.NewTickScheduler(testTickInterval)
+	defer ticks.Stop()
+
+	var first time.Time
+
+	for i := range 10 {
+
+		<-ticks.C()
+		scheduled := ticks.Tick()
+
+		if i == 0 {
+			first = scheduled
+		} else if got, want := scheduled.Sub(first), time.Duration(i)*testTickInterval; got != want {
+			t.Errorf("tick %d scheduled %v after the first tick, want %v", i, got, want)
+		}
+
+		// Processing time doesn't push back the next tick
+		time.Sleep(testTickInterval / 4)
+	}
+
+	stats := ticks.Stats()
+
+	if stats.Ticks != 10 {
+		t.Errorf("Stats().Ticks = %d, want 10", stats.Ticks)
+	}
+
+	if stats.Max < stats.Mean {
+		t.Errorf("Stats().Max = %v, want at least Stats().Mean (%v)", stats.Max, stats.Mean)
+	}
+
+}
+
+// TestTickSchedulerOverrun tests that the ticks missed while processing overran the interval are
+// skipped, rather than delivered in a burst
+func TestTickSchedulerOverrun(t *testing.T) {
+
+	ticks := sm.NewTickScheduler(testTickInterval)
+	defer ticks.Stop()
+
+	<-ticks.C()
+	first := ticks.Tick()
+
+	// Overrun the interval by more than three ticks
+	time.Sleep(3*testTickInterval + testTickInterval/2)
+
+	// The late tick is delivered, and then the ticks that were also missed are skipped
+	<-ticks.C()
+	if got := ticks.Tick().Sub(first); got != testTickInterval {
+		t.Errorf("late tick scheduled %v after the previous tick, want %v", got, testTickInterval)
+	}
+
+	<-ticks.C()
+	if got := ticks.Tick().Sub(first); got < 4*testTickInterval || got%testTickInterval != 0 {
+		t.Errorf("tick after overrun scheduled %v after the first tick, want a whole number of intervals (at least %v)", got, 4*testTickInterval)
+	}
+
+	stats := ticks.Stats()
+
+	if stats.Skipped < 2 {
+		t.Errorf("Stats().Skipped = %d, want at least 2", stats.Skipped)
+	}
+
+	if stats.Max < 2*testTickInterval {
+		t.Errorf("Stats().Max = %v, want at least %v", stats.Max, 2*testTickInterval)
+	}
+
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Heartbeat records the progress of a service monitored by a Watchdog
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+// Beat records that the service has made progress
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Last returns the time of the most recent heartbeat
+func (h *Heartbeat) Last() time.Time {
+	return time.Unix(0, h.last.Load())
+}
+
+// monitor represents a service monitored by a Watchdog
+type monitor struct {
+	name      string
+	heartbeat *Heartbeat
+	timeout   time.Duration
+	onStall   func(stalled time.Duration)
+	stalled   bool
+}
+
+// Watchdog monitors service heartbeats, calling a service stall handler when a service stops
+// making progress (e.g., the media player hangs)
+type Watchdog struct {
+	monitors []*monitor
+	interval time.Duration
+	mu       sync.Mutex
+}
+
+// NewWatchdog creates a new watchdog that checks service heartbeats at the given interval
+func NewWatchdog(interval time.Duration) *Watchdog {
+
+	return &Watchdog{
+		interval: interval,
+	}
+}
+
+// Monitor adds a service to the watchdog, returning the heartbeat the service uses to report
+// progress: if no heartbeat is recorded within the timeout, onStall is called (once per stall)
+func (w *Watchdog) Monitor(name string, timeout time.Duration, onStall func(stalled time.Duration)) *Heartbeat {
+
+	heartbeat := &Heartbeat{}
+	heartbeat.Beat()
+
+	w.mu.Lock()
+	w.monitors = append(w.monitors, &monitor{
+		name:      name,
+		heartbeat: heartbeat,
+		timeout:   timeout,
+		onStall:   onStall,
+	})
+	w.mu.Unlock()
+
+	return heartbeat
+}
+
+// Run checks service heartbeats until the context is canceled
+func (w *Watchdog) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case now := <-ticker.C:
+			w.check(ctx, now)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+}
+
+// check compares each service heartbeat against its timeout, reporting stalled services
+func (w *Watchdog) check(ctx context.Context, now time.Time) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, m := range w.monitors {
+
+		since := now.Sub(m.heartbeat.Last())
+
+		if since < m.timeout {
+
+			if m.stalled {
+				logger.Info(ctx, logger.APP, fmt.Sprintf("watchdog: %s service is making progress again", m.name))
+				m.stalled = false
+			}
+
+			continue
+		}
+
+		if m.stalled {
+			continue
+		}
+
+		m.stalled = true
+		logger.Warn(ctx, logger.APP, fmt.Sprintf("watchdog: %s service has made no progress for %v", m.name, since.Round(time.Second)))
+
+		if m.onStall != nil {
+			m.onStall(since)
+		}
+	}
+
+}
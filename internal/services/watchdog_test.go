@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	sm "github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// TestWatchdog tests that the watchdog reports a stalled service once, but not a healthy service
+func TestWatchdog(t *testing.T) {
+
+	logger.Initialize("debug")
+
+	watchdog := sm.NewWatchdog(10 * time.Millisecond)
+	var healthyStalls, hungStalls atomic.Int32
+
+	healthy := watchdog.Monitor("BLE", 50*time.Millisecond, func(_ time.Duration) {
+		healthyStalls.Add(1)
+	})
+
+	watchdog.Monitor("video", 50*time.Millisecond, func(_ time.Duration) {
+		hungStalls.Add(1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := watchdog.Run(ctx); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+
+	}()
+
+	// Keep one service making progress while the other stalls
+	for range 20 {
+		healthy.Beat()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if got := healthyStalls.Load(); got != 0 {
+		t.Errorf("healthy service stalls = %d, want 0", got)
+	}
+
+	if got := hungStalls.Load(); got != 1 {
+		t.Errorf("stalled service stalls = %d, want 1", got)
+	}
+
+}
+
+// TestHeartbeat tests that a heartbeat records the time of the last beat
+func TestHeartbeat(t *testing.T) {
+
+	var heartbeat sm.Heartbeat
+
+	before := time.Now()
+	heartbeat.Beat()
+
+	if last := heartbeat.Last(); last.Before(before) || last.After(time.Now()) {
+		t.Errorf("Last() = %v, want a time after %v", last, before)
+	}
+
+}
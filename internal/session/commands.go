@@ -0,0 +1,78 @@
+package session
+
+import (
+	"sync"
+)
+
+// commandQueue runs the session lifecycle commands (start, stop, pause and resume) one at a time,
+// in the order they're requested, so that requests racing each other (e.g., a GUI button clicked
+// rapidly, or CTRL+C pressed while the GUI stops the session) never interleave their state changes
+type commandQueue struct {
+	pending []func()
+	running bool // A goroutine is running the pending commands
+	mu      sync.Mutex
+}
+
+// submit queues a command without waiting for it to run, returning a channel that receives the
+// result of the command
+func (q *commandQueue) submit(cmd func() error) <-chan error {
+
+	result := make(chan error, 1)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, func() {
+		result <- cmd()
+	})
+
+	if !q.running {
+		q.running = true
+		go q.drain()
+	}
+
+	return result
+}
+
+// run queues a command and waits until it has run, returning its result
+func (q *commandQueue) run(cmd func() error) error {
+	return <-q.submit(cmd)
+}
+
+// drain runs the pending commands until none are left
+func (q *commandQueue) drain() {
+
+	for {
+
+		q.mu.Lock()
+
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+
+			return
+		}
+
+		cmd := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		cmd()
+	}
+
+}
+
+// pendingStart is a session startup in progress, whose result is shared by every start request
+// made before the startup completes (e.g., a Start button clicked twice)
+type pendingStart struct {
+	done chan struct{}
+	err  error
+}
+
+// wait waits until the session startup completes, returning its result
+func (s *pendingStart) wait() error {
+
+	<-s.done
+
+	return s.err
+}
@@ -0,0 +1,420 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// Number of start and stop requests made by the rapid clicking tests
+const rapidClicks = 10
+
+// newCommandTestManager creates a session manager with a loaded self-test session
+func newCommandTestManager(t *testing.T) (*StateManager, *selfTestPlayer) {
+
+	t.Helper()
+
+	videoPath := filepath.Join(t.TempDir(), selfTestVideoFile)
+	if err := os.WriteFile(videoPath, nil, 0o600); err != nil {
+		t.Fatalf("failed to create video file: %v", err)
+	}
+
+	player := &selfTestPlayer{}
+	m := NewManagerWithFactory(selfTestFactory(player))
+
+	if err := m.UpdateLoadedSession(selfTestConfig(videoPath), ""); err != nil {
+		t.Fatalf("UpdateLoadedSession() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = m.StopSession()
+		m.historyWrites.Wait()
+	})
+
+	return m, player
+}
+
+// checkStopped confirms that a session manager has stopped its session
+func checkStopped(t *testing.T, m *StateManager) {
+
+	t.Helper()
+
+	if state := m.SessionState(); state != StateLoaded {
+		t.Errorf("SessionState() = %v, want %v", state, StateLoaded)
+	}
+
+	if m.IsStarting() {
+		t.Error("IsStarting() = true after stopping, want false")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.controllers != nil || m.shutdownMgr != nil {
+		t.Error("controllers and shutdown manager not released after stopping")
+	}
+
+}
+
+// currentShutdownMgr returns the shutdown manager of the session
+func currentShutdownMgr(m *StateManager) *services.ShutdownManager {
+
+	defer m.readLock()()
+
+	return m.shutdownMgr
+}
+
+// awaitResult returns a channel closed once a command result is received
+func awaitResult(result <-chan error) <-chan struct{} {
+
+	done := make(chan struct{})
+
+	go func() {
+		<-result
+		close(done)
+	}()
+
+	return done
+}
+
+// TestCommandQueue tests that queued commands run one at a time, in the order they're submitted
+func TestCommandQueue(t *testing.T) {
+
+	var (
+		q       commandQueue
+		running atomic.Int32
+		order   []int
+		results []<-chan error
+	)
+
+	errOdd := errors.New("odd command")
+
+	for i := range 20 {
+
+		results = append(results, q.submit(func() error {
+
+			if running.Add(1) > 1 {
+				t.Error("commands ran at the same time")
+			}
+
+			defer running.Add(-1)
+
+			time.Sleep(time.Millisecond)
+			order = append(order, i)
+
+			if i%2 == 1 {
+				return errOdd
+			}
+
+			return nil
+		}))
+	}
+
+	for i, result := range results {
+
+		if err := <-result; (i%2 == 1) != errors.Is(err, errOdd) {
+			t.Errorf("command %d result = %v", i, err)
+		}
+	}
+
+	for i, got := range order {
+
+		if got != i {
+			t.Fatalf("commands ran in order %v, want submission order", order)
+		}
+	}
+
+	if err := q.run(func() error { return nil }); err != nil {
+		t.Errorf("run() error = %v, want nil", err)
+	}
+
+}
+
+// TestDuplicateStart tests that a start requested while another is pending shares its result,
+// and that starting a running session does nothing
+func TestDuplicateStart(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	first, second := m.StartSessionAsync(), m.StartSessionAsync()
+
+	if !m.IsStarting() {
+		t.Error("IsStarting() = false once a start is requested, want true")
+	}
+
+	if err := <-first; err != nil {
+		t.Fatalf("first StartSession() error = %v", err)
+	}
+
+	if err := <-second; err != nil {
+		t.Fatalf("second StartSession() error = %v, want nil (shared start)", err)
+	}
+
+	shutdownMgr := currentShutdownMgr(m)
+
+	if err := m.StartSession(); err != nil {
+		t.Errorf("StartSession() of a running session error = %v, want nil", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.shutdownMgr != shutdownMgr {
+		t.Error("StartSession() of a running session started another session")
+	}
+
+}
+
+// TestRapidStartStop tests that start and stop requests made at the same time (e.g., a Start/Stop
+// button clicked rapidly) leave the session in a consistent state
+func TestRapidStartStop(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	var wg sync.WaitGroup
+
+	for range rapidClicks {
+
+		wg.Go(func() {
+			if err := m.StartSession(); err != nil && !errors.Is(err, context.Canceled) {
+				t.Errorf("StartSession() error = %v", err)
+			}
+		})
+
+		wg.Go(func() {
+			if err := m.StopSession(); err != nil {
+				t.Errorf("StopSession() error = %v", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	// Each start requested after the last stop has either started the session or failed
+	if err := m.StopSession(); err != nil {
+		t.Fatalf("StopSession() error = %v", err)
+	}
+
+	checkStopped(t, m)
+
+	// The session can still be started and stopped
+	if err := m.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	if err := m.StopSession(); err != nil {
+		t.Fatalf("StopSession() error = %v", err)
+	}
+
+	checkStopped(t, m)
+
+}
+
+// TestStopDuringStart tests that a stop requested right after a start cancels the pending start
+func TestStopDuringStart(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	for range rapidClicks {
+
+		result := m.StartSessionAsync()
+
+		if err := m.StopSession(); err != nil {
+			t.Fatalf("StopSession() error = %v", err)
+		}
+
+		// The start may have completed before the stop was run
+		if err := <-result; err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("StartSession() error = %v, want nil or %v", err, context.Canceled)
+		}
+
+		checkStopped(t, m)
+	}
+
+}
+
+// TestStopIdempotent tests that stopping a session that isn't running does nothing
+func TestStopIdempotent(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	for range 2 {
+
+		if err := m.StopSession(); err != nil {
+			t.Errorf("StopSession() of a stopped session error = %v, want nil", err)
+		}
+
+		checkStopped(t, m)
+	}
+
+}
+
+// TestPauseResumeIdempotent tests that pausing a paused session (or resuming a running session)
+// does nothing
+func TestPauseResumeIdempotent(t *testing.T) {
+
+	m, player := newCommandTestManager(t)
+
+	if err := m.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	if err := m.ResumeSession(); err != nil || m.SessionState() != StateRunning {
+		t.Errorf("ResumeSession() of a running session = (%v, %v), want (nil, %v)", err, m.SessionState(), StateRunning)
+	}
+
+	for range 2 {
+
+		if err := m.PauseSession(); err != nil || m.SessionState() != StatePaused || !player.paused.Load() {
+			t.Errorf("PauseSession() = (%v, %v), want (nil, %v)", err, m.SessionState(), StatePaused)
+		}
+	}
+
+	for range 2 {
+
+		if err := m.ResumeSession(); err != nil || m.SessionState() != StateRunning || player.paused.Load() {
+			t.Errorf("ResumeSession() = (%v, %v), want (nil, %v)", err, m.SessionState(), StateRunning)
+		}
+	}
+
+	if err := m.StopSession(); err != nil {
+		t.Fatalf("StopSession() error = %v", err)
+	}
+
+	if err := m.PauseSession(); !errors.Is(err, errNoActivePlayback) {
+		t.Errorf("PauseSession() of a stopped session error = %v, want %v", err, errNoActivePlayback)
+	}
+
+}
+
+// TestInterruptDuringStop tests that a shutdown signal (e.g., CTRL+C) received while the session
+// is being stopped neither deadlocks nor leaves the session running
+func TestInterruptDuringStop(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	for range rapidClicks {
+
+		if err := m.StartSession(); err != nil {
+			t.Fatalf("StartSession() error = %v", err)
+		}
+
+		ctx := m.Context()
+		shutdownMgr := currentShutdownMgr(m)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			if err := m.StopSession(); err != nil {
+				t.Errorf("StopSession() error = %v", err)
+			}
+		}()
+
+		interrupted := m.requestInterrupt(shutdownMgr)
+
+		for _, ch := range []<-chan struct{}{done, awaitResult(interrupted)} {
+
+			select {
+			case <-ch:
+			case <-time.After(2 * selfTestStepTimeout):
+				t.Fatal("StopSession() deadlocked with a shutdown signal")
+			}
+		}
+
+		if ctx.Err() == nil {
+			t.Error("session context not canceled after stopping")
+		}
+
+		checkStopped(t, m)
+	}
+
+}
+
+// TestInterruptDuringStart tests that a shutdown signal (e.g., CTRL+C) received while the session
+// is starting cancels the start (unless the session has already started)
+func TestInterruptDuringStart(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	for range rapidClicks {
+
+		result := m.StartSessionAsync()
+
+		// Wait until the start has been run, so that its shutdown manager can be signaled
+		_ = m.commands.run(func() error { return nil })
+		<-m.requestInterrupt(currentShutdownMgr(m))
+
+		err := <-result
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("StartSession() error = %v, want nil or %v", err, context.Canceled)
+		}
+
+		// A session that had started is left for ReleaseSession
+		if err == nil {
+			m.Wait()
+			m.ReleaseSession()
+		}
+
+		if err := m.StopSession(); err != nil {
+			t.Fatalf("StopSession() error = %v", err)
+		}
+
+		checkStopped(t, m)
+	}
+
+}
+
+// TestInterrupt tests that a shutdown signal (e.g., CTRL+C) shuts down a CLI session, leaving its
+// controllers for ReleaseSession, while a session whose shutdown signals are handled by the
+// application keeps running
+func TestInterrupt(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+
+	if err := m.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	shutdownMgr := currentShutdownMgr(m)
+
+	// A session of the GUI ignores the signal
+	m.mu.Lock()
+	m.ignoreInterrupts = true
+	m.mu.Unlock()
+
+	<-m.requestInterrupt(shutdownMgr)
+
+	if m.Interrupted() || m.Context().Err() != nil {
+		t.Error("session with shutdown signals handled by the application was interrupted")
+	}
+
+	// A CLI session is shut down by the signal
+	m.mu.Lock()
+	m.ignoreInterrupts = false
+	m.mu.Unlock()
+
+	<-m.requestInterrupt(shutdownMgr)
+
+	if !m.Interrupted() || m.Context().Err() == nil {
+		t.Error("session was not interrupted by the shutdown signal")
+	}
+
+	m.Wait()
+	m.ReleaseSession()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.controllers != nil {
+		t.Error("ReleaseSession() did not release the interrupted session's controllers")
+	}
+
+}
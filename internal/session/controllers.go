@@ -4,157 +4,657 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
 	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/services"
 	"github.com/richbl/go-ble-sync-cycle/internal/speed"
 	"github.com/richbl/go-ble-sync-cycle/internal/video"
-	"tinygo.org/x/bluetooth"
 )
 
 // Error definitions
 var (
 	errNoActiveConfig            = errors.New("cannot initialize controllers: no active configuration")
-	errNoActiveSession           = errors.New("no active session to stop")
+	errNoActivePlayback          = errors.New("no active video playback")
+	errSessionNotRunning         = errors.New("session is not running")
 	errInitializeControllers     = errors.New("failed to initialize controllers")
 	errBLEConnectionFailed       = errors.New("failed to connect to BLE device")
-	ErrFailedToGetBatteryService = errors.New("failed to get battery service")
-	ErrFailedToGetBatteryLevel   = errors.New("failed to get battery level")
+	ErrFailedToGetBatteryService = apperr.New(apperr.CodeSensorTimeout, "failed to get battery service")
+	ErrFailedToGetBatteryLevel   = apperr.New(apperr.CodeSensorTimeout, "failed to get battery level")
+	errServiceStalled            = apperr.New(apperr.CodePlayerStalled, "service stopped making progress")
+	errSensorNotConnected        = errors.New("no BLE sensor connected awaiting video playback")
+)
+
+// Service teardown settings: video playback is stopped before BLE notifications are disabled (the
+// media player is allowed the session's shutdown timeout to stop), and a panicking BLE service is
+// restarted (a panicking video service errors the session)
+const (
+	bleServiceName        = "BLE"
+	videoServiceName      = "video"
+	bleServiceTimeout     = 5 * time.Second
+	bleServiceMaxRestarts = 3
+)
+
+// Watchdog settings: a stalled video service (e.g., a hung media player) errors the session, while
+// a quiet BLE sensor is only reported (sensors may stop notifying when the wheel stops turning)
+const (
+	watchdogServiceName = "watchdog"
+	watchdogInterval    = 5 * time.Second
+	videoStallTimeout   = 30 * time.Second
+	bleStallTimeout     = 2 * time.Minute
+	watchdogStopTimeout = time.Second
 )
 
 // controllers holds the application component controllers
 type controllers struct {
 	speedController *speed.Controller
-	videoPlayer     *video.PlaybackController
-	bleController   *ble.Controller
-	bleDevice       bluetooth.Device
+	videoPlayer     VideoPlayer
+	bleController   *ble.Controller   // nil when another speed source is used
+	source          speed.SpeedSource // nil when using a BLE sensor
+	bleDevice       ble.Device
+	goal            *speed.GoalTracker
+	intervals       *speed.IntervalTimer
+	laps            *speed.LapTracker
+	race            *speed.RaceScorer
+	env             *envTracker // nil when the session has no environmental sensor
+	videoFirst      bool        // Video playback starts (paused) before the BLE sensor connects
+	servicesStarted bool        // BLE and video services are running
 }
 
-// StartSession initializes controllers and starts BLE and video services
+// StartSession initializes controllers and starts BLE and video services, waiting until the
+// session startup completes, fails, or is canceled. A start requested while another is pending
+// shares the result of the pending start, and starting a running session does nothing
 func (m *StateManager) StartSession() error {
+	return <-m.StartSessionAsync()
+}
+
+// StartSessionAsync requests a session start (run after any start, stop, pause, or resume already
+// requested) without waiting on it, returning a channel that receives the result of the start
+func (m *StateManager) StartSessionAsync() <-chan error {
+	return m.requestStart(m.performSessionStartup)
+}
+
+// ConnectSensor initializes controllers and connects to the BLE sensor without starting video
+// playback, so the sensor (e.g., its battery level) can be checked before riding: StartPlayback
+// then starts the BLE and video services
+func (m *StateManager) ConnectSensor() error {
+	return <-m.ConnectSensorAsync()
+}
+
+// ConnectSensorAsync requests a sensor connection (see ConnectSensor) without waiting on it,
+// returning a channel that receives the result of the connection
+func (m *StateManager) ConnectSensorAsync() <-chan error {
+	return m.requestStart(m.performSensorConnect)
+}
+
+// requestStart queues a session startup sequence, returning a channel that receives its result
+// once the startup completes, fails, or is canceled
+func (m *StateManager) requestStart(perform func(context.Context, *services.ShutdownManager) error) <-chan error {
+
+	result := make(chan error, 1)
+	m.startRequests.Add(1)
+
+	m.commands.submit(func() error {
+
+		start, err := m.beginStartup(perform)
+		m.startRequests.Add(-1)
+
+		if err != nil || start == nil {
+			result <- err
+
+			return err
+		}
+
+		// Wait on the startup outside of the command queue, so that a stop can cancel it
+		go func() {
+			result <- start.wait()
+		}()
+
+		return nil
+	})
+
+	return result
+}
+
+// beginStartup starts a session startup sequence (run by the command queue), returning the
+// pending startup: a startup already pending is shared rather than started again, and nil is
+// returned (with no error) if the session is already running
+func (m *StateManager) beginStartup(perform func(context.Context, *services.ShutdownManager) error) (*pendingStart, error) {
+
+	m.mu.RLock()
+	start, running := m.starting, m.state.isRunning()
+	m.mu.RUnlock()
+
+	if start != nil {
+		logger.Debug(logger.BackgroundCtx, logger.APP, "session startup already pending: waiting on it")
+
+		return start, nil
+	}
+
+	if running {
+		logger.Debug(logger.BackgroundCtx, logger.APP, "session already running: nothing to start")
+
+		return nil, nil //nolint:nilnil // nothing to start
+	}
 
 	// Confirm start state, otherwise... why are we here?
-	if err := m.prepareStart(); err != nil {
-		return err
+	start, err := m.prepareStart()
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Debug(logger.BackgroundCtx, logger.APP, "session startup sequence starting...")
 
-	shutdownMgr := services.NewShutdownManager(30 * time.Second)
+	shutdownMgr := services.NewShutdownManager(m.shutdownTimeout())
+	shutdownMgr.SetInterruptHandler(func() {
+		m.requestInterrupt(shutdownMgr)
+	})
 	shutdownMgr.Start()
 	m.storeShutdownMgr(shutdownMgr)
 
 	setupDone := make(chan error, 1)
 
-	// Wrap connection phase in a managed WaitGroup to ensure clean shutdown
-	shutdownMgr.Run(func(ctx context.Context) error {
+	// Wrap connection phase in a managed WaitGroup to ensure clean shutdown (started before any
+	// stop can be run, so that the stop waits on it)
+	shutdownMgr.RunNamed("session startup", func(ctx context.Context) error {
 
-		err := m.performSessionStartup(ctx, shutdownMgr)
+		err := perform(ctx, shutdownMgr)
 		setupDone <- err
 
 		return err
 	})
 
+	go m.awaitStartup(start, shutdownMgr, setupDone)
+
+	return start, nil
+}
+
+// awaitStartup waits until a session startup sequence completes, fails, or is canceled, and then
+// completes the pending startup
+func (m *StateManager) awaitStartup(start *pendingStart, shutdownMgr *services.ShutdownManager, setupDone <-chan error) {
+
+	var err error
+
 	// Wait for connection success, internal failure, or user cancellation
 	select {
+	case err = <-setupDone:
+	case <-(*shutdownMgr.Context()).Done():
+		err = context.Canceled
+	}
 
-	case err := <-setupDone:
+	if err == nil {
+		logger.Debug(logger.BackgroundCtx, logger.APP, "session startup sequence completed")
+	} else {
 
-		if err != nil {
-			m.cleanupStartFailure(shutdownMgr)
+		// A startup canceled by a stop fails however far it got (e.g., with its config released)
+		m.mu.RLock()
+		stopped := m.starting != start
+		m.mu.RUnlock()
 
-			return err
+		if stopped {
+			err = context.Canceled
 		}
 
-		logger.Debug(logger.BackgroundCtx, logger.APP, "session startup sequence completed")
+		// Clean up through the command queue, so that the cleanup can't interleave with a stop (or
+		// a later start) requested at the same time
+		_ = m.commands.run(func() error {
+			m.cleanupStartFailure(shutdownMgr)
+
+			return nil
+		})
+
+		err = m.takeStartError(err)
+	}
+
+	m.finishStart(start, err)
+
+}
+
+// finishStart completes a pending startup with its result
+func (m *StateManager) finishStart(start *pendingStart, err error) {
+
+	m.mu.Lock()
+
+	if m.starting == start {
+		m.starting = nil
+	}
+
+	m.mu.Unlock()
+
+	start.err = err
+	close(start.done)
+
+}
+
+// requestInterrupt queues the shutdown of the session on a shutdown signal (e.g., CTRL+C), so that
+// the signal can't race a start or stop requested at the same time, returning a channel that
+// receives nil once the shutdown has run
+func (m *StateManager) requestInterrupt(shutdownMgr *services.ShutdownManager) <-chan error {
+	return m.commands.submit(func() error {
+		m.interrupt(shutdownMgr)
 
 		return nil
+	})
+}
 
-	case <-(*shutdownMgr.Context()).Done():
-		m.cleanupStartFailure(shutdownMgr)
+// interrupt shuts down the session on a shutdown signal (run by the command queue). The
+// controllers of the session are left for ReleaseSession (once Wait returns), and a session
+// already stopped (or one whose shutdown signals are handled by the application) is left alone
+func (m *StateManager) interrupt(shutdownMgr *services.ShutdownManager) {
+
+	m.mu.RLock()
+	current, ignore := m.shutdownMgr == shutdownMgr, m.ignoreInterrupts
+	m.mu.RUnlock()
 
-		return context.Canceled
+	if !current || ignore {
+		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("shutdown signal ignored by ShutdownManager (id:%04d)", shutdownMgr.InstanceID))
+
+		return
 	}
 
+	shutdownMgr.Interrupt()
+
+}
+
+// shutdownTimeout returns the time allowed for the services of the session to stop
+func (m *StateManager) shutdownTimeout() time.Duration {
+
+	cfg := m.ActiveConfig()
+	if cfg == nil {
+		return services.DefaultShutdownTimeout
+	}
+
+	return cfg.App.ShutdownTimeout()
 }
 
 // performSessionStartup handles the initialization and connection logic for a session
 func (m *StateManager) performSessionStartup(ctx context.Context, shutdownMgr *services.ShutdownManager) error {
 
+	controllers, err := m.setupControllers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if controllers.videoFirst {
+		return m.performVideoFirstStartup(ctx, controllers, shutdownMgr)
+	}
+
+	if err := m.connectSpeedSource(ctx, controllers); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+
+	// The session may have been stopped while connecting
+	if err := m.transitionLocked(StateRunning); err != nil {
+		m.mu.Unlock()
+		controllers.releaseSensor()
+
+		return err
+	}
+
+	m.controllers = controllers
+	m.controllers.servicesStarted = true
+	m.mu.Unlock()
+
+	logger.Debug(ctx, logger.APP, "starting services...")
+	m.startServices(ctx, controllers, shutdownMgr)
+	logger.Debug(ctx, logger.APP, "services started")
+	m.recordEvent("Video playback started")
+
+	return nil
+}
+
+// setupControllers initializes the session controllers
+func (m *StateManager) setupControllers(ctx context.Context) (*controllers, error) {
+
 	logger.Debug(ctx, logger.APP, "initializing controllers...")
 
 	controllers, err := m.initializeControllers(ctx)
 	if err != nil {
 		logger.Error(ctx, logger.APP, fmt.Sprintf("controllers init failed: %v", err))
 
-		return fmt.Errorf(errFormat, errInitializeControllers, err)
+		return nil, fmt.Errorf(errFormat, errInitializeControllers, err)
 	}
 
 	// Check if user clicked 'Stop' during init
 	if err := ctx.Err(); err != nil {
-		return fmt.Errorf(errFormat, errInitializeControllers, err)
+		return nil, fmt.Errorf(errFormat, errInitializeControllers, err)
 	}
 
 	logger.Debug(ctx, logger.APP, "controllers initialized OK")
+
+	return controllers, nil
+}
+
+// performSensorConnect handles the initialization and connection logic for a session whose video
+// playback is started later (using StartPlayback)
+func (m *StateManager) performSensorConnect(ctx context.Context, _ *services.ShutdownManager) error {
+
+	controllers, err := m.setupControllers(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Video playback waits on StartPlayback, even for a video-first session
+	controllers.videoFirst = false
+
+	if err := m.connectSpeedSource(ctx, controllers); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+
+	// The session may have been stopped while connecting
+	if !m.state.isActive() {
+		m.mu.Unlock()
+		controllers.releaseSensor()
+
+		return fmt.Errorf(errFormatRev, errInvalidState, m.state)
+	}
+
+	m.controllers = controllers
+	m.mu.Unlock()
+
+	m.recordEvent("BLE sensor ready: start the session to begin video playback")
+
+	return nil
+}
+
+// StartPlayback starts the BLE and video services of a session whose sensor was connected using
+// ConnectSensor (starting playback that's already started does nothing)
+func (m *StateManager) StartPlayback() error {
+	return m.commands.run(m.startPlayback)
+}
+
+// startPlayback starts the BLE and video services of a session whose sensor was connected (run by
+// the command queue)
+func (m *StateManager) startPlayback() error {
+
+	m.mu.Lock()
+
+	ctrl, shutdownMgr := m.controllers, m.shutdownMgr
+
+	if m.state.isRunning() && ctrl != nil && ctrl.servicesStarted {
+		m.mu.Unlock()
+
+		return nil
+	}
+
+	if m.state != StateConnected || ctrl == nil || ctrl.servicesStarted || shutdownMgr == nil {
+		m.mu.Unlock()
+
+		return errSensorNotConnected
+	}
+
+	if err := m.transitionLocked(StateRunning); err != nil {
+		m.mu.Unlock()
+
+		return err
+	}
+
+	ctrl.servicesStarted = true
+	m.mu.Unlock()
+
+	ctx := *shutdownMgr.Context()
+
+	logger.Debug(ctx, logger.APP, "starting services...")
+	m.startServices(ctx, ctrl, shutdownMgr)
+	logger.Debug(ctx, logger.APP, "services started")
+	m.recordEvent("Video playback started")
+
+	return nil
+}
+
+// DisconnectSensor disconnects the BLE sensor connected using ConnectSensor (before video
+// playback starts), returning the session to the Loaded state
+func (m *StateManager) DisconnectSensor() error {
+	return m.commands.run(m.disconnectSensor)
+}
+
+// disconnectSensor disconnects the BLE sensor connected using ConnectSensor (run by the command
+// queue)
+func (m *StateManager) disconnectSensor() error {
+
+	m.mu.RLock()
+
+	ready := m.sensorReadyLocked()
+
+	var device ble.Device
+	if ready {
+		device = m.controllers.bleDevice
+	}
+
+	m.mu.RUnlock()
+
+	if !ready {
+		return errSensorNotConnected
+	}
+
+	if err := m.stop(0); err != nil {
+		return err
+	}
+
+	if device != nil {
+
+		if err := device.Disconnect(); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("failed to disconnect BLE peripheral: %v", err))
+		}
+
+	}
+
+	m.recordEvent("BLE sensor disconnected")
+
+	return nil
+}
+
+// IsSensorReady returns true if the session's sensor was connected using ConnectSensor and is
+// waiting on StartPlayback
+func (m *StateManager) IsSensorReady() bool {
+
+	defer m.readLock()()
+
+	return m.sensorReadyLocked()
+}
+
+// sensorReadyLocked returns true if the session's sensor is connected and waiting on
+// StartPlayback (the caller must hold a lock)
+func (m *StateManager) sensorReadyLocked() bool {
+	return m.state == StateConnected && m.controllers != nil && !m.controllers.servicesStarted
+}
+
+// performVideoFirstStartup starts video playback (paused until speed data arrives) before
+// connecting to the BLE sensor, so the first frame and OSD are shown while the sensor connects
+func (m *StateManager) performVideoFirstStartup(ctx context.Context, controllers *controllers, shutdownMgr *services.ShutdownManager) error {
+
+	m.mu.Lock()
+	m.controllers = controllers
+	m.controllers.servicesStarted = true
+	m.startErr = nil
+	m.mu.Unlock()
+
+	logger.Debug(ctx, logger.APP, "starting video service ahead of BLE connection...")
+	m.startVideoServices(ctx, controllers, shutdownMgr, func(err error) {
+
+		// A failed video service cancels the startup, so retain its error for StartSession
+		m.mu.Lock()
+		m.startErr = err
+		m.mu.Unlock()
+
+	})
+	m.recordEvent("Video ready: waiting for BLE sensor...")
+
+	if err := m.connectSpeedSource(ctx, controllers); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+
+	// The session may have been stopped while connecting
+	if m.controllers != controllers || !m.state.isActive() {
+		m.mu.Unlock()
+		controllers.releaseSensor()
+
+		return fmt.Errorf(errFormatRev, errInvalidState, m.state)
+	}
+
+	m.mu.Unlock()
+
+	logger.Debug(ctx, logger.APP, "starting BLE service...")
+	m.startSpeedService(ctx, controllers, shutdownMgr)
+	m.recordEvent("Waiting for speed data...")
+
+	return nil
+}
+
+// takeStartError returns (and clears) the error of a service that failed while the session was
+// starting, falling back to the given startup error
+func (m *StateManager) takeStartError(err error) error {
+
+	defer m.writeLock()()
+
+	if m.startErr != nil {
+		err, m.startErr = m.startErr, nil
+	}
+
+	return err
+}
+
+// speedDataArrived moves a video-first session to Running once its BLE sensor sends speed data
+func (m *StateManager) speedDataArrived() {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != StateConnected {
+		return
+	}
+
+	if err := m.transitionLocked(StateRunning); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, err.Error())
+
+		return
+	}
+
+	m.recordEvent("Speed data received: session running")
+
+}
+
+// connectSpeedSource connects to the BLE sensor, or (when using another speed source, such as a
+// recorded session replay) simply marks the speed source as connected
+func (m *StateManager) connectSpeedSource(ctx context.Context, controllers *controllers) error {
+
+	if controllers.source != nil {
+		logger.Info(ctx, logger.APP, fmt.Sprintf("using %s speed source in place of the BLE sensor", controllers.source.Name()))
+		m.recordEvent(fmt.Sprintf("Using %s speed source", controllers.source.Name()))
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		return m.transitionLocked(StateConnected)
+	}
+
+	// Reserve the BLE sensor so that another concurrently running session can't connect to it
+	if err := controllers.bleController.ClaimSensor(); err != nil {
+		return apperr.Wrap(apperr.CodeSensorConnect, fmt.Errorf(errFormat, errBLEConnectionFailed, err))
+	}
+
 	logger.Debug(ctx, logger.APP, "establishing connection to BLE peripheral...")
+	m.recordEvent("Connecting to BLE sensor...")
 
 	// Connect to the BLE peripheral
 	device, err := m.connectBLE(ctx, controllers)
 	if err != nil {
+		controllers.releaseSensor()
 		logger.Error(ctx, logger.APP, fmt.Sprintf("BLE connect failed: %v", err))
 
-		return fmt.Errorf(errFormat, errBLEConnectionFailed, err)
+		return apperr.Wrap(apperr.CodeSensorConnect, fmt.Errorf(errFormat, errBLEConnectionFailed, err))
 	}
 
+	m.mu.Lock()
 	controllers.bleDevice = device
+	profile, ok := m.sensorProfileLocked(controllers)
+	m.mu.Unlock()
+
+	if ok {
+		go recordSensorProfile(profile)
+	}
 
 	logger.Debug(ctx, logger.APP, "BLE peripheral now connected")
+	m.recordEvent("BLE sensor connected")
 
-	m.mu.Lock()
-	m.controllers = controllers
-	m.state = StateRunning
-	m.PendingStart = false
-	m.mu.Unlock()
+	return nil
+}
 
-	logger.Debug(ctx, logger.APP, "starting services...")
-	m.startServices(ctx, controllers, shutdownMgr)
-	logger.Debug(ctx, logger.APP, "services started")
+// releaseSensor releases the claimed BLE sensor (if any)
+func (c *controllers) releaseSensor() {
+
+	if c.bleController != nil {
+		c.bleController.ReleaseSensor()
+	}
 
-	return nil
 }
 
-// StopSession stops all services and cleans up controllers
+// speedSource returns the source of the session speeds: the BLE sensor or another speed source
+func (c *controllers) speedSource() speed.SpeedSource {
+
+	if c.source != nil {
+		return c.source
+	}
+
+	return c.bleController
+}
+
+// StopSession stops all services and cleans up controllers, canceling a pending startup (stopping
+// a session that isn't running does nothing)
 func (m *StateManager) StopSession() error {
+	return m.StopSessionWithin(0)
+}
+
+// StopSessionWithin stops the session as StopSession does, allowing its services the given timeout
+// to stop in place of the session's shutdown timeout (e.g., a much shorter timeout when exiting
+// right away), where a timeout that isn't positive uses the session's shutdown timeout
+func (m *StateManager) StopSessionWithin(timeout time.Duration) error {
+	return m.commands.run(func() error {
+		return m.stop(timeout)
+	})
+}
+
+// stop stops all services and cleans up controllers, allowing services the given timeout to stop
+// (run by the command queue)
+func (m *StateManager) stop(timeout time.Duration) error {
 
 	m.mu.Lock()
 
 	// Capture the manager instance we are about to stop
 	targetMgr := m.shutdownMgr
-	wasPending := m.PendingStart
+	wasPending := m.starting != nil
+
+	// If there's nothing to stop, return
+	if targetMgr == nil && !wasPending {
+		m.mu.Unlock()
+		logger.Debug(logger.BackgroundCtx, logger.APP, "stop requested, but no active session to stop")
+
+		return nil
+	}
 
 	// Log the release of specific controller IDs before we destroy the manager object
 	m.logControllersRelease(targetMgr)
 
 	// Reset state
-	m.state = StateLoaded
-	m.PendingStart = false
-
-	// Null the StateManager fields only if they still point to the manager we are stopping
-	if m.shutdownMgr == targetMgr {
-		m.controllers = nil
-		m.shutdownMgr = nil
-		m.activeConfig = nil
+	if err := m.transitionLocked(StateLoaded); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, err.Error())
 	}
 
+	m.starting = nil
+	m.releaseControllersLocked()
+	m.shutdownMgr = nil
 	m.mu.Unlock()
 
-	// If there's nothing to stop, return
-	if targetMgr == nil && !wasPending {
-		return errNoActiveSession
-	}
-
 	// Determine the log context (use the target manager's context if available)
 	ctx := logger.BackgroundCtx
 
@@ -170,7 +670,7 @@ func (m *StateManager) StopSession() error {
 
 	// Finally, we can now stop the session object
 	if targetMgr != nil {
-		targetMgr.Shutdown()
+		targetMgr.ShutdownWithin(timeout)
 	}
 
 	if wasPending {
@@ -179,6 +679,8 @@ func (m *StateManager) StopSession() error {
 		logger.Debug(ctx, logger.APP, "active session stopped")
 	}
 
+	m.recordEvent("Session stopped")
+
 	return nil
 }
 
@@ -198,55 +700,109 @@ func (m *StateManager) logControllersRelease(shutdownMgr *services.ShutdownManag
 		logger.Debug(ctx, logger.SPEED, fmt.Sprintf("releasing speed controller object (id:%04d)", m.controllers.speedController.InstanceID))
 	}
 	if m.controllers.videoPlayer != nil {
-		logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("releasing video controller object (id:%04d)", m.controllers.videoPlayer.InstanceID))
+		logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("releasing video controller object (id:%04d)", m.controllers.videoPlayer.ID()))
 	}
 
 }
 
+// snapshotControllers returns the session controllers and the running session config (either may
+// be nil), so that controller calls (e.g., media player property queries) are made without
+// holding the manager lock. The controllers aren't changed once created, and calls into stopped
+// controllers fail safely
+func (m *StateManager) snapshotControllers() (*controllers, *config.Config) {
+
+	defer m.readLock()()
+
+	return m.controllers, m.currentConfigLocked()
+}
+
+// videoPlayer returns the video player of the running session (nil if no session is running)
+func (m *StateManager) videoPlayer() VideoPlayer {
+
+	ctrl, _ := m.snapshotControllers()
+	if ctrl == nil {
+		return nil
+	}
+
+	return ctrl.videoPlayer
+}
+
 // BatteryLevel returns the current battery level from the BLE controller
 func (m *StateManager) BatteryLevel() byte {
 
-	defer m.readLock()()
+	ctrl, _ := m.snapshotControllers()
 
-	if m.controllers != nil && m.controllers.bleController != nil {
-		return m.controllers.bleController.BatteryLevelLast()
+	if ctrl != nil && ctrl.bleController != nil {
+		return ctrl.bleController.BatteryLevelLast()
 	}
 
 	return 0 // Unknown (0%)
 }
 
+// SensorRSSI returns the signal strength (dBm) of the BLE sensor when it was found
+func (m *StateManager) SensorRSSI() (int16, bool) {
+
+	ctrl, _ := m.snapshotControllers()
+
+	if ctrl != nil && ctrl.bleController != nil && ctrl.bleDevice != nil {
+		return ctrl.bleController.RSSILast(), true
+	}
+
+	return 0, false
+}
+
 // CurrentSpeed returns the current smoothed speed from the speed controller
 func (m *StateManager) CurrentSpeed() (float64, string) {
 
-	defer m.readLock()()
+	// Use the running session config (if any) to ensure we return the units of the active session
+	ctrl, cfg := m.snapshotControllers()
 
-	// Use ActiveConfig here to ensure we return the units of the active running session
-	cfg := m.activeConfig
-	if cfg == nil {
-		cfg = m.editConfig
+	// Check for nil controllers (session stopped or not started)
+	if ctrl == nil || ctrl.speedController == nil || cfg == nil {
+		return 0.0, ""
 	}
 
+	return ctrl.speedController.SmoothedSpeed(), cfg.Speed.SpeedUnits
+}
+
+// SpeedStats returns the average (while moving) and maximum smoothed speeds of the running
+// session from the speed controller
+func (m *StateManager) SpeedStats() (average, maximum float64, units string) {
+
+	ctrl, cfg := m.snapshotControllers()
+
 	// Check for nil controllers (session stopped or not started)
-	if m.controllers == nil || m.controllers.speedController == nil || cfg == nil {
-		return 0.0, ""
+	if ctrl == nil || ctrl.speedController == nil || cfg == nil {
+		return 0.0, 0.0, ""
+	}
+
+	return ctrl.speedController.AverageSpeed(), ctrl.speedController.MaxSpeed(), cfg.Speed.SpeedUnits
+}
+
+// StartCountdown returns the time remaining in the start countdown of the running session (zero
+// if there's no countdown running)
+func (m *StateManager) StartCountdown() time.Duration {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return 0
 	}
 
-	return m.controllers.speedController.SmoothedSpeed(), cfg.Speed.SpeedUnits
+	return player.CountdownRemaining()
 }
 
 // VideoTimeRemaining returns the formatted time remaining string (HH:MM:SS)
 func (m *StateManager) VideoTimeRemaining() string {
 
-	defer m.readLock()()
-
 	noTime := "--:--:--"
 
 	// Check for nil controllers (session stopped or not started)
-	if m.controllers == nil || m.controllers.videoPlayer == nil {
+	player := m.videoPlayer()
+	if player == nil {
 		return noTime
 	}
 
-	timeStr, err := m.controllers.videoPlayer.TimeRemaining()
+	timeStr, err := player.TimeRemaining()
 	if err != nil {
 		return noTime
 	}
@@ -257,15 +813,14 @@ func (m *StateManager) VideoTimeRemaining() string {
 // VideoPlaybackPosition returns the formatted current playback position (HH:MM:SS)
 func (m *StateManager) VideoPlaybackPosition() string {
 
-	defer m.readLock()()
-
 	noTime := "00:00:00"
 
-	if m.controllers == nil || m.controllers.videoPlayer == nil {
+	player := m.videoPlayer()
+	if player == nil {
 		return noTime
 	}
 
-	timeStr, err := m.controllers.videoPlayer.PlaybackPosition()
+	timeStr, err := player.PlaybackPosition()
 	if err != nil {
 		return noTime
 	}
@@ -273,23 +828,175 @@ func (m *StateManager) VideoPlaybackPosition() string {
 	return timeStr
 }
 
+// VideoPosition returns the current video playback position (0 if unavailable)
+func (m *StateManager) VideoPosition() time.Duration {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return 0
+	}
+
+	position, err := player.Position()
+	if err != nil {
+		return 0
+	}
+
+	return position
+}
+
+// VideoDuration returns the total duration of the video (0 if unavailable)
+func (m *StateManager) VideoDuration() time.Duration {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return 0
+	}
+
+	duration, err := player.Duration()
+	if err != nil {
+		return 0
+	}
+
+	return duration
+}
+
 // VideoPlaybackRate returns the current video playback multiplier (e.g. 1.0x)
 func (m *StateManager) VideoPlaybackRate() float64 {
 
-	defer m.readLock()()
-
-	if m.controllers == nil || m.controllers.videoPlayer == nil {
+	player := m.videoPlayer()
+	if player == nil {
 		return 0.0
 	}
 
-	return m.controllers.videoPlayer.PlaybackSpeed()
+	return player.PlaybackSpeed()
+}
+
+// VideoVolume returns the current video audio volume (0-100) and mute state
+func (m *StateManager) VideoVolume() (int, bool) {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return 0, false
+	}
+
+	return player.Volume()
+}
+
+// SetVideoVolume sets the audio volume (0-100) of the running video playback
+func (m *StateManager) SetVideoVolume(volume int) error {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return errNoActivePlayback
+	}
+
+	return player.SetVolume(volume)
+}
+
+// SetVideoMute sets the audio mute state of the running video playback
+func (m *StateManager) SetVideoMute(muted bool) error {
+
+	player := m.videoPlayer()
+	if player == nil {
+		return errNoActivePlayback
+	}
+
+	return player.SetMute(muted)
+}
+
+// SeekVideo seeks video playback of the running session by an offset from the current playback
+// position, or to an absolute playback position
+func (m *StateManager) SeekVideo(position time.Duration, absolute bool) error {
+
+	ctrl, _ := m.snapshotControllers()
+	if ctrl == nil || ctrl.videoPlayer == nil {
+		return errNoActivePlayback
+	}
+
+	if !m.SessionState().isRunning() {
+		return errSessionNotRunning
+	}
+
+	if err := ctrl.videoPlayer.Seek(position, absolute); err != nil {
+		return err
+	}
+
+	if absolute {
+		m.recordEvent(fmt.Sprintf("Video skipped to %s", position))
+	} else {
+		m.recordEvent(fmt.Sprintf("Video skipped %+ds", int(position.Seconds())))
+	}
+
+	return nil
 }
 
-// initializeControllers creates the speed, video, and BLE controllers
+// PauseSession pauses video playback of the running session (pausing a paused session does
+// nothing)
+func (m *StateManager) PauseSession() error {
+	return m.commands.run(func() error {
+		return m.setPlaybackPaused(true)
+	})
+}
+
+// ResumeSession resumes video playback of a paused session (resuming a running session does
+// nothing)
+func (m *StateManager) ResumeSession() error {
+	return m.commands.run(func() error {
+		return m.setPlaybackPaused(false)
+	})
+}
+
+// setPlaybackPaused pauses or resumes video playback, updating the session state accordingly (run
+// by the command queue). The video player is called outside the manager lock, so that a slow
+// media player never blocks session state (and metric) reads
+func (m *StateManager) setPlaybackPaused(paused bool) error {
+
+	ctrl, _ := m.snapshotControllers()
+	if ctrl == nil || ctrl.videoPlayer == nil {
+		return errNoActivePlayback
+	}
+
+	next, event := StateRunning, "Session resumed"
+	if paused {
+		next, event = StatePaused, "Session paused"
+	}
+
+	switch state := m.SessionState(); {
+	case !state.isRunning():
+		return errSessionNotRunning
+
+	case state == next:
+		return nil
+	}
+
+	if err := ctrl.videoPlayer.SetPaused(paused); err != nil {
+		return err
+	}
+
+	defer m.writeLock()()
+
+	// The session may have stopped (or failed) while the video player was called
+	if m.controllers != ctrl {
+		return errNoActivePlayback
+	}
+
+	if err := m.transitionLocked(next); err != nil {
+		return err
+	}
+
+	m.recordEvent(event)
+
+	return nil
+}
+
+// initializeControllers creates the speed, video, and BLE controllers (or another speed source)
+// using the session controller factory
 func (m *StateManager) initializeControllers(ctx context.Context) (*controllers, error) {
 
 	m.mu.RLock()
 	cfg := m.activeConfig
+	factory := m.factory
+	intervalHandler := m.intervalHandler
 	m.mu.RUnlock()
 
 	logger.Debug(ctx, logger.APP, "creating and initializing controllers...")
@@ -299,93 +1006,234 @@ func (m *StateManager) initializeControllers(ctx context.Context) (*controllers,
 	}
 
 	logger.Debug(ctx, logger.APP, "creating new speed controller...")
-	speedController := speed.NewSpeedController(ctx, cfg.Speed.SmoothingWindow)
+	speedController := factory.SpeedController(ctx, cfg)
 	logger.Debug(ctx, logger.APP, "creating new video controller...")
 
-	videoPlayer, err := video.NewPlaybackController(ctx, cfg.Video, cfg.Speed)
+	videoPlayer, err := factory.VideoPlayer(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create video controller: %w", err)
+		return nil, apperr.Wrap(apperr.CodePlayerInit, fmt.Errorf("failed to create video controller: %w", err))
 	}
 
-	logger.Debug(ctx, logger.APP, "creating new BLE controller...")
-	bleController, err := ble.NewBLEController(ctx, cfg.BLE, cfg.Speed)
+	videoPlayer.SetEventHandler(m.recordEvent)
+
+	ctrl := &controllers{
+		speedController: speedController,
+		videoPlayer:     videoPlayer,
+		goal:            speed.NewGoalTracker(cfg.Goal, cfg.Speed),
+		intervals:       speed.NewIntervalTimer(cfg.Intervals),
+		laps:            speed.NewLapTracker(cfg.Laps, cfg.Speed.SpeedUnits),
+		race:            speed.NewRaceScorer(cfg.Race),
+	}
+
+	if cfg.BLE.EnvSensorBDAddr != "" {
+		ctrl.env = &envTracker{}
+	}
+
+	// An auto-resumed session carries on the ride saved with its playback position
+	resumeRide(ctx, ctrl, cfg.Video)
+
+	videoPlayer.SetGoalTracker(ctrl.goal)
+	videoPlayer.SetIntervalTimer(ctrl.intervals, intervalHandler)
+	videoPlayer.SetLapTracker(ctrl.laps, func(_ speed.Lap) { m.fireHook(config.HookLap) })
+	videoPlayer.SetRaceScorer(ctrl.race)
+
+	// An outdoor ride of the same route is compared against the session ("vs outdoor you")
+	if cfg.Race.OutdoorGPXFile != "" {
+		outdoor, err := speed.LoadOutdoorRide(cfg.Race.OutdoorGPXFile)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.CodeConfigInvalid, err)
+		}
+
+		logger.Debug(ctx, logger.APP, fmt.Sprintf("comparing against an outdoor ride of %.1f km (%s riding time)", outdoor.DistanceKM(), outdoor.RideTime()))
+		videoPlayer.SetOutdoorRide(outdoor)
+	}
+
+	// Another speed source (e.g., a recorded session) replaces the BLE sensor
+	ctrl.source, err = factory.SpeedSource(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create BLE controller: %w", err)
+		return nil, apperr.Wrap(apperr.CodeConfigInvalid, fmt.Errorf("failed to create speed source: %w", err))
+	}
+
+	if ctrl.source == nil {
+		logger.Debug(ctx, logger.APP, "creating new BLE controller...")
+
+		ctrl.bleController, err = factory.BLEController(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BLE controller: %w", err)
+		}
+
+		ctrl.videoFirst = cfg.Video.VideoFirstStart
 	}
 
 	logger.Debug(ctx, logger.APP, "all controllers created and initialized")
 
-	return &controllers{
-		speedController: speedController,
-		videoPlayer:     videoPlayer,
-		bleController:   bleController,
-	}, nil
+	return ctrl, nil
 }
 
 // connectBLE handles BLE scanning, connection, and service discovery
-func (m *StateManager) connectBLE(ctx context.Context, ctrl *controllers) (bluetooth.Device, error) {
+//
+//nolint:ireturn // Returns the connected peripheral provided by the BLE adapter
+func (m *StateManager) connectBLE(ctx context.Context, ctrl *controllers) (ble.Device, error) {
 
 	// Scan for BLE peripheral
+	m.scanProgress.Store(nil)
+	m.wakeHint.Store(false)
+	ctrl.bleController.SetScanProgress(m.recordScanProgress)
+	ctrl.bleController.SetWakeHint(m.recordWakeHint)
+
 	scanResult, err := ctrl.bleController.ScanForBLEPeripheral(ctx)
+	m.scanProgress.Store(nil)
+	m.wakeHint.Store(false)
+
 	if err != nil {
-		return bluetooth.Device{}, fmt.Errorf("BLE scan failed: %w", err)
+		return nil, fmt.Errorf("BLE scan failed: %w", err)
 	}
 
 	m.mu.Lock()
-	m.state = StateConnecting
+	err = m.transitionLocked(StateConnecting)
 	m.mu.Unlock()
 
+	if err != nil {
+		return nil, err
+	}
+
 	// Connect to peripheral
 	device, err := ctrl.bleController.ConnectToBLEPeripheral(ctx, scanResult)
 	if err != nil {
-		return bluetooth.Device{}, fmt.Errorf("BLE connection failed: %w", err)
+		return nil, fmt.Errorf("BLE connection failed: %w", err)
 	}
 
 	m.mu.Lock()
-	m.state = StateConnected
+	err = m.transitionLocked(StateConnected)
 	m.mu.Unlock()
 
+	if err != nil {
+		return nil, err
+	}
+
 	// Get battery service
-	batteryServices, err := ctrl.bleController.BatteryService(ctx, &device)
+	batteryServices, err := ctrl.bleController.BatteryService(ctx, device)
 	if err != nil {
-		return bluetooth.Device{}, ErrFailedToGetBatteryService
+		return nil, ErrFailedToGetBatteryService
 	}
 
 	// Get battery level
 	if err = ctrl.bleController.BatteryLevel(ctx, batteryServices); err != nil {
-		return bluetooth.Device{}, ErrFailedToGetBatteryLevel
+		return nil, ErrFailedToGetBatteryLevel
 	}
 
 	// Get CSC services
-	cscServices, err := ctrl.bleController.CSCServices(ctx, &device)
+	cscServices, err := ctrl.bleController.CSCServices(ctx, device)
 	if err != nil {
-		return bluetooth.Device{}, fmt.Errorf("failed to get CSC services: %w", err)
+		return nil, fmt.Errorf("failed to get CSC services: %w", err)
 	}
 
 	// Get CSC characteristics
 	if err := ctrl.bleController.CSCCharacteristics(ctx, cscServices); err != nil {
-		return bluetooth.Device{}, fmt.Errorf("failed to get CSC characteristics: %w", err)
+		return nil, fmt.Errorf("failed to get CSC characteristics: %w", err)
 	}
 
 	return device, nil
 }
 
-// startServices launches BLE and video services in background goroutines
+// startServices launches BLE, video and watchdog services in background goroutines
 func (m *StateManager) startServices(ctx context.Context, ctrl *controllers, shutdownMgr *services.ShutdownManager) {
 
-	m.runService(ctx, shutdownMgr, "BLE", func(ctx context.Context) error {
-		return ctrl.bleController.BLEUpdates(ctx, ctrl.speedController)
+	m.startSpeedService(ctx, ctrl, shutdownMgr)
+	m.startVideoServices(ctx, ctrl, shutdownMgr, nil)
+
+	logger.Debug(ctx, logger.APP, "BLE and video services started")
+
+}
+
+// startSpeedService launches the BLE service, where the speed controller consumes speeds from its
+// registered source (along with the environmental sensor service, if any)
+func (m *StateManager) startSpeedService(ctx context.Context, ctrl *controllers, shutdownMgr *services.ShutdownManager) {
+
+	bleOpts := services.ServiceOptions{
+		Timeout:     bleServiceTimeout,
+		Restart:     services.RestartOnPanic,
+		MaxRestarts: bleServiceMaxRestarts,
+	}
+
+	ctrl.speedController.RegisterSource(ctrl.speedSource())
+
+	m.runService(ctx, shutdownMgr, bleServiceName, bleOpts, ctrl.speedController.Run)
+	m.startEnvService(ctx, ctrl, shutdownMgr)
+
+}
+
+// startVideoServices launches the video, watchdog and time limit services, calling onVideoError (if
+// set) when video playback fails
+func (m *StateManager) startVideoServices(ctx context.Context, ctrl *controllers, shutdownMgr *services.ShutdownManager, onVideoError func(error)) {
+
+	// Heartbeats are wired up before the monitored services start
+	watchdog := m.newWatchdog(ctrl, shutdownMgr)
+
+	// Video playback is stopped before the BLE service (even when started ahead of it)
+	videoOpts := services.ServiceOptions{DependsOn: []string{bleServiceName}}
+	m.runService(ctx, shutdownMgr, videoServiceName, videoOpts, func(ctx context.Context) error {
+
+		err := ctrl.videoPlayer.StartPlayback(ctx, ctrl.speedController)
+		if err != nil && !errors.Is(err, video.ErrVideoComplete) {
+			err = apperr.Wrap(apperr.CodePlayerFailed, err)
+		}
+
+		if err != nil && onVideoError != nil {
+			onVideoError(err)
+		}
+
+		return err
 	})
 
-	m.runService(ctx, shutdownMgr, "video", func(ctx context.Context) error {
-		return ctrl.videoPlayer.StartPlayback(ctx, ctrl.speedController)
+	// The watchdog depends on the services it monitors, so it's stopped before them
+	watchdogOpts := services.ServiceOptions{DependsOn: []string{bleServiceName, videoServiceName}, Timeout: watchdogStopTimeout}
+	shutdownMgr.RunService(watchdogServiceName, watchdogOpts, watchdog.Run)
+
+	m.startTimeLimitService(ctx, shutdownMgr)
+
+}
+
+// newWatchdog creates a watchdog that monitors the progress of the BLE and video services
+func (m *StateManager) newWatchdog(ctrl *controllers, shutdownMgr *services.ShutdownManager) *services.Watchdog {
+
+	watchdog := services.NewWatchdog(watchdogInterval)
+
+	videoHeartbeat := watchdog.Monitor(videoServiceName, videoStallTimeout, func(stalled time.Duration) {
+		err := fmt.Errorf("%s service failed: %w (%v)", videoServiceName, errServiceStalled, stalled.Round(time.Second))
+		m.handleServiceError(videoServiceName, err)
+		shutdownMgr.ReportError(err)
 	})
 
-	logger.Debug(ctx, logger.APP, "BLE and video services started")
+	// Another speed source (e.g., a replayed session) has no BLE sensor to monitor
+	if ctrl.bleController != nil {
+		bleHeartbeat := watchdog.Monitor(bleServiceName, bleStallTimeout, func(_ time.Duration) {
+			m.recordEvent("BLE sensor not responding")
+			m.fireHook(config.HookSensorLost)
+		})
 
+		beat := bleHeartbeat.Beat
+
+		// A video-first session starts running once the BLE sensor sends speed data
+		if ctrl.videoFirst {
+			var speedData sync.Once
+
+			beat = func() {
+				bleHeartbeat.Beat()
+				speedData.Do(m.speedDataArrived)
+			}
+		}
+
+		ctrl.bleController.SetHeartbeat(beat)
+	}
+
+	ctrl.videoPlayer.SetHeartbeat(videoHeartbeat.Beat)
+
+	return watchdog
 }
 
-// cleanupStartFailure handles cleaning manager state when session startup fails
+// cleanupStartFailure handles cleaning manager state when session startup fails (run by the
+// command queue)
 func (m *StateManager) cleanupStartFailure(shutdownMgr *services.ShutdownManager) {
 
 	m.mu.Lock()
@@ -397,11 +1245,14 @@ func (m *StateManager) cleanupStartFailure(shutdownMgr *services.ShutdownManager
 
 		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("resetting state for current ShutdownManager (id:%04d)", shutdownMgr.InstanceID))
 
-		m.PendingStart = false
-		m.state = StateLoaded
-		m.controllers = nil
+		m.starting = nil
+
+		if err := m.transitionLocked(StateLoaded); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.APP, err.Error())
+		}
+
+		m.releaseControllersLocked()
 		m.shutdownMgr = nil
-		m.activeConfig = nil
 	}
 	m.mu.Unlock()
 
@@ -422,35 +1273,52 @@ func (m *StateManager) cleanupStartFailure(shutdownMgr *services.ShutdownManager
 }
 
 // runService helper to launch a service with standard error handling and logging
-func (m *StateManager) runService(ctx context.Context, shutdownMgr *services.ShutdownManager, service string, action func(context.Context) error) {
+func (m *StateManager) runService(ctx context.Context, shutdownMgr *services.ShutdownManager, service string, opts services.ServiceOptions, action func(context.Context) error) {
 
 	logger.Debug(ctx, logger.APP, fmt.Sprintf("starting %s service goroutine", service))
 
-	shutdownMgr.Run(func(ctx context.Context) error {
+	// If this service fails (or panics), we reset the state and clean up resources
+	opts.OnError = func(err error) {
+		m.handleServiceError(service, err)
+	}
+
+	shutdownMgr.RunService(service, opts, func(ctx context.Context) error {
 
 		logger.Debug(ctx, logger.APP, service+" service starting")
 
-		err := action(ctx)
+		if err := action(ctx); err != nil {
+			return fmt.Errorf(errFormat, service+" service failed", err)
+		}
 
-		// If this goroutine fails, we reset the state and clean up resources
-		if err != nil && !errors.Is(err, context.Canceled) {
+		return nil
+	})
 
-			m.mu.Lock()
+}
 
-			// Only update if we were previously running
-			if m.state == StateRunning {
-				m.state = StateError
-				m.errorMsg = fmt.Sprintf("%s service failed: %v", service, err)
-			}
+// handleServiceError moves a running session to the Error state and releases its resources
+func (m *StateManager) handleServiceError(service string, err error) {
 
-			// Rest resources state
-			m.controllers = nil
-			m.activeConfig = nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Only update if we were previously running (or, for a video-first session, connected and
+	// waiting for speed data)
+	if m.state.isRunning() || (m.state == StateConnected && m.controllers != nil) {
 
-			m.mu.Unlock()
+		// Service errors are already wrapped by runService, but recovered panics are not
+		if errors.Is(err, services.ErrServicePanic) {
+			err = fmt.Errorf(errFormat, service+" service failed", err)
 		}
 
-		return fmt.Errorf(errFormat, service+" service failed", err)
-	})
+		m.setErrorLocked(err)
+
+		// Video completion (and the time limit) are reported by the services themselves
+		if !errors.Is(err, video.ErrVideoComplete) && !errors.Is(err, ErrSessionTimeLimit) {
+			m.recordEvent(m.errorMsg)
+		}
+	}
+
+	// Reset resources state
+	m.releaseControllersLocked()
 
 }
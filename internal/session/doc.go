@@ -4,6 +4,7 @@
 //   - Loading and validating session configurations
 //   - Initializing and synchronizing controllers (BLE, Video, Speed)
 //   - Managing the application state machine (Running, Stopped, Editing)
+//   - Serializing start, stop, pause, and resume requests (e.g., from the GUI and CTRL+C)
 //   - Coordinating the clean shutdown of all active components
 //
 // The session package acts as the glue that binds the configuration, hardware interfaces,
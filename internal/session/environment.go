@@ -0,0 +1,196 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// Environmental sensor settings
+const (
+	envServiceName     = "environment"
+	envServiceTimeout  = 5 * time.Second
+	envPollInterval    = 30 * time.Second
+	envReconnectPause  = time.Minute
+	envMaxReadFailures = 3 // Consecutive failed reads before reconnecting to the sensor
+)
+
+// newEnvController creates the BLE controller of an environmental sensor (replaced in tests)
+var newEnvController = func(ctx context.Context, bleConfig config.BLEConfig, speedConfig config.SpeedConfig) (*ble.Controller, error) {
+	return ble.NewBLEController(ctx, bleConfig, speedConfig)
+}
+
+// envTracker holds the readings of the environmental sensor of a session
+type envTracker struct {
+	last          ble.EnvReading
+	temperatureC  float64 // Sum of temperature readings
+	humidityPct   float64 // Sum of humidity readings
+	readings      int
+	humidityCount int
+	mu            sync.Mutex
+}
+
+// add adds a reading to the environmental sensor readings
+func (t *envTracker) add(reading ble.EnvReading) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.last = reading
+	t.temperatureC += reading.TemperatureC
+	t.readings++
+
+	if reading.HasHumidity {
+		t.humidityPct += reading.HumidityPct
+		t.humidityCount++
+	}
+
+}
+
+// latest returns the last environmental sensor reading (false if there's no reading)
+func (t *envTracker) latest() (ble.EnvReading, bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.last, t.readings > 0
+}
+
+// averages returns the average temperature and humidity of the environmental sensor readings
+// (zero if there's no reading)
+func (t *envTracker) averages() (temperatureC, humidityPct float64) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.readings > 0 {
+		temperatureC = t.temperatureC / float64(t.readings)
+	}
+
+	if t.humidityCount > 0 {
+		humidityPct = t.humidityPct / float64(t.humidityCount)
+	}
+
+	return temperatureC, humidityPct
+}
+
+// Environment returns the last reading of the environmental sensor of the running session (false
+// if no session is running, or there's no reading)
+func (m *StateManager) Environment() (ble.EnvReading, bool) {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || m.controllers.env == nil {
+		return ble.EnvReading{}, false
+	}
+
+	return m.controllers.env.latest()
+}
+
+// startEnvService launches the environmental sensor service, if the session has an environmental
+// sensor (the sensor is optional, so its failures never stop the session)
+func (m *StateManager) startEnvService(ctx context.Context, ctrl *controllers, shutdownMgr *services.ShutdownManager) {
+
+	m.mu.RLock()
+	cfg := m.activeConfig
+	m.mu.RUnlock()
+
+	if cfg == nil || cfg.BLE.EnvSensorBDAddr == "" || ctrl.env == nil {
+		return
+	}
+
+	bleConfig := cfg.BLE
+	bleConfig.SensorBDAddr = cfg.BLE.EnvSensorBDAddr
+	bleConfig.SampleExportFile, bleConfig.TrafficLogFile = "", ""
+
+	logger.Debug(ctx, logger.APP, fmt.Sprintf("starting %s service goroutine", envServiceName))
+
+	shutdownMgr.RunService(envServiceName, services.ServiceOptions{Timeout: envServiceTimeout}, func(ctx context.Context) error {
+
+		for ctx.Err() == nil {
+
+			if err := m.monitorEnvSensor(ctx, ctrl.env, bleConfig, cfg.Speed); err != nil && ctx.Err() == nil {
+				logger.Warn(ctx, logger.BLE, fmt.Sprintf("environmental sensor unavailable: %v", err))
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(envReconnectPause):
+			}
+
+		}
+
+		return nil
+	})
+
+}
+
+// monitorEnvSensor connects to the environmental sensor and reads its temperature and humidity
+// until the session stops (or the sensor stops responding)
+func (m *StateManager) monitorEnvSensor(ctx context.Context, tracker *envTracker, bleConfig config.BLEConfig, speedConfig config.SpeedConfig) error {
+
+	controller, err := newEnvController(ctx, bleConfig, speedConfig)
+	if err != nil {
+		return err
+	}
+
+	scanResult, err := controller.ScanForBLEPeripheral(ctx)
+	if err != nil {
+		return fmt.Errorf(errFormat, "scan failed", err)
+	}
+
+	device, err := controller.ConnectToBLEPeripheral(ctx, scanResult)
+	if err != nil {
+		return fmt.Errorf(errFormat, "connection failed", err)
+	}
+
+	defer func() {
+
+		if err := device.Disconnect(); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("failed to disconnect environmental sensor: %v", err))
+		}
+
+	}()
+
+	envServices, err := controller.EnvServices(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.EnvCharacteristics(ctx, envServices); err != nil {
+		return err
+	}
+
+	m.recordEvent("Environmental sensor connected")
+
+	ticker := time.NewTicker(envPollInterval)
+	defer ticker.Stop()
+
+	failures := 0
+
+	for {
+
+		reading, err := controller.ReadEnvironment()
+		if err == nil {
+			failures = 0
+			tracker.add(reading)
+			logger.Debug(ctx, logger.BLE, fmt.Sprintf("environmental sensor reading: %.1f°C, %.0f%% humidity", reading.TemperatureC, reading.HumidityPct))
+		} else if failures++; failures >= envMaxReadFailures {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+	}
+
+}
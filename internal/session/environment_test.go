@@ -0,0 +1,37 @@
+package session
+
+import (
+	"math"
+	"testing"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+)
+
+// TestEnvTracker tests that the environmental sensor readings are averaged, skipping readings
+// without humidity when averaging humidity
+func TestEnvTracker(t *testing.T) {
+
+	tracker := &envTracker{}
+
+	if _, ok := tracker.latest(); ok {
+		t.Errorf("latest() reported a reading before any was added")
+	}
+
+	if temperature, humidity := tracker.averages(); temperature != 0 || humidity != 0 {
+		t.Errorf("averages() = %.2f, %.2f, want 0, 0", temperature, humidity)
+	}
+
+	tracker.add(ble.EnvReading{TemperatureC: 20, HumidityPct: 40, HasHumidity: true})
+	tracker.add(ble.EnvReading{TemperatureC: 22})
+	tracker.add(ble.EnvReading{TemperatureC: 24, HumidityPct: 50, HasHumidity: true})
+
+	temperature, humidity := tracker.averages()
+	if math.Abs(temperature-22) > 0.001 || math.Abs(humidity-45) > 0.001 {
+		t.Errorf("averages() = %.2f, %.2f, want 22, 45", temperature, humidity)
+	}
+
+	if reading, ok := tracker.latest(); !ok || reading.TemperatureC != 24 {
+		t.Errorf("latest() = %+v, %t, want the last reading", reading, ok)
+	}
+
+}
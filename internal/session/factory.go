@@ -0,0 +1,108 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+	"github.com/richbl/go-ble-sync-cycle/internal/video"
+)
+
+// VideoPlayer provides the video playback operations used by a session
+type VideoPlayer interface {
+	StartPlayback(ctx context.Context, speedController *speed.Controller) error
+	SetPaused(paused bool) error
+	Seek(position time.Duration, absolute bool) error
+	SetEventHandler(handler func(message string))
+	SetHeartbeat(heartbeat func())
+	SetGoalTracker(tracker *speed.GoalTracker)
+	SetIntervalTimer(timer *speed.IntervalTimer, handler func(state speed.IntervalState))
+	SetLapTracker(tracker *speed.LapTracker, handler func(lap speed.Lap))
+	SetRaceScorer(scorer *speed.RaceScorer)
+	SetOutdoorRide(ride *speed.OutdoorRide)
+	TimeRemaining() (string, error)
+	PlaybackPosition() (string, error)
+	Position() (time.Duration, error)
+	Duration() (time.Duration, error)
+	PlaybackSpeed() float64
+	CountdownRemaining() time.Duration
+	Volume() (int, bool)
+	SetVolume(volume int) error
+	SetMute(muted bool) error
+	ID() int64
+}
+
+// ControllerFactory creates the controllers used by a session, allowing them to be replaced
+// (e.g., by in-memory controllers in tests or the self-test)
+type ControllerFactory struct {
+
+	// SpeedController creates the speed controller
+	SpeedController func(ctx context.Context, cfg *config.Config) *speed.Controller
+
+	// VideoPlayer creates the video player
+	VideoPlayer func(ctx context.Context, cfg *config.Config) (VideoPlayer, error)
+
+	// SpeedSource creates a speed source used in place of the BLE sensor (nil to use the sensor)
+	SpeedSource func(ctx context.Context, cfg *config.Config) (speed.SpeedSource, error)
+
+	// BLEController creates the BLE controller (only called when there's no other speed source)
+	BLEController func(ctx context.Context, cfg *config.Config) (*ble.Controller, error)
+}
+
+// DefaultControllerFactory returns the factory used to create the application controllers: a
+// BLE sensor (or a recorded session replay) driving mpv video playback
+func DefaultControllerFactory() ControllerFactory {
+
+	return ControllerFactory{
+		SpeedController: newSpeedController,
+		VideoPlayer:     newVideoPlayer,
+		SpeedSource:     newReplaySource,
+		BLEController:   newBLEController,
+	}
+}
+
+// newSpeedController creates the speed controller for the session
+func newSpeedController(ctx context.Context, cfg *config.Config) *speed.Controller {
+
+	speedController := speed.NewSpeedController(ctx, cfg.Speed.SmoothingWindow)
+	speedController.SetCoastDecay(cfg.Speed.CoastDecay())
+
+	return speedController
+}
+
+// newVideoPlayer creates the media player used for session video playback
+//
+//nolint:ireturn // Returns the media player implementation of the VideoPlayer interface
+func newVideoPlayer(ctx context.Context, cfg *config.Config) (VideoPlayer, error) {
+
+	videoPlayer, err := video.NewPlaybackController(ctx, cfg.Video, cfg.Speed)
+	if err != nil {
+		return nil, err
+	}
+
+	return videoPlayer, nil
+}
+
+// newReplaySource creates a replay speed source when replaying a recorded session
+//
+//nolint:ireturn // Returns the replay implementation of the SpeedSource interface
+func newReplaySource(_ context.Context, cfg *config.Config) (speed.SpeedSource, error) {
+
+	if cfg.Speed.ReplayFile == "" {
+		return nil, nil //nolint:nilnil // no recorded session to replay
+	}
+
+	source, err := speed.NewReplaySource(cfg.Speed.ReplayFile, cfg.Speed.ReplayRate, cfg.Speed.SpeedUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// newBLEController creates the BLE controller using the system BLE adapter
+func newBLEController(ctx context.Context, cfg *config.Config) (*ble.Controller, error) {
+	return ble.NewBLEController(ctx, cfg.BLE, cfg.Speed)
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Paths of the session history and usage statistics (replaced in tests)
+var (
+	sessionHistoryPath = config.SessionHistoryPath
+	usageStatsPath     = config.UsageStatsPath
+)
+
+// GoalProgress returns the ride totals of the running session and its progress toward the
+// session goal (false if no session is running)
+func (m *StateManager) GoalProgress() (speed.GoalProgress, bool) {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || m.controllers.goal == nil {
+		return speed.GoalProgress{}, false
+	}
+
+	return m.controllers.goal.Progress(), true
+}
+
+// sessionRecordLocked returns the session history record of the ride of the session controllers,
+// if there was a ride to record (the caller must hold at least the read lock)
+func (m *StateManager) sessionRecordLocked(ctrl *controllers) (config.SessionRecord, bool) {
+
+	if ctrl == nil || ctrl.goal == nil || m.activeConfig == nil {
+		return config.SessionRecord{}, false
+	}
+
+	progress := ctrl.goal.Progress()
+	if progress.RideTime <= 0 {
+		return config.SessionRecord{}, false
+	}
+
+	record := config.SessionRecord{
+		Title:       m.activeConfig.App.SessionTitle,
+		Started:     progress.Started,
+		RideSecs:    int(progress.RideTime.Seconds()),
+		DistanceKM:  progress.DistanceKM,
+		Calories:    progress.Calories,
+		GoalType:    progress.Goal.Type,
+		GoalTarget:  progress.Goal.Target,
+		GoalReached: progress.Reached,
+	}
+
+	if m.activeConfig.Video.FilePath != "" {
+		record.Video = filepath.Base(m.activeConfig.Video.FilePath)
+	}
+
+	if ctrl.speedController != nil {
+		converter := m.activeConfig.Speed.Converter()
+		record.AvgSpeedKPH = converter.KilometersPerHour(ctrl.speedController.AverageSpeed())
+		record.MaxSpeedKPH = converter.KilometersPerHour(ctrl.speedController.MaxSpeed())
+	}
+
+	for _, zoneTime := range ctrl.goal.ZoneTimes() {
+		record.ZoneSecs = append(record.ZoneSecs, int(zoneTime.Seconds()))
+	}
+
+	if ctrl.env != nil {
+		record.TemperatureC, record.HumidityPct = ctrl.env.averages()
+	}
+
+	if ctrl.race.Enabled() {
+		record.RaceScore = ctrl.race.Score().Score
+	}
+
+	if ctrl.laps != nil {
+
+		for _, lap := range ctrl.laps.Summary(progress) {
+			record.Laps = append(record.Laps, config.LapRecord{RideSecs: int(lap.RideTime.Seconds()), DistanceKM: lap.DistanceKM, Manual: lap.Manual})
+		}
+	}
+
+	return record, true
+}
+
+// TakeLastSessionRecord returns the session history record of the last ride stopped (false if
+// there's none), so that notes and tags are requested only once for each ride
+func (m *StateManager) TakeLastSessionRecord() (config.SessionRecord, bool) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record := m.lastRecord
+	m.lastRecord = nil
+
+	if record == nil {
+		return config.SessionRecord{}, false
+	}
+
+	return *record, true
+}
+
+// AnnotateSession adds notes and tags to a ride recorded in the session history
+func (m *StateManager) AnnotateSession(record config.SessionRecord, notes string, tags []string) error {
+
+	// The ride is recorded in the background when the session stops
+	m.historyWrites.Wait()
+
+	path, err := sessionHistoryPath()
+	if err != nil {
+		return fmt.Errorf(errFormat, "failed to locate session history", err)
+	}
+
+	if err := config.AnnotateSessionHistory(path, record.Started, notes, tags); err != nil {
+		return fmt.Errorf(errFormat, "failed to annotate session", err)
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("session notes and tags recorded (%d tags)", len(tags)))
+
+	return nil
+}
+
+// recordSessionHistory adds the record of a completed session to the session history
+func recordSessionHistory(record config.SessionRecord) {
+
+	path, err := sessionHistoryPath()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to locate session history: %v", err))
+
+		return
+	}
+
+	if err := config.AppendSessionHistory(path, record); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to update session history: %v", err))
+	}
+
+}
+
+// recordUsageStats adds the record of a completed session to the usage statistics
+func recordUsageStats(record config.SessionRecord) {
+
+	path, err := usageStatsPath()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to locate usage statistics: %v", err))
+
+		return
+	}
+
+	if err := config.AddUsageStats(path, record); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to update usage statistics: %v", err))
+	}
+
+}
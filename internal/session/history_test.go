@@ -0,0 +1,48 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestAnnotateSession tests that the last ride stopped is taken only once, and that its notes and
+// tags are added to the session history
+func TestAnnotateSession(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), config.SessionHistoryFile)
+
+	sessionHistoryPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { sessionHistoryPath = config.SessionHistoryPath })
+
+	mgr := NewManager()
+	record := config.SessionRecord{Title: "Morning ride", Started: time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC), RideSecs: 1800}
+
+	mgr.lastRecord = &record
+	mgr.historyWrites.Go(func() { recordSessionHistory(record) })
+
+	got, ok := mgr.TakeLastSessionRecord()
+	if !ok || !got.Started.Equal(record.Started) {
+		t.Fatalf("TakeLastSessionRecord() = %+v, %t, want the last ride", got, ok)
+	}
+
+	if _, ok := mgr.TakeLastSessionRecord(); ok {
+		t.Errorf("TakeLastSessionRecord() returned the last ride twice")
+	}
+
+	if err := mgr.AnnotateSession(got, "Easy spin", []string{"recovery"}); err != nil {
+		t.Fatalf("AnnotateSession() returned error: %v", err)
+	}
+
+	history, err := config.LoadSessionHistory(path)
+	if err != nil {
+		t.Fatalf("LoadSessionHistory() returned error: %v", err)
+	}
+
+	if len(history.Sessions) != 1 || history.Sessions[0].Notes != "Easy spin" || len(history.Sessions[0].Tags) != 1 {
+		t.Errorf("session history = %+v, want the ride with its notes and tags", history.Sessions)
+	}
+
+}
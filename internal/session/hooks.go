@@ -0,0 +1,128 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// HookContext is the session event and metrics passed (as JSON) to a hook command
+type HookContext struct {
+	Event        string    `json:"event"`
+	Time         time.Time `json:"time"`
+	SessionTitle string    `json:"session_title"`
+	SpeedUnits   string    `json:"speed_units"`
+	Speed        float64   `json:"speed"`
+	RideTimeSecs int       `json:"ride_time_secs"`
+	DistanceKM   float64   `json:"distance_km"`
+	Calories     float64   `json:"calories"`
+	GoalType     string    `json:"goal_type"`
+	GoalPercent  float64   `json:"goal_percent"`
+	GoalReached  bool      `json:"goal_reached"`
+	LapCount     int       `json:"lap_count"`
+
+	// Last lap of the session (lap events only)
+	LapTimeSecs   int     `json:"lap_time_secs,omitempty"`
+	LapDistanceKM float64 `json:"lap_distance_km,omitempty"`
+}
+
+// runHookCommand runs a hook command (replaced in tests)
+var runHookCommand = runHook
+
+// fireHook runs the hook command configured for the session event, if any
+func (m *StateManager) fireHook(event string) {
+
+	defer m.readLock()()
+
+	m.fireHookLocked(event, m.controllers)
+
+}
+
+// fireHookLocked runs the hook command configured for the session event (if any) in the
+// background, passing the metrics of the session controllers (the caller must hold at least the
+// read lock)
+func (m *StateManager) fireHookLocked(event string, ctrl *controllers) {
+
+	if m.activeConfig == nil {
+		return
+	}
+
+	command := m.activeConfig.Hooks.Command(event)
+	if command == "" {
+		return
+	}
+
+	hookCtx := HookContext{
+		Event:        event,
+		Time:         time.Now(),
+		SessionTitle: m.activeConfig.App.SessionTitle,
+		SpeedUnits:   m.activeConfig.Speed.SpeedUnits,
+	}
+
+	if ctrl != nil && ctrl.speedController != nil {
+		hookCtx.Speed = ctrl.speedController.SmoothedSpeed()
+	}
+
+	if ctrl != nil && ctrl.goal != nil {
+		progress := ctrl.goal.Progress()
+		hookCtx.RideTimeSecs = int(progress.RideTime.Seconds())
+		hookCtx.DistanceKM = progress.DistanceKM
+		hookCtx.Calories = progress.Calories
+		hookCtx.GoalType = progress.Goal.Type
+		hookCtx.GoalPercent = progress.Percent()
+		hookCtx.GoalReached = progress.Reached
+	}
+
+	if ctrl != nil && ctrl.laps != nil {
+
+		laps := ctrl.laps.Laps()
+		hookCtx.LapCount = len(laps)
+
+		if event == config.HookLap && len(laps) > 0 {
+			hookCtx.LapTimeSecs = int(laps[len(laps)-1].RideTime.Seconds())
+			hookCtx.LapDistanceKM = laps[len(laps)-1].DistanceKM
+		}
+	}
+
+	go runHookCommand(command, hookCtx, m.activeConfig.Hooks.Timeout())
+
+}
+
+// runHook runs a hook command using the shell, passing the session event and metrics as JSON on
+// its standard input (and in the BSC_HOOK_CONTEXT environment variable)
+func runHook(command string, hookCtx HookContext, timeout time.Duration) {
+
+	payload, err := json.Marshal(hookCtx)
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to encode %s hook context: %v", hookCtx.Event, err))
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("running %s hook: %s", hookCtx.Event, command))
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // Hook commands are configured by the user to be run
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), "BSC_HOOK_EVENT="+hookCtx.Event, "BSC_HOOK_CONTEXT="+string(payload))
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("%s hook failed: %v (%s)", hookCtx.Event, err, strings.TrimSpace(string(output))))
+
+		return
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("%s hook completed", hookCtx.Event))
+
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestRunHook tests that a hook command receives the session event and metrics as JSON
+func TestRunHook(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "hook.json")
+	hookCtx := HookContext{Event: config.HookSessionStop, SessionTitle: "Morning ride", SpeedUnits: config.SpeedUnitsMPH, RideTimeSecs: 600}
+
+	runHook("cat > "+path+" && test \"$BSC_HOOK_EVENT\" = session_stop", hookCtx, 5*time.Second)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("hook command didn't run: %v", err)
+	}
+
+	var got HookContext
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook command received invalid JSON %q: %v", data, err)
+	}
+
+	if got.Event != hookCtx.Event || got.SessionTitle != hookCtx.SessionTitle || got.RideTimeSecs != hookCtx.RideTimeSecs {
+		t.Errorf("hook command received %+v, want %+v", got, hookCtx)
+	}
+
+}
+
+// TestFireHook tests that only the hook command configured for a session event is run
+func TestFireHook(t *testing.T) {
+
+	commands := make(chan string, 3)
+
+	runHookCommand = func(command string, _ HookContext, _ time.Duration) { commands <- command }
+	t.Cleanup(func() { runHookCommand = runHook })
+
+	mgr := NewManager()
+	mgr.activeConfig = &config.Config{Hooks: config.HooksConfig{OnSessionStart: "lights on", TimeoutSecs: 10}}
+
+	mgr.fireHook(config.HookSessionStop)
+	mgr.fireHook(config.HookSessionStart)
+
+	select {
+	case command := <-commands:
+		if command != "lights on" {
+			t.Errorf("ran hook command %q, want %q", command, "lights on")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("session start hook command wasn't run")
+	}
+
+	select {
+	case command := <-commands:
+		t.Errorf("ran unexpected hook command %q", command)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+}
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// SetIntervalHandler sets the handler notified at each interval transition of the interval timer
+// (e.g., to sound a beep), used by sessions started after it's set
+func (m *StateManager) SetIntervalHandler(handler func(state speed.IntervalState)) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.intervalHandler = handler
+
+}
+
+// IntervalState returns the current interval of the interval timer of the running session (false
+// if no session is running, or the session has no interval timer)
+func (m *StateManager) IntervalState() (speed.IntervalState, bool) {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || m.controllers.intervals == nil || !m.controllers.intervals.Enabled() {
+		return speed.IntervalState{}, false
+	}
+
+	return m.controllers.intervals.State(), true
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// MarkLap requests a manual lap of the running session, marked (and shown on the OSD) at the next
+// playback speed update
+func (m *StateManager) MarkLap() error {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || m.controllers.laps == nil || !m.state.isRunning() {
+		return errSessionNotRunning
+	}
+
+	m.controllers.laps.Mark()
+	logger.Debug(logger.BackgroundCtx, logger.APP, "manual lap requested")
+
+	return nil
+}
+
+// Laps returns the completed laps of the running session (nil if no session is running)
+func (m *StateManager) Laps() []speed.Lap {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || m.controllers.laps == nil {
+		return nil
+	}
+
+	return m.controllers.laps.Laps()
+}
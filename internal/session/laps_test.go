@@ -0,0 +1,37 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// TestMarkLap tests that a manual lap can only be requested while a session is running
+func TestMarkLap(t *testing.T) {
+
+	mgr := NewManager()
+
+	if err := mgr.MarkLap(); !errors.Is(err, errSessionNotRunning) {
+		t.Errorf("MarkLap() without a session returned %v, want %v", err, errSessionNotRunning)
+	}
+
+	tracker := speed.NewLapTracker(config.LapConfig{}, config.SpeedUnitsKMH)
+	mgr.controllers = &controllers{laps: tracker}
+	mgr.state = StateRunning
+
+	if err := mgr.MarkLap(); err != nil {
+		t.Fatalf("MarkLap() returned error: %v", err)
+	}
+
+	if _, ok := tracker.Update(speed.GoalProgress{RideTime: time.Minute, DistanceKM: 0.5}); !ok {
+		t.Fatal("manual lap wasn't marked at the next update")
+	}
+
+	if laps := mgr.Laps(); len(laps) != 1 || !laps[0].Manual {
+		t.Errorf("Laps() = %+v, want a single manual lap", laps)
+	}
+
+}
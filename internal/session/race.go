@@ -0,0 +1,18 @@
+package session
+
+import (
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// RaceScore returns the live deviation from the target speed and the effort score of the running
+// session (false if no session is running, or the session isn't a race against the clock)
+func (m *StateManager) RaceScore() (speed.RaceScore, bool) {
+
+	defer m.readLock()()
+
+	if m.controllers == nil || !m.controllers.race.Enabled() {
+		return speed.RaceScore{}, false
+	}
+
+	return m.controllers.race.Score(), true
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Registry tracks the StateManager instances hosted by a single process, allowing more than one
+// session (e.g., two trainers, each with its own BLE sensor and display) to run concurrently
+type Registry struct {
+	managers []*StateManager
+	mu       sync.RWMutex
+}
+
+// NewRegistry creates a new session registry holding a single (Idle) session instance
+func NewRegistry() *Registry {
+
+	r := &Registry{}
+	r.Add()
+
+	return r
+}
+
+// Add creates a new session instance in Idle state and adds it to the registry
+func (r *Registry) Add() *StateManager {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := NewManager()
+	m.ignoreInterrupts = true // The application confirms exit on CTRL+C, and then stops every instance
+	r.managers = append(r.managers, m)
+
+	return m
+}
+
+// Managers returns a snapshot of the session instances in the order they were added
+func (r *Registry) Managers() []*StateManager {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	managers := make([]*StateManager, len(r.managers))
+	copy(managers, r.managers)
+
+	return managers
+}
+
+// Len returns the number of session instances in the registry
+func (r *Registry) Len() int {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.managers)
+}
+
+// Manager returns the session instance at the given index, or nil if the index is out of range
+func (r *Registry) Manager(index int) *StateManager {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if index < 0 || index >= len(r.managers) {
+		return nil
+	}
+
+	return r.managers[index]
+}
+
+// Running returns the running (or connecting) session instances in the registry
+func (r *Registry) Running() []*StateManager {
+
+	var running []*StateManager
+
+	for _, m := range r.Managers() {
+
+		if instanceRunning(m) {
+			running = append(running, m)
+		}
+	}
+
+	return running
+}
+
+// instanceRunning returns true if the session instance is running (or connecting)
+func instanceRunning(m *StateManager) bool {
+
+	state := m.SessionState()
+
+	return state == StateConnecting || state.isActive()
+}
+
+// StopAll stops every running (or connecting) session instance in the registry, waiting until
+// their rides are recorded in the session history
+func (r *Registry) StopAll() {
+	r.StopAllWithin(0)
+}
+
+// StopAllWithin stops every session instance as StopAll does, allowing the services of each
+// instance the given timeout to stop (see StopSessionWithin)
+func (r *Registry) StopAllWithin(timeout time.Duration) {
+
+	for i, m := range r.Managers() {
+
+		if !instanceRunning(m) {
+			continue
+		}
+
+		if err := m.StopSessionWithin(timeout); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to stop session instance %d: %v", i+1, err))
+		}
+	}
+
+	// Don't exit while a ride is still being written to the session history
+	for _, m := range r.Managers() {
+		m.historyWrites.Wait()
+	}
+
+}
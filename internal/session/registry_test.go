@@ -0,0 +1,51 @@
+package session
+
+import (
+	"testing"
+)
+
+// TestRegistry tests adding and retrieving session instances from the registry
+func TestRegistry(t *testing.T) {
+
+	r := NewRegistry()
+
+	if got := r.Len(); got != 1 {
+		t.Fatalf("NewRegistry() Len() = %d, want 1", got)
+	}
+
+	second := r.Add()
+
+	if got := r.Len(); got != 2 {
+		t.Fatalf("Len() after Add() = %d, want 2", got)
+	}
+
+	if got := r.Manager(1); got != second {
+		t.Errorf("Manager(1) = %p, want %p", got, second)
+	}
+
+	if r.Manager(0) == second {
+		t.Error("Manager(0) returned the second session instance")
+	}
+
+	for _, index := range []int{-1, 2} {
+
+		if got := r.Manager(index); got != nil {
+			t.Errorf("Manager(%d) = %p, want nil", index, got)
+		}
+	}
+
+	for i, m := range r.Managers() {
+
+		if state := m.SessionState(); state != StateIdle {
+			t.Errorf("session instance %d state = %s, want %s", i+1, state, StateIdle)
+		}
+	}
+
+	if got := r.Running(); len(got) != 0 {
+		t.Errorf("Running() = %d session instances, want none", len(got))
+	}
+
+	// Stopping idle session instances is a no-op
+	r.StopAll()
+
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Playback position of a session that isn't resumed from an earlier ride
+const startPosition = "00:00:00"
+
+// ResumePoint is where an auto-resumed session carries on from: the video playback position,
+// along with the state of the ride saved with it
+type ResumePoint struct {
+	Position   string    // Video playback position (HH:MM:SS)
+	DistanceKM float64   // Distance ridden, including any distance carried over from earlier rides
+	Speeds     []float64 // Speeds in the smoothing window, used to warm start speed smoothing
+}
+
+// IsStart returns true if the resume point is the start of the video (i.e., nothing to resume)
+func (r ResumePoint) IsStart() bool {
+	return r.Position == "" || r.Position == startPosition
+}
+
+// Apply saves the resume point in the video settings of a session configuration
+func (r ResumePoint) Apply(vc *config.VideoConfig) {
+
+	vc.SeekToPosition = r.Position
+	vc.ResumeDistanceKM = r.DistanceKM
+	vc.ResumeSpeeds = r.Speeds
+
+}
+
+// ResumePoint returns the resume point of the running session (at the start of the video if no
+// session is running)
+func (m *StateManager) ResumePoint() ResumePoint {
+
+	point := ResumePoint{Position: m.VideoPlaybackPosition()}
+
+	ctrl, _ := m.snapshotControllers()
+	if ctrl == nil {
+		return point
+	}
+
+	if ctrl.goal != nil {
+		point.DistanceKM = ctrl.goal.Progress().TotalDistanceKM()
+	}
+
+	if ctrl.speedController != nil {
+		point.Speeds = ctrl.speedController.Speeds()
+	}
+
+	return point
+}
+
+// resumeRide restores the ride state saved with the auto-resume position of a session: the speeds
+// warm start speed smoothing, and (if enabled) the distance ridden so far is carried over
+func resumeRide(ctx context.Context, ctrl *controllers, vc config.VideoConfig) {
+
+	if !vc.AutoResume || (ResumePoint{Position: vc.SeekToPosition}).IsStart() {
+		return
+	}
+
+	if ctrl.speedController != nil && len(vc.ResumeSpeeds) > 0 {
+		ctrl.speedController.WarmStart(vc.ResumeSpeeds)
+		logger.Debug(ctx, logger.APP, fmt.Sprintf("speed smoothing warm started with %d saved speeds", len(vc.ResumeSpeeds)))
+	}
+
+	if vc.ResumeDistance && ctrl.goal != nil && vc.ResumeDistanceKM > 0 {
+		ctrl.goal.CarryDistance(vc.ResumeDistanceKM)
+		logger.Info(ctx, logger.APP, fmt.Sprintf("carrying over %.2f km ridden so far", vc.ResumeDistanceKM))
+	}
+
+}
@@ -0,0 +1,428 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Self-test settings
+const (
+	selfTestSpeed        = 15.0
+	selfTestInterval     = 20 * time.Millisecond
+	selfTestStepTimeout  = 5 * time.Second
+	selfTestSourceName   = "self-test"
+	selfTestVideoFile    = "selftest.mp4"
+	selfTestTransitionCh = 16
+	selfTestShutdownSecs = 2 // The simulated services stop right away
+)
+
+// Error definitions
+var (
+	errSelfTestStep       = errors.New("self-test step failed")
+	errSelfTestNoSpeed    = errors.New("video player received no speed updates")
+	errSelfTestNotPaused  = errors.New("video player was not paused")
+	errSelfTestNotStopped = errors.New("video playback did not stop")
+	errSelfTestSequence   = errors.New("unexpected session state sequence")
+)
+
+// selfTestStates is the sequence of session states expected during the self-test
+var selfTestStates = []State{StateConnecting, StateConnected, StateRunning, StatePaused, StateRunning, StateLoaded}
+
+// SelfTest runs a complete session lifecycle (Start → Run → Pause → Resume → Stop) using
+// in-memory controllers, verifying the session lifecycle without a BLE sensor or a display
+func SelfTest(ctx context.Context) error {
+
+	logger.Info(ctx, logger.APP, "running session self-test...")
+
+	dir, err := os.MkdirTemp("", "bsc-selftest-")
+	if err != nil {
+		return fmt.Errorf(errFormat, errSelfTestStep, err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The in-memory video player never reads the video file, but session validation requires one
+	videoPath := filepath.Join(dir, selfTestVideoFile)
+	if err := os.WriteFile(videoPath, nil, 0o600); err != nil {
+		return fmt.Errorf(errFormat, errSelfTestStep, err)
+	}
+
+	player := &selfTestPlayer{}
+	m := NewManagerWithFactory(selfTestFactory(player))
+
+	if err := m.UpdateLoadedSession(selfTestConfig(videoPath), ""); err != nil {
+		return fmt.Errorf("%w (load): %v", errSelfTestStep, err)
+	}
+
+	transitions := m.SubscribeTransitions(selfTestTransitionCh)
+
+	if err := runSelfTestSteps(ctx, m, player); err != nil {
+
+		if m.SessionState() != StateLoaded {
+			_ = m.StopSession()
+		}
+
+		return err
+	}
+
+	if err := checkSelfTestStates(transitions); err != nil {
+		return err
+	}
+
+	logger.Info(ctx, logger.APP, "session self-test passed")
+
+	return nil
+}
+
+// runSelfTestSteps starts, pauses, resumes, and then stops the session
+func runSelfTestSteps(ctx context.Context, m *StateManager, player *selfTestPlayer) error {
+
+	logger.Info(ctx, logger.APP, "self-test: starting session...")
+
+	if err := m.StartSession(); err != nil {
+		return fmt.Errorf("%w (start): %v", errSelfTestStep, err)
+	}
+
+	logger.Info(ctx, logger.APP, "self-test: waiting for speed updates...")
+
+	if !waitFor(func() bool { return player.lastSpeed() > 0 }) {
+		return fmt.Errorf("%w (run): %w", errSelfTestStep, errSelfTestNoSpeed)
+	}
+
+	logger.Info(ctx, logger.APP, "self-test: pausing and resuming session...")
+
+	if err := m.PauseSession(); err != nil {
+		return fmt.Errorf("%w (pause): %v", errSelfTestStep, err)
+	}
+
+	if !player.paused.Load() {
+		return fmt.Errorf("%w (pause): %w", errSelfTestStep, errSelfTestNotPaused)
+	}
+
+	if err := m.ResumeSession(); err != nil {
+		return fmt.Errorf("%w (resume): %v", errSelfTestStep, err)
+	}
+
+	logger.Info(ctx, logger.APP, "self-test: stopping session...")
+
+	if err := m.StopSession(); err != nil {
+		return fmt.Errorf("%w (stop): %v", errSelfTestStep, err)
+	}
+
+	if !waitFor(func() bool { return !player.playing.Load() }) {
+		return fmt.Errorf("%w (stop): %w", errSelfTestStep, errSelfTestNotStopped)
+	}
+
+	return nil
+}
+
+// checkSelfTestStates confirms that the session moved through the expected states
+func checkSelfTestStates(transitions <-chan Transition) error {
+
+	var states []State
+
+	for {
+
+		select {
+
+		case t := <-transitions:
+
+			if t.From != t.To {
+				states = append(states, t.To)
+			}
+
+		default:
+
+			if !slices.Equal(states, selfTestStates) {
+				return fmt.Errorf("%w: got %v, want %v", errSelfTestSequence, states, selfTestStates)
+			}
+
+			return nil
+		}
+	}
+
+}
+
+// waitFor polls the condition until it's true or the self-test step times out
+func waitFor(condition func() bool) bool {
+
+	deadline := time.Now().Add(selfTestStepTimeout)
+
+	for time.Now().Before(deadline) {
+
+		if condition() {
+			return true
+		}
+
+		time.Sleep(selfTestInterval)
+	}
+
+	return condition()
+}
+
+// selfTestFactory creates the in-memory controllers used by the self-test
+func selfTestFactory(player *selfTestPlayer) ControllerFactory {
+
+	factory := DefaultControllerFactory()
+
+	factory.VideoPlayer = func(_ context.Context, _ *config.Config) (VideoPlayer, error) {
+		return player, nil
+	}
+
+	factory.SpeedSource = func(_ context.Context, cfg *config.Config) (speed.SpeedSource, error) {
+		return &selfTestSource{units: cfg.Speed.SpeedUnits}, nil
+	}
+
+	factory.BLEController = func(_ context.Context, _ *config.Config) (*ble.Controller, error) {
+		return nil, errSelfTestStep // The self-test never uses the BLE sensor
+	}
+
+	return factory
+}
+
+// selfTestConfig returns a valid session configuration for the self-test
+func selfTestConfig(videoPath string) *config.Config {
+
+	return &config.Config{
+		App: config.AppConfig{
+			SessionTitle:        "BSC Self-Test",
+			LogLevel:            "info",
+			ShutdownTimeoutSecs: selfTestShutdownSecs,
+		},
+		BLE: config.BLEConfig{
+			SensorBDAddr:    "AA:BB:CC:DD:EE:FF",
+			ScanTimeoutSecs: 1,
+			ScanAttempts:    1,
+		},
+		Speed: config.SpeedConfig{
+			WheelCircumferenceMM: 2155,
+			SpeedUnits:           config.SpeedUnitsMPH,
+			SpeedThreshold:       0.25,
+			SmoothingWindow:      1,
+		},
+		Video: config.VideoConfig{
+			MediaPlayer:       config.MediaPlayerMPV,
+			FilePath:          videoPath,
+			SeekToPosition:    "00:00:00",
+			WindowScaleFactor: 1.0,
+			UpdateIntervalSec: 0.25,
+			SpeedMultiplier:   1.0,
+			HardwareDecoding:  config.HWDecOff,
+			Audio: config.VideoAudioConfig{
+				Volume: 100,
+			},
+			OnScreenDisplay: config.VideoOSDConfig{
+				FontSize: 40,
+				AlignX:   "left",
+				AlignY:   "top",
+			},
+		},
+		Goal: config.GoalConfig{
+			RiderWeightKG: 75,
+		},
+		Hooks: config.HooksConfig{
+			TimeoutSecs: 10,
+		},
+	}
+}
+
+// selfTestSource is an in-memory speed source that reports a constant speed
+type selfTestSource struct {
+	units string
+}
+
+// Name returns the name of the self-test speed source
+func (s *selfTestSource) Name() string {
+	return selfTestSourceName
+}
+
+// Units returns the speed units of the self-test speed source
+func (s *selfTestSource) Units() string {
+	return s.units
+}
+
+// Updates sends a constant speed to the updater until the context is canceled
+func (s *selfTestSource) Updates(ctx context.Context, updater speed.Updater) error {
+
+	ticker := time.NewTicker(selfTestInterval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case <-ticker.C:
+			updater.UpdateSpeed(ctx, selfTestSpeed)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+}
+
+// selfTestPlayer is an in-memory video player that records the speeds it would play back
+type selfTestPlayer struct {
+	heartbeat func()
+	speed     float64
+	volume    int
+	paused    atomic.Bool
+	playing   atomic.Bool
+	muted     bool
+	mu        sync.Mutex
+}
+
+// StartPlayback "plays" the video, tracking the smoothed speed until the context is canceled
+func (p *selfTestPlayer) StartPlayback(ctx context.Context, speedController *speed.Controller) error {
+
+	p.playing.Store(true)
+	defer p.playing.Store(false)
+
+	ticker := time.NewTicker(selfTestInterval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case <-ticker.C:
+			p.mu.Lock()
+			p.speed = speedController.SmoothedSpeed()
+			heartbeat := p.heartbeat
+			p.mu.Unlock()
+
+			if heartbeat != nil {
+				heartbeat()
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+}
+
+// lastSpeed returns the most recent speed tracked by the player
+func (p *selfTestPlayer) lastSpeed() float64 {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.speed
+}
+
+// SetPaused pauses or resumes playback
+func (p *selfTestPlayer) SetPaused(paused bool) error {
+
+	p.paused.Store(paused)
+
+	return nil
+}
+
+// SetEventHandler ignores playback events (the self-test player reports none)
+func (p *selfTestPlayer) SetEventHandler(_ func(message string)) {}
+
+// SetGoalTracker ignores the session goal (the self-test doesn't ride toward one)
+func (p *selfTestPlayer) SetGoalTracker(_ *speed.GoalTracker) {}
+
+// SetIntervalTimer ignores the interval timer (the self-test doesn't ride intervals)
+func (p *selfTestPlayer) SetIntervalTimer(_ *speed.IntervalTimer, _ func(state speed.IntervalState)) {
+}
+
+// SetLapTracker ignores the lap tracker (the self-test doesn't ride laps)
+func (p *selfTestPlayer) SetLapTracker(_ *speed.LapTracker, _ func(lap speed.Lap)) {}
+
+// SetRaceScorer ignores the race scorer (the self-test doesn't race against the clock)
+func (p *selfTestPlayer) SetRaceScorer(_ *speed.RaceScorer) {}
+
+// SetOutdoorRide ignores the outdoor ride (the self-test doesn't compare against an outdoor ride)
+func (p *selfTestPlayer) SetOutdoorRide(_ *speed.OutdoorRide) {}
+
+// SetHeartbeat sets the function called each time playback makes progress
+func (p *selfTestPlayer) SetHeartbeat(heartbeat func()) {
+
+	p.mu.Lock()
+	p.heartbeat = heartbeat
+	p.mu.Unlock()
+
+}
+
+// TimeRemaining returns the time remaining in the (nonexistent) video
+func (p *selfTestPlayer) TimeRemaining() (string, error) {
+	return "00:00:00", nil
+}
+
+// PlaybackPosition returns the playback position in the (nonexistent) video
+func (p *selfTestPlayer) PlaybackPosition() (string, error) {
+	return "00:00:00", nil
+}
+
+// CountdownRemaining returns no start countdown (the self-test starts at once)
+func (p *selfTestPlayer) CountdownRemaining() time.Duration {
+	return 0
+}
+
+// Seek ignores seeking (the self-test video has no playback position)
+func (p *selfTestPlayer) Seek(_ time.Duration, _ bool) error {
+	return nil
+}
+
+// Position returns the playback position in the (nonexistent) video
+func (p *selfTestPlayer) Position() (time.Duration, error) {
+	return 0, nil
+}
+
+// Duration returns the duration of the (nonexistent) video
+func (p *selfTestPlayer) Duration() (time.Duration, error) {
+	return 0, nil
+}
+
+// PlaybackSpeed returns the playback rate multiplier
+func (p *selfTestPlayer) PlaybackSpeed() float64 {
+	return p.lastSpeed() / selfTestSpeed
+}
+
+// Volume returns the audio volume and mute state
+func (p *selfTestPlayer) Volume() (int, bool) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.volume, p.muted
+}
+
+// SetVolume sets the audio volume
+func (p *selfTestPlayer) SetVolume(volume int) error {
+
+	p.mu.Lock()
+	p.volume = volume
+	p.mu.Unlock()
+
+	return nil
+}
+
+// SetMute sets the audio mute state
+func (p *selfTestPlayer) SetMute(muted bool) error {
+
+	p.mu.Lock()
+	p.muted = muted
+	p.mu.Unlock()
+
+	return nil
+}
+
+// ID returns the instance ID of the self-test player
+func (p *selfTestPlayer) ID() int64 {
+	return 0
+}
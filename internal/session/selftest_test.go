@@ -0,0 +1,33 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// TestSelfTest runs a complete session lifecycle using in-memory controllers
+func TestSelfTest(t *testing.T) {
+
+	if err := SelfTest(logger.BackgroundCtx); err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+
+}
+
+// TestNewManagerWithFactory tests that a session manager uses the given controller factory
+func TestNewManagerWithFactory(t *testing.T) {
+
+	player := &selfTestPlayer{}
+	mgr := NewManagerWithFactory(selfTestFactory(player))
+
+	if mgr.SessionState() != StateIdle {
+		t.Errorf("NewManagerWithFactory() state = %v, want %v", mgr.SessionState(), StateIdle)
+	}
+
+	videoPlayer, err := mgr.factory.VideoPlayer(logger.BackgroundCtx, nil)
+	if err != nil || videoPlayer != player {
+		t.Errorf("NewManagerWithFactory() video player = (%v, %v), want self-test player", videoPlayer, err)
+	}
+
+}
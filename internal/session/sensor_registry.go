@@ -0,0 +1,58 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// sensorRegistryPath returns the path of the sensor registry (replaced in tests)
+var sensorRegistryPath = config.SensorRegistryPath
+
+// sensorProfileLocked returns the registry profile of the BLE sensor connected by the session
+// controllers (the caller must hold at least the read lock)
+func (m *StateManager) sensorProfileLocked(ctrl *controllers) (config.SensorProfile, bool) {
+
+	if ctrl == nil || ctrl.bleController == nil || ctrl.bleDevice == nil || m.activeConfig == nil {
+		return config.SensorProfile{}, false
+	}
+
+	bleController := ctrl.bleController
+
+	return config.SensorProfile{
+		Name:         bleController.LocalNameLast(),
+		BDAddr:       m.activeConfig.BLE.SensorBDAddr,
+		Type:         bleController.SensorType(),
+		BatteryLevel: int(bleController.BatteryLevelLast()),
+		LastSeen:     time.Now(),
+	}, true
+}
+
+// recordSensorProfile records a BLE sensor profile in the sensor registry: the sensor's advertised
+// name is only used until the sensor is given a nickname
+func recordSensorProfile(profile config.SensorProfile) {
+
+	path, err := sensorRegistryPath()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to locate sensor registry: %v", err))
+
+		return
+	}
+
+	err = config.UpdateSensorRegistry(path, func(r *config.SensorRegistry) {
+
+		if known, ok := r.Lookup(profile.BDAddr); ok && known.Name != "" {
+			profile.Name = ""
+		}
+
+		r.Record(profile)
+
+	})
+
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("failed to update sensor registry: %v", err))
+	}
+
+}
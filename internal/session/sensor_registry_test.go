@@ -0,0 +1,55 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestRecordSensorProfile tests that recording a connected sensor keeps the sensor's nickname
+func TestRecordSensorProfile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), config.SensorRegistryFile)
+
+	sensorRegistryPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { sensorRegistryPath = config.SensorRegistryPath })
+
+	lastSeen := time.Now()
+
+	// The advertised name is used until the sensor is given a nickname
+	recordSensorProfile(config.SensorProfile{Name: "Speed 2", BDAddr: "F1:42:D8:DE:35:16", LastSeen: lastSeen})
+
+	registry, err := config.LoadSensorRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadSensorRegistry() returned error: %v", err)
+	}
+
+	if got := registry.DisplayName("F1:42:D8:DE:35:16"); got != "Speed 2" {
+		t.Errorf("DisplayName() = %q, want %q", got, "Speed 2")
+	}
+
+	registry.Rename("F1:42:D8:DE:35:16", "Rear wheel")
+
+	if err := registry.Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	recordSensorProfile(config.SensorProfile{Name: "Speed 2", BDAddr: "F1:42:D8:DE:35:16", Type: config.SensorTypeSpeed, BatteryLevel: 90, LastSeen: lastSeen.Add(time.Minute)})
+
+	registry, err = config.LoadSensorRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadSensorRegistry() returned error: %v", err)
+	}
+
+	profile, ok := registry.Lookup("F1:42:D8:DE:35:16")
+	if !ok {
+		t.Fatal("Lookup() did not find the recorded sensor")
+	}
+
+	if profile.Name != "Rear wheel" || profile.Type != config.SensorTypeSpeed || profile.BatteryLevel != 90 {
+		t.Errorf("Lookup() = %+v, want nickname %q, type %q and battery level 90", profile, "Rear wheel", config.SensorTypeSpeed)
+	}
+
+}
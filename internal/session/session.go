@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/services"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
 )
 
 const (
@@ -21,32 +24,21 @@ var (
 	errNoSessionLoaded       = errors.New("no session loaded")
 	errSessionAlreadyStarted = errors.New("session already started")
 	errInvalidState          = errors.New("invalid state for start")
+	errInvalidTransition     = errors.New("invalid session state transition")
 )
 
-// State represents the current state of a session
-type State int
-
-const (
-	StateIdle State = iota
-	StateLoaded
-	StateConnecting
-	StateConnected
-	StateRunning
-	StatePaused
-	StateError
-)
+// Event captures a significant session event (e.g., sensor connected) for display
+type Event struct {
+	Time    time.Time
+	Message string
+}
 
-// String returns a human-readable representation of the state
-func (s State) String() string {
-	return [...]string{
-		"Idle",
-		"Loaded",
-		"Connecting",
-		"Connected",
-		"Running",
-		"Paused",
-		"Error",
-	}[s]
+// ScanProgress captures the progress of the BLE peripheral scan (e.g., attempt 2 of 3), and
+// whether the sensor likely needs waking (no advertisement seen yet)
+type ScanProgress struct {
+	Attempt  int
+	Attempts int
+	WakeHint bool
 }
 
 // StateManager coordinates session lifecycle and state
@@ -59,18 +51,40 @@ type StateManager struct {
 	editConfig     *config.Config // The "getting edited" config
 	editConfigPath string
 
-	controllers  *controllers
-	shutdownMgr  *services.ShutdownManager
-	errorMsg     string
-	state        State
-	mu           sync.RWMutex
-	PendingStart bool
+	intervalHandler func(state speed.IntervalState) // Notified at each interval transition (e.g., to sound a beep)
+
+	lastRecord    *config.SessionRecord // Record of the last ride stopped (until taken to add notes and tags)
+	historyWrites sync.WaitGroup        // Pending session history updates
+
+	commands         commandQueue  // Serializes start, stop, pause, and resume requests
+	starting         *pendingStart // Startup in progress (nil once the session has started or stopped)
+	startRequests    atomic.Int32  // Start requests queued but not yet run
+	ignoreInterrupts bool          // Shutdown signals (e.g., CTRL+C) are handled by the application
+	controllers      *controllers
+	factory          ControllerFactory
+	shutdownMgr      *services.ShutdownManager
+	lastEvent        atomic.Pointer[Event]
+	scanProgress     atomic.Pointer[ScanProgress]
+	wakeHint         atomic.Bool
+	transitionCh     []chan Transition
+	errorMsg         string
+	err              error // Last session error (categorized by the apperr package, where known)
+	startErr         error // Service failure while starting (e.g., video playback of a video-first start)
+	state            State
+	mu               sync.RWMutex
 }
 
 // NewManager creates a new session manager in Idle state
 func NewManager() *StateManager {
+	return NewManagerWithFactory(DefaultControllerFactory())
+}
+
+// NewManagerWithFactory creates a new session manager in Idle state that creates its controllers
+// using the given controller factory
+func NewManagerWithFactory(factory ControllerFactory) *StateManager {
 	return &StateManager{
-		state: StateIdle,
+		state:   StateIdle,
+		factory: factory,
 	}
 }
 
@@ -82,12 +96,14 @@ func (m *StateManager) LoadTargetSession(configPath string) error {
 	cfg, err := config.Load(configPath)
 	if err != nil {
 
-		if m.state != StateRunning && m.state != StatePaused && m.state != StateConnected {
-			m.state = StateError
+		// Don't disrupt an active session, but retain the error message
+		if m.state.isActive() {
+			m.errorMsg = err.Error()
+			m.err = err
+		} else {
+			m.setErrorLocked(err)
 		}
 
-		m.errorMsg = err.Error()
-
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
@@ -101,14 +117,18 @@ func (m *StateManager) LoadTargetSession(configPath string) error {
 	}
 
 	m.errorMsg = ""
-	if m.state == StateIdle || m.state == StateError {
-		m.state = StateLoaded
-	}
+	m.err = nil
+	m.markLoadedLocked()
 
 	if cfg.App.LogLevel != "" {
 		logger.SetLogLevel(cfg.App.LogLevel)
 	}
 
+	// Settings that are likely mistakes are logged, but don't prevent the session from running
+	for _, warning := range cfg.Lint() {
+		logger.Warn(logger.BackgroundCtx, logger.APP, fmt.Sprintf("configuration warning: %s", warning))
+	}
+
 	return nil
 }
 
@@ -144,13 +164,7 @@ func (m *StateManager) UpdateLoadedSession(cfg *config.Config, path string) erro
 	// If the path matches the loaded config path, update the loaded config too
 	if m.loadedConfigPath == path {
 		m.loadedConfig = cfg
-
-		// Set the state to Loaded if we were in Error or Idle state
-		if m.state == StateError || m.state == StateIdle {
-			m.state = StateLoaded
-			m.errorMsg = ""
-		}
-
+		m.markLoadedLocked()
 	}
 
 	return nil
@@ -177,18 +191,7 @@ func (m *StateManager) ActiveConfig() *config.Config {
 
 	defer m.readLock()()
 
-	// If running, return an immutable snapshot
-	if m.activeConfig != nil {
-		return m.activeConfig
-	}
-
-	// If a session is loaded to run, return it
-	if m.loadedConfig != nil {
-		return m.loadedConfig
-	}
-
-	// Fallback to editConfig (default behavior)
-	return m.editConfig
+	return m.currentConfigLocked()
 }
 
 // EditConfigPath returns the path to the configuration currently being edited
@@ -215,12 +218,71 @@ func (m *StateManager) ErrorMessage() string {
 	return m.errorMsg
 }
 
-// SetState updates the session state (used by service controllers)
-func (m *StateManager) SetState(newState State) {
+// Err returns the last session error (nil if none), whose category (see the apperr package)
+// chooses how it's presented
+func (m *StateManager) Err() error {
+
+	defer m.readLock()()
+
+	return m.err
+}
+
+// LastEvent returns the most recent significant session event, if any
+func (m *StateManager) LastEvent() (Event, bool) {
+
+	event := m.lastEvent.Load()
+	if event == nil {
+		return Event{}, false
+	}
+
+	return *event, true
+}
+
+// recordEvent retains a significant session event as the last event
+func (m *StateManager) recordEvent(message string) {
+	m.lastEvent.Store(&Event{Time: time.Now(), Message: message})
+}
+
+// ScanProgress returns the progress of the current BLE peripheral scan, if any
+func (m *StateManager) ScanProgress() (ScanProgress, bool) {
+
+	progress := m.scanProgress.Load()
+	if progress == nil {
+		return ScanProgress{}, false
+	}
+
+	result := *progress
+	result.WakeHint = m.wakeHint.Load()
+
+	return result, true
+}
+
+// recordScanProgress retains the progress of the BLE peripheral scan as each scan attempt starts
+func (m *StateManager) recordScanProgress(attempt, attempts int) {
+
+	m.scanProgress.Store(&ScanProgress{Attempt: attempt, Attempts: attempts})
+
+	if attempts > 1 {
+		m.recordEvent(fmt.Sprintf("Scanning for BLE sensor (attempt %d/%d)...", attempt, attempts))
+	}
+
+}
+
+// recordWakeHint notes that the BLE sensor hasn't advertised yet (and likely needs waking)
+func (m *StateManager) recordWakeHint() {
+
+	m.wakeHint.Store(true)
+	m.recordEvent("Spin the wheel to wake the BLE sensor...")
+
+}
+
+// SetState updates the session state (used by service controllers), rejecting transitions not
+// permitted by the session state machine
+func (m *StateManager) SetState(newState State) error {
 
 	defer m.writeLock()()
-	m.state = newState
 
+	return m.transitionLocked(newState)
 }
 
 // SetError sets the error state with a message
@@ -228,12 +290,7 @@ func (m *StateManager) SetError(err error) {
 
 	defer m.writeLock()()
 
-	m.state = StateError
-	if err != nil {
-		m.errorMsg = err.Error()
-	} else {
-		m.errorMsg = ""
-	}
+	m.setErrorLocked(err)
 
 }
 
@@ -242,13 +299,14 @@ func (m *StateManager) Reset() {
 
 	defer m.writeLock()()
 
-	m.state = StateIdle
+	m.applyTransitionLocked(StateIdle) // Resetting to Idle is always allowed
 	m.editConfig = nil
 	m.loadedConfig = nil
 	m.activeConfig = nil
 	m.editConfigPath = ""
 	m.loadedConfigPath = ""
 	m.errorMsg = ""
+	m.err = nil
 
 }
 
@@ -260,12 +318,29 @@ func (m *StateManager) IsLoaded() bool {
 	return (m.loadedConfig != nil || m.editConfig != nil) && m.state != StateIdle
 }
 
-// IsRunning returns true if services are currently running
+// IsRunning returns true if services are currently running (including while paused)
 func (m *StateManager) IsRunning() bool {
 
 	defer m.readLock()()
 
-	return m.state == StateRunning
+	return m.state.isRunning()
+}
+
+// IsStarting returns true if a session start has been requested and the session startup hasn't
+// yet completed (e.g., while connecting to the BLE sensor)
+func (m *StateManager) IsStarting() bool {
+
+	defer m.readLock()()
+
+	return m.starting != nil || m.startRequests.Load() > 0
+}
+
+// IsPaused returns true if video playback of the running session is paused
+func (m *StateManager) IsPaused() bool {
+
+	defer m.readLock()()
+
+	return m.state == StatePaused
 }
 
 // Context returns the session's context
@@ -293,49 +368,161 @@ func (m *StateManager) Wait() {
 
 }
 
-// prepareStart validates state and snapshots editConfig to activeConfig
-func (m *StateManager) prepareStart() error {
+// Interrupted returns true if the running session was stopped by a shutdown signal (e.g., Ctrl+C)
+func (m *StateManager) Interrupted() bool {
+
+	m.mu.RLock()
+	shutdownMgr := m.shutdownMgr
+	m.mu.RUnlock()
+
+	return shutdownMgr != nil && shutdownMgr.Interrupted()
+}
+
+// ReleaseSession releases the controllers of a session whose services have already stopped (e.g.,
+// a CLI session stopped with Ctrl+C), recording its ride in the session history. A session that
+// didn't fail is moved back to the Loaded state, as if stopped
+func (m *StateManager) ReleaseSession() {
+
+	defer m.writeLock()()
+
+	if m.controllers == nil {
+		return
+	}
+
+	if m.state.isActive() || m.state == StateConnecting {
+
+		if err := m.transitionLocked(StateLoaded); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.APP, err.Error())
+		}
+	}
+
+	m.releaseControllersLocked()
+	m.shutdownMgr = nil
+
+}
+
+// prepareStart validates state and snapshots editConfig to activeConfig, returning the startup
+// now pending
+func (m *StateManager) prepareStart() (*pendingStart, error) {
 
 	defer m.writeLock()()
 
 	if m.editConfig == nil {
 		logger.Debug(logger.BackgroundCtx, logger.APP, "exiting: no config")
 
-		return errNoSessionLoaded
+		return nil, errNoSessionLoaded
 	}
 
 	// Create a snapshot of the config
-	switch {
-	case m.loadedConfig != nil:
-		m.activeConfig = m.loadedConfig
-	case m.editConfig != nil:
-		m.activeConfig = m.editConfig
-	default:
-
-		return errNoSessionLoaded
-	}
+	m.activeConfig = m.runnableConfigLocked()
 
 	if m.state == StateError {
 		logger.Debug(logger.BackgroundCtx, logger.APP, "reset from Error state to Loaded state")
-		m.state = StateLoaded
+		m.markLoadedLocked()
 	}
 
 	if m.state != StateLoaded {
 		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("exiting: invalid state for start: %s", m.state))
 
-		return fmt.Errorf(errFormatRev, errInvalidState, m.state)
+		return nil, fmt.Errorf(errFormatRev, errInvalidState, m.state)
 	}
 
 	if m.controllers != nil {
 		logger.Debug(logger.BackgroundCtx, logger.APP, "exiting: controllers already exist")
 
-		return errSessionAlreadyStarted
+		return nil, errSessionAlreadyStarted
 	}
 
-	m.PendingStart = true
-	m.state = StateConnecting
+	if err := m.transitionLocked(StateConnecting); err != nil {
+		return nil, err
+	}
+
+	m.starting = &pendingStart{done: make(chan struct{})}
+
+	return m.starting, nil
+}
+
+// currentConfigLocked returns the running session config, falling back to the config that
+// would be run next (the caller must hold a lock)
+func (m *StateManager) currentConfigLocked() *config.Config {
+
+	if m.activeConfig != nil {
+		return m.activeConfig
+	}
+
+	return m.runnableConfigLocked()
+}
+
+// runnableConfigLocked returns the config used when a session is started: the loaded config if
+// one is loaded, otherwise the config being edited (the caller must hold a lock)
+func (m *StateManager) runnableConfigLocked() *config.Config {
+
+	if m.loadedConfig != nil {
+		return m.loadedConfig
+	}
+
+	return m.editConfig
+}
+
+// markLoadedLocked moves an Idle or Error session to the Loaded state, clearing any error
+// message (the caller must hold the write lock)
+func (m *StateManager) markLoadedLocked() {
+
+	if m.state != StateIdle && m.state != StateError {
+		return
+	}
+
+	if err := m.transitionLocked(StateLoaded); err == nil {
+		m.errorMsg = ""
+		m.err = nil
+	}
+
+}
+
+// setErrorLocked moves the session to the Error state with the given error (the caller must hold
+// the write lock)
+func (m *StateManager) setErrorLocked(err error) {
+
+	m.applyTransitionLocked(StateError) // Failing to Error is always allowed
+	m.err = err
+	m.errorMsg = ""
+
+	if err != nil {
+		m.errorMsg = err.Error()
+	}
+
+}
+
+// releaseControllersLocked releases the claimed BLE sensor and drops references to the session
+// controllers and the running config snapshot (the caller must hold the write lock)
+func (m *StateManager) releaseControllersLocked() {
+
+	// Record the sensor's last seen time (and the type of data it reported) in the sensor registry
+	if profile, ok := m.sensorProfileLocked(m.controllers); ok {
+		go recordSensorProfile(profile)
+	}
+
+	// Record the ride (and whether the session goal was reached) in the session history, and
+	// add it to the usage statistics
+	if record, ok := m.sessionRecordLocked(m.controllers); ok {
+		m.lastRecord = &record
+		m.historyWrites.Go(func() {
+			recordSessionHistory(record)
+			recordUsageStats(record)
+		})
+	}
+
+	if m.controllers != nil && m.controllers.servicesStarted {
+		m.fireHookLocked(config.HookSessionStop, m.controllers)
+	}
+
+	if m.controllers != nil {
+		m.controllers.releaseSensor()
+	}
+
+	m.controllers = nil
+	m.activeConfig = nil
 
-	return nil
 }
 
 // storeShutdownMgr stores the shutdown manager under lock
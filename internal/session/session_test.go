@@ -1,11 +1,18 @@
 package session
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
 )
 
 var (
@@ -116,7 +123,10 @@ func TestStateTransitions(t *testing.T) {
 	states := []State{StateLoaded, StateConnecting, StateConnected, StateRunning}
 
 	for _, expected := range states {
-		mgr.SetState(expected)
+
+		if err := mgr.SetState(expected); err != nil {
+			t.Fatalf("SetState(%v) unexpected error: %v", expected, err)
+		}
 
 		if mgr.SessionState() != expected {
 			t.Errorf("SetState() state = %v, want %v", mgr.SessionState(), expected)
@@ -142,6 +152,14 @@ func TestSetError(t *testing.T) {
 		t.Errorf("SetError() error = %v, want %v", mgr.ErrorMessage(), errTest.Error())
 	}
 
+	// The error itself is kept, so its category can choose how it's presented
+	sensorErr := fmt.Errorf("BLE scan failed: %w", ble.ErrScanTimeout)
+	mgr.SetError(sensorErr)
+
+	if !errors.Is(mgr.Err(), ble.ErrScanTimeout) || apperr.CategoryOf(mgr.Err()) != apperr.SensorError {
+		t.Errorf("SetError() Err() = %v (%v), want a sensor error", mgr.Err(), apperr.CategoryOf(mgr.Err()))
+	}
+
 	// Test with nil error (should not panic)
 	mgr.SetError(nil)
 
@@ -151,6 +169,112 @@ func TestSetError(t *testing.T) {
 
 }
 
+// TestLastEvent tests recording and retrieving the last session event
+func TestLastEvent(t *testing.T) {
+
+	mgr := NewManager()
+
+	if _, ok := mgr.LastEvent(); ok {
+		t.Error("LastEvent() expected no event for new manager")
+	}
+
+	mgr.recordEvent("BLE sensor connected")
+	mgr.recordEvent("Video 50% complete")
+
+	event, ok := mgr.LastEvent()
+	if !ok {
+		t.Fatal("LastEvent() expected an event")
+	}
+
+	if event.Message != "Video 50% complete" {
+		t.Errorf("LastEvent() message = %q, want %q", event.Message, "Video 50% complete")
+	}
+
+	if event.Time.IsZero() {
+		t.Error("LastEvent() time should be set")
+	}
+
+}
+
+// TestSpeedDataArrived tests moving a connected video-first session to Running on speed data
+func TestSpeedDataArrived(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name  string
+		state State
+		want  State
+	}{
+		{"connected session starts running", StateConnected, StateRunning},
+		{"paused session stays paused", StatePaused, StatePaused},
+		{"stopped session stays loaded", StateLoaded, StateLoaded},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			mgr := NewManager()
+			mgr.state = tt.state
+
+			mgr.speedDataArrived()
+
+			if mgr.SessionState() != tt.want {
+				t.Errorf("speedDataArrived() state = %v, want %v", mgr.SessionState(), tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestTakeStartError tests reporting a service failure in place of the startup error it caused
+func TestTakeStartError(t *testing.T) {
+
+	mgr := NewManager()
+
+	if err := mgr.takeStartError(context.Canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("takeStartError() = %v, want %v", err, context.Canceled)
+	}
+
+	mgr.startErr = errTest
+
+	if err := mgr.takeStartError(context.Canceled); !errors.Is(err, errTest) {
+		t.Errorf("takeStartError() = %v, want %v", err, errTest)
+	}
+
+	// The service failure is only reported once
+	if err := mgr.takeStartError(context.Canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("takeStartError() = %v, want %v", err, context.Canceled)
+	}
+
+}
+
+// TestSensorNotConnected tests starting playback of (and disconnecting) a session whose sensor
+// hasn't been connected
+func TestSensorNotConnected(t *testing.T) {
+
+	mgr := NewManager()
+
+	if mgr.IsSensorReady() {
+		t.Error("IsSensorReady() should be false for new manager")
+	}
+
+	if err := mgr.StartPlayback(); !errors.Is(err, errSensorNotConnected) {
+		t.Errorf("StartPlayback() error = %v, want %v", err, errSensorNotConnected)
+	}
+
+	if err := mgr.DisconnectSensor(); !errors.Is(err, errSensorNotConnected) {
+		t.Errorf("DisconnectSensor() error = %v, want %v", err, errSensorNotConnected)
+	}
+
+	if _, ok := mgr.SensorRSSI(); ok {
+		t.Error("SensorRSSI() expected no signal strength for new manager")
+	}
+
+}
+
 // TestReset tests resetting the manager back to idle state
 func TestReset(t *testing.T) {
 
@@ -218,17 +342,37 @@ func TestConcurrentAccess(t *testing.T) {
 		})
 	}
 
-	// Concurrent state changes
+	var applied atomic.Int64
+
+	// Concurrent state changes, cycling through legal transitions (Loaded -> Connecting ->
+	// Connected -> Loaded), where a transition may be rejected after another goroutine's change
 	for range 5 {
 		wg.Go(func() {
 			for range iterations {
-				mgr.SetState(StateConnecting)
-				mgr.SetState(StateConnected)
+
+				for _, state := range []State{StateConnecting, StateConnected, StateLoaded} {
+
+					err := mgr.SetState(state)
+					if err == nil {
+						applied.Add(1)
+
+						continue
+					}
+
+					if !errors.Is(err, errInvalidTransition) {
+						t.Errorf("SetState(%v) error = %v, want nil or %v", state, err, errInvalidTransition)
+					}
+				}
 			}
 		})
 	}
 
 	wg.Wait()
+
+	if applied.Load() == 0 {
+		t.Error("SetState() rejected every state change, want legal transitions applied")
+	}
+
 }
 
 // TestStateString tests the String() method for State
@@ -296,3 +440,141 @@ func loadSession(t *testing.T, configPath string, mgr *StateManager, errMsg stri
 	}
 
 }
+
+// lockCheckingPlayer is a self-test player recording whether the manager lock is held while the
+// player is called
+type lockCheckingPlayer struct {
+	*selfTestPlayer
+	mgr    *StateManager
+	locked bool
+}
+
+// TimeRemaining records whether the manager lock is held, then returns the self-test time remaining
+func (p *lockCheckingPlayer) TimeRemaining() (string, error) {
+
+	p.checkLock()
+
+	return p.selfTestPlayer.TimeRemaining()
+}
+
+// Seek records whether the manager lock is held, then seeks the self-test video
+func (p *lockCheckingPlayer) Seek(position time.Duration, absolute bool) error {
+
+	p.checkLock()
+
+	return p.selfTestPlayer.Seek(position, absolute)
+}
+
+// SetPaused records whether the manager lock is held, then pauses or resumes the self-test video
+func (p *lockCheckingPlayer) SetPaused(paused bool) error {
+
+	p.checkLock()
+
+	return p.selfTestPlayer.SetPaused(paused)
+}
+
+// checkLock records whether the manager lock is held
+func (p *lockCheckingPlayer) checkLock() {
+
+	if p.mgr.mu.TryLock() {
+		p.mgr.mu.Unlock()
+	} else {
+		p.locked = true
+	}
+
+}
+
+// TestPlaybackControlsOutsideLock tests that seeking, pausing and resuming call the video player
+// outside the manager lock, while still updating the session state
+func TestPlaybackControlsOutsideLock(t *testing.T) {
+
+	mgr := NewManager()
+
+	player := &lockCheckingPlayer{selfTestPlayer: &selfTestPlayer{}, mgr: mgr}
+	mgr.controllers = &controllers{videoPlayer: player}
+	mgr.state = StateRunning
+
+	if err := mgr.SeekVideo(10*time.Second, false); err != nil {
+		t.Errorf("SeekVideo() error = %v", err)
+	}
+
+	if err := mgr.setPlaybackPaused(true); err != nil || mgr.SessionState() != StatePaused {
+		t.Errorf("setPlaybackPaused(true) = (%v, %v), want (nil, %v)", err, mgr.SessionState(), StatePaused)
+	}
+
+	if err := mgr.setPlaybackPaused(false); err != nil || mgr.SessionState() != StateRunning {
+		t.Errorf("setPlaybackPaused(false) = (%v, %v), want (nil, %v)", err, mgr.SessionState(), StateRunning)
+	}
+
+	if player.locked {
+		t.Error("playback controls called the video player while holding the manager lock")
+	}
+
+}
+
+// TestMetricGettersOutsideLock tests that metric getters query the controllers outside the
+// manager lock
+func TestMetricGettersOutsideLock(t *testing.T) {
+
+	mgr := NewManager()
+
+	// Check the defaults with no controllers
+	if got := mgr.VideoTimeRemaining(); got != "--:--:--" {
+		t.Errorf("VideoTimeRemaining() without controllers = %v, want --:--:--", got)
+	}
+
+	player := &lockCheckingPlayer{selfTestPlayer: &selfTestPlayer{}, mgr: mgr}
+	mgr.controllers = &controllers{videoPlayer: player}
+
+	if got := mgr.VideoTimeRemaining(); got != "00:00:00" {
+		t.Errorf("VideoTimeRemaining() = %v, want 00:00:00", got)
+	}
+
+	if player.locked {
+		t.Error("VideoTimeRemaining() queried the video player while holding the manager lock")
+	}
+
+}
+
+// TestServiceCleanExit tests that a service exiting cleanly (e.g., video playback returning on
+// CTRL+C) doesn't move a running session to the Error state
+func TestServiceCleanExit(t *testing.T) {
+
+	m, _ := newCommandTestManager(t)
+	transitions := m.SubscribeTransitions(selfTestTransitionCh)
+
+	if err := m.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	exited := make(chan struct{})
+
+	m.runService(m.Context(), currentShutdownMgr(m), "test", services.ServiceOptions{}, func(_ context.Context) error {
+		close(exited)
+
+		return nil
+	})
+
+	<-exited
+
+	// Stopping the session waits until the exited service has been handled
+	if err := m.StopSession(); err != nil {
+		t.Fatalf("StopSession() error = %v", err)
+	}
+
+	for {
+
+		select {
+
+		case tr := <-transitions:
+
+			if tr.To == StateError {
+				t.Fatalf("session moved to %v after a service exited cleanly (%q)", StateError, m.ErrorMessage())
+			}
+
+		default:
+			return
+		}
+	}
+
+}
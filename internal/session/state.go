@@ -0,0 +1,161 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// State represents the current state of a session
+type State int
+
+const (
+	StateIdle State = iota
+	StateLoaded
+	StateConnecting
+	StateConnected
+	StateRunning
+	StatePaused
+	StateError
+)
+
+// String returns a human-readable representation of the state
+func (s State) String() string {
+	return [...]string{
+		"Idle",
+		"Loaded",
+		"Connecting",
+		"Connected",
+		"Running",
+		"Paused",
+		"Error",
+	}[s]
+}
+
+// Transition describes a change in session state
+type Transition struct {
+	From State
+	To   State
+}
+
+// stateTransitions defines the allowed transitions out of each session state (remaining in the
+// same state, resetting to Idle, and failing to Error are always allowed)
+var stateTransitions = map[State][]State{
+	StateIdle:       {StateLoaded},
+	StateLoaded:     {StateConnecting},
+	StateConnecting: {StateConnected, StateRunning, StateLoaded},
+	StateConnected:  {StateRunning, StateLoaded},
+	StateRunning:    {StatePaused, StateLoaded},
+	StatePaused:     {StateRunning, StateLoaded},
+	StateError:      {StateLoaded},
+}
+
+// canTransitionTo returns true if the transition from this state to the next state is allowed
+func (s State) canTransitionTo(next State) bool {
+
+	if next == s || next == StateIdle || next == StateError {
+		return true
+	}
+
+	for _, allowed := range stateTransitions[s] {
+
+		if allowed == next {
+			return true
+		}
+
+	}
+
+	return false
+}
+
+// isActive returns true if the state has a session connected to its BLE sensor (and possibly
+// running services)
+func (s State) isActive() bool {
+	return s == StateConnected || s == StateRunning || s == StatePaused
+}
+
+// isRunning returns true if the state has session services running (including while paused)
+func (s State) isRunning() bool {
+	return s == StateRunning || s == StatePaused
+}
+
+// transitionLocked validates and applies a state transition (the caller must hold the write lock)
+func (m *StateManager) transitionLocked(next State) error {
+
+	if !m.state.canTransitionTo(next) {
+		logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("rejected session state transition: %s -> %s", m.state, next))
+
+		return fmt.Errorf("%w: %s -> %s", errInvalidTransition, m.state, next)
+	}
+
+	m.applyTransitionLocked(next)
+
+	return nil
+}
+
+// applyTransitionLocked sets the session state and notifies transition subscribers (the caller
+// must hold the write lock and have validated the transition)
+func (m *StateManager) applyTransitionLocked(next State) {
+
+	prev := m.state
+	m.state = next
+
+	if prev == next {
+		return
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.APP, fmt.Sprintf("session state transition: %s -> %s", prev, next))
+
+	// A session starts running once (resuming a paused session isn't a session start)
+	if next == StateRunning && prev != StatePaused {
+		m.fireHookLocked(config.HookSessionStart, m.controllers)
+	}
+
+	// Never block state changes on slow subscribers
+	for _, ch := range m.transitionCh {
+
+		select {
+		case ch <- Transition{From: prev, To: next}:
+		default:
+			logger.Debug(logger.BackgroundCtx, logger.APP, "session state transition subscriber full; event dropped")
+		}
+
+	}
+
+}
+
+// SubscribeTransitions returns a channel that receives session state transitions (events are
+// dropped if the channel buffer is full)
+func (m *StateManager) SubscribeTransitions(buffer int) <-chan Transition {
+
+	defer m.writeLock()()
+
+	ch := make(chan Transition, buffer)
+	m.transitionCh = append(m.transitionCh, ch)
+
+	return ch
+}
+
+// StateDiagram returns the session state machine as a Mermaid state diagram
+func StateDiagram() string {
+
+	var sb strings.Builder
+
+	sb.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&sb, "    [*] --> %s\n", StateIdle)
+
+	for from := StateIdle; from <= StateError; from++ {
+
+		for to := StateIdle; to <= StateError; to++ {
+
+			if from != to && from.canTransitionTo(to) {
+				fmt.Fprintf(&sb, "    %s --> %s\n", from, to)
+			}
+
+		}
+	}
+
+	return sb.String()
+}
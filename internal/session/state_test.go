@@ -0,0 +1,131 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCanTransitionTo tests every edge of the session state machine
+func TestCanTransitionTo(t *testing.T) {
+
+	// Allowed transitions, in addition to self-transitions and transitions to Idle and Error
+	allowed := map[State][]State{
+		StateIdle:       {StateLoaded},
+		StateLoaded:     {StateConnecting},
+		StateConnecting: {StateConnected, StateRunning, StateLoaded},
+		StateConnected:  {StateRunning, StateLoaded},
+		StateRunning:    {StatePaused, StateLoaded},
+		StatePaused:     {StateRunning, StateLoaded},
+		StateError:      {StateLoaded},
+	}
+
+	for from := StateIdle; from <= StateError; from++ {
+
+		for to := StateIdle; to <= StateError; to++ {
+
+			want := from == to || to == StateIdle || to == StateError
+
+			for _, s := range allowed[from] {
+				want = want || s == to
+			}
+
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+
+				if got := from.canTransitionTo(to); got != want {
+					t.Errorf("canTransitionTo() = %v, want %v", got, want)
+				}
+
+			})
+		}
+	}
+
+}
+
+// TestSetStateRejectsInvalidTransition tests that SetState rejects illegal state jumps
+func TestSetStateRejectsInvalidTransition(t *testing.T) {
+
+	mgr := NewManager()
+
+	err := mgr.SetState(StateRunning)
+	if !errors.Is(err, errInvalidTransition) {
+		t.Errorf("SetState(Running) from Idle error = %v, want %v", err, errInvalidTransition)
+	}
+
+	if mgr.SessionState() != StateIdle {
+		t.Errorf("SetState() state = %v, want %v", mgr.SessionState(), StateIdle)
+	}
+
+}
+
+// TestSubscribeTransitions tests that state transitions are delivered to subscribers
+func TestSubscribeTransitions(t *testing.T) {
+
+	mgr := NewManager()
+	transitions := mgr.SubscribeTransitions(4)
+
+	if err := mgr.SetState(StateLoaded); err != nil {
+		t.Fatalf("SetState(Loaded) unexpected error: %v", err)
+	}
+
+	// Self-transitions and rejected transitions are not delivered
+	if err := mgr.SetState(StateLoaded); err != nil {
+		t.Fatalf("SetState(Loaded) unexpected error: %v", err)
+	}
+
+	_ = mgr.SetState(StateRunning)
+	mgr.SetError(errTest)
+
+	want := []Transition{
+		{From: StateIdle, To: StateLoaded},
+		{From: StateLoaded, To: StateError},
+	}
+
+	for _, w := range want {
+
+		select {
+		case got := <-transitions:
+			if got != w {
+				t.Errorf("transition = %+v, want %+v", got, w)
+			}
+		default:
+			t.Fatalf("expected transition %+v, got none", w)
+		}
+
+	}
+
+	select {
+	case got := <-transitions:
+		t.Errorf("unexpected transition %+v", got)
+	default:
+	}
+
+}
+
+// TestStateDiagram tests the Mermaid state diagram helper
+func TestStateDiagram(t *testing.T) {
+
+	diagram := StateDiagram()
+
+	wantLines := []string{
+		"stateDiagram-v2",
+		"[*] --> Idle",
+		"Idle --> Loaded",
+		"Running --> Paused",
+		"Paused --> Running",
+		"Error --> Loaded",
+	}
+
+	for _, line := range wantLines {
+
+		if !strings.Contains(diagram, line) {
+			t.Errorf("StateDiagram() missing %q", line)
+		}
+
+	}
+
+	if strings.Contains(diagram, "Idle --> Running") {
+		t.Error("StateDiagram() contains illegal transition Idle --> Running")
+	}
+
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// Session time limit settings
+const (
+	timeLimitServiceName = "time limit"
+	timeLimitStopTimeout = time.Second
+)
+
+// ErrSessionTimeLimit is the error of a session stopped when its maximum duration was reached
+var ErrSessionTimeLimit = errors.New("maximum session duration reached")
+
+// startTimeLimitService launches the time limit service, if the session has a maximum duration: the
+// session is stopped (and its ride recorded) once video playback has run for that long, even while
+// paused, so a forgotten session doesn't keep the media player and BLE sensor running indefinitely
+func (m *StateManager) startTimeLimitService(ctx context.Context, shutdownMgr *services.ShutdownManager) {
+
+	m.mu.RLock()
+	cfg := m.activeConfig
+	m.mu.RUnlock()
+
+	if cfg == nil || cfg.App.MaxSessionMinutes <= 0 {
+		return
+	}
+
+	limit := time.Duration(cfg.App.MaxSessionMinutes) * time.Minute
+
+	logger.Debug(ctx, logger.APP, fmt.Sprintf("starting %s service goroutine (%v)", timeLimitServiceName, limit))
+
+	opts := services.ServiceOptions{
+		DependsOn: []string{bleServiceName, videoServiceName},
+		Timeout:   timeLimitStopTimeout,
+		OnError: func(err error) {
+			m.handleServiceError(timeLimitServiceName, err)
+		},
+	}
+
+	shutdownMgr.RunService(timeLimitServiceName, opts, func(ctx context.Context) error {
+
+		timer := time.NewTimer(limit)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-timer.C:
+			logger.Info(ctx, logger.APP, fmt.Sprintf("maximum session duration (%v) reached, stopping session...", limit))
+			m.recordEvent(fmt.Sprintf("Session stopped after %d minutes (max_session_minutes)", cfg.App.MaxSessionMinutes))
+
+			return fmt.Errorf("%w (%v)", ErrSessionTimeLimit, limit)
+		}
+	})
+
+}
@@ -0,0 +1,89 @@
+package speed
+
+import (
+	"math"
+	"time"
+)
+
+// Coasting model settings
+const (
+	coastStopFraction = 0.05            // Fraction of the coasting start speed considered stopped
+	coastHoldTime     = 2 * time.Second // Time the last speed is held before a silent sensor coasts
+)
+
+// coastState holds the state of a wheel spinning down after the rider stops pedaling
+type coastState struct {
+	decay      time.Duration
+	startSpeed float64
+}
+
+// SetCoastDecay sets the time constant of the coasting model (0 disables coasting): when the
+// rider stops pedaling, a wheel spinning down on a trainer (e.g., a fluid trainer) slows down
+// exponentially, so measured speeds that drop faster than that (e.g., the zero speed reported when
+// no wheel revolution completed since the last notification) are replaced by the coasting speed,
+// making video playback decelerate smoothly instead of stopping abruptly
+func (sc *Controller) SetCoastDecay(decay time.Duration) {
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.coast = coastState{decay: max(decay, 0)}
+
+}
+
+// coastingSpeed returns the speed to record for a speed measurement: the measured speed, or the
+// speed of the wheel coasting since the last measurement if the measured speed dropped faster
+// than that (the caller must hold the lock)
+func (sc *Controller) coastingSpeed(measured float64, now time.Time) float64 {
+
+	if sc.coast.decay <= 0 || sc.state.timestamp.IsZero() {
+		return measured
+	}
+
+	coasting := coastSpeed(sc.state.currentSpeed, now.Sub(sc.state.timestamp), sc.coast.decay)
+
+	if measured >= coasting {
+		sc.coast.startSpeed = 0
+
+		return measured
+	}
+
+	if sc.coast.startSpeed == 0 {
+		sc.coast.startSpeed = sc.state.currentSpeed
+	}
+
+	// The wheel has (nearly) stopped
+	if coasting < sc.coast.startSpeed*coastStopFraction {
+		sc.coast.startSpeed = 0
+
+		return measured
+	}
+
+	return coasting
+}
+
+// heldSpeed returns the smoothed speed, decayed by the coasting model once the speed source has
+// gone quiet for longer than the hold time (e.g., a sensor that stops sending notifications when
+// the wheel slows down), rather than holding the last measurement (the caller must hold the lock)
+func (sc *Controller) heldSpeed(now time.Time) float64 {
+
+	speed := sc.state.smoothedSpeed
+	elapsed := now.Sub(sc.state.timestamp)
+
+	if sc.coast.decay <= 0 || sc.state.timestamp.IsZero() || elapsed <= coastHoldTime {
+		return speed
+	}
+
+	coasting := coastSpeed(speed, elapsed-coastHoldTime, sc.coast.decay)
+	if coasting < speed*coastStopFraction {
+		return 0
+	}
+
+	return coasting
+}
+
+// coastSpeed returns the speed of a wheel coasting from a starting speed for the elapsed time,
+// given the decay time constant of the exponential spin-down
+func coastSpeed(speed float64, elapsed, decay time.Duration) float64 {
+	return speed * math.Exp(-elapsed.Seconds()/decay.Seconds())
+}
@@ -0,0 +1,96 @@
+package speed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// TestCoastingSpeed tests replacing measured speeds that drop faster than a coasting wheel
+func TestCoastingSpeed(t *testing.T) {
+
+	logger.Initialize("debug")
+
+	const decay = 10 * time.Second
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		decay      time.Duration
+		startSpeed float64 // Speed when coasting began (0 if not coasting)
+		lastSpeed  float64
+		elapsed    time.Duration
+		measured   float64
+		want       float64
+	}{
+		{"coasting disabled", 0, 0, 20.0, time.Second, 0.0, 0.0},
+		{"speeding up", decay, 0, 20.0, time.Second, 22.0, 22.0},
+		{"slowing down while pedaling", decay, 0, 20.0, time.Second, 19.0, 19.0},
+		{"stopped pedaling", decay, 0, 20.0, time.Second, 0.0, 20.0 * math.Exp(-0.1)},
+		{"still coasting", decay, 20.0, 10.0, time.Second, 0.0, 10.0 * math.Exp(-0.1)},
+		{"wheel stopped", decay, 20.0, 1.0, time.Second, 0.0, 0.0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			controller := NewSpeedController(logger.BackgroundCtx, 1)
+			controller.SetCoastDecay(tt.decay)
+
+			now := time.Now()
+			controller.coast.startSpeed = tt.startSpeed
+			controller.state.currentSpeed = tt.lastSpeed
+			controller.state.timestamp = now.Add(-tt.elapsed)
+
+			if got := controller.coastingSpeed(tt.measured, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("coastingSpeed() = %v, want %v", got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestHeldSpeed tests the decay of the speed once speed measurements stop arriving
+func TestHeldSpeed(t *testing.T) {
+
+	logger.Initialize("debug")
+
+	const decay = 10 * time.Second
+
+	// Define test cases
+	tests := []struct {
+		name    string
+		decay   time.Duration
+		elapsed time.Duration
+		want    float64
+	}{
+		{"coasting disabled", 0, time.Minute, 20.0},
+		{"within hold time", decay, coastHoldTime, 20.0},
+		{"coasting", decay, coastHoldTime + decay, 20.0 * math.Exp(-1)},
+		{"wheel stopped", decay, coastHoldTime + 4*decay, 0.0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			controller := NewSpeedController(logger.BackgroundCtx, 1)
+			controller.SetCoastDecay(tt.decay)
+			controller.UpdateSpeed(logger.BackgroundCtx, 20.0)
+
+			now := controller.LastUpdate().Add(tt.elapsed)
+
+			if got := controller.heldSpeed(now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("heldSpeed() = %v, want %v", got, tt.want)
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,196 @@
+package speed
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Longest interval between goal tracker updates counted as riding time (longer intervals, e.g.,
+// after the host resumes from suspend, are counted as this interval)
+const maxGoalUpdateInterval = 10 * time.Second
+
+// metSpeeds defines the metabolic equivalent (MET) of cycling at (or above) each speed in km/h,
+// as used to estimate calories burned
+var metSpeeds = []struct {
+	kmh float64
+	met float64
+}{
+	{30.6, 15.8},
+	{25.7, 12.0},
+	{22.5, 10.0},
+	{19.3, 8.0},
+	{16.0, 6.8},
+	{0.0, 4.0},
+}
+
+// GoalProgress holds the ride totals of a session and its progress toward the session goal (if
+// any)
+type GoalProgress struct {
+	Goal       config.GoalConfig
+	Units      string        // Units of the goal target (e.g., "min" or "km")
+	Value      float64       // Progress toward the goal target, in the goal units
+	Started    time.Time     // Time riding started (zero until the first ride update)
+	RideTime   time.Duration // Time spent riding (moving)
+	DistanceKM float64
+	CarriedKM  float64 // Distance carried over from earlier rides of an auto-resumed session
+	Calories   float64 // Estimated calories burned, in kilocalories
+	Reached    bool
+}
+
+// Percent returns the progress toward the goal target as a percentage (0-100)
+func (g GoalProgress) Percent() float64 {
+
+	if !g.Goal.Enabled() || g.Goal.Target <= 0 {
+		return 0
+	}
+
+	return min(g.Value*100/g.Goal.Target, 100)
+}
+
+// TotalDistanceKM returns the distance ridden, including any distance carried over from earlier
+// rides of an auto-resumed session
+func (g GoalProgress) TotalDistanceKM() float64 {
+	return g.CarriedKM + g.DistanceKM
+}
+
+// String returns the progress toward the goal (e.g., "27 / 60 min (45%)")
+func (g GoalProgress) String() string {
+
+	if !g.Goal.Enabled() {
+		return "no goal"
+	}
+
+	return fmt.Sprintf("%s / %s %s (%.0f%%)", config.FormatGoalValue(g.Goal.Type, g.Value),
+		config.FormatGoalValue(g.Goal.Type, g.Goal.Target), g.Units, g.Percent())
+}
+
+// GoalTracker accumulates the ride totals (time, distance, and calories) of a session from its
+// speed updates, and tracks progress toward the session goal
+type GoalTracker struct {
+	speedConfig config.SpeedConfig
+	progress    GoalProgress
+	zoneTimes   []time.Duration // Ride time spent in each speed zone (nil if no speed zones)
+	mu          sync.Mutex
+}
+
+// NewGoalTracker creates a goal tracker for the session goal, where speeds are given in the
+// configured speed units
+func NewGoalTracker(goal config.GoalConfig, speedConfig config.SpeedConfig) *GoalTracker {
+
+	tracker := &GoalTracker{
+		speedConfig: speedConfig,
+		progress: GoalProgress{
+			Goal:  goal,
+			Units: goal.Units(speedConfig.SpeedUnits),
+		},
+	}
+
+	if len(speedConfig.SpeedZones) > 0 {
+		tracker.zoneTimes = make([]time.Duration, len(config.SpeedZoneColors))
+	}
+
+	return tracker
+}
+
+// Update adds the ride of the elapsed interval at the given speed to the ride totals, returning
+// true if this update reached the session goal
+func (t *GoalTracker) Update(speed float64, elapsed time.Duration) bool {
+
+	if speed <= 0 || elapsed <= 0 {
+		return false
+	}
+
+	elapsed = min(elapsed, maxGoalUpdateInterval)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := &t.progress
+
+	if p.Started.IsZero() {
+		p.Started = time.Now().Add(-elapsed)
+	}
+
+	kmh := t.speedConfig.Converter().KilometersPerHour(speed)
+
+	p.RideTime += elapsed
+	p.DistanceKM += kmh * elapsed.Hours()
+	p.Calories += metForSpeed(kmh) * float64(p.Goal.RiderWeightKG) * elapsed.Hours()
+
+	if zone := t.speedConfig.SpeedZone(speed); zone > 0 && zone <= len(t.zoneTimes) {
+		t.zoneTimes[zone-1] += elapsed
+	}
+
+	switch p.Goal.Type {
+	case config.GoalTypeTime:
+		p.Value = p.RideTime.Minutes()
+	case config.GoalTypeDistance:
+		p.Value = units.Distance(p.TotalDistanceKM(), t.speedConfig.SpeedUnits)
+	case config.GoalTypeCalories:
+		p.Value = p.Calories
+	default:
+		return false
+	}
+
+	if p.Reached || p.Value < p.Goal.Target {
+		return false
+	}
+
+	p.Reached = true
+
+	return true
+}
+
+// CarryDistance carries the distance ridden in earlier rides of an auto-resumed session over to
+// this ride, so a distance goal counts the total distance (the ride totals of this ride, e.g., as
+// saved in the session history, are kept apart)
+func (t *GoalTracker) CarryDistance(distanceKM float64) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := &t.progress
+	p.CarriedKM = max(distanceKM, 0)
+
+	if p.Goal.Type == config.GoalTypeDistance {
+		p.Value = units.Distance(p.TotalDistanceKM(), t.speedConfig.SpeedUnits)
+	}
+
+}
+
+// Progress returns the ride totals and the progress toward the session goal
+func (t *GoalTracker) Progress() GoalProgress {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.progress
+}
+
+// ZoneTimes returns the ride time spent in each speed zone, from the slowest zone (nil if no speed
+// zones are configured)
+func (t *GoalTracker) ZoneTimes() []time.Duration {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.zoneTimes)
+}
+
+// metForSpeed returns the metabolic equivalent (MET) of cycling at the given speed in km/h
+func metForSpeed(kmh float64) float64 {
+
+	for _, m := range metSpeeds {
+
+		if kmh >= m.kmh {
+			return m.met
+		}
+	}
+
+	return metSpeeds[len(metSpeeds)-1].met
+}
@@ -0,0 +1,199 @@
+package speed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestGoalTracker tests the ride totals and goal progress accumulated by the goal tracker
+func TestGoalTracker(t *testing.T) {
+
+	speedConfig := config.SpeedConfig{SpeedUnits: config.SpeedUnitsMPH, WheelCircumferenceMM: 2155}
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		goal      config.GoalConfig
+		speed     float64
+		updates   int
+		wantValue float64
+		wantUnits string
+		reachedAt int // Update that reaches the goal (0 = not reached)
+	}{
+		{"time goal", config.GoalConfig{Type: config.GoalTypeTime, Target: 2, RiderWeightKG: 75}, 15, 15, 2.5, "min", 12},
+		{"distance goal", config.GoalConfig{Type: config.GoalTypeDistance, Target: 0.9, RiderWeightKG: 75}, 20, 20, 20.0 / 60 * 20 / 60 * 10, "mi", 17},
+		{"calories goal", config.GoalConfig{Type: config.GoalTypeCalories, Target: 1000, RiderWeightKG: 75}, 15, 6, 10.0 * 75 / 60, "kcal", 0},
+		{"no goal", config.GoalConfig{RiderWeightKG: 75}, 15, 6, 0, "", 0},
+		{"stopped", config.GoalConfig{Type: config.GoalTypeTime, Target: 1, RiderWeightKG: 75}, 0, 10, 0, "min", 0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			tracker := NewGoalTracker(tt.goal, speedConfig)
+			reachedAt := 0
+
+			// Each update is a 10-second interval of riding
+			for i := 1; i <= tt.updates; i++ {
+
+				if tracker.Update(tt.speed, 10*time.Second) {
+
+					if reachedAt != 0 {
+						t.Errorf("Update() reported the goal reached twice (updates %d and %d)", reachedAt, i)
+					}
+
+					reachedAt = i
+				}
+			}
+
+			progress := tracker.Progress()
+
+			if reachedAt != tt.reachedAt || progress.Reached != (tt.reachedAt != 0) {
+				t.Errorf("goal reached at update %d (Reached %t), want update %d", reachedAt, progress.Reached, tt.reachedAt)
+			}
+
+			if math.Abs(progress.Value-tt.wantValue) > 1e-6 {
+				t.Errorf("Progress().Value = %.4f, want %.4f", progress.Value, tt.wantValue)
+			}
+
+			if progress.Units != tt.wantUnits {
+				t.Errorf("Progress().Units = %q, want %q", progress.Units, tt.wantUnits)
+			}
+
+		})
+	}
+
+}
+
+// TestGoalTrackerCarryDistance tests that the distance carried over from earlier rides counts
+// toward a distance goal, but not toward the ride totals of this ride
+func TestGoalTrackerCarryDistance(t *testing.T) {
+
+	speedConfig := config.SpeedConfig{SpeedUnits: config.SpeedUnitsKMH, WheelCircumferenceMM: 2155}
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		goal      config.GoalConfig
+		carriedKM float64
+		wantValue float64
+		reached   bool
+	}{
+		{"distance goal", config.GoalConfig{Type: config.GoalTypeDistance, Target: 10, RiderWeightKG: 75}, 9.5, 9.5 + 36.0/60, true},
+		{"nothing carried", config.GoalConfig{Type: config.GoalTypeDistance, Target: 10, RiderWeightKG: 75}, 0, 36.0 / 60, false},
+		{"time goal", config.GoalConfig{Type: config.GoalTypeTime, Target: 60, RiderWeightKG: 75}, 9.5, 1, false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			tracker := NewGoalTracker(tt.goal, speedConfig)
+			tracker.CarryDistance(tt.carriedKM)
+
+			// One minute of riding at 36 km/h
+			for range 6 {
+				tracker.Update(36, 10*time.Second)
+			}
+
+			progress := tracker.Progress()
+
+			if math.Abs(progress.Value-tt.wantValue) > 1e-6 {
+				t.Errorf("Progress().Value = %.4f, want %.4f", progress.Value, tt.wantValue)
+			}
+
+			if math.Abs(progress.DistanceKM-36.0/60) > 1e-6 {
+				t.Errorf("Progress().DistanceKM = %.4f, want %.4f", progress.DistanceKM, 36.0/60)
+			}
+
+			if math.Abs(progress.TotalDistanceKM()-(tt.carriedKM+36.0/60)) > 1e-6 {
+				t.Errorf("Progress().TotalDistanceKM() = %.4f, want %.4f", progress.TotalDistanceKM(), tt.carriedKM+36.0/60)
+			}
+
+			if progress.Reached != tt.reached {
+				t.Errorf("Progress().Reached = %t, want %t", progress.Reached, tt.reached)
+			}
+
+		})
+	}
+
+}
+
+// TestGoalProgressPercent tests that goal progress is capped at 100%
+func TestGoalProgressPercent(t *testing.T) {
+
+	goal := config.GoalConfig{Type: config.GoalTypeTime, Target: 60}
+
+	// Define test cases
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0 / 60 min (0%)"},
+		{27, "27 / 60 min (45%)"},
+		{75, "75 / 60 min (100%)"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.want, func(t *testing.T) {
+
+			progress := GoalProgress{Goal: goal, Units: "min", Value: tt.value}
+			if got := progress.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+		})
+	}
+
+}
+
+// TestGoalTrackerZoneTimes tests the ride time spent in each speed zone
+func TestGoalTrackerZoneTimes(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name   string
+		zones  []float64
+		speeds []float64
+		want   []time.Duration
+	}{
+		{"no speed zones", nil, []float64{10, 20}, nil},
+		{"speed zones", []float64{10, 15, 20}, []float64{5, 12, 12, 25, 0}, []time.Duration{10 * time.Second, 20 * time.Second, 0, 10 * time.Second}},
+		{"zone limits", []float64{10, 15, 20}, []float64{10, 15, 20}, []time.Duration{0, 10 * time.Second, 10 * time.Second, 10 * time.Second}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			tracker := NewGoalTracker(config.GoalConfig{}, config.SpeedConfig{SpeedUnits: config.SpeedUnitsKMH, SpeedZones: tt.zones})
+
+			for _, speed := range tt.speeds {
+				tracker.Update(speed, 10*time.Second)
+			}
+
+			got := tracker.ZoneTimes()
+			if len(got) != len(tt.want) {
+				t.Fatalf("ZoneTimes() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+
+				if got[i] != tt.want[i] {
+					t.Errorf("ZoneTimes()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,110 @@
+package speed
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// Interval timer phases
+const (
+	IntervalHard = "hard"
+	IntervalEasy = "easy"
+)
+
+// IntervalState holds the current interval of the interval timer and the time remaining in it
+type IntervalState struct {
+	Phase     string        // Current interval phase ("hard" or "easy")
+	Remaining time.Duration // Time remaining in the current interval
+	Count     int           // Number of intervals started (the first hard interval is 1)
+}
+
+// String returns the current interval and its countdown (e.g., "HARD 0:25")
+func (s IntervalState) String() string {
+
+	seconds := int((s.Remaining + time.Second - 1) / time.Second)
+
+	return fmt.Sprintf("%s %d:%02d", s.Name(), seconds/60, seconds%60)
+}
+
+// Name returns the display name of the current interval phase (e.g., "HARD")
+func (s IntervalState) Name() string {
+
+	if s.Phase == IntervalHard {
+		return "HARD"
+	}
+
+	return "EASY"
+}
+
+// IntervalTimer counts down a repeating hard/easy interval timer, starting with a hard interval
+type IntervalTimer struct {
+	intervals config.IntervalConfig
+	state     IntervalState
+	mu        sync.Mutex
+}
+
+// NewIntervalTimer creates an interval timer with the configured hard and easy intervals
+func NewIntervalTimer(intervals config.IntervalConfig) *IntervalTimer {
+
+	return &IntervalTimer{
+		intervals: intervals,
+		state: IntervalState{
+			Phase:     IntervalHard,
+			Remaining: time.Duration(intervals.HardSecs) * time.Second,
+			Count:     1,
+		},
+	}
+}
+
+// Enabled returns true if the interval timer has both a hard and an easy interval
+func (t *IntervalTimer) Enabled() bool {
+	return t.intervals.Enabled()
+}
+
+// Update counts down the elapsed interval, returning true if the timer moved to a new interval
+func (t *IntervalTimer) Update(elapsed time.Duration) bool {
+
+	if !t.Enabled() || elapsed <= 0 {
+		return false
+	}
+
+	elapsed = min(elapsed, maxGoalUpdateInterval)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &t.state
+	s.Remaining -= elapsed
+
+	if s.Remaining > 0 {
+		return false
+	}
+
+	// Carry any overrun into the next interval (or beyond, for intervals shorter than the update)
+	for s.Remaining <= 0 {
+
+		if s.Phase == IntervalHard {
+			s.Phase = IntervalEasy
+			s.Remaining += time.Duration(t.intervals.EasySecs) * time.Second
+		} else {
+			s.Phase = IntervalHard
+			s.Remaining += time.Duration(t.intervals.HardSecs) * time.Second
+		}
+
+		s.Count++
+	}
+
+	return true
+}
+
+// State returns the current interval of the interval timer
+func (t *IntervalTimer) State() IntervalState {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.state
+}
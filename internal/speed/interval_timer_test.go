@@ -0,0 +1,78 @@
+package speed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestIntervalTimer tests the interval transitions and countdown of the interval timer
+func TestIntervalTimer(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name            string
+		intervals       config.IntervalConfig
+		updates         int
+		wantTransitions int
+		wantState       IntervalState
+	}{
+		{"first hard interval", config.IntervalConfig{HardSecs: 30, EasySecs: 90}, 2, 0, IntervalState{IntervalHard, 10 * time.Second, 1}},
+		{"first easy interval", config.IntervalConfig{HardSecs: 30, EasySecs: 90}, 4, 1, IntervalState{IntervalEasy, 80 * time.Second, 2}},
+		{"second hard interval", config.IntervalConfig{HardSecs: 30, EasySecs: 90}, 12, 2, IntervalState{IntervalHard, 30 * time.Second, 3}},
+		{"intervals shorter than an update", config.IntervalConfig{HardSecs: 3, EasySecs: 4}, 1, 1, IntervalState{IntervalEasy, 4 * time.Second, 4}},
+		{"no interval timer", config.IntervalConfig{}, 10, 0, IntervalState{IntervalHard, 0, 1}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			timer := NewIntervalTimer(tt.intervals)
+			transitions := 0
+
+			// Each update is 10 seconds of riding
+			for range tt.updates {
+
+				if timer.Update(10 * time.Second) {
+					transitions++
+				}
+			}
+
+			if transitions != tt.wantTransitions {
+				t.Errorf("Update() reported %d transitions, want %d", transitions, tt.wantTransitions)
+			}
+
+			if state := timer.State(); state != tt.wantState {
+				t.Errorf("State() = %+v, want %+v", state, tt.wantState)
+			}
+
+		})
+	}
+
+}
+
+// TestIntervalStateString tests the interval countdown shown on the OSD
+func TestIntervalStateString(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		state IntervalState
+		want  string
+	}{
+		{IntervalState{Phase: IntervalHard, Remaining: 25 * time.Second}, "HARD 0:25"},
+		{IntervalState{Phase: IntervalEasy, Remaining: 89500 * time.Millisecond}, "EASY 1:30"},
+		{IntervalState{Phase: IntervalEasy, Remaining: 0}, "EASY 0:00"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		if got := tt.state.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+
+}
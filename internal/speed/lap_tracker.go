@@ -0,0 +1,150 @@
+package speed
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Lap is a lap of a session, marked automatically (by distance or time) or manually
+type Lap struct {
+	Number     int
+	RideTime   time.Duration // Time spent riding (moving) during the lap
+	DistanceKM float64
+	Manual     bool // Marked by the rider (rather than automatically)
+}
+
+// AverageKPH returns the average speed of the lap in km/h
+func (l Lap) AverageKPH() float64 {
+
+	if l.RideTime <= 0 {
+		return 0
+	}
+
+	return l.DistanceKM / l.RideTime.Hours()
+}
+
+// Describe returns a short summary of the lap in the given speed units (e.g., "Lap 3: 5:02,
+// 2.0 km, 23.8 km/h")
+func (l Lap) Describe(speedUnits string) string {
+
+	speed, shownUnits := l.AverageKPH(), units.KMH
+	if units.Imperial(speedUnits) {
+		speed, shownUnits = units.KilometersToMiles(speed), units.MPH
+	}
+
+	seconds := int(l.RideTime.Round(time.Second).Seconds())
+
+	return fmt.Sprintf("Lap %d: %d:%02d, %s, %.1f %s", l.Number, seconds/60, seconds%60, units.FormatDistance(l.DistanceKM, speedUnits), speed, shownUnits)
+}
+
+// LapTracker marks the laps of a session from its ride totals, either automatically (every lap
+// distance or lap length) or when requested by the rider
+type LapTracker struct {
+	laps      config.LapConfig
+	lapKM     float64       // Automatic lap distance in kilometers (0 = no distance laps)
+	startTime time.Duration // Ride time at the start of the current lap
+	startKM   float64       // Distance ridden at the start of the current lap
+	completed []Lap
+	requested bool // A manual lap is marked at the next update
+	mu        sync.Mutex
+}
+
+// NewLapTracker creates a lap tracker for the configured automatic laps, where lap distances are
+// given in the distance units of the speed units
+func NewLapTracker(laps config.LapConfig, speedUnits string) *LapTracker {
+
+	return &LapTracker{
+		laps:  laps,
+		lapKM: laps.DistanceKM(speedUnits),
+	}
+}
+
+// Mark requests a manual lap, marked at the next update
+func (t *LapTracker) Mark() {
+
+	t.mu.Lock()
+	t.requested = true
+	t.mu.Unlock()
+
+}
+
+// Update checks the ride totals of the session for the end of the current lap, returning the lap
+// if one was marked
+func (t *LapTracker) Update(progress GoalProgress) (Lap, bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	requested := t.requested
+	t.requested = false
+
+	lap := Lap{
+		Number:     len(t.completed) + 1,
+		RideTime:   progress.RideTime - t.startTime,
+		DistanceKM: progress.DistanceKM - t.startKM,
+		Manual:     requested,
+	}
+
+	// A manual lap needs some riding since the last lap
+	if requested && lap.RideTime > 0 {
+		return t.completeLocked(lap, progress.RideTime, progress.DistanceKM), true
+	}
+
+	switch {
+	case t.lapKM > 0 && lap.DistanceKM >= t.lapKM:
+		lap.DistanceKM = t.lapKM
+
+		return t.completeLocked(lap, progress.RideTime, t.startKM+t.lapKM), true
+
+	case t.laps.Minutes > 0 && lap.RideTime >= time.Duration(t.laps.Minutes)*time.Minute:
+		lap.RideTime = time.Duration(t.laps.Minutes) * time.Minute
+
+		return t.completeLocked(lap, t.startTime+lap.RideTime, progress.DistanceKM), true
+	}
+
+	return Lap{}, false
+}
+
+// completeLocked records a completed lap, starting the next lap at the given ride time and
+// distance (the caller must hold the lock)
+func (t *LapTracker) completeLocked(lap Lap, startTime time.Duration, startKM float64) Lap {
+
+	t.completed = append(t.completed, lap)
+	t.startTime = startTime
+	t.startKM = startKM
+
+	return lap
+}
+
+// Laps returns the completed laps of the session
+func (t *LapTracker) Laps() []Lap {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Lap(nil), t.completed...)
+}
+
+// Summary returns the laps of a finished session: the completed laps, followed by the ride since
+// the last lap (if any laps were completed, and there was some riding since)
+func (t *LapTracker) Summary(progress GoalProgress) []Lap {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	laps := append([]Lap(nil), t.completed...)
+
+	if len(laps) > 0 && progress.RideTime > t.startTime {
+		laps = append(laps, Lap{
+			Number:     len(laps) + 1,
+			RideTime:   progress.RideTime - t.startTime,
+			DistanceKM: progress.DistanceKM - t.startKM,
+		})
+	}
+
+	return laps
+}
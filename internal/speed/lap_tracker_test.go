@@ -0,0 +1,108 @@
+package speed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// TestLapTracker tests marking automatic (distance and timed) and manual laps
+func TestLapTracker(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		laps       config.LapConfig
+		speedUnits string
+		manualAt   int // Update at which a manual lap is requested (0 = none)
+		wantLaps   []Lap
+	}{
+		{"no laps", config.LapConfig{}, config.SpeedUnitsKMH, 0, nil},
+		{"distance laps", config.LapConfig{Distance: 1}, config.SpeedUnitsKMH, 0, []Lap{
+			{Number: 1, RideTime: 2 * time.Minute, DistanceKM: 1},
+			{Number: 2, RideTime: 2 * time.Minute, DistanceKM: 1},
+			{Number: 3, RideTime: 2 * time.Minute, DistanceKM: 1},
+		}},
+		{"distance laps in miles", config.LapConfig{Distance: 1}, config.SpeedUnitsMPH, 0, []Lap{
+			{Number: 1, RideTime: 4 * time.Minute, DistanceKM: units.KilometersPerMile},
+			{Number: 2, RideTime: 3 * time.Minute, DistanceKM: units.KilometersPerMile},
+		}},
+		{"timed laps", config.LapConfig{Minutes: 3}, config.SpeedUnitsKMH, 0, []Lap{
+			{Number: 1, RideTime: 3 * time.Minute, DistanceKM: 1.5},
+			{Number: 2, RideTime: 3 * time.Minute, DistanceKM: 1.5},
+		}},
+		{"manual lap", config.LapConfig{}, config.SpeedUnitsKMH, 5, []Lap{
+			{Number: 1, RideTime: 5 * time.Minute, DistanceKM: 2.5, Manual: true},
+		}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			tracker := NewLapTracker(tt.laps, tt.speedUnits)
+
+			// Each update is another minute of riding at 30 km/h
+			for minute := 1; minute <= 7; minute++ {
+
+				if minute == tt.manualAt {
+					tracker.Mark()
+				}
+
+				tracker.Update(GoalProgress{RideTime: time.Duration(minute) * time.Minute, DistanceKM: float64(minute) / 2})
+			}
+
+			laps := tracker.Laps()
+			if len(laps) != len(tt.wantLaps) {
+				t.Fatalf("Laps() returned %d laps, want %d: %+v", len(laps), len(tt.wantLaps), laps)
+			}
+
+			for i, lap := range laps {
+
+				want := tt.wantLaps[i]
+				if lap.Number != want.Number || lap.RideTime != want.RideTime || lap.Manual != want.Manual || math.Abs(lap.DistanceKM-want.DistanceKM) > 0.001 {
+					t.Errorf("lap %d = %+v, want %+v", i+1, lap, want)
+				}
+			}
+
+		})
+	}
+
+}
+
+// TestLapTrackerSummary tests that the ride since the last lap ends the laps of a finished session
+func TestLapTrackerSummary(t *testing.T) {
+
+	tracker := NewLapTracker(config.LapConfig{Minutes: 2}, config.SpeedUnitsKMH)
+
+	if laps := tracker.Summary(GoalProgress{RideTime: time.Minute, DistanceKM: 0.5}); len(laps) != 0 {
+		t.Errorf("Summary() returned %d laps before any lap was completed, want 0", len(laps))
+	}
+
+	tracker.Update(GoalProgress{RideTime: 2 * time.Minute, DistanceKM: 1})
+
+	laps := tracker.Summary(GoalProgress{RideTime: 3 * time.Minute, DistanceKM: 1.6})
+	if len(laps) != 2 || laps[1].RideTime != time.Minute || math.Abs(laps[1].DistanceKM-0.6) > 0.001 {
+		t.Errorf("Summary() = %+v, want a completed lap followed by the last partial lap", laps)
+	}
+
+}
+
+// TestLapDescribe tests the lap summary shown on the OSD
+func TestLapDescribe(t *testing.T) {
+
+	lap := Lap{Number: 3, RideTime: 5*time.Minute + 2*time.Second, DistanceKM: 2}
+
+	if got, want := lap.Describe(config.SpeedUnitsKMH), "Lap 3: 5:02, 2.0 km, 23.8 km/h"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+	if got, want := lap.Describe(config.SpeedUnitsMPH), "Lap 3: 5:02, 1.2 mi, 14.8 mph"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+}
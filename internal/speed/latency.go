@@ -0,0 +1,87 @@
+package speed
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Number of the most recent latency samples used to compute latency percentiles
+const latencyWindow = 1000
+
+// LatencyStats holds the percentiles of the latency samples recorded by a LatencyRecorder
+type LatencyStats struct {
+	Samples int // Number of samples recorded
+	P50     time.Duration
+	P95     time.Duration
+	Max     time.Duration
+}
+
+// LatencyRecorder records latency samples (e.g., from a BLE speed notification to the media
+// player speed change it causes), keeping the most recent samples to compute their percentiles
+type LatencyRecorder struct {
+	samples []time.Duration
+	next    int
+	count   int
+	mu      sync.Mutex
+}
+
+// NewLatencyRecorder creates a new latency recorder
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{samples: make([]time.Duration, 0, latencyWindow)}
+}
+
+// Record records a latency sample, replacing the oldest sample once the window is full
+func (r *LatencyRecorder) Record(latency time.Duration) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count++
+
+	if len(r.samples) < latencyWindow {
+		r.samples = append(r.samples, latency)
+
+		return
+	}
+
+	r.samples[r.next] = latency
+	r.next = (r.next + 1) % latencyWindow
+
+}
+
+// Stats returns the percentiles of the most recent latency samples
+func (r *LatencyRecorder) Stats() LatencyStats {
+
+	r.mu.Lock()
+	sorted := slices.Clone(r.samples)
+	count := r.count
+	r.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return LatencyStats{}
+	}
+
+	slices.Sort(sorted)
+
+	return LatencyStats{
+		Samples: count,
+		P50:     percentile(sorted, 50),
+		P95:     percentile(sorted, 95),
+		Max:     sorted[len(sorted)-1],
+	}
+}
+
+// String returns the latency percentiles in milliseconds (e.g., "p50 120ms, p95 240ms, max 310ms")
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("p50 %dms, p95 %dms, max %dms", s.P50.Milliseconds(), s.P95.Milliseconds(), s.Max.Milliseconds())
+}
+
+// percentile returns the nearest-rank percentile of sorted (ascending) latency samples
+func percentile(sorted []time.Duration, pct int) time.Duration {
+
+	rank := (pct*len(sorted) + 99) / 100
+
+	return sorted[max(rank-1, 0)]
+}
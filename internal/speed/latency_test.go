@@ -0,0 +1,41 @@
+package speed
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLatencyRecorder tests the latency percentiles of recorded latency samples
+func TestLatencyRecorder(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name    string
+		samples int // Samples of 1ms, 2ms, ... recorded
+		want    LatencyStats
+	}{
+		{"no samples", 0, LatencyStats{}},
+		{"one sample", 1, LatencyStats{Samples: 1, P50: time.Millisecond, P95: time.Millisecond, Max: time.Millisecond}},
+		{"hundred samples", 100, LatencyStats{Samples: 100, P50: 50 * time.Millisecond, P95: 95 * time.Millisecond, Max: 100 * time.Millisecond}},
+		{"window exceeded", latencyWindow + 500, LatencyStats{Samples: latencyWindow + 500, P50: 1000 * time.Millisecond, P95: 1450 * time.Millisecond, Max: 1500 * time.Millisecond}},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			r := NewLatencyRecorder()
+
+			for i := 1; i <= tt.samples; i++ {
+				r.Record(time.Duration(i) * time.Millisecond)
+			}
+
+			if got := r.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,228 @@
+package speed
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Error definitions
+var (
+	errOutdoorNoPoints = errors.New("GPX file contains fewer than two track points")
+	errOutdoorNoTime   = errors.New("GPX track point has no time")
+)
+
+// Mean radius of the Earth, in kilometers
+const earthRadiusKM = 6371.0088
+
+// Track points recorded below this speed (km/h) count as stopped, so their time isn't counted as
+// riding time (like the ride time of an indoor session)
+const outdoorStoppedKPH = 2.0
+
+// gpxFile holds the track points of a GPX file (track segments are joined into a single ride)
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// gpxPoint holds the position and time of a GPX track point
+type gpxPoint struct {
+	Lat  float64   `xml:"lat,attr"`
+	Lon  float64   `xml:"lon,attr"`
+	Time time.Time `xml:"time"`
+}
+
+// outdoorPoint holds the riding time and distance ridden at a point of an outdoor ride
+type outdoorPoint struct {
+	rideTime   time.Duration
+	distanceKM float64
+}
+
+// OutdoorComparison holds how far ahead of (or behind) an outdoor ride of the same route a session
+// is, by distance at the same riding time and by time at the same distance
+type OutdoorComparison struct {
+	DistanceKM float64       // Distance ahead of the outdoor ride (negative when behind)
+	Time       time.Duration // Time ahead of the outdoor ride (negative when behind)
+	TimeKnown  bool          // False once the session has ridden farther than the outdoor ride
+}
+
+// Ahead returns true if the session is ahead of (or level with) the outdoor ride
+func (c OutdoorComparison) Ahead() bool {
+	return c.DistanceKM >= 0
+}
+
+// Describe returns how far ahead of or behind the outdoor ride the session is, in the distance
+// units used with the given units of speed (e.g., "0.3 mi ahead (0:42)")
+func (c OutdoorComparison) Describe(speedUnits string) string {
+
+	direction := "ahead"
+	if !c.Ahead() {
+		direction = "behind"
+	}
+
+	description := fmt.Sprintf("%s %s", units.FormatDistance(math.Abs(c.DistanceKM), speedUnits), direction)
+
+	if c.TimeKnown {
+		secs := int(math.Abs(c.Time.Round(time.Second).Seconds()))
+		description += fmt.Sprintf(" (%d:%02d)", secs/60, secs%60)
+	}
+
+	return description
+}
+
+// OutdoorRide holds an outdoor ride of the same route as a session, recorded as a GPX file, so the
+// session can be compared against it ("vs outdoor you")
+type OutdoorRide struct {
+	points []outdoorPoint
+}
+
+// LoadOutdoorRide creates an outdoor ride from the track points of a GPX file
+func LoadOutdoorRide(path string) (*OutdoorRide, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outdoor ride GPX file: %w", err)
+	}
+	defer file.Close()
+
+	ride, err := parseOutdoorRide(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outdoor ride GPX file %s: %w", path, err)
+	}
+
+	return ride, nil
+}
+
+// parseOutdoorRide reads the track points of GPX data, accumulating the riding time (time spent
+// stopped isn't counted) and the distance ridden at each point
+func parseOutdoorRide(r io.Reader) (*OutdoorRide, error) {
+
+	var gpx gpxFile
+	if err := xml.NewDecoder(r).Decode(&gpx); err != nil {
+		return nil, err
+	}
+
+	var trackPoints []gpxPoint
+
+	for _, track := range gpx.Tracks {
+		for _, segment := range track.Segments {
+			trackPoints = append(trackPoints, segment.Points...)
+		}
+	}
+
+	if len(trackPoints) < 2 {
+		return nil, errOutdoorNoPoints
+	}
+
+	ride := &OutdoorRide{points: make([]outdoorPoint, 0, len(trackPoints))}
+	point := outdoorPoint{}
+
+	for i, trackPoint := range trackPoints {
+
+		if trackPoint.Time.IsZero() {
+			return nil, fmt.Errorf("%w (track point %d)", errOutdoorNoTime, i+1)
+		}
+
+		if i > 0 {
+			last := trackPoints[i-1]
+			distance := haversineKM(last.Lat, last.Lon, trackPoint.Lat, trackPoint.Lon)
+			elapsed := trackPoint.Time.Sub(last.Time)
+
+			if elapsed > 0 && distance/elapsed.Hours() >= outdoorStoppedKPH {
+				point.rideTime += elapsed
+			}
+
+			point.distanceKM += distance
+		}
+
+		ride.points = append(ride.points, point)
+	}
+
+	return ride, nil
+}
+
+// RideTime returns the riding time of the outdoor ride
+func (o *OutdoorRide) RideTime() time.Duration {
+	return o.points[len(o.points)-1].rideTime
+}
+
+// DistanceKM returns the distance of the outdoor ride, in kilometers
+func (o *OutdoorRide) DistanceKM() float64 {
+	return o.points[len(o.points)-1].distanceKM
+}
+
+// distanceAt returns the distance ridden outdoors after the given riding time (the whole distance
+// once the outdoor ride has ended)
+func (o *OutdoorRide) distanceAt(rideTime time.Duration) float64 {
+
+	i := sort.Search(len(o.points), func(i int) bool { return o.points[i].rideTime >= rideTime })
+	if i == len(o.points) {
+		return o.DistanceKM()
+	}
+
+	if i == 0 {
+		return o.points[0].distanceKM
+	}
+
+	from, to := o.points[i-1], o.points[i]
+	fraction := float64(rideTime-from.rideTime) / float64(to.rideTime-from.rideTime)
+
+	return from.distanceKM + fraction*(to.distanceKM-from.distanceKM)
+}
+
+// timeAt returns the riding time taken outdoors to ride the given distance (false if the distance
+// is farther than the outdoor ride)
+func (o *OutdoorRide) timeAt(distanceKM float64) (time.Duration, bool) {
+
+	i := sort.Search(len(o.points), func(i int) bool { return o.points[i].distanceKM >= distanceKM })
+	if i == len(o.points) {
+		return 0, false
+	}
+
+	if i == 0 {
+		return o.points[0].rideTime, true
+	}
+
+	from, to := o.points[i-1], o.points[i]
+	fraction := (distanceKM - from.distanceKM) / (to.distanceKM - from.distanceKM)
+
+	return from.rideTime + time.Duration(fraction*float64(to.rideTime-from.rideTime)), true
+}
+
+// Compare compares the ride totals of a session against the outdoor ride
+func (o *OutdoorRide) Compare(progress GoalProgress) OutdoorComparison {
+
+	comparison := OutdoorComparison{
+		DistanceKM: progress.DistanceKM - o.distanceAt(progress.RideTime),
+	}
+
+	if outdoorTime, ok := o.timeAt(progress.DistanceKM); ok {
+		comparison.Time = outdoorTime - progress.RideTime
+		comparison.TimeKnown = true
+	}
+
+	return comparison
+}
+
+// haversineKM returns the great-circle distance between two positions (in degrees), in kilometers
+func haversineKM(lat1, lon1, lat2, lon2 float64) float64 {
+
+	toRadians := math.Pi / 180
+	dLat := (lat2 - lat1) * toRadians
+	dLon := (lon2 - lon1) * toRadians
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRadians)*math.Cos(lat2*toRadians)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(a))
+}
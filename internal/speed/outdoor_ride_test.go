@@ -0,0 +1,148 @@
+package speed
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// testOutdoorGPX is an outdoor ride along the equator with a one-minute stop halfway
+const testOutdoorGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk>
+    <trkseg>
+      <trkpt lat="0" lon="0"><time>2026-06-01T08:00:00Z</time></trkpt>
+      <trkpt lat="0" lon="0.01"><time>2026-06-01T08:01:00Z</time></trkpt>
+      <trkpt lat="0" lon="0.01"><time>2026-06-01T08:02:00Z</time></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="0" lon="0.02"><time>2026-06-01T08:03:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+// TestParseOutdoorRide tests reading the riding time and distance of an outdoor ride from GPX data
+func TestParseOutdoorRide(t *testing.T) {
+
+	step := haversineKM(0, 0, 0, 0.01)
+
+	ride, err := parseOutdoorRide(strings.NewReader(testOutdoorGPX))
+	if err != nil {
+		t.Fatalf("parseOutdoorRide() error = %v", err)
+	}
+
+	if ride.RideTime() != 2*time.Minute {
+		t.Errorf("RideTime() = %v, want 2m0s (the stop isn't riding time)", ride.RideTime())
+	}
+
+	if math.Abs(ride.DistanceKM()-2*step) > 1e-9 || math.Abs(step-1.111951) > 1e-6 {
+		t.Errorf("DistanceKM() = %v, want %v", ride.DistanceKM(), 2*step)
+	}
+
+	// Define test cases
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"invalid XML", "<gpx><trk>", true},
+		{"no track points", `<gpx><trk><trkseg></trkseg></trk></gpx>`, true},
+		{"one track point", `<gpx><trk><trkseg><trkpt lat="0" lon="0"><time>2026-06-01T08:00:00Z</time></trkpt></trkseg></trk></gpx>`, true},
+		{"no time", `<gpx><trk><trkseg><trkpt lat="0" lon="0"></trkpt><trkpt lat="0" lon="1"></trkpt></trkseg></trk></gpx>`, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			if _, err := parseOutdoorRide(strings.NewReader(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("parseOutdoorRide() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+		})
+	}
+
+}
+
+// TestOutdoorRideCompare tests comparing the ride totals of a session against an outdoor ride
+func TestOutdoorRideCompare(t *testing.T) {
+
+	step := haversineKM(0, 0, 0, 0.01)
+
+	ride, err := parseOutdoorRide(strings.NewReader(testOutdoorGPX))
+	if err != nil {
+		t.Fatalf("parseOutdoorRide() error = %v", err)
+	}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		rideTime      time.Duration
+		distanceKM    float64
+		wantDistance  float64
+		wantTime      time.Duration
+		wantTimeKnown bool
+	}{
+		{"level", time.Minute, step, 0, 0, true},
+		{"ahead", 30 * time.Second, step, step / 2, 30 * time.Second, true},
+		{"behind", 90 * time.Second, step, -step / 2, -30 * time.Second, true},
+		{"past the finish", 3 * time.Minute, 3, 3 - 2*step, 0, false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			got := ride.Compare(GoalProgress{RideTime: tt.rideTime, DistanceKM: tt.distanceKM})
+
+			if math.Abs(got.DistanceKM-tt.wantDistance) > 1e-9 || got.TimeKnown != tt.wantTimeKnown {
+				t.Errorf("Compare() = %+v, want distance %v (time known %v)", got, tt.wantDistance, tt.wantTimeKnown)
+			}
+
+			if (got.Time - tt.wantTime).Abs() > time.Millisecond {
+				t.Errorf("Compare() time = %v, want %v", got.Time, tt.wantTime)
+			}
+
+			if got.Ahead() != (tt.wantDistance >= 0) {
+				t.Errorf("Ahead() = %v, want %v", got.Ahead(), tt.wantDistance >= 0)
+			}
+
+		})
+	}
+
+}
+
+// TestOutdoorComparisonDescribe tests describing how far ahead of or behind an outdoor ride a
+// session is
+func TestOutdoorComparisonDescribe(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		comparison OutdoorComparison
+		speedUnits string
+		want       string
+	}{
+		{"ahead", OutdoorComparison{DistanceKM: 0.5, Time: 30 * time.Second, TimeKnown: true}, config.SpeedUnitsKMH, "0.5 km ahead (0:30)"},
+		{"behind", OutdoorComparison{DistanceKM: -1.609344, Time: -65 * time.Second, TimeKnown: true}, config.SpeedUnitsMPH, "1.0 mi behind (1:05)"},
+		{"past the finish", OutdoorComparison{DistanceKM: 2}, config.SpeedUnitsKMH, "2.0 km ahead"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := tt.comparison.Describe(tt.speedUnits); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+
+		})
+	}
+
+}
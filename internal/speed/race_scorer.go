@@ -0,0 +1,90 @@
+package speed
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Speeds within this fraction of the target speed are shown as on pace
+const racePaceTolerance = 0.05
+
+// RaceScore holds the live deviation from the target speed of a race against the clock and the
+// effort score of the race so far
+type RaceScore struct {
+	Speed     float64       // Speed at the last update, in the configured speed units
+	Target    float64       // Target speed at the last update
+	Deviation float64       // Speed minus target speed (negative when behind the target)
+	Score     float64       // Effort score (0-100): how closely speed tracked the target speed
+	Scored    time.Duration // Time scored (playback time while not paused)
+}
+
+// OnPace returns true if the speed is within the pace tolerance of the target speed, or faster
+func (r RaceScore) OnPace() bool {
+	return r.Deviation >= -r.Target*racePaceTolerance
+}
+
+// Describe returns the live deviation and effort score of the race in the given speed units
+// (e.g., "14.2 / 15.0 mph (-0.8), score 87%")
+func (r RaceScore) Describe(speedUnits string) string {
+
+	return fmt.Sprintf("%s / %s %s (%+.1f), score %.0f%%", units.FormatSpeed(r.Speed, speedUnits),
+		units.FormatSpeed(r.Target, speedUnits), speedUnits, r.Deviation, r.Score)
+}
+
+// RaceScorer scores a race against the clock (where the video plays at a fixed pace) by how
+// closely the rider's speed tracks the target speed: each update is scored by its accuracy (100%
+// at the target speed, falling to 0% at twice or zero the target speed), weighted by its length
+type RaceScorer struct {
+	race     config.RaceConfig
+	accuracy float64 // Sum of the accuracy of each update, weighted by its length in seconds
+	score    RaceScore
+	mu       sync.Mutex
+}
+
+// NewRaceScorer creates a race scorer for the race-the-clock settings
+func NewRaceScorer(race config.RaceConfig) *RaceScorer {
+	return &RaceScorer{race: race}
+}
+
+// Enabled returns true if the session is a race against the clock
+func (s *RaceScorer) Enabled() bool {
+	return s != nil && s.race.Enabled()
+}
+
+// Update scores the elapsed interval at the given speed against the target speed (the easy
+// interval target if easy is set)
+func (s *RaceScorer) Update(speed float64, easy bool, elapsed time.Duration) {
+
+	if !s.Enabled() || elapsed <= 0 {
+		return
+	}
+
+	elapsed = min(elapsed, maxGoalUpdateInterval)
+	target := s.race.Target(easy)
+	accuracy := max(1-math.Abs(speed-target)/target, 0)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accuracy += accuracy * elapsed.Seconds()
+	s.score.Scored += elapsed
+	s.score.Speed = speed
+	s.score.Target = target
+	s.score.Deviation = speed - target
+	s.score.Score = s.accuracy * 100 / s.score.Scored.Seconds()
+
+}
+
+// Score returns the live deviation from the target speed and the effort score so far
+func (s *RaceScorer) Score() RaceScore {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.score
+}
@@ -0,0 +1,73 @@
+package speed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestRaceScorer tests scoring how closely speed tracks the target speed of a race
+func TestRaceScorer(t *testing.T) {
+
+	race := config.RaceConfig{TargetSpeed: 20, EasyTargetSpeed: 10}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		speeds        []float64 // Speeds of successive 10-second updates
+		easy          bool
+		wantScore     float64
+		wantDeviation float64
+		wantOnPace    bool
+	}{
+		{"on target", []float64{20, 20}, false, 100, 0, true},
+		{"slightly behind", []float64{19.5}, false, 97.5, -0.5, true},
+		{"behind then ahead", []float64{15, 25}, false, 75, 5, true},
+		{"stopped", []float64{0, 20}, false, 50, 0, true},
+		{"far behind", []float64{10}, false, 50, -10, false},
+		{"easy interval", []float64{10}, true, 100, 0, true},
+		{"twice the target", []float64{50}, false, 0, 30, true},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			scorer := NewRaceScorer(race)
+
+			for _, speed := range tt.speeds {
+				scorer.Update(speed, tt.easy, 10*time.Second)
+			}
+
+			score := scorer.Score()
+
+			if math.Abs(score.Score-tt.wantScore) > 1e-9 || math.Abs(score.Deviation-tt.wantDeviation) > 1e-9 {
+				t.Errorf("Score() = %+v, want score %v and deviation %v", score, tt.wantScore, tt.wantDeviation)
+			}
+
+			if score.OnPace() != tt.wantOnPace {
+				t.Errorf("OnPace() = %v, want %v", score.OnPace(), tt.wantOnPace)
+			}
+
+			if score.Scored != time.Duration(len(tt.speeds))*10*time.Second {
+				t.Errorf("Scored = %v, want %d updates of 10s", score.Scored, len(tt.speeds))
+			}
+
+		})
+	}
+
+	// Sessions without a race (and no scorer at all) aren't scored
+	var none *RaceScorer
+	if none.Enabled() || NewRaceScorer(config.RaceConfig{}).Enabled() {
+		t.Errorf("Enabled() = true, want false without a target speed")
+	}
+
+	score := RaceScore{Speed: 14.2, Target: 15, Deviation: -0.8, Score: 87.4}
+	if got, want := score.Describe(config.SpeedUnitsMPH), "14.2 / 15.0 mph (-0.8), score 87%"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+}
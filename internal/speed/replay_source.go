@@ -0,0 +1,174 @@
+package speed
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error definitions
+var (
+	errReplayHeader  = errors.New("replay file requires a 'speed' column and a 'timestamp' or 'elapsed_secs' column")
+	errReplaySample  = errors.New("invalid replay sample")
+	errReplayNoData  = errors.New("replay file contains no speed samples")
+	errReplayBadRate = errors.New("replay rate must be greater than zero")
+)
+
+// replaySample holds a recorded speed and its offset from the start of the recording
+type replaySample struct {
+	offset time.Duration
+	speed  float64
+}
+
+// ReplaySource is a speed source that replays the speeds of a previously recorded session in
+// real time (or at a chosen rate)
+type ReplaySource struct {
+	samples []replaySample
+	rate    float64
+	units   string
+}
+
+// The replay source is a speed source for the speed controller
+var _ SpeedSource = (*ReplaySource)(nil)
+
+// NewReplaySource creates a replay source from a recorded session CSV file, where rate scales the
+// replay speed (e.g., 2.0 replays twice as fast as recorded)
+func NewReplaySource(path string, rate float64, units string) (*ReplaySource, error) {
+
+	if rate <= 0 {
+		return nil, fmt.Errorf(errFormatRev, errReplayBadRate, rate)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer file.Close()
+
+	samples, err := parseReplaySamples(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file %s: %w", path, err)
+	}
+
+	return &ReplaySource{
+		samples: samples,
+		rate:    rate,
+		units:   units,
+	}, nil
+}
+
+// Name returns the name of the replay speed source
+func (rs *ReplaySource) Name() string {
+	return "replay"
+}
+
+// Units returns the speed units of the recorded speeds
+func (rs *ReplaySource) Units() string {
+	return rs.units
+}
+
+// Updates sends the recorded speeds to the updater with their recorded timing (scaled by the
+// replay rate), and then holds at zero speed until the context is canceled
+func (rs *ReplaySource) Updates(ctx context.Context, updater Updater) error {
+
+	start := time.Now()
+
+	for _, sample := range rs.samples {
+
+		due := start.Add(time.Duration(float64(sample.offset) / rs.rate))
+		timer := time.NewTimer(time.Until(due))
+
+		select {
+
+		case <-timer.C:
+			updater.UpdateSpeed(ctx, sample.speed)
+
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		}
+	}
+
+	// The recording has ended, so the rider has stopped
+	updater.UpdateSpeed(ctx, 0)
+	<-ctx.Done()
+
+	return nil
+}
+
+// parseReplaySamples reads recorded speed samples from CSV data with a header row, using either
+// RFC 3339 timestamps or elapsed seconds to time each sample
+func parseReplaySamples(r io.Reader) ([]replaySample, error) {
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errReplayHeader
+	}
+
+	header := records[0]
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	speedCol := slices.Index(header, "speed")
+	timestampCol := slices.Index(header, "timestamp")
+	elapsedCol := slices.Index(header, "elapsed_secs")
+
+	if speedCol < 0 || (timestampCol < 0 && elapsedCol < 0) {
+		return nil, errReplayHeader
+	}
+
+	samples := make([]replaySample, 0, len(records)-1)
+
+	var first time.Time
+
+	for line, record := range records[1:] {
+
+		speed, err := strconv.ParseFloat(strings.TrimSpace(record[speedCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %v", errReplaySample, line+2, err)
+		}
+
+		var offset time.Duration
+
+		if elapsedCol >= 0 {
+			secs, err := strconv.ParseFloat(strings.TrimSpace(record[elapsedCol]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w (line %d): %v", errReplaySample, line+2, err)
+			}
+
+			offset = time.Duration(secs * float64(time.Second))
+		} else {
+			timestamp, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(record[timestampCol]))
+			if err != nil {
+				return nil, fmt.Errorf("%w (line %d): %v", errReplaySample, line+2, err)
+			}
+
+			if first.IsZero() {
+				first = timestamp
+			}
+
+			offset = timestamp.Sub(first)
+		}
+
+		samples = append(samples, replaySample{offset: offset, speed: speed})
+	}
+
+	if len(samples) == 0 {
+		return nil, errReplayNoData
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,147 @@
+package speed
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// recordingUpdater records the speeds sent by a speed source
+type recordingUpdater struct {
+	speeds []float64
+	mu     sync.Mutex
+}
+
+// UpdateSpeed records a speed sent by a speed source
+func (r *recordingUpdater) UpdateSpeed(_ context.Context, speed float64) {
+
+	r.mu.Lock()
+	r.speeds = append(r.speeds, speed)
+	r.mu.Unlock()
+
+}
+
+// TestParseReplaySamples tests parsing recorded speed samples from CSV data
+func TestParseReplaySamples(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		data        string
+		wantOffsets []time.Duration
+		wantErr     error
+	}{
+		{
+			name:        "elapsed seconds",
+			data:        "elapsed_secs,speed\n0,10.5\n0.5,11\n1.25,12\n",
+			wantOffsets: []time.Duration{0, 500 * time.Millisecond, 1250 * time.Millisecond},
+		},
+		{
+			name:        "timestamps",
+			data:        "timestamp,wheel_revs,wheel_time,speed\n2026-01-02T10:00:00Z,1,1024,10\n2026-01-02T10:00:02Z,3,2048,12\n",
+			wantOffsets: []time.Duration{0, 2 * time.Second},
+		},
+		{
+			name:    "missing speed column",
+			data:    "elapsed_secs,cadence\n0,80\n",
+			wantErr: errReplayHeader,
+		},
+		{
+			name:    "invalid speed",
+			data:    "elapsed_secs,speed\n0,fast\n",
+			wantErr: errReplaySample,
+		},
+		{
+			name:    "no samples",
+			data:    "elapsed_secs,speed\n",
+			wantErr: errReplayNoData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			samples, err := parseReplaySamples(strings.NewReader(tt.data))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("parseReplaySamples() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseReplaySamples() error = %v", err)
+			}
+
+			if len(samples) != len(tt.wantOffsets) {
+				t.Fatalf("parseReplaySamples() returned %d samples, want %d", len(samples), len(tt.wantOffsets))
+			}
+
+			for i, sample := range samples {
+
+				if sample.offset != tt.wantOffsets[i] {
+					t.Errorf("sample %d offset = %v, want %v", i, sample.offset, tt.wantOffsets[i])
+				}
+			}
+
+		})
+	}
+
+}
+
+// TestReplaySource tests replaying a recorded session at an accelerated rate
+func TestReplaySource(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	if err := os.WriteFile(path, []byte("elapsed_secs,speed\n0,10\n1,20\n2,30\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewReplaySource(path, 0, "mph"); !errors.Is(err, errReplayBadRate) {
+		t.Errorf("NewReplaySource() with zero rate error = %v, want %v", err, errReplayBadRate)
+	}
+
+	// Replay 2 seconds of samples in 20 milliseconds
+	source, err := NewReplaySource(path, 100, "mph")
+	if err != nil {
+		t.Fatalf("NewReplaySource() error = %v", err)
+	}
+
+	if source.Units() != "mph" {
+		t.Errorf("Units() = %q, want %q", source.Units(), "mph")
+	}
+
+	ctx, cancel := context.WithTimeout(logger.BackgroundCtx, 200*time.Millisecond)
+	defer cancel()
+
+	updater := &recordingUpdater{}
+
+	if err := source.Updates(ctx, updater); err != nil {
+		t.Fatalf("Updates() error = %v", err)
+	}
+
+	want := []float64{10, 20, 30, 0}
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+
+	if len(updater.speeds) != len(want) {
+		t.Fatalf("replayed speeds = %v, want %v", updater.speeds, want)
+	}
+
+	for i, speed := range updater.speeds {
+
+		if speed != want[i] {
+			t.Errorf("replayed speeds = %v, want %v", updater.speeds, want)
+		}
+	}
+
+}
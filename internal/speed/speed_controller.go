@@ -17,13 +17,25 @@ type state struct {
 	timestamp     time.Time
 	currentSpeed  float64
 	smoothedSpeed float64
+	stats         speedStats
+}
+
+// speedStats holds the session statistics of the smoothed speed (only speeds while moving are
+// averaged, so stops don't lower the average speed)
+type speedStats struct {
+	maxSpeed    float64
+	movingSum   float64
+	movingCount int
 }
 
 // Controller manages speed measurements with smoothing over a specified time window
 type Controller struct {
 	speeds     *ring.Ring
 	state      state
+	source     SpeedSource
+	coast      coastState
 	window     int
+	warm       bool // Speeds of an earlier ride were restored, pending the first speed measurement
 	mu         sync.RWMutex
 	InstanceID int64
 }
@@ -71,6 +83,18 @@ func (sc *Controller) UpdateSpeed(ctx context.Context, speed float64) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
+	now := time.Now()
+	speed = sc.coastingSpeed(speed, now)
+
+	// Speeds restored from an earlier ride only carry on if the rider is moving at the start
+	if sc.warm {
+		sc.warm = false
+
+		if speed <= 0 {
+			sc.fillSpeeds(0)
+		}
+	}
+
 	sc.state.currentSpeed = speed
 	sc.speeds.Value = speed
 	sc.speeds = sc.speeds.Next()
@@ -91,18 +115,56 @@ func (sc *Controller) UpdateSpeed(ctx context.Context, speed float64) {
 
 	// Ahh... smoothness
 	sc.state.smoothedSpeed = sum / float64(sc.window)
-	sc.state.timestamp = time.Now()
+	sc.state.timestamp = now
+
+	if sc.state.smoothedSpeed > 0 {
+		sc.state.stats.maxSpeed = max(sc.state.stats.maxSpeed, sc.state.smoothedSpeed)
+		sc.state.stats.movingSum += sc.state.smoothedSpeed
+		sc.state.stats.movingCount++
+	}
 
 }
 
-// SmoothedSpeed returns the current smoothed speed measurement
+// SmoothedSpeed returns the current smoothed speed measurement (decayed by the coasting model, if
+// enabled, when speed measurements have stopped arriving)
 func (sc *Controller) SmoothedSpeed() float64 {
 
 	// Lock the mutex to protect the fields
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
 
-	return sc.state.smoothedSpeed
+	return sc.heldSpeed(time.Now())
+}
+
+// LastUpdate returns the time of the last speed measurement (zero if there's been none)
+func (sc *Controller) LastUpdate() time.Time {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.state.timestamp
+}
+
+// AverageSpeed returns the average smoothed speed while moving since the controller was created
+func (sc *Controller) AverageSpeed() float64 {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	if sc.state.stats.movingCount == 0 {
+		return 0
+	}
+
+	return sc.state.stats.movingSum / float64(sc.state.stats.movingCount)
+}
+
+// MaxSpeed returns the maximum smoothed speed since the controller was created
+func (sc *Controller) MaxSpeed() float64 {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.state.stats.maxSpeed
 }
 
 // SpeedBuffer returns the current speed buffer
@@ -131,3 +193,63 @@ func (sc *Controller) SpeedBuffer(ctx context.Context) []string {
 
 	return speeds
 }
+
+// Speeds returns the speeds in the smoothing window, from the oldest
+func (sc *Controller) Speeds() []float64 {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	speeds := make([]float64, 0, sc.window)
+	sc.speeds.Do(func(x any) {
+
+		if value, ok := x.(float64); ok {
+			speeds = append(speeds, value)
+		}
+
+	})
+
+	return speeds
+}
+
+// WarmStart fills the smoothing window with the speeds of an earlier ride (e.g., those saved with
+// the auto-resume position), so the smoothed speed picks up where that ride left off rather than
+// ramping up from zero. The most recent speeds are kept if there are more than fit the window,
+// and the window is topped up with their average if there are fewer. The restored speeds are
+// dropped if the first speed measurement finds the rider isn't moving
+func (sc *Controller) WarmStart(speeds []float64) {
+
+	if len(speeds) == 0 {
+		return
+	}
+
+	speeds = speeds[max(len(speeds)-sc.window, 0):]
+
+	var sum float64
+	for _, speed := range speeds {
+		sum += speed
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.fillSpeeds(sum / float64(len(speeds)))
+
+	for _, speed := range speeds {
+		sc.speeds.Value = speed
+		sc.speeds = sc.speeds.Next()
+	}
+
+	sc.warm = true
+
+}
+
+// fillSpeeds sets every speed in the smoothing window (the caller must hold the lock)
+func (sc *Controller) fillSpeeds(speed float64) {
+
+	for range sc.window {
+		sc.speeds.Value = speed
+		sc.speeds = sc.speeds.Next()
+	}
+
+}
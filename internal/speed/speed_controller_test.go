@@ -1,6 +1,8 @@
 package speed
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -114,6 +116,44 @@ func TestSmoothedSpeed(t *testing.T) {
 
 }
 
+// TestSpeedStats tests the AverageSpeed and MaxSpeed methods of Controller
+func TestSpeedStats(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		updates     []float64
+		expectedAvg float64
+		expectedMax float64
+	}{
+		{"no updates", nil, 0, 0},
+		{"stopped", []float64{0.0, 0.0}, 0, 0},
+		{"riding", []float64{10.0, 20.0}, 4.0, 6.0},
+		{"stops excluded", []float64{10.0, 0.0, 0.0, 0.0, 0.0, 0.0}, 2.0, 2.0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewSpeedController(logger.BackgroundCtx, td.window)
+
+			for _, speed := range tt.updates {
+				controller.UpdateSpeed(logger.BackgroundCtx, speed)
+			}
+
+			if got := controller.AverageSpeed(); got != tt.expectedAvg {
+				t.Errorf("AverageSpeed() = %f, want %f", got, tt.expectedAvg)
+			}
+
+			if got := controller.MaxSpeed(); got != tt.expectedMax {
+				t.Errorf("MaxSpeed() = %f, want %f", got, tt.expectedMax)
+			}
+
+		})
+	}
+
+}
+
 // TestSpeedBuffer tests the SpeedBuffer method of Controller
 func TestSpeedBuffer(t *testing.T) {
 
@@ -166,3 +206,107 @@ func TestConcurrency(t *testing.T) {
 	}
 
 }
+
+// TestWarmStart tests that the speeds of an earlier ride warm start speed smoothing
+func TestWarmStart(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		warm       []float64
+		update     float64
+		wantSpeeds []float64
+		wantSpeed  float64
+	}{
+		{"no speeds", nil, 10.0, []float64{0, 0, 0, 0, 0}, 2.0},
+		{"full window", []float64{10, 10, 10, 10, 10}, 10.0, []float64{10, 10, 10, 10, 10}, 10.0},
+		{"topped up window", []float64{1, 2, 3}, 2.0, []float64{2, 2, 1, 2, 3}, 2.0},
+		{"most recent speeds kept", []float64{0, 0, 10, 10, 10, 10, 10}, 10.0, []float64{10, 10, 10, 10, 10}, 10.0},
+		{"stopped at start", []float64{10, 10, 10, 10, 10}, 0.0, []float64{10, 10, 10, 10, 10}, 0.0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewSpeedController(logger.BackgroundCtx, td.window)
+			controller.WarmStart(tt.warm)
+
+			got := controller.Speeds()
+			if len(got) != len(tt.wantSpeeds) {
+				t.Fatalf("Speeds() = %v, want %v", got, tt.wantSpeeds)
+			}
+
+			for i, val := range tt.wantSpeeds {
+
+				if got[i] != val {
+					t.Errorf("Speeds() = %v, want %v", got, tt.wantSpeeds)
+
+					break
+				}
+
+			}
+
+			controller.UpdateSpeed(logger.BackgroundCtx, tt.update)
+
+			if got := controller.SmoothedSpeed(); got != tt.wantSpeed {
+				t.Errorf("SmoothedSpeed() = %f, want %f", got, tt.wantSpeed)
+			}
+
+		})
+	}
+
+}
+
+// fakeSource is a speed source that sends a fixed set of speeds
+type fakeSource struct {
+	speeds []float64
+}
+
+// Name returns the name of the fake speed source
+func (f *fakeSource) Name() string {
+	return "fake"
+}
+
+// Units returns the speed units of the fake speed source
+func (f *fakeSource) Units() string {
+	return "km/h"
+}
+
+// Updates sends the fake speeds to the updater
+func (f *fakeSource) Updates(ctx context.Context, updater Updater) error {
+
+	for _, speed := range f.speeds {
+		updater.UpdateSpeed(ctx, speed)
+	}
+
+	return nil
+}
+
+// TestRegisterSource tests that the controller consumes speeds from a registered speed source
+func TestRegisterSource(t *testing.T) {
+
+	controller := NewSpeedController(logger.BackgroundCtx, td.window)
+
+	if err := controller.Run(logger.BackgroundCtx); !errors.Is(err, errNoSpeedSource) {
+		t.Errorf("Run() without source error = %v, want %v", err, errNoSpeedSource)
+	}
+
+	controller.RegisterSource(&fakeSource{speeds: td.speeds})
+
+	if got := controller.SourceName(); got != "fake" {
+		t.Errorf("SourceName() = %q, want %q", got, "fake")
+	}
+
+	if got := controller.SourceUnits(); got != "km/h" {
+		t.Errorf("SourceUnits() = %q, want %q", got, "km/h")
+	}
+
+	if err := controller.Run(logger.BackgroundCtx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := controller.SmoothedSpeed(); got != td.expectedSpeed {
+		t.Errorf("SmoothedSpeed() = %v, want %v", got, td.expectedSpeed)
+	}
+
+}
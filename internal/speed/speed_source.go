@@ -0,0 +1,80 @@
+package speed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Error definitions
+var (
+	errNoSpeedSource = errors.New("no speed source registered")
+)
+
+// Updater receives speed measurements from a speed source (implemented by Controller)
+type Updater interface {
+	UpdateSpeed(ctx context.Context, speed float64)
+}
+
+// SpeedSource provides cycling speed measurements (e.g., from a BLE sensor, a simulator, or a
+// recorded session), decoupling the speed controller from any one kind of sensor
+type SpeedSource interface {
+	Name() string                                       // Name identifies the source (e.g., "BLE")
+	Units() string                                      // Units of the speeds the source provides
+	Updates(ctx context.Context, updater Updater) error // Updates sends speeds until ctx is canceled
+}
+
+// RegisterSource sets the speed source consumed by the controller when it runs
+func (sc *Controller) RegisterSource(source SpeedSource) {
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.source = source
+
+}
+
+// SourceName returns the name of the registered speed source, or an empty string if none
+func (sc *Controller) SourceName() string {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	if sc.source == nil {
+		return ""
+	}
+
+	return sc.source.Name()
+}
+
+// SourceUnits returns the speed units of the registered speed source, or an empty string if none
+func (sc *Controller) SourceUnits() string {
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	if sc.source == nil {
+		return ""
+	}
+
+	return sc.source.Units()
+}
+
+// Run consumes speed measurements from the registered speed source until the context is canceled
+// (or the source stops)
+func (sc *Controller) Run(ctx context.Context) error {
+
+	sc.mu.RLock()
+	source := sc.source
+	sc.mu.RUnlock()
+
+	if source == nil {
+		return errNoSpeedSource
+	}
+
+	if err := source.Updates(ctx, sc); err != nil {
+		return fmt.Errorf("%s speed source: %w", source.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+// Package units converts and formats units of speed, distance, and temperature for BLE Sync Cycle
+// (BSC)
+//
+// All conversions between units of speed go through a single set of exact conversion factors
+// (e.g., 1 mile = 1609.344 meters), so that BLE speed calculations, playback speed, ride totals,
+// and displayed values always agree.
+package units
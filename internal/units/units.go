@@ -0,0 +1,175 @@
+package units
+
+import "fmt"
+
+// Units of speed
+const (
+	KMH = "km/h"
+	MPH = "mph"
+	MPS = "m/s"
+	RPM = "rpm" // Wheel revolutions per minute
+)
+
+// Exact conversion factors (the international mile is defined as 1609.344 meters)
+const (
+	MetersPerMile       = 1609.344
+	MetersPerKilometer  = 1000.0
+	KilometersPerMile   = MetersPerMile / MetersPerKilometer
+	SecondsPerHour      = 3600.0
+	SecondsPerMinute    = 60.0
+	MillimetersPerMeter = 1000.0
+)
+
+// Converter converts speeds between a unit of speed and other units of speed
+type Converter struct {
+	units         string
+	metersPerHour float64 // Meters per hour at a speed of 1 (0 if the units are unknown)
+}
+
+// NewConverter creates a converter for speeds in the given units (wheel revolutions per minute
+// depend on the wheel circumference)
+func NewConverter(speedUnits string, wheelCircumferenceMM int) Converter {
+
+	c := Converter{units: speedUnits}
+
+	switch speedUnits {
+	case KMH:
+		c.metersPerHour = MetersPerKilometer
+	case MPH:
+		c.metersPerHour = MetersPerMile
+	case MPS:
+		c.metersPerHour = SecondsPerHour
+	case RPM:
+		c.metersPerHour = float64(wheelCircumferenceMM) / MillimetersPerMeter * SecondsPerHour / SecondsPerMinute
+	}
+
+	return c
+}
+
+// Units returns the units of speed of the converter
+func (c Converter) Units() string {
+	return c.units
+}
+
+// Valid returns true if the units of speed are known (and, for wheel revolutions per minute, the
+// wheel circumference is set)
+func (c Converter) Valid() bool {
+	return c.metersPerHour > 0
+}
+
+// MetersPerSecond converts a speed to meters per second
+func (c Converter) MetersPerSecond(speed float64) float64 {
+	return speed * c.metersPerHour / SecondsPerHour
+}
+
+// FromMetersPerSecond converts a speed in meters per second to the units of the converter
+func (c Converter) FromMetersPerSecond(mps float64) float64 {
+	return c.fromMetersPerHour(mps * SecondsPerHour)
+}
+
+// KilometersPerHour converts a speed to kilometers per hour
+func (c Converter) KilometersPerHour(speed float64) float64 {
+	return speed * c.metersPerHour / MetersPerKilometer
+}
+
+// FromKilometersPerHour converts a speed in kilometers per hour to the units of the converter
+func (c Converter) FromKilometersPerHour(kmh float64) float64 {
+	return c.fromMetersPerHour(kmh * MetersPerKilometer)
+}
+
+// MilesPerHour converts a speed to miles per hour
+func (c Converter) MilesPerHour(speed float64) float64 {
+	return speed * c.metersPerHour / MetersPerMile
+}
+
+// FromMilesPerHour converts a speed in miles per hour to the units of the converter
+func (c Converter) FromMilesPerHour(mph float64) float64 {
+	return c.fromMetersPerHour(mph * MetersPerMile)
+}
+
+// fromMetersPerHour converts a speed in meters per hour to the units of the converter (0 if the
+// units are unknown)
+func (c Converter) fromMetersPerHour(metersPerHour float64) float64 {
+
+	if !c.Valid() {
+		return 0
+	}
+
+	return metersPerHour / c.metersPerHour
+}
+
+// Imperial returns true if distances ridden in the given units of speed are shown in miles
+// (temperatures in degrees Fahrenheit)
+func Imperial(speedUnits string) bool {
+	return speedUnits == MPH
+}
+
+// KilometersToMiles converts kilometers (or kilometers per hour) to miles (or miles per hour)
+func KilometersToMiles(km float64) float64 {
+	return km / KilometersPerMile
+}
+
+// MilesToKilometers converts miles (or miles per hour) to kilometers (or kilometers per hour)
+func MilesToKilometers(miles float64) float64 {
+	return miles * KilometersPerMile
+}
+
+// Distance converts a distance in kilometers to the distance units used with the given units of
+// speed
+func Distance(km float64, speedUnits string) float64 {
+
+	if Imperial(speedUnits) {
+		return KilometersToMiles(km)
+	}
+
+	return km
+}
+
+// DistanceKM converts a distance in the distance units used with the given units of speed to
+// kilometers
+func DistanceKM(distance float64, speedUnits string) float64 {
+
+	if Imperial(speedUnits) {
+		return MilesToKilometers(distance)
+	}
+
+	return distance
+}
+
+// DistanceUnits returns the units used for distances ridden in the given units of speed
+func DistanceUnits(speedUnits string) string {
+
+	if Imperial(speedUnits) {
+		return "mi"
+	}
+
+	return "km"
+}
+
+// FormatSpeed formats a speed for display in the given units of speed (wheel revolutions per
+// minute are displayed as whole numbers)
+func FormatSpeed(speed float64, speedUnits string) string {
+
+	if speedUnits == RPM {
+		return fmt.Sprintf("%.0f", speed)
+	}
+
+	return fmt.Sprintf("%.1f", speed)
+}
+
+// FormatDistance formats a distance in kilometers for display in the distance units used with the
+// given units of speed (e.g., "12.4 mi")
+func FormatDistance(km float64, speedUnits string) string {
+	return fmt.Sprintf("%.1f %s", Distance(km, speedUnits), DistanceUnits(speedUnits))
+}
+
+// FormatTemperature formats a temperature in degrees Celsius for display in degrees Fahrenheit
+// with imperial units of speed, or in degrees Celsius otherwise (e.g., "21.5 °C")
+func FormatTemperature(celsius float64, speedUnits string) string {
+
+	if Imperial(speedUnits) {
+		return fmt.Sprintf("%.1f °F", celsius*9/5+32)
+	}
+
+	return fmt.Sprintf("%.1f °C", celsius)
+}
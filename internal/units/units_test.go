@@ -0,0 +1,141 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+// Tolerance of floating-point speed comparisons
+const tolerance = 1e-9
+
+// TestConverter tests converting speeds between units of speed
+func TestConverter(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		speedUnits string
+		speed      float64
+		wantMPS    float64
+		wantKMH    float64
+		wantMPH    float64
+	}{
+		{KMH, 36, 10, 36, 36 / KilometersPerMile},
+		{MPH, 10, 4.4704, 16.09344, 10},
+		{MPS, 5, 5, 18, 18 / KilometersPerMile},
+		{RPM, 120, 4, 14.4, 14.4 / KilometersPerMile}, // 2000 mm wheel circumference
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.speedUnits, func(t *testing.T) {
+
+			c := NewConverter(tt.speedUnits, 2000)
+
+			if !c.Valid() || c.Units() != tt.speedUnits {
+				t.Fatalf("NewConverter(%q) = %+v, want a valid converter", tt.speedUnits, c)
+			}
+
+			if got := c.MetersPerSecond(tt.speed); math.Abs(got-tt.wantMPS) > tolerance {
+				t.Errorf("MetersPerSecond(%v) = %v, want %v", tt.speed, got, tt.wantMPS)
+			}
+
+			if got := c.KilometersPerHour(tt.speed); math.Abs(got-tt.wantKMH) > tolerance {
+				t.Errorf("KilometersPerHour(%v) = %v, want %v", tt.speed, got, tt.wantKMH)
+			}
+
+			if got := c.MilesPerHour(tt.speed); math.Abs(got-tt.wantMPH) > tolerance {
+				t.Errorf("MilesPerHour(%v) = %v, want %v", tt.speed, got, tt.wantMPH)
+			}
+
+			// Conversions back to the units of the converter round-trip
+			for name, got := range map[string]float64{
+				"FromMetersPerSecond":   c.FromMetersPerSecond(tt.wantMPS),
+				"FromKilometersPerHour": c.FromKilometersPerHour(tt.wantKMH),
+				"FromMilesPerHour":      c.FromMilesPerHour(tt.wantMPH),
+			} {
+
+				if math.Abs(got-tt.speed) > tolerance {
+					t.Errorf("%s() = %v, want %v", name, got, tt.speed)
+				}
+			}
+
+		})
+	}
+
+	// Unknown units (or wheel revolutions without a wheel circumference) convert nothing
+	for _, c := range []Converter{NewConverter("knots", 2000), NewConverter(RPM, 0)} {
+
+		if c.Valid() || c.FromMetersPerSecond(10) != 0 {
+			t.Errorf("converter %+v is valid, want an invalid converter", c)
+		}
+	}
+
+}
+
+// TestDistance tests converting and formatting distances and temperatures for units of speed
+func TestDistance(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		speedUnits      string
+		km              float64
+		wantDistance    string
+		wantTemperature string
+	}{
+		{MPH, 2 * KilometersPerMile, "2.0 mi", "68.0 °F"},
+		{KMH, 12.34, "12.3 km", "20.0 °C"},
+		{MPS, 5, "5.0 km", "20.0 °C"},
+		{RPM, 5, "5.0 km", "20.0 °C"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.speedUnits, func(t *testing.T) {
+
+			if got := FormatDistance(tt.km, tt.speedUnits); got != tt.wantDistance {
+				t.Errorf("FormatDistance() = %v, want %v", got, tt.wantDistance)
+			}
+
+			if got := DistanceKM(Distance(tt.km, tt.speedUnits), tt.speedUnits); math.Abs(got-tt.km) > tolerance {
+				t.Errorf("DistanceKM(Distance(%v)) = %v, want %v", tt.km, got, tt.km)
+			}
+
+			if got := FormatTemperature(20, tt.speedUnits); got != tt.wantTemperature {
+				t.Errorf("FormatTemperature() = %v, want %v", got, tt.wantTemperature)
+			}
+
+		})
+	}
+
+}
+
+// TestFormatSpeed tests the FormatSpeed function
+func TestFormatSpeed(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		speedUnits string
+		speed      float64
+		want       string
+	}{
+		{MPH, 12.345, "12.3"},
+		{KMH, 20.06, "20.1"},
+		{MPS, 5.55, "5.5"},
+		{RPM, 128.6, "129"},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.speedUnits, func(t *testing.T) {
+
+			if got := FormatSpeed(tt.speed, tt.speedUnits); got != tt.want {
+				t.Errorf("FormatSpeed() = %v, want %v", got, tt.want)
+			}
+
+		})
+	}
+
+}
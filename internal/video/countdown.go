@@ -0,0 +1,79 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Duration of each OSD message of the start countdown (slightly longer than a second, so the
+// messages don't flicker between updates)
+const countdownMessageDurationMs = 1200
+
+// startCountdown starts the countdown (if configured) held before video playback can start,
+// giving the rider time to clip in
+func (p *PlaybackController) startCountdown() {
+
+	if p.videoConfig.StartCountdownSecs <= 0 {
+		return
+	}
+
+	ends := time.Now().Add(time.Duration(p.videoConfig.StartCountdownSecs) * time.Second)
+	p.countdown.ends.Store(ends.UnixNano())
+
+}
+
+// CountdownRemaining returns the time remaining in the start countdown (zero if there's no
+// countdown, or once it completes)
+func (p *PlaybackController) CountdownRemaining() time.Duration {
+
+	ends := p.countdown.ends.Load()
+	if ends == 0 {
+		return 0
+	}
+
+	return max(time.Until(time.Unix(0, ends)), 0)
+}
+
+// updateCountdown holds video playback (paused) while the start countdown runs, showing the
+// seconds remaining on the OSD, and returns true until the countdown completes
+func (p *PlaybackController) updateCountdown(ctx context.Context) bool {
+
+	if p.countdown.ends.Load() == 0 {
+		return false
+	}
+
+	remaining := p.CountdownRemaining()
+
+	if remaining <= 0 {
+		p.countdown.ends.Store(0)
+		logger.Debug(ctx, logger.VIDEO, "start countdown completed")
+		p.reportEvent("Countdown complete: start pedaling")
+
+		if err := p.player.showOSDMessage("Go!", countdownMessageDurationMs); err != nil {
+			logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to show countdown message: %v", err))
+		}
+
+		return false
+	}
+
+	if err := p.applyState(playerState{paused: true}); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to pause video during countdown: %v", err))
+	}
+
+	// Show each second of the countdown once
+	seconds := int((remaining + time.Second - 1) / time.Second)
+	if seconds == p.countdown.shown {
+		return true
+	}
+
+	p.countdown.shown = seconds
+
+	if err := p.player.showOSDMessage(fmt.Sprintf("Starting in %d…", seconds), countdownMessageDurationMs); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to show countdown message: %v", err))
+	}
+
+	return true
+}
@@ -3,6 +3,7 @@ package video
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/diamondburned/gotk4/pkg/gdk/v4"
@@ -12,7 +13,19 @@ import (
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 )
 
-// ValidateDisplay checks the requested display name against active monitors.
+// DisplayIndex reports whether the requested display is a monitor index (e.g., "1") rather than
+// a connector name, returning the parsed index
+func DisplayIndex(requestedName string) (uint, bool) {
+
+	index, err := strconv.ParseUint(strings.TrimSpace(requestedName), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint(index), true
+}
+
+// ValidateDisplay checks the requested display (by connector name or monitor index) against active monitors.
 func ValidateDisplay(ctx context.Context, requestedName string) config.DisplayValidationResult {
 	requestedName = strings.TrimSpace(requestedName)
 	result := config.DisplayValidationResult{
@@ -37,6 +50,7 @@ func ValidateDisplay(ctx context.Context, requestedName string) config.DisplayVa
 // findDisplayMonitor iterates over available monitors to find a match
 func findDisplayMonitor(ctx context.Context, requestedName string, monitors *gio.ListModel) config.DisplayValidationResult {
 	count := monitors.NItems()
+	requestedIndex, byIndex := DisplayIndex(requestedName)
 	var available []string
 
 	for i := range count {
@@ -58,6 +72,11 @@ func findDisplayMonitor(ctx context.Context, requestedName string, monitors *gio
 		if connector == requestedName {
 			return processMatchedDisplay(ctx, requestedName, i)
 		}
+
+		// Resolve a monitor index to its connector name so the media player can target it
+		if byIndex && i == requestedIndex && connector != "" {
+			return processMatchedDisplay(ctx, connector, i)
+		}
 	}
 
 	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("target display '%s' not found among available monitors %v — falling back to default", requestedName, available))
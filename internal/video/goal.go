@@ -0,0 +1,77 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Duration of the OSD message shown when the session goal is reached
+const goalMessageDurationMs = 5000
+
+// goalState holds the goal tracker of the session and the time of its last update
+type goalState struct {
+	tracker *speed.GoalTracker
+	updated time.Time
+}
+
+// SetGoalTracker sets the tracker that accumulates the ride totals of the session and its progress
+// toward the session goal, updated with each playback speed update
+func (p *PlaybackController) SetGoalTracker(tracker *speed.GoalTracker) {
+	p.goal.tracker = tracker
+}
+
+// updateGoal adds the ride since the last update to the goal tracker (ride time isn't counted
+// while playback is paused), announcing the session goal when it's reached
+func (p *PlaybackController) updateGoal(ctx context.Context) {
+
+	if p.goal.tracker == nil {
+		return
+	}
+
+	now := p.now()
+	last := p.goal.updated
+	p.goal.updated = now
+
+	if last.IsZero() || p.paused.Load() {
+		return
+	}
+
+	if !p.goal.tracker.Update(p.speedState.current, now.Sub(last)) {
+		return
+	}
+
+	progress := p.goal.tracker.Progress()
+	description := progress.Goal.Describe(p.speedConfig.SpeedUnits)
+
+	logger.Info(ctx, logger.VIDEO, "session goal reached: "+description)
+	p.reportEvent("Goal reached: " + description)
+
+	if err := p.player.showOSDMessage("Goal reached: "+description, goalMessageDurationMs); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to show goal reached message: %v", err))
+	}
+
+}
+
+// goalOSDText returns the OSD line showing the progress toward the session goal (empty if the
+// session has no goal)
+func (p *PlaybackController) goalOSDText() string {
+
+	if p.goal.tracker == nil {
+		return ""
+	}
+
+	progress := p.goal.tracker.Progress()
+	if !progress.Goal.Enabled() {
+		return ""
+	}
+
+	if progress.Reached {
+		return fmt.Sprintf("Goal Reached: %s\n", progress)
+	}
+
+	return fmt.Sprintf("Goal: %s\n", progress)
+}
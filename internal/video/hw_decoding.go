@@ -0,0 +1,75 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// hwDecoders captures the hardware decoding APIs detected on the host
+type hwDecoders struct {
+	vaapi bool
+	nvdec bool
+}
+
+// mpvHWDecValues maps the configured hardware decoding option to its mpv "hwdec" value
+var mpvHWDecValues = map[string]string{
+	config.HWDecAuto:  "auto-safe",
+	config.HWDecVAAPI: "vaapi",
+	config.HWDecNVDEC: "nvdec",
+	config.HWDecOff:   "no",
+}
+
+// detectHWDecoders probes the host for device nodes used by the supported hardware decoding APIs
+func detectHWDecoders() hwDecoders {
+
+	renderNodes, _ := filepath.Glob("/dev/dri/renderD*")
+	_, nvidiaErr := os.Stat("/proc/driver/nvidia/version")
+
+	return hwDecoders{
+		vaapi: len(renderNodes) > 0,
+		nvdec: nvidiaErr == nil,
+	}
+}
+
+// resolveHWDec returns the mpv "hwdec" value for the requested option, falling back to automatic
+// (safe) selection if the requested decoding API was not detected on the host
+func resolveHWDec(requested string, available hwDecoders) (string, bool) {
+
+	switch requested {
+	case config.HWDecVAAPI:
+
+		if !available.vaapi {
+			return mpvHWDecValues[config.HWDecAuto], true
+		}
+
+	case config.HWDecNVDEC:
+
+		if !available.nvdec {
+			return mpvHWDecValues[config.HWDecAuto], true
+		}
+
+	case "":
+		return mpvHWDecValues[config.HWDecOff], false
+	}
+
+	return mpvHWDecValues[requested], false
+}
+
+// hardwareDecodingOption detects host decoding capabilities and returns the mpv "hwdec" value to use
+func hardwareDecodingOption(ctx context.Context, requested string) string {
+
+	available := detectHWDecoders()
+	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("hardware decoding support detected: vaapi=%t, nvdec=%t", available.vaapi, available.nvdec))
+
+	value, fellBack := resolveHWDec(requested, available)
+	if fellBack {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("requested hardware decoding (%s) not detected on this host: falling back to %s", requested, value))
+	}
+
+	return value
+}
@@ -0,0 +1,39 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// TestResolveHWDec tests the resolveHWDec function
+func TestResolveHWDec(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		requested    string
+		available    hwDecoders
+		wantValue    string
+		wantFallback bool
+	}{
+		{"auto", config.HWDecAuto, hwDecoders{}, "auto-safe", false},
+		{"off", config.HWDecOff, hwDecoders{vaapi: true}, "no", false},
+		{"vaapi available", config.HWDecVAAPI, hwDecoders{vaapi: true}, "vaapi", false},
+		{"vaapi unavailable", config.HWDecVAAPI, hwDecoders{nvdec: true}, "auto-safe", true},
+		{"nvdec available", config.HWDecNVDEC, hwDecoders{nvdec: true}, "nvdec", false},
+		{"nvdec unavailable", config.HWDecNVDEC, hwDecoders{vaapi: true}, "auto-safe", true},
+		{"unset", "", hwDecoders{}, "no", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			value, fellBack := resolveHWDec(tt.requested, tt.available)
+			if value != tt.wantValue || fellBack != tt.wantFallback {
+				t.Errorf("resolveHWDec(%q) = (%q, %v), want (%q, %v)", tt.requested, value, fellBack, tt.wantValue, tt.wantFallback)
+			}
+
+		})
+	}
+
+}
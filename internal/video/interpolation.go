@@ -0,0 +1,100 @@
+package video
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Number of frames dropped while frame interpolation is enabled before warning that the GPU may
+// be too weak for frame interpolation
+const maxInterpolationDrops = 100
+
+// interpolationState holds the state of frame interpolation, which is enabled while playback runs
+// slower than the configured frame interpolation speed
+type interpolationState struct {
+	available    bool  // Frame interpolation is configured and supported by the media player
+	active       bool  // Frame interpolation is currently enabled
+	dropsAtStart int64 // Dropped frame count when frame interpolation was last enabled
+	warned       bool  // A weak GPU warning has been logged
+}
+
+// configureInterpolation prepares the media player for frame interpolation (if configured), after
+// checking that the media player video output supports it. Frame interpolation is an
+// enhancement, so failures are logged rather than returned
+func (p *PlaybackController) configureInterpolation(ctx context.Context) {
+
+	if p.videoConfig.InterpolationSpeed <= 0 {
+		return
+	}
+
+	if p.videoConfig.LowPower {
+		logger.Info(ctx, logger.VIDEO, "low-power profile: frame interpolation disabled")
+
+		return
+	}
+
+	if !p.player.supportsInterpolation() {
+		logger.Warn(ctx, logger.VIDEO, "frame interpolation requires a GPU video output, which the media player isn't using: frame interpolation disabled")
+
+		return
+	}
+
+	if err := p.player.configureInterpolation(); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to configure frame interpolation: %v", err))
+
+		return
+	}
+
+	p.interpolation.available = true
+	logger.Info(ctx, logger.VIDEO, fmt.Sprintf("frame interpolation enabled below %.2fx playback speed", p.videoConfig.InterpolationSpeed))
+}
+
+// updateInterpolation enables frame interpolation while playback runs slower than the configured
+// frame interpolation speed, and disables it otherwise
+func (p *PlaybackController) updateInterpolation(ctx context.Context, playbackSpeed float64) {
+
+	if !p.interpolation.available {
+		return
+	}
+
+	enable := playbackSpeed > 0 && playbackSpeed < p.videoConfig.InterpolationSpeed
+	if enable == p.interpolation.active {
+		return
+	}
+
+	if err := p.player.setInterpolation(enable); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to set frame interpolation: %v", err))
+
+		return
+	}
+
+	p.interpolation.active = enable
+
+	if enable {
+		p.interpolation.dropsAtStart, _ = p.player.droppedFrames()
+	}
+
+	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("frame interpolation active: %t (playback speed %.2fx)", enable, playbackSpeed))
+}
+
+// checkInterpolationDrops warns (once) when the media player drops frames while frame
+// interpolation is enabled, which suggests the GPU is too weak for frame interpolation
+func (p *PlaybackController) checkInterpolationDrops(ctx context.Context) {
+
+	if !p.interpolation.active || p.interpolation.warned {
+		return
+	}
+
+	drops, err := p.player.droppedFrames()
+	if err != nil {
+		return
+	}
+
+	if dropped := drops - p.interpolation.dropsAtStart; dropped > maxInterpolationDrops {
+		p.interpolation.warned = true
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("%d video frames dropped with frame interpolation enabled: the GPU may be too weak for frame interpolation (consider setting frame_interpolation_speed to 0.00)", dropped))
+	}
+
+}
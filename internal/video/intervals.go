@@ -0,0 +1,83 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Duration of the OSD message shown at each interval transition
+const intervalMessageDurationMs = 2000
+
+// intervalColors maps interval timer phases to the color of their OSD countdown
+var intervalColors = map[string]string{
+	speed.IntervalHard: "#e01b24",
+	speed.IntervalEasy: "#33d17a",
+}
+
+// intervalState holds the interval timer of the session, the handler notified at each interval
+// transition (e.g., to sound a beep), and the time of its last update
+type intervalState struct {
+	timer   *speed.IntervalTimer
+	handler func(state speed.IntervalState)
+	updated time.Time
+}
+
+// SetIntervalTimer sets the interval timer counted down with each playback speed update, and the
+// handler notified at each interval transition
+func (p *PlaybackController) SetIntervalTimer(timer *speed.IntervalTimer, handler func(state speed.IntervalState)) {
+	p.intervals.timer = timer
+	p.intervals.handler = handler
+}
+
+// updateIntervals counts down the interval timer while riding (the timer holds while playback is
+// paused or stopped), announcing each interval transition
+func (p *PlaybackController) updateIntervals(ctx context.Context) {
+
+	if p.intervals.timer == nil || !p.intervals.timer.Enabled() {
+		return
+	}
+
+	now := p.now()
+	last := p.intervals.updated
+	p.intervals.updated = now
+
+	if last.IsZero() || p.paused.Load() || p.speedState.current <= 0 {
+		return
+	}
+
+	if !p.intervals.timer.Update(now.Sub(last)) {
+		return
+	}
+
+	state := p.intervals.timer.State()
+	message := fmt.Sprintf("Interval %d: %s", state.Count, state.Name())
+
+	logger.Debug(ctx, logger.VIDEO, "interval timer transition: "+message)
+	p.reportEvent(message)
+
+	if err := p.player.showOSDMessage(p.player.styleOSDText(message, intervalColors[state.Phase]), intervalMessageDurationMs); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to show interval message: %v", err))
+	}
+
+	if p.intervals.handler != nil {
+		p.intervals.handler(state)
+	}
+
+}
+
+// intervalOSDText returns the OSD line counting down the current interval, colored by interval
+// phase (empty if the session has no interval timer)
+func (p *PlaybackController) intervalOSDText() string {
+
+	if p.intervals.timer == nil || !p.intervals.timer.Enabled() {
+		return ""
+	}
+
+	state := p.intervals.timer.State()
+
+	return p.player.styleOSDText("Interval: "+state.String(), intervalColors[state.Phase]) + "\n"
+}
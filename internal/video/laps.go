@@ -0,0 +1,54 @@
+package video
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Duration of the OSD message showing the stats of the last lap
+const lapMessageDurationMs = 5000
+
+// lapState holds the lap tracker of the session and the handler notified at each lap (e.g., to
+// run a hook command)
+type lapState struct {
+	tracker *speed.LapTracker
+	handler func(lap speed.Lap)
+}
+
+// SetLapTracker sets the lap tracker checked for the end of a lap with each playback speed update,
+// and the handler notified at each lap
+func (p *PlaybackController) SetLapTracker(tracker *speed.LapTracker, handler func(lap speed.Lap)) {
+	p.laps.tracker = tracker
+	p.laps.handler = handler
+}
+
+// updateLaps marks a lap (automatically, or when requested by the rider) from the ride totals of
+// the goal tracker, briefly showing the stats of the lap on the OSD
+func (p *PlaybackController) updateLaps(ctx context.Context) {
+
+	if p.laps.tracker == nil || p.goal.tracker == nil {
+		return
+	}
+
+	lap, ok := p.laps.tracker.Update(p.goal.tracker.Progress())
+	if !ok {
+		return
+	}
+
+	message := lap.Describe(p.speedConfig.SpeedUnits)
+
+	logger.Info(ctx, logger.VIDEO, "lap marked: "+message)
+	p.reportEvent(message)
+
+	if err := p.player.showOSDMessage(message, lapMessageDurationMs); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to show lap message: %v", err))
+	}
+
+	if p.laps.handler != nil {
+		p.laps.handler(lap)
+	}
+
+}
@@ -0,0 +1,76 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Interval between latency reports while the session runs
+const latencyReportInterval = time.Minute
+
+// latencyState holds the measured latency from speed measurements (e.g., BLE notifications) to
+// the media player speed changes they cause
+type latencyState struct {
+	recorder *speed.LatencyRecorder // nil unless latency is measured
+	from     time.Time              // Speed measurement of the last latency sample recorded
+	reported time.Time
+}
+
+// newLatencyState creates the latency measurement state, measuring latency only if enabled
+func newLatencyState(enabled bool) latencyState {
+
+	if !enabled {
+		return latencyState{}
+	}
+
+	return latencyState{recorder: speed.NewLatencyRecorder(), reported: time.Now()}
+}
+
+// recordLatency records the latency from the speed measurement last read from the speed
+// controller to the playback speed change just sent to the media player (once per measurement)
+func (p *PlaybackController) recordLatency() {
+
+	if p.latency.recorder == nil || !p.speedState.updated.After(p.latency.from) {
+		return
+	}
+
+	p.latency.from = p.speedState.updated
+	p.latency.recorder.Record(time.Since(p.speedState.updated))
+
+}
+
+// updateLatencyReport reports the measured latency once every report interval
+func (p *PlaybackController) updateLatencyReport(ctx context.Context) {
+
+	if p.latency.recorder == nil || time.Since(p.latency.reported) < latencyReportInterval {
+		return
+	}
+
+	p.reportLatency(ctx)
+
+}
+
+// reportLatency logs the percentiles of the measured latency, compared to update_interval_secs
+// (the interval at which speed measurements are read from the speed controller)
+func (p *PlaybackController) reportLatency(ctx context.Context) {
+
+	if p.latency.recorder == nil {
+		return
+	}
+
+	p.latency.reported = time.Now()
+
+	stats := p.latency.recorder.Stats()
+	if stats.Samples == 0 {
+		logger.Info(ctx, logger.VIDEO, "speed update latency: no playback speed changes measured yet")
+
+		return
+	}
+
+	logger.Info(ctx, logger.VIDEO, fmt.Sprintf("speed update latency (%d samples): %s (update_interval_secs = %.2f)", stats.Samples, stats, p.videoConfig.UpdateIntervalSec))
+
+}
@@ -0,0 +1,39 @@
+package video
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Low-power profile settings
+const (
+	lowPowerOSDInterval = 1 * time.Second
+)
+
+// applyLowPowerProfile adjusts the OSD configuration to reduce rendering and polling overhead
+func applyLowPowerProfile(ctx context.Context, osd *osdConfig) {
+
+	osd.updateInterval = max(osd.updateInterval, lowPowerOSDInterval)
+
+	// Time remaining requires querying the media player on every update, so disable it
+	if osd.displayTimeRemaining {
+		osd.displayTimeRemaining = false
+		logger.Debug(ctx, logger.VIDEO, "low-power profile: time remaining display disabled")
+	}
+
+	// The metrics overlay is redrawn on every update, so disable it
+	if osd.displayMetricsOverlay {
+		osd.displayMetricsOverlay = false
+		logger.Debug(ctx, logger.VIDEO, "low-power profile: metrics overlay disabled")
+	}
+
+	logger.Info(ctx, logger.VIDEO, "low-power profile enabled")
+}
+
+// hasDisplayServer reports whether a Wayland or X11 display server is available to the session
+func hasDisplayServer() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") != ""
+}
@@ -6,6 +6,9 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
 )
 
 // Error definitions
@@ -24,7 +27,7 @@ var (
 	errFailedToValidateVideo     = errors.New("failed to validate video file")
 	errFailedToLoadVideo         = errors.New("failed to load video")
 	errUnableToSeek              = errors.New("failed to seek to specified position in media player")
-	ErrSeekExceedsDuration       = errors.New("seek position exceeds video file duration")
+	ErrSeekExceedsDuration       = apperr.New(apperr.CodeVideoSeek, "seek position exceeds video file duration")
 
 	//
 	ErrVideoComplete = errors.New("video playback completed")
@@ -56,17 +59,37 @@ type playerEvent struct {
 	id eventID
 }
 
+// playerState holds the playback state applied to the media player in a single update
+type playerState struct {
+	speed       float64 // Playback speed (0 = leave the playback speed unchanged)
+	paused      bool
+	updatePause bool // Set the pause state to paused
+	osdText     string
+	updateOSD   bool // Replace the OSD text with osdText
+}
+
 // osdConfig manages the configuration for the On-Screen Display (OSD)
 type osdConfig struct {
-	fontSize             int
-	marginX              int
-	marginY              int
-	alignX               string
-	alignY               string
-	showOSD              bool
-	displayCycleSpeed    bool
-	displayPlaybackSpeed bool
-	displayTimeRemaining bool
+	fontSize              int
+	marginX               int
+	marginY               int
+	alignX                string
+	alignY                string
+	showOSD               bool
+	displayCycleSpeed     bool
+	displayPlaybackSpeed  bool
+	displayTimeRemaining  bool
+	displaySpeedStats     bool
+	displayMetricsOverlay bool
+	updateInterval        time.Duration // Minimum time between OSD refreshes (0 = refresh on every update)
+}
+
+// audioConfig manages the configuration for media player audio playback
+type audioConfig struct {
+	volume          int
+	mute            bool
+	track           int
+	pitchCorrection bool
 }
 
 // mediaPlayer defines the interface abstraction for a video player
@@ -77,22 +100,44 @@ type mediaPlayer interface {
 	loadFile(path string) error
 	setSpeed(speed float64) error
 	setPause(paused bool) error
+	applyState(state playerState) error // Applies speed, pause, and OSD text as a single update
 	timeRemaining() (int64, error)
 	playbackPosition() (int64, error)
+	duration() (int64, error)
 	terminatePlayer()
 
 	// Configuration methods
 	setPlaybackSize(windowSize float64) error
+	setWindowPosition(x, y int) error
 	setKeepOpen(keepOpen bool) error // Used by mpv to prevent application exit on video EOF
 	seek(position string) error
+	seekTo(seconds float64) error // Seeks to an absolute position during playback
 	setOSD(options osdConfig) error
+	setSubtitles(path string, visible bool) error
+
+	// Frame interpolation methods
+	supportsInterpolation() bool   // Reports whether the video output supports frame interpolation
+	configureInterpolation() error // Prepares (but doesn't enable) frame interpolation
+	setInterpolation(enabled bool) error
+	droppedFrames() (int64, error) // Number of video frames dropped by the video output
+
+	// Audio methods
+	setAudioTrack(track int) error
+	setVolume(volume int) error
+	setMute(muted bool) error
+	setPitchCorrection(enabled bool) error // Keeps the audio pitch natural at non-normal playback speeds
+	setAudioGain(gain float64) error       // Scales the volume (0.0-1.0) on top of setVolume (e.g., to fade audio in)
 
 	// Event handling methods
 	setupEvents() error
-	waitEvent(timeout float64) *playerEvent
+	events() <-chan playerEvent
 
 	// On Screen Display (OSD) methods
 	showOSDText(text string) error
+	showOSDMessage(text string, durationMs int) error
+	showOverlay(assText string) error       // Draws ASS-formatted graphics over the video
+	styleOSDText(text, color string) string // Colors OSD text ("#RRGGBB") where the back-end allows it
+	setOSDColor(color string) error         // Sets the default color ("#RRGGBB") of OSD text
 }
 
 // wrapError helper function adds return context only if an error occurred
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
 )
 
 // playerFactory is a function type that creates a media player instance
@@ -95,6 +97,14 @@ func testPlayerPlaybackControls(t *testing.T, factory playerFactory) {
 
 	})
 
+	t.Run("applyState", func(t *testing.T) {
+
+		if err := player.applyState(playerState{speed: 1.2, paused: false, updatePause: true, osdText: "Playback Speed: 1.20x", updateOSD: true}); err != nil {
+			t.Errorf("applyState() error = %v", err)
+		}
+
+	})
+
 	t.Run("seek", func(t *testing.T) {
 
 		if err := player.seek("10"); err != nil {
@@ -121,6 +131,14 @@ func testPlayerConfiguration(t *testing.T, factory playerFactory, playerName str
 
 	})
 
+	t.Run("setWindowPosition", func(t *testing.T) {
+
+		if err := player.setWindowPosition(100, 50); err != nil {
+			t.Errorf("setWindowPosition(100, 50) error = %v", err)
+		}
+
+	})
+
 	t.Run("setKeepOpen", func(t *testing.T) {
 		err := player.setKeepOpen(true)
 
@@ -139,6 +157,46 @@ func testPlayerConfiguration(t *testing.T, factory playerFactory, playerName str
 
 	})
 
+	t.Run("setSubtitles", func(t *testing.T) {
+
+		if err := player.setSubtitles("", false); err != nil {
+			t.Errorf("setSubtitles(\"\", false) error = %v", err)
+		}
+
+	})
+
+	t.Run("setVolume", func(t *testing.T) {
+
+		if err := player.setVolume(50); err != nil {
+			t.Errorf("setVolume(50) error = %v", err)
+		}
+
+	})
+
+	t.Run("setMute", func(t *testing.T) {
+
+		if err := player.setMute(true); err != nil {
+			t.Errorf("setMute(true) error = %v", err)
+		}
+
+	})
+
+	t.Run("setPitchCorrection", func(t *testing.T) {
+
+		if err := player.setPitchCorrection(true); err != nil {
+			t.Errorf("setPitchCorrection(true) error = %v", err)
+		}
+
+	})
+
+	t.Run("setAudioGain", func(t *testing.T) {
+
+		if err := player.setAudioGain(0.5); err != nil {
+			t.Errorf("setAudioGain(0.5) error = %v", err)
+		}
+
+	})
+
 	t.Run("showOSDText", func(t *testing.T) {
 
 		if err := player.showOSDText("Hello " + playerName); err != nil {
@@ -147,4 +205,30 @@ func testPlayerConfiguration(t *testing.T, factory playerFactory, playerName str
 
 	})
 
+	t.Run("styleOSDText", func(t *testing.T) {
+
+		if err := player.showOSDText(player.styleOSDText("Cycle Speed: 12.0 mph", config.SpeedZoneColors[0])); err != nil {
+			t.Errorf("showOSDText() with styled text error = %v", err)
+		}
+
+	})
+
+	t.Run("setOSDColor", func(t *testing.T) {
+
+		if err := player.setOSDColor(nightOSDColor(40)); err != nil {
+			t.Errorf("setOSDColor() error = %v", err)
+		}
+
+	})
+
+	t.Run("showOverlay", func(t *testing.T) {
+
+		overlay := metricsOverlay{history: []float64{10.0, 12.0}}
+
+		if err := player.showOverlay(overlay.render(config.SpeedUnitsMPH)); err != nil {
+			t.Errorf("showOverlay() error = %v", err)
+		}
+
+	})
+
 }
@@ -0,0 +1,123 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Metrics overlay layout (in overlay coordinates, scaled by the media player to the window size)
+const (
+	overlayResX         = 1280
+	overlayResY         = 720
+	overlayHistoryLen   = 60 // Number of speed samples plotted in the speed graph
+	overlayPanelWidth   = 300
+	overlayPanelHeight  = 110
+	overlayPanelMargin  = 20
+	overlayGraphPadding = 10
+	overlayTextHeight   = 30
+)
+
+// metricsOverlay holds the recent speed history rendered in the metrics overlay panel
+type metricsOverlay struct {
+	history []float64
+}
+
+// add appends a speed to the speed history, discarding the oldest speed when full
+func (o *metricsOverlay) add(speed float64) {
+
+	o.history = append(o.history, speed)
+
+	if len(o.history) > overlayHistoryLen {
+		o.history = o.history[len(o.history)-overlayHistoryLen:]
+	}
+
+}
+
+// render returns the metrics overlay panel as ASS (Advanced SubStation Alpha) events: a
+// semi-transparent panel with the current speed and a sparkline graph of the recent speed history
+func (o *metricsOverlay) render(speedUnits string) string {
+
+	x := overlayResX - overlayPanelWidth - overlayPanelMargin
+	y := overlayPanelMargin
+
+	var ass strings.Builder
+
+	// Semi-transparent panel
+	fmt.Fprintf(&ass, "{\\an7\\pos(%d,%d)\\bord0\\shad0\\1c&H000000&\\1a&H60&\\p1}m 0 0 l %d 0 %d %d 0 %d{\\p0}\n",
+		x, y, overlayPanelWidth, overlayPanelWidth, overlayPanelHeight, overlayPanelHeight)
+
+	// Current speed
+	current := 0.0
+	if len(o.history) > 0 {
+		current = o.history[len(o.history)-1]
+	}
+
+	fmt.Fprintf(&ass, "{\\an7\\pos(%d,%d)\\fs24\\bord0\\shad0\\1c&HFFFFFF&}Cycle Speed: %s %s\n",
+		x+overlayGraphPadding, y+overlayGraphPadding/2, units.FormatSpeed(current, speedUnits), speedUnits)
+
+	// Speed graph
+	if path := o.sparkline(); path != "" {
+		fmt.Fprintf(&ass, "{\\an7\\pos(%d,%d)\\bord2\\shad0\\1a&HFF&\\3c&H00D7FF&\\p1}%s{\\p0}\n",
+			x+overlayGraphPadding, y+overlayTextHeight+overlayGraphPadding, path)
+	}
+
+	return ass.String()
+}
+
+// sparkline returns an ASS drawing of the speed history as a line graph (relative to the graph
+// origin), or an empty string if there are too few speeds to plot
+func (o *metricsOverlay) sparkline() string {
+
+	if len(o.history) < 2 {
+		return ""
+	}
+
+	width := float64(overlayPanelWidth - 2*overlayGraphPadding)
+	height := float64(overlayPanelHeight - overlayTextHeight - 2*overlayGraphPadding)
+	step := width / float64(overlayHistoryLen-1)
+
+	maxSpeed := 0.0
+	for _, speed := range o.history {
+		maxSpeed = max(maxSpeed, speed)
+	}
+
+	if maxSpeed == 0 {
+		maxSpeed = 1
+	}
+
+	points := make([]string, len(o.history))
+	offset := overlayHistoryLen - len(o.history) // Right-align a partial history
+
+	for i, speed := range o.history {
+		points[i] = fmt.Sprintf("%.0f %.0f", float64(offset+i)*step, height-(speed/maxSpeed)*height)
+	}
+
+	// Trace the line forward and back again so that the (closed) drawing has no fill area
+	path := make([]string, 0, 2*len(points)-1)
+	path = append(path, points...)
+
+	for i := len(points) - 2; i >= 0; i-- {
+		path = append(path, points[i])
+	}
+
+	return "m " + path[0] + " l " + strings.Join(path[1:], " ")
+}
+
+// updateMetricsOverlay adds the current speed to the metrics overlay and redraws it
+func (p *PlaybackController) updateMetricsOverlay(ctx context.Context) {
+
+	if !p.osdConfig.displayMetricsOverlay || p.paused.Load() {
+		return
+	}
+
+	p.overlay.add(p.speedState.current)
+
+	if err := p.player.showOverlay(p.overlay.render(p.speedConfig.SpeedUnits)); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to update metrics overlay: %v", err))
+	}
+
+}
@@ -15,8 +15,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	mpv "github.com/gen2brain/go-mpv"
@@ -26,23 +30,35 @@ import (
 
 // mpvPlayer is a wrapper around the go-mpv client
 type mpvPlayer struct {
-	player *mpv.Mpv
-	mu     sync.RWMutex
+	player    *mpv.Mpv
+	mu        sync.RWMutex
+	eventChan chan playerEvent
+	closing   atomic.Bool // Stops the forwarding of player events
 }
 
+// Maximum time (in seconds) to wait for an mpv event before checking for player termination
+const eventWaitTimeoutSecs = 1.0
+
+// Minimum mpv volume gain (in decibels), which is silent
+const minVolumeGainDB = -96.0
+
 // mpv-specific error definitions
 var (
 	errMPVPlayback = errors.New("mpv playback error")
 )
 
+// assEscaper escapes text shown as ASS-formatted OSD text: ASS override tags ("{" and "\") are
+// escaped as mpv does (a word joiner follows a backslash), as is mpv property expansion ("$")
+var assEscaper = strings.NewReplacer("\\", "\\\u2060", "{", "\\{", "$", "$$")
+
 // newMpvPlayer creates a new mpvPlayer instance
 func newMpvPlayer(ctx context.Context, videoConfig config.VideoConfig) (*mpvPlayer, error) {
 
-	// Ensure C locale is set to "C" for numeric formats
-	C.set_c_locale_numeric()
+	setNumericLocale()
 
 	m := &mpvPlayer{
-		player: mpv.New(),
+		player:    mpv.New(),
+		eventChan: make(chan playerEvent, 1),
 	}
 
 	if m.player == nil {
@@ -57,6 +73,21 @@ func newMpvPlayer(ctx context.Context, videoConfig config.VideoConfig) (*mpvPlay
 		return nil, err
 	}
 
+	// Configure hardware-accelerated decoding based on host capabilities
+	if err := m.player.SetOptionString("hwdec", hardwareDecodingOption(ctx, videoConfig.HardwareDecoding)); err != nil {
+		return nil, fmt.Errorf(errFormat, "failed to set hardware decoding option", err)
+	}
+
+	// Prefer lighter-weight video output when the low-power profile is enabled
+	if videoConfig.LowPower {
+		m.setupLowPowerOutput(ctx)
+	}
+
+	// Apply any user-supplied media player options
+	if err := m.applyPlayerArgs(ctx, videoConfig.MediaPlayerArgs); err != nil {
+		return nil, err
+	}
+
 	// Initialize the mpv player
 	if err := m.player.Initialize(); err != nil {
 		return nil, fmt.Errorf(errFormat, "failed to initialize mpv player", err)
@@ -67,6 +98,11 @@ func newMpvPlayer(ctx context.Context, videoConfig config.VideoConfig) (*mpvPlay
 	return m, nil
 }
 
+// setNumericLocale ensures the C locale is set to "C" for numeric formats (required by mpv)
+func setNumericLocale() {
+	C.set_c_locale_numeric()
+}
+
 // setupGPUContext attempts to force Wayland context if we detect a Wayland environment
 func (m *mpvPlayer) setupGPUContext(ctx context.Context) {
 
@@ -82,6 +118,29 @@ func (m *mpvPlayer) setupGPUContext(ctx context.Context) {
 
 }
 
+// setupLowPowerOutput applies the mpv "fast" profile and, when no display server is running,
+// prefers direct DRM/KMS video output
+func (m *mpvPlayer) setupLowPowerOutput(ctx context.Context) {
+
+	if err := m.player.SetOptionString("profile", "fast"); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to set profile=fast: %v", err))
+	}
+
+	// DRM/KMS output requires exclusive access to the display, so only use it outside a desktop session
+	if hasDisplayServer() {
+		logger.Debug(ctx, logger.VIDEO, "display server detected; DRM/KMS video output not used")
+
+		return
+	}
+
+	if err := m.player.SetOptionString("gpu-context", "drm"); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to set gpu-context=drm: %v", err))
+	} else {
+		logger.Debug(ctx, logger.VIDEO, "mpv configured for DRM/KMS video output")
+	}
+
+}
+
 // setupDisplayTargeting configures mpv to target a specific display
 func (m *mpvPlayer) setupDisplayTargeting(ctx context.Context, videoConfig config.VideoConfig) error {
 
@@ -107,6 +166,25 @@ func (m *mpvPlayer) setupDisplayTargeting(ctx context.Context, videoConfig confi
 		return nil
 	}
 
+	// A focus mode plays the video full screen, on the target display if it was found
+	if videoConfig.FocusEnabled() {
+
+		if err := m.player.SetOptionString("fs", "yes"); err != nil {
+			return fmt.Errorf("failed to set fullscreen (fs) option: %w", err)
+		}
+
+		if isValid && targetName != "" {
+
+			if err := m.player.SetOptionString("fs-screen-name", targetName); err != nil {
+				return fmt.Errorf("failed to set fs-screen-name option to %s: %w", targetName, err)
+			}
+		}
+
+		logger.Info(ctx, logger.VIDEO, fmt.Sprintf("mpv configured for %s focus mode in fullscreen", videoConfig.FocusMode))
+
+		return nil
+	}
+
 	// Either default/embedded monitor or invalid/fallback: Wayland supports windowed mode here
 	if err := m.player.SetOptionString("fs", "no"); err != nil {
 		return fmt.Errorf("failed to unset fullscreen option: %w", err)
@@ -126,6 +204,22 @@ func (m *mpvPlayer) setupDisplayTargeting(ctx context.Context, videoConfig confi
 	return nil
 }
 
+// applyPlayerArgs passes user-supplied options verbatim to mpv prior to initialization
+func (m *mpvPlayer) applyPlayerArgs(ctx context.Context, args []string) error {
+
+	for _, arg := range args {
+		name, value := config.ParseMediaPlayerArg(arg)
+
+		if err := m.player.SetOptionString(name, value); err != nil {
+			return fmt.Errorf("failed to set media player option %s=%s: %w", name, value, err)
+		}
+
+		logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("mpv option set from media_player_args: %s=%s", name, value))
+	}
+
+	return nil
+}
+
 // validateVideoFile validates the video file using a tmp/headless MPV instance
 func (m *mpvPlayer) validateVideoFile(videoPath, position string) error {
 
@@ -208,10 +302,25 @@ func (m *mpvPlayer) loadFile(path string) error {
 			return err
 		}
 
+		m.logHardwareDecoding()
+
 		return nil
 	})
 }
 
+// logHardwareDecoding logs the hardware decoding API actually selected by mpv for the loaded file
+func (m *mpvPlayer) logHardwareDecoding() {
+
+	current, err := m.player.GetProperty("hwdec-current", mpv.FormatString)
+	if err != nil || !isNonEmptyString(current) || current == "no" {
+		logger.Info(logger.BackgroundCtx, logger.VIDEO, "mpv using software video decoding")
+
+		return
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.VIDEO, fmt.Sprintf("mpv using hardware video decoding: %v", current))
+}
+
 // configureHeadless configures an mpv instance for tmp/headless operation
 func (m *mpvPlayer) configureHeadless(p *mpv.Mpv) error {
 
@@ -415,6 +524,36 @@ func (m *mpvPlayer) setPause(paused bool) error {
 	})
 }
 
+// applyState applies the playback speed, pause state, and OSD text as a single update, issuing
+// the property changes asynchronously so that mpv can coalesce them
+func (m *mpvPlayer) applyState(state playerState) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+
+		if state.speed > 0 {
+
+			if err := m.player.SetPropertyAsync("speed", 0, mpv.FormatDouble, state.speed); err != nil {
+				return fmt.Errorf(errFormat, "failed to set video playback speed", err)
+			}
+
+		}
+
+		if state.updatePause {
+
+			if err := m.player.SetPropertyAsync("pause", 0, mpv.FormatFlag, state.paused); err != nil {
+				return fmt.Errorf(errFormat, "failed to pause video", err)
+			}
+
+		}
+
+		if state.updateOSD {
+			return wrapError("failed to show OSD text", m.player.SetOptionString("osd-msg1", state.osdText))
+		}
+
+		return nil
+	})
+}
+
 // getInt64Property is a helper to retrieve an integer property from the mpv player
 func (m *mpvPlayer) getInt64Property(property string, format mpv.Format, errorContext string) (int64, error) {
 
@@ -446,6 +585,11 @@ func (m *mpvPlayer) playbackPosition() (int64, error) {
 	return m.getInt64Property("time-pos", mpv.FormatDouble, "failed to get video playback position")
 }
 
+// duration gets the total duration of the video
+func (m *mpvPlayer) duration() (int64, error) {
+	return m.getInt64Property("duration", mpv.FormatDouble, "failed to get video duration")
+}
+
 // setPlaybackSize sets media player window size
 func (m *mpvPlayer) setPlaybackSize(windowSize float64) error {
 
@@ -463,6 +607,15 @@ func (m *mpvPlayer) setPlaybackSize(windowSize float64) error {
 	})
 }
 
+// setWindowPosition sets the position of the (windowed) video window, which Wayland compositors
+// may ignore
+func (m *mpvPlayer) setWindowPosition(x, y int) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set window position", m.player.SetOptionString("geometry", fmt.Sprintf("+%d+%d", x, y)))
+	})
+}
+
 // setKeepOpen configures the player to keep the window open after playback completes
 func (m *mpvPlayer) setKeepOpen(keepOpen bool) error {
 
@@ -484,6 +637,14 @@ func (m *mpvPlayer) seek(position string) error {
 	})
 }
 
+// seekTo seeks to an absolute playback position (in seconds) during playback
+func (m *mpvPlayer) seekTo(seconds float64) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError(errUnableToSeek.Error(), m.player.Command([]string{"seek", strconv.FormatFloat(seconds, 'f', 1, 64), "absolute"}))
+	})
+}
+
 // setOSD configures the On-Screen Display (OSD)
 func (m *mpvPlayer) setOSD(options osdConfig) error {
 
@@ -513,48 +674,204 @@ func (m *mpvPlayer) setOSD(options osdConfig) error {
 	})
 }
 
-// setupEvents prepares the player to listen for end-of-file and file-loaded events
-func (m *mpvPlayer) setupEvents() error {
+// setSubtitles adds an external subtitle file (if provided) and sets subtitle visibility
+func (m *mpvPlayer) setSubtitles(path string, visible bool) error {
 
 	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+
+		if !visible {
+			return wrapError("failed to hide subtitles", m.player.SetProperty("sub-visibility", mpv.FormatFlag, false))
+		}
+
+		// Add and select the external subtitle file, otherwise fall back to embedded subtitles
+		if path != "" {
+
+			if err := m.player.Command([]string{"sub-add", path, "select"}); err != nil {
+				return fmt.Errorf(errFormat, "failed to add subtitle file", err)
+			}
+
+		} else if err := m.player.SetPropertyString("sid", "auto"); err != nil {
+			return fmt.Errorf(errFormat, "failed to select embedded subtitles", err)
+		}
+
+		return wrapError("failed to show subtitles", m.player.SetProperty("sub-visibility", mpv.FormatFlag, true))
+	})
+}
+
+// setAudioTrack selects the audio track to play, where 0 selects the mpv default track
+func (m *mpvPlayer) setAudioTrack(track int) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		value := "auto"
+		if track > 0 {
+			value = strconv.Itoa(track)
+		}
+
+		return wrapError("failed to set audio track", m.player.SetPropertyString("aid", value))
+	})
+}
+
+// setVolume sets the audio playback volume (0-100)
+func (m *mpvPlayer) setVolume(volume int) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set audio volume", m.player.SetProperty("volume", mpv.FormatDouble, float64(volume)))
+	})
+}
+
+// setMute sets the audio mute state
+func (m *mpvPlayer) setMute(muted bool) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set audio mute", m.player.SetProperty("mute", mpv.FormatFlag, muted))
+	})
+}
+
+// setPitchCorrection enables (or disables) audio pitch correction (the scaletempo filter), which
+// keeps the audio pitch natural when playback runs faster or slower than normal
+func (m *mpvPlayer) setPitchCorrection(enabled bool) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set audio pitch correction", m.player.SetProperty("audio-pitch-correction", mpv.FormatFlag, enabled))
+	})
+}
+
+// setAudioGain scales the audio volume by a gain (0.0-1.0) using the mpv volume-gain property,
+// which applies on top of the volume (so a fade doesn't change the volume setting)
+func (m *mpvPlayer) setAudioGain(gain float64) error {
+
+	// mpv volume gain is in decibels, where the minimum gain is silent
+	gainDB := minVolumeGainDB
+	if gain > 0 {
+		gainDB = max(20*math.Log10(min(gain, 1.0)), minVolumeGainDB)
+	}
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set audio volume gain", m.player.SetProperty("volume-gain", mpv.FormatDouble, gainDB))
+	})
+}
+
+// supportsInterpolation reports whether the mpv video output supports frame interpolation (only
+// the GPU video outputs do)
+func (m *mpvPlayer) supportsInterpolation() bool {
+
+	supported, _ := queryGuarded(&m.mu, func() bool { return m.player == nil }, func() (bool, error) {
+
+		vo, err := m.player.GetProperty("current-vo", mpv.FormatString)
+		if err != nil {
+			return false, err
+		}
+
+		return vo == "gpu" || vo == "gpu-next", nil
+	})
+
+	return supported
+}
+
+// configureInterpolation prepares mpv for frame interpolation, which requires video to be synced
+// to the display refresh rate
+func (m *mpvPlayer) configureInterpolation() error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+
+		if err := m.player.SetPropertyString("video-sync", "display-resample"); err != nil {
+			return wrapError("failed to set video sync mode", err)
+		}
+
+		return wrapError("failed to set interpolation filter", m.player.SetPropertyString("tscale", "oversample"))
+	})
+}
+
+// setInterpolation enables (or disables) frame interpolation
+func (m *mpvPlayer) setInterpolation(enabled bool) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set frame interpolation", m.player.SetProperty("interpolation", mpv.FormatFlag, enabled))
+	})
+}
+
+// droppedFrames gets the number of video frames dropped by the video output
+func (m *mpvPlayer) droppedFrames() (int64, error) {
+	return m.getInt64Property("frame-drop-count", mpv.FormatInt64, "failed to get dropped frame count")
+}
+
+// setupEvents prepares the player to listen for end-of-file events, and starts delivering player
+// events to the events channel
+func (m *mpvPlayer) setupEvents() error {
+
+	err := execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
 		return wrapError("failed to setup end-of-file observe event", m.player.ObserveProperty(0, "eof-reached", mpv.FormatFlag))
 	})
+
+	if err != nil {
+		return err
+	}
+
+	go m.forwardEvents()
+
+	return nil
 }
 
-// waitEvent waits for an mpv event and translates it to a generic playerEvent
-func (m *mpvPlayer) waitEvent(timeout float64) *playerEvent {
+// events returns the channel on which player events are delivered
+func (m *mpvPlayer) events() <-chan playerEvent {
+	return m.eventChan
+}
+
+// forwardEvents waits for mpv events and delivers them to the events channel until the player is
+// terminated
+func (m *mpvPlayer) forwardEvents() {
+
+	for !m.closing.Load() {
 
-	res, _ := queryGuarded(&m.mu, func() bool { return m.player == nil }, func() (*playerEvent, error) {
+		event, err := queryGuarded(&m.mu, func() bool { return m.player == nil }, func() (*mpv.Event, error) {
+			return m.player.WaitEvent(eventWaitTimeoutSecs), nil
+		})
 
-		// If no event generated before timeout, return an empty event
-		e := m.player.WaitEvent(timeout)
-		if e == nil || e.EventID == mpv.EventNone {
-			return &playerEvent{id: eventNone}, nil
+		if err != nil {
+			return
 		}
 
-		switch e.EventID {
-		case mpv.EventPropertyChange:
-			prop := e.Property()
-			if prop.Name == "eof-reached" {
+		if e := translateEvent(event); e.id != eventNone {
 
-				if val, ok := prop.Data.(int); ok && val == 1 {
-					return &playerEvent{id: eventEndFile}, nil
-				}
+			// Drop the event if the previous one hasn't been consumed
+			select {
+			case m.eventChan <- e:
+			default:
+			}
 
+		}
+	}
+
+}
+
+// translateEvent translates an mpv event to a generic playerEvent
+func translateEvent(e *mpv.Event) playerEvent {
+
+	if e == nil {
+		return playerEvent{id: eventNone}
+	}
+
+	switch e.EventID {
+	case mpv.EventPropertyChange:
+		prop := e.Property()
+		if prop.Name == "eof-reached" {
+
+			if val, ok := prop.Data.(int); ok && val == 1 {
+				return playerEvent{id: eventEndFile}
 			}
 
-		case mpv.EventEnd:
-			return &playerEvent{id: eventEndFile}, nil
 		}
 
-		return &playerEvent{id: eventNone}, nil
-	})
+	case mpv.EventEnd:
+		return playerEvent{id: eventEndFile}
 
-	if res == nil {
-		return &playerEvent{id: eventNone}
+	case mpv.EventSetPropertyReply:
+		if e.Error != nil {
+			logger.Warn(logger.BackgroundCtx, logger.VIDEO, fmt.Sprintf("failed to apply player state: %v", e.Error))
+		}
 	}
 
-	return res
+	return playerEvent{id: eventNone}
 }
 
 // showOSDText displays text on the OSD
@@ -565,9 +882,58 @@ func (m *mpvPlayer) showOSDText(text string) error {
 	})
 }
 
+// showOSDMessage displays a transient message on the OSD for the given duration
+func (m *mpvPlayer) showOSDMessage(text string, durationMs int) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to show OSD message", m.player.Command([]string{"show-text", text, strconv.Itoa(durationMs)}))
+	})
+}
+
+// styleOSDText returns OSD text drawn in the given color ("#RRGGBB"), using ASS override tags
+// (mpv expects ASS colors in "&HBBGGRR&" order), or the text unchanged if the color isn't valid
+func (m *mpvPlayer) styleOSDText(text, color string) string {
+
+	rgb := strings.TrimPrefix(color, "#")
+	if len(rgb) != 6 {
+		return text
+	}
+
+	bgr := rgb[4:6] + rgb[2:4] + rgb[0:2]
+
+	return "${osd-ass-cc/0}{\\1c&H" + strings.ToUpper(bgr) + "&}" + assEscaper.Replace(text) + "{\\r}${osd-ass-cc/1}"
+}
+
+// setOSDColor sets the default color of OSD text ("#RRGGBB")
+func (m *mpvPlayer) setOSDColor(color string) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to set OSD color", m.player.SetOptionString("osd-color", color))
+	})
+}
+
+// showOverlay draws ASS-formatted graphics over the video using an OSD overlay
+func (m *mpvPlayer) showOverlay(assText string) error {
+
+	return execGuarded(&m.mu, func() bool { return m.player == nil }, func() error {
+		return wrapError("failed to show OSD overlay", m.player.Command([]string{
+			"osd-overlay", "1", "ass-events", assText, strconv.Itoa(overlayResX), strconv.Itoa(overlayResY),
+		}))
+	})
+}
+
 // terminatePlayer terminates the mpv player instance and cleans up resources
 func (m *mpvPlayer) terminatePlayer() {
 
+	// Stop forwarding player events, interrupting any wait for an event
+	m.closing.Store(true)
+
+	m.mu.RLock()
+	if m.player != nil {
+		m.player.Wakeup()
+	}
+	m.mu.RUnlock()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -0,0 +1,83 @@
+package video
+
+import (
+	"fmt"
+	"time"
+
+	mpv "github.com/gen2brain/go-mpv"
+)
+
+// VideoFileInfo describes the video stream of a video file
+type VideoFileInfo struct {
+	Codec    string
+	Width    int
+	Height   int
+	Duration time.Duration
+}
+
+// ProbeMediaPlayer creates a headless mpv player, returning the mpv (and FFmpeg) versions it
+// reports
+func ProbeMediaPlayer() (string, error) {
+
+	setNumericLocale()
+
+	p := mpv.New()
+	if p == nil {
+		return "", errFailedToCreatePlayer
+	}
+
+	defer p.TerminateDestroy()
+
+	m := &mpvPlayer{}
+	if err := m.configureHeadless(p); err != nil {
+		return "", err
+	}
+
+	version := p.GetPropertyString("mpv-version")
+	if ffmpeg := p.GetPropertyString("ffmpeg-version"); ffmpeg != "" {
+		version = fmt.Sprintf("%s (FFmpeg %s)", version, ffmpeg)
+	}
+
+	return version, nil
+}
+
+// ProbeVideoFile loads a video file into a headless mpv player, returning details of its video
+// stream (or an error if the video can't be decoded)
+func ProbeVideoFile(videoPath string) (VideoFileInfo, error) {
+
+	setNumericLocale()
+
+	p := mpv.New()
+	if p == nil {
+		return VideoFileInfo{}, errFailedToCreatePlayer
+	}
+
+	defer p.TerminateDestroy()
+
+	m := &mpvPlayer{}
+	if err := m.configureHeadless(p); err != nil {
+		return VideoFileInfo{}, err
+	}
+
+	if err := p.Command([]string{"loadfile", videoPath}); err != nil {
+		return VideoFileInfo{}, fmt.Errorf(errFormat, errFailedToLoadVideo, err)
+	}
+
+	stream, err := m.pollForActiveStream(p)
+	if err != nil {
+		return VideoFileInfo{}, err
+	}
+
+	info := VideoFileInfo{
+		Codec:  p.GetPropertyString("video-codec"),
+		Width:  stream.width,
+		Height: stream.height,
+	}
+
+	val, _ := p.GetProperty("duration", mpv.FormatDouble)
+	if secs, ok := val.(float64); ok {
+		info.Duration = time.Duration(secs * float64(time.Second))
+	}
+
+	return info, nil
+}
@@ -0,0 +1,57 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// OSD text color outside of the night mode (the mpv default)
+const osdDayColor = "#FFFFFF"
+
+// nightState holds whether the OSD is dimmed by the night mode of the session
+type nightState struct {
+	dimmed bool
+}
+
+// nightOSDColor returns the OSD text color (a gray) at the given night mode brightness (percent)
+func nightOSDColor(brightness int) string {
+
+	level := min(max(brightness, 0), 100) * 255 / 100
+
+	return fmt.Sprintf("#%02X%02X%02X", level, level, level)
+}
+
+// updateNightMode dims the OSD text once the night mode hours of the session begin, and restores
+// it once they end (so a session ridden past the start of the night mode is dimmed as it runs)
+func (p *PlaybackController) updateNightMode(ctx context.Context) {
+
+	osd := p.videoConfig.OnScreenDisplay
+
+	night := osd.NightModeAt(time.Now())
+	if night == p.night.dimmed {
+		return
+	}
+
+	color := osdDayColor
+	if night {
+		color = nightOSDColor(osd.NightBrightness)
+	}
+
+	if err := p.player.setOSDColor(color); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to set night mode OSD color: %v", err))
+
+		return
+	}
+
+	p.night.dimmed = night
+
+	if night {
+		logger.Info(ctx, logger.VIDEO, fmt.Sprintf("night mode on: OSD dimmed to %d%% brightness", osd.NightBrightness))
+	} else {
+		logger.Info(ctx, logger.VIDEO, "night mode off: OSD brightness restored")
+	}
+
+}
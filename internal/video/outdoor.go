@@ -0,0 +1,60 @@
+package video
+
+import (
+	"context"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// SetOutdoorRide sets the outdoor ride of the same route that the ride totals of the session are
+// compared against ("vs outdoor you"), shown on the OSD
+func (p *PlaybackController) SetOutdoorRide(ride *speed.OutdoorRide) {
+	p.outdoor = ride
+}
+
+// outdoorComparison compares the ride totals of the goal tracker against the outdoor ride (false
+// if the session has no outdoor ride, or nothing has been ridden yet)
+func (p *PlaybackController) outdoorComparison() (speed.OutdoorComparison, bool) {
+
+	if p.outdoor == nil || p.goal.tracker == nil {
+		return speed.OutdoorComparison{}, false
+	}
+
+	progress := p.goal.tracker.Progress()
+	if progress.RideTime == 0 {
+		return speed.OutdoorComparison{}, false
+	}
+
+	return p.outdoor.Compare(progress), true
+}
+
+// outdoorOSDText returns the OSD line showing how far ahead of or behind the outdoor ride the
+// session is, colored by whether the rider is ahead (empty if the session has no outdoor ride)
+func (p *PlaybackController) outdoorOSDText() string {
+
+	comparison, ok := p.outdoorComparison()
+	if !ok {
+		return ""
+	}
+
+	color := raceBehindColor
+	if comparison.Ahead() {
+		color = raceOnPaceColor
+	}
+
+	return p.player.styleOSDText("vs Outdoor: "+comparison.Describe(p.speedConfig.SpeedUnits), color) + "\n"
+}
+
+// reportOutdoor logs how far ahead of or behind the outdoor ride the session finished once
+// playback stops
+func (p *PlaybackController) reportOutdoor(ctx context.Context) {
+
+	comparison, ok := p.outdoorComparison()
+	if !ok {
+		return
+	}
+
+	logger.Info(ctx, logger.VIDEO, "vs outdoor ride: finished "+comparison.Describe(p.speedConfig.SpeedUnits))
+
+}
@@ -0,0 +1,112 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// Fixed playback speed of a race against the clock
+const racePlaybackSpeed = 1.0
+
+// OSD colors of the race deviation, when on pace and when behind the target speed
+const (
+	raceOnPaceColor = "#33d17a"
+	raceBehindColor = "#e01b24"
+)
+
+// raceState holds the race scorer of the session and the time of its last update
+type raceState struct {
+	scorer  *speed.RaceScorer
+	updated time.Time
+}
+
+// SetRaceScorer sets the scorer of a race against the clock, where the video plays at a fixed
+// 1.0x while the rider's speed is scored against the target speed
+func (p *PlaybackController) SetRaceScorer(scorer *speed.RaceScorer) {
+	p.race.scorer = scorer
+}
+
+// racing returns true if the session is a race against the clock
+func (p *PlaybackController) racing() bool {
+	return p.race.scorer.Enabled()
+}
+
+// updateRacePlayback keeps playback at the fixed race pace, whatever the speed, refreshing the
+// OSD with the deviation from the target speed
+func (p *PlaybackController) updateRacePlayback(ctx context.Context) error {
+
+	p.resumed.Store(false)
+
+	state := playerState{speed: racePlaybackSpeed, paused: false}
+	state.osdText, state.updateOSD = p.buildOSDText(ctx, p.speedState.current, racePlaybackSpeed)
+
+	if err := p.applyState(state); err != nil {
+		return fmt.Errorf(errFormat, "failed to set race playback speed", err)
+	}
+
+	p.speedState.last = p.speedState.current
+
+	return nil
+}
+
+// updateRace scores the ride since the last update against the target speed (the race clock
+// holds while playback is paused or the start countdown runs)
+func (p *PlaybackController) updateRace() {
+
+	if !p.racing() {
+		return
+	}
+
+	now := p.now()
+	last := p.race.updated
+	p.race.updated = now
+
+	if last.IsZero() || p.paused.Load() || p.countdown.ends.Load() != 0 {
+		return
+	}
+
+	easy := false
+	if p.intervals.timer != nil && p.intervals.timer.Enabled() {
+		easy = p.intervals.timer.State().Phase == speed.IntervalEasy
+	}
+
+	p.race.scorer.Update(p.speedState.current, easy, now.Sub(last))
+
+}
+
+// raceOSDText returns the OSD line showing the deviation from the target speed and the effort
+// score, colored by whether the rider is on pace (empty if the session isn't a race)
+func (p *PlaybackController) raceOSDText() string {
+
+	if !p.racing() {
+		return ""
+	}
+
+	score := p.race.scorer.Score()
+	if score.Scored == 0 {
+		return ""
+	}
+
+	color := raceBehindColor
+	if score.OnPace() {
+		color = raceOnPaceColor
+	}
+
+	return p.player.styleOSDText("Race: "+score.Describe(p.speedConfig.SpeedUnits), color) + "\n"
+}
+
+// reportRace logs the effort score of the race once playback stops
+func (p *PlaybackController) reportRace(ctx context.Context) {
+
+	if !p.racing() {
+		return
+	}
+
+	score := p.race.scorer.Score()
+	logger.Info(ctx, logger.VIDEO, fmt.Sprintf("race against the clock: effort score %.0f%% over %s", score.Score, score.Scored.Round(time.Second)))
+
+}
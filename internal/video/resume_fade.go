@@ -0,0 +1,87 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Playback speed that the fade-in of resumed playback starts from
+const resumeFadeStartSpeed = 0.5
+
+// resumeFadeState holds the state of the fade-in of playback resumed after a pause
+type resumeFadeState struct {
+	started     time.Time // Start of the fade-in (zero while not fading in)
+	noAudioFade bool      // The media player failed to fade the audio
+}
+
+// startResumeFade starts fading playback in (if configured) as playback resumes after a pause
+func (p *PlaybackController) startResumeFade(ctx context.Context) {
+
+	if p.videoConfig.ResumeFadeSecs <= 0 || !p.commanded.known || !p.commanded.paused {
+		return
+	}
+
+	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("fading in resumed playback over %.1fs", p.videoConfig.ResumeFadeSecs))
+
+	p.fade.started = time.Now()
+
+}
+
+// fadingIn returns true while resumed playback is fading in
+func (p *PlaybackController) fadingIn() bool {
+	return !p.fade.started.IsZero()
+}
+
+// stopResumeFade stops fading playback in (e.g., when playback is paused again mid-fade),
+// restoring the audio volume
+func (p *PlaybackController) stopResumeFade(ctx context.Context) {
+
+	if !p.fadingIn() {
+		return
+	}
+
+	p.fade.started = time.Time{}
+	p.fadeAudio(ctx, 1.0)
+
+}
+
+// resumeFadeSpeed returns the playback speed while resumed playback fades in, ramped from
+// resumeFadeStartSpeed up to the playback speed (with the audio faded in alongside)
+func (p *PlaybackController) resumeFadeSpeed(ctx context.Context, playbackSpeed float64) float64 {
+
+	if !p.fadingIn() {
+		return playbackSpeed
+	}
+
+	progress := min(time.Since(p.fade.started).Seconds()/p.videoConfig.ResumeFadeSecs, 1.0)
+	if progress >= 1.0 {
+		p.stopResumeFade(ctx)
+
+		return playbackSpeed
+	}
+
+	p.fadeAudio(ctx, progress)
+
+	start := min(max(resumeFadeStartSpeed, p.videoConfig.MinPlaybackSpeed), playbackSpeed)
+
+	return start + (playbackSpeed-start)*progress
+}
+
+// fadeAudio scales the audio volume by the gain (0.0-1.0) of the fade-in, leaving the audio as is
+// if the media player can't fade audio
+func (p *PlaybackController) fadeAudio(ctx context.Context, gain float64) {
+
+	if p.fade.noAudioFade {
+		return
+	}
+
+	if err := p.player.setAudioGain(gain); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("audio fade-in not supported by the media player: %v", err))
+
+		p.fade.noAudioFade = true
+	}
+
+}
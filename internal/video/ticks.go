@@ -0,0 +1,82 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/services"
+)
+
+// Interval between tick drift reports while the session runs
+const tickDriftReportInterval = 10 * time.Minute
+
+// tickState holds the ticks of the playback event loop, scheduled on the monotonic clock so that
+// update times don't drift from video time over long sessions
+type tickState struct {
+	scheduler *services.TickScheduler
+	current   time.Time // Scheduled time of the tick being processed (zero outside of the event loop)
+	reported  time.Time
+}
+
+// startTicks starts the ticks of the playback event loop, one every update interval
+func (p *PlaybackController) startTicks() *services.TickScheduler {
+
+	p.ticks = tickState{
+		scheduler: services.NewTickScheduler(time.Duration(p.videoConfig.UpdateIntervalSec * float64(time.Second))),
+		reported:  time.Now(),
+	}
+
+	return p.ticks.scheduler
+}
+
+// nextTick starts processing a tick of the playback event loop
+func (p *PlaybackController) nextTick() {
+	p.ticks.current = p.ticks.scheduler.Tick()
+}
+
+// stopTicks stops the ticks of the playback event loop, reporting their drift
+func (p *PlaybackController) stopTicks(ctx context.Context) {
+
+	p.ticks.scheduler.Stop()
+	p.ticks.current = time.Time{}
+
+	logger.Info(ctx, logger.VIDEO, p.tickDriftReport())
+
+}
+
+// updateTickDriftReport reports the drift of the playback event loop ticks once every report
+// interval
+func (p *PlaybackController) updateTickDriftReport(ctx context.Context) {
+
+	if time.Since(p.ticks.reported) < tickDriftReportInterval {
+		return
+	}
+
+	p.ticks.reported = time.Now()
+
+	logger.Debug(ctx, logger.VIDEO, p.tickDriftReport())
+
+}
+
+// tickDriftReport returns the drift of the playback event loop ticks from their schedule (how late
+// each tick was processed), compared to update_interval_secs
+func (p *PlaybackController) tickDriftReport() string {
+
+	stats := p.ticks.scheduler.Stats()
+
+	return fmt.Sprintf("update tick drift (%d ticks): %s (update_interval_secs = %.2f)", stats.Ticks, stats, p.videoConfig.UpdateIntervalSec)
+}
+
+// now returns the time of the work done by the playback event loop: the scheduled time of the
+// tick being processed (so that elapsed times add up to whole update intervals, however late each
+// tick is processed), or the current time outside of a tick
+func (p *PlaybackController) now() time.Time {
+
+	if p.ticks.current.IsZero() {
+		return time.Now()
+	}
+
+	return p.ticks.current
+}
@@ -0,0 +1,77 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Interval between refreshes of the time remaining estimate from the media player
+const timeRemainingRefreshInterval = time.Second
+
+// remainingState holds the estimate of the time remaining in the video, refreshed from the media
+// player by the playback event loop so that frequent reads (e.g., GUI polling and OSD updates) are
+// served from memory
+type remainingState struct {
+	seconds   atomic.Int64
+	known     atomic.Bool // The estimate has been refreshed from the media player
+	refreshed time.Time   // Last refresh from the media player (event loop only)
+}
+
+// refreshTimeRemaining refreshes the time remaining estimate from the media player, once every
+// refresh interval
+func (p *PlaybackController) refreshTimeRemaining(ctx context.Context) {
+
+	now := p.now()
+	if now.Sub(p.remaining.refreshed) < timeRemainingRefreshInterval {
+		return
+	}
+
+	p.remaining.refreshed = now
+
+	seconds, err := p.player.timeRemaining()
+	if err != nil {
+		logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("%s: %v", errTimeRemaining, err))
+
+		return
+	}
+
+	p.storeTimeRemaining(seconds)
+
+}
+
+// correctTimeRemaining corrects the time remaining estimate from the media player (e.g., once
+// playback is paused), if the estimate is maintained by the playback event loop
+func (p *PlaybackController) correctTimeRemaining() {
+
+	if !p.remaining.known.Load() {
+		return
+	}
+
+	if seconds, err := p.player.timeRemaining(); err == nil {
+		p.storeTimeRemaining(seconds)
+	}
+
+}
+
+// storeTimeRemaining stores the time remaining estimate (in seconds)
+func (p *PlaybackController) storeTimeRemaining(seconds int64) {
+
+	p.remaining.seconds.Store(max(seconds, 0))
+	p.remaining.known.Store(true)
+
+}
+
+// timeRemaining returns the time remaining in the video (in seconds) from the estimate, querying
+// the media player only until the estimate is first refreshed
+func (p *PlaybackController) timeRemaining() (int64, error) {
+
+	if p.remaining.known.Load() {
+		return p.remaining.seconds.Load(), nil
+	}
+
+	return p.player.timeRemaining()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
 )
 
 // PlaybackController manages video playback
@@ -20,33 +21,98 @@ type PlaybackController struct {
 	videoConfig config.VideoConfig
 	speedConfig config.SpeedConfig
 	osdConfig   osdConfig
+	audioConfig audioConfig
 	InstanceID  int64
 
 	// Media player state
 	player              mediaPlayer
 	speedState          *speedState
+	audioState          *audioState
 	speedUnitMultiplier float64
+	commanded           commandedState
+	overlay             metricsOverlay
+	interpolation       interpolationState
+	goal                goalState
+	intervals           intervalState
+	laps                lapState
+	race                raceState
+	outdoor             *speed.OutdoorRide
+	night               nightState
+	fade                resumeFadeState
+	countdown           countdownState
+	remaining           remainingState
+	ticks               tickState
+
+	// Playback pause state (set independently of cycling speed, e.g., from the GUI)
+	paused  atomic.Bool
+	resumed atomic.Bool
+
+	// Session event reporting
+	eventHandler   func(message string)
+	milestoneIndex int
+
+	// Playback progress reporting (e.g., to a watchdog)
+	heartbeat func()
+
+	// Speed update latency measurement (if enabled from the command-line)
+	latency latencyState
+}
+
+// countdownState holds the start countdown of the session
+type countdownState struct {
+	ends  atomic.Int64 // End of the countdown (UnixNano, 0 = no countdown running)
+	shown int          // Seconds remaining last shown on the OSD
+}
+
+// commandedState holds the playback state last sent to the media player, used to skip sending
+// redundant commands
+type commandedState struct {
+	speed   float64
+	paused  bool
+	known   bool // A pause state has been sent
+	osdText string
+}
+
+// progressMilestones defines the playback completion percentages reported as session events
+var progressMilestones = []int{25, 50, 75}
+
+// audioState holds the runtime audio state of the media player, which may be changed during
+// playback (e.g., from the GUI)
+type audioState struct {
+	volume atomic.Int64
+	muted  atomic.Bool
 }
 
 // speedState holds the state of the speedController speed
 type speedState struct {
-	current float64
-	last    float64
+	current    float64
+	last       float64
+	average    float64 // Session average speed (while moving)
+	maximum    float64 // Session maximum speed
+	osdUpdated time.Time
+	updated    time.Time // Time of the speed measurement read from the speed controller
 }
 
 // Instance counter to distinguish between controller object instances
 var videoInstanceCounter atomic.Int64
 
 const (
+	// Duration of transient OSD messages (e.g., volume changes)
+	osdMessageDurationMs = 1500
+
 	// Divisor used to convert speed relative to playback rate
 	// e.g., a speed of 10 mph = 1.0x video playback (hence divisor of 10)
 	speedDivisor = 10.0
+
+	// Playback speed limits shown on the OSD while the playback speed is limited
+	playbackLimitMin = "MIN"
+	playbackLimitMax = "MAX"
 )
 
-// speedUnitConversion maps units of speed to their multiplier for consistent playback speed
-var speedUnitConversion = map[string]float64{
-	config.SpeedUnitsKMH: 1.60934,
-	config.SpeedUnitsMPH: 1.0,
+// speedUnitFactor returns the multiplier of the configured speed units relative to mph (wheel
+// revolutions per minute depend on the wheel circumference), for consistent playback speed
+func speedUnitFactor(speedConfig config.SpeedConfig) float64 {
+	return speedConfig.Converter().FromMilesPerHour(1)
 }
 
 // NewPlaybackController creates a new video player instance with the given config
@@ -75,31 +141,143 @@ func NewPlaybackController(ctx context.Context, videoConfig config.VideoConfig,
 		return nil, fmt.Errorf("failed to create %s player: %w", videoConfig.MediaPlayer, err)
 	}
 
+	osd := newOSDConfig(videoConfig.OnScreenDisplay)
+
+	if videoConfig.LowPower {
+		applyLowPowerProfile(ctx, &osd)
+	}
+
 	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("created video controller object (id:%04d)", instanceID))
 
 	return &PlaybackController{
 		videoConfig: videoConfig,
 		speedConfig: speedConfig,
-		osdConfig:   newOSDConfig(videoConfig.OnScreenDisplay),
+		osdConfig:   osd,
+		audioConfig: newAudioConfig(videoConfig.Audio),
 		player:      player,
 		InstanceID:  instanceID,
 		speedState:  &speedState{},
+		audioState:  newAudioState(videoConfig.Audio),
+		latency:     newLatencyState(videoConfig.MeasureLatency),
 	}, nil
 }
 
 // newOSDConfig creates a new OSD configuration from the video config
 func newOSDConfig(displayConfig config.VideoOSDConfig) osdConfig {
 	return osdConfig{
-		showOSD:              displayConfig.ShowOSD,
-		fontSize:             displayConfig.FontSize,
-		displayCycleSpeed:    displayConfig.DisplayCycleSpeed,
-		displayPlaybackSpeed: displayConfig.DisplayPlaybackSpeed,
-		displayTimeRemaining: displayConfig.DisplayTimeRemaining,
-		marginX:              displayConfig.MarginX,
-		marginY:              displayConfig.MarginY,
-		alignX:               displayConfig.AlignX,
-		alignY:               displayConfig.AlignY,
+		showOSD:               displayConfig.ShowOSD,
+		fontSize:              displayConfig.FontSize,
+		displayCycleSpeed:     displayConfig.DisplayCycleSpeed,
+		displayPlaybackSpeed:  displayConfig.DisplayPlaybackSpeed,
+		displayTimeRemaining:  displayConfig.DisplayTimeRemaining,
+		displaySpeedStats:     displayConfig.DisplaySpeedStats,
+		displayMetricsOverlay: displayConfig.DisplayMetricsOverlay,
+		marginX:               displayConfig.MarginX,
+		marginY:               displayConfig.MarginY,
+		alignX:                displayConfig.AlignX,
+		alignY:                displayConfig.AlignY,
+		updateInterval:        time.Duration(displayConfig.UpdateIntervalSec * float64(time.Second)),
+	}
+}
+
+// newAudioConfig creates a new audio configuration from the video config
+func newAudioConfig(audio config.VideoAudioConfig) audioConfig {
+	return audioConfig{
+		volume:          audio.Volume,
+		mute:            audio.Mute,
+		track:           audio.AudioTrack,
+		pitchCorrection: audio.PitchCorrection,
+	}
+}
+
+// newAudioState creates the runtime audio state, initialized from the video config
+func newAudioState(audio config.VideoAudioConfig) *audioState {
+
+	state := &audioState{}
+	state.volume.Store(int64(audio.Volume))
+	state.muted.Store(audio.Mute)
+
+	return state
+}
+
+// Paused returns true if video playback has been paused independently of cycling speed
+func (p *PlaybackController) Paused() bool {
+	return p.paused.Load()
+}
+
+// SetPaused pauses (or resumes) video playback independently of cycling speed
+func (p *PlaybackController) SetPaused(paused bool) error {
+
+	if p.paused.Swap(paused) == paused {
+		return nil
 	}
+
+	if !paused {
+		p.resumed.Store(true)
+
+		return p.player.showOSDMessage("Resumed", osdMessageDurationMs)
+	}
+
+	if err := p.player.setPause(true); err != nil {
+		return fmt.Errorf(errFormat, "failed to pause playback", err)
+	}
+
+	p.commanded.paused, p.commanded.known = true, true
+	p.correctTimeRemaining()
+
+	return p.player.showOSDMessage("Paused", osdMessageDurationMs)
+}
+
+// Seek seeks video playback by an offset from the current playback position (e.g., -30s), or to an
+// absolute playback position
+func (p *PlaybackController) Seek(position time.Duration, absolute bool) error {
+
+	target := position
+
+	if !absolute {
+
+		current, err := p.Position()
+		if err != nil {
+			return fmt.Errorf(errFormat, errUnableToSeek, err)
+		}
+
+		target += current
+	}
+
+	target = max(target, 0)
+
+	// Seeking past the end of the video would complete the session
+	duration, durationErr := p.Duration()
+	if durationErr == nil && target >= duration {
+		return fmt.Errorf(errFormat, formatSeconds(int64(target.Seconds())), ErrSeekExceedsDuration)
+	}
+
+	if err := p.player.seekTo(target.Seconds()); err != nil {
+		return err
+	}
+
+	// Correct the time remaining estimate to the seek target
+	if durationErr == nil {
+		p.storeTimeRemaining(int64((duration - target).Seconds()))
+	}
+
+	return p.player.showOSDMessage("Position: "+formatSeconds(int64(target.Seconds())), osdMessageDurationMs)
+}
+
+// ID returns the instance ID of the video controller
+func (p *PlaybackController) ID() int64 {
+	return p.InstanceID
+}
+
+// SetEventHandler sets the handler called when a significant playback event occurs (e.g., the
+// video reaches 50% completion)
+func (p *PlaybackController) SetEventHandler(handler func(message string)) {
+	p.eventHandler = handler
+}
+
+// SetHeartbeat sets the function called each time the playback event loop makes progress
+func (p *PlaybackController) SetHeartbeat(heartbeat func()) {
+	p.heartbeat = heartbeat
 }
 
 // StartPlayback configures and starts playback of the media player
@@ -126,10 +304,11 @@ func (p *PlaybackController) StartPlayback(ctx context.Context, speedController
 	return nil
 }
 
-// TimeRemaining returns the time remaining in the video
+// TimeRemaining returns the time remaining in the video (estimated, refreshed once per second
+// while playback runs)
 func (p *PlaybackController) TimeRemaining() (string, error) {
 
-	seconds, err := p.player.timeRemaining()
+	seconds, err := p.timeRemaining()
 	if err != nil {
 		return "--:--:--", err
 	}
@@ -148,14 +327,112 @@ func (p *PlaybackController) PlaybackPosition() (string, error) {
 	return formatSeconds(seconds), nil
 }
 
-// PlaybackSpeed returns the current calculated playback rate multiplier
+// Position returns the current playback position in the video
+func (p *PlaybackController) Position() (time.Duration, error) {
+
+	seconds, err := p.player.playbackPosition()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// Duration returns the total duration of the video
+func (p *PlaybackController) Duration() (time.Duration, error) {
+
+	seconds, err := p.player.duration()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// PlaybackSpeed returns the current calculated playback rate multiplier (limited to the
+// configured minimum and maximum playback speeds)
 func (p *PlaybackController) PlaybackSpeed() float64 {
 
 	if p.speedState == nil {
 		return 0.0
 	}
 
-	return p.speedState.current * p.speedUnitMultiplier
+	if p.racing() {
+		return racePlaybackSpeed
+	}
+
+	playbackSpeed, _ := p.clampPlaybackSpeed(p.speedState.current * p.speedUnitMultiplier)
+
+	return playbackSpeed
+}
+
+// clampPlaybackSpeed limits a playback speed to the configured minimum and maximum playback
+// speeds (where 0.0 = no limit), returning the limit applied ("MIN", "MAX", or "" if none). A
+// playback speed of 0.0 (paused) is never limited
+func (p *PlaybackController) clampPlaybackSpeed(playbackSpeed float64) (float64, string) {
+
+	if playbackSpeed <= 0 {
+		return playbackSpeed, ""
+	}
+
+	if maxSpeed := p.videoConfig.MaxPlaybackSpeed; maxSpeed > 0 && playbackSpeed > maxSpeed {
+		return maxSpeed, playbackLimitMax
+	}
+
+	if minSpeed := p.videoConfig.MinPlaybackSpeed; minSpeed > 0 && playbackSpeed < minSpeed {
+		return minSpeed, playbackLimitMin
+	}
+
+	return playbackSpeed, ""
+}
+
+// Volume returns the current audio volume (0-100) and mute state
+func (p *PlaybackController) Volume() (int, bool) {
+
+	if p.audioState == nil {
+		return 0, false
+	}
+
+	return int(p.audioState.volume.Load()), p.audioState.muted.Load()
+}
+
+// SetVolume sets the audio volume (clamped to 0-100) and displays the change on the OSD
+func (p *PlaybackController) SetVolume(volume int) error {
+
+	volume = max(0, min(100, volume))
+
+	if err := p.player.setVolume(volume); err != nil {
+		return err
+	}
+
+	p.audioState.volume.Store(int64(volume))
+
+	return p.showAudioStatus()
+}
+
+// SetMute sets the audio mute state and displays the change on the OSD
+func (p *PlaybackController) SetMute(muted bool) error {
+
+	if err := p.player.setMute(muted); err != nil {
+		return err
+	}
+
+	p.audioState.muted.Store(muted)
+
+	return p.showAudioStatus()
+}
+
+// showAudioStatus briefly displays the current audio volume/mute state on the OSD
+func (p *PlaybackController) showAudioStatus() error {
+
+	volume, muted := p.Volume()
+	text := fmt.Sprintf("Volume: %d%%", volume)
+
+	if muted {
+		text = "Volume: muted"
+	}
+
+	return p.player.showOSDMessage(text, osdMessageDurationMs)
 }
 
 // configurePlayback configures the media player for playback based on the video configuration
@@ -183,7 +460,7 @@ func (p *PlaybackController) configurePlayback(ctx context.Context) error {
 	}
 
 	// Precalculate playback speed multiplier based on speed units
-	p.speedUnitMultiplier = p.videoConfig.SpeedMultiplier / (speedUnitConversion[p.speedConfig.SpeedUnits] * speedDivisor)
+	p.speedUnitMultiplier = p.videoConfig.SpeedMultiplier / (speedUnitFactor(p.speedConfig) * speedDivisor)
 
 	return nil
 }
@@ -201,6 +478,20 @@ func (p *PlaybackController) configureCommon() error {
 		return err
 	}
 
+	// Configure audio playback (volume, mute, and audio track selection)
+	if err := p.configureAudio(); err != nil {
+		return err
+	}
+
+	// Configure subtitles (must be done after loadFile() for mpv since tracks are per-file)
+	if err := p.player.setSubtitles(p.videoConfig.SubtitlePath, p.videoConfig.ShowSubtitles); err != nil {
+		return err
+	}
+
+	// Configure frame interpolation (must be done after loadFile() for mpv since the video output
+	// it depends on is only known once initialized)
+	p.configureInterpolation(logger.BackgroundCtx)
+
 	// Configure OSD if enabled (must be done after loadFile() for mpv since vout needs to be initialized)
 	if p.osdConfig.showOSD {
 		return p.player.setOSD(p.osdConfig)
@@ -209,19 +500,47 @@ func (p *PlaybackController) configureCommon() error {
 	return nil
 }
 
+// configureAudio applies the configured audio settings to the media player
+func (p *PlaybackController) configureAudio() error {
+
+	if err := p.player.setVolume(p.audioConfig.volume); err != nil {
+		return err
+	}
+
+	if err := p.player.setMute(p.audioConfig.mute); err != nil {
+		return err
+	}
+
+	if err := p.player.setPitchCorrection(p.audioConfig.pitchCorrection); err != nil {
+		return err
+	}
+
+	return p.player.setAudioTrack(p.audioConfig.track)
+}
+
 // setPlaybackOptions sets load-time sensitive playback options for mpv
 func (p *PlaybackController) setPlaybackOptions(ctx context.Context) error {
 
 	// Set the initial window scale factor for mpv, unless the target display requires fullscreen
-	// (e.g., a non-default monitor under Wayland)
-	if p.videoConfig.ValidationResult.IsValid && p.videoConfig.ValidationResult.IsNonDefaultMonitor {
+	// (e.g., a non-default monitor under Wayland), or a focus mode plays the video full screen
+	switch {
+	case p.videoConfig.ValidationResult.IsValid && p.videoConfig.ValidationResult.IsNonDefaultMonitor:
 		logger.Debug(ctx, logger.VIDEO, "bypassing autofit scale factor: target display requires forced fullscreen")
-	} else {
+
+	case p.videoConfig.FocusEnabled():
+		logger.Debug(ctx, logger.VIDEO, "bypassing autofit scale factor: focus mode plays the video full screen")
+
+		if err := p.player.setPlaybackSize(1.0); err != nil {
+			return err
+		}
+
+	default:
 
 		if err := p.player.setPlaybackSize(p.videoConfig.WindowScaleFactor); err != nil {
 			return err
 		}
 
+		p.applyWindowPosition(ctx)
 	}
 
 	// Set seek position into video playback
@@ -232,29 +551,93 @@ func (p *PlaybackController) setPlaybackOptions(ctx context.Context) error {
 	return nil
 }
 
+// applyWindowPosition places a windowed (not full screen) video window at the position set in the
+// session file (the position isn't recorded when the window is moved, as mpv can't report it)
+func (p *PlaybackController) applyWindowPosition(ctx context.Context) {
+
+	if p.videoConfig.WindowScaleFactor >= 1.0 {
+		return
+	}
+
+	x, y, ok := config.ParseWindowPosition(p.videoConfig.WindowPosition)
+	if !ok {
+		return
+	}
+
+	if err := p.player.setWindowPosition(x, y); err != nil {
+		logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("failed to set video window position: %v", err))
+
+		return
+	}
+
+	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("video window position set to %d,%d", x, y))
+}
+
 // eventLoop is the main event loop for the media player
 func (p *PlaybackController) eventLoop(ctx context.Context, speedController *speed.Controller) error {
 
-	// Start a ticker to check updates from SpeedController
-	ticker := time.NewTicker(time.Duration(p.videoConfig.UpdateIntervalSec * float64(time.Second)))
+	// Start the ticks to check updates from SpeedController
+	ticks := p.startTicks()
 
-	defer ticker.Stop()
+	defer p.stopTicks(ctx)
+	defer p.reportLatency(ctx)
+	defer p.reportRace(ctx)
+	defer p.reportOutdoor(ctx)
 
-	for {
+	events := p.player.events()
 
-		// Check player events (give priority to video completion)
-		if err := p.handlePlayerEvents(); err != nil {
-			return err
-		}
+	p.startCountdown()
+
+	for {
 
 		select {
 
-		case <-ticker.C:
+		case event, ok := <-events:
+
+			if !ok {
+				events = nil // Player events are no longer delivered
+
+				continue
+			}
+
+			if err := p.handlePlayerEvent(event); err != nil {
+				return err
+			}
+
+		case <-ticks.C():
+
+			p.nextTick()
+
+			p.refreshTimeRemaining(ctx)
 
 			if err := p.updateSpeedFromController(ctx, speedController); err != nil {
 				logger.Warn(ctx, logger.VIDEO, fmt.Sprintf("speed update error: %v", err))
 			}
 
+			p.updateMetricsOverlay(ctx)
+
+			p.updateNightMode(ctx)
+
+			p.checkInterpolationDrops(ctx)
+
+			p.updateGoal(ctx)
+
+			p.updateIntervals(ctx)
+
+			p.updateLaps(ctx)
+
+			p.updateRace()
+
+			p.checkProgressMilestone()
+
+			p.updateLatencyReport(ctx)
+
+			p.updateTickDriftReport(ctx)
+
+			if p.heartbeat != nil {
+				p.heartbeat()
+			}
+
 		case <-ctx.Done():
 			logger.Debug(ctx, logger.VIDEO, fmt.Sprintf("interrupt detected, stopping %s video playback...", p.videoConfig.MediaPlayer))
 
@@ -264,32 +647,92 @@ func (p *PlaybackController) eventLoop(ctx context.Context, speedController *spe
 
 }
 
-// handlePlayerEvents handles callback events from the media player
-func (p *PlaybackController) handlePlayerEvents() error {
+// handlePlayerEvent handles an event delivered by the media player
+func (p *PlaybackController) handlePlayerEvent(event playerEvent) error {
+
+	if event.id == eventEndFile {
+		p.reportEvent("Video playback completed")
 
-	event := p.player.waitEvent(0)
-	if event != nil && event.id == eventEndFile {
 		return fmt.Errorf("%w", ErrVideoComplete)
 	}
 
 	return nil
 }
 
+// checkProgressMilestone reports an event when playback passes the next completion milestone
+func (p *PlaybackController) checkProgressMilestone() {
+
+	// Progress requires polling the media player, so skip when the low-power profile is enabled
+	if p.eventHandler == nil || p.videoConfig.LowPower || p.milestoneIndex >= len(progressMilestones) {
+		return
+	}
+
+	position, err := p.player.playbackPosition()
+	if err != nil {
+		return
+	}
+
+	remaining, err := p.timeRemaining()
+	if err != nil || position+remaining <= 0 {
+		return
+	}
+
+	percent := int(position * 100 / (position + remaining))
+	reached := 0
+
+	for p.milestoneIndex < len(progressMilestones) && percent >= progressMilestones[p.milestoneIndex] {
+		reached = progressMilestones[p.milestoneIndex]
+		p.milestoneIndex++
+	}
+
+	if reached > 0 {
+		p.reportEvent(fmt.Sprintf("Video %d%% complete", reached))
+	}
+
+}
+
+// reportEvent passes a significant playback event to the event handler, if one is set
+func (p *PlaybackController) reportEvent(message string) {
+
+	if p.eventHandler != nil {
+		p.eventHandler(message)
+	}
+
+}
+
 // updateSpeedFromController manages updates from the speedController component
 func (p *PlaybackController) updateSpeedFromController(ctx context.Context, speedController *speed.Controller) error {
 
-	p.speedState.current = speedController.SmoothedSpeed()
+	// Ignore speed updates while playback is paused
+	if p.paused.Load() {
+		return nil
+	}
+
+	// Hold playback until the start countdown completes
+	if p.updateCountdown(ctx) {
+		return nil
+	}
+
+	p.speedState.current, p.speedState.updated = speedController.SmoothedSpeed(), speedController.LastUpdate()
+	p.speedState.average, p.speedState.maximum = speedController.AverageSpeed(), speedController.MaxSpeed()
 	p.logDebugInfo(ctx, speedController)
 
+	// A race against the clock plays at a fixed pace, whatever the speed
+	if p.racing() {
+		return p.updateRacePlayback(ctx)
+	}
+
 	if p.speedState.current == 0 {
 		return p.handleZeroSpeed(ctx)
 	}
 
-	if p.shouldUpdateSpeed() {
+	// Always update the speed (and unpause the player) when playback is resumed, and while resumed
+	// playback fades in
+	if p.resumed.Swap(false) || p.shouldUpdateSpeed() || p.fadingIn() {
 		return p.updateSpeed(ctx)
 	}
 
-	return nil
+	return p.refreshDisplay(ctx)
 }
 
 // handleZeroSpeed handles the case when no speed is detected
@@ -297,62 +740,167 @@ func (p *PlaybackController) handleZeroSpeed(ctx context.Context) error {
 
 	logger.Debug(ctx, logger.VIDEO, "no speed detected, pausing video")
 
-	if err := p.updateDisplay(ctx, 0.0, 0.0); err != nil {
-		return fmt.Errorf(errFormat, errOSDUpdate, err)
+	p.stopResumeFade(ctx)
+
+	state := playerState{paused: true}
+	state.osdText, state.updateOSD = p.buildOSDText(ctx, 0.0, 0.0)
+
+	if err := p.applyState(state); err != nil {
+		return fmt.Errorf(errFormat, "failed to pause video", err)
 	}
 
-	return p.player.setPause(true)
+	// Any speed above the speed threshold resumes playback
+	p.speedState.last = 0
+
+	return nil
 }
 
 // shouldUpdateSpeed determines if the playback speed needs updating
 func (p *PlaybackController) shouldUpdateSpeed() bool {
 
-	// Always update the speed if "display time remaining" option is enabled
-	// Else update only if the speed delta is greater than the configured speed threshold
-	return p.osdConfig.displayTimeRemaining ||
-		(math.Abs(p.speedState.current-p.speedState.last) > p.speedConfig.SpeedThreshold)
+	// Update only if the speed delta is greater than the configured speed threshold (OSD refreshes
+	// are handled separately by refreshDisplay)
+	return math.Abs(p.speedState.current-p.speedState.last) > p.speedConfig.SpeedThreshold
+}
+
+// refreshDisplay refreshes the on-screen display (e.g., the time remaining) without sending a
+// speed update to the media player
+func (p *PlaybackController) refreshDisplay(ctx context.Context) error {
+
+	// Only the time remaining and interval countdown change between speed updates (and a paused
+	// state is already displayed)
+	countdown := p.osdConfig.displayTimeRemaining || (p.intervals.timer != nil && p.intervals.timer.Enabled())
+	if !p.osdConfig.showOSD || !countdown || p.speedState.last == 0 {
+		return nil
+	}
+
+	playbackSpeed, _ := p.clampPlaybackSpeed(p.speedState.last * p.speedUnitMultiplier)
+
+	if err := p.updateDisplay(ctx, p.speedState.last, playbackSpeed); err != nil {
+		return fmt.Errorf(errFormat, errOSDUpdate, err)
+	}
+
+	return nil
 }
 
 // updateSpeed adjusts the playback speed based on current speed
 func (p *PlaybackController) updateSpeed(ctx context.Context) error {
 
-	// Update the playback speed based on current speed and unit multiplier
-	playbackSpeed := p.PlaybackSpeed()
+	// Update the playback speed based on current speed and unit multiplier (eased in when playback
+	// resumes after a pause)
+	p.startResumeFade(ctx)
+	playbackSpeed := p.resumeFadeSpeed(ctx, p.PlaybackSpeed())
 
 	logger.Debug(ctx, logger.VIDEO, fmt.Sprintf(logger.Cyan+"updating video playback speed to %.2fx...", playbackSpeed))
 
-	if err := p.player.setSpeed(playbackSpeed); err != nil {
+	// Send the playback speed, pause state, and OSD text to the media player as a single update
+	state := playerState{speed: playbackSpeed, paused: false}
+	state.osdText, state.updateOSD = p.buildOSDText(ctx, p.speedState.current, playbackSpeed)
+
+	if err := p.applyState(state); err != nil {
 		return fmt.Errorf(errFormat, "failed to set playback speed", err)
 	}
 
-	if p.osdConfig.showOSD {
-		if err := p.updateDisplay(ctx, p.speedState.current, playbackSpeed); err != nil {
-			return fmt.Errorf(errFormat, errOSDUpdate, err)
-		}
+	p.speedState.last = p.speedState.current
+	p.recordLatency()
+	p.updateInterpolation(ctx, playbackSpeed)
+
+	return nil
+}
+
+// applyState sends the playback state to the media player, skipping any playback speed, pause
+// state, or OSD text unchanged from that last sent
+func (p *PlaybackController) applyState(state playerState) error {
+
+	if state.speed == p.commanded.speed {
+		state.speed = 0
 	}
 
-	p.speedState.last = p.speedState.current
+	state.updatePause = !p.commanded.known || state.paused != p.commanded.paused
+	state.updateOSD = state.updateOSD && state.osdText != p.commanded.osdText
+
+	if state.speed == 0 && !state.updatePause && !state.updateOSD {
+		return nil
+	}
+
+	if err := p.player.applyState(state); err != nil {
+		return err
+	}
+
+	if state.speed > 0 {
+		p.commanded.speed = state.speed
+	}
+
+	if state.updatePause {
+		p.commanded.paused, p.commanded.known = state.paused, true
+	}
+
+	if state.updateOSD {
+		p.commanded.osdText = state.osdText
+	}
 
-	return p.player.setPause(false)
+	return nil
 }
 
 // updateDisplay updates the on-screen display
 func (p *PlaybackController) updateDisplay(ctx context.Context, cycleSpeed, playbackSpeed float64) error {
 
-	if !p.osdConfig.showOSD {
+	osdText, ok := p.buildOSDText(ctx, cycleSpeed, playbackSpeed)
+	if !ok || osdText == p.commanded.osdText {
 		return nil
 	}
 
+	if err := p.player.showOSDText(osdText); err != nil {
+		return err
+	}
+
+	p.commanded.osdText = osdText
+
+	return nil
+}
+
+// buildOSDText builds the on-screen display text, returning false if the OSD isn't shown or its
+// refresh is throttled
+func (p *PlaybackController) buildOSDText(ctx context.Context, cycleSpeed, playbackSpeed float64) (string, bool) {
+
+	if !p.osdConfig.showOSD {
+		return "", false
+	}
+
+	// Throttle OSD refreshes (if configured), but always display a paused state
+	if playbackSpeed != 0 && !p.osdRefreshDue() {
+		return "", false
+	}
+
 	var osdText strings.Builder
 
 	if p.osdConfig.displayCycleSpeed {
-		fmt.Fprintf(&osdText, "Cycle Speed: %.1f %s\n", cycleSpeed, p.speedConfig.SpeedUnits)
+
+		speedText := fmt.Sprintf("Cycle Speed: %s %s", units.FormatSpeed(cycleSpeed, p.speedConfig.SpeedUnits), p.speedConfig.SpeedUnits)
+
+		// Color the cycle speed by speed zone (if configured)
+		if zone := p.speedConfig.SpeedZone(cycleSpeed); zone > 0 {
+			speedText = p.player.styleOSDText(speedText, config.SpeedZoneColors[zone-1])
+		}
+
+		fmt.Fprintf(&osdText, "%s\n", speedText)
+	}
+
+	if p.osdConfig.displaySpeedStats {
+		fmt.Fprintf(&osdText, "Avg / Max Speed: %s / %s %s\n", units.FormatSpeed(p.speedState.average, p.speedConfig.SpeedUnits),
+			units.FormatSpeed(p.speedState.maximum, p.speedConfig.SpeedUnits), p.speedConfig.SpeedUnits)
 	}
 
 	if p.osdConfig.displayPlaybackSpeed {
 		fmt.Fprintf(&osdText, "Playback Speed: %.2fx\n", playbackSpeed)
 	}
 
+	// Indicate when the playback speed is held at its configured minimum or maximum (a race
+	// plays at a fixed pace)
+	if _, limit := p.clampPlaybackSpeed(cycleSpeed * p.speedUnitMultiplier); limit != "" && !p.racing() {
+		fmt.Fprintf(&osdText, "Playback Speed Limit: %s %.2fx\n", limit, playbackSpeed)
+	}
+
 	if p.osdConfig.displayTimeRemaining {
 
 		if timeRemaining, err := p.timeRemaining(); err == nil {
@@ -364,17 +912,34 @@ func (p *PlaybackController) updateDisplay(ctx context.Context, cycleSpeed, play
 
 	}
 
+	osdText.WriteString(p.goalOSDText())
+	osdText.WriteString(p.intervalOSDText())
+	osdText.WriteString(p.raceOSDText())
+	osdText.WriteString(p.outdoorOSDText())
+
 	// Display "PAUSED" if the playback speed is 0
-	if cycleSpeed == 0 {
+	if playbackSpeed == 0 {
 		fmt.Fprintf(&osdText, "PAUSED")
 	}
 
-	return p.player.showOSDText(osdText.String())
+	return osdText.String(), true
 }
 
-// timeRemaining calculates the time remaining in the video
-func (p *PlaybackController) timeRemaining() (int64, error) {
-	return p.player.timeRemaining()
+// osdRefreshDue reports whether enough time has elapsed since the last OSD refresh
+func (p *PlaybackController) osdRefreshDue() bool {
+
+	if p.osdConfig.updateInterval <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(p.speedState.osdUpdated) < p.osdConfig.updateInterval {
+		return false
+	}
+
+	p.speedState.osdUpdated = now
+
+	return true
 }
 
 // logDebugInfo logs debug information about current speeds
@@ -3,6 +3,13 @@ package video
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -17,7 +24,14 @@ type mockMediaPlayer struct {
 	mu                   sync.Mutex
 	calls                map[string]int
 	lastShowText         string
+	lastOSDMessage       string
+	lastOverlay          string
+	lastOSDColor         string
+	lastVolume           int
+	lastAudioGain        float64
+	lastMute             bool
 	lastSpeed            float64
+	lastSeekTo           float64
 	lastPauseState       bool
 	validateVideoFileErr error
 	loadFileErr          error
@@ -30,10 +44,13 @@ type mockMediaPlayer struct {
 	setPauseErr          error
 	showTextErr          error
 	remainingTime        int64
+	interpolation        bool
+	noInterpolation      bool // The video output doesn't support frame interpolation
+	droppedFrameCount    int64
 	remainingTimeErr     error
 	playbackPos          int64
 	playbackPosErr       error
-	eventChan            chan *playerEvent
+	eventChan            chan playerEvent
 }
 
 // updateSpeedTestCase defines a test case for updateSpeedFromController
@@ -79,6 +96,10 @@ func createTestConfig() (config.VideoConfig, config.SpeedConfig) {
 		SeekToPosition:    testConfigData.SeekToPosition,
 		UpdateIntervalSec: testConfigData.updateInterval,
 		SpeedMultiplier:   testConfigData.speedMultiplier,
+		HardwareDecoding:  config.HWDecOff,
+		Audio: config.VideoAudioConfig{
+			Volume: 80,
+		},
 		OnScreenDisplay: config.VideoOSDConfig{
 			FontSize:             24,
 			DisplayCycleSpeed:    true,
@@ -100,7 +121,7 @@ func createTestConfig() (config.VideoConfig, config.SpeedConfig) {
 func newMockMediaPlayer() *mockMediaPlayer {
 	return &mockMediaPlayer{
 		calls:     make(map[string]int),
-		eventChan: make(chan *playerEvent, 1),
+		eventChan: make(chan playerEvent, 1),
 	}
 }
 
@@ -122,7 +143,7 @@ func (m *mockMediaPlayer) callCount(name string) int {
 	return m.calls[name]
 }
 
-// waitEvent waits for a player event or times out
+// terminatePlayer terminates the media player
 func (m *mockMediaPlayer) terminatePlayer() {
 	m.recordCall("terminatePlayer")
 }
@@ -159,6 +180,14 @@ func (m *mockMediaPlayer) setPlaybackSize(_ float64) error {
 	return m.setFullscreenErr
 }
 
+// setWindowPosition sets the position of the video window
+func (m *mockMediaPlayer) setWindowPosition(_, _ int) error {
+
+	m.recordCall("setWindowPosition")
+
+	return nil
+}
+
 // setKeepOpen configures whether the player window stays open after playback
 func (m *mockMediaPlayer) setKeepOpen(_ bool) error {
 
@@ -183,6 +212,15 @@ func (m *mockMediaPlayer) seek(_ string) error {
 	return m.seekErr
 }
 
+// seekTo seeks to an absolute playback position during playback
+func (m *mockMediaPlayer) seekTo(seconds float64) error {
+
+	m.recordCall("seekTo")
+	m.lastSeekTo = seconds
+
+	return m.seekErr
+}
+
 // setSpeed sets the playback speed of the video
 func (m *mockMediaPlayer) setSpeed(speed float64) error {
 
@@ -201,6 +239,35 @@ func (m *mockMediaPlayer) setPause(pause bool) error {
 	return m.setPauseErr
 }
 
+// applyState applies the playback state using the individual player calls (as a player without
+// coalesced updates would)
+func (m *mockMediaPlayer) applyState(state playerState) error {
+
+	m.recordCall("applyState")
+
+	if state.speed > 0 {
+
+		if err := m.setSpeed(state.speed); err != nil {
+			return err
+		}
+
+	}
+
+	if state.updatePause {
+
+		if err := m.setPause(state.paused); err != nil {
+			return err
+		}
+
+	}
+
+	if state.updateOSD {
+		return m.showOSDText(state.osdText)
+	}
+
+	return nil
+}
+
 // showOSDText displays text on the OSD
 func (m *mockMediaPlayer) showOSDText(text string) error {
 
@@ -210,6 +277,133 @@ func (m *mockMediaPlayer) showOSDText(text string) error {
 	return m.showTextErr
 }
 
+// styleOSDText returns the OSD text unchanged (the mock doesn't style OSD text)
+func (m *mockMediaPlayer) styleOSDText(text, _ string) string {
+
+	m.recordCall("styleOSDText")
+
+	return text
+}
+
+// setOSDColor sets the default color of OSD text
+func (m *mockMediaPlayer) setOSDColor(color string) error {
+
+	m.recordCall("setOSDColor")
+	m.lastOSDColor = color
+
+	return m.SetOSDErr
+}
+
+// showOverlay draws ASS-formatted graphics over the video
+func (m *mockMediaPlayer) showOverlay(assText string) error {
+
+	m.recordCall("showOverlay")
+	m.lastOverlay = assText
+
+	return m.showTextErr
+}
+
+// showOSDMessage displays a transient message on the OSD
+func (m *mockMediaPlayer) showOSDMessage(text string, _ int) error {
+
+	m.recordCall("showOSDMessage")
+	m.lastOSDMessage = text
+
+	return m.showTextErr
+}
+
+// setSubtitles configures subtitle display
+func (m *mockMediaPlayer) setSubtitles(_ string, _ bool) error {
+
+	m.recordCall("setSubtitles")
+
+	return nil
+}
+
+// setAudioTrack selects the audio track to play
+func (m *mockMediaPlayer) setAudioTrack(_ int) error {
+
+	m.recordCall("setAudioTrack")
+
+	return nil
+}
+
+// setVolume sets the audio playback volume
+func (m *mockMediaPlayer) setVolume(volume int) error {
+
+	m.recordCall("setVolume")
+	m.lastVolume = volume
+
+	return nil
+}
+
+// setMute sets the audio mute state
+func (m *mockMediaPlayer) setMute(muted bool) error {
+
+	m.recordCall("setMute")
+	m.lastMute = muted
+
+	return nil
+}
+
+// supportsInterpolation reports whether the video output supports frame interpolation
+func (m *mockMediaPlayer) supportsInterpolation() bool {
+
+	m.recordCall("supportsInterpolation")
+
+	return !m.noInterpolation
+}
+
+// configureInterpolation prepares frame interpolation
+func (m *mockMediaPlayer) configureInterpolation() error {
+
+	m.recordCall("configureInterpolation")
+
+	return nil
+}
+
+// setInterpolation sets the frame interpolation state
+func (m *mockMediaPlayer) setInterpolation(enabled bool) error {
+
+	m.recordCall("setInterpolation")
+	m.interpolation = enabled
+
+	return nil
+}
+
+// droppedFrames gets the number of dropped video frames
+func (m *mockMediaPlayer) droppedFrames() (int64, error) {
+
+	m.recordCall("droppedFrames")
+
+	return m.droppedFrameCount, nil
+}
+
+// setAudioGain sets the audio volume gain
+func (m *mockMediaPlayer) setAudioGain(gain float64) error {
+
+	m.recordCall("setAudioGain")
+	m.lastAudioGain = gain
+
+	return nil
+}
+
+// setPitchCorrection sets the audio pitch correction state
+func (m *mockMediaPlayer) setPitchCorrection(_ bool) error {
+
+	m.recordCall("setPitchCorrection")
+
+	return nil
+}
+
+// duration gets the total duration of the video
+func (m *mockMediaPlayer) duration() (int64, error) {
+
+	m.recordCall("duration")
+
+	return m.playbackPos + m.remainingTime, m.playbackPosErr
+}
+
 // timeRemaining gets the remaining time of the video
 func (m *mockMediaPlayer) timeRemaining() (int64, error) {
 
@@ -226,17 +420,12 @@ func (m *mockMediaPlayer) playbackPosition() (int64, error) {
 	return m.playbackPos, m.playbackPosErr
 }
 
-// waitEvent waits for a player event or times out
-func (m *mockMediaPlayer) waitEvent(timeout float64) *playerEvent {
+// events returns the channel on which player events are delivered
+func (m *mockMediaPlayer) events() <-chan playerEvent {
 
-	m.recordCall("waitEvent")
+	m.recordCall("events")
 
-	select {
-	case e := <-m.eventChan:
-		return e
-	case <-time.After(time.Duration(timeout * float64(time.Second))):
-		return &playerEvent{id: eventNone}
-	}
+	return m.eventChan
 }
 
 // TestNewPlaybackController tests the NewPlaybackController function
@@ -290,8 +479,10 @@ func setupTestController(t *testing.T) (*PlaybackController, *mockMediaPlayer, *
 			displayPlaybackSpeed: vc.OnScreenDisplay.DisplayPlaybackSpeed,
 			displayTimeRemaining: vc.OnScreenDisplay.DisplayTimeRemaining,
 		},
-		player:     mockPlayer,
-		speedState: &speedState{},
+		audioConfig: newAudioConfig(vc.Audio),
+		player:      mockPlayer,
+		speedState:  &speedState{},
+		audioState:  newAudioState(vc.Audio),
 	}
 
 	return controller, mockPlayer, speedCtrl
@@ -328,17 +519,43 @@ func TestPlaybackControllerStart(t *testing.T) {
 	})
 }
 
+// TestEventLoopVideoComplete tests that the event loop stops as soon as the media player delivers
+// an end-of-file event
+func TestEventLoopVideoComplete(t *testing.T) {
+
+	controller, mockPlayer, speedCtrl := setupTestController(t)
+
+	ctx, cancel := context.WithTimeout(logger.BackgroundCtx, 5*time.Second)
+	defer cancel()
+
+	mockPlayer.eventChan <- playerEvent{id: eventEndFile}
+
+	if err := controller.eventLoop(ctx, speedCtrl); !errors.Is(err, ErrVideoComplete) {
+		t.Errorf("eventLoop() error = %v, want %v", err, ErrVideoComplete)
+	}
+
+	if ctx.Err() != nil {
+		t.Error("expected eventLoop() to return before the context timed out")
+	}
+
+}
+
 // verifyInitializationCalls checks that the expected player methods are called during startup
 func verifyInitializationCalls(t *testing.T, mockPlayer *mockMediaPlayer) {
 
 	t.Helper()
 	expectedCalls := map[string]int{
-		"loadFile":        1,
-		"setupEvents":     1,
-		"setPlaybackSize": 1,
-		"setKeepOpen":     1,
-		"setOSD":          1,
-		"seek":            1,
+		"loadFile":           1,
+		"setupEvents":        1,
+		"setPlaybackSize":    1,
+		"setKeepOpen":        1,
+		"setOSD":             1,
+		"seek":               1,
+		"setVolume":          1,
+		"setMute":            1,
+		"setAudioTrack":      1,
+		"setPitchCorrection": 1,
+		"setSubtitles":       1,
 	}
 
 	for method, count := range expectedCalls {
@@ -471,27 +688,91 @@ func TestUpdateSpeedFromController(t *testing.T) {
 
 }
 
-// TestHandleZeroSpeed tests the handleZeroSpeed method
-func TestFormatSeconds(t *testing.T) {
+// TestRefreshDisplay tests that the time remaining is refreshed without a playback speed update
+func TestRefreshDisplay(t *testing.T) {
 
-	testCases := []struct {
-		name     string
-		seconds  int64
-		expected string
+	vc, sc := createTestConfig()
+	sc.SpeedThreshold = 0.2
+	mockPlayer := newMockMediaPlayer()
+	mockPlayer.remainingTime = 125 // 00:02:05
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		osdConfig:   osdConfig{showOSD: true, displayTimeRemaining: true},
+		player:      mockPlayer,
+		speedState:  &speedState{last: 10.0},
+	}
+	controller.speedUnitMultiplier = 0.1 // For simplicity
+
+	speedCtrl := speed.NewSpeedController(logger.BackgroundCtx, 5)
+
+	// Fill the speed controller's buffer with a speed below the speed threshold
+	for range 5 {
+		speedCtrl.UpdateSpeed(logger.BackgroundCtx, 10.1)
+	}
+
+	if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedCtrl); err != nil {
+		t.Fatalf("updateSpeedFromController() returned an error: %v", err)
+	}
+
+	if mockPlayer.callCount(setSpeed) > 0 {
+		t.Errorf("expected setSpeed not to be called, but was called %d times", mockPlayer.callCount(setSpeed))
+	}
+
+	if mockPlayer.lastShowText != "Time Remaining: 00:02:05\n" {
+		t.Errorf("unexpected OSD text %q", mockPlayer.lastShowText)
+	}
+
+}
+
+// TestClampPlaybackSpeed tests that the playback speed is limited to the configured minimum and
+// maximum playback speeds, and that the OSD shows the limit applied
+func TestClampPlaybackSpeed(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	vc.MinPlaybackSpeed = 0.5
+	vc.MaxPlaybackSpeed = 2.0
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		speed     float64
+		wantSpeed float64
+		wantLimit string
 	}{
-		{"zero seconds", 0, "00:00:00"},
-		{"less than a minute", 59, "00:00:59"},
-		{"one minute", 60, "00:01:00"},
-		{"one hour", 3600, "01:00:00"},
-		{"complex time", 3661, "01:01:01"},
-		{"large time", 86399, "23:59:59"}, // 24 hours - 1 second
+		{"below minimum", 2.0, 0.5, playbackLimitMin},
+		{"within limits", 15.0, 1.5, ""},
+		{"above maximum", 30.0, 2.0, playbackLimitMax},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
+	// Run tests
+	for _, tt := range tests {
 
-			if got := formatSeconds(tc.seconds); got != tc.expected {
-				t.Errorf("formatSeconds(%d) = %q, want %q", tc.seconds, got, tc.expected)
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockPlayer := newMockMediaPlayer()
+
+			controller := &PlaybackController{
+				videoConfig: vc,
+				speedConfig: sc,
+				osdConfig:   osdConfig{showOSD: true, displayPlaybackSpeed: true},
+				player:      mockPlayer,
+				speedState:  &speedState{current: tt.speed},
+			}
+			controller.speedUnitMultiplier = 0.1 // For simplicity
+
+			if err := controller.updateSpeed(logger.BackgroundCtx); err != nil {
+				t.Fatalf("updateSpeed() returned an error: %v", err)
+			}
+
+			if math.Abs(mockPlayer.lastSpeed-tt.wantSpeed) > 1e-9 {
+				t.Errorf("playback speed = %.2f, want %.2f", mockPlayer.lastSpeed, tt.wantSpeed)
+			}
+
+			wantLimit := tt.wantLimit != ""
+			if gotLimit := strings.Contains(mockPlayer.lastShowText, "Playback Speed Limit: "+tt.wantLimit); gotLimit != wantLimit {
+				t.Errorf("unexpected OSD text %q (want limit %q)", mockPlayer.lastShowText, tt.wantLimit)
 			}
 
 		})
@@ -499,64 +780,1126 @@ func TestFormatSeconds(t *testing.T) {
 
 }
 
-// TestUpdateDisplay tests the updateDisplay method of PlaybackController
-func TestUpdateDisplay(t *testing.T) {
+// TestFrameInterpolation tests that frame interpolation is enabled only while playback runs
+// slower than the frame interpolation speed, and that dropped frames generate a warning
+func TestFrameInterpolation(t *testing.T) {
 
 	vc, sc := createTestConfig()
+	vc.InterpolationSpeed = 0.5
+
 	mockPlayer := newMockMediaPlayer()
 
 	controller := &PlaybackController{
 		videoConfig: vc,
 		speedConfig: sc,
-		osdConfig: osdConfig{
-			showOSD:              true,
-			displayCycleSpeed:    true,
-			displayPlaybackSpeed: true,
-			displayTimeRemaining: true,
-		},
-		player:     mockPlayer,
-		speedState: &speedState{},
+		player:      mockPlayer,
+		speedState:  &speedState{},
 	}
+	controller.speedUnitMultiplier = 0.1 // For simplicity
+	controller.configureInterpolation(logger.BackgroundCtx)
 
-	t.Run("paused display", func(t *testing.T) {
+	// Define test cases (run in order against the same controller)
+	tests := []struct {
+		name              string
+		speed             float64
+		wantInterpolation bool
+	}{
+		{"normal playback", 10.0, false},
+		{"slow playback", 3.0, true},
+		{"still slow playback", 4.0, true},
+		{"normal playback again", 8.0, false},
+	}
 
-		err := controller.updateDisplay(logger.BackgroundCtx, 0.0, 0.0)
-		if err != nil {
-			t.Fatalf("updateDisplay failed: %v", err)
-		}
+	// Run tests
+	for _, tt := range tests {
 
-		if mockPlayer.lastShowText != "Cycle Speed: 0.0 mph\nPlayback Speed: 0.00x\nTime Remaining: 00:00:00\nPAUSED" {
-			t.Errorf("expected OSD text 'Paused', got %q", mockPlayer.lastShowText)
-		}
+		t.Run(tt.name, func(t *testing.T) {
 
-	})
+			controller.speedState.current = tt.speed
 
-	t.Run("active display", func(t *testing.T) {
+			if err := controller.updateSpeed(logger.BackgroundCtx); err != nil {
+				t.Fatalf("updateSpeed() returned an error: %v", err)
+			}
 
-		mockPlayer.remainingTime = 125 // 00:02:05
-		err := controller.updateDisplay(logger.BackgroundCtx, 15.5, 1.55)
-		if err != nil {
-			t.Fatalf("updateDisplay failed: %v", err)
-		}
+			if mockPlayer.interpolation != tt.wantInterpolation {
+				t.Errorf("frame interpolation = %t, want %t", mockPlayer.interpolation, tt.wantInterpolation)
+			}
 
-		var expectedText bytes.Buffer
-		var errFailedToWriteExpectedText = "failed to write expected text: %v"
+		})
+	}
 
-		if _, err := expectedText.WriteString("Cycle Speed: 15.5 mph\n"); err != nil {
-			t.Fatalf(errFailedToWriteExpectedText, err)
-		}
+	if got := mockPlayer.callCount("setInterpolation"); got != 2 {
+		t.Errorf("expected setInterpolation to be called 2 times, got %d", got)
+	}
 
-		if _, err := expectedText.WriteString("Playback Speed: 1.55x\n"); err != nil {
-			t.Fatalf(errFailedToWriteExpectedText, err)
-		}
+	// Frames dropped while interpolating generate a (single) warning
+	controller.speedState.current = 3.0
+	if err := controller.updateSpeed(logger.BackgroundCtx); err != nil {
+		t.Fatalf("updateSpeed() returned an error: %v", err)
+	}
 
-		if _, err := expectedText.WriteString("Time Remaining: 00:02:05\n"); err != nil {
-			t.Fatalf(errFailedToWriteExpectedText, err)
-		}
+	mockPlayer.droppedFrameCount = maxInterpolationDrops + 1
+	controller.checkInterpolationDrops(logger.BackgroundCtx)
 
-		if mockPlayer.lastShowText != expectedText.String() {
-			t.Errorf("unexpected OSD text\ngot:  %q\nwant: %q", mockPlayer.lastShowText, expectedText.String())
-		}
-	})
+	if !controller.interpolation.warned {
+		t.Error("expected a warning for frames dropped with frame interpolation enabled")
+	}
+
+	// A video output without frame interpolation support leaves frame interpolation disabled
+	unsupported := newMockMediaPlayer()
+	unsupported.noInterpolation = true
+	controller = &PlaybackController{videoConfig: vc, player: unsupported}
+	controller.configureInterpolation(logger.BackgroundCtx)
+	controller.updateInterpolation(logger.BackgroundCtx, 0.3)
+
+	if unsupported.callCount("setInterpolation") != 0 {
+		t.Error("expected frame interpolation to stay disabled without video output support")
+	}
+
+}
+
+// TestSpeedZoneOSD tests that the OSD cycle speed is styled by speed zone (only when speed zones
+// are configured)
+func TestSpeedZoneOSD(t *testing.T) {
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		zones      []float64
+		wantStyled int
+	}{
+		{"no speed zones", nil, 0},
+		{"speed zones", []float64{10.0, 15.0, 20.0}, 1},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			controller, mockPlayer, _ := setupTestController(t)
+			controller.speedConfig.SpeedZones = tt.zones
+
+			if _, ok := controller.buildOSDText(logger.BackgroundCtx, 12.0, 1.0); !ok {
+				t.Fatal("buildOSDText() returned no OSD text")
+			}
+
+			if got := mockPlayer.callCount("styleOSDText"); got != tt.wantStyled {
+				t.Errorf("styleOSDText called %d times, want %d", got, tt.wantStyled)
+			}
+
+		})
+	}
+
+}
+
+// TestSessionGoal tests that reaching the session goal is reported (once) as an event and an OSD
+// message, and that paused playback doesn't count toward the goal
+func TestSessionGoal(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	var events []string
+
+	controller := &PlaybackController{
+		videoConfig:  vc,
+		speedConfig:  sc,
+		player:       mockPlayer,
+		speedState:   &speedState{current: 10.0},
+		eventHandler: func(message string) { events = append(events, message) },
+	}
+
+	// A goal of 0.06 minutes is reached after 3.6 seconds of riding
+	goal := config.GoalConfig{Type: config.GoalTypeTime, Target: 0.06, RiderWeightKG: 75}
+	controller.SetGoalTracker(speed.NewGoalTracker(goal, sc))
+
+	ride := func(elapsed time.Duration) {
+		controller.goal.updated = time.Now().Add(-elapsed)
+		controller.updateGoal(logger.BackgroundCtx)
+	}
+
+	ride(2 * time.Second)
+
+	if !strings.HasPrefix(controller.goalOSDText(), "Goal: ") || len(events) != 0 {
+		t.Fatalf("goal reached early (OSD %q, events %v)", controller.goalOSDText(), events)
+	}
+
+	// Paused playback doesn't count toward the goal
+	controller.paused.Store(true)
+	ride(5 * time.Second)
+	controller.paused.Store(false)
+
+	if len(events) != 0 {
+		t.Fatalf("goal reached while paused (events %v)", events)
+	}
+
+	ride(2 * time.Second)
+	ride(2 * time.Second)
+
+	if len(events) != 1 || !strings.HasPrefix(events[0], "Goal reached") {
+		t.Errorf("events = %v, want a single goal reached event", events)
+	}
+
+	if !strings.HasPrefix(mockPlayer.lastOSDMessage, "Goal reached") {
+		t.Errorf("OSD message = %q, want a goal reached message", mockPlayer.lastOSDMessage)
+	}
+
+	if !strings.HasPrefix(controller.goalOSDText(), "Goal Reached: ") {
+		t.Errorf("goalOSDText() = %q, want a goal reached line", controller.goalOSDText())
+	}
+
+}
+
+// TestIntervalTimer tests that interval transitions are reported as events, OSD messages and
+// handler calls, and that the interval timer holds while stopped
+func TestIntervalTimer(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	var events []string
+	var transitions []speed.IntervalState
+
+	controller := &PlaybackController{
+		videoConfig:  vc,
+		speedConfig:  sc,
+		player:       mockPlayer,
+		speedState:   &speedState{current: 10.0},
+		eventHandler: func(message string) { events = append(events, message) },
+	}
+
+	timer := speed.NewIntervalTimer(config.IntervalConfig{HardSecs: 3, EasySecs: 5})
+	controller.SetIntervalTimer(timer, func(state speed.IntervalState) { transitions = append(transitions, state) })
+
+	ride := func(elapsed time.Duration) {
+		controller.intervals.updated = time.Now().Add(-elapsed)
+		controller.updateIntervals(logger.BackgroundCtx)
+	}
+
+	ride(2 * time.Second)
+
+	if !strings.HasPrefix(controller.intervalOSDText(), "Interval: HARD 0:01") || len(events) != 0 {
+		t.Fatalf("unexpected hard interval (OSD %q, events %v)", controller.intervalOSDText(), events)
+	}
+
+	// The interval timer holds while stopped
+	controller.speedState.current = 0
+	ride(5 * time.Second)
+	controller.speedState.current = 10.0
+
+	if len(transitions) != 0 {
+		t.Fatalf("interval transition while stopped (transitions %v)", transitions)
+	}
+
+	ride(2 * time.Second)
+
+	if len(events) != 1 || events[0] != "Interval 2: EASY" {
+		t.Errorf("events = %v, want a single easy interval event", events)
+	}
+
+	if len(transitions) != 1 || transitions[0].Phase != speed.IntervalEasy {
+		t.Errorf("transitions = %v, want a single easy interval transition", transitions)
+	}
+
+	if mockPlayer.lastOSDMessage != "Interval 2: EASY" {
+		t.Errorf("OSD message = %q, want the easy interval message", mockPlayer.lastOSDMessage)
+	}
+
+	if !strings.HasPrefix(controller.intervalOSDText(), "Interval: EASY 0:04") {
+		t.Errorf("intervalOSDText() = %q, want the easy interval countdown", controller.intervalOSDText())
+	}
+
+	if mockPlayer.callCount("styleOSDText") == 0 {
+		t.Error("interval countdown wasn't styled")
+	}
+
+}
+
+// TestLaps tests that laps marked automatically and manually are reported as events, OSD messages
+// and handler calls
+func TestLaps(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	sc.SpeedUnits = config.SpeedUnitsKMH
+	mockPlayer := newMockMediaPlayer()
+
+	var events []string
+	var laps []speed.Lap
+
+	controller := &PlaybackController{
+		videoConfig:  vc,
+		speedConfig:  sc,
+		player:       mockPlayer,
+		speedState:   &speedState{current: 30.0},
+		eventHandler: func(message string) { events = append(events, message) },
+	}
+
+	goal := speed.NewGoalTracker(config.GoalConfig{}, sc)
+	controller.SetGoalTracker(goal)
+	controller.SetLapTracker(speed.NewLapTracker(config.LapConfig{Minutes: 1}, sc.SpeedUnits), func(lap speed.Lap) { laps = append(laps, lap) })
+
+	// Ride 50 seconds: no lap yet
+	for range 5 {
+		goal.Update(30.0, 10*time.Second)
+		controller.updateLaps(logger.BackgroundCtx)
+	}
+
+	if len(laps) != 0 || len(events) != 0 {
+		t.Fatalf("lap marked early (laps %v, events %v)", laps, events)
+	}
+
+	// Ride another 10 seconds: the first (timed) lap
+	goal.Update(30.0, 10*time.Second)
+	controller.updateLaps(logger.BackgroundCtx)
+
+	if len(laps) != 1 || laps[0].Number != 1 || laps[0].Manual {
+		t.Fatalf("laps = %+v, want a single timed lap", laps)
+	}
+
+	if len(events) != 1 || events[0] != "Lap 1: 1:00, 0.5 km, 30.0 km/h" || mockPlayer.lastOSDMessage != events[0] {
+		t.Errorf("events = %v (OSD message %q), want the first lap stats", events, mockPlayer.lastOSDMessage)
+	}
+
+	// A manual lap is marked at the next update
+	goal.Update(30.0, 20*time.Second)
+	controller.laps.tracker.Mark()
+	controller.updateLaps(logger.BackgroundCtx)
+
+	if len(laps) != 2 || !laps[1].Manual || laps[1].RideTime != 20*time.Second {
+		t.Errorf("laps = %+v, want a manual second lap", laps)
+	}
+
+}
+
+// TestSkipRedundantCommands tests that unchanged pause states and playback speeds aren't re-sent
+// to the media player
+func TestSkipRedundantCommands(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		player:      mockPlayer,
+		speedState:  &speedState{},
+	}
+	controller.speedUnitMultiplier = 0.1 // For simplicity
+
+	// Define test cases (run in order against the same controller)
+	tests := []struct {
+		name       string
+		speed      float64
+		wantSpeeds int
+		wantPauses int
+	}{
+		{"first update", 10.0, 1, 1},
+		{"unchanged update", 10.0, 1, 1},
+		{"changed speed", 12.0, 2, 1},
+		{"first pause", 0.0, 2, 2},
+		{"repeated pause", 0.0, 2, 2},
+		{"resume at same speed", 12.0, 2, 3},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			controller.speedState.current = tt.speed
+
+			var err error
+			if tt.speed == 0 {
+				err = controller.handleZeroSpeed(logger.BackgroundCtx)
+			} else {
+				err = controller.updateSpeed(logger.BackgroundCtx)
+			}
+
+			if err != nil {
+				t.Fatalf("update returned an error: %v", err)
+			}
+
+			if got := mockPlayer.callCount(setSpeed); got != tt.wantSpeeds {
+				t.Errorf("setSpeed called %d time(s), want %d", got, tt.wantSpeeds)
+			}
+
+			if got := mockPlayer.callCount(setPause); got != tt.wantPauses {
+				t.Errorf("setPause called %d time(s), want %d", got, tt.wantPauses)
+			}
+
+		})
+	}
+
+}
+
+// TestHandleZeroSpeed tests the handleZeroSpeed method
+func TestFormatSeconds(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		seconds  int64
+		expected string
+	}{
+		{"zero seconds", 0, "00:00:00"},
+		{"less than a minute", 59, "00:00:59"},
+		{"one minute", 60, "00:01:00"},
+		{"one hour", 3600, "01:00:00"},
+		{"complex time", 3661, "01:01:01"},
+		{"large time", 86399, "23:59:59"}, // 24 hours - 1 second
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			if got := formatSeconds(tc.seconds); got != tc.expected {
+				t.Errorf("formatSeconds(%d) = %q, want %q", tc.seconds, got, tc.expected)
+			}
+
+		})
+	}
+
+}
+
+// TestSpeedUnitFactor tests the speedUnitFactor function
+func TestSpeedUnitFactor(t *testing.T) {
+
+	testCases := []struct {
+		speedUnits string
+		expected   float64
+	}{
+		{config.SpeedUnitsMPH, 1.0},
+		{config.SpeedUnitsKMH, 1.609344},
+		{config.SpeedUnitsMPS, 0.44704},
+		{config.SpeedUnitsRPM, 26.8224}, // 1 mph with a 1000 mm wheel circumference
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.speedUnits, func(t *testing.T) {
+
+			sc := config.SpeedConfig{SpeedUnits: tc.speedUnits, WheelCircumferenceMM: 1000}
+
+			if got := speedUnitFactor(sc); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("speedUnitFactor(%s) = %v, want %v", tc.speedUnits, got, tc.expected)
+			}
+
+		})
+	}
+
+}
+
+// TestUpdateDisplay tests the updateDisplay method of PlaybackController
+func TestUpdateDisplay(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		osdConfig: osdConfig{
+			showOSD:              true,
+			displayCycleSpeed:    true,
+			displayPlaybackSpeed: true,
+			displayTimeRemaining: true,
+		},
+		player:     mockPlayer,
+		speedState: &speedState{},
+	}
+
+	t.Run("paused display", func(t *testing.T) {
+
+		err := controller.updateDisplay(logger.BackgroundCtx, 0.0, 0.0)
+		if err != nil {
+			t.Fatalf("updateDisplay failed: %v", err)
+		}
+
+		if mockPlayer.lastShowText != "Cycle Speed: 0.0 mph\nPlayback Speed: 0.00x\nTime Remaining: 00:00:00\nPAUSED" {
+			t.Errorf("expected OSD text 'Paused', got %q", mockPlayer.lastShowText)
+		}
+
+	})
+
+	t.Run("active display", func(t *testing.T) {
+
+		mockPlayer.remainingTime = 125 // 00:02:05
+		err := controller.updateDisplay(logger.BackgroundCtx, 15.5, 1.55)
+		if err != nil {
+			t.Fatalf("updateDisplay failed: %v", err)
+		}
+
+		var expectedText bytes.Buffer
+		var errFailedToWriteExpectedText = "failed to write expected text: %v"
+
+		if _, err := expectedText.WriteString("Cycle Speed: 15.5 mph\n"); err != nil {
+			t.Fatalf(errFailedToWriteExpectedText, err)
+		}
+
+		if _, err := expectedText.WriteString("Playback Speed: 1.55x\n"); err != nil {
+			t.Fatalf(errFailedToWriteExpectedText, err)
+		}
+
+		if _, err := expectedText.WriteString("Time Remaining: 00:02:05\n"); err != nil {
+			t.Fatalf(errFailedToWriteExpectedText, err)
+		}
+
+		if mockPlayer.lastShowText != expectedText.String() {
+			t.Errorf("unexpected OSD text\ngot:  %q\nwant: %q", mockPlayer.lastShowText, expectedText.String())
+		}
+	})
+
+}
+
+// TestSetVolume tests the SetVolume and SetMute methods of PlaybackController
+func TestSetVolume(t *testing.T) {
+
+	controller, mockPlayer, _ := setupTestController(t)
+
+	t.Run("initial volume", func(t *testing.T) {
+
+		if volume, muted := controller.Volume(); volume != 80 || muted {
+			t.Errorf("Volume() = (%d, %v), want (80, false)", volume, muted)
+		}
+
+	})
+
+	t.Run("clamped volume", func(t *testing.T) {
+
+		if err := controller.SetVolume(150); err != nil {
+			t.Fatalf("SetVolume() failed: %v", err)
+		}
+
+		if mockPlayer.lastVolume != 100 {
+			t.Errorf("expected volume to be clamped to 100, got %d", mockPlayer.lastVolume)
+		}
+
+		if mockPlayer.lastOSDMessage != "Volume: 100%" {
+			t.Errorf("unexpected OSD message %q", mockPlayer.lastOSDMessage)
+		}
+
+	})
+
+	t.Run("muted", func(t *testing.T) {
+
+		if err := controller.SetMute(true); err != nil {
+			t.Fatalf("SetMute() failed: %v", err)
+		}
+
+		if volume, muted := controller.Volume(); volume != 100 || !muted {
+			t.Errorf("Volume() = (%d, %v), want (100, true)", volume, muted)
+		}
+
+		if mockPlayer.lastOSDMessage != "Volume: muted" {
+			t.Errorf("unexpected OSD message %q", mockPlayer.lastOSDMessage)
+		}
+
+	})
+
+}
+
+// TestLowPowerOSD tests that the low-power profile throttles OSD refreshes
+func TestLowPowerOSD(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	osd := osdConfig{
+		showOSD:               true,
+		displayCycleSpeed:     true,
+		displayTimeRemaining:  true,
+		displayMetricsOverlay: true,
+	}
+	applyLowPowerProfile(logger.BackgroundCtx, &osd)
+
+	if osd.displayTimeRemaining {
+		t.Error("expected time remaining display to be disabled in low-power profile")
+	}
+
+	if osd.displayMetricsOverlay {
+		t.Error("expected metrics overlay to be disabled in low-power profile")
+	}
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		osdConfig:   osd,
+		player:      mockPlayer,
+		speedState:  &speedState{},
+	}
+
+	t.Run("first refresh", func(t *testing.T) {
+
+		if err := controller.updateDisplay(logger.BackgroundCtx, 10.0, 1.0); err != nil {
+			t.Fatalf("updateDisplay failed: %v", err)
+		}
+
+		if mockPlayer.lastShowText != "Cycle Speed: 10.0 mph\n" {
+			t.Errorf("unexpected OSD text %q", mockPlayer.lastShowText)
+		}
+
+	})
+
+	t.Run("throttled refresh", func(t *testing.T) {
+
+		if err := controller.updateDisplay(logger.BackgroundCtx, 12.0, 1.2); err != nil {
+			t.Fatalf("updateDisplay failed: %v", err)
+		}
+
+		if mockPlayer.lastShowText != "Cycle Speed: 10.0 mph\n" {
+			t.Errorf("expected OSD refresh to be throttled, got %q", mockPlayer.lastShowText)
+		}
+
+	})
+
+	t.Run("paused refresh", func(t *testing.T) {
+
+		if err := controller.updateDisplay(logger.BackgroundCtx, 0.0, 0.0); err != nil {
+			t.Fatalf("updateDisplay failed: %v", err)
+		}
+
+		if mockPlayer.lastShowText != "Cycle Speed: 0.0 mph\nPAUSED" {
+			t.Errorf("expected paused state to bypass throttling, got %q", mockPlayer.lastShowText)
+		}
+
+	})
+
+}
+
+// TestMetricsOverlay tests the speed history and rendering of the metrics overlay
+func TestMetricsOverlay(t *testing.T) {
+
+	var overlay metricsOverlay
+
+	t.Run("single speed", func(t *testing.T) {
+
+		overlay.add(10.0)
+		assText := overlay.render(config.SpeedUnitsMPH)
+
+		if !strings.Contains(assText, "Cycle Speed: 10.0 mph") {
+			t.Errorf("expected overlay to contain the cycle speed, got %q", assText)
+		}
+
+		if overlay.sparkline() != "" {
+			t.Errorf("expected no speed graph for a single speed, got %q", overlay.sparkline())
+		}
+
+	})
+
+	t.Run("full history", func(t *testing.T) {
+
+		for i := range 2 * overlayHistoryLen {
+			overlay.add(float64(i))
+		}
+
+		if len(overlay.history) != overlayHistoryLen {
+			t.Errorf("expected history length %d, got %d", overlayHistoryLen, len(overlay.history))
+		}
+
+		if got := overlay.history[len(overlay.history)-1]; got != float64(2*overlayHistoryLen-1) {
+			t.Errorf("expected latest speed %d, got %v", 2*overlayHistoryLen-1, got)
+		}
+
+		if !strings.HasPrefix(overlay.sparkline(), "m 0 ") {
+			t.Errorf("expected speed graph to start at the graph origin, got %q", overlay.sparkline())
+		}
+
+	})
+
+}
+
+// TestUpdateMetricsOverlay tests that the metrics overlay is only drawn when enabled and playing
+func TestUpdateMetricsOverlay(t *testing.T) {
+
+	vc, sc := createTestConfig()
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		enabled   bool
+		paused    bool
+		wantCalls int
+	}{
+		{"disabled", false, false, 0},
+		{"enabled", true, false, 1},
+		{"enabled while paused", true, true, 0},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockPlayer := newMockMediaPlayer()
+			controller := &PlaybackController{
+				videoConfig: vc,
+				speedConfig: sc,
+				osdConfig:   osdConfig{displayMetricsOverlay: tt.enabled},
+				player:      mockPlayer,
+				speedState:  &speedState{current: 12.5},
+			}
+			controller.paused.Store(tt.paused)
+
+			controller.updateMetricsOverlay(logger.BackgroundCtx)
+
+			if got := mockPlayer.callCount("showOverlay"); got != tt.wantCalls {
+				t.Errorf("showOverlay called %d time(s), want %d", got, tt.wantCalls)
+			}
+
+			if tt.wantCalls > 0 && !strings.Contains(mockPlayer.lastOverlay, "Cycle Speed: 12.5 mph") {
+				t.Errorf("unexpected overlay %q", mockPlayer.lastOverlay)
+			}
+
+		})
+	}
+
+}
+
+// TestUpdateNightMode tests dimming the OSD text during the night mode hours of the session
+func TestUpdateNightMode(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	hour := time.Now().Hour()
+
+	// Night mode hours that are on (or off) for the next hour or so
+	nightOn := fmt.Sprintf("%d-%d", hour, (hour+23)%24)
+	nightOff := fmt.Sprintf("%d-%d", (hour+2)%24, (hour+3)%24)
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		hours     string
+		dimmed    bool
+		wantCalls int
+		wantColor string
+	}{
+		{"no night mode", "", false, 0, ""},
+		{"night mode begins", nightOn, false, 1, "#666666"},
+		{"night mode continues", nightOn, true, 0, ""},
+		{"night mode ends", nightOff, true, 1, osdDayColor},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			vc.OnScreenDisplay.NightModeHours = tt.hours
+			vc.OnScreenDisplay.NightBrightness = 40
+
+			mockPlayer := newMockMediaPlayer()
+			controller := &PlaybackController{
+				videoConfig: vc,
+				speedConfig: sc,
+				player:      mockPlayer,
+				night:       nightState{dimmed: tt.dimmed},
+			}
+
+			controller.updateNightMode(logger.BackgroundCtx)
+
+			if got := mockPlayer.callCount("setOSDColor"); got != tt.wantCalls {
+				t.Errorf("setOSDColor called %d time(s), want %d", got, tt.wantCalls)
+			}
+
+			if mockPlayer.lastOSDColor != tt.wantColor {
+				t.Errorf("OSD color = %q, want %q", mockPlayer.lastOSDColor, tt.wantColor)
+			}
+
+		})
+	}
+
+}
+
+// TestResumeFadeSpeed tests the playback speed and audio gain while resumed playback fades in
+func TestResumeFadeSpeed(t *testing.T) {
+
+	vc, sc := createTestConfig()
+
+	const playbackSpeed = 2.0
+
+	// Define test cases
+	tests := []struct {
+		name       string
+		fadeSecs   float64
+		elapsed    time.Duration
+		wantSpeed  float64
+		wantGain   float64
+		wantFading bool
+	}{
+		{"no fade-in", 0.0, 0, playbackSpeed, 0.0, false},
+		{"fade-in begins", 2.0, 0, resumeFadeStartSpeed, 0.0, true},
+		{"fade-in halfway", 2.0, time.Second, 1.25, 0.5, true},
+		{"fade-in complete", 2.0, 3 * time.Second, playbackSpeed, 1.0, false},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			vc.ResumeFadeSecs = tt.fadeSecs
+
+			mockPlayer := newMockMediaPlayer()
+			controller := &PlaybackController{
+				videoConfig: vc,
+				speedConfig: sc,
+				player:      mockPlayer,
+				commanded:   commandedState{paused: true, known: true},
+			}
+
+			controller.startResumeFade(logger.BackgroundCtx)
+			if controller.fadingIn() {
+				controller.fade.started = controller.fade.started.Add(-tt.elapsed)
+			}
+
+			if got := controller.resumeFadeSpeed(logger.BackgroundCtx, playbackSpeed); math.Abs(got-tt.wantSpeed) > 0.01 {
+				t.Errorf("resumeFadeSpeed() = %.2f, want %.2f", got, tt.wantSpeed)
+			}
+
+			if math.Abs(mockPlayer.lastAudioGain-tt.wantGain) > 0.01 {
+				t.Errorf("audio gain = %.2f, want %.2f", mockPlayer.lastAudioGain, tt.wantGain)
+			}
+
+			if got := controller.fadingIn(); got != tt.wantFading {
+				t.Errorf("fadingIn() = %v, want %v", got, tt.wantFading)
+			}
+
+		})
+	}
+
+}
+
+// TestPositionAndDuration tests the Position and Duration methods of PlaybackController
+func TestPositionAndDuration(t *testing.T) {
+
+	controller, mockPlayer, _ := setupTestController(t)
+	mockPlayer.playbackPos = 90
+	mockPlayer.remainingTime = 30
+
+	position, err := controller.Position()
+	if err != nil || position != 90*time.Second {
+		t.Errorf("Position() = (%v, %v), want (%v, nil)", position, err, 90*time.Second)
+	}
+
+	duration, err := controller.Duration()
+	if err != nil || duration != 2*time.Minute {
+		t.Errorf("Duration() = (%v, %v), want (%v, nil)", duration, err, 2*time.Minute)
+	}
+
+}
+
+// TestSeek tests the Seek method of PlaybackController
+func TestSeek(t *testing.T) {
+
+	controller, mockPlayer, _ := setupTestController(t)
+	mockPlayer.playbackPos = 90
+	mockPlayer.remainingTime = 30
+
+	// Define test cases (total video duration of 120 seconds)
+	tests := []struct {
+		name     string
+		position time.Duration
+		absolute bool
+		wantSeek float64
+		wantErr  error
+	}{
+		{"skip back", -30 * time.Second, false, 60, nil},
+		{"skip back past the start", -120 * time.Second, false, 0, nil},
+		{"absolute position", 10 * time.Second, true, 10, nil},
+		{"skip forward past the end", 30 * time.Second, false, 0, ErrSeekExceedsDuration},
+	}
+
+	// Run tests
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockPlayer.lastSeekTo = -1
+
+			err := controller.Seek(tt.position, tt.absolute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Seek() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && mockPlayer.lastSeekTo != tt.wantSeek {
+				t.Errorf("Seek() seeked to %v, want %v", mockPlayer.lastSeekTo, tt.wantSeek)
+			}
+
+		})
+	}
+
+}
+
+// TestCheckProgressMilestone tests that playback completion milestones are reported once each
+func TestCheckProgressMilestone(t *testing.T) {
+
+	controller, mockPlayer, _ := setupTestController(t)
+
+	var events []string
+	controller.SetEventHandler(func(message string) {
+		events = append(events, message)
+	})
+
+	// Define test cases (total video duration of 100 seconds)
+	tests := []struct {
+		name       string
+		position   int64
+		wantEvents []string
+	}{
+		{"before first milestone", 10, nil},
+		{"first milestone", 30, []string{"Video 25% complete"}},
+		{"no repeat", 40, []string{"Video 25% complete"}},
+		{"skipped milestone", 80, []string{"Video 25% complete", "Video 75% complete"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockPlayer.playbackPos = tt.position
+			mockPlayer.remainingTime = 100 - tt.position
+			controller.checkProgressMilestone()
+
+			if !slices.Equal(events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+
+		})
+	}
+
+}
+
+// TestSetPaused tests that pausing playback suspends speed updates until resumed
+func TestSetPaused(t *testing.T) {
+
+	controller, mockPlayer, speedController := setupTestController(t)
+	speedController.UpdateSpeed(logger.BackgroundCtx, 10.0)
+
+	t.Run("paused", func(t *testing.T) {
+
+		if err := controller.SetPaused(true); err != nil {
+			t.Fatalf("SetPaused(true) failed: %v", err)
+		}
+
+		if !controller.Paused() || !mockPlayer.lastPauseState {
+			t.Error("expected playback to be paused")
+		}
+
+		if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedController); err != nil {
+			t.Fatalf("updateSpeedFromController() failed: %v", err)
+		}
+
+		if !mockPlayer.lastPauseState {
+			t.Error("expected speed updates to be ignored while paused")
+		}
+
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+
+		if err := controller.SetPaused(false); err != nil {
+			t.Fatalf("SetPaused(false) failed: %v", err)
+		}
+
+		if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedController); err != nil {
+			t.Fatalf("updateSpeedFromController() failed: %v", err)
+		}
+
+		if controller.Paused() || mockPlayer.lastPauseState {
+			t.Error("expected playback to be resumed")
+		}
+
+	})
+
+}
+
+// TestStartCountdown tests that video playback is held (paused) until the start countdown
+// completes
+func TestStartCountdown(t *testing.T) {
+
+	controller, mockPlayer, speedController := setupTestController(t)
+	speedController.UpdateSpeed(logger.BackgroundCtx, 10.0)
+
+	controller.videoConfig.StartCountdownSecs = 5
+	controller.startCountdown()
+
+	if remaining := controller.CountdownRemaining(); remaining <= 4*time.Second || remaining > 5*time.Second {
+		t.Fatalf("CountdownRemaining() = %v, want about 5s", remaining)
+	}
+
+	if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedController); err != nil {
+		t.Fatalf("updateSpeedFromController() failed: %v", err)
+	}
+
+	if !mockPlayer.lastPauseState || mockPlayer.lastOSDMessage != "Starting in 5…" {
+		t.Errorf("pause state %t (OSD message %q), want playback held by the countdown", mockPlayer.lastPauseState, mockPlayer.lastOSDMessage)
+	}
+
+	// Complete the countdown
+	controller.countdown.ends.Store(time.Now().Add(-time.Second).UnixNano())
+
+	if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedController); err != nil {
+		t.Fatalf("updateSpeedFromController() failed: %v", err)
+	}
+
+	if controller.CountdownRemaining() != 0 || mockPlayer.lastPauseState {
+		t.Errorf("CountdownRemaining() = %v (paused %t), want playback started", controller.CountdownRemaining(), mockPlayer.lastPauseState)
+	}
+
+}
+
+// TestRecordLatency tests that the latency of each speed measurement that changes the playback
+// speed is recorded once
+func TestRecordLatency(t *testing.T) {
+
+	controller, _, speedController := setupTestController(t)
+	controller.latency = newLatencyState(true)
+
+	for _, speed := range []float64{10.0, 20.0} {
+
+		speedController.UpdateSpeed(logger.BackgroundCtx, speed)
+
+		// Only the first update after a speed measurement records its latency
+		for range 2 {
+
+			if err := controller.updateSpeedFromController(logger.BackgroundCtx, speedController); err != nil {
+				t.Fatalf("updateSpeedFromController() failed: %v", err)
+			}
+		}
+	}
+
+	stats := controller.latency.recorder.Stats()
+	if stats.Samples != 2 || stats.Max <= 0 || stats.Max > time.Second {
+		t.Errorf("latency stats = %+v, want 2 samples", stats)
+	}
+
+}
+
+// TestTimeRemainingEstimate tests that the time remaining is served from the estimate refreshed
+// from the media player, and corrected on seek
+func TestTimeRemainingEstimate(t *testing.T) {
+
+	controller, mockPlayer, _ := setupTestController(t)
+	mockPlayer.playbackPos = 20
+	mockPlayer.remainingTime = 100
+
+	controller.refreshTimeRemaining(logger.BackgroundCtx)
+
+	// The estimate isn't refreshed again within the refresh interval
+	mockPlayer.remainingTime = 90
+	controller.refreshTimeRemaining(logger.BackgroundCtx)
+
+	if got, err := controller.TimeRemaining(); err != nil || got != "00:01:40" {
+		t.Errorf("TimeRemaining() = (%v, %v), want (00:01:40, nil)", got, err)
+	}
+
+	if calls := mockPlayer.callCount("timeRemaining"); calls != 1 {
+		t.Errorf("expected the media player to be queried once, but was queried %d times", calls)
+	}
+
+	// Seeking corrects the estimate (total video duration of 110 seconds)
+	if err := controller.Seek(80*time.Second, true); err != nil {
+		t.Fatalf("Seek() failed: %v", err)
+	}
+
+	if got, err := controller.TimeRemaining(); err != nil || got != "00:00:30" {
+		t.Errorf("TimeRemaining() after seek = (%v, %v), want (00:00:30, nil)", got, err)
+	}
+
+}
+
+// TestRace tests that a race against the clock plays at a fixed pace (even when stopped), and
+// scores the ride against the target speed while playback isn't paused
+func TestRace(t *testing.T) {
+
+	vc, sc := createTestConfig()
+	mockPlayer := newMockMediaPlayer()
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		osdConfig:   osdConfig{showOSD: true, displayCycleSpeed: true},
+		player:      mockPlayer,
+		speedState:  &speedState{},
+	}
+
+	controller.SetRaceScorer(speed.NewRaceScorer(config.RaceConfig{TargetSpeed: 10}))
+
+	// Stopping doesn't pause a race
+	if err := controller.updateRacePlayback(logger.BackgroundCtx); err != nil {
+		t.Fatalf("updateRacePlayback() failed: %v", err)
+	}
+
+	if mockPlayer.lastSpeed != racePlaybackSpeed || mockPlayer.lastPauseState || controller.PlaybackSpeed() != racePlaybackSpeed {
+		t.Errorf("playback speed = %v (paused %v), want a fixed %v", mockPlayer.lastSpeed, mockPlayer.lastPauseState, racePlaybackSpeed)
+	}
+
+	if strings.Contains(mockPlayer.lastShowText, "PAUSED") {
+		t.Errorf("OSD text = %q, want no paused state", mockPlayer.lastShowText)
+	}
+
+	ride := func(speed float64, elapsed time.Duration) {
+		controller.speedState.current = speed
+		controller.race.updated = time.Now().Add(-elapsed)
+		controller.updateRace()
+	}
+
+	ride(5, 10*time.Second)
+
+	// The race clock holds while playback is paused
+	controller.paused.Store(true)
+	ride(0, 10*time.Second)
+	controller.paused.Store(false)
+
+	if want := "Race: 5.0 / 10.0 mph (-5.0), score 50%\n"; controller.raceOSDText() != want {
+		t.Errorf("raceOSDText() = %q, want %q", controller.raceOSDText(), want)
+	}
+
+	if score := controller.race.scorer.Score(); score.Scored != 10*time.Second {
+		t.Errorf("Scored = %v, want 10s", score.Scored)
+	}
+
+}
+
+// TestOutdoorOSDText tests the OSD line comparing the session against an outdoor ride
+func TestOutdoorOSDText(t *testing.T) {
+
+	vc, sc := createTestConfig()
+
+	controller := &PlaybackController{
+		videoConfig: vc,
+		speedConfig: sc,
+		player:      newMockMediaPlayer(),
+		speedState:  &speedState{},
+	}
+
+	if text := controller.outdoorOSDText(); text != "" {
+		t.Errorf("outdoorOSDText() = %q, want no text without an outdoor ride", text)
+	}
+
+	gpx := filepath.Join(t.TempDir(), "outdoor.gpx")
+	data := `<gpx><trk><trkseg>
+<trkpt lat="0" lon="0"><time>2026-06-01T08:00:00Z</time></trkpt>
+<trkpt lat="0" lon="1"><time>2026-06-01T10:00:00Z</time></trkpt>
+</trkseg></trk></gpx>`
+
+	if err := os.WriteFile(gpx, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write GPX file: %v", err)
+	}
+
+	ride, err := speed.LoadOutdoorRide(gpx)
+	if err != nil {
+		t.Fatalf("LoadOutdoorRide() failed: %v", err)
+	}
+
+	controller.SetOutdoorRide(ride)
+	controller.SetGoalTracker(speed.NewGoalTracker(config.GoalConfig{}, sc))
+
+	// Riding 40 mph for a minute is ahead of the outdoor ride (about 34.5 mph)
+	for range 6 {
+		controller.goal.tracker.Update(40, 10*time.Second)
+	}
+
+	if text := controller.outdoorOSDText(); !strings.HasPrefix(text, "vs Outdoor: ") || !strings.Contains(text, "0.1 mi ahead (0:09)") {
+		t.Errorf("outdoorOSDText() = %q, want the session ahead of the outdoor ride", text)
+	}
 
 }
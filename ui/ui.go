@@ -3,19 +3,20 @@ package ui
 import (
 	_ "embed" // required for go:embed
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
 	"github.com/diamondburned/gotk4/pkg/gdk/v4"
 	"github.com/diamondburned/gotk4/pkg/gio/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/flags"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/services"
 )
 
 const (
-	ApplicationID = "com.github.richbl.ble-sync-cycle"
+	ApplicationID = config.ApplicationID
 )
 
 //go:embed assets/bsc_gui.ui
@@ -23,47 +24,87 @@ var uiXML string
 
 // AppUI serves as the central controller for the GUI
 type AppUI struct {
-	Window      *adw.ApplicationWindow
-	ViewStack   *adw.ViewStack
-	Page1       *PageSessionSelect
-	Page2       *PageSessionStatus
-	Page3       *PageSessionLog
-	Page4       *PageSessionEditor
-	shutdownMgr *services.ShutdownManager
+	Window       *adw.ApplicationWindow
+	ViewStack    *adw.ViewStack
+	ToastOverlay *adw.ToastOverlay
+	Page1        *PageSessionSelect
+	Page2        *PageSessionStatus
+	Page3        *PageSessionLog
+	Page4        *PageSessionEditor
+	Page5        *PageSessionHistory
+	shutdownMgr  *services.ShutdownManager
+	exitDialog   *adw.AlertDialog // Exit confirmation dialog (if shown)
 }
 
 // PageSessionSelect holds widgets for the Session Selection tab (Page 1)
 type PageSessionSelect struct {
-	ListBox    *gtk.ListBox
-	EditButton *gtk.Button
-	LoadButton *gtk.Button
+	ListBox     *gtk.ListBox
+	ScanSpinner *gtk.Spinner
+	EditButton  *gtk.Button
+	LoadButton  *gtk.Button
 }
 
 // PageSessionStatus holds widgets for the Session Status tab (Page 2)
 type PageSessionStatus struct {
+	InstanceRow              *adw.ComboRow
+	VideoCheckBanner         *adw.Banner
 	SessionNameRow           *adw.ActionRow
+	LastEventRow             *adw.ActionRow
 	SensorStatusRow          *adw.ActionRow
 	SensorBatteryRow         *adw.ActionRow
 	SpeedRow                 *adw.ActionRow
 	SpeedLabel               *gtk.Label
+	SpeedStatsRow            *adw.ActionRow
+	SpeedStatsLabel          *gtk.Label
 	PlaybackSpeedRow         *adw.ActionRow
 	PlaybackSpeedLabel       *gtk.Label
 	RideTimeLabel            *gtk.Label
 	RideTimeRow              *adw.ActionRow
 	TimeRemainingLabel       *gtk.Label
 	TimeRemainingRow         *adw.ActionRow
+	GoalLabel                *gtk.Label
+	GoalRow                  *adw.ActionRow
+	LapRow                   *adw.ActionRow
+	LapButton                *gtk.Button
+	RaceLabel                *gtk.Label
+	RaceRow                  *adw.ActionRow
+	EnvironmentLabel         *gtk.Label
+	EnvironmentRow           *adw.ActionRow
+	SpeedGraphRow            *gtk.ListBoxRow
+	SpeedGraphArea           *gtk.DrawingArea
 	SessionControlRow        *gtk.ListBoxRow
 	SessionControlBtn        *gtk.Button
 	SessionControlBtnContent *adw.ButtonContent
+	SensorConnectBtn         *gtk.Button
+	SensorConnectBtnContent  *adw.ButtonContent
 	SensorConnIcon           *gtk.Image
 	SensorBattIcon           *gtk.Image
+	VolumeRow                *adw.ActionRow
+	VolumeScale              *gtk.Scale
+	MuteButton               *gtk.ToggleButton
+	SeekRow                  *adw.ActionRow
+	SeekBackButton           *gtk.Button
+	SeekForwardButton        *gtk.Button
 }
 
 // PageSessionLog holds widgets for the Session Log tab (Page 3)
 type PageSessionLog struct {
-	LogLevelRow *adw.ActionRow
-	TextView    *gtk.TextView
-	LogWriter   *GuiLogWriter
+	LogLevelRow      *adw.ComboRow
+	AutoScrollSwitch *adw.SwitchRow
+	SaveLogButton    *gtk.Button
+	TextView         *gtk.TextView
+	LogWriter        *GuiLogWriter
+	AsyncWriter      *logger.AsyncWriter
+}
+
+// PageSessionHistory holds widgets for the Session History tab (Page 5)
+type PageSessionHistory struct {
+	TotalsTimeRow     *adw.ActionRow
+	TotalsDistanceRow *adw.ActionRow
+	TotalsSessionsRow *adw.ActionRow
+	TotalsVideosRow   *adw.ActionRow
+	SearchEntry       *gtk.SearchEntry
+	ListBox           *gtk.ListBox
 }
 
 // PageSessionEditor holds widgets for the Session Edit tab (Page 4)
@@ -72,42 +113,95 @@ type PageSessionEditor struct {
 	// Scrolled window
 	ScrolledWindow *adw.PreferencesPage
 
+	// Configuration Warnings
+	WarningsGroup *adw.PreferencesGroup
+	WarningsLabel *gtk.Label
+
 	// Session Details
-	TitleEntry *adw.EntryRow
-	LogLevel   *adw.ComboRow
+	TitleEntry     *adw.EntryRow
+	LogLevel       *adw.ComboRow
+	Backups        *adw.SpinRow
+	MaxSessionTime *adw.SpinRow
+	ShutdownTime   *adw.SpinRow
 
 	// BLE Sensor
 	BTAddressEntry *adw.EntryRow
+	BTAddressPaste *gtk.Button
+	BTAddressQR    *gtk.Button
+	KnownSensors   *adw.ComboRow
+	SensorNickname *adw.EntryRow
 	ScanTimeout    *adw.SpinRow
+	ScanAttempts   *adw.SpinRow
 
 	// Speed Settings
 	WheelCircumference *adw.SpinRow
 	SpeedUnits         *adw.ComboRow
-	SpeedThreshold     *adw.SpinRow
-	SpeedSmoothing     *adw.SpinRow
+	SpeedZones         *adw.EntryRow
+
+	// Session Goal
+	GoalType    *adw.ComboRow
+	GoalTarget  *adw.SpinRow
+	RiderWeight *adw.SpinRow
+
+	// Interval Timer
+	IntervalHard *adw.SpinRow
+	IntervalEasy *adw.SpinRow
+
+	// Race
+	RaceTarget         *adw.SpinRow
+	RaceEasyTarget     *adw.SpinRow
+	OutdoorGPXFileRow  *adw.ActionRow
+	OutdoorGPXFileBtn  *gtk.Button
+	OutdoorGPXClearBtn *gtk.Button
 
 	// Video Settings
 	MediaPlayer       *adw.ComboRow
 	SessionFileRow    *adw.ActionRow
 	VideoFileRow      *adw.ActionRow
 	VideoFileButton   *gtk.Button
+	SubtitleFileRow   *adw.ActionRow
+	SubtitleFileBtn   *gtk.Button
+	SubtitleClearBtn  *gtk.Button
+	SwitchSubtitles   *adw.SwitchRow
 	StartTimeEntry    *adw.EntryRow
 	SwitchAutoResume  *adw.SwitchRow
+	SwitchResumeDist  *adw.SwitchRow
+	StartCountdown    *adw.SpinRow
+	ResumeFade        *adw.SpinRow
 	WindowScale       *adw.SpinRow
-	UpdateInterval    *adw.SpinRow
 	SpeedMultiplier   *adw.SpinRow
+	MinPlaybackSpeed  *adw.SpinRow
+	MaxPlaybackSpeed  *adw.SpinRow
+	Interpolation     *adw.SpinRow
 	TargetDisplayName *adw.ComboRow
+	FocusMode         *adw.ComboRow
 
 	// OSD
 	SwitchCycleSpeed    *adw.SwitchRow
 	SwitchPlaybackSpeed *adw.SwitchRow
 	SwitchTimeRemaining *adw.SwitchRow
+	SwitchSpeedStats    *adw.SwitchRow
 	FontSize            *adw.SpinRow
-	MarginLeft          *adw.SpinRow
-	MarginTop           *adw.SpinRow
+	NightModeHours      *adw.EntryRow
+	NightBrightness     *adw.SpinRow
 	AlignX              *adw.ComboRow
 	AlignY              *adw.ComboRow
 
+	// Advanced (rarely-used settings, each with a reset-to-default button)
+	AdvancedExpander    *adw.ExpanderRow
+	UpdateInterval      *adw.SpinRow
+	SpeedThreshold      *adw.SpinRow
+	SpeedSmoothing      *adw.SpinRow
+	MediaPlayerArgs     *adw.EntryRow
+	MarginLeft          *adw.SpinRow
+	MarginTop           *adw.SpinRow
+	ResetUpdateInterval *gtk.Button
+	ResetSpeedThreshold *gtk.Button
+	ResetSpeedSmoothing *gtk.Button
+	ResetPlayerArgs     *gtk.Button
+	ResetMarginLeft     *gtk.Button
+	ResetMarginTop      *gtk.Button
+
 	// Save/Delete Actions
 	SaveRow      *gtk.ListBoxRow
 	DeleteButton *gtk.Button
@@ -119,12 +213,14 @@ type PageSessionEditor struct {
 func NewAppUI(builder *gtk.Builder) *AppUI {
 
 	ui := &AppUI{
-		Window:    objGTK[*adw.ApplicationWindow](builder, "main_window"),
-		ViewStack: objGTK[*adw.ViewStack](builder, "view_stack"),
-		Page1:     hydrateSessionSelect(builder),
-		Page2:     hydrateSessionStatus(builder),
-		Page3:     hydrateSessionLog(builder),
-		Page4:     hydrateSessionEditor(builder),
+		Window:       objGTK[*adw.ApplicationWindow](builder, "main_window"),
+		ViewStack:    objGTK[*adw.ViewStack](builder, "view_stack"),
+		ToastOverlay: objGTK[*adw.ToastOverlay](builder, "toast_overlay"),
+		Page1:        hydrateSessionSelect(builder),
+		Page2:        hydrateSessionStatus(builder),
+		Page3:        hydrateSessionLog(builder),
+		Page4:        hydrateSessionEditor(builder),
+		Page5:        hydrateSessionHistory(builder),
 	}
 
 	return ui
@@ -152,32 +248,58 @@ func objGTK[T any](builder *gtk.Builder, id string) T {
 func hydrateSessionSelect(builder *gtk.Builder) *PageSessionSelect {
 
 	return &PageSessionSelect{
-		ListBox:    objGTK[*gtk.ListBox](builder, "session_listbox"),
-		EditButton: objGTK[*gtk.Button](builder, "edit_session_button"),
-		LoadButton: objGTK[*gtk.Button](builder, "load_session_button"),
+		ListBox:     objGTK[*gtk.ListBox](builder, "session_listbox"),
+		ScanSpinner: objGTK[*gtk.Spinner](builder, "session_scan_spinner"),
+		EditButton:  objGTK[*gtk.Button](builder, "edit_session_button"),
+		LoadButton:  objGTK[*gtk.Button](builder, "load_session_button"),
 	}
 }
 
 // hydrateSessionStatus constructs the PageSessionStatus from the GTK-Builder GUI file (bsc_gui.ui)
 func hydrateSessionStatus(builder *gtk.Builder) *PageSessionStatus {
 
+	applyStatusStyles()
+
 	return &PageSessionStatus{
+		InstanceRow:              objGTK[*adw.ComboRow](builder, "session_instance_combo"),
+		VideoCheckBanner:         objGTK[*adw.Banner](builder, "video_check_banner"),
 		SessionNameRow:           objGTK[*adw.ActionRow](builder, "session_name_row"),
+		LastEventRow:             objGTK[*adw.ActionRow](builder, "last_event_row"),
 		SensorStatusRow:          objGTK[*adw.ActionRow](builder, "sensor_status_row"),
 		SensorBatteryRow:         objGTK[*adw.ActionRow](builder, "battery_level_row"),
 		SpeedRow:                 objGTK[*adw.ActionRow](builder, "speed_row"),
 		SpeedLabel:               objGTK[*gtk.Label](builder, "speed_large_label"),
+		SpeedStatsRow:            objGTK[*adw.ActionRow](builder, "speed_stats_row"),
+		SpeedStatsLabel:          objGTK[*gtk.Label](builder, "speed_stats_large_label"),
 		PlaybackSpeedLabel:       objGTK[*gtk.Label](builder, "playback_speed_large_label"),
 		PlaybackSpeedRow:         objGTK[*adw.ActionRow](builder, "playback_speed_row"),
 		RideTimeLabel:            objGTK[*gtk.Label](builder, "ride_time_large_label"),
 		RideTimeRow:              objGTK[*adw.ActionRow](builder, "ride_time_row"),
 		TimeRemainingLabel:       objGTK[*gtk.Label](builder, "time_remaining_large_label"),
 		TimeRemainingRow:         objGTK[*adw.ActionRow](builder, "time_remaining_row"),
+		GoalLabel:                objGTK[*gtk.Label](builder, "goal_large_label"),
+		GoalRow:                  objGTK[*adw.ActionRow](builder, "goal_row"),
+		LapRow:                   objGTK[*adw.ActionRow](builder, "lap_row"),
+		LapButton:                objGTK[*gtk.Button](builder, "lap_button"),
+		RaceLabel:                objGTK[*gtk.Label](builder, "race_large_label"),
+		RaceRow:                  objGTK[*adw.ActionRow](builder, "race_row"),
+		EnvironmentLabel:         objGTK[*gtk.Label](builder, "environment_large_label"),
+		EnvironmentRow:           objGTK[*adw.ActionRow](builder, "environment_row"),
+		SpeedGraphRow:            objGTK[*gtk.ListBoxRow](builder, "speed_graph_row"),
+		SpeedGraphArea:           objGTK[*gtk.DrawingArea](builder, "speed_graph_area"),
 		SessionControlRow:        objGTK[*gtk.ListBoxRow](builder, "session_control_row"),
 		SessionControlBtn:        objGTK[*gtk.Button](builder, "session_control_button"),
 		SessionControlBtnContent: objGTK[*adw.ButtonContent](builder, "session_control_button_content"),
+		SensorConnectBtn:         objGTK[*gtk.Button](builder, "sensor_connect_button"),
+		SensorConnectBtnContent:  objGTK[*adw.ButtonContent](builder, "sensor_connect_button_content"),
 		SensorConnIcon:           objGTK[*gtk.Image](builder, "connection_status_icon"),
 		SensorBattIcon:           objGTK[*gtk.Image](builder, "battery_icon"),
+		VolumeRow:                objGTK[*adw.ActionRow](builder, "volume_row"),
+		VolumeScale:              objGTK[*gtk.Scale](builder, "volume_scale"),
+		MuteButton:               objGTK[*gtk.ToggleButton](builder, "mute_toggle_button"),
+		SeekRow:                  objGTK[*adw.ActionRow](builder, "seek_row"),
+		SeekBackButton:           objGTK[*gtk.Button](builder, "seek_back_button"),
+		SeekForwardButton:        objGTK[*gtk.Button](builder, "seek_forward_button"),
 	}
 }
 
@@ -185,12 +307,14 @@ func hydrateSessionStatus(builder *gtk.Builder) *PageSessionStatus {
 func hydrateSessionLog(builder *gtk.Builder) *PageSessionLog {
 
 	sessionLog := &PageSessionLog{
-		LogLevelRow: objGTK[*adw.ActionRow](builder, "logging_level_row"),
-		TextView:    objGTK[*gtk.TextView](builder, "logging_view"),
+		LogLevelRow:      objGTK[*adw.ComboRow](builder, "logging_level_row"),
+		AutoScrollSwitch: objGTK[*adw.SwitchRow](builder, "log_auto_scroll_switch"),
+		SaveLogButton:    objGTK[*gtk.Button](builder, "save_log_button"),
+		TextView:         objGTK[*gtk.TextView](builder, "logging_view"),
 	}
 
 	// Display logging level
-	sessionLog.LogLevelRow.SetTitle(logger.LogLevel())
+	sessionLog.LogLevelRow.SetSelected(indexOf(strings.ToLower(logger.LogLevel()), logLevels))
 
 	// Configure TextView for logging
 	tv := sessionLog.TextView
@@ -206,30 +330,23 @@ func hydrateSessionLog(builder *gtk.Builder) *PageSessionLog {
 	tv.AddCSSClass("session-log-view")
 	applyLogStyles()
 
-	// Configure scrolling behavior to always scroll to the bottom
-	scrolledWindow := objGTK[*gtk.ScrolledWindow](builder, "logging_scroll_window")
-	vAdj := scrolledWindow.VAdjustment()
-	vAdj.Connect("changed", func() {
-
-		// Calculate the bottom-most position
-		target := vAdj.Upper() - vAdj.PageSize()
-
-		// Only scroll if there is scrollable content
-		if target > 0 {
-			vAdj.SetValue(target)
-		}
+	// Set up logging bridge (permits logger GUI output), written from a background worker so a
+	// busy GUI never stalls the goroutines logging (e.g., BLE and video)
+	sessionLog.LogWriter = NewGuiLogWriter(tv)
+	sessionLog.AsyncWriter = logger.NewAsyncWriter(sessionLog.LogWriter)
+	logWriter := sessionLog.AsyncWriter
 
+	// Scroll to new log messages unless auto-scroll is switched off (e.g., to read earlier messages)
+	sessionLog.AutoScrollSwitch.Connect("notify::active", func() {
+		sessionLog.LogWriter.SetAutoScroll(sessionLog.AutoScrollSwitch.Active())
 	})
 
-	// Set up logging bridge (permits logger GUI output)
-	sessionLog.LogWriter = NewGuiLogWriter(tv)
-
 	// Enable logging to the console in GUI mode if requested
 	if flags.IsGUIConsoleLogging() {
-		logger.AddWriter(sessionLog.LogWriter)
+		logger.AddWriter(logWriter)
 		logger.Debug(logger.BackgroundCtx, logger.GUI, "logging via Session Log started with added console/CLI output")
 	} else {
-		logger.UseGUIWriterOnly(sessionLog.LogWriter)
+		logger.UseGUIWriterOnly(logWriter)
 		logger.Debug(logger.BackgroundCtx, logger.GUI, "logging via Session Log started")
 	}
 
@@ -256,36 +373,103 @@ func applyLogStyles() {
 
 }
 
+// applyStatusStyles injects a CSS provider to style the Session Status hints and speed zones
+func applyStatusStyles() {
+
+	// Create CSS styles that pulse the wake-the-sensor hint and color the speed zones
+	css := `
+	.` + cssWakeHint + ` {
+		animation: wake-hint-pulse 1s ease-in-out infinite alternate;
+	}
+
+	@keyframes wake-hint-pulse {
+		from { opacity: 1.0; }
+		to { opacity: 0.4; }
+	}
+	` + speedZoneStyles()
+	provider := gtk.NewCSSProvider()
+	provider.LoadFromString(css)
+
+	display := gdk.DisplayGetDefault()
+	if display != nil {
+		gtk.StyleContextAddProviderForDisplay(display, provider, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+	}
+
+}
+
 // hydrateSessionEditor constructs the PageSessionEditor from the GTK builder
 func hydrateSessionEditor(builder *gtk.Builder) *PageSessionEditor {
 	return &PageSessionEditor{
 		ScrolledWindow:      objGTK[*adw.PreferencesPage](builder, "session_editor_page"),
+		WarningsGroup:       objGTK[*adw.PreferencesGroup](builder, "edit_warnings_group"),
+		WarningsLabel:       objGTK[*gtk.Label](builder, "config_warnings_label"),
 		SessionFileRow:      objGTK[*adw.ActionRow](builder, "session_file_row"),
 		TitleEntry:          objGTK[*adw.EntryRow](builder, "session_title_entry_row"),
 		LogLevel:            objGTK[*adw.ComboRow](builder, "log_level_combo"),
+		Backups:             objGTK[*adw.SpinRow](builder, "backup_count_spin"),
+		MaxSessionTime:      objGTK[*adw.SpinRow](builder, "max_session_minutes_spin"),
+		ShutdownTime:        objGTK[*adw.SpinRow](builder, "shutdown_timeout_spin"),
 		BTAddressEntry:      objGTK[*adw.EntryRow](builder, "bt_address_entry_row"),
+		BTAddressPaste:      objGTK[*gtk.Button](builder, "bt_address_paste_button"),
+		BTAddressQR:         objGTK[*gtk.Button](builder, "bt_address_qr_button"),
+		KnownSensors:        objGTK[*adw.ComboRow](builder, "known_sensors_combo"),
+		SensorNickname:      objGTK[*adw.EntryRow](builder, "sensor_nickname_entry_row"),
 		ScanTimeout:         objGTK[*adw.SpinRow](builder, "scan_timeout_spin"),
+		ScanAttempts:        objGTK[*adw.SpinRow](builder, "scan_attempts_spin"),
 		WheelCircumference:  objGTK[*adw.SpinRow](builder, "edit_wheel_circumference_spin"),
 		SpeedUnits:          objGTK[*adw.ComboRow](builder, "edit_speed_units_combo"),
 		SpeedThreshold:      objGTK[*adw.SpinRow](builder, "edit_speed_threshold_spin"),
 		SpeedSmoothing:      objGTK[*adw.SpinRow](builder, "edit_speed_smoothing_spin"),
+		SpeedZones:          objGTK[*adw.EntryRow](builder, "edit_speed_zones_entry_row"),
+		GoalType:            objGTK[*adw.ComboRow](builder, "edit_goal_type_combo"),
+		GoalTarget:          objGTK[*adw.SpinRow](builder, "edit_goal_target_spin"),
+		RiderWeight:         objGTK[*adw.SpinRow](builder, "edit_rider_weight_spin"),
+		IntervalHard:        objGTK[*adw.SpinRow](builder, "edit_interval_hard_spin"),
+		IntervalEasy:        objGTK[*adw.SpinRow](builder, "edit_interval_easy_spin"),
+		RaceTarget:          objGTK[*adw.SpinRow](builder, "edit_race_target_spin"),
+		RaceEasyTarget:      objGTK[*adw.SpinRow](builder, "edit_race_easy_target_spin"),
+		OutdoorGPXFileRow:   objGTK[*adw.ActionRow](builder, "outdoor_gpx_file_row"),
+		OutdoorGPXFileBtn:   objGTK[*gtk.Button](builder, "outdoor_gpx_file_button"),
+		OutdoorGPXClearBtn:  objGTK[*gtk.Button](builder, "outdoor_gpx_file_clear_button"),
 		MediaPlayer:         objGTK[*adw.ComboRow](builder, "edit_media_player_combo"),
 		VideoFileRow:        objGTK[*adw.ActionRow](builder, "video_file_row"),
 		VideoFileButton:     objGTK[*gtk.Button](builder, "video_file_button"),
+		SubtitleFileRow:     objGTK[*adw.ActionRow](builder, "subtitle_file_row"),
+		SubtitleFileBtn:     objGTK[*gtk.Button](builder, "subtitle_file_button"),
+		SubtitleClearBtn:    objGTK[*gtk.Button](builder, "subtitle_file_clear_button"),
+		SwitchSubtitles:     objGTK[*adw.SwitchRow](builder, "show_subtitles_switch"),
 		StartTimeEntry:      objGTK[*adw.EntryRow](builder, "start_time_entry_row"),
+		StartCountdown:      objGTK[*adw.SpinRow](builder, "edit_start_countdown_spin"),
+		ResumeFade:          objGTK[*adw.SpinRow](builder, "edit_resume_fade_spin"),
 		WindowScale:         objGTK[*adw.SpinRow](builder, "edit_window_scale_factor_spin"),
 		UpdateInterval:      objGTK[*adw.SpinRow](builder, "edit_update_interval_spin"),
 		SpeedMultiplier:     objGTK[*adw.SpinRow](builder, "edit_speed_multiplier_spin"),
+		MinPlaybackSpeed:    objGTK[*adw.SpinRow](builder, "edit_min_playback_speed_spin"),
+		MaxPlaybackSpeed:    objGTK[*adw.SpinRow](builder, "edit_max_playback_speed_spin"),
+		Interpolation:       objGTK[*adw.SpinRow](builder, "edit_frame_interpolation_spin"),
 		TargetDisplayName:   objGTK[*adw.ComboRow](builder, "edit_screen-name_combo"),
+		FocusMode:           objGTK[*adw.ComboRow](builder, "edit_focus_mode_combo"),
 		SwitchCycleSpeed:    objGTK[*adw.SwitchRow](builder, "display_cycle_speed_switch"),
 		SwitchPlaybackSpeed: objGTK[*adw.SwitchRow](builder, "display_playback_speed_switch"),
 		SwitchTimeRemaining: objGTK[*adw.SwitchRow](builder, "display_time_remaining_switch"),
+		SwitchSpeedStats:    objGTK[*adw.SwitchRow](builder, "display_speed_stats_switch"),
 		SwitchAutoResume:    objGTK[*adw.SwitchRow](builder, "auto_resume_switch"),
+		SwitchResumeDist:    objGTK[*adw.SwitchRow](builder, "resume_distance_switch"),
 		FontSize:            objGTK[*adw.SpinRow](builder, "display_font_size_spin"),
 		MarginLeft:          objGTK[*adw.SpinRow](builder, "pixel_offset_left_spin"),
 		MarginTop:           objGTK[*adw.SpinRow](builder, "pixel_offset_top_spin"),
+		NightModeHours:      objGTK[*adw.EntryRow](builder, "edit_night_mode_hours_entry_row"),
+		NightBrightness:     objGTK[*adw.SpinRow](builder, "night_brightness_spin"),
 		AlignX:              objGTK[*adw.ComboRow](builder, "align_x_combo"),
 		AlignY:              objGTK[*adw.ComboRow](builder, "align_y_combo"),
+		AdvancedExpander:    objGTK[*adw.ExpanderRow](builder, "edit_advanced_expander"),
+		MediaPlayerArgs:     objGTK[*adw.EntryRow](builder, "edit_media_player_args_entry_row"),
+		ResetUpdateInterval: objGTK[*gtk.Button](builder, "reset_update_interval_button"),
+		ResetSpeedThreshold: objGTK[*gtk.Button](builder, "reset_speed_threshold_button"),
+		ResetSpeedSmoothing: objGTK[*gtk.Button](builder, "reset_speed_smoothing_button"),
+		ResetPlayerArgs:     objGTK[*gtk.Button](builder, "reset_media_player_args_button"),
+		ResetMarginLeft:     objGTK[*gtk.Button](builder, "reset_margin_left_button"),
+		ResetMarginTop:      objGTK[*gtk.Button](builder, "reset_margin_top_button"),
 		SaveRow:             objGTK[*gtk.ListBoxRow](builder, "edit_save_row"),
 		DeleteButton:        objGTK[*gtk.Button](builder, "delete_session_button"),
 		SaveButton:          objGTK[*gtk.Button](builder, "save_button"),
@@ -293,6 +477,19 @@ func hydrateSessionEditor(builder *gtk.Builder) *PageSessionEditor {
 	}
 }
 
+// hydrateSessionHistory constructs the PageSessionHistory from the GTK-Builder GUI file (bsc_gui.ui)
+func hydrateSessionHistory(builder *gtk.Builder) *PageSessionHistory {
+
+	return &PageSessionHistory{
+		TotalsTimeRow:     objGTK[*adw.ActionRow](builder, "totals_time_row"),
+		TotalsDistanceRow: objGTK[*adw.ActionRow](builder, "totals_distance_row"),
+		TotalsSessionsRow: objGTK[*adw.ActionRow](builder, "totals_sessions_row"),
+		TotalsVideosRow:   objGTK[*adw.ActionRow](builder, "totals_videos_row"),
+		SearchEntry:       objGTK[*gtk.SearchEntry](builder, "history_search_entry"),
+		ListBox:           objGTK[*gtk.ListBox](builder, "history_listbox"),
+	}
+}
+
 // setupAllSignals sets up all UI signal handlers for the application
 func setupAllSignals(sc *SessionController) {
 
@@ -301,9 +498,7 @@ func setupAllSignals(sc *SessionController) {
 
 		"page1": func() {
 			logger.Debug(logger.BackgroundCtx, logger.GUI, "view switched to Session Select: refreshing session list...")
-			sc.scanForSessions()
-			sc.PopulateSessionList()
-			sc.CheckForNoSessions()
+			sc.scanForSessions(sc.CheckForNoSessions)
 		},
 
 		"page2": func() {
@@ -319,6 +514,12 @@ func setupAllSignals(sc *SessionController) {
 			logger.Debug(logger.BackgroundCtx, logger.GUI, "view switched to Session Editor")
 			sc.UI.Page4.ScrolledWindow.ScrollToTop()
 		},
+
+		"page5": func() {
+			logger.Debug(logger.BackgroundCtx, logger.GUI, "view switched to Session History")
+			sc.populateTotals()
+			sc.populateHistory()
+		},
 	}
 
 	// Reuse existing navigation setup utility
@@ -327,8 +528,10 @@ func setupAllSignals(sc *SessionController) {
 	// Per-tab signal setups
 	sc.setupSessionSelectSignals()
 	sc.setupSessionStatusSignals()
+	sc.setupInstanceSwitcherSignals()
 	sc.setupSessionLogSignals()
 	sc.setupSessionEditSignals()
+	sc.setupSessionHistorySignals()
 
 }
 
@@ -337,7 +540,7 @@ func StartGUI() {
 
 	// Create a global ShutdownManager for GUI mode to handle signals (CTRL+C)
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "creating ShutdownManager service...")
-	shutdownMgr := services.NewShutdownManager(30 * time.Second)
+	shutdownMgr := services.NewShutdownManager(services.DefaultShutdownTimeout)
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "ShutdownManager service created")
 
 	// Initialize the application
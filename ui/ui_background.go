@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Background mode notification identifier
+const backgroundNotificationID = "bsc-background"
+
+// setupBackgroundActions creates the application actions used to run the GUI in the background
+// while a session is running, and to control the session from the background notification
+func setupBackgroundActions(app *gtk.Application, sc *SessionController) {
+
+	actions := map[string]func(){
+		"background":   sc.enterBackground,
+		"show-window":  sc.exitBackground,
+		"toggle-pause": sc.toggleBackgroundPause,
+		"stop-session": sc.stopFromBackground,
+	}
+
+	for name, handler := range actions {
+		action := gio.NewSimpleAction(name, nil)
+		action.ConnectActivate(func(_ *glib.Variant) {
+			handler()
+		})
+
+		app.AddAction(action)
+	}
+
+}
+
+// enterBackground hides the main window while a session is running, leaving a notification
+// with session controls in its place
+func (sc *SessionController) enterBackground() {
+
+	if !sc.SessionManager.IsRunning() {
+		displayAlertDialog(sc.UI.Window, "Run in Background", "Background mode is only available while a BSC Session is running.")
+
+		return
+	}
+
+	if sc.inBackground {
+		return
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "running GUI in background mode")
+
+	// Keep the application alive while no window is visible
+	sc.UI.Window.Application().Hold()
+	sc.inBackground = true
+
+	sc.UI.Window.SetVisible(false)
+	sc.sendBackgroundNotification()
+
+}
+
+// exitBackground restores the main window and removes the background notification
+func (sc *SessionController) exitBackground() {
+
+	if sc.inBackground {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "exiting GUI background mode")
+
+		app := sc.UI.Window.Application()
+		app.WithdrawNotification(backgroundNotificationID)
+		app.Release()
+		sc.inBackground = false
+	}
+
+	sc.UI.Window.Present()
+
+}
+
+// toggleBackgroundPause pauses or resumes the running session from the background notification
+func (sc *SessionController) toggleBackgroundPause() {
+
+	var err error
+
+	if sc.SessionManager.IsPaused() {
+		err = sc.SessionManager.ResumeSession()
+	} else {
+		err = sc.SessionManager.PauseSession()
+	}
+
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to pause/resume session: %v", err))
+
+		return
+	}
+
+	// Refresh the notification to reflect the new pause state
+	if sc.inBackground {
+		sc.sendBackgroundNotification()
+	}
+
+}
+
+// stopFromBackground stops the running session and restores the main window
+func (sc *SessionController) stopFromBackground() {
+
+	sc.exitBackground()
+
+	if err := sc.handleStop(); err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to stop session from background: %v", err))
+	}
+
+}
+
+// sendBackgroundNotification sends (or replaces) the notification used to control the session
+// while the GUI runs in the background
+func (sc *SessionController) sendBackgroundNotification() {
+
+	pauseLabel := "Pause"
+	body := "BSC Session running in the background"
+
+	if sc.SessionManager.IsPaused() {
+		pauseLabel = "Resume"
+		body = "BSC Session paused in the background"
+	}
+
+	notification := gio.NewNotification("BLE Sync Cycle")
+	notification.SetBody(body)
+	notification.SetDefaultAction("app.show-window")
+	notification.AddButton(pauseLabel, "app.toggle-pause")
+	notification.AddButton("Stop Session", "app.stop-session")
+	notification.AddButton("Show Window", "app.show-window")
+
+	sc.UI.Window.Application().SendNotification(backgroundNotificationID, notification)
+
+}
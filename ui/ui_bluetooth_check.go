@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// CheckBluetoothPermissions checks for common Bluetooth permission problems at startup, and shows
+// a helper dialog describing how to fix them
+func (sc *SessionController) CheckBluetoothPermissions() {
+
+	issues := ble.CheckPermissions()
+	if len(issues) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("BSC may be unable to connect to your BLE sensor:\n")
+
+	for _, issue := range issues {
+		logger.Warn(logger.BackgroundCtx, logger.BLE, fmt.Sprintf("%s: to fix, %s", issue.Problem, issue.Remedy))
+		fmt.Fprintf(&sb, "\n• %s\n   To fix, %s\n", capitalize(issue.Problem), issue.Remedy)
+	}
+
+	safeUpdateUI(func() {
+		displayAlertDialog(sc.UI.Window, "Bluetooth Setup", sb.String())
+	})
+
+}
+
+// capitalize returns the string with its first letter in upper case
+func capitalize(s string) string {
+
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
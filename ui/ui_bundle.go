@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Default name of an exported settings bundle
+const bundleFileName = "bsc-settings.zip"
+
+// setupBundleActions creates the application actions used to export and import settings bundles
+func setupBundleActions(app *gtk.Application, sc *SessionController) {
+
+	exportAction := gio.NewSimpleAction("export-settings", nil)
+	exportAction.ConnectActivate(func(_ *glib.Variant) {
+		sc.exportSettingsBundle()
+	})
+
+	app.AddAction(exportAction)
+
+	importAction := gio.NewSimpleAction("import-settings", nil)
+	importAction.ConnectActivate(func(_ *glib.Variant) {
+		sc.importSettingsBundle()
+	})
+
+	app.AddAction(importAction)
+
+}
+
+// bundleFileDialog creates a file dialog limited to settings bundles
+func bundleFileDialog(title string) *gtk.FileDialog {
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle(title)
+	fileDialog.SetModal(true)
+
+	filter := gtk.NewFileFilter()
+	filter.SetName("BSC Settings Bundles")
+	filter.AddPattern("*.zip")
+
+	filters := gio.NewListStore(filter.Type())
+	filters.Append(filter.Object)
+	fileDialog.SetFilters(filters)
+
+	return fileDialog
+}
+
+// exportSettingsBundle exports the session directory (sessions and sensor registry) into a
+// settings bundle chosen by the user
+func (sc *SessionController) exportSettingsBundle() {
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "opening settings bundle export dialog...")
+
+	fileDialog := bundleFileDialog("Export BSC Settings")
+	fileDialog.SetInitialName(bundleFileName)
+
+	cb := func(res gio.AsyncResulter) {
+
+		file, err := fileDialog.SaveFinish(res)
+		if err != nil {
+			return
+		}
+
+		configDir, err := getSessionConfigDir()
+		if err != nil {
+			logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to locate session directory: %v", err))
+
+			return
+		}
+
+		count, err := config.ExportBundle(configDir, file.Path())
+		if err != nil {
+			logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to export settings bundle: %v", err))
+			sc.displayErrorDialog("BSC Settings Export Error", "The BSC settings could not be exported.\n\nPlease review the BSC Session Log for details.")
+
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("%d file(s) exported to %s", count, file.Path()))
+		displayAlertDialog(sc.UI.Window, "BSC Settings Exported", fmt.Sprintf("%d file(s) exported to:\n\n%s", count, file.Path()))
+
+	}
+
+	fileDialog.Save(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// importSettingsBundle imports a settings bundle chosen by the user into the session directory
+// (without overwriting existing sessions), then refreshes the session list
+func (sc *SessionController) importSettingsBundle() {
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "opening settings bundle import dialog...")
+
+	fileDialog := bundleFileDialog("Import BSC Settings")
+
+	cb := func(res gio.AsyncResulter) {
+
+		file, err := fileDialog.OpenFinish(res)
+		if err != nil {
+			return
+		}
+
+		configDir, err := getSessionConfigDir()
+		if err != nil {
+			logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to locate session directory: %v", err))
+
+			return
+		}
+
+		result, err := config.ImportBundle(file.Path(), configDir, false)
+		if err != nil {
+			logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to import settings bundle: %v", err))
+			displayAlertDialog(sc.UI.Window, "BSC Settings Import Error", fmt.Sprintf("The BSC settings could not be imported:\n\n%v", err))
+
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("%d file(s) imported from %s", len(result.Imported), file.Path()))
+
+		message := fmt.Sprintf("%d file(s) imported.", len(result.Imported))
+		if len(result.Skipped) > 0 {
+			message += fmt.Sprintf("\n\nThe following file(s) already exist and were not imported:\n\n%s", strings.Join(result.Skipped, "\n"))
+		}
+
+		sc.scanForSessions(nil)
+		displayAlertDialog(sc.UI.Window, "BSC Settings Imported", message)
+
+	}
+
+	fileDialog.Open(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// setupConfigWarningsSignals refreshes the configuration warnings whenever a Session Editor
+// setting that is checked for likely mistakes changes
+func (sc *SessionController) setupConfigWarningsSignals() {
+
+	p4 := sc.UI.Page4
+	update := func() {
+		sc.updateConfigWarnings()
+	}
+
+	p4.UpdateInterval.Connect("notify::value", update)
+	p4.SpeedThreshold.Connect("notify::value", update)
+	p4.WheelCircumference.Connect("notify::value", update)
+	p4.SpeedUnits.Connect("notify::selected", update)
+	p4.SwitchTimeRemaining.Connect("notify::active", update)
+
+}
+
+// updateConfigWarnings shows (or hides) the warnings for Session Editor settings that are valid,
+// but likely mistakes (e.g., a wheel circumference entered in inches)
+func (sc *SessionController) updateConfigWarnings() {
+
+	p4 := sc.UI.Page4
+
+	current := sc.SessionManager.Config()
+	if current == nil {
+		p4.WarningsGroup.SetVisible(false)
+
+		return
+	}
+
+	// Check a copy of the session being edited, updated with the settings checked for mistakes
+	cfg := *current
+	cfg.Speed.WheelCircumferenceMM = int(p4.WheelCircumference.Value())
+	cfg.Speed.SpeedThreshold = p4.SpeedThreshold.Value()
+	cfg.Video.UpdateIntervalSec = p4.UpdateInterval.Value()
+	cfg.Video.OnScreenDisplay.DisplayTimeRemaining = p4.SwitchTimeRemaining.Active()
+
+	if idx := p4.SpeedUnits.Selected(); idx < uint(len(speedUnits)) {
+		cfg.Speed.SpeedUnits = speedUnits[idx]
+	}
+
+	warnings := cfg.Lint()
+	if len(warnings) == 0 {
+		p4.WarningsGroup.SetVisible(false)
+
+		return
+	}
+
+	lines := make([]string, len(warnings))
+	for i, warning := range warnings {
+		lines[i] = "• " + warning.Message
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session editor shows %d configuration warning(s)", len(warnings)))
+
+	p4.WarningsLabel.SetText(strings.Join(lines, "\n"))
+	p4.WarningsGroup.SetVisible(true)
+
+}
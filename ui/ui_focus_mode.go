@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// focusLayout holds the GUI layout saved when a focus mode rearranges the GUI, so the layout can
+// be restored when the session stops
+type focusLayout struct {
+	mode string
+	page string
+}
+
+// enterFocusMode rearranges the GUI for the focus mode of the running session (if any), running
+// the GUI in the background (with the video full screen on the target display)
+func (sc *SessionController) enterFocusMode() {
+
+	cfg := sc.SessionManager.ActiveConfig()
+	if cfg == nil || !cfg.Video.FocusEnabled() || sc.focus != nil {
+		return
+	}
+
+	sc.focus = &focusLayout{
+		mode: cfg.Video.FocusMode,
+		page: sc.UI.ViewStack.VisibleChildName(),
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("entering %s focus mode", sc.focus.mode))
+
+	sc.UI.ViewStack.SetVisibleChildName("page2")
+
+	// Background mode is only available while session services are running
+	if sc.SessionManager.IsRunning() {
+		sc.enterBackground()
+	}
+
+}
+
+// exitFocusMode restores the GUI layout saved when the focus mode was entered (if any)
+func (sc *SessionController) exitFocusMode() {
+
+	layout := sc.focus
+	if layout == nil {
+		return
+	}
+
+	sc.focus = nil
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("exiting %s focus mode", layout.mode))
+
+	if sc.inBackground {
+		sc.exitBackground()
+	}
+
+	sc.UI.ViewStack.SetVisibleChildName(layout.page)
+
+}
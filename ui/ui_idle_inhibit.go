@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Reason given to the desktop for inhibiting the screensaver (shown by some desktops)
+const idleInhibitReason = "BSC Session running"
+
+// updateIdleInhibitor inhibits the desktop screensaver, screen lock and automatic suspend while any
+// session instance is running (since a steady ride can go a long time without any keyboard or
+// mouse input), and releases the inhibitor once no session instance is running
+func (sc *SessionController) updateIdleInhibitor() {
+
+	app := sc.UI.Window.Application()
+	if app == nil {
+		return
+	}
+
+	running := len(sc.registry.Running()) > 0
+
+	switch {
+	case running && sc.idleInhibitor == 0:
+		sc.idleInhibitor = app.Inhibit(&sc.UI.Window.Window, gtk.ApplicationInhibitIdle|gtk.ApplicationInhibitSuspend, idleInhibitReason)
+
+		if sc.idleInhibitor == 0 {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, "desktop refused to inhibit the screensaver: the screen may blank during the session")
+
+			return
+		}
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "screensaver inhibited while the session is running")
+
+	case !running && sc.idleInhibitor != 0:
+		app.Uninhibit(sc.idleInhibitor)
+		sc.idleInhibitor = 0
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "screensaver inhibitor released")
+	}
+
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/crashreport"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Layout of the timestamp in the name of a saved log file
+const logFileTimeLayout = "20060102-150405"
+
+// logFileName returns the timestamped name of a saved log file (e.g., "bsc-log-20261016-145210.txt")
+func logFileName() string {
+	return "bsc-log-" + time.Now().Format(logFileTimeLayout) + ".txt"
+}
+
+// sessionLogText returns the entire Session Log, including any log messages not yet shown
+func (sc *SessionController) sessionLogText() string {
+
+	sc.UI.Page3.AsyncWriter.Flush()
+
+	return sc.UI.Page3.LogWriter.Text()
+}
+
+// saveSessionLog writes the entire Session Log to a file
+func (sc *SessionController) saveSessionLog(path string) error {
+
+	if err := os.WriteFile(path, []byte(sc.sessionLogText()), 0644); err != nil {
+		return fmt.Errorf("failed to save Session Log: %w", err)
+	}
+
+	return nil
+}
+
+// newLogFileDialog creates a file dialog for saving the Session Log, with a timestamped file name
+func newLogFileDialog() *gtk.FileDialog {
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle("Save BSC Session Log")
+	fileDialog.SetModal(true)
+	fileDialog.SetInitialName(logFileName())
+
+	filter := gtk.NewFileFilter()
+	filter.SetName("Log Files")
+	filter.AddPattern("*.txt")
+	filter.AddPattern("*.log")
+
+	filters := gio.NewListStore(filter.Type())
+	filters.Append(filter.Object)
+	fileDialog.SetFilters(filters)
+
+	return fileDialog
+}
+
+// exportSessionLog saves the entire Session Log to a file chosen by the user
+func (sc *SessionController) exportSessionLog() {
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "opening Session Log save dialog...")
+
+	fileDialog := newLogFileDialog()
+
+	cb := func(res gio.AsyncResulter) {
+
+		file, err := fileDialog.SaveFinish(res)
+		if err != nil {
+			return
+		}
+
+		if err := sc.saveSessionLog(file.Path()); err != nil {
+			logger.Error(logger.BackgroundCtx, logger.GUI, err.Error())
+			displayAlertDialog(sc.UI.Window, "BSC Session Log Save Error", fmt.Sprintf("The file %s could not be saved:\n\n%v", file.Path(), err))
+
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("Session Log saved to %s", file.Path()))
+		displayAlertDialog(sc.UI.Window, "BSC Session Log Saved", fmt.Sprintf("The Session Log was saved to:\n\n%s", file.Path()))
+
+	}
+
+	fileDialog.Save(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// saveErrorLog saves the entire Session Log to a timestamped file in the log directory, returning
+// the path of the log file
+func (sc *SessionController) saveErrorLog() (string, error) {
+
+	logDir, err := config.LogDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	path := filepath.Join(logDir, logFileName())
+
+	return path, sc.saveSessionLog(path)
+}
+
+// displayErrorDialog shows an error alert dialog, adding the path of the Session Log saved when
+// the error occurred (so the log can be attached to a bug report)
+func (sc *SessionController) displayErrorDialog(title, message string) {
+
+	path, err := sc.saveErrorLog()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save Session Log for error report: %v", err))
+	} else {
+		logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("Session Log saved to %s", path))
+		message += fmt.Sprintf("\n\nThe BSC Session Log was saved to:\n\n%s", path)
+	}
+
+	// Point to the crash report generated for the error (e.g., a service panic), if any
+	if bundle, ok := crashreport.TakeLatest(); ok {
+		message += fmt.Sprintf("\n\nA crash report was saved to:\n\n%s\n\nPlease attach it when filing an issue.", bundle)
+	}
+
+	displayAlertDialog(sc.UI.Window, title, message)
+
+}
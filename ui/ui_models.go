@@ -33,6 +33,9 @@ type StatusPresentation struct {
 	CSSStyle string
 }
 
+// CSS class of the pulsing wake-the-sensor hint on the sensor status row
+const cssWakeHint = "wake-hint"
+
 const (
 	// BLE icons
 	iconBLEConnected    = "bluetooth-symbolic"
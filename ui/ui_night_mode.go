@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// setupNightModeSignals validates the night mode hours of the Session Editor as they're typed (the
+// hours must be in "START-END" form, or empty)
+func (sc *SessionController) setupNightModeSignals(onUpdate func()) {
+
+	entry := sc.UI.Page4.NightModeHours
+
+	entry.Connect("changed", func() {
+
+		hours := strings.TrimSpace(entry.Text())
+
+		if _, _, ok := config.ParseNightModeHours(hours); ok || hours == "" {
+			entry.RemoveCSSClass("error")
+		} else {
+			entry.AddCSSClass("error")
+		}
+
+		onUpdate()
+
+	})
+
+}
+
+// updateNightTheme switches the GUI to its dark theme during the night mode hours of the running
+// session shown in the GUI (see night_mode_hours in the [video.OSD] section), and back to the
+// desktop's theme once the night mode ends or the session stops
+func (sc *SessionController) updateNightTheme() {
+
+	cfg := sc.SessionManager.ActiveConfig()
+	night := cfg != nil && sc.SessionManager.IsRunning() && cfg.Video.OnScreenDisplay.NightModeAt(time.Now())
+
+	if night == sc.nightTheme {
+		return
+	}
+
+	sc.nightTheme = night
+
+	scheme := adw.ColorSchemeDefault
+	if night {
+		scheme = adw.ColorSchemeForceDark
+	}
+
+	adw.StyleManagerGetDefault().SetColorScheme(scheme)
+
+	if night {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "night mode on: dark theme applied")
+	} else {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "night mode off: desktop theme restored")
+	}
+
+}
@@ -0,0 +1,414 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Onboarding settings
+const (
+	onboardingScanSecs    = 10
+	onboardingNoVideo     = "No video selected"
+	onboardingNoSensors   = "No sensors found"
+	onboardingWheelCustom = "Custom"
+)
+
+// wheelSize is a common bicycle wheel (and tire) size and its circumference
+type wheelSize struct {
+	name            string
+	circumferenceMM int
+}
+
+// wheelSizes are the wheel sizes offered during onboarding (the last entry is a custom size)
+var wheelSizes = []wheelSize{
+	{"700x25c (road)", 2105},
+	{"700x28c (road)", 2136},
+	{"700x32c (hybrid)", 2155},
+	{"26x2.1 (mountain)", 2068},
+	{"27.5x2.25 (mountain)", 2182},
+	{"29x2.2 (mountain)", 2288},
+	{onboardingWheelCustom, 0},
+}
+
+// onboarding holds the widgets of the first-run onboarding dialog
+type onboarding struct {
+	dialog        *adw.Dialog
+	titleEntry    *adw.EntryRow
+	sensorRow     *adw.ComboRow
+	scanButton    *gtk.Button
+	addressEntry  *adw.EntryRow
+	videoRow      *adw.ActionRow
+	wheelRow      *adw.ComboRow
+	circumference *adw.SpinRow
+	unitsRow      *adw.ComboRow
+	saveButton    *gtk.Button
+	sensors       []ble.DiscoveredSensor
+	videoPath     string
+}
+
+// CheckForNoSessions checks if any session files exist and, if not, guides the user through
+// creating their first session
+func (sc *SessionController) CheckForNoSessions() {
+
+	if len(sc.Sessions) == 0 {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "no session configuration files found, starting onboarding...")
+
+		safeUpdateUI(func() {
+			sc.showOnboarding()
+		})
+	}
+
+}
+
+// showOnboarding presents the onboarding dialog, which walks through the sensor scan, video
+// selection, and wheel size before saving the first session
+func (sc *SessionController) showOnboarding() {
+
+	ob := &onboarding{}
+	defaults := createDefaultConfig("")
+
+	// Session
+	ob.titleEntry = adw.NewEntryRow()
+	ob.titleEntry.SetTitle("Session Title")
+	ob.titleEntry.SetText(defaults.App.SessionTitle)
+
+	sessionGroup := newOnboardingGroup("Welcome to BLE Sync Cycle", "Let's create your first BSC session. You can change any of these settings later in the Session Editor.")
+	sessionGroup.Add(ob.titleEntry)
+
+	// BLE sensor
+	ob.sensorRow = adw.NewComboRow()
+	ob.sensorRow.SetTitle("Detected Sensors")
+	ob.sensorRow.SetModel(gtk.NewStringList([]string{onboardingNoSensors}))
+	ob.sensorRow.SetSensitive(false)
+
+	ob.scanButton = gtk.NewButtonWithLabel("Scan")
+	ob.scanButton.SetVAlign(gtk.AlignCenter)
+	ob.sensorRow.AddSuffix(ob.scanButton)
+
+	ob.addressEntry = adw.NewEntryRow()
+	ob.addressEntry.SetTitle("Sensor BD_ADDR")
+	ob.addressEntry.SetText(defaults.BLE.SensorBDAddr)
+
+	sensorGroup := newOnboardingGroup("1. BLE Sensor", "Wake your speed sensor by spinning the wheel, and then scan for it (or enter its BD_ADDR).")
+	sensorGroup.Add(ob.sensorRow)
+	sensorGroup.Add(ob.addressEntry)
+
+	// Video
+	videoButton := gtk.NewButtonWithLabel("Choose…")
+	videoButton.SetVAlign(gtk.AlignCenter)
+
+	ob.videoRow = adw.NewActionRow()
+	ob.videoRow.SetTitle("Video File")
+	ob.videoRow.SetSubtitle(onboardingNoVideo)
+	ob.videoRow.AddSuffix(videoButton)
+
+	videoGroup := newOnboardingGroup("2. Video", "Choose the video to play back while you ride.")
+	videoGroup.Add(ob.videoRow)
+
+	// Wheel size
+	names := make([]string, len(wheelSizes))
+	for i, ws := range wheelSizes {
+		names[i] = ws.name
+	}
+
+	ob.wheelRow = adw.NewComboRow()
+	ob.wheelRow.SetTitle("Wheel Size")
+	ob.wheelRow.SetModel(gtk.NewStringList(names))
+
+	ob.circumference = adw.NewSpinRowWithRange(50, 3000, 1)
+	ob.circumference.SetTitle("Wheel Circumference")
+	ob.circumference.SetSubtitle("mm")
+
+	ob.unitsRow = adw.NewComboRow()
+	ob.unitsRow.SetTitle("Speed Units")
+	ob.unitsRow.SetModel(gtk.NewStringList(speedUnits))
+	ob.unitsRow.SetSelected(indexOf(defaults.Speed.SpeedUnits, speedUnits))
+
+	ob.selectWheelSize(defaults.Speed.WheelCircumferenceMM)
+
+	wheelGroup := newOnboardingGroup("3. Wheel Size", "Choose the size of the wheel the sensor is mounted on (or enter its circumference).")
+	wheelGroup.Add(ob.wheelRow)
+	wheelGroup.Add(ob.circumference)
+	wheelGroup.Add(ob.unitsRow)
+
+	page := adw.NewPreferencesPage()
+	page.Add(sessionGroup)
+	page.Add(sensorGroup)
+	page.Add(videoGroup)
+	page.Add(wheelGroup)
+
+	// Actions
+	skipButton := gtk.NewButtonWithLabel("Skip")
+	skipButton.SetTooltipText("Create a placeholder session to complete later in the Session Editor")
+
+	ob.saveButton = gtk.NewButtonWithLabel("Save Session")
+	ob.saveButton.AddCSSClass("suggested-action")
+
+	actions := gtk.NewBox(gtk.OrientationHorizontal, 12)
+	actions.SetHAlign(gtk.AlignEnd)
+	actions.SetMarginTop(12)
+	actions.SetMarginBottom(12)
+	actions.SetMarginStart(12)
+	actions.SetMarginEnd(12)
+	actions.Append(skipButton)
+	actions.Append(ob.saveButton)
+
+	toolbar := adw.NewToolbarView()
+	toolbar.AddTopBar(adw.NewHeaderBar())
+	toolbar.SetContent(page)
+	toolbar.AddBottomBar(actions)
+
+	ob.dialog = adw.NewDialog()
+	ob.dialog.SetTitle("Create Your First BSC Session")
+	ob.dialog.SetContentWidth(560)
+	ob.dialog.SetContentHeight(720)
+	ob.dialog.SetChild(toolbar)
+
+	sc.setupOnboardingSignals(ob, videoButton, skipButton)
+	ob.updateSaveButton()
+
+	ob.dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
+
+// setupOnboardingSignals wires up event listeners for the onboarding dialog
+func (sc *SessionController) setupOnboardingSignals(ob *onboarding, videoButton, skipButton *gtk.Button) {
+
+	updateSaveButton := func() {
+		ob.updateSaveButton()
+	}
+
+	bindValidator(ob.titleEntry, patternSessionTitle, updateSaveButton)
+	bindBDAddrValidator(ob.addressEntry, updateSaveButton)
+
+	ob.scanButton.ConnectClicked(func() {
+		sc.scanForOnboardingSensors(ob)
+	})
+
+	// Fill in the BD_ADDR of the selected sensor
+	ob.sensorRow.Connect("notify::selected", func() {
+
+		if idx := int(ob.sensorRow.Selected()); idx < len(ob.sensors) {
+			ob.addressEntry.SetText(ob.sensors[idx].Address)
+		}
+
+	})
+
+	videoButton.ConnectClicked(func() {
+
+		sc.chooseVideoFile(func(path string) {
+			ob.videoPath = path
+			ob.videoRow.SetSubtitle(path)
+			ob.updateSaveButton()
+		})
+
+	})
+
+	// Fill in the circumference of the selected wheel size (a custom size keeps the current value)
+	ob.wheelRow.Connect("notify::selected", func() {
+
+		if ws := wheelSizes[ob.wheelRow.Selected()]; ws.circumferenceMM > 0 {
+			ob.circumference.SetValue(float64(ws.circumferenceMM))
+		}
+
+	})
+
+	ob.circumference.Connect("notify::value", func() {
+		ob.selectWheelSize(int(ob.circumference.Value()))
+	})
+
+	skipButton.ConnectClicked(func() {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "onboarding skipped, creating placeholder session...")
+		ob.dialog.Close()
+		sc.createNewDefaultSession()
+	})
+
+	ob.saveButton.ConnectClicked(func() {
+		sc.saveOnboardingSession(ob)
+	})
+
+}
+
+// scanForOnboardingSensors scans for BLE CSC sensors in the background, listing the sensors found
+func (sc *SessionController) scanForOnboardingSensors(ob *onboarding) {
+
+	ob.scanButton.SetSensitive(false)
+	ob.scanButton.SetLabel("Scanning…")
+
+	go func() {
+
+		ctx, cancel := context.WithTimeout(logger.BackgroundCtx, onboardingScanSecs*time.Second)
+		defer cancel()
+
+		sensors, err := ble.DiscoverSensors(ctx, ble.DefaultAdapter())
+
+		safeUpdateUI(func() {
+
+			ob.scanButton.SetSensitive(true)
+			ob.scanButton.SetLabel("Scan Again")
+
+			if err != nil {
+				logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("sensor scan failed: %v", err))
+				displayAlertDialog(sc.UI.Window, "BLE Sensor Scan Failed", fmt.Sprintf("Unable to scan for BLE sensors:\n\n%v", err))
+
+				return
+			}
+
+			ob.showSensors(sensors)
+		})
+
+	}()
+
+}
+
+// showSensors lists the sensors found, selecting the sensor with the strongest signal
+func (ob *onboarding) showSensors(sensors []ble.DiscoveredSensor) {
+
+	ob.sensors = sensors
+	recordDiscoveredSensors(sensors)
+
+	if len(sensors) == 0 {
+		ob.sensorRow.SetModel(gtk.NewStringList([]string{onboardingNoSensors}))
+		ob.sensorRow.SetSensitive(false)
+
+		return
+	}
+
+	names := make([]string, len(sensors))
+	for i, sensor := range sensors {
+		names[i] = sensor.String()
+	}
+
+	ob.sensorRow.SetModel(gtk.NewStringList(names))
+	ob.sensorRow.SetSensitive(true)
+	ob.sensorRow.SetSelected(0)
+	ob.addressEntry.SetText(sensors[0].Address)
+
+}
+
+// selectWheelSize selects the wheel size matching the circumference (or the custom size)
+func (ob *onboarding) selectWheelSize(circumferenceMM int) {
+
+	selected := len(wheelSizes) - 1
+
+	for i, ws := range wheelSizes {
+
+		if ws.circumferenceMM == circumferenceMM {
+			selected = i
+
+			break
+		}
+	}
+
+	if ob.wheelRow.Selected() != uint(selected) {
+		ob.wheelRow.SetSelected(uint(selected))
+	}
+
+	if int(ob.circumference.Value()) != circumferenceMM {
+		ob.circumference.SetValue(float64(circumferenceMM))
+	}
+
+}
+
+// updateSaveButton enables the Save button once the session title, BD_ADDR, and video are valid
+func (ob *onboarding) updateSaveButton() {
+
+	valid := regexp.MustCompile(patternSessionTitle).MatchString(ob.titleEntry.Text()) &&
+		!ob.addressEntry.HasCSSClass("error") &&
+		ob.videoPath != ""
+
+	ob.saveButton.SetSensitive(valid)
+
+}
+
+// saveOnboardingSession saves the new session into the BSC configuration directory, and then
+// refreshes the session list
+func (sc *SessionController) saveOnboardingSession(ob *onboarding) {
+
+	cfg := createDefaultConfig(ob.videoPath)
+	cfg.App.SessionTitle = ob.titleEntry.Text()
+	cfg.BLE.SensorBDAddr = config.CanonicalBDAddr(ob.addressEntry.Text())
+	cfg.Speed.WheelCircumferenceMM = int(ob.circumference.Value())
+	cfg.Speed.SpeedUnits = speedUnits[ob.unitsRow.Selected()]
+
+	if err := cfg.Validate(); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("new session failed validation: %v", err))
+		displayAlertDialog(sc.UI.Window, "BSC Session Error", fmt.Sprintf("The new session is not valid:\n\n%v", err))
+
+		return
+	}
+
+	configDir, err := getSessionConfigDir()
+	if err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to get session config directory: %v", err))
+
+		return
+	}
+
+	path, err := newSessionPath(configDir, cfg.App.SessionTitle)
+	if err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to choose session file name: %v", err))
+
+		return
+	}
+
+	if err := config.Save(path, cfg, config.GetVersion()); err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save new session file: %v", err))
+		sc.displayErrorDialog("BSC Session Save Error", "Failed to save a new session file.\n\nPlease review the BSC Session Log for details.")
+
+		return
+	}
+
+	logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session file '%s' saved to: %s", cfg.App.SessionTitle, path))
+
+	ob.dialog.Close()
+
+	sc.scanForSessions(nil)
+
+	displayAlertDialog(sc.UI.Window, "BSC Session Saved", fmt.Sprintf("'%s' is ready to ride.\n\nSelect it in the BSC Sessions list, and then click Load Session.", cfg.App.SessionTitle))
+
+}
+
+// newSessionPath returns a path for a new session file (named after the session title) that
+// doesn't overwrite an existing session file
+func newSessionPath(configDir, sessionTitle string) (string, error) {
+
+	base := convertSessionTitle(sessionTitle)
+	path := filepath.Join(configDir, base+".toml")
+
+	for i := 2; ; i++ {
+
+		_, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return path, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(configDir, fmt.Sprintf("%s_%d.toml", base, i))
+	}
+
+}
+
+// newOnboardingGroup creates a preferences group with the given title and description
+func newOnboardingGroup(title, description string) *adw.PreferencesGroup {
+
+	group := adw.NewPreferencesGroup()
+	group.SetTitle(title)
+	group.SetDescription(description)
+
+	return group
+}
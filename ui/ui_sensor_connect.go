@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/session"
+)
+
+// setupSensorConnectSignals wires up event listeners for the sensor connect/disconnect button
+func (sc *SessionController) setupSensorConnectSignals() {
+
+	sc.UI.Page2.SensorConnectBtn.ConnectClicked(func() {
+
+		if sc.SessionManager.IsSensorReady() {
+			sc.handleSensorDisconnect()
+
+			return
+		}
+
+		sc.handleSensorConnect()
+
+	})
+
+}
+
+// handleSensorConnect connects to the BLE sensor of the session without starting video playback
+func (sc *SessionController) handleSensorConnect() {
+
+	logger.Info(logger.BackgroundCtx, logger.GUI, "connecting to BLE sensor...")
+
+	if sc.SessionManager.IsStarting() {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, "connect request ignored: start already pending")
+
+		return
+	}
+
+	// Request the connection right away, so that the session manager runs it ahead of any stop
+	// requested next
+	result := sc.SessionManager.ConnectSensorAsync()
+
+	safeUpdateUI(func() {
+		sc.updateSessionControlButton(true)
+		sc.updatePage2Status(StatusConnecting, StatusNotConnected, StatusUnknown)
+		sc.startScanProgressLoop()
+	})
+
+	go func() {
+
+		if err := <-result; err != nil {
+			sc.handleStartError(err)
+
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, "BLE sensor connected: ready to start video playback")
+
+		safeUpdateUI(func() {
+			sc.showSensorConnected()
+			sc.updateSessionControlButton(false)
+			sc.updateLastEvent()
+		})
+
+	}()
+
+}
+
+// handleSensorDisconnect disconnects the BLE sensor connected using the sensor connect button
+func (sc *SessionController) handleSensorDisconnect() {
+
+	logger.Info(logger.BackgroundCtx, logger.GUI, "disconnecting from BLE sensor...")
+
+	if err := sc.SessionManager.DisconnectSensor(); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to disconnect BLE sensor: %v", err))
+	}
+
+	safeUpdateUI(func() {
+		sc.updateSessionControlButton(false)
+		sc.updatePage2Status(StatusStopped, StatusNotConnected, StatusUnknown)
+		sc.updateLastEvent()
+	})
+
+}
+
+// handleStartPlayback starts video playback of a session whose sensor is already connected
+func (sc *SessionController) handleStartPlayback() {
+
+	logger.Info(logger.BackgroundCtx, logger.GUI, "starting BSC Session video playback...")
+
+	if err := sc.SessionManager.StartPlayback(); err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to start video playback: %v", err))
+		sc.displaySessionErrorDialog(err, "Failed to start the BSC Session video playback.\n\nPlease review the BSC Session Log for details.")
+
+		return
+	}
+
+	// Record start time for session playback
+	sc.startTime = time.Now()
+
+	safeUpdateUI(func() {
+		sc.updateSessionControlButton(true)
+		sc.showSensorConnected()
+
+		volume, muted := sc.SessionManager.VideoVolume()
+		sc.syncAudioControls(volume, muted, true)
+
+		sc.startMetricsLoop()
+		sc.updateIdleInhibitor()
+	})
+
+}
+
+// showSensorConnected shows the connected sensor status, including its nickname, battery level
+// and signal strength
+func (sc *SessionController) showSensorConnected() {
+
+	battery := fmt.Sprintf("%d%%", sc.SessionManager.BatteryLevel())
+	sc.updatePage2Status(StatusConnected, StatusConnected, battery)
+
+	status := statusTable[ObjectBLE][StatusConnected].Display
+
+	// Show the sensor by nickname (if it has one) rather than by BD_ADDR
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil {
+
+		if nickname := sensorNickname(cfg.BLE.SensorBDAddr); nickname != "" {
+			status = fmt.Sprintf("%s to %s", status, nickname)
+		}
+
+	}
+
+	if rssi, ok := sc.SessionManager.SensorRSSI(); ok {
+		status = fmt.Sprintf("%s (%d dBm)", status, rssi)
+	}
+
+	sc.UI.Page2.SensorStatusRow.SetSubtitle(status)
+
+}
+
+// updateSensorConnectButton sets the label and sensitivity of the sensor connect button: the
+// sensor can be connected while the session is stopped, and disconnected until playback starts
+func (sc *SessionController) updateSensorConnectButton() {
+
+	if sc.SessionManager.IsSensorReady() {
+		sc.UI.Page2.SensorConnectBtnContent.SetLabel("Disconnect Sensor")
+		sc.UI.Page2.SensorConnectBtnContent.SetIconName("bluetooth-disconnected-symbolic")
+		sc.UI.Page2.SensorConnectBtn.SetSensitive(true)
+
+		return
+	}
+
+	state := sc.SessionManager.SessionState()
+
+	sc.UI.Page2.SensorConnectBtnContent.SetLabel("Connect Sensor")
+	sc.UI.Page2.SensorConnectBtnContent.SetIconName("bluetooth-symbolic")
+	sc.UI.Page2.SensorConnectBtn.SetSensitive((state == session.StateLoaded || state == session.StateError) && !sc.SessionManager.IsStarting())
+
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// QR code decoder (from the zbar-tools package), and the time limit for decoding an image
+const (
+	qrDecoder        = "zbarimg"
+	qrDecodeTimeout  = 10 * time.Second
+	qrDecoderPackage = "zbar-tools"
+)
+
+// setupSensorImportSignals wires up the buttons of the BD_ADDR entry of the Session Editor, which
+// fill in the BD_ADDR from the clipboard, or the sensor details from a photo of its QR code
+func (sc *SessionController) setupSensorImportSignals() {
+
+	sc.UI.Page4.BTAddressPaste.ConnectClicked(func() {
+		sc.pasteBDAddr()
+	})
+
+	sc.UI.Page4.BTAddressQR.ConnectClicked(func() {
+		sc.openSensorQRFilePicker()
+	})
+
+}
+
+// pasteBDAddr fills in the BD_ADDR found in the clipboard text, cleaned of any surrounding text
+// (e.g., a line of "bluetoothctl devices" output) and in its canonical form
+func (sc *SessionController) pasteBDAddr() {
+
+	clipboard := sc.UI.Window.Clipboard()
+
+	clipboard.ReadTextAsync(logger.BackgroundCtx, func(res gio.AsyncResulter) {
+
+		text, err := clipboard.ReadTextFinish(res)
+		if err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to read clipboard: %v", err))
+
+			return
+		}
+
+		addr, ok := config.FindBDAddr(text)
+		if !ok {
+			displayAlertDialog(sc.UI.Window, "No BD_ADDR Found", "The clipboard doesn't hold a Bluetooth Device Address (e.g., FA:46:1D:77:C8:E1).")
+
+			return
+		}
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "BD_ADDR pasted from clipboard: "+addr)
+		sc.UI.Page4.BTAddressEntry.SetText(addr)
+
+	})
+
+}
+
+// openSensorQRFilePicker opens a native file dialog to select a photo (or screenshot) of the QR code
+// of a sensor, and then fills in the sensor details read from the QR code
+func (sc *SessionController) openSensorQRFilePicker() {
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle("Select Photo of Sensor QR Code")
+
+	// Set filters
+	filter := gtk.NewFileFilter()
+	filter.SetName("Image Files")
+	filter.AddMIMEType("image/*")
+
+	filters := gio.NewListStore(filter.Type())
+	filters.Append(filter.Object)
+	fileDialog.SetFilters(filters)
+
+	// Define callback to handle file selection
+	cb := func(res gio.AsyncResulter) {
+		file, err := fileDialog.OpenFinish(res)
+		if err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("File dialog cancelled or error: %v", err))
+
+			return
+		}
+
+		path := file.Path()
+		if path == "" {
+			return
+		}
+
+		// Decoding a large photo can take a moment, so keep the GUI responsive
+		go func() {
+
+			payload, err := decodeQRCode(path)
+
+			safeUpdateUI(func() {
+				sc.importSensorQR(payload, err)
+			})
+
+		}()
+	}
+
+	// Launch dialog
+	fileDialog.Open(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// importSensorQR fills in the BD_ADDR (and nickname, if given) of the sensor read from its QR code
+func (sc *SessionController) importSensorQR(payload string, decodeErr error) {
+
+	if errors.Is(decodeErr, exec.ErrNotFound) {
+		displayAlertDialog(sc.UI.Window, "QR Code Reader Not Found", fmt.Sprintf("Reading QR codes requires the %s command.\n\nInstall it (e.g., with \"sudo apt install %s\"), and then try again.", qrDecoder, qrDecoderPackage))
+
+		return
+	}
+
+	if decodeErr != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to read QR code: %v", decodeErr))
+		displayAlertDialog(sc.UI.Window, "No QR Code Found", "No QR code could be read from the selected image.\n\nTry a sharper photo, taken straight on and filling more of the frame.")
+
+		return
+	}
+
+	profile, err := config.ParseSensorQR(payload)
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to import QR code %q: %v", payload, err))
+		displayAlertDialog(sc.UI.Window, "No BD_ADDR Found", "The QR code doesn't hold a Bluetooth Device Address.")
+
+		return
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "sensor imported from QR code: "+profile.BDAddr)
+
+	sc.UI.Page4.BTAddressEntry.SetText(profile.BDAddr)
+
+	// Keep the nickname of a known sensor over the name printed in its QR code
+	if profile.Name != "" && sc.UI.Page4.SensorNickname.Text() == "" {
+		sc.UI.Page4.SensorNickname.SetText(profile.Name)
+	}
+
+}
+
+// decodeQRCode returns the payload of the QR code found in an image file
+func decodeQRCode(path string) (string, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), qrDecodeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, qrDecoder, "--raw", "--quiet", "-Sdisable", "-Sqrcode.enable", path).Output()
+	if err != nil {
+		return "", fmt.Errorf(errFormat, qrDecoder, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/ble"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Placeholder labels for the Known Sensors dropdown
+const (
+	knownSensorsPrompt = "Select a known sensor…"
+	knownSensorsNone   = "No known sensors"
+)
+
+// setupKnownSensorsSignals wires up the Known Sensors dropdown of the Session Editor, which fills
+// in the BD_ADDR (and nickname) of the selected sensor, and keeps the nickname in step with the
+// BD_ADDR
+func (sc *SessionController) setupKnownSensorsSignals() {
+
+	sc.UI.Page4.KnownSensors.Connect("notify::selected", func() {
+
+		// The first entry is a prompt, not a sensor
+		idx := int(sc.UI.Page4.KnownSensors.Selected()) - 1
+		if idx < 0 || idx >= len(sc.knownSensors) {
+			return
+		}
+
+		profile := sc.knownSensors[idx]
+		sc.UI.Page4.BTAddressEntry.SetText(profile.BDAddr)
+		sc.UI.Page4.SensorNickname.SetText(profile.Name)
+
+	})
+
+	// A BD_ADDR typed into the editor shows the nickname of that sensor (if known)
+	sc.UI.Page4.BTAddressEntry.ConnectChanged(func() {
+
+		nickname := ""
+
+		for _, profile := range sc.knownSensors {
+
+			if strings.EqualFold(profile.BDAddr, config.CanonicalBDAddr(sc.UI.Page4.BTAddressEntry.Text())) {
+				nickname = profile.Name
+
+				break
+			}
+		}
+
+		sc.UI.Page4.SensorNickname.SetText(nickname)
+
+	})
+
+}
+
+// populateKnownSensors lists the sensors of the sensor registry in the Known Sensors dropdown,
+// selecting the sensor with the given BD_ADDR (if known)
+func (sc *SessionController) populateKnownSensors(bdAddr string) {
+
+	p4 := sc.UI.Page4
+	sc.knownSensors = nil
+
+	if registry := loadSensorRegistry(); registry != nil {
+		sc.knownSensors = registry.Sensors
+	}
+
+	if len(sc.knownSensors) == 0 {
+		p4.KnownSensors.SetModel(gtk.NewStringList([]string{knownSensorsNone}))
+		p4.KnownSensors.SetSelected(0)
+		p4.SensorNickname.SetText("")
+
+		return
+	}
+
+	labels := []string{knownSensorsPrompt}
+	for _, profile := range sc.knownSensors {
+		labels = append(labels, profile.String())
+	}
+
+	p4.KnownSensors.SetModel(gtk.NewStringList(labels))
+	p4.KnownSensors.SetSelected(0)
+	p4.SensorNickname.SetText("")
+
+	for i, profile := range sc.knownSensors {
+
+		if strings.EqualFold(profile.BDAddr, config.CanonicalBDAddr(bdAddr)) {
+			p4.KnownSensors.SetSelected(uint(i + 1))
+			p4.SensorNickname.SetText(profile.Name)
+
+			break
+		}
+	}
+
+}
+
+// saveSensorNickname stores the nickname given to a sensor in the Session Editor in the sensor
+// registry
+func saveSensorNickname(bdAddr, nickname string) {
+
+	nickname = strings.TrimSpace(nickname)
+
+	updateSensorRegistry(func(r *config.SensorRegistry) {
+
+		// Nothing to save for an unnamed (or unchanged) sensor
+		profile, known := r.Lookup(bdAddr)
+		if (!known && nickname == "") || (known && profile.Name == nickname) {
+			return
+		}
+
+		r.Rename(bdAddr, nickname)
+		logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("BLE sensor %s nicknamed %q", bdAddr, nickname))
+
+	})
+
+}
+
+// recordDiscoveredSensors adds the sensors found by a sensor scan to the sensor registry, so they
+// can be selected in the Session Editor
+func recordDiscoveredSensors(sensors []ble.DiscoveredSensor) {
+
+	if len(sensors) == 0 {
+		return
+	}
+
+	updateSensorRegistry(func(r *config.SensorRegistry) {
+
+		for _, sensor := range sensors {
+
+			profile := config.SensorProfile{BDAddr: sensor.Address, LastSeen: time.Now()}
+
+			// A sensor's advertised name is only used until the sensor is given a nickname
+			if known, ok := r.Lookup(sensor.Address); !ok || known.Name == "" {
+				profile.Name = sensor.Name
+			}
+
+			r.Record(profile)
+		}
+
+	})
+
+}
+
+// sensorNickname returns the nickname of the sensor with the given BD_ADDR, or an empty string if
+// the sensor has no nickname
+func sensorNickname(bdAddr string) string {
+
+	registry := loadSensorRegistry()
+	if registry == nil {
+		return ""
+	}
+
+	if profile, ok := registry.Lookup(bdAddr); ok {
+		return profile.Name
+	}
+
+	return ""
+}
+
+// loadSensorRegistry loads the sensor registry, logging (and returning nil on) failure
+func loadSensorRegistry() *config.SensorRegistry {
+
+	path, err := config.SensorRegistryPath()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to locate sensor registry: %v", err))
+
+		return nil
+	}
+
+	registry, err := config.LoadSensorRegistry(path)
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to load sensor registry: %v", err))
+
+		return nil
+	}
+
+	return registry
+}
+
+// updateSensorRegistry applies an update to the sensor registry, logging any failure
+func updateSensorRegistry(update func(r *config.SensorRegistry)) {
+
+	path, err := config.SensorRegistryPath()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to locate sensor registry: %v", err))
+
+		return
+	}
+
+	if err := config.UpdateSensorRegistry(path, update); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to update sensor registry: %v", err))
+	}
+
+}
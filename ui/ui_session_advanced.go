@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// setupAdvancedSignals wires up the Advanced section of the Session Editor: the media player
+// arguments are validated as they're typed, and each reset button restores its setting to the
+// default value of the setting
+func (sc *SessionController) setupAdvancedSignals(onUpdate func()) {
+
+	p4 := sc.UI.Page4
+
+	// Reset buttons of the settings shown in spin rows, with the TOML key of each setting
+	spinResets := []struct {
+		button *gtk.Button
+		row    *adw.SpinRow
+		key    string
+	}{
+		{p4.ResetUpdateInterval, p4.UpdateInterval, "video.update_interval_secs"},
+		{p4.ResetSpeedThreshold, p4.SpeedThreshold, "speed.speed_threshold"},
+		{p4.ResetSpeedSmoothing, p4.SpeedSmoothing, "speed.smoothing_window"},
+		{p4.ResetMarginLeft, p4.MarginLeft, "video.OSD.margin_x"},
+		{p4.ResetMarginTop, p4.MarginTop, "video.OSD.margin_y"},
+	}
+
+	for _, reset := range spinResets {
+
+		reset.button.ConnectClicked(func() {
+			resetSpinRow(reset.row, reset.key)
+		})
+
+	}
+
+	p4.ResetPlayerArgs.ConnectClicked(func() {
+
+		args, _ := config.DefaultValue("video.media_player_args")
+		defaultArgs, _ := args.([]string)
+
+		p4.MediaPlayerArgs.SetText(config.FormatMediaPlayerArgs(defaultArgs))
+
+	})
+
+	p4.MediaPlayerArgs.Connect("changed", func() {
+
+		if _, err := config.ParseMediaPlayerArgs(p4.MediaPlayerArgs.Text()); err != nil {
+			p4.MediaPlayerArgs.AddCSSClass("error")
+		} else {
+			p4.MediaPlayerArgs.RemoveCSSClass("error")
+		}
+
+		onUpdate()
+
+	})
+
+}
+
+// resetSpinRow sets a spin row to the default value of its setting (given by its TOML key)
+func resetSpinRow(row *adw.SpinRow, key string) {
+
+	value, _ := config.DefaultValue(key)
+
+	switch defaultValue := value.(type) {
+	case int:
+		row.SetValue(float64(defaultValue))
+	case float64:
+		row.SetValue(defaultValue)
+	default:
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("no default value for setting %q", key))
+	}
+
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+)
+
+// updateCountdown shows the start countdown of the running session (if any) as a toast, counting
+// down the seconds until video playback can start
+func (sc *SessionController) updateCountdown() {
+
+	remaining := sc.SessionManager.StartCountdown()
+	if remaining <= 0 {
+		sc.resetCountdown()
+
+		return
+	}
+
+	seconds := int((remaining + time.Second - 1) / time.Second)
+	title := fmt.Sprintf("Starting in %d… clip in and get ready to pedal", seconds)
+
+	if sc.countdownToast != nil {
+		sc.countdownToast.SetTitle(title)
+
+		return
+	}
+
+	toast := adw.NewToast(title)
+	toast.SetTimeout(0)
+	toast.ConnectDismissed(func() {
+
+		if sc.countdownToast == toast {
+			sc.countdownToast = nil
+		}
+
+	})
+
+	sc.countdownToast = toast
+	sc.UI.ToastOverlay.AddToast(toast)
+
+}
+
+// resetCountdown dismisses the start countdown toast (if shown)
+func (sc *SessionController) resetCountdown() {
+
+	if sc.countdownToast == nil {
+		return
+	}
+
+	toast := sc.countdownToast
+	sc.countdownToast = nil
+	toast.Dismiss()
+
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// setupSessionDirActions creates the application action used to manage the session directories
+func setupSessionDirActions(app *gtk.Application, sc *SessionController) {
+
+	action := gio.NewSimpleAction("session-dirs", nil)
+	action.ConnectActivate(func(_ *glib.Variant) {
+		sc.showSessionDirsDialog()
+	})
+
+	app.AddAction(action)
+
+}
+
+// sessionDirs returns the directories searched for session files: the application's config
+// directory, followed by the session directories added in the application preferences (e.g., on
+// a network share holding both videos and session files)
+func (sc *SessionController) sessionDirs() []string {
+
+	var dirs []string
+
+	if configDir, err := getSessionConfigDir(); err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to get session config directory: %v", err))
+	} else {
+		dirs = append(dirs, configDir)
+	}
+
+	for _, dir := range sc.prefs.SessionDirs {
+
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// showSessionDirsDialog shows the session directories, where directories can be added (using a
+// folder chooser) and removed, saving them in the application preferences
+func (sc *SessionController) showSessionDirsDialog() {
+
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("Session Folders")
+	group.SetDescription("BSC sessions are listed from each of these folders (e.g., a folder on a network share)")
+
+	addButton := gtk.NewButtonWithLabel("Add Folder…")
+	addButton.SetVAlign(gtk.AlignCenter)
+	addButton.AddCSSClass("flat")
+	group.SetHeaderSuffix(addButton)
+
+	var rows []*adw.ActionRow
+
+	var refresh func()
+	refresh = func() {
+
+		for _, row := range rows {
+			group.Remove(row)
+		}
+
+		rows = nil
+
+		if configDir, err := getSessionConfigDir(); err == nil {
+			row := adw.NewActionRow()
+			row.SetTitle(configDir)
+			row.SetSubtitle("Default (new sessions are saved here)")
+			rows = append(rows, row)
+		}
+
+		for _, dir := range sc.prefs.SessionDirs {
+
+			removeButton := gtk.NewButtonFromIconName("user-trash-symbolic")
+			removeButton.SetTooltipText("Remove folder")
+			removeButton.SetVAlign(gtk.AlignCenter)
+			removeButton.AddCSSClass("flat")
+			removeButton.ConnectClicked(func() {
+				sc.prefs.RemoveSessionDir(dir)
+				sc.applySessionDirs()
+				refresh()
+			})
+
+			row := adw.NewActionRow()
+			row.SetTitle(dir)
+			row.AddSuffix(removeButton)
+			rows = append(rows, row)
+		}
+
+		for _, row := range rows {
+			group.Add(row)
+		}
+
+	}
+
+	refresh()
+
+	addButton.ConnectClicked(func() {
+		sc.chooseSessionDir(refresh)
+	})
+
+	page := adw.NewPreferencesPage()
+	page.Add(group)
+
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle("Session Folders")
+	dialog.Add(page)
+	dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
+
+// chooseSessionDir asks for a folder to add to the session directories
+func (sc *SessionController) chooseSessionDir(onAdd func()) {
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle("Add Session Folder")
+	fileDialog.SetModal(true)
+
+	cb := func(res gio.AsyncResulter) {
+
+		folder, err := fileDialog.SelectFolderFinish(res)
+		if err != nil {
+			return
+		}
+
+		// Remote locations are only supported when mounted locally (e.g., by the file manager)
+		dir := folder.Path()
+		if dir == "" {
+			displayAlertDialog(sc.UI.Window, "Folder Not Supported", fmt.Sprintf("%s is not mounted as a local folder.\n\nMount the network share (e.g., by opening it in the file manager), and then add it again.", folder.URI()))
+
+			return
+		}
+
+		if !sc.prefs.AddSessionDir(dir) {
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, "session folder added: "+dir)
+
+		sc.applySessionDirs()
+		onAdd()
+
+	}
+
+	fileDialog.SelectFolder(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// applySessionDirs saves the session directories in the application preferences, then watches
+// and rescans them
+func (sc *SessionController) applySessionDirs() {
+
+	sc.savePreferences()
+	sc.watchSessionDirs()
+	sc.scanForSessions(nil)
+
+}
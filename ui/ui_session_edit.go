@@ -15,18 +15,41 @@ import (
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/session"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+	"github.com/richbl/go-ble-sync-cycle/internal/video"
 )
 
 // Maps for dropdown list widgets
 var (
 	logLevels      = []string{"debug", "info", "warn", "error"}
-	speedUnits     = []string{"mph", "km/h"}
+	speedUnits     = []string{config.SpeedUnitsMPH, config.SpeedUnitsKMH, config.SpeedUnitsMPS, config.SpeedUnitsRPM}
 	mediaPlayers   = []string{"mpv"}
 	targetDisplays = []string{""}
+	focusModes     = []string{config.FocusModeOff, config.FocusModeHide}
 	alignX         = []string{"left", "center", "right"}
 	alignY         = []string{"top", "center", "bottom"}
 )
 
+// Monitor index of each target display in the dropdown list (the "default" entry being the
+// primary display, monitor 0)
+var targetDisplayMonitors = []uint{0}
+
+// Target display of the session being edited and its row in the dropdown list, so that the target
+// is saved in its original form (e.g., a monitor index) unless another display is selected
+var (
+	editedDisplayTarget string
+	editedDisplayRow    uint
+)
+
+// Placeholder displayed when no subtitle file is configured
+const placeholderNoSubtitleFile = "none"
+
+// Validation patterns for the Session Title and video seek/start time widgets
+const (
+	patternSessionTitle = `^[^<&\"]{1,200}$`
+	patternStartTime    = `^\d{2}:[0-5]\d:[0-5]\d$`
+)
+
 // setupSessionEditSignals wires up event listeners for the Edit tab and its controls
 func (sc *SessionController) setupSessionEditSignals() {
 
@@ -54,9 +77,22 @@ func (sc *SessionController) setupSessionEditSignals() {
 	}
 
 	// Define widget validators for Session Title, BD_ADDR, and video seek/start time
-	bindValidator(sc.UI.Page4.TitleEntry, `^[^<&\"]{1,200}$`, updateSaveButtons)
-	bindValidator(sc.UI.Page4.BTAddressEntry, `^([0-9A-Fa-f]{2}:){5}[0-9A-Fa-f]{2}$`, updateSaveButtons)
-	bindValidator(sc.UI.Page4.StartTimeEntry, `^\d{2}:[0-5]\d:[0-5]\d$`, updateSaveButtons)
+	bindValidator(sc.UI.Page4.TitleEntry, patternSessionTitle, updateSaveButtons)
+	bindBDAddrValidator(sc.UI.Page4.BTAddressEntry, updateSaveButtons)
+	bindValidator(sc.UI.Page4.StartTimeEntry, patternStartTime, updateSaveButtons)
+	sc.setupSpeedZoneSignals(updateSaveButtons)
+	sc.setupNightModeSignals(updateSaveButtons)
+	sc.setupAdvancedSignals(updateSaveButtons)
+
+	// Known Sensors dropdown
+	sc.setupKnownSensorsSignals()
+	sc.setupSensorImportSignals()
+
+	// Configuration warnings
+	sc.setupConfigWarningsSignals()
+	sc.setupGoalSignals()
+	sc.setupIntervalSignals()
+	sc.setupRaceSignals()
 
 	// Video file picker dialog
 	sc.UI.Page4.VideoFileButton.ConnectClicked(func() {
@@ -64,6 +100,17 @@ func (sc *SessionController) setupSessionEditSignals() {
 		sc.openVideoFilePicker()
 	})
 
+	// Subtitle file picker dialog
+	sc.UI.Page4.SubtitleFileBtn.ConnectClicked(func() {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "Subtitle file button clicked")
+		sc.openSubtitleFilePicker()
+	})
+
+	// Subtitle file clear button
+	sc.UI.Page4.SubtitleClearBtn.ConnectClicked(func() {
+		sc.UI.Page4.SubtitleFileRow.SetSubtitle(placeholderNoSubtitleFile)
+	})
+
 	// Save button
 	sc.UI.Page4.SaveButton.ConnectClicked(func() {
 		sc.saveSession(false) // Save to current path
@@ -105,6 +152,9 @@ func (sc *SessionController) updateSaveButtonState() {
 	isTitleValid := titleEntry.Text() != "" && !titleEntry.HasCSSClass("error")
 	isBDAddrValid := bdAddrEntry.Text() != "" && !bdAddrEntry.HasCSSClass("error")
 	isTimeValid := timeEntry.Text() != "" && !timeEntry.HasCSSClass("error")
+	isZonesValid := !p4.SpeedZones.HasCSSClass("error")
+	isNightModeValid := !p4.NightModeHours.HasCSSClass("error")
+	isPlayerArgsValid := !p4.MediaPlayerArgs.HasCSSClass("error")
 
 	// Validate VideoFileRow
 	videoPath := videoFileRow.Subtitle()
@@ -116,7 +166,7 @@ func (sc *SessionController) updateSaveButtonState() {
 		videoFileRow.AddCSSClass("error")
 	}
 
-	canSave := isTitleValid && isBDAddrValid && isTimeValid && isVideoValid
+	canSave := isTitleValid && isBDAddrValid && isTimeValid && isZonesValid && isNightModeValid && isPlayerArgsValid && isVideoValid && sc.isGoalValid() && sc.isIntervalsValid() && sc.isRaceValid()
 
 	p4.SaveButton.SetSensitive(canSave)
 	p4.SaveAsButton.SetSensitive(canSave)
@@ -166,6 +216,9 @@ func (sc *SessionController) populateEditor() {
 	// Refresh button states (Save, Delete)
 	sc.updateSaveButtonState()
 
+	// Show any settings that are likely mistakes
+	sc.updateConfigWarnings()
+
 }
 
 // populateEditorFields maps configuration data to UI widgets
@@ -177,10 +230,15 @@ func (sc *SessionController) populateEditorFields(cfg *config.Config, path strin
 	p4.TitleEntry.SetText(cfg.App.SessionTitle)
 	p4.SessionFileRow.SetSubtitle(path)
 	p4.LogLevel.SetSelected(indexOf(cfg.App.LogLevel, logLevels))
+	p4.Backups.SetValue(float64(cfg.App.BackupCount))
+	p4.MaxSessionTime.SetValue(float64(cfg.App.MaxSessionMinutes))
+	p4.ShutdownTime.SetValue(float64(cfg.App.ShutdownTimeoutSecs))
 
 	// --- BLE Section ---
 	p4.BTAddressEntry.SetText(cfg.BLE.SensorBDAddr)
+	sc.populateKnownSensors(cfg.BLE.SensorBDAddr)
 	p4.ScanTimeout.SetValue(float64(cfg.BLE.ScanTimeoutSecs))
+	p4.ScanAttempts.SetValue(float64(cfg.BLE.ScanAttempts))
 
 	// --- Speed Section ---
 	p4.WheelCircumference.SetValue(float64(cfg.Speed.WheelCircumferenceMM))
@@ -188,30 +246,62 @@ func (sc *SessionController) populateEditorFields(cfg *config.Config, path strin
 	p4.SpeedThreshold.SetValue(cfg.Speed.SpeedThreshold)
 	p4.SpeedThreshold.SetSubtitle(cfg.Speed.SpeedUnits)
 	p4.SpeedSmoothing.SetValue(float64(cfg.Speed.SmoothingWindow))
+	p4.SpeedZones.SetText(config.FormatSpeedZones(cfg.Speed.SpeedZones))
+
+	// --- Goal Section ---
+	p4.GoalType.SetSelected(indexOf(cfg.Goal.Type, goalTypes))
+	p4.GoalTarget.SetValue(cfg.Goal.Target)
+	p4.RiderWeight.SetValue(float64(cfg.Goal.RiderWeightKG))
+	sc.updateGoalTargetSubtitle()
+	p4.IntervalHard.SetValue(float64(cfg.Intervals.HardSecs))
+	p4.IntervalEasy.SetValue(float64(cfg.Intervals.EasySecs))
+	sc.updateIntervalValidity()
+	sc.populateRace(cfg.Race)
 
 	// --- Video Section ---
 	p4.MediaPlayer.SetSelected(indexOf(cfg.Video.MediaPlayer, mediaPlayers))
 	p4.VideoFileRow.SetSubtitle(cfg.Video.FilePath)
 	p4.StartTimeEntry.SetText(cfg.Video.SeekToPosition)
+	p4.SwitchSubtitles.SetActive(cfg.Video.ShowSubtitles)
+
+	if cfg.Video.SubtitlePath != "" {
+		p4.SubtitleFileRow.SetSubtitle(cfg.Video.SubtitlePath)
+	} else {
+		p4.SubtitleFileRow.SetSubtitle(placeholderNoSubtitleFile)
+	}
+
 	p4.SwitchAutoResume.SetActive(cfg.Video.AutoResume)
+	p4.SwitchResumeDist.SetActive(cfg.Video.ResumeDistance)
+	p4.SwitchResumeDist.SetSubtitle(resumeDistanceSubtitle(cfg))
+	p4.StartCountdown.SetValue(float64(cfg.Video.StartCountdownSecs))
+	p4.ResumeFade.SetValue(cfg.Video.ResumeFadeSecs)
 	p4.WindowScale.SetValue(cfg.Video.WindowScaleFactor)
 	p4.UpdateInterval.SetValue(cfg.Video.UpdateIntervalSec)
 	p4.SpeedMultiplier.SetValue(cfg.Video.SpeedMultiplier)
+	p4.MinPlaybackSpeed.SetValue(cfg.Video.MinPlaybackSpeed)
+	p4.MaxPlaybackSpeed.SetValue(cfg.Video.MaxPlaybackSpeed)
+	p4.Interpolation.SetValue(cfg.Video.InterpolationSpeed)
 
 	// Dynamically build comboRow list elements for display targets, then set values
 	p4.setupTargetDisplayCombo(cfg.Video.TargetDisplayName)
-	p4.TargetDisplayName.SetSelected(indexOf(cfg.Video.TargetDisplayName, targetDisplays))
+	p4.FocusMode.SetSelected(indexOf(cfg.Video.FocusMode, focusModes))
 
 	// --- OSD Section ---
 	p4.SwitchCycleSpeed.SetActive(cfg.Video.OnScreenDisplay.DisplayCycleSpeed)
 	p4.SwitchPlaybackSpeed.SetActive(cfg.Video.OnScreenDisplay.DisplayPlaybackSpeed)
 	p4.SwitchTimeRemaining.SetActive(cfg.Video.OnScreenDisplay.DisplayTimeRemaining)
+	p4.SwitchSpeedStats.SetActive(cfg.Video.OnScreenDisplay.DisplaySpeedStats)
 	p4.FontSize.SetValue(float64(cfg.Video.OnScreenDisplay.FontSize))
 	p4.MarginLeft.SetValue(float64(cfg.Video.OnScreenDisplay.MarginX))
 	p4.MarginTop.SetValue(float64(cfg.Video.OnScreenDisplay.MarginY))
+	p4.NightModeHours.SetText(cfg.Video.OnScreenDisplay.NightModeHours)
+	p4.NightBrightness.SetValue(float64(cfg.Video.OnScreenDisplay.NightBrightness))
 	p4.AlignX.SetSelected(indexOf(cfg.Video.OnScreenDisplay.AlignX, alignX))
 	p4.AlignY.SetSelected(indexOf(cfg.Video.OnScreenDisplay.AlignY, alignY))
 
+	// --- Advanced Section ---
+	p4.MediaPlayerArgs.SetText(config.FormatMediaPlayerArgs(cfg.Video.MediaPlayerArgs))
+
 }
 
 // setupTargetDisplayCombo populates the ComboRow with active Wayland monitors
@@ -231,7 +321,8 @@ func (ui *PageSessionEditor) setupTargetDisplayCombo(currentConfigTarget string)
 	ui.TargetDisplayName.SetModel(stringList)
 	ui.TargetDisplayName.SetSensitive(len(targetDisplays) > 1)
 
-	selectInitialDisplay(ui.TargetDisplayName, currentConfigTarget)
+	editedDisplayTarget = currentConfigTarget
+	editedDisplayRow = selectInitialDisplay(ui.TargetDisplayName, currentConfigTarget)
 
 }
 
@@ -257,6 +348,7 @@ func populateSecondaryMonitors(monitors *gio.ListModel, stringList *gtk.StringLi
 
 	// Reset the list of secondary displays to just the "default" entry
 	targetDisplays = []string{""}
+	targetDisplayMonitors = []uint{0}
 
 	if monitors == nil {
 		return
@@ -271,8 +363,9 @@ func populateSecondaryMonitors(monitors *gio.ListModel, stringList *gtk.StringLi
 		if mon, ok := item.Cast().(*gdk.Monitor); ok {
 			name := mon.Connector()
 			if name != "" {
-				stringList.Append(name)
+				stringList.Append(fmt.Sprintf("%s (display %d)", name, i))
 				targetDisplays = append(targetDisplays, name)
+				targetDisplayMonitors = append(targetDisplayMonitors, i)
 			}
 
 		}
@@ -280,8 +373,24 @@ func populateSecondaryMonitors(monitors *gio.ListModel, stringList *gtk.StringLi
 
 }
 
-// selectInitialDisplay sets the initial selection for the target display dropdown
-func selectInitialDisplay(comboRow *adw.ComboRow, currentConfigTarget string) {
+// selectInitialDisplay sets the initial selection for the target display dropdown, returning the
+// selected row
+func selectInitialDisplay(comboRow *adw.ComboRow, currentConfigTarget string) uint {
+
+	// Session files may identify the target display by monitor index, which isn't its row (as
+	// the list skips monitors without a connector name)
+	if index, ok := video.DisplayIndex(currentConfigTarget); ok {
+
+		for row, monitor := range targetDisplayMonitors {
+
+			if monitor == index {
+				comboRow.SetSelected(uint(row))
+
+				return uint(row)
+			}
+
+		}
+	}
 
 	if currentConfigTarget != "" {
 
@@ -290,7 +399,7 @@ func selectInitialDisplay(comboRow *adw.ComboRow, currentConfigTarget string) {
 			if val == currentConfigTarget {
 				comboRow.SetSelected(uint(idx))
 
-				return
+				return uint(idx)
 			}
 
 		}
@@ -299,6 +408,18 @@ func selectInitialDisplay(comboRow *adw.ComboRow, currentConfigTarget string) {
 	// Fallback to the first item (default)
 	comboRow.SetSelected(0)
 
+	return 0
+}
+
+// selectedDisplayTarget returns the target display for a row of the target display dropdown,
+// keeping the target of the session being edited if its row is still selected
+func selectedDisplayTarget(row uint) string {
+
+	if row == editedDisplayRow {
+		return editedDisplayTarget
+	}
+
+	return targetDisplays[row]
 }
 
 // toggleSensitive enables or disables widgets
@@ -328,19 +449,28 @@ func toggleSensitive(p4 *PageSessionEditor, enabled bool) {
 
 }
 
-// harvestEditor gathers data from widgets into a new Config struct
+// harvestEditor gathers data from widgets into a new Config struct, preserving any settings of
+// the session being edited that have no corresponding editor widget
 func (sc *SessionController) harvestEditor() *config.Config {
 
 	p4 := sc.UI.Page4
 	cfg := &config.Config{}
 
+	if current := sc.SessionManager.Config(); current != nil {
+		*cfg = *current
+	}
+
 	// App
 	cfg.App.SessionTitle = p4.TitleEntry.Text()
 	cfg.App.LogLevel = logLevels[p4.LogLevel.Selected()]
+	cfg.App.BackupCount = int(p4.Backups.Value())
+	cfg.App.MaxSessionMinutes = int(p4.MaxSessionTime.Value())
+	cfg.App.ShutdownTimeoutSecs = int(p4.ShutdownTime.Value())
 
 	// BLE
-	cfg.BLE.SensorBDAddr = p4.BTAddressEntry.Text()
+	cfg.BLE.SensorBDAddr = config.CanonicalBDAddr(p4.BTAddressEntry.Text())
 	cfg.BLE.ScanTimeoutSecs = int(p4.ScanTimeout.Value())
+	cfg.BLE.ScanAttempts = int(p4.ScanAttempts.Value())
 
 	// Speed
 	cfg.Speed.WheelCircumferenceMM = int(p4.WheelCircumference.Value())
@@ -348,32 +478,76 @@ func (sc *SessionController) harvestEditor() *config.Config {
 	cfg.Speed.SpeedThreshold = p4.SpeedThreshold.Value()
 	cfg.Speed.SmoothingWindow = int(p4.SpeedSmoothing.Value())
 
+	if zones, err := config.ParseSpeedZones(p4.SpeedZones.Text()); err == nil {
+		cfg.Speed.SpeedZones = zones
+	}
+
+	// Goal
+	cfg.Goal.Type = goalTypes[p4.GoalType.Selected()]
+	cfg.Goal.Target = p4.GoalTarget.Value()
+	cfg.Goal.RiderWeightKG = int(p4.RiderWeight.Value())
+	cfg.Intervals = sc.selectedIntervals()
+	cfg.Race = sc.selectedRace()
+
 	// Video
 	cfg.Video.MediaPlayer = mediaPlayers[p4.MediaPlayer.Selected()]
 	cfg.Video.FilePath = p4.VideoFileRow.Subtitle()
 	cfg.Video.SeekToPosition = p4.StartTimeEntry.Text()
+	cfg.Video.ShowSubtitles = p4.SwitchSubtitles.Active()
+	cfg.Video.SubtitlePath = ""
+
+	if path := p4.SubtitleFileRow.Subtitle(); path != placeholderNoSubtitleFile {
+		cfg.Video.SubtitlePath = path
+	}
+
 	cfg.Video.AutoResume = p4.SwitchAutoResume.Active()
+	cfg.Video.ResumeDistance = p4.SwitchResumeDist.Active()
+	cfg.Video.StartCountdownSecs = int(p4.StartCountdown.Value())
+	cfg.Video.ResumeFadeSecs = p4.ResumeFade.Value()
 	cfg.Video.WindowScaleFactor = p4.WindowScale.Value()
 	cfg.Video.UpdateIntervalSec = p4.UpdateInterval.Value()
 	cfg.Video.SpeedMultiplier = p4.SpeedMultiplier.Value()
-	cfg.Video.TargetDisplayName = targetDisplays[p4.TargetDisplayName.Selected()]
+	cfg.Video.MinPlaybackSpeed = p4.MinPlaybackSpeed.Value()
+	cfg.Video.MaxPlaybackSpeed = p4.MaxPlaybackSpeed.Value()
+	cfg.Video.InterpolationSpeed = p4.Interpolation.Value()
+	cfg.Video.TargetDisplayName = selectedDisplayTarget(p4.TargetDisplayName.Selected())
+	cfg.Video.FocusMode = focusModes[p4.FocusMode.Selected()]
 
 	// OSD
 	cfg.Video.OnScreenDisplay.DisplayCycleSpeed = p4.SwitchCycleSpeed.Active()
 	cfg.Video.OnScreenDisplay.DisplayPlaybackSpeed = p4.SwitchPlaybackSpeed.Active()
 	cfg.Video.OnScreenDisplay.DisplayTimeRemaining = p4.SwitchTimeRemaining.Active()
+	cfg.Video.OnScreenDisplay.DisplaySpeedStats = p4.SwitchSpeedStats.Active()
 	cfg.Video.OnScreenDisplay.FontSize = int(p4.FontSize.Value())
 	cfg.Video.OnScreenDisplay.MarginX = int(p4.MarginLeft.Value())
 	cfg.Video.OnScreenDisplay.MarginY = int(p4.MarginTop.Value())
+	cfg.Video.OnScreenDisplay.NightModeHours = strings.TrimSpace(p4.NightModeHours.Text())
+	cfg.Video.OnScreenDisplay.NightBrightness = int(p4.NightBrightness.Value())
 	cfg.Video.OnScreenDisplay.AlignX = alignX[p4.AlignX.Selected()]
 	cfg.Video.OnScreenDisplay.AlignY = alignY[p4.AlignY.Selected()]
 
+	// Advanced
+	if args, err := config.ParseMediaPlayerArgs(p4.MediaPlayerArgs.Text()); err == nil {
+		cfg.Video.MediaPlayerArgs = args
+	}
+
 	return cfg
 }
 
-// openVideoFilePicker opens a native file dialog to select a video
+// openVideoFilePicker opens a native file dialog to select a video for the Session Editor
 func (sc *SessionController) openVideoFilePicker() {
 
+	sc.chooseVideoFile(func(path string) {
+		sc.UI.Page4.VideoFileRow.SetSubtitle(path)
+		sc.updateSaveButtonState()
+	})
+
+}
+
+// chooseVideoFile opens a native file dialog to select a video, calling onSelected (on the main
+// thread) with the path of the selected video
+func (sc *SessionController) chooseVideoFile(onSelected func(path string)) {
+
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "Opening video file dialog...")
 
 	fileDialog := gtk.NewFileDialog()
@@ -416,8 +590,56 @@ func (sc *SessionController) openVideoFilePicker() {
 			logger.Debug(logger.BackgroundCtx, logger.GUI, "File selected: "+path)
 
 			if path != "" {
-				sc.UI.Page4.VideoFileRow.SetSubtitle(path)
-				sc.updateSaveButtonState()
+				onSelected(path)
+			}
+
+		})
+	}
+
+	// Launch dialog
+	fileDialog.Open(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// openSubtitleFilePicker opens a native file dialog to select a subtitle file
+func (sc *SessionController) openSubtitleFilePicker() {
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "Opening subtitle file dialog...")
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle("Select Subtitle File")
+
+	// Set filters
+	filter := gtk.NewFileFilter()
+	filter.SetName("Subtitle Files")
+	filter.AddPattern("*.srt")
+	filter.AddPattern("*.ass")
+	filter.AddPattern("*.ssa")
+	filter.AddPattern("*.vtt")
+	filter.AddPattern("*.sub")
+
+	filters := gio.NewListStore(filter.Type())
+	filters.Append(filter.Object)
+	fileDialog.SetFilters(filters)
+
+	// Define callback to handle file selection
+	cb := func(res gio.AsyncResulter) {
+		file, err := fileDialog.OpenFinish(res)
+		if err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("File dialog cancelled or error: %v", err))
+
+			return
+		}
+
+		// Update the UI with the selected path
+		path := file.Path()
+
+		safeUpdateUI(func() {
+			logger.Debug(logger.BackgroundCtx, logger.GUI, "Subtitle file selected: "+path)
+
+			if path != "" {
+				sc.UI.Page4.SubtitleFileRow.SetSubtitle(path)
+				sc.UI.Page4.SwitchSubtitles.SetActive(true)
 			}
 
 		})
@@ -434,6 +656,9 @@ func (sc *SessionController) saveSession(saveAs bool) {
 	// Harvest the data from the UI widgets
 	newConfig := sc.harvestEditor()
 
+	// Sensor nicknames are kept in the sensor registry, not the session file
+	saveSensorNickname(newConfig.BLE.SensorBDAddr, sc.UI.Page4.SensorNickname.Text())
+
 	// We get the path from the SessionManager
 	currentPath := sc.SessionManager.EditConfigPath()
 
@@ -510,7 +735,7 @@ func (sc *SessionController) performSessionSave(path string, cfg *config.Config)
 		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save config: %v", err))
 
 		safeUpdateUI(func() {
-			displayAlertDialog(sc.UI.Window, "BSC Session Save Error", fmt.Sprintf("The file %s could not be saved.\n\nPlease review the BSC Session Log for details.", path))
+			sc.displayErrorDialog("BSC Session Save Error", fmt.Sprintf("The file %s could not be saved.\n\nPlease review the BSC Session Log for details.", path))
 		})
 
 		return
@@ -539,8 +764,7 @@ func (sc *SessionController) performSessionSave(path string, cfg *config.Config)
 	}
 
 	// Refresh the Session List (Page 1) to show new session(s)
-	sc.scanForSessions()
-	sc.PopulateSessionList()
+	sc.scanForSessions(nil)
 
 	// Refresh the Session Editor UI to show the updated file data
 	sc.populateEditor()
@@ -587,7 +811,7 @@ func (sc *SessionController) performDelete(path, title, loadedPath string) {
 	if err := os.Remove(path); err != nil {
 		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to delete session file: %v", err))
 		safeUpdateUI(func() {
-			displayAlertDialog(sc.UI.Window, "BSC Session Delete Error", fmt.Sprintf("The file %s could not be deleted.\n\nPlease review the BSC Session Log for details.", path))
+			sc.displayErrorDialog("BSC Session Delete Error", fmt.Sprintf("The file %s could not be deleted.\n\nPlease review the BSC Session Log for details.", path))
 		})
 
 		return
@@ -605,8 +829,7 @@ func (sc *SessionController) performDelete(path, title, loadedPath string) {
 			sc.clearPage2()
 		}
 
-		sc.scanForSessions()
-		sc.PopulateSessionList()
+		sc.scanForSessions(nil)
 
 		displayAlertDialog(sc.UI.Window, "BSC Session Deleted", fmt.Sprintf("'%s' has been deleted.", title))
 	})
@@ -627,12 +850,17 @@ func (sc *SessionController) resetEditor() {
 
 	// Explicitly override the TargetDisplayName with an "n/a" placeholder
 	targetDisplays = []string{"n/a"}
+	targetDisplayMonitors = []uint{0}
+	editedDisplayTarget, editedDisplayRow = targetDisplays[0], 0
 	p4.TargetDisplayName.SetModel(gtk.NewStringList(targetDisplays))
 	p4.TargetDisplayName.SetSelected(0)
 
 	// Disable all widgets
 	toggleSensitive(p4, false)
 
+	// No configuration warnings without a session
+	p4.WarningsGroup.SetVisible(false)
+
 }
 
 // deleteSession initiates the session deletion process
@@ -677,6 +905,17 @@ func convertSessionTitle(sessionTitle string) string {
 	return "BSC_session"
 }
 
+// resumeDistanceSubtitle returns the subtitle of the Carry Over Distance switch, showing the
+// distance ridden so far (if any)
+func resumeDistanceSubtitle(cfg *config.Config) string {
+
+	if cfg.Video.ResumeDistanceKM <= 0 {
+		return ""
+	}
+
+	return units.FormatDistance(cfg.Video.ResumeDistanceKM, cfg.Speed.SpeedUnits) + " ridden so far"
+}
+
 // indexOf returns the index of the target string in the slice
 func indexOf(target string, options []string) uint {
 
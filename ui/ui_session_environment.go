@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// resetEnvironment hides the environmental sensor readings on the Session Status page
+func (sc *SessionController) resetEnvironment() {
+
+	sc.UI.Page2.EnvironmentRow.SetVisible(false)
+	sc.UI.Page2.EnvironmentLabel.SetLabel("--")
+
+}
+
+// updateEnvironment shows the latest temperature and humidity reported by the environmental sensor
+// on the Session Status page (in Fahrenheit when the session speed units are mph)
+func (sc *SessionController) updateEnvironment() {
+
+	reading, ok := sc.SessionManager.Environment()
+	if !ok {
+		sc.UI.Page2.EnvironmentRow.SetVisible(false)
+
+		return
+	}
+
+	speedUnits := config.SpeedUnitsKMH
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil {
+		speedUnits = cfg.Speed.SpeedUnits
+	}
+
+	temperature := units.FormatTemperature(reading.TemperatureC, speedUnits)
+
+	label := temperature
+	if reading.HasHumidity {
+		label = fmt.Sprintf("%s · %.0f%% RH", temperature, reading.HumidityPct)
+	}
+
+	sc.UI.Page2.EnvironmentRow.SetVisible(true)
+	sc.UI.Page2.EnvironmentRow.SetSubtitle("updated " + reading.Time.Format(time.Kitchen))
+	sc.UI.Page2.EnvironmentLabel.SetLabel(label)
+
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/apperr"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// displaySessionErrorDialog shows a session error with a title and advice matching its category
+// (see the apperr package), offering to restart the session when retrying might help. Errors
+// without a category are shown with the fallback message, and the Session Log saved for review
+func (sc *SessionController) displaySessionErrorDialog(err error, fallback string) {
+
+	category := apperr.CategoryOf(err)
+
+	switch category {
+	case apperr.ConfigError:
+		displayAlertDialog(sc.UI.Window, "BSC Session Configuration Error", fmt.Sprintf("The BSC Session configuration is not valid:\n\n%v\n\nPlease edit the BSC Session and try again.", err))
+
+	case apperr.PlayerError:
+		sc.displayErrorDialog("BSC Session Video Error", fmt.Sprintf("The media player failed:\n\n%v", err))
+
+	case apperr.SensorError:
+		sc.displayRetryDialog("BSC Session Sensor Error", fmt.Sprintf("The BLE sensor could not be used:\n\n%v\n\nCheck that the sensor is awake and in range, and then retry.", err))
+
+	case apperr.Timeout:
+		sc.displayRetryDialog(sessionTimeout, fmt.Sprintf("A device or service stopped responding:\n\n%v", err))
+
+	default:
+		sc.displayErrorDialog(sessionError, fallback)
+	}
+
+}
+
+// displayRetryDialog shows an error that retrying might fix, with a Retry button that restarts the
+// session
+func (sc *SessionController) displayRetryDialog(title, message string) {
+
+	const (
+		retry       = "retry"
+		closeDialog = "close"
+	)
+
+	dialog := adw.NewAlertDialog(title, message)
+	dialog.AddResponse(closeDialog, "Close")
+	dialog.AddResponse(retry, "Retry")
+	dialog.SetResponseAppearance(retry, adw.ResponseSuggested)
+	dialog.SetDefaultResponse(retry)
+	dialog.SetCloseResponse(closeDialog)
+
+	dialog.ConnectResponse(func(response string) {
+
+		if response != retry {
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, "retrying BSC Session start...")
+
+		if err := sc.handleSessionControl(); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to retry session: %v", err))
+		}
+
+	})
+
+	dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Goal reached notification identifier
+const goalNotificationID = "bsc-goal"
+
+// Maps for the goal type dropdown list widget
+var goalTypes = []string{config.GoalTypeNone, config.GoalTypeTime, config.GoalTypeDistance, config.GoalTypeCalories}
+
+// setupGoalSignals wires up the Session Goal widgets of the Session Editor, keeping the goal
+// target units current and requiring a goal target when a goal type is selected
+func (sc *SessionController) setupGoalSignals() {
+
+	p4 := sc.UI.Page4
+	update := func() {
+		sc.updateGoalTargetSubtitle()
+		sc.updateSaveButtonState()
+	}
+
+	p4.GoalType.Connect("notify::selected", update)
+	p4.GoalTarget.Connect("notify::value", update)
+	p4.SpeedUnits.Connect("notify::selected", update)
+
+}
+
+// selectedGoal returns the session goal selected in the Session Editor
+func (sc *SessionController) selectedGoal() (config.GoalConfig, string) {
+
+	p4 := sc.UI.Page4
+	goal := config.GoalConfig{Target: p4.GoalTarget.Value()}
+
+	if idx := p4.GoalType.Selected(); idx < uint(len(goalTypes)) {
+		goal.Type = goalTypes[idx]
+	}
+
+	units := config.SpeedUnitsMPH
+	if idx := p4.SpeedUnits.Selected(); idx < uint(len(speedUnits)) {
+		units = speedUnits[idx]
+	}
+
+	return goal, units
+}
+
+// updateGoalTargetSubtitle shows the units of the goal target (e.g., "minutes of riding time")
+func (sc *SessionController) updateGoalTargetSubtitle() {
+
+	goal, targetUnits := sc.selectedGoal()
+
+	subtitle := "no goal"
+
+	switch goal.Type {
+	case config.GoalTypeTime:
+		subtitle = "minutes of riding time"
+	case config.GoalTypeDistance:
+		subtitle = units.DistanceUnits(targetUnits) + " ridden"
+	case config.GoalTypeCalories:
+		subtitle = "kilocalories burned (estimated)"
+	}
+
+	sc.UI.Page4.GoalTarget.SetSubtitle(subtitle)
+
+	if sc.isGoalValid() {
+		sc.UI.Page4.GoalTarget.RemoveCSSClass("error")
+	} else {
+		sc.UI.Page4.GoalTarget.AddCSSClass("error")
+	}
+
+}
+
+// isGoalValid returns false if a goal type is selected without a goal target
+func (sc *SessionController) isGoalValid() bool {
+
+	goal, _ := sc.selectedGoal()
+
+	return !goal.Enabled() || goal.Target > 0
+}
+
+// resetGoal hides the session goal progress on the Session Status page
+func (sc *SessionController) resetGoal() {
+
+	sc.UI.Page2.GoalRow.SetVisible(false)
+	sc.UI.Page2.GoalRow.SetSubtitle("no goal")
+	sc.UI.Page2.GoalLabel.SetLabel("0%")
+
+}
+
+// updateGoal shows the progress toward the session goal on the Session Status page, and sends a
+// notification (with an alert sound) when the goal is reached
+func (sc *SessionController) updateGoal() {
+
+	progress, ok := sc.SessionManager.GoalProgress()
+	if !ok || !progress.Goal.Enabled() {
+		sc.UI.Page2.GoalRow.SetVisible(false)
+
+		return
+	}
+
+	cfg := sc.SessionManager.ActiveConfig()
+	units := config.SpeedUnitsMPH
+	if cfg != nil {
+		units = cfg.Speed.SpeedUnits
+	}
+
+	subtitle := fmt.Sprintf("%s (%s %s so far)", progress.Goal.Describe(units), config.FormatGoalValue(progress.Goal.Type, progress.Value), progress.Units)
+	if progress.Reached {
+		subtitle = fmt.Sprintf("%s (reached)", progress.Goal.Describe(units))
+	}
+
+	sc.UI.Page2.GoalRow.SetVisible(true)
+	sc.UI.Page2.GoalRow.SetSubtitle(subtitle)
+	sc.UI.Page2.GoalLabel.SetLabel(fmt.Sprintf("%.0f%%", progress.Percent()))
+
+	// Notify once for each ride that reaches its goal
+	if !progress.Reached || progress.Started.Equal(sc.goalNotified) {
+		return
+	}
+
+	sc.goalNotified = progress.Started
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, "session goal reached: sending notification")
+
+	notification := gio.NewNotification("BSC Session Goal Reached")
+	notification.SetBody(fmt.Sprintf("Goal reached: %s. Nice ride!", progress.Goal.Describe(units)))
+	notification.SetDefaultAction("app.show-window")
+
+	sc.UI.Window.Application().SendNotification(goalNotificationID, notification)
+
+	if display := gdk.DisplayGetDefault(); display != nil {
+		display.Beep()
+	}
+
+}
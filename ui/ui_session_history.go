@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Number of the most ridden videos shown on the Totals card
+const totalsTopVideos = 3
+
+// Session notes dialog responses
+const (
+	notesResponseSave = "save"
+	notesResponseSkip = "skip"
+)
+
+// setupSessionHistorySignals wires up event listeners for the Session History tab (Page 5)
+func (sc *SessionController) setupSessionHistorySignals() {
+
+	sc.UI.Page5.SearchEntry.ConnectSearchChanged(sc.populateHistory)
+
+}
+
+// populateHistory lists the completed rides of the session history matching the search query,
+// newest first
+func (sc *SessionController) populateHistory() {
+
+	listBox := sc.UI.Page5.ListBox
+	listBox.RemoveAll()
+
+	records, err := loadHistory(sc.UI.Page5.SearchEntry.Text())
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to load session history: %v", err))
+	}
+
+	if len(records) == 0 {
+		row := adw.NewActionRow()
+		row.SetTitle("No rides found")
+		listBox.Append(row)
+
+		return
+	}
+
+	for _, record := range records {
+		row := adw.NewActionRow()
+		row.SetTitle(html.EscapeString(fmt.Sprintf("%s (%s)", record.Title, record.Started.Local().Format("Jan 2, 2006 3:04 PM"))))
+		row.SetSubtitle(html.EscapeString(historySubtitle(record)))
+		row.SetSubtitleLines(3)
+
+		if zoneTotal(record.ZoneSecs) > 0 {
+			row.AddSuffix(newZoneBar(record.ZoneSecs))
+		}
+
+		listBox.Append(row)
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session history lists %d ride(s)", len(records)))
+
+}
+
+// loadHistory loads the session history records matching the search query, newest first
+func loadHistory(query string) ([]config.SessionRecord, error) {
+
+	path, err := config.SessionHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := config.LoadSessionHistory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Search(query), nil
+}
+
+// populateTotals shows the usage statistics (the lifetime totals of all completed rides) on the
+// Totals card
+func (sc *SessionController) populateTotals() {
+
+	page := sc.UI.Page5
+
+	stats, err := loadUsageStats()
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to load usage statistics: %v", err))
+	}
+
+	if stats == nil || stats.Sessions == 0 {
+
+		for _, row := range []*adw.ActionRow{page.TotalsTimeRow, page.TotalsDistanceRow, page.TotalsSessionsRow, page.TotalsVideosRow} {
+			row.SetSubtitle("no rides")
+		}
+
+		return
+	}
+
+	page.TotalsTimeRow.SetSubtitle(fmt.Sprintf("%.1f hours", float64(stats.RideSecs)/3600))
+	page.TotalsDistanceRow.SetSubtitle(fmt.Sprintf("%.1f km · %.0f kcal", stats.DistanceKM, stats.Calories))
+	page.TotalsSessionsRow.SetSubtitle(fmt.Sprintf("%d since %s", stats.Sessions, stats.Since.Local().Format("Jan 2, 2006")))
+
+	var videos []string
+	for _, video := range stats.TopVideos(totalsTopVideos) {
+		videos = append(videos, fmt.Sprintf("%s (%d)", video.Video, video.Sessions))
+	}
+
+	if len(videos) == 0 {
+		page.TotalsVideosRow.SetSubtitle("no videos recorded")
+
+		return
+	}
+
+	page.TotalsVideosRow.SetSubtitle(html.EscapeString(strings.Join(videos, "\n")))
+
+}
+
+// loadUsageStats loads the usage statistics
+func loadUsageStats() (*config.UsageStats, error) {
+
+	path, err := config.UsageStatsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.LoadUsageStats(path)
+}
+
+// historySubtitle summarizes a completed ride (e.g., "1:00:00 · 25.2 km · 610 kcal"), followed by
+// its tags and notes
+func historySubtitle(record config.SessionRecord) string {
+
+	summary := fmt.Sprintf("%d:%02d:%02d · %.1f km · %.0f kcal", record.RideSecs/3600, record.RideSecs/60%60, record.RideSecs%60, record.DistanceKM, record.Calories)
+
+	if record.MaxSpeedKPH > 0 {
+		summary += fmt.Sprintf(" · %.1f avg / %.1f max km/h", record.AvgSpeedKPH, record.MaxSpeedKPH)
+	}
+
+	if record.GoalReached {
+		summary += " · goal reached"
+	}
+
+	if len(record.Laps) > 0 {
+		summary += fmt.Sprintf(" · %d laps", len(record.Laps))
+	}
+
+	if record.RaceScore > 0 {
+		summary += fmt.Sprintf(" · race score %.0f%%", record.RaceScore)
+	}
+
+	lines := []string{summary}
+
+	if len(record.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(record.Tags, ", "))
+	}
+
+	if record.Notes != "" {
+		lines = append(lines, record.Notes)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// showSessionNotesDialog asks for notes and tags to add to the ride just recorded in the session
+// history
+func (sc *SessionController) showSessionNotesDialog(record config.SessionRecord) {
+
+	dialog := adw.NewAlertDialog("Add Session Notes", "Add notes and tags to this ride to find it later on the Session History page.")
+
+	notesRow := adw.NewEntryRow()
+	notesRow.SetTitle("Notes")
+
+	tagsRow := adw.NewEntryRow()
+	tagsRow.SetTitle("Tags (comma-separated, e.g., recovery, FTP test)")
+
+	list := gtk.NewListBox()
+	list.SetSelectionMode(gtk.SelectionNone)
+	list.AddCSSClass("boxed-list")
+	list.Append(notesRow)
+	list.Append(tagsRow)
+
+	content := gtk.NewBox(gtk.OrientationVertical, 12)
+
+	// Summarize the time spent in each speed zone (if speed zones are configured)
+	if zoneTotal(record.ZoneSecs) > 0 {
+
+		label := gtk.NewLabel("Time in Speed Zones")
+		label.SetHAlign(gtk.AlignStart)
+		label.AddCSSClass("heading")
+
+		bar := newZoneBar(record.ZoneSecs)
+		bar.SetHExpand(true)
+
+		content.Append(label)
+		content.Append(bar)
+	}
+
+	content.Append(list)
+	dialog.SetExtraChild(content)
+
+	dialog.AddResponse(notesResponseSkip, "Skip")
+	dialog.AddResponse(notesResponseSave, "Save")
+	dialog.SetResponseAppearance(notesResponseSave, adw.ResponseSuggested)
+	dialog.SetDefaultResponse(notesResponseSave)
+	dialog.SetCloseResponse(notesResponseSkip)
+
+	dialog.ConnectResponse(func(response string) {
+
+		notes := strings.TrimSpace(notesRow.Text())
+		tags := config.ParseTags(tagsRow.Text())
+
+		if response != notesResponseSave || (notes == "" && len(tags) == 0) {
+			return
+		}
+
+		go func() {
+
+			if err := sc.SessionManager.AnnotateSession(record, notes, tags); err != nil {
+				logger.Warn(logger.BackgroundCtx, logger.GUI, err.Error())
+
+				return
+			}
+
+			safeUpdateUI(sc.populateHistory)
+		}()
+
+	})
+
+	dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
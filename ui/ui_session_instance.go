@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/session"
+)
+
+// setupInstanceActions creates the application action used to add a new session instance
+func setupInstanceActions(app *gtk.Application, sc *SessionController) {
+
+	action := gio.NewSimpleAction("new-instance", nil)
+	action.ConnectActivate(func(_ *glib.Variant) {
+		sc.addSessionInstance()
+	})
+
+	app.AddAction(action)
+
+}
+
+// setupInstanceSwitcherSignals wires up event listeners for the session instance switcher
+func (sc *SessionController) setupInstanceSwitcherSignals() {
+
+	sc.refreshInstanceSwitcher()
+
+	sc.UI.Page2.InstanceRow.Connect("notify::selected", func() {
+
+		if sc.syncingInst {
+			return
+		}
+
+		sc.switchSessionInstance(int(sc.UI.Page2.InstanceRow.Selected()))
+
+	})
+
+}
+
+// refreshInstanceSwitcher rebuilds the session instance list, selecting the instance currently
+// shown in the GUI
+func (sc *SessionController) refreshInstanceSwitcher() {
+
+	managers := sc.registry.Managers()
+	names := make([]string, len(managers))
+	selected := 0
+
+	for i, m := range managers {
+		names[i] = fmt.Sprintf("Session %d", i+1)
+
+		if m == sc.SessionManager {
+			selected = i
+		}
+	}
+
+	sc.syncingInst = true
+	sc.UI.Page2.InstanceRow.SetModel(gtk.NewStringList(names))
+	sc.UI.Page2.InstanceRow.SetSelected(uint(selected))
+	sc.syncingInst = false
+
+}
+
+// addSessionInstance creates a new (Idle) session instance and switches the GUI to it
+func (sc *SessionController) addSessionInstance() {
+
+	sc.registry.Add().SetIntervalHandler(beepIntervalTransition)
+	logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session instance %d created", sc.registry.Len()))
+
+	sc.refreshInstanceSwitcher()
+	sc.UI.Page2.InstanceRow.SetSelected(uint(sc.registry.Len() - 1))
+	sc.UI.ViewStack.SetVisibleChildName("page2")
+
+}
+
+// switchSessionInstance shows the session instance at the given index in the GUI, leaving any
+// other running session instances running
+func (sc *SessionController) switchSessionInstance(index int) {
+
+	target := sc.registry.Manager(index)
+	if target == nil || target == sc.SessionManager {
+		return
+	}
+
+	// Don't switch away from a session instance while it is still starting up
+	if sc.SessionManager.IsStarting() {
+		displayAlertDialog(sc.UI.Window, "Switch Session Instance", "Please wait until the current BSC Session has started before switching session instances.")
+		sc.refreshInstanceSwitcher()
+
+		return
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("switching to session instance %d", index+1))
+
+	// Retain the ride time of the session instance being hidden
+	sc.startTimes[sc.SessionManager] = sc.startTime
+	sc.startTime = sc.startTimes[target]
+	delete(sc.startTimes, target)
+
+	sc.SessionManager = target
+	sc.metricsGen++
+
+	sc.showSessionInstance()
+
+}
+
+// showSessionInstance refreshes the Session Status and Session Editor pages using the session
+// instance currently shown in the GUI
+func (sc *SessionController) showSessionInstance() {
+
+	// Instances not shown may have stopped since the inhibitor was last updated
+	sc.updateIdleInhibitor()
+	sc.updateNightTheme()
+
+	// Refresh the Session Editor using this instance's edited session (if any)
+	if sc.SessionManager.Config() != nil {
+		sc.populateEditor()
+	} else {
+		sc.resetEditor()
+	}
+
+	cfg := sc.SessionManager.ActiveConfig()
+	if cfg == nil || sc.SessionManager.SessionState() == session.StateIdle {
+		sc.clearPage2()
+
+		return
+	}
+
+	sc.updatePage2WithSession(Session{Title: cfg.App.SessionTitle, ConfigPath: sc.SessionManager.LoadedConfigPath()})
+	sc.UI.Page2.LastEventRow.SetSubtitle("n/a")
+	sc.updateLastEvent()
+
+	if !sc.SessionManager.IsRunning() {
+		sc.UI.Page2.VolumeRow.SetSensitive(false)
+		sc.UI.Page2.SeekRow.SetSensitive(false)
+
+		if sc.SessionManager.SessionState() == session.StateError {
+			sc.updatePage2Status(StatusFailed, StatusNotConnected, StatusUnknown)
+		}
+
+		if sc.SessionManager.IsSensorReady() {
+			sc.showSensorConnected()
+		}
+
+		return
+	}
+
+	battery := fmt.Sprintf("%d%%", sc.SessionManager.BatteryLevel())
+	sc.updateSessionControlButton(true)
+	sc.updatePage2Status(StatusConnected, StatusConnected, battery)
+
+	volume, muted := sc.SessionManager.VideoVolume()
+	sc.syncAudioControls(volume, muted, true)
+
+	sc.startMetricsLoop()
+
+}
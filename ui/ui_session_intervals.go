@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/speed"
+)
+
+// setupIntervalSignals wires up the Interval Timer widgets of the Session Editor, requiring both a
+// hard and an easy interval (or neither)
+func (sc *SessionController) setupIntervalSignals() {
+
+	p4 := sc.UI.Page4
+	update := func() {
+		sc.updateIntervalValidity()
+		sc.updateSaveButtonState()
+	}
+
+	p4.IntervalHard.Connect("notify::value", update)
+	p4.IntervalEasy.Connect("notify::value", update)
+
+}
+
+// selectedIntervals returns the interval timer settings selected in the Session Editor
+func (sc *SessionController) selectedIntervals() config.IntervalConfig {
+
+	return config.IntervalConfig{
+		HardSecs: int(sc.UI.Page4.IntervalHard.Value()),
+		EasySecs: int(sc.UI.Page4.IntervalEasy.Value()),
+	}
+}
+
+// updateIntervalValidity flags the interval left unset when only one interval is set
+func (sc *SessionController) updateIntervalValidity() {
+
+	valid := sc.isIntervalsValid()
+
+	for _, spin := range []*adw.SpinRow{sc.UI.Page4.IntervalHard, sc.UI.Page4.IntervalEasy} {
+
+		if !valid && spin.Value() == 0 {
+			spin.AddCSSClass("error")
+		} else {
+			spin.RemoveCSSClass("error")
+		}
+	}
+
+}
+
+// isIntervalsValid returns false if only one of the hard and easy intervals is set
+func (sc *SessionController) isIntervalsValid() bool {
+
+	intervals := sc.selectedIntervals()
+
+	return (intervals.HardSecs > 0) == (intervals.EasySecs > 0)
+}
+
+// beepIntervalTransition sounds an alert at each interval transition of the interval timer
+func beepIntervalTransition(_ speed.IntervalState) {
+
+	safeUpdateUI(func() {
+
+		if display := gdk.DisplayGetDefault(); display != nil {
+			display.Beep()
+		}
+
+	})
+
+}
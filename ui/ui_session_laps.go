@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// setupLapActions creates the application action used to mark a lap (bound to its keyboard
+// shortcut by setupShortcutActions)
+func setupLapActions(app *gtk.Application, sc *SessionController) {
+
+	action := gio.NewSimpleAction("lap", nil)
+	action.ConnectActivate(func(_ *glib.Variant) {
+		sc.markLap()
+	})
+
+	app.AddAction(action)
+
+}
+
+// setupLapSignals wires up event listeners for the Lap button
+func (sc *SessionController) setupLapSignals() {
+
+	sc.UI.Page2.LapButton.ConnectClicked(sc.markLap)
+
+}
+
+// markLap marks a manual lap of the running session
+func (sc *SessionController) markLap() {
+
+	if err := sc.SessionManager.MarkLap(); err != nil {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("lap not marked: %v", err))
+	}
+
+}
+
+// resetLaps clears the laps shown on the Session Status page
+func (sc *SessionController) resetLaps() {
+
+	sc.UI.Page2.LapRow.SetTitle("Laps")
+	sc.UI.Page2.LapRow.SetSubtitle("no laps")
+	sc.UI.Page2.LapButton.SetSensitive(false)
+
+}
+
+// updateLaps shows the number of laps of the running session and the stats of the last lap on the
+// Session Status page
+func (sc *SessionController) updateLaps() {
+
+	sc.UI.Page2.LapButton.SetSensitive(sc.SessionManager.IsRunning())
+
+	laps := sc.SessionManager.Laps()
+	if len(laps) == 0 {
+		sc.UI.Page2.LapRow.SetTitle("Laps")
+		sc.UI.Page2.LapRow.SetSubtitle("no laps")
+
+		return
+	}
+
+	units := config.SpeedUnitsMPH
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil {
+		units = cfg.Speed.SpeedUnits
+	}
+
+	sc.UI.Page2.LapRow.SetTitle(fmt.Sprintf("Laps (%d)", len(laps)))
+	sc.UI.Page2.LapRow.SetSubtitle(laps[len(laps)-1].Describe(units))
+
+}
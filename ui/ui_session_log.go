@@ -1,12 +1,19 @@
 package ui
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/diamondburned/gotk4/pkg/pango"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 )
 
+// Maximum number of lines kept in the Session Log (the oldest lines are removed first)
+const sessionLogMaxLines = 5000
+
 // Regex to find ANSI escape sequences
 var ansiSplitRegex = regexp.MustCompile(`(\x1b\[[0-9;]*m)`)
 
@@ -22,47 +29,207 @@ var ansiToTag = map[string]string{
 	"\x1b[0m":  "reset",   // Reset
 }
 
-// GuiLogWriter implements io.Writer to bridge application logs to a GTK TextView
+// Regex to match the header of a log message (timestamp, level, and optional component), with or
+// without the ANSI codes coloring each part
+var logHeaderRegex = regexp.MustCompile(`^(?:\x1b\[[0-9;]*m)?(\d{2}:\d{2}:\d{2})(?:\x1b\[0m)? (?:\x1b\[[0-9;]*m)?(\[[A-Z]{3}\])(?:\x1b\[0m)? (?:(?:\x1b\[[0-9;]*m)?(\[[A-Z]{3}\])(?:\x1b\[0m)? )?`)
+
+// Map log levels to their GTK Tag names and colors
+var levelTags = map[string]logTag{
+	"[DBG]": {"level-debug", "#8be9fd"},
+	"[INF]": {"level-info", "#50fa7b"},
+	"[WRN]": {"level-warn", "#f1fa8c"},
+	"[ERR]": {"level-error", "#ff5555"},
+	"[FTL]": {"level-fatal", "#ff79c6"},
+}
+
+// Map log components to their GTK Tag names and colors, so each component stands out
+var componentTags = map[string]logTag{
+	string(logger.APP):   {"component-app", "#f8f8f2"},
+	string(logger.BLE):   {"component-ble", "#bd93f9"},
+	string(logger.SPEED): {"component-speed", "#ffb86c"},
+	string(logger.VIDEO): {"component-video", "#ff92df"},
+	string(logger.GUI):   {"component-gui", "#a4ffff"},
+}
+
+// logTag defines a GTK Tag used to style part of a log message
+type logTag struct {
+	name  string
+	color string
+}
+
+// GuiLogWriter implements io.Writer to bridge application logs to a GTK TextView, batching log
+// messages into a single TextView update per GTK idle callback
 type GuiLogWriter struct {
-	textView *gtk.TextView
-	buffer   *gtk.TextBuffer
+	textView   *gtk.TextView
+	buffer     *gtk.TextBuffer
+	endMark    *gtk.TextMark
+	autoScroll bool // Scroll to new log messages (main thread only)
+
+	mu        sync.Mutex
+	pending   []string
+	scheduled bool
+	history   strings.Builder // Every log message written (without ANSI codes), for saving the log
 }
 
 // setupSessionLogSignals wires up event listeners for the Session Log view (Page 3)
 func (sc *SessionController) setupSessionLogSignals() {
+
+	sc.UI.Page3.LogLevelRow.Connect("notify::selected", func() {
+
+		idx := sc.UI.Page3.LogLevelRow.Selected()
+		if idx < uint(len(logLevels)) {
+			sc.changeLogLevel(logLevels[idx])
+		}
+
+	})
+
+	sc.UI.Page3.SaveLogButton.ConnectClicked(sc.exportSessionLog)
+
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "Session Log: signals setup complete")
 }
 
 // UpdateLogLevel updates the log level component in the view
 func (sc *SessionController) UpdateLogLevel() {
-	sc.UI.Page3.LogLevelRow.SetTitle(logger.LogLevel())
+	sc.UI.Page3.LogLevelRow.SetSelected(indexOf(strings.ToLower(logger.LogLevel()), logLevels))
+}
+
+// changeLogLevel changes the logging level of the running application, and selects it in the
+// Session Editor when editing the loaded session, so the level is saved with the session
+func (sc *SessionController) changeLogLevel(level string) {
+
+	if strings.EqualFold(level, logger.LogLevel()) {
+		return
+	}
+
+	logger.SetLogLevel(level)
+	logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("logging level changed to %s", level))
+
+	mgr := sc.SessionManager
+	if path := mgr.EditConfigPath(); path != "" && path == mgr.LoadedConfigPath() {
+		sc.UI.Page4.LogLevel.SetSelected(indexOf(level, logLevels))
+	}
+
 }
 
 // NewGuiLogWriter creates a new writer for the specified TextView and initializes color tags
 func NewGuiLogWriter(tv *gtk.TextView) *GuiLogWriter {
 
 	w := &GuiLogWriter{
-		textView: tv,
-		buffer:   tv.Buffer(),
+		textView:   tv,
+		buffer:     tv.Buffer(),
+		autoScroll: true,
 	}
 	w.initTags()
+	w.endMark = w.buffer.CreateMark("log-end", w.buffer.EndIter(), false)
 
 	return w
 }
 
-// Write satisfies the io.Writer interface, parsing ANSI codes and inserts styled text
+// Write satisfies the io.Writer interface, queuing the log message to be inserted with any other
+// messages written before the next GTK idle callback
 func (w *GuiLogWriter) Write(p []byte) (int, error) {
 
-	fullText := string(p)
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	safeUpdateUI(func() {
-		w.processAnsiAndInsert(fullText)
-	})
+	w.pending = append(w.pending, string(p))
+	w.history.WriteString(ansiSplitRegex.ReplaceAllString(string(p), ""))
+
+	if !w.scheduled {
+		w.scheduled = true
+		safeUpdateUI(w.flush)
+	}
 
 	return len(p), nil
 }
 
-// processAnsiAndInsert parses the text for ANSI codes and inserts into the buffer
+// Text returns every log message written, including those no longer shown in the Session Log
+func (w *GuiLogWriter) Text() string {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.history.String()
+}
+
+// SetAutoScroll sets whether the Session Log scrolls to new log messages (scrolling to the newest
+// message when enabled)
+func (w *GuiLogWriter) SetAutoScroll(enabled bool) {
+
+	w.autoScroll = enabled
+
+	if enabled {
+		w.textView.ScrollToMark(w.endMark, 0, false, 0, 0)
+	}
+
+}
+
+// flush inserts the queued log messages as styled text, removes the oldest lines beyond the
+// Session Log line limit, and scrolls to the newest message (if enabled)
+func (w *GuiLogWriter) flush() {
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.scheduled = false
+	w.mu.Unlock()
+
+	for _, text := range batch {
+		w.insertLogMessage(text)
+	}
+
+	w.trimLines()
+
+	if w.autoScroll {
+		w.textView.ScrollToMark(w.endMark, 0, false, 0, 0)
+	}
+
+}
+
+// trimLines removes the oldest lines of the Session Log beyond its line limit
+func (w *GuiLogWriter) trimLines() {
+
+	// The buffer ends with an empty line after the last newline-terminated message
+	excess := w.buffer.LineCount() - 1 - sessionLogMaxLines
+	if excess <= 0 {
+		return
+	}
+
+	end, _ := w.buffer.IterAtLine(excess)
+	w.buffer.Delete(w.buffer.StartIter(), end)
+
+}
+
+// insertLogMessage inserts a log message into the buffer, styling its timestamp, level, and
+// component by their GTK Tags, and the rest of the message by its ANSI codes
+func (w *GuiLogWriter) insertLogMessage(text string) {
+
+	match := logHeaderRegex.FindStringSubmatchIndex(text)
+	if match == nil {
+		w.processAnsiAndInsert(text)
+
+		return
+	}
+
+	table := w.buffer.TagTable()
+	endIter := w.buffer.EndIter()
+
+	w.insertWithTag(endIter, text[match[2]:match[3]]+" ", table.Lookup("log-time"))
+
+	level := text[match[4]:match[5]]
+	w.insertWithTag(endIter, level+" ", table.Lookup(levelTags[level].name))
+
+	if match[6] >= 0 {
+		component := text[match[6]:match[7]]
+		w.insertWithTag(endIter, component+" ", table.Lookup(componentTags[component].name))
+	}
+
+	w.processAnsiAndInsert(text[match[1]:])
+
+}
+
+// processAnsiAndInsert parses the text for ANSI codes and inserts into the buffer (without the
+// ANSI codes, which are replaced by their GTK Tags)
 func (w *GuiLogWriter) processAnsiAndInsert(text string) {
 
 	// Get the logger endpoint
@@ -141,4 +308,16 @@ func (w *GuiLogWriter) initTags() {
 	createTag("cyan", "#8be9fd")
 	createTag("white", "#f8f8f2")
 
+	// Log message header styles (dimmed timestamp, and bold levels and components)
+	createTag("log-time", "#6272a4")
+
+	for _, tags := range []map[string]logTag{levelTags, componentTags} {
+
+		for _, tag := range tags {
+			createTag(tag.name, tag.color)
+			table.Lookup(tag.name).SetObjectProperty("weight", int32(pango.WeightBold))
+		}
+
+	}
+
 }
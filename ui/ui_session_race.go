@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
+)
+
+// Placeholder text for the outdoor ride when no GPX file is selected
+const placeholderNoOutdoorGPXFile = "none"
+
+// setupRaceSignals wires up the Race widgets of the Session Editor, keeping the target speed units
+// current and requiring a target speed when an easy interval target speed is set
+func (sc *SessionController) setupRaceSignals() {
+
+	p4 := sc.UI.Page4
+	update := func() {
+		sc.updateRaceValidity()
+		sc.updateSaveButtonState()
+	}
+
+	p4.RaceTarget.Connect("notify::value", update)
+	p4.RaceEasyTarget.Connect("notify::value", update)
+	p4.SpeedUnits.Connect("notify::selected", update)
+
+	// Outdoor ride file picker dialog
+	p4.OutdoorGPXFileBtn.ConnectClicked(func() {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "Outdoor ride file button clicked")
+		sc.openOutdoorGPXFilePicker()
+	})
+
+	// Outdoor ride clear button
+	p4.OutdoorGPXClearBtn.ConnectClicked(func() {
+		p4.OutdoorGPXFileRow.SetSubtitle(placeholderNoOutdoorGPXFile)
+	})
+
+}
+
+// populateRace shows the race settings of the session in the Session Editor
+func (sc *SessionController) populateRace(race config.RaceConfig) {
+
+	p4 := sc.UI.Page4
+	p4.RaceTarget.SetValue(race.TargetSpeed)
+	p4.RaceEasyTarget.SetValue(race.EasyTargetSpeed)
+
+	if race.OutdoorGPXFile != "" {
+		p4.OutdoorGPXFileRow.SetSubtitle(race.OutdoorGPXFile)
+	} else {
+		p4.OutdoorGPXFileRow.SetSubtitle(placeholderNoOutdoorGPXFile)
+	}
+
+	sc.updateRaceValidity()
+
+}
+
+// selectedRace returns the race settings selected in the Session Editor
+func (sc *SessionController) selectedRace() config.RaceConfig {
+
+	race := config.RaceConfig{
+		TargetSpeed:     sc.UI.Page4.RaceTarget.Value(),
+		EasyTargetSpeed: sc.UI.Page4.RaceEasyTarget.Value(),
+	}
+
+	if path := sc.UI.Page4.OutdoorGPXFileRow.Subtitle(); path != placeholderNoOutdoorGPXFile {
+		race.OutdoorGPXFile = path
+	}
+
+	return race
+}
+
+// openOutdoorGPXFilePicker opens a native file dialog to select the GPX file of an outdoor ride
+func (sc *SessionController) openOutdoorGPXFilePicker() {
+
+	fileDialog := gtk.NewFileDialog()
+	fileDialog.SetTitle("Select Outdoor Ride")
+
+	// Set filters
+	filter := gtk.NewFileFilter()
+	filter.SetName("GPX Files")
+	filter.AddPattern("*.gpx")
+	filter.AddPattern("*.GPX")
+
+	filters := gio.NewListStore(filter.Type())
+	filters.Append(filter.Object)
+	fileDialog.SetFilters(filters)
+
+	// Define callback to handle file selection
+	cb := func(res gio.AsyncResulter) {
+		file, err := fileDialog.OpenFinish(res)
+		if err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("File dialog cancelled or error: %v", err))
+
+			return
+		}
+
+		path := file.Path()
+
+		safeUpdateUI(func() {
+			logger.Debug(logger.BackgroundCtx, logger.GUI, "Outdoor ride file selected: "+path)
+
+			if path != "" {
+				sc.UI.Page4.OutdoorGPXFileRow.SetSubtitle(path)
+			}
+
+		})
+	}
+
+	// Launch dialog
+	fileDialog.Open(logger.BackgroundCtx, &sc.UI.Window.Window, cb)
+
+}
+
+// updateRaceValidity shows the units of the target speeds, flagging the target speed left unset
+// when only the easy interval target speed is set
+func (sc *SessionController) updateRaceValidity() {
+
+	p4 := sc.UI.Page4
+
+	targetUnits := config.SpeedUnitsMPH
+	if idx := p4.SpeedUnits.Selected(); idx < uint(len(speedUnits)) {
+		targetUnits = speedUnits[idx]
+	}
+
+	p4.RaceTarget.SetSubtitle(targetUnits + " (0 for no race)")
+	p4.RaceEasyTarget.SetSubtitle(targetUnits + " (0 for the target speed)")
+
+	if sc.isRaceValid() {
+		p4.RaceTarget.RemoveCSSClass("error")
+	} else {
+		p4.RaceTarget.AddCSSClass("error")
+	}
+
+}
+
+// isRaceValid returns false if an easy interval target speed is set without a target speed
+func (sc *SessionController) isRaceValid() bool {
+
+	race := sc.selectedRace()
+
+	return race.EasyTargetSpeed == 0 || race.Enabled()
+}
+
+// resetRace clears the race shown on the Session Status page
+func (sc *SessionController) resetRace() {
+
+	sc.UI.Page2.RaceRow.SetVisible(false)
+	sc.UI.Page2.RaceRow.SetSubtitle("no race")
+	sc.UI.Page2.RaceLabel.SetLabel("0%")
+
+}
+
+// updateRace shows the speed against the target speed of a race against the clock, and the
+// effort score so far, on the Session Status page
+func (sc *SessionController) updateRace() {
+
+	score, ok := sc.SessionManager.RaceScore()
+	if !ok {
+		sc.UI.Page2.RaceRow.SetVisible(false)
+
+		return
+	}
+
+	targetUnits := config.SpeedUnitsMPH
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil {
+		targetUnits = cfg.Speed.SpeedUnits
+	}
+
+	subtitle := "waiting for the race to start"
+	if score.Scored > 0 {
+		subtitle = fmt.Sprintf("%s / %s %s (%+.1f)", units.FormatSpeed(score.Speed, targetUnits), units.FormatSpeed(score.Target, targetUnits), targetUnits, score.Deviation)
+	}
+
+	sc.UI.Page2.RaceRow.SetVisible(true)
+	sc.UI.Page2.RaceRow.SetSubtitle(subtitle)
+	sc.UI.Page2.RaceLabel.SetLabel(fmt.Sprintf("%.0f%%", score.Score))
+
+}
@@ -6,11 +6,11 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
-	"sync/atomic"
 	"time"
 
 	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
 	"github.com/diamondburned/gotk4/pkg/core/glib"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
@@ -25,21 +25,46 @@ const placeholderNullVideoFile = "new_session_null_video"
 type SessionController struct {
 	UI             *AppUI
 	Sessions       []Session
-	SessionManager *session.StateManager
+	SessionManager *session.StateManager // The session instance currently shown in the GUI
+	registry       *session.Registry
 	shutdownMgr    *services.ShutdownManager
-	starting       atomic.Bool
 	startTime      time.Time
+	startTimes     map[*session.StateManager]time.Time // Start times of the instances not shown
+	goalNotified   time.Time                           // Ride start time of the last goal reached notification
 	metricsLoop    glib.SourceHandle
+	metricsGen     uint
+	speedGraph     speedGraph
 	saveFileDialog *gtk.FileDialog
+	knownSensors   []config.SensorProfile // Sensors listed in the Known Sensors dropdown
+	sessionCache   *config.SessionCache   // Metadata of the session files found by previous scans
+	scanGen        uint                   // Generation of the latest session scan
+	dirWatchers    []*gio.FileMonitor     // Monitors of changes to the session directories
+	rescanTimer    glib.SourceHandle      // Pending rescan of the session directory (0 if none)
+	videoCheckGen  uint                   // Generation of the latest video check of a loaded session
+	syncingAudio   bool
+	syncingInst    bool
+	inBackground   bool
+	prefs          *config.Preferences // Application preferences (e.g., the keyboard shortcuts)
+	countdownToast *adw.Toast          // Toast counting down the start of the session (if shown)
+	focus          *focusLayout        // GUI layout saved while a focus mode is active (if any)
+	idleInhibitor  uint                // Cookie of the screensaver inhibitor held while a session runs (0 if none)
+	nightTheme     bool                // Dark theme applied by the night mode of the running session
 }
 
 // NewSessionController creates the controller
 func NewSessionController(ui *AppUI, shutdownMgr *services.ShutdownManager) *SessionController {
 
+	registry := session.NewRegistry()
+	registry.Manager(0).SetIntervalHandler(beepIntervalTransition)
+
 	return &SessionController{
 		UI:             ui,
-		SessionManager: session.NewManager(),
+		SessionManager: registry.Manager(0),
+		registry:       registry,
 		shutdownMgr:    shutdownMgr,
+		startTimes:     make(map[*session.StateManager]time.Time),
+		sessionCache:   config.NewSessionCache(),
+		prefs:          loadPreferences(),
 	}
 }
 
@@ -69,6 +94,12 @@ func (sc *SessionController) PopulateSessionList() {
 	for _, s := range sc.Sessions {
 		row := adw.NewActionRow()
 		row.SetTitle(s.Title)
+
+		// Show where sessions found outside of the application's config directory are stored
+		if dir := filepath.Dir(s.ConfigPath); slices.Contains(sc.prefs.SessionDirs, dir) {
+			row.SetSubtitle(dir)
+		}
+
 		sc.UI.Page1.ListBox.Append(row)
 	}
 
@@ -81,27 +112,6 @@ func (sc *SessionController) PopulateSessionList() {
 
 }
 
-// CheckForNoSessions checks if any session files exist and prompts to create one if not
-func (sc *SessionController) CheckForNoSessions() {
-
-	if len(sc.Sessions) == 0 {
-		logger.Debug(logger.BackgroundCtx, logger.GUI, "no session configuration files found, prompting to create new session...")
-
-		safeUpdateUI(func() {
-			displayConfirmationDialog(
-				sc.UI.Window,
-				"No BSC Sessions",
-				"No Configuration files found in the BSC configuration directory.\n\nDo you want to create a new BSC session file?",
-				adw.ResponseSuggested,
-				func() {
-					sc.createNewDefaultSession()
-				},
-			)
-		})
-	}
-
-}
-
 // setupSessionSelectSignals wires up event listeners for the session selection tab (Page 1)
 func (sc *SessionController) setupSessionSelectSignals() {
 
@@ -111,60 +121,130 @@ func (sc *SessionController) setupSessionSelectSignals() {
 
 }
 
-// scanForSessions looks for valid session config files in the application's config directory
-func (sc *SessionController) scanForSessions() {
+// scanForSessions scans the session directories (see sessionDirs) for valid session config files
+// in the background (keeping the GUI responsive with many session files, of which only new and
+// changed files are loaded again), then refreshes the session list and calls done (if not nil) on
+// the GTK main thread. Results of a scan superseded by a later scan are discarded
+func (sc *SessionController) scanForSessions(done func()) {
 
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "scanning for session configuration files...")
 
-	sc.Sessions = nil
+	sc.scanGen++
+	gen := sc.scanGen
+	dirs := sc.sessionDirs()
 
-	// Get session configuration directory
-	configDir, err := getSessionConfigDir()
-	if err != nil {
-		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to get session config directory: %v", err))
+	sc.UI.Page1.ScanSpinner.SetVisible(true)
+	sc.UI.Page1.ScanSpinner.Start()
 
-		return
-	}
+	go func() {
 
-	// Find all .toml files in the config directory
-	files, err := filepath.Glob(filepath.Join(configDir, "*.toml"))
-	if err != nil {
-		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("pattern-matching error when scanning for sessions: %v", err))
+		sessions := sc.loadSessions(dirs)
 
-		return
-	}
+		safeUpdateUI(func() {
 
-	// Load metadata for each session file found
-	sessionID := 1
-	for _, filePath := range files {
-		metadata, err := config.LoadSessionMetadata(filePath)
+			if gen != sc.scanGen {
+				return
+			}
+
+			sc.UI.Page1.ScanSpinner.Stop()
+			sc.UI.Page1.ScanSpinner.SetVisible(false)
+
+			selected := sc.selectedSessionPath()
+
+			sc.Sessions = sessions
+			sc.PopulateSessionList()
+			sc.selectSession(selected)
+
+			if done != nil {
+				done()
+			}
+
+		})
 
+	}()
+
+}
+
+// loadSessions returns the valid sessions found in the session directories, sorted alphabetically
+// by title
+func (sc *SessionController) loadSessions(dirs []string) []Session {
+
+	var files []config.SessionMetadata
+
+	for _, dir := range dirs {
+
+		// Skip session directories that can't be reached (e.g., an unmounted network share)
+		if _, err := os.Stat(dir); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("skipping unavailable session directory: %v", err))
+
+			continue
+		}
+
+		// Load metadata for each session file found (in parallel, unless cached)
+		metadata, err := sc.sessionCache.ScanSessions(dir)
 		if err != nil {
-			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("skipping invalid config file %s: %v", filePath, err))
+			logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("pattern-matching error when scanning for sessions: %v", err))
 
 			continue
 		}
 
-		if metadata.IsValid {
-			session := Session{
-				ID:         sessionID,
-				Title:      metadata.Title,
-				ConfigPath: metadata.FilePath,
-			}
+		files = append(files, metadata...)
+	}
 
-			sc.Sessions = append(sc.Sessions, session)
-			sessionID++
+	var sessions []Session
+
+	sessionID := 1
+	for _, metadata := range files {
+
+		if !metadata.IsValid {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("skipping invalid config file %s: %s", metadata.FilePath, metadata.ErrorMsg))
+
+			continue
 		}
 
+		sessions = append(sessions, Session{
+			ID:         sessionID,
+			Title:      metadata.Title,
+			ConfigPath: metadata.FilePath,
+		})
+
+		sessionID++
 	}
 
-	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session scan complete: found %d valid session(s)", len(sc.Sessions)))
+	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session scan complete: found %d valid session(s)", len(sessions)))
 
 	// Sort sessions alphabetically by title
-	slices.SortFunc(sc.Sessions, func(a, b Session) int {
+	slices.SortFunc(sessions, func(a, b Session) int {
 		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
 	})
 
+	return sessions
+}
+
+// selectedSessionPath returns the config file path of the session selected in the session list
+// (or "" if none)
+func (sc *SessionController) selectedSessionPath() string {
+
+	row := sc.UI.Page1.ListBox.SelectedRow()
+	if row == nil || row.Index() < 0 || row.Index() >= len(sc.Sessions) {
+		return ""
+	}
+
+	return sc.Sessions[row.Index()].ConfigPath
+}
+
+// selectSession selects the session with the given config file path in the session list (if
+// listed), so a refresh of the session list keeps the selection
+func (sc *SessionController) selectSession(configPath string) {
+
+	idx := slices.IndexFunc(sc.Sessions, func(s Session) bool {
+		return configPath != "" && s.ConfigPath == configPath
+	})
+
+	if idx >= 0 {
+		sc.UI.Page1.ListBox.SelectRow(sc.UI.Page1.ListBox.RowAtIndex(idx))
+	}
+
 }
 
 // createNewDefaultSession creates a default configuration file, a placeholder video, and refreshes the list
@@ -204,7 +284,7 @@ func (sc *SessionController) createNewDefaultSession() {
 		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save new session file: %v", err))
 
 		safeUpdateUI(func() {
-			displayAlertDialog(sc.UI.Window, "BSC Session Save Error", "Failed to save a new session file.\n\nPlease review the BSC Session Log for details.")
+			sc.displayErrorDialog("BSC Session Save Error", "Failed to save a new session file.\n\nPlease review the BSC Session Log for details.")
 		})
 
 		return
@@ -213,54 +293,18 @@ func (sc *SessionController) createNewDefaultSession() {
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "successfully created new session file at "+filePath)
 
 	// Refresh the GUI list
-	sc.scanForSessions()
-
-	safeUpdateUI(func() {
-		sc.PopulateSessionList()
-	})
+	sc.scanForSessions(nil)
 
 }
 
-// createDefaultConfig returns a Config struct populated with default values
+// createDefaultConfig returns a Config struct populated with default values (see
+// config.NewDefault) for the given video file
 func createDefaultConfig(videoPath string) *config.Config {
 
-	return &config.Config{
-		App: config.AppConfig{
-			SessionTitle: "New BSC Session",
-			LogLevel:     "info",
-		},
-		BLE: config.BLEConfig{
-			SensorBDAddr:    "AA:BB:CC:DD:EE:FF",
-			ScanTimeoutSecs: 30,
-		},
-		Speed: config.SpeedConfig{
-			WheelCircumferenceMM: 2155,
-			SpeedUnits:           config.SpeedUnitsMPH,
-			SpeedThreshold:       0.25,
-			SmoothingWindow:      5,
-		},
-		Video: config.VideoConfig{
-			MediaPlayer:       config.MediaPlayerMPV,
-			FilePath:          videoPath,
-			SeekToPosition:    "00:00:00",
-			AutoResume:        false,
-			WindowScaleFactor: 1.0,
-			UpdateIntervalSec: 0.25,
-			SpeedMultiplier:   0.8,
-			TargetDisplayName: "",
-			OnScreenDisplay: config.VideoOSDConfig{
-				DisplayCycleSpeed:    true,
-				DisplayPlaybackSpeed: true,
-				DisplayTimeRemaining: true,
-				FontSize:             40,
-				MarginX:              20,
-				MarginY:              20,
-				AlignX:               "left",
-				AlignY:               "top",
-				ShowOSD:              true,
-			},
-		},
-	}
+	cfg := config.NewDefault()
+	cfg.Video.FilePath = videoPath
+
+	return cfg
 }
 
 // setupListBoxSignals wires up event listeners for the ListBox
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/diamondburned/gotk4/pkg/core/glib"
@@ -12,6 +11,7 @@ import (
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/session"
+	"github.com/richbl/go-ble-sync-cycle/internal/units"
 	"github.com/richbl/go-ble-sync-cycle/internal/video"
 )
 
@@ -22,11 +22,90 @@ const (
 	errSeekExceedsDuration = "The configured start/seek time exceeds the video playback duration.\n\nPlease edit the BSC session file and try again."
 	sessionTimeout         = "BSC Session Timeout"
 	sessionError           = "BSC Session Error"
+
+	metricsIntervalMs         = 250
+	lowPowerMetricsIntervalMs = 1000
+
+	seekStep = 30 * time.Second // Playback skip back/forward step
 )
 
 // setupSessionStatusSignals wires up event listeners for the session status tab (Page 2)
 func (sc *SessionController) setupSessionStatusSignals() {
 	sc.setupSessionControlSignals()
+	sc.setupSensorConnectSignals()
+	sc.setupAudioControlSignals()
+	sc.setupSeekControlSignals()
+	sc.setupLapSignals()
+	sc.setupSpeedGraph()
+	sc.setupVideoCheckSignals()
+}
+
+// setupAudioControlSignals wires up event listeners for the playback volume and mute controls
+func (sc *SessionController) setupAudioControlSignals() {
+
+	sc.UI.Page2.VolumeScale.ConnectValueChanged(func() {
+
+		if sc.syncingAudio {
+			return
+		}
+
+		volume := int(sc.UI.Page2.VolumeScale.Value())
+		if err := sc.SessionManager.SetVideoVolume(volume); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to set playback volume: %v", err))
+		}
+
+	})
+
+	sc.UI.Page2.MuteButton.ConnectToggled(func() {
+
+		if sc.syncingAudio {
+			return
+		}
+
+		if err := sc.SessionManager.SetVideoMute(sc.UI.Page2.MuteButton.Active()); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to set playback mute: %v", err))
+		}
+
+	})
+
+}
+
+// setupSeekControlSignals wires up event listeners for the playback skip back/forward buttons
+func (sc *SessionController) setupSeekControlSignals() {
+
+	sc.UI.Page2.SeekBackButton.ConnectClicked(func() {
+		sc.seekVideo(-seekStep)
+	})
+
+	sc.UI.Page2.SeekForwardButton.ConnectClicked(func() {
+		sc.seekVideo(seekStep)
+	})
+
+}
+
+// seekVideo skips video playback by an offset from the current playback position
+func (sc *SessionController) seekVideo(offset time.Duration) {
+
+	if err := sc.SessionManager.SeekVideo(offset, false); err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to skip video playback: %v", err))
+
+		return
+	}
+
+	sc.updateLastEvent()
+}
+
+// syncAudioControls updates the volume and mute controls without triggering player updates
+func (sc *SessionController) syncAudioControls(volume int, muted bool, enabled bool) {
+
+	sc.syncingAudio = true
+	sc.UI.Page2.VolumeScale.SetValue(float64(volume))
+	sc.UI.Page2.MuteButton.SetActive(muted)
+	sc.syncingAudio = false
+
+	sc.UI.Page2.VolumeRow.SetSensitive(enabled)
+	sc.UI.Page2.SeekRow.SetSensitive(enabled)
+
 }
 
 // setupSessionControlSignals wires up event listeners for the session control button
@@ -58,7 +137,14 @@ func (sc *SessionController) handleSessionControl() error {
 
 	logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("Session Start/Stop button clicked: session status: %s", currentState))
 
-	if currentState >= session.StateConnecting || sc.starting.Load() {
+	// A sensor connected ahead of time only needs video playback started
+	if sc.SessionManager.IsSensorReady() {
+		sc.handleStartPlayback()
+
+		return nil
+	}
+
+	if currentState >= session.StateConnecting || sc.SessionManager.IsStarting() {
 
 		// Stop the session!
 		if err := sc.handleStop(); err != nil {
@@ -78,12 +164,16 @@ func (sc *SessionController) handleStart() {
 
 	logger.Info(logger.BackgroundCtx, logger.GUI, "starting BSC Session...")
 
-	if !sc.starting.CompareAndSwap(false, true) {
+	if sc.SessionManager.IsStarting() {
 		logger.Warn(logger.BackgroundCtx, logger.GUI, "start request ignored: already pending")
 
 		return
 	}
 
+	// Request the start right away (rather than from the goroutine waiting on it), so that the
+	// session manager runs it ahead of any stop requested next
+	result := sc.SessionManager.StartSessionAsync()
+
 	// Record start time for session playback
 	sc.startTime = time.Now()
 
@@ -93,13 +183,11 @@ func (sc *SessionController) handleStart() {
 	safeUpdateUI(func() {
 		sc.updateSessionControlButton(true)
 		sc.updatePage2Status(StatusConnecting, StatusNotConnected, StatusUnknown)
+		sc.startScanProgressLoop()
 	})
 
-	// Launch goroutine to start session
-	go func() {
-		defer sc.starting.Store(false)
-		sc.startSessionGUI()
-	}()
+	// Launch goroutine to wait on the session start
+	go sc.startSessionGUI(result)
 
 }
 
@@ -127,8 +215,11 @@ func (sc *SessionController) handleStartError(err error) {
 		// Check for specific error cases and show appropriate messages
 		switch {
 		case errors.Is(err, ble.ErrScanTimeout):
-			sessionConnectTimeout := sc.SessionManager.ActiveConfig().BLE.ScanTimeoutSecs
-			displayAlertDialog(sc.UI.Window, "BSC Session Start Timeout", fmt.Sprintf("Failed to start the BSC Session due to BLE device timeout (%ds).\n\nPlease restart the BSC Session.", sessionConnectTimeout))
+			bleConfig := sc.SessionManager.ActiveConfig().BLE
+			displayAlertDialog(sc.UI.Window, "BSC Session Start Timeout", fmt.Sprintf("Failed to start the BSC Session due to BLE device timeout (%ds, %d scan attempts).\n\nPlease restart the BSC Session.", bleConfig.ScanTimeoutSecs, max(bleConfig.ScanAttempts, 1)))
+
+		case errors.Is(err, ble.ErrBluetoothPermission), errors.Is(err, ble.ErrNoBluetoothAdapter), errors.Is(err, ble.ErrAdapterNotPowered):
+			displayAlertDialog(sc.UI.Window, "Bluetooth Setup Required", fmt.Sprintf("Failed to start the BSC Session due to a Bluetooth setup problem:\n\n%v", err))
 
 		case errors.Is(err, video.ErrSeekExceedsDuration):
 			displayAlertDialog(sc.UI.Window, "BSC Session Video Error", errSeekExceedsDuration)
@@ -140,7 +231,7 @@ func (sc *SessionController) handleStartError(err error) {
 			displayAlertDialog(sc.UI.Window, sessionTimeout, "Unable to acquire the BLE device battery level due to BLE device timeout.\n\nPlease restart the BSC Session.")
 
 		default:
-			displayAlertDialog(sc.UI.Window, sessionError, "Failed to start the BSC Session.\n\nPlease review the BSC Session Log for details.")
+			sc.displaySessionErrorDialog(err, "Failed to start the BSC Session.\n\nPlease review the BSC Session Log for details.")
 		}
 
 	})
@@ -154,12 +245,12 @@ func (sc *SessionController) handleStop() error {
 	runningCfg := sc.SessionManager.ActiveConfig()
 	shouldAutoResume := false
 	autoResumeSaved := false
-	var currentPos string
+	var resumePoint session.ResumePoint
 
-	// If Auto-Resume is enabled, get the current playback position
+	// If Auto-Resume is enabled, get the current playback position (and the ride state saved with it)
 	if runningCfg != nil && runningCfg.Video.AutoResume {
 		shouldAutoResume = true
-		currentPos = sc.SessionManager.VideoPlaybackPosition()
+		resumePoint = sc.SessionManager.ResumePoint()
 	}
 
 	// Get the path of the session that is currently running
@@ -176,19 +267,26 @@ func (sc *SessionController) handleStop() error {
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "session services stopped")
 
 	// If Auto-Resume is enabled and we have a valid playback position, save it to the config
-	if shouldAutoResume && currentPos != "" && currentPos != "00:00:00" {
-		autoResumeSaved = sc.saveAutoResumePosition(activePath, currentPos)
+	if shouldAutoResume && !resumePoint.IsStart() {
+		autoResumeSaved = sc.saveAutoResumePosition(sc.SessionManager, activePath, resumePoint)
 	}
 
 	safeUpdateUI(func() {
 		sc.updateSessionControlButton(false)
 		sc.updatePage2Status(StatusStopped, StatusNotConnected, StatusUnknown)
 		sc.resetMetrics()
+		sc.updateLastEvent()
+		sc.UI.Page2.VolumeRow.SetSensitive(false)
+		sc.UI.Page2.SeekRow.SetSensitive(false)
+		sc.exitFocusMode()
+		sc.updateIdleInhibitor()
+		sc.updateNightTheme()
 
 		// User edited the running session! (so update the details using latest config)
 		if c := sc.SessionManager.ActiveConfig(); c != nil {
 			sc.UI.Page2.SessionNameRow.SetSubtitle(c.App.SessionTitle)
 			sc.UI.Page2.SpeedRow.SetSubtitle(c.Speed.SpeedUnits)
+			sc.UI.Page2.SpeedStatsRow.SetSubtitle(c.Speed.SpeedUnits)
 		}
 
 		// Safely synchronize the Session Editor UI with the new auto-resume position
@@ -196,13 +294,18 @@ func (sc *SessionController) handleStop() error {
 			sc.populateEditor()
 		}
 
+		// Ask for notes and tags describing the ride (if there was one)
+		if record, ok := sc.SessionManager.TakeLastSessionRecord(); ok {
+			sc.showSessionNotesDialog(record)
+		}
+
 	})
 
 	return nil
 }
 
-// startSessionGUI runs the StartSession method and updates UI based on result
-func (sc *SessionController) startSessionGUI() {
+// startSessionGUI waits on the result of a session start and updates UI based on result
+func (sc *SessionController) startSessionGUI(result <-chan error) {
 
 	defer func() {
 		logger.Debug(logger.BackgroundCtx, logger.GUI, "session services stopped")
@@ -221,7 +324,7 @@ func (sc *SessionController) startSessionGUI() {
 	// Start the session
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "session services starting...")
 
-	err := sc.SessionManager.StartSession()
+	err := <-result
 	if err != nil {
 		sc.handleStartError(err)
 
@@ -232,23 +335,31 @@ func (sc *SessionController) startSessionGUI() {
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "session services started")
 
 	safeUpdateUI(func() {
-		battery := fmt.Sprintf("%d%%", sc.SessionManager.BatteryLevel())
-		sc.updatePage2Status(StatusConnected, StatusConnected, battery)
+		sc.showSensorConnected()
+		sc.updateSensorConnectButton()
+
+		volume, muted := sc.SessionManager.VideoVolume()
+		sc.syncAudioControls(volume, muted, true)
+
 		sc.startMetricsLoop()
+		sc.enterFocusMode()
+		sc.updateIdleInhibitor()
+		sc.updateNightTheme()
 	})
 
 }
 
-// saveAutoResumePosition persists the current playback position to the session configuration
-func (sc *SessionController) saveAutoResumePosition(path, pos string) bool {
+// saveAutoResumePosition persists the current playback position of a session instance (along with
+// the ride state saved with it) to its session configuration
+func (sc *SessionController) saveAutoResumePosition(m *session.StateManager, path string, point session.ResumePoint) bool {
 
-	cfg := sc.SessionManager.ActiveConfig()
+	cfg := m.ActiveConfig()
 	if cfg == nil {
 		return false
 	}
 
-	// Merge just the playback position into the freshest config
-	cfg.Video.SeekToPosition = pos
+	// Merge just the resume point into the freshest config
+	point.Apply(&cfg.Video)
 
 	if err := config.Save(path, cfg, config.GetVersion()); err != nil {
 		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save auto-resume position: %v", err))
@@ -256,10 +367,10 @@ func (sc *SessionController) saveAutoResumePosition(path, pos string) bool {
 		return false
 	}
 
-	logger.Info(logger.BackgroundCtx, logger.GUI, "auto-resume position saved: "+pos)
+	logger.Info(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("auto-resume position saved: %s (%.2f km ridden so far)", point.Position, point.DistanceKM))
 
 	// Only synchronize if the user is editing the same session that was just stopped
-	if sc.SessionManager.EditConfigPath() == path {
+	if m == sc.SessionManager && m.EditConfigPath() == path {
 		sc.handleLoadedSessionUpdate(path, cfg)
 	}
 
@@ -272,10 +383,12 @@ func (sc *SessionController) updatePage2WithSession(sess Session) {
 	// Update session name
 	sc.UI.Page2.SessionNameRow.SetSubtitle(sess.Title)
 	sc.UI.Page2.SessionNameRow.SetSensitive(true)
+	sc.UI.Page2.LastEventRow.SetSensitive(true)
 
 	// Update the speed units based on the loaded configuration
 	if c := sc.SessionManager.ActiveConfig(); c != nil {
 		sc.UI.Page2.SpeedRow.SetSubtitle(c.Speed.SpeedUnits)
+		sc.UI.Page2.SpeedStatsRow.SetSubtitle(c.Speed.SpeedUnits)
 	}
 
 	// Initial state: BLE not connected, Battery unknown
@@ -288,9 +401,11 @@ func (sc *SessionController) updatePage2WithSession(sess Session) {
 
 	// Enable session metrics controls
 	sc.UI.Page2.SpeedRow.SetSensitive(true)
+	sc.UI.Page2.SpeedStatsRow.SetSensitive(true)
 	sc.UI.Page2.PlaybackSpeedRow.SetSensitive(true)
 	sc.UI.Page2.RideTimeRow.SetSensitive(true)
 	sc.UI.Page2.TimeRemainingRow.SetSensitive(true)
+	sc.UI.Page2.SpeedGraphRow.SetSensitive(true)
 
 	// Set button to start mode
 	sc.updateSessionControlButton(false)
@@ -298,6 +413,9 @@ func (sc *SessionController) updatePage2WithSession(sess Session) {
 	// Enable the button now that session is loaded
 	sc.UI.Page2.SessionControlRow.SetSensitive(true)
 
+	// Check the video file before the session is started
+	sc.checkSessionVideo()
+
 	logger.Debug(logger.BackgroundCtx, logger.GUI, "Session Status page updated with session: "+sess.Title)
 
 }
@@ -306,9 +424,41 @@ func (sc *SessionController) updatePage2WithSession(sess Session) {
 func (sc *SessionController) resetMetrics() {
 
 	sc.UI.Page2.SpeedLabel.SetLabel("0.0")
+	sc.updateSpeedZone(0)
+	sc.UI.Page2.SpeedStatsLabel.SetLabel("0.0 / 0.0")
 	sc.UI.Page2.PlaybackSpeedLabel.SetLabel("0.00x")
 	sc.UI.Page2.RideTimeLabel.SetLabel(undefinedTimeStamp)
 	sc.UI.Page2.TimeRemainingLabel.SetLabel(undefinedTimeStamp)
+	sc.resetGoal()
+	sc.resetLaps()
+	sc.resetRace()
+	sc.resetEnvironment()
+	sc.resetCountdown()
+	sc.speedGraph.reset()
+
+}
+
+// updateSpeedStats displays the session average (while moving) and max speeds
+func (sc *SessionController) updateSpeedStats() {
+
+	average, maximum, speedUnits := sc.SessionManager.SpeedStats()
+	if speedUnits == "" {
+		return
+	}
+
+	sc.UI.Page2.SpeedStatsLabel.SetLabel(units.FormatSpeed(average, speedUnits) + " / " + units.FormatSpeed(maximum, speedUnits))
+
+}
+
+// updateLastEvent displays the most recent significant session event
+func (sc *SessionController) updateLastEvent() {
+
+	event, ok := sc.SessionManager.LastEvent()
+	if !ok {
+		return
+	}
+
+	sc.UI.Page2.LastEventRow.SetSubtitle(fmt.Sprintf("%s  %s", event.Time.Format("15:04:05"), event.Message))
 
 }
 
@@ -317,18 +467,25 @@ func (sc *SessionController) clearPage2() {
 
 	// Reset labels and icons
 	sc.UI.Page2.SessionNameRow.SetSubtitle("n/a")
+	sc.UI.Page2.LastEventRow.SetSubtitle("n/a")
 	sc.UI.Page2.SpeedRow.SetSubtitle("n/a")
+	sc.UI.Page2.SpeedStatsRow.SetSubtitle("n/a")
 	sc.updatePage2Status(StatusNotConnected, StatusNotConnected, StatusUnknown)
 	sc.resetMetrics()
 
 	// Disable all rows
 	sc.UI.Page2.SessionNameRow.SetSensitive(false)
+	sc.UI.Page2.LastEventRow.SetSensitive(false)
 	sc.UI.Page2.SensorStatusRow.SetSensitive(false)
 	sc.UI.Page2.SensorBatteryRow.SetSensitive(false)
 	sc.UI.Page2.SpeedRow.SetSensitive(false)
+	sc.UI.Page2.SpeedStatsRow.SetSensitive(false)
 	sc.UI.Page2.PlaybackSpeedRow.SetSensitive(false)
 	sc.UI.Page2.RideTimeRow.SetSensitive(false)
 	sc.UI.Page2.TimeRemainingRow.SetSensitive(false)
+	sc.UI.Page2.SpeedGraphRow.SetSensitive(false)
+	sc.UI.Page2.VolumeRow.SetSensitive(false)
+	sc.UI.Page2.SeekRow.SetSensitive(false)
 	sc.UI.Page2.SessionControlRow.SetSensitive(false)
 
 }
@@ -346,6 +503,7 @@ func (sc *SessionController) setBLEStatus(status Status) {
 
 	p := statusTable[ObjectBLE][status]
 	sc.UI.Page2.SensorStatusRow.SetSubtitle(p.Display)
+	sc.UI.Page2.SensorStatusRow.RemoveCSSClass(cssWakeHint)
 	sc.UI.Page2.SensorConnIcon.SetFromIconName(p.Icon)
 	sc.UI.Page2.SensorConnIcon.SetCSSClasses([]string{p.CSSStyle})
 
@@ -368,7 +526,8 @@ func (sc *SessionController) setBatteryStatus(status Status, level string) {
 
 }
 
-// updateSessionControlButton updates the session control button label and icon
+// updateSessionControlButton updates the session control button label and icon (along with the
+// sensor connect button)
 func (sc *SessionController) updateSessionControlButton(isRunning bool) {
 
 	if isRunning {
@@ -379,34 +538,110 @@ func (sc *SessionController) updateSessionControlButton(isRunning bool) {
 		sc.UI.Page2.SessionControlBtnContent.SetIconName("media-playback-start-symbolic")
 	}
 
+	sc.updateSensorConnectButton()
+
+}
+
+// startScanProgressLoop initiates a GLib timeout to show BLE peripheral scan progress (e.g.,
+// "attempt 2/3") while the session starts
+func (sc *SessionController) startScanProgressLoop() {
+
+	mgr := sc.SessionManager
+	scanning := false
+
+	glib.TimeoutAdd(metricsIntervalMs, func() bool {
+
+		if !mgr.IsStarting() || mgr != sc.SessionManager {
+			return false
+		}
+
+		sc.updateLastEvent()
+
+		progress, ok := mgr.ScanProgress()
+
+		switch {
+		case ok && progress.WakeHint:
+			sc.showWakeHint(progress)
+			scanning = true
+
+		case ok && progress.Attempts > 1:
+			sc.UI.Page2.SensorStatusRow.SetSubtitle(fmt.Sprintf("Scanning (attempt %d/%d)...", progress.Attempt, progress.Attempts))
+			scanning = true
+
+		case !ok && scanning && mgr.SessionState() == session.StateConnecting:
+			sc.setBLEStatus(StatusConnecting)
+			scanning = false
+		}
+
+		return true
+	})
+
+}
+
+// showWakeHint shows a pulsing hint on the sensor status row to spin the wheel to wake the sensor
+func (sc *SessionController) showWakeHint(progress session.ScanProgress) {
+
+	hint := "Spin the wheel to wake the sensor..."
+	if progress.Attempts > 1 {
+		hint = fmt.Sprintf("Spin the wheel to wake the sensor (attempt %d/%d)...", progress.Attempt, progress.Attempts)
+	}
+
+	sc.UI.Page2.SensorStatusRow.SetSubtitle(hint)
+	sc.UI.Page2.SensorStatusRow.AddCSSClass(cssWakeHint)
+
 }
 
 // startMetricsLoop initiates a GLib timeout to poll the SessionManager for real-time data
 func (sc *SessionController) startMetricsLoop() {
 
-	// Poll every 250ms
-	sc.metricsLoop = glib.TimeoutAdd(250, func() bool {
+	// Poll every 250ms (or less frequently when the low-power profile is enabled)
+	interval := uint(metricsIntervalMs)
+	lowPower := false
+
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil && cfg.Video.LowPower {
+		interval = lowPowerMetricsIntervalMs
+		lowPower = true
+	}
+
+	// Invalidate any loop polling a previously shown session instance
+	sc.metricsGen++
+	gen := sc.metricsGen
+
+	sc.metricsLoop = glib.TimeoutAdd(interval, func() bool {
+
+		if gen != sc.metricsGen {
+			return false
+		}
 
 		state := sc.SessionManager.SessionState()
 
 		// Check for async failure (e.g., invalid video file)
 		if state == session.StateError {
 
-			errMsg := sc.SessionManager.ErrorMessage()
+			sessionErr := sc.SessionManager.Err()
 
 			logger.Debug(logger.BackgroundCtx, logger.GUI, "metrics loop detected session error")
-			logger.Error(logger.BackgroundCtx, logger.GUI, "session error: "+errMsg)
+			logger.Error(logger.BackgroundCtx, logger.GUI, "session error: "+sc.SessionManager.ErrorMessage())
+
+			// Bring the GUI back from background mode so the user sees the alert
+			sc.exitBackground()
 
-			// Present clean, friendly UI alerts based on the specific error
+			// Present clean, friendly UI alerts based on the specific error (or its category)
 			switch {
-			case strings.Contains(errMsg, video.ErrVideoComplete.Error()):
+			case errors.Is(sessionErr, video.ErrVideoComplete):
 				displayAlertDialog(sc.UI.Window, "The BSC Session has Ended", "The video playback has finished.\n\nSession stopped.")
 
-			case strings.Contains(errMsg, video.ErrSeekExceedsDuration.Error()):
+			case errors.Is(sessionErr, session.ErrSessionTimeLimit):
+				displayAlertDialog(sc.UI.Window, "The BSC Session has Ended", "The maximum session duration has been reached.\n\nSession stopped.")
+
+			case errors.Is(sessionErr, video.ErrSeekExceedsDuration):
 				displayAlertDialog(sc.UI.Window, "BSC Session Load Error", errSeekExceedsDuration)
 
+			case errors.Is(sessionErr, ble.ErrCadenceOnly):
+				displayAlertDialog(sc.UI.Window, "BSC Session Sensor Error", "The BLE sensor provides cadence only (no wheel speed).\n\nTo ride using cadence, set cadence_speed_per_rpm in the [speed] section of the BSC session file.")
+
 			default:
-				displayAlertDialog(sc.UI.Window, sessionError, "An unexpected session error has occurred.\n\nPlease review the BSC Session Log for details.")
+				sc.displaySessionErrorDialog(sessionErr, "An unexpected session error has occurred.\n\nPlease review the BSC Session Log for details.")
 			}
 
 			// Reset UI and application state
@@ -417,19 +652,27 @@ func (sc *SessionController) startMetricsLoop() {
 			return false
 		}
 
-		// If session isn't running, stop the loop
-		if state != session.StateRunning {
+		// If session isn't running (or paused, or waiting on speed data for a video-first start),
+		// stop the loop
+		if state != session.StateRunning && state != session.StatePaused && state != session.StateConnected {
 			return false
 		}
 
 		// Update metrics
-		speed, _ := sc.SessionManager.CurrentSpeed()
-		timeRem := sc.SessionManager.VideoTimeRemaining()
+		speed, speedUnits := sc.SessionManager.CurrentSpeed()
+		timeRem := undefinedTimeStamp
+		if !lowPower {
+			timeRem = sc.SessionManager.VideoTimeRemaining()
+		}
+
 		rate := sc.SessionManager.VideoPlaybackRate()
 
 		// Update widget labels
-		sc.UI.Page2.SpeedLabel.SetLabel(fmt.Sprintf("%.1f", speed))
+		sc.UI.Page2.SpeedLabel.SetLabel(units.FormatSpeed(speed, speedUnits))
+		sc.updateSpeedZone(speed)
+		sc.updateSpeedStats()
 		sc.UI.Page2.PlaybackSpeedLabel.SetLabel(fmt.Sprintf("%.2fx", rate))
+		sc.speedGraph.add(speed, time.Now())
 
 		rideTime := undefinedTimeStamp
 
@@ -444,6 +687,13 @@ func (sc *SessionController) startMetricsLoop() {
 
 		sc.UI.Page2.RideTimeLabel.SetLabel(rideTime)
 		sc.UI.Page2.TimeRemainingLabel.SetLabel(timeRem)
+		sc.updateGoal()
+		sc.updateLaps()
+		sc.updateRace()
+		sc.updateEnvironment()
+		sc.updateCountdown()
+		sc.updateNightTheme()
+		sc.updateLastEvent()
 
 		// Return true to keep the loop chugging along...
 		return true
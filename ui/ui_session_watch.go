@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/diamondburned/gotk4/pkg/core/glib"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// Time (in milliseconds) that changes to the session directories must settle before a rescan
+// (e.g., while a session file is being written, or many session files are copied in)
+const sessionWatchSettleMs = 500
+
+// watchSessionDirs watches the session directories, so the session list (Page 1) is refreshed when
+// session files are added, removed, or edited outside of the GUI (e.g., in a text editor), replacing
+// the watches of any previous session directories
+func (sc *SessionController) watchSessionDirs() {
+
+	for _, watcher := range sc.dirWatchers {
+		watcher.Cancel()
+	}
+
+	sc.dirWatchers = nil
+
+	for _, dir := range sc.sessionDirs() {
+
+		// Network shares may not report changes, in which case the session list is refreshed only
+		// when shown
+		monitor, err := gio.NewFileForPath(dir).MonitorDirectory(context.Background(), gio.FileMonitorWatchMoves)
+		if err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("session list won't refresh automatically: failed to watch %s: %v", dir, err))
+
+			continue
+		}
+
+		// Keep a reference to the monitor, which stops watching once garbage collected
+		watcher := gio.BaseFileMonitor(monitor)
+		sc.dirWatchers = append(sc.dirWatchers, watcher)
+
+		watcher.ConnectChanged(func(file, otherFile gio.Filer, event gio.FileMonitorEvent) {
+
+			if isSessionFileEvent(event) && (isSessionFile(file) || isSessionFile(otherFile)) {
+				sc.scheduleSessionRescan()
+			}
+
+		})
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "watching for changes to session files in "+dir)
+	}
+
+}
+
+// scheduleSessionRescan rescans the session directories once changes to them have settled, with
+// further changes postponing the rescan
+func (sc *SessionController) scheduleSessionRescan() {
+
+	if sc.rescanTimer != 0 {
+		glib.SourceRemove(sc.rescanTimer)
+	}
+
+	sc.rescanTimer = glib.TimeoutAdd(sessionWatchSettleMs, func() bool {
+
+		sc.rescanTimer = 0
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "session files changed: refreshing session list...")
+		sc.scanForSessions(nil)
+
+		return false
+	})
+
+}
+
+// isSessionFileEvent returns true for the file monitor events that can change the session list
+// (a file written, removed, or renamed), ignoring the intermediate writes to a file
+func isSessionFileEvent(event gio.FileMonitorEvent) bool {
+
+	switch event {
+	case gio.FileMonitorEventChangesDoneHint, gio.FileMonitorEventDeleted, gio.FileMonitorEventCreated,
+		gio.FileMonitorEventRenamed, gio.FileMonitorEventMovedIn, gio.FileMonitorEventMovedOut:
+		return true
+	default:
+		return false
+	}
+
+}
+
+// isSessionFile returns true if the file (which may be nil) is a session file (*.toml)
+func isSessionFile(file gio.Filer) bool {
+	return file != nil && filepath.Ext(file.Basename()) == ".toml"
+}
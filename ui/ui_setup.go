@@ -6,6 +6,7 @@ import (
 	"github.com/diamondburned/gotk4/pkg/glib/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
 	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/crashreport"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
 	"github.com/richbl/go-ble-sync-cycle/internal/services"
 )
@@ -27,6 +28,7 @@ func setupGUIApplication(app *gtk.Application, shutdownMgr *services.ShutdownMan
 		aboutDialog.SetApplicationIcon("com.github.richbl.ble-sync-cycle")
 		aboutDialog.SetApplicationName("BLE Sync Cycle")
 		aboutDialog.SetVersion(config.GetVersion())
+		aboutDialog.SetDebugInfo(config.GetBuildInfo().String())
 		aboutDialog.SetCopyright("Copyright © 2025-2026 Rich Bloch")
 		aboutDialog.SetDevelopers([]string{"Rich Bloch"})
 		aboutDialog.SetIssueURL("https://github.com/richbl/go-ble-sync-cycle/issues/new?template=issue-report.md")
@@ -43,11 +45,17 @@ func setupGUIApplication(app *gtk.Application, shutdownMgr *services.ShutdownMan
 
 	app.AddAction(aboutAction)
 
+	// Create SessionController and initialize (Page 1)
+	sessionCtrl := NewSessionController(ui, shutdownMgr)
+	sessionCtrl.scanForSessions(sessionCtrl.CheckForNoSessions)
+	sessionCtrl.watchSessionDirs()
+	sessionCtrl.CheckBluetoothPermissions()
+
 	// Create the "Exit" menu item action handler
 	exitAction := gio.NewSimpleAction("quit", nil)
 	exitAction.ConnectActivate(func(_ *glib.Variant) {
 		logger.Debug(logger.BackgroundCtx, logger.GUI, "exit action triggered from GUI app menu item")
-		ui.createExitDialog()
+		sessionCtrl.createExitDialog()
 	})
 
 	app.AddAction(exitAction)
@@ -57,17 +65,53 @@ func setupGUIApplication(app *gtk.Application, shutdownMgr *services.ShutdownMan
 
 		safeUpdateUI(func() {
 			logger.Debug(logger.BackgroundCtx, logger.GUI, "exit action triggered from GUI close button")
-			ui.createExitDialog()
+			sessionCtrl.createExitDialog()
 		})
 
 		return true
 	})
 
-	// Create SessionController and initialize (Page 1)
-	sessionCtrl := NewSessionController(ui, shutdownMgr)
-	sessionCtrl.scanForSessions()
-	sessionCtrl.PopulateSessionList()
-	sessionCtrl.CheckForNoSessions()
+	// Confirm exit on CTRL+C as well, rather than tearing down running sessions mid-ride
+	shutdownMgr.SetInterruptHandler(func() {
+
+		safeUpdateUI(func() {
+			logger.Debug(logger.BackgroundCtx, logger.GUI, "exit action triggered from CTRL+C")
+			ui.Window.Present()
+			sessionCtrl.createExitDialog()
+		})
+
+	})
+
+	// Create the "Run in Background" menu item and background notification action handlers
+	setupBackgroundActions(app, sessionCtrl)
+
+	// Create the "New Session Instance" menu item action handler, and stop every session
+	// instance on application shutdown
+	setupInstanceActions(app, sessionCtrl)
+	shutdownMgr.RegisterCleanup("session instances", sessionCtrl.registry.StopAll)
+
+	// Include the configuration of a running session instance in crash reports
+	crashreport.SetConfigSource(func() *config.Config {
+
+		if running := sessionCtrl.registry.Running(); len(running) > 0 {
+			return running[0].ActiveConfig()
+		}
+
+		return nil
+	})
+
+	// Create the "Export Settings" and "Import Settings" menu item action handlers
+	setupBundleActions(app, sessionCtrl)
+
+	// Create the "Session Folders" menu item action handler
+	setupSessionDirActions(app, sessionCtrl)
+
+	// Create the "Lap" action
+	setupLapActions(app, sessionCtrl)
+
+	// Create the "Keyboard Shortcuts" menu item action handler and the keyboard shortcut actions,
+	// and bind the keyboard shortcuts of the application preferences
+	setupShortcutActions(app, sessionCtrl)
 
 	// Initialize the Session Editor (Page 4) to a clean state
 	sessionCtrl.resetEditor()
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+)
+
+// shortcutPages maps the page keyboard shortcut actions to the pages they show
+var shortcutPages = map[string]string{
+	config.ShortcutShowSelect:  "page1",
+	config.ShortcutShowStatus:  "page2",
+	config.ShortcutShowLog:     "page3",
+	config.ShortcutShowEditor:  "page4",
+	config.ShortcutShowHistory: "page5",
+}
+
+// shortcutTitles maps keyboard shortcut actions to their titles in the Keyboard Shortcuts dialog
+var shortcutTitles = map[string]string{
+	config.ShortcutStartStop:    "Start/Stop Session",
+	config.ShortcutTogglePause:  "Pause/Resume Video",
+	config.ShortcutLap:          "Mark a Lap",
+	config.ShortcutShowSelect:   "Show Session Select",
+	config.ShortcutShowStatus:   "Show Session Status",
+	config.ShortcutShowLog:      "Show Session Log",
+	config.ShortcutShowEditor:   "Show Session Editor",
+	config.ShortcutShowHistory:  "Show Session History",
+	config.ShortcutShowKeyboard: "Show Keyboard Shortcuts",
+}
+
+// setupShortcutActions creates the application actions activated by keyboard shortcuts (the "lap"
+// and "toggle-pause" actions are created with the laps and background actions), and binds the
+// keyboard shortcuts of the application preferences
+func setupShortcutActions(app *gtk.Application, sc *SessionController) {
+
+	actions := map[string]func(){
+		config.ShortcutStartStop:    sc.startStopFromShortcut,
+		config.ShortcutShowKeyboard: sc.showShortcutsDialog,
+	}
+
+	for name, page := range shortcutPages {
+		actions[name] = func() {
+			sc.UI.ViewStack.SetVisibleChildName(page)
+		}
+	}
+
+	for name, handler := range actions {
+		action := gio.NewSimpleAction(name, nil)
+		action.ConnectActivate(func(_ *glib.Variant) {
+			handler()
+		})
+
+		app.AddAction(action)
+	}
+
+	sc.applyKeymap(app)
+
+}
+
+// loadPreferences loads the application preferences, falling back to the default preferences if
+// they can't be loaded
+func loadPreferences() *config.Preferences {
+
+	path, err := config.PreferencesPath()
+	if err == nil {
+
+		var prefs *config.Preferences
+		if prefs, err = config.LoadPreferences(path); err == nil {
+			return prefs
+		}
+	}
+
+	logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("using default application preferences: %v", err))
+
+	return &config.Preferences{}
+}
+
+// savePreferences saves the application preferences
+func (sc *SessionController) savePreferences() {
+
+	path, err := config.PreferencesPath()
+	if err == nil {
+		err = sc.prefs.Save(path)
+	}
+
+	if err != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to save application preferences: %v", err))
+	}
+
+}
+
+// applyKeymap binds the keyboard shortcuts of the application preferences to their application
+// actions
+func (sc *SessionController) applyKeymap(app *gtk.Application) {
+
+	for _, shortcut := range config.Shortcuts {
+
+		var accels []string
+		if accel := sc.prefs.Accelerator(shortcut.Action); accel != "" {
+			accels = []string{accel}
+		}
+
+		app.SetAccelsForAction("app."+shortcut.Action, accels)
+	}
+
+	lapTip := "Mark a lap"
+	if label := shortcutLabel(sc.prefs.Accelerator(config.ShortcutLap)); label != "" {
+		lapTip += " (" + label + ")"
+	}
+
+	sc.UI.Page2.LapButton.SetTooltipText(lapTip)
+
+}
+
+// shortcutLabel returns the display label of an accelerator (e.g., "Ctrl+L"), or an empty string
+// if the accelerator is unbound (or invalid)
+func shortcutLabel(accel string) string {
+
+	key, mods, ok := gtk.AcceleratorParse(accel)
+	if !ok {
+		return ""
+	}
+
+	return gtk.AcceleratorGetLabel(key, mods)
+}
+
+// startStopFromShortcut starts or stops the session shown on the Session Status page, if a
+// session is loaded
+func (sc *SessionController) startStopFromShortcut() {
+
+	if !sc.UI.Page2.SessionControlRow.Sensitive() {
+		return
+	}
+
+	if err := sc.handleSessionControl(); err != nil {
+		logger.Error(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("failed to handle session control: %v", err))
+	}
+
+}
+
+// showShortcutsDialog shows the keyboard shortcuts, where each shortcut can be rebound by selecting
+// it and pressing the new shortcut
+func (sc *SessionController) showShortcutsDialog() {
+
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("Keyboard Shortcuts")
+	group.SetDescription("Select a shortcut to change it")
+
+	resetButton := gtk.NewButtonWithLabel("Reset to Defaults")
+	resetButton.SetVAlign(gtk.AlignCenter)
+	resetButton.AddCSSClass("flat")
+	group.SetHeaderSuffix(resetButton)
+
+	labels := make(map[string]*gtk.ShortcutLabel, len(config.Shortcuts))
+
+	refresh := func() {
+
+		for action, label := range labels {
+			label.SetAccelerator(sc.prefs.Accelerator(action))
+		}
+
+	}
+
+	for _, shortcut := range config.Shortcuts {
+
+		label := gtk.NewShortcutLabel(sc.prefs.Accelerator(shortcut.Action))
+		label.SetDisabledText("Disabled")
+		label.SetVAlign(gtk.AlignCenter)
+		labels[shortcut.Action] = label
+
+		row := adw.NewActionRow()
+		row.SetTitle(shortcutTitles[shortcut.Action])
+		row.AddSuffix(label)
+		row.SetActivatable(true)
+		row.ConnectActivated(func() {
+			sc.captureShortcut(shortcut.Action, refresh)
+		})
+
+		group.Add(row)
+	}
+
+	resetButton.ConnectClicked(func() {
+		sc.prefs.ResetKeymap()
+		sc.savePreferences()
+		sc.applyKeymap(sc.UI.Window.Application())
+		refresh()
+	})
+
+	page := adw.NewPreferencesPage()
+	page.Add(group)
+
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle("Keyboard Shortcuts")
+	dialog.Add(page)
+	dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
+
+// captureShortcut asks for the new keyboard shortcut of an action (Backspace disables the
+// shortcut, and Escape cancels), saving the rebound shortcut in the application preferences
+func (sc *SessionController) captureShortcut(action string, onRebind func()) {
+
+	const responseCancel = "cancel"
+
+	dialog := adw.NewAlertDialog("Set Shortcut", fmt.Sprintf("Press the new shortcut for \"%s\", or Backspace to disable it.", shortcutTitles[action]))
+	dialog.AddResponse(responseCancel, "Cancel")
+	dialog.SetCloseResponse(responseCancel)
+
+	keys := gtk.NewEventControllerKey()
+	keys.SetPropagationPhase(gtk.PhaseCapture)
+	keys.ConnectKeyPressed(func(keyval, _ uint, state gdk.ModifierType) bool {
+
+		mods := state & gtk.AcceleratorGetDefaultModMask()
+
+		switch {
+		case keyval == gdk.KEY_Escape && mods == 0:
+			dialog.Close()
+
+			return true
+
+		case keyval == gdk.KEY_BackSpace && mods == 0:
+			sc.prefs.Rebind(action, "")
+
+		case gtk.AcceleratorValid(keyval, mods):
+			sc.prefs.Rebind(action, gtk.AcceleratorName(keyval, mods))
+
+		default:
+			// Wait for a complete shortcut (e.g., a modifier key pressed on its own)
+			return true
+		}
+
+		logger.Debug(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("keyboard shortcut of %s set to %q", action, sc.prefs.Accelerator(action)))
+
+		sc.savePreferences()
+		onRebind()
+		dialog.Close()
+
+		return true
+	})
+
+	// Application shortcuts are handled before the dialog sees the key press, so unbind them
+	// while capturing the new shortcut
+	app := sc.UI.Window.Application()
+	for _, shortcut := range config.Shortcuts {
+		app.SetAccelsForAction("app."+shortcut.Action, nil)
+	}
+
+	dialog.ConnectClosed(func() {
+		sc.applyKeymap(app)
+	})
+
+	dialog.AddController(keys)
+	dialog.Present(gtk.Widgetter(sc.UI.Window))
+
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/cairo"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+const (
+	speedGraphWindow    = 5 * time.Minute // Period of ride time plotted in the speed graph
+	speedGraphGridLines = 4
+	speedGraphLineWidth = 2.0
+)
+
+// speedSample is a smoothed speed plotted in the speed graph
+type speedSample struct {
+	time  time.Time
+	speed float64
+}
+
+// speedGraph plots the recent smoothed speed of the session instance shown on Page 2
+type speedGraph struct {
+	area    *gtk.DrawingArea
+	samples []speedSample
+}
+
+// setupSpeedGraph connects the speed graph to its drawing area on Page 2
+func (sc *SessionController) setupSpeedGraph() {
+
+	sc.speedGraph.area = sc.UI.Page2.SpeedGraphArea
+	sc.speedGraph.area.SetDrawFunc(func(_ *gtk.DrawingArea, cr *cairo.Context, width, height int) {
+		sc.speedGraph.draw(cr, float64(width), float64(height))
+	})
+
+}
+
+// add appends a speed to the speed graph, discarding speeds older than the graph window
+func (g *speedGraph) add(speed float64, now time.Time) {
+
+	g.samples = append(g.samples, speedSample{time: now, speed: speed})
+
+	cutoff := now.Add(-speedGraphWindow)
+	first := 0
+
+	for first < len(g.samples) && g.samples[first].time.Before(cutoff) {
+		first++
+	}
+
+	g.samples = g.samples[first:]
+	g.queueDraw()
+
+}
+
+// reset clears the speed graph
+func (g *speedGraph) reset() {
+
+	g.samples = nil
+	g.queueDraw()
+
+}
+
+// queueDraw requests a redraw of the speed graph (if connected to its drawing area)
+func (g *speedGraph) queueDraw() {
+
+	if g.area != nil {
+		g.area.QueueDraw()
+	}
+
+}
+
+// draw renders the speed graph grid and speed line, scaled to the fastest speed in the window
+func (g *speedGraph) draw(cr *cairo.Context, width, height float64) {
+
+	// Horizontal grid lines
+	cr.SetSourceRGBA(0.5, 0.5, 0.5, 0.25)
+	cr.SetLineWidth(1)
+
+	for i := range speedGraphGridLines + 1 {
+		y := 0.5 + float64(i)*(height-1)/speedGraphGridLines
+		cr.MoveTo(0, y)
+		cr.LineTo(width, y)
+	}
+
+	cr.Stroke()
+
+	if len(g.samples) < 2 {
+		return
+	}
+
+	maxSpeed := 0.0
+	for _, s := range g.samples {
+		maxSpeed = max(maxSpeed, s.speed)
+	}
+
+	if maxSpeed == 0 {
+		maxSpeed = 1
+	}
+
+	// Plot the window right-aligned to the most recent speed, so the graph scrolls as it fills
+	end := g.samples[len(g.samples)-1].time
+	inset := speedGraphLineWidth
+	plotHeight := height - 2*inset
+
+	for i, s := range g.samples {
+		x := width * (1 - float64(end.Sub(s.time))/float64(speedGraphWindow))
+		y := inset + plotHeight*(1-s.speed/maxSpeed)
+
+		if i == 0 {
+			cr.MoveTo(x, y)
+		} else {
+			cr.LineTo(x, y)
+		}
+
+	}
+
+	// Adwaita accent blue
+	cr.SetSourceRGBA(0.21, 0.52, 0.89, 1)
+	cr.SetLineWidth(speedGraphLineWidth)
+	cr.Stroke()
+
+}
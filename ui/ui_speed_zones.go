@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+// CSS class prefix of the speed zone backgrounds of the speed label (e.g., "speed-zone-1")
+const cssSpeedZone = "speed-zone-"
+
+// speedZoneClass returns the CSS class of a speed zone (1 = slowest zone)
+func speedZoneClass(zone int) string {
+	return fmt.Sprintf("%s%d", cssSpeedZone, zone)
+}
+
+// speedZoneStyles returns the CSS styles of the speed zone backgrounds, one for each speed zone
+// color
+func speedZoneStyles() string {
+
+	var css strings.Builder
+
+	for i, color := range config.SpeedZoneColors {
+		fmt.Fprintf(&css, `
+	.%s {
+		background-color: alpha(%s, 0.35);
+		border-radius: 6px;
+		padding: 0 8px;
+	}
+	`, speedZoneClass(i+1), color)
+	}
+
+	return css.String()
+}
+
+// setupSpeedZoneSignals validates the speed zones of the Session Editor as they're typed (speed
+// zones must be 3 ascending speeds, or empty)
+func (sc *SessionController) setupSpeedZoneSignals(onUpdate func()) {
+
+	entry := sc.UI.Page4.SpeedZones
+
+	entry.Connect("changed", func() {
+
+		if _, err := config.ParseSpeedZones(entry.Text()); err != nil {
+			entry.AddCSSClass("error")
+		} else {
+			entry.RemoveCSSClass("error")
+		}
+
+		onUpdate()
+
+	})
+
+}
+
+// updateSpeedZone colors the background of the speed label by the speed zone of the current speed
+// (the background is cleared when stopped, or when no speed zones are configured)
+func (sc *SessionController) updateSpeedZone(speed float64) {
+
+	zone := 0
+	if cfg := sc.SessionManager.ActiveConfig(); cfg != nil && speed > 0 {
+		zone = cfg.Speed.SpeedZone(speed)
+	}
+
+	label := sc.UI.Page2.SpeedLabel
+
+	for i := range config.SpeedZoneColors {
+
+		if class := speedZoneClass(i + 1); i+1 != zone {
+			label.RemoveCSSClass(class)
+		} else {
+			label.AddCSSClass(class)
+		}
+	}
+
+}
@@ -3,27 +3,31 @@ package ui
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
 	"github.com/diamondburned/gotk4/pkg/glib/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
 	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/session"
 )
 
+// Time allowed for session services (and the application) to stop when exiting right away (an
+// exit requested again while confirming exit), in place of their shutdown timeouts
+const forcedExitTimeout = 2 * time.Second
+
 // getSessionConfigDir returns the directory path for session configuration files, using
 // os.UserConfigDir(), which follows the XDG Base Directory specification
 func getSessionConfigDir() (string, error) {
 
-	configHome, err := os.UserConfigDir()
+	configDir, err := config.ConfigDir()
 	if err != nil {
 
-		return "", fmt.Errorf("failed to get user config dir: %w", err)
+		return "", err
 	}
 
-	configDir := filepath.Join(configHome, ApplicationID)
-
 	// Ensure the directory exists
 	if _, err := os.Stat(configDir); os.IsNotExist(err) {
 		if err := os.MkdirAll(configDir, 0755); err != nil {
@@ -97,18 +101,99 @@ func displayConfirmationDialog(window *adw.ApplicationWindow, title, message str
 
 }
 
-// createExitDialog creates the application exit confirmation dialog
-func (ui *AppUI) createExitDialog() {
-	displayConfirmationDialog(
-		ui.Window,
-		"Exit BLE Sync Cycle?",
-		"Are you sure you want to exit?",
-		adw.ResponseDestructive,
-		func() {
-			logger.Info(logger.BackgroundCtx, logger.GUI, "user confirmed application exit, so...")
-			ui.shutdownMgr.Shutdown()
-		},
+// createExitDialog creates the application exit confirmation dialog, where any running session
+// instances are first stopped (saving their progress) before exiting. An exit request made while
+// the dialog is shown (e.g., CTRL+C pressed again) exits immediately
+func (sc *SessionController) createExitDialog() {
+
+	const (
+		yes = "yes"
+		no  = "no"
 	)
+
+	ui := sc.UI
+
+	if ui.exitDialog != nil {
+		logger.Warn(logger.BackgroundCtx, logger.GUI, "exit requested again while confirming exit, so exiting now...")
+		sc.registry.StopAllWithin(forcedExitTimeout)
+		ui.shutdownMgr.ShutdownWithin(forcedExitTimeout)
+
+		return
+	}
+
+	heading, body := "Exit BLE Sync Cycle?", "Are you sure you want to exit?"
+	noLabel, yesLabel := "No", "Yes"
+
+	// Running sessions are stopped the same way the Stop button stops them
+	switch running := len(sc.registry.Running()); {
+	case running == 1:
+		heading = "Stop BSC Session and Exit?"
+		body = "A BSC Session is still running. It will be stopped, and its progress saved (its ride history and auto-resume position), before exiting."
+		noLabel, yesLabel = "Cancel", "Stop and Exit"
+
+	case running > 1:
+		heading = "Stop BSC Sessions and Exit?"
+		body = fmt.Sprintf("%d BSC Sessions are still running. They will be stopped, and their progress saved (their ride history and auto-resume positions), before exiting.", running)
+		noLabel, yesLabel = "Cancel", "Stop and Exit"
+	}
+
+	dialog := adw.NewAlertDialog(heading, body)
+	dialog.AddResponse(no, noLabel)
+	dialog.AddResponse(yes, yesLabel)
+	dialog.SetCloseResponse(no)
+	dialog.SetDefaultResponse(no)
+	dialog.SetResponseAppearance(yes, adw.ResponseDestructive)
+
+	dialog.ConnectResponse(func(response string) {
+
+		if response != yes {
+			return
+		}
+
+		logger.Info(logger.BackgroundCtx, logger.GUI, "user confirmed application exit, so...")
+		sc.stopSessionsForExit()
+		ui.shutdownMgr.Shutdown()
+
+	})
+
+	dialog.ConnectClosed(func() {
+		ui.exitDialog = nil
+	})
+
+	ui.exitDialog = dialog
+	dialog.Present(gtk.Widgetter(ui.Window))
+
+}
+
+// stopSessionsForExit stops every running session instance before exiting, saving the auto-resume
+// position of each (as the Stop button does), and waits until their rides are recorded in the
+// session history
+func (sc *SessionController) stopSessionsForExit() {
+
+	for _, m := range sc.registry.Running() {
+
+		cfg := m.ActiveConfig()
+		path := m.LoadedConfigPath()
+
+		var point session.ResumePoint
+		if cfg != nil && cfg.Video.AutoResume {
+			point = m.ResumePoint()
+		}
+
+		if err := m.StopSession(); err != nil {
+			logger.Warn(logger.BackgroundCtx, logger.GUI, fmt.Sprintf("unable to stop session services: %v", err))
+
+			continue
+		}
+
+		if !point.IsStart() {
+			sc.saveAutoResumePosition(m, path, point)
+		}
+	}
+
+	// With every session instance stopped, this only waits on the session history writes
+	sc.registry.StopAll()
+
 }
 
 // bindValidator ties regex validation check to an EntryRow widget
@@ -135,3 +220,24 @@ func bindValidator(entry *adw.EntryRow, pattern string, onUpdate func()) {
 	})
 
 }
+
+// bindBDAddrValidator binds a BD_ADDR validator to an Adw.EntryRow, accepting a BD_ADDR in any of
+// the formats understood by config.NormalizeBDAddr (e.g., in lower case, or without separators)
+func bindBDAddrValidator(entry *adw.EntryRow, onUpdate func()) {
+
+	entry.Connect("changed", func() {
+
+		if _, err := config.NormalizeBDAddr(entry.Text()); err != nil {
+			entry.AddCSSClass("error")
+		} else {
+			entry.RemoveCSSClass("error")
+		}
+
+		// Trigger the callback if provided (e.g., to update button state)
+		if onUpdate != nil {
+			onUpdate()
+		}
+
+	})
+
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/richbl/go-ble-sync-cycle/internal/logger"
+	"github.com/richbl/go-ble-sync-cycle/internal/session"
+	"github.com/richbl/go-ble-sync-cycle/internal/video"
+)
+
+// setupVideoCheckSignals wires up the button of the video check banner (Page 2), which opens the
+// loaded session in the Session Editor (Page 4) to choose another video file
+func (sc *SessionController) setupVideoCheckSignals() {
+
+	sc.UI.Page2.VideoCheckBanner.ConnectButtonClicked(func() {
+		logger.Debug(logger.BackgroundCtx, logger.GUI, "navigating to Session Editor (page 4) to fix video file...")
+		sc.UI.ViewStack.SetVisibleChildName("page4")
+	})
+
+}
+
+// checkSessionVideo checks the video file of the loaded session in the background (so a missing or
+// corrupt video is reported before the session is started), showing any problem in a banner on
+// Page 2
+func (sc *SessionController) checkSessionVideo() {
+
+	sc.videoCheckGen++
+	gen := sc.videoCheckGen
+
+	sc.UI.Page2.VideoCheckBanner.SetRevealed(false)
+
+	// A session already past the Loaded state has opened its video file
+	cfg := sc.SessionManager.ActiveConfig()
+	if cfg == nil || sc.SessionManager.SessionState() != session.StateLoaded {
+		return
+	}
+
+	videoPath := cfg.Video.FilePath
+
+	go func() {
+
+		problem := checkVideoFile(videoPath)
+
+		safeUpdateUI(func() {
+
+			// Ignore the result if another session has been loaded since
+			if gen != sc.videoCheckGen {
+				return
+			}
+
+			if problem == "" {
+				return
+			}
+
+			logger.Warn(logger.BackgroundCtx, logger.VIDEO, "video file check failed: "+problem)
+
+			sc.UI.Page2.VideoCheckBanner.SetTitle(problem)
+			sc.UI.Page2.VideoCheckBanner.SetRevealed(true)
+
+		})
+
+	}()
+
+}
+
+// checkVideoFile checks that a video file exists, can be read, and can be decoded, returning a
+// description of the problem found (or an empty string if none)
+func checkVideoFile(videoPath string) string {
+
+	file, err := os.Open(videoPath)
+	if err != nil {
+
+		if os.IsNotExist(err) {
+			return "Video file not found: " + videoPath
+		}
+
+		return fmt.Sprintf("Video file can't be read: %v", err)
+	}
+
+	file.Close()
+
+	info, err := video.ProbeVideoFile(videoPath)
+	if err != nil {
+		return fmt.Sprintf("Video file can't be played: %v", err)
+	}
+
+	logger.Debug(logger.BackgroundCtx, logger.VIDEO, fmt.Sprintf("video file check passed: %s video (%dx%d, %s)", info.Codec, info.Width, info.Height, info.Duration.Round(time.Second)))
+
+	if info.Duration <= 0 {
+		return "Video file duration is unknown: seeking and the time remaining won't be available"
+	}
+
+	return ""
+}
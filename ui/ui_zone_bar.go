@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/cairo"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/richbl/go-ble-sync-cycle/internal/config"
+)
+
+const (
+	zoneBarWidth  = 160
+	zoneBarHeight = 12
+)
+
+// newZoneBar creates a stacked bar of the ride time spent in each speed zone, colored by speed
+// zone (the tooltip lists the time spent in each zone)
+func newZoneBar(zoneSecs []int) *gtk.DrawingArea {
+
+	bar := gtk.NewDrawingArea()
+	bar.SetContentWidth(zoneBarWidth)
+	bar.SetContentHeight(zoneBarHeight)
+	bar.SetVAlign(gtk.AlignCenter)
+	bar.SetTooltipText(zoneBarTooltip(zoneSecs))
+
+	bar.SetDrawFunc(func(_ *gtk.DrawingArea, cr *cairo.Context, width, height int) {
+		drawZoneBar(cr, zoneSecs, float64(width), float64(height))
+	})
+
+	return bar
+}
+
+// drawZoneBar renders each speed zone as a segment of the bar, sized by its share of the ride time
+func drawZoneBar(cr *cairo.Context, zoneSecs []int, width, height float64) {
+
+	total := zoneTotal(zoneSecs)
+	if total == 0 {
+		return
+	}
+
+	x := 0.0
+
+	for i, secs := range zoneSecs {
+
+		if i >= len(config.SpeedZoneColors) || secs <= 0 {
+			continue
+		}
+
+		segment := width * float64(secs) / float64(total)
+		r, g, b := hexColor(config.SpeedZoneColors[i])
+
+		cr.SetSourceRGB(r, g, b)
+		cr.Rectangle(x, 0, segment, height)
+		cr.Fill()
+
+		x += segment
+	}
+
+}
+
+// zoneBarTooltip lists the ride time spent in each speed zone (e.g., "Zone 1: 5:02 (20%)")
+func zoneBarTooltip(zoneSecs []int) string {
+
+	total := zoneTotal(zoneSecs)
+	if total == 0 {
+		return "No time in speed zones"
+	}
+
+	lines := make([]string, 0, len(zoneSecs))
+
+	for i, secs := range zoneSecs {
+		lines = append(lines, fmt.Sprintf("Zone %d: %d:%02d (%.0f%%)", i+1, secs/60, secs%60, float64(secs)*100/float64(total)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// zoneTotal returns the ride time spent in all speed zones
+func zoneTotal(zoneSecs []int) int {
+
+	total := 0
+	for _, secs := range zoneSecs {
+		total += max(secs, 0)
+	}
+
+	return total
+}
+
+// hexColor converts a "#RRGGBB" color to its red, green, and blue components (0-1)
+func hexColor(color string) (r, g, b float64) {
+
+	var red, green, blue uint8
+	if _, err := fmt.Sscanf(color, "#%02x%02x%02x", &red, &green, &blue); err != nil {
+		return 0.5, 0.5, 0.5
+	}
+
+	return float64(red) / 255, float64(green) / 255, float64(blue) / 255
+}